@@ -0,0 +1,209 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"arbitrage.trade/orderbook"
+)
+
+// BookSource provides the cross-venue books served by /book/aggregated, the per-venue levels served by
+// /book/top, the recent snapshots served by /book/history, the feed stats served by /feed/stats, the
+// pair health served by /feed/health and the book summaries included in /debug/state
+type BookSource interface {
+	AggregatedBook(pairName string, isSpot bool, opts orderbook.AggregateOptions) (*orderbook.AggregatedBook, bool)
+	TopOfBooks(pairName string, depth int) ([]orderbook.VenueBook, bool)
+	BookHistory(pairName string, from, to time.Time, books ...string) ([]orderbook.BookSnapshot, bool)
+	FeedStats() []orderbook.FeedStats
+	PairHealth() []orderbook.PairHealth
+	BookSummaries() []orderbook.BookSummary
+}
+
+var bookSource BookSource
+
+// SetBookSource sets the orderbooks the book endpoints read from
+func SetBookSource(source BookSource) {
+	bookSource = source
+}
+
+// handleAggregatedBook serves a pair's depth consolidated across venues with per-level venue volumes
+// ?pair=btc-usdt&market=spot|perp (default spot), depth=N (default 20, 0 for all),
+// tick=T groups prices into T-wide buckets, max_age=5s leaves out books not updated within it
+func handleAggregatedBook(w http.ResponseWriter, r *http.Request) {
+	if bookSource == nil {
+		writeError(w, http.StatusServiceUnavailable, fmt.Errorf("orderbooks not available"))
+		return
+	}
+
+	q := r.URL.Query()
+	pair := q.Get("pair")
+	if pair == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("pair is required"))
+		return
+	}
+
+	market := q.Get("market")
+	if market == "" {
+		market = "spot"
+	}
+	if market != "spot" && market != "perp" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid market: %q", market))
+		return
+	}
+
+	opts := orderbook.AggregateOptions{Depth: 20}
+	if v := q.Get("depth"); v != "" {
+		depth, err := strconv.Atoi(v)
+		if err != nil || depth < 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid depth: %q", v))
+			return
+		}
+		opts.Depth = depth
+	}
+	if v := q.Get("tick"); v != "" {
+		tick, err := strconv.ParseFloat(v, 64)
+		if err != nil || tick < 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid tick: %q", v))
+			return
+		}
+		opts.Tick = tick
+	}
+	if v := q.Get("max_age"); v != "" {
+		maxAge, err := time.ParseDuration(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid max_age: %q", v))
+			return
+		}
+		opts.MaxAge = maxAge
+	}
+
+	book, ok := bookSource.AggregatedBook(pair, market == "spot", opts)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("pair not monitored: %s", pair))
+		return
+	}
+	writeJSON(w, http.StatusOK, book)
+}
+
+// handleTopOfBooks serves the top price levels of each venue's book for a pair, to compare against the exchange UI
+// ?pair=btc-usdt, depth=N (default 10, 0 for all), market=spot|perp (default both), exchange=X limits to one venue
+func handleTopOfBooks(w http.ResponseWriter, r *http.Request) {
+	if bookSource == nil {
+		writeError(w, http.StatusServiceUnavailable, fmt.Errorf("orderbooks not available"))
+		return
+	}
+
+	q := r.URL.Query()
+	pair := q.Get("pair")
+	if pair == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("pair is required"))
+		return
+	}
+
+	market := q.Get("market")
+	if market != "" && market != "spot" && market != "perp" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid market: %q", market))
+		return
+	}
+
+	depth := 10
+	if v := q.Get("depth"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid depth: %q", v))
+			return
+		}
+		depth = n
+	}
+
+	books, ok := bookSource.TopOfBooks(pair, depth)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("pair not monitored: %s", pair))
+		return
+	}
+
+	exchange := q.Get("exchange")
+	filtered := make([]orderbook.VenueBook, 0, len(books))
+	for _, book := range books {
+		if (market == "" || book.Market == market) && (exchange == "" || book.Exchange == exchange) {
+			filtered = append(filtered, book)
+		}
+	}
+	writeJSON(w, http.StatusOK, filtered)
+}
+
+// handleBookHistory serves the snapshots each venue book of a pair went through around a moment, from the
+// last BOOK_HISTORY_WINDOW only; ?pair=btc-usdt, at=<RFC 3339 time> (default now), window=2s (default) on
+// each side, book=spot:binance limits to one venue book (repeatable)
+func handleBookHistory(w http.ResponseWriter, r *http.Request) {
+	if bookSource == nil {
+		writeError(w, http.StatusServiceUnavailable, fmt.Errorf("orderbooks not available"))
+		return
+	}
+
+	q := r.URL.Query()
+	pair := q.Get("pair")
+	if pair == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("pair is required"))
+		return
+	}
+
+	at := time.Now()
+	if v := q.Get("at"); v != "" {
+		t, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid at: %q", v))
+			return
+		}
+		at = t
+	}
+
+	window := 2 * time.Second
+	if v := q.Get("window"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid window: %q", v))
+			return
+		}
+		window = d
+	}
+
+	snapshots, ok := bookSource.BookHistory(pair, at.Add(-window), at.Add(window), q["book"]...)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("pair not monitored: %s", pair))
+		return
+	}
+	writeJSON(w, http.StatusOK, snapshots)
+}
+
+// handleFeedStats serves each pair's signal feed update rate, decode and analysis times and backpressure
+func handleFeedStats(w http.ResponseWriter, r *http.Request) {
+	if bookSource == nil {
+		writeError(w, http.StatusServiceUnavailable, fmt.Errorf("orderbooks not available"))
+		return
+	}
+	writeJSON(w, http.StatusOK, bookSource.FeedStats())
+}
+
+// handleFeedHealth serves which pairs have a blind side (no reliable venue book) and since when,
+// degraded pairs first; ?degraded=true leaves out healthy pairs
+func handleFeedHealth(w http.ResponseWriter, r *http.Request) {
+	if bookSource == nil {
+		writeError(w, http.StatusServiceUnavailable, fmt.Errorf("orderbooks not available"))
+		return
+	}
+
+	health := bookSource.PairHealth()
+	if r.URL.Query().Get("degraded") == "true" {
+		degraded := make([]orderbook.PairHealth, 0, len(health))
+		for _, h := range health {
+			if h.State != orderbook.HealthOK {
+				degraded = append(degraded, h)
+			}
+		}
+		health = degraded
+	}
+	writeJSON(w, http.StatusOK, health)
+}
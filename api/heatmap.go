@@ -0,0 +1,42 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"arbitrage.trade/redis"
+)
+
+// HeatmapSource provides the observed opportunities served by /opportunities/heatmap
+type HeatmapSource interface {
+	Heatmap(window time.Duration) []redis.HeatmapCell
+}
+
+var heatmapSource HeatmapSource
+
+// SetHeatmapSource sets the analyzer the heatmap endpoint reads from
+func SetHeatmapSource(source HeatmapSource) {
+	heatmapSource = source
+}
+
+// handleHeatmap serves observation count and average spread per pair and venue combination
+// ?window=1h (default) up to 24h
+func handleHeatmap(w http.ResponseWriter, r *http.Request) {
+	if heatmapSource == nil {
+		writeError(w, http.StatusServiceUnavailable, fmt.Errorf("analyzer not available"))
+		return
+	}
+
+	window := time.Hour
+	if v := r.URL.Query().Get("window"); v != "" {
+		parsed, err := time.ParseDuration(v)
+		if err != nil || parsed <= 0 || parsed > 24*time.Hour {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid window: %q, want up to 24h", v))
+			return
+		}
+		window = parsed
+	}
+
+	writeJSON(w, http.StatusOK, heatmapSource.Heatmap(window))
+}
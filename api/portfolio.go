@@ -0,0 +1,29 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"arbitrage.trade/redis"
+)
+
+// PortfolioSource provides the aggregate exposure served by /portfolio
+type PortfolioSource interface {
+	Portfolio() redis.Portfolio
+}
+
+var portfolioSource PortfolioSource
+
+// SetPortfolioSource sets where the portfolio endpoint reads the exposure from
+func SetPortfolioSource(source PortfolioSource) {
+	portfolioSource = source
+}
+
+// handlePortfolio serves the net delta per asset and gross notional per venue across every open position and holding
+func handlePortfolio(w http.ResponseWriter, r *http.Request) {
+	if portfolioSource == nil {
+		writeError(w, http.StatusServiceUnavailable, fmt.Errorf("portfolio not available"))
+		return
+	}
+	writeJSON(w, http.StatusOK, portfolioSource.Portfolio())
+}
@@ -0,0 +1,347 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"arbitrage.trade/clients"
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/config"
+	"arbitrage.trade/fees"
+	"arbitrage.trade/funding"
+	"arbitrage.trade/inventory"
+	"arbitrage.trade/logging"
+	"arbitrage.trade/report"
+	"arbitrage.trade/risk"
+	"arbitrage.trade/scheduler"
+	"arbitrage.trade/storage"
+)
+
+func registerRoutes() {
+	Handle("/pairs/leaderboard", handleLeaderboard)
+	Handle("/pnl/equity", handleEquityCurve)
+	Handle("/wallets", handleWallets)
+	Handle("/balances/low", handleLowBalances)
+	Handle("/rebalance/plan", handleRebalancePlan)
+	Handle("/venues/backoff", handleRejectBackoffs)
+	Handle("/venues/entry", handleVenueEntry)
+	Handle("/venues/divergence", handleDivergencePauses)
+	Handle("/inventory", handleInventory)
+	Handle("/positions/pnl", handlePositionsPnL)
+	Handle("/portfolio", handlePortfolio)
+	Handle("/trades/execution", handleExecution)
+	Handle("/trades/execution/stats", handleExecutionStats)
+	Handle("/trades/experiment", handleExperiment)
+	Handle("/trading-window", handleTradingWindow)
+	Handle("/funding", handleFunding)
+	Handle("/fees", handleFees)
+	Handle("/trading-state", handleTradingState)
+	Handle("/jobs", handleJobs)
+	Handle("/http/stats", handleHTTPStats)
+	Handle("/book/aggregated", handleAggregatedBook)
+	Handle("/book/top", handleTopOfBooks)
+	Handle("/book/history", handleBookHistory)
+	Handle("/book/captures", handleBookCaptures)
+	Handle("/feed/stats", handleFeedStats)
+	Handle("/feed/health", handleFeedHealth)
+	Handle("/debug/state", handleState)
+	Handle("/opportunities/heatmap", handleHeatmap)
+	Handle("/control/pause", handlePause)
+	Handle("/control/resume", handleResume)
+	Handle("/control/log-level", handleLogLevel)
+}
+
+// queryDays parses the ?days=N window, 0 when absent
+func queryDays(r *http.Request) (int, error) {
+	v := r.URL.Query().Get("days")
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid days: %q", v)
+	}
+	return n, nil
+}
+
+// handleLeaderboard serves per-pair performance, best first (?days=N limits the window)
+func handleLeaderboard(w http.ResponseWriter, r *http.Request) {
+	days, err := queryDays(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	board, err := report.LoadLeaderboard(days)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, board)
+}
+
+// handleEquityCurve serves the cumulative P&L per day in USDT and REPORT_CURRENCY (?days=N, default 30, 0 = all time)
+func handleEquityCurve(w http.ResponseWriter, r *http.Request) {
+	days := 30
+	if r.URL.Query().Has("days") {
+		var err error
+		if days, err = queryDays(r); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	curve, err := report.LoadEquityCurve(r.Context(), days)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, curve)
+}
+
+// handleWallets serves the last known deposit/withdrawal status per venue and asset
+func handleWallets(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, clients.WalletSnapshot())
+}
+
+// handleRebalancePlan serves the last USDT rebalance plan (?refresh=true recomputes it)
+func handleRebalancePlan(w http.ResponseWriter, r *http.Request) {
+	plan, err := clients.RebalancePlanSnapshot(r.Context(), r.URL.Query().Get("refresh") == "true")
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, plan)
+}
+
+// handleRejectBackoffs serves the venues held back from new legs after a spike in rejected orders
+func handleRejectBackoffs(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, clients.RejectBackoffs())
+}
+
+// handleVenueEntry serves the venues closed to new legs; POST ?exchange=X&disabled=true|false&reason=...
+// stops or resumes new legs on one until restart, positions open there keep being managed
+func handleVenueEntry(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost {
+		q := r.URL.Query()
+		exchange := q.Get("exchange")
+		if exchange == "" {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("exchange is required"))
+			return
+		}
+		disabled, err := strconv.ParseBool(q.Get("disabled"))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid disabled: %q", q.Get("disabled")))
+			return
+		}
+		if err := clients.SetEntryDisabled(exchange, disabled, q.Get("reason")); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, clients.EntryDisables())
+}
+
+// handleDivergencePauses serves the venue combinations held back from new entries after a diverged fill
+func handleDivergencePauses(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, clients.DivergencePauses())
+}
+
+// handleLowBalances serves the venue markets whose free USDT is below its floor
+func handleLowBalances(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, clients.LowBalances())
+}
+
+// handleJobs serves the periodic jobs' schedules and run metrics
+func handleJobs(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, scheduler.Stats())
+}
+
+// handlePositionsPnL serves the live PnL of every open position: venue-reported perp PnL where available,
+// book-marked spot and perp otherwise
+func handlePositionsPnL(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, positionsPnL())
+}
+
+// handleHTTPStats serves each venue's REST call counts, errors, retries, latency and last rate-limit headers
+func handleHTTPStats(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, common.RESTStats())
+}
+
+// handleInventory serves standing spot inventory marked to market
+func handleInventory(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, inventory.Snapshot())
+}
+
+// handleExecution serves per-trade execution quality, newest first (?days=N, default 1; ?pair= filters)
+func handleExecution(w http.ResponseWriter, r *http.Request) {
+	days, err := queryDays(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if days <= 0 {
+		days = 1
+	}
+
+	to := time.Now()
+	records, err := storage.LoadExecutions(to.AddDate(0, 0, -days), to)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	pair := r.URL.Query().Get("pair")
+	result := make([]storage.ExecutionRecord, 0, len(records))
+	for i := len(records) - 1; i >= 0; i-- {
+		if pair == "" || records[i].Pair == pair {
+			result = append(result, records[i])
+		}
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// handleBookCaptures serves the venue books stored around trades' executions, latest first
+// ?days=N (default 1), trade_id=X or pair=X limit to one trade or pair
+func handleBookCaptures(w http.ResponseWriter, r *http.Request) {
+	days, err := queryDays(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if days <= 0 {
+		days = 1
+	}
+
+	to := time.Now()
+	captures, err := storage.LoadBookCaptures(to.AddDate(0, 0, -days), to)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	q := r.URL.Query()
+	tradeID, pair := q.Get("trade_id"), q.Get("pair")
+	result := make([]storage.BookCapture, 0, len(captures))
+	for i := len(captures) - 1; i >= 0; i-- {
+		if (tradeID == "" || captures[i].TradeID == tradeID) && (pair == "" || captures[i].Pair == pair) {
+			result = append(result, captures[i])
+		}
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// handleExecutionStats serves execution quality per spot:perp venue combination, most diverged fills first
+// (?days=N, default 7)
+func handleExecutionStats(w http.ResponseWriter, r *http.Request) {
+	days, err := queryDays(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if days <= 0 {
+		days = 7
+	}
+
+	to := time.Now()
+	records, err := storage.LoadExecutions(to.AddDate(0, 0, -days), to)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, report.ExecutionQuality(records))
+}
+
+// handleExperiment compares an A/B experiment's variants (?name= defaults to EXPERIMENT_NAME, ?days=N limits the window)
+func handleExperiment(w http.ResponseWriter, r *http.Request) {
+	days, err := queryDays(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		name = config.GetString("EXPERIMENT_NAME", "")
+	}
+	if name == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("no experiment: pass ?name= or set EXPERIMENT_NAME"))
+		return
+	}
+
+	comparison, err := report.LoadExperiment(name, days)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, comparison)
+}
+
+// handleTradingWindow serves whether new entries are allowed by the schedule and each pair's realized volatility
+func handleTradingWindow(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, risk.CurrentWindow())
+}
+
+// handleFunding serves each perp venue's average funding per pair, best for shorts first
+func handleFunding(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, funding.Snapshot())
+}
+
+// handleFees serves every venue market's fee rate, tier and 30-day volume
+func handleFees(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, fees.Snapshot())
+}
+
+// handleTradingState serves whether new entries are paused and every active reason
+func handleTradingState(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, risk.CurrentState())
+}
+
+// handlePause pauses new entries as the operator (POST, ?detail= describes why)
+func handlePause(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("use POST"))
+		return
+	}
+	risk.Pause(risk.ReasonOperator, r.URL.Query().Get("detail"))
+	writeJSON(w, http.StatusOK, risk.CurrentState())
+}
+
+// handleResume clears a pause reason (POST, ?reason= defaults to operator)
+func handleResume(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("use POST"))
+		return
+	}
+	reason := risk.PauseReason(r.URL.Query().Get("reason"))
+	if reason == "" {
+		reason = risk.ReasonOperator
+	}
+	risk.Resume(reason)
+	writeJSON(w, http.StatusOK, risk.CurrentState())
+}
+
+// handleLogLevel serves every module's log level; POST ?module=binance&level=debug overrides one
+// (level=reset drops the override)
+func handleLogLevel(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost {
+		q := r.URL.Query()
+		module := q.Get("module")
+		if module == "" {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("module is required"))
+			return
+		}
+		if q.Get("level") == "reset" {
+			logging.ClearLevel(module)
+		} else {
+			level, err := logging.ParseLevel(q.Get("level"))
+			if err != nil {
+				writeError(w, http.StatusBadRequest, err)
+				return
+			}
+			logging.SetLevel(module, level)
+		}
+	}
+	writeJSON(w, http.StatusOK, logging.Levels())
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync"
+
+	"arbitrage.trade/config"
+)
+
+// Operator HTTP API
+//
+// Listens on API_ADDR (default 127.0.0.1:8090, empty disables). Packages register
+// their endpoints with Handle before Start is called.
+
+var (
+	mux     = http.NewServeMux()
+	muxOnce sync.Once
+)
+
+// Handle registers an endpoint on the API server
+func Handle(pattern string, handler http.HandlerFunc) {
+	mux.HandleFunc(pattern, handler)
+}
+
+// Start serves the API in the background
+func Start() {
+	addr := config.GetString("API_ADDR", "127.0.0.1:8090")
+	if addr == "" {
+		return
+	}
+
+	muxOnce.Do(registerRoutes)
+
+	go func() {
+		log.Printf("[API] Listening on %s", addr)
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			log.Printf("[API] Start - ERROR: %v", err)
+		}
+	}()
+}
+
+// writeJSON writes a JSON response with the given status
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("[API] writeJSON - ERROR: %v", err)
+	}
+}
+
+// writeError writes a JSON error response
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
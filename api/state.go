@@ -0,0 +1,141 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"runtime"
+	"strings"
+	"time"
+
+	"arbitrage.trade/clients"
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/orderbook"
+	"arbitrage.trade/redis"
+	"arbitrage.trade/risk"
+	"arbitrage.trade/storage"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// PositionSource provides the tracked positions included in /debug/state
+type PositionSource interface {
+	Positions() []storage.PositionSnapshot
+}
+
+var positionSource PositionSource
+
+// SetPositionSource sets where the state endpoint reads tracked positions from
+func SetPositionSource(source PositionSource) {
+	positionSource = source
+}
+
+// PositionPnL is an open position's live PnL as served by /positions/pnl
+type PositionPnL struct {
+	TradeID       string `json:"trade_id,omitempty"`
+	Strategy      string `json:"strategy,omitempty"`
+	ConfigVersion string `json:"config_version,omitempty"`
+	Experiment    string `json:"experiment,omitempty"`
+	Variant       string `json:"variant,omitempty"`
+	Pair          string `json:"pair"`
+	ShortExchange string `json:"short_exchange"`
+	LongExchange  string `json:"long_exchange"`
+	Reverse       bool   `json:"reverse"`
+	storage.LivePnL
+}
+
+// positionsPnL returns the live PnL of every open position valued so far
+func positionsPnL() []PositionPnL {
+	pnl := make([]PositionPnL, 0)
+	if positionSource == nil {
+		return pnl
+	}
+	for _, p := range positionSource.Positions() {
+		if p.LivePnL == nil || !p.IsOpen {
+			continue
+		}
+		pnl = append(pnl, PositionPnL{
+			TradeID:       p.TradeID,
+			Strategy:      p.Strategy,
+			ConfigVersion: p.ConfigVersion,
+			Experiment:    p.Experiment,
+			Variant:       p.Variant,
+			Pair:          p.Pair,
+			ShortExchange: p.ShortExchange,
+			LongExchange:  p.LongExchange,
+			Reverse:       p.Reverse,
+			LivePnL:       *p.LivePnL,
+		})
+	}
+	return pnl
+}
+
+// State is the bot's in-memory state as served by /debug/state
+type State struct {
+	At               time.Time                               `json:"at"`
+	PaperTrading     bool                                    `json:"paper_trading"`
+	Goroutines       int                                     `json:"goroutines"`
+	Trading          risk.TradingState                       `json:"trading"`
+	Positions        []storage.PositionSnapshot              `json:"positions"`
+	ReservedNotional map[string]float64                      `json:"reserved_notional"` // exchange:market -> USDT
+	LockedCapital    map[string]float64                      `json:"locked_capital"`    // exchange:market -> USDT
+	Balances         map[string]common.CachedBalanceSnapshot `json:"balances"`          // exchange:market -> snapshot
+	RejectBackoffs   []clients.RejectBackoff                 `json:"reject_backoffs"`
+	EntryDisabled    []clients.EntryDisable                  `json:"entry_disabled"` // Venues closed to new legs
+	DivergencePauses []clients.DivergencePause               `json:"divergence_pauses"`
+	Books            []orderbook.BookSummary                 `json:"books"`
+	Feed             []orderbook.FeedStats                   `json:"feed"`
+	PairHealth       []orderbook.PairHealth                  `json:"pair_health"` // Degraded pairs first
+	RedisSpool       redis.SpoolStats                        `json:"redis_spool"` // Publish failures and spooled trade events
+}
+
+// currentState collects the state from every source that is set
+func currentState() State {
+	state := State{
+		At:               time.Now(),
+		PaperTrading:     clients.IsPaperTrading(),
+		Goroutines:       runtime.NumGoroutine(),
+		Trading:          risk.CurrentState(),
+		ReservedNotional: risk.Snapshot(),
+		LockedCapital:    risk.CapitalSnapshot(),
+		Balances:         common.BalanceSnapshots(),
+		RejectBackoffs:   clients.RejectBackoffs(),
+		EntryDisabled:    clients.EntryDisables(),
+		DivergencePauses: clients.DivergencePauses(),
+		RedisSpool:       redis.CurrentSpoolStats(),
+	}
+	if positionSource != nil {
+		state.Positions = positionSource.Positions()
+	}
+	if bookSource != nil {
+		state.Books = bookSource.BookSummaries()
+		state.Feed = bookSource.FeedStats()
+		state.PairHealth = bookSource.PairHealth()
+	}
+	return state
+}
+
+// handleState serves a diagnostic dump of books, positions, reserved capital, balances and pause state
+// JSON by default; ?format=msgpack or Accept: application/msgpack returns the same fields as msgpack
+func handleState(w http.ResponseWriter, r *http.Request) {
+	state := currentState()
+
+	if r.URL.Query().Get("format") != "msgpack" && !strings.Contains(r.Header.Get("Accept"), "application/msgpack") {
+		writeJSON(w, http.StatusOK, state)
+		return
+	}
+
+	enc := msgpack.GetEncoder()
+	defer msgpack.PutEncoder(enc)
+
+	var buf bytes.Buffer
+	enc.Reset(&buf)
+	enc.SetCustomStructTag("json")
+	if err := enc.Encode(state); err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("failed to encode state: %w", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/msgpack")
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
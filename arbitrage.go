@@ -2,37 +2,350 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
+	"math"
+	"strings"
 	"sync"
 	"time"
 
 	"arbitrage.trade/clients"
 	"arbitrage.trade/clients/common"
+	"arbitrage.trade/funding"
+	"arbitrage.trade/notify"
 	"arbitrage.trade/orderbook"
 	"arbitrage.trade/redis"
+	"arbitrage.trade/risk"
+	"arbitrage.trade/storage"
 )
 
 var (
 	activePositions = make(map[string]*ArbitragePosition)
 	positionsMutex  sync.RWMutex
-	globalAnalyzer  *orderbook.Analyzer // Reference to reset execution flag after trade closes
+	globalAnalyzer  *orderbook.Analyzer      // Reference to reset execution flag after trade closes
+	globalBooks     *orderbook.GlobalManager // Reference to start/stop venue feeds for open positions
 )
 
 type ArbitragePosition struct {
-	PairName        string
-	ShortExchange   common.ExchangeType
-	LongExchange    common.ExchangeType
-	EntryShortPrice float64
-	EntryLongPrice  float64
-	EntrySpread     float64
-	AmountUSDT      float64
-	EntryTime       time.Time
-	IsOpen          bool
-	LastLogTime     time.Time // Track when we last logged to avoid spam
-	mu              sync.RWMutex
+	TradeID              string // Assigned at detection, tags the position's orders, events and records
+	Strategy             string // Strategy the position was opened under, see strategy.go
+	ConfigVersion        string // Parameter set of the strategy, see strategy.go
+	Experiment           string // A/B experiment the entry was assigned in, see experiment.go
+	Variant              string // Execution configuration the experiment assigned
+	PairName             string
+	ShortExchange        common.ExchangeType
+	LongExchange         common.ExchangeType
+	EntryShortPrice      float64
+	EntryLongPrice       float64
+	EntrySpread          float64
+	CurrentSpread        float64 // Latest tracked exit spread, used to pick taker/maker on close
+	CurrentShort         float64 // Latest best ask of the short leg's book, the expected buy-back price
+	CurrentLong          float64 // Latest best bid of the long leg's book, the expected sell price
+	Fees                 float64 // USDT fees paid across all legs
+	Rebates              float64 // USDT maker rebates earned across all legs
+	Slippage             float64 // Adverse slippage in %, summed over filled legs
+	AmountUSDT           float64
+	EntryTime            time.Time
+	IsOpen               bool
+	IsClosing            bool                // Close orders sent, waiting for both venues to confirm flat
+	LastLogTime          time.Time           // Track when we last logged to avoid spam
+	Reverse              bool                // Sold spot inventory (short leg) and went long the perp (long leg)
+	Inverse              bool                // Perp leg is a coin-margined short (INVERSE_PERP_VENUES)
+	SpotQuantity         float64             // Reverse: base quantity sold from inventory, bought back on close
+	SpotProceeds         float64             // Reverse: net USDT received for the inventory sale
+	Option               *common.OptionQuote // Protective option held while open, nil when unhedged
+	OptionExchange       common.ExchangeType
+	OptionFill           *common.TradeResult
+	DetectedAt           time.Time              // When the analyzer saw the opportunity
+	FirstFillAt          time.Time              // When the first open leg reported its fill
+	Legs                 []storage.LegExecution // Every filled leg against its decision price
+	SpotFill             storage.LegFill        // What the spot leg actually opened at, may differ from AmountUSDT
+	PerpFill             storage.LegFill        // What the perp leg actually opened at
+	SpotRoutes           []storage.SpotRoute    // Spot leg split across venues, nil when it is on LongExchange alone
+	PartialClosed        bool                   // Part of the legs was closed at PARTIAL_CLOSE_AT
+	PartialSpotProfit    float64                // USDT realized on the spot leg by the partial close
+	PartialFuturesProfit float64                // USDT realized on the perp leg by the partial close
+	ExitReason           string
+	LivePnL              *storage.LivePnL // Unrealized PnL while held, see live_pnl.go
+	partialDone          chan struct{}    // Closed once an in-flight partial close finished
+	mu                   sync.RWMutex
 }
 
-// UpdatePrices is called from main WebSocket loop to track current prices
+// tradeContext returns a background context carrying the position's trade ID, strategy tag and execution playbook
+// for its orders
+func (p *ArbitragePosition) tradeContext() context.Context {
+	ctx := common.WithStrategy(common.WithTradeID(context.Background(), p.TradeID), p.strategyTag())
+	return clients.WithPlaybook(ctx, p.playbook())
+}
+
+// spotExchange returns the venue of the position's spot leg
+func (p *ArbitragePosition) spotExchange() common.ExchangeType {
+	if p.Reverse {
+		return p.ShortExchange
+	}
+	return p.LongExchange
+}
+
+// perpExchange returns the venue of the position's perp leg
+func (p *ArbitragePosition) perpExchange() common.ExchangeType {
+	if p.Reverse {
+		return p.LongExchange
+	}
+	return p.ShortExchange
+}
+
+// spotRoutes returns every venue a forward position's spot leg was bought on, with its share of the notional
+func (p *ArbitragePosition) spotRoutes() []storage.SpotRoute {
+	if len(p.SpotRoutes) > 0 {
+		return p.SpotRoutes
+	}
+	return []storage.SpotRoute{{Exchange: string(p.LongExchange), Price: p.EntryLongPrice, AmountUSDT: p.AmountUSDT}}
+}
+
+// perpMarket returns the market of the position's perp leg, "inverse" for coin-margined shorts
+func (p *ArbitragePosition) perpMarket() string {
+	if p.Inverse {
+		return "inverse"
+	}
+	return "futures"
+}
+
+// perpCloseCommand returns the order that closes the position's perp leg
+func (p *ArbitragePosition) perpCloseCommand() common.OrderType {
+	switch {
+	case p.Reverse:
+		return common.CloseFuturesLong
+	case p.Inverse:
+		return common.CloseInverseShort
+	}
+	return common.CloseFuturesShort
+}
+
+// shortFill and longFill return the open fills of the position's short and long legs
+// Callers must hold p.mu.
+func (p *ArbitragePosition) shortFill() storage.LegFill {
+	if p.Reverse {
+		return p.SpotFill
+	}
+	return p.PerpFill
+}
+
+func (p *ArbitragePosition) longFill() storage.LegFill {
+	if p.Reverse {
+		return p.PerpFill
+	}
+	return p.SpotFill
+}
+
+// convergence returns how much of the entry edge has been captured in %, from the legs' actual fills
+// at the current short and long prices. Falls back to the spread when a leg's fill is unknown.
+// Callers must hold p.mu.
+func (p *ArbitragePosition) convergence(currentSpread, shortPrice, longPrice float64) float64 {
+	short, long := p.shortFill(), p.longFill()
+	edge := math.Min(short.Quantity, long.Quantity) * (short.AvgPrice() - long.AvgPrice())
+	if common.IsNegativeOrZero(edge) {
+		return (p.EntrySpread - currentSpread) / p.EntrySpread * 100.0
+	}
+
+	// Unrealized P&L of both legs; a quantity mismatch between them shows up as directional exposure
+	captured := (short.Notional - short.Quantity*shortPrice) + (long.Quantity*longPrice - long.Notional)
+	return captured / edge * 100.0
+}
+
+// addFill records a leg's fill and accumulates its fee and slippage against the price it was expected at
+// leg is "open_spot", "open_perp", "close_spot" or "close_perp"
+func (p *ArbitragePosition) addFill(leg string, result *common.TradeResult, expected float64, isBuy bool) {
+	exchange := p.perpExchange()
+	if strings.HasSuffix(leg, "_spot") {
+		exchange = p.spotExchange()
+	}
+	p.addVenueFill(leg, exchange, result, expected, isBuy)
+}
+
+// addVenueFill is addFill for a leg filled on the given venue, used by spot legs routed across venues
+func (p *ArbitragePosition) addVenueFill(leg string, exchange common.ExchangeType, result *common.TradeResult, expected float64, isBuy bool) {
+	if result == nil {
+		return
+	}
+
+	slippage := slippagePct(result, expected, isBuy)
+	diverged := p.checkDivergence(leg, exchange, slippage)
+	fee, rebate := legFees(result)
+	now := time.Now()
+
+	p.mu.Lock()
+	p.Fees += fee
+	p.Rebates += rebate
+	p.Slippage += slippage
+	if strings.HasPrefix(leg, "open_") {
+		fill := &p.PerpFill
+		if strings.HasSuffix(leg, "_spot") {
+			fill = &p.SpotFill
+		}
+		fill.Quantity += result.ExecutedQty
+		fill.Notional += result.ExecutedPrice * result.ExecutedQty
+		if result.OrderID != "" {
+			fill.OrderIDs = append(fill.OrderIDs, result.OrderID)
+		}
+	}
+	if strings.HasPrefix(leg, "open_") && p.FirstFillAt.IsZero() {
+		p.FirstFillAt = now
+	}
+	p.Legs = append(p.Legs, storage.LegExecution{
+		Leg:         leg,
+		Exchange:    string(exchange),
+		Expected:    expected,
+		Price:       result.ExecutedPrice,
+		Quantity:    result.ExecutedQty,
+		Fee:         fee,
+		MakerQty:    result.MakerQty,
+		Rebate:      rebate,
+		SlippagePct: slippage,
+		Diverged:    diverged,
+		FilledAt:    now,
+	})
+	p.mu.Unlock()
+
+	persistPositions()
+}
+
+// legFees splits a fill's fee into what was paid and the maker rebate the venue reported
+// A negative fee outside the maker path is a rebate as well
+func legFees(result *common.TradeResult) (fee, rebate float64) {
+	if result.Fee < 0 {
+		return 0, result.Rebate - result.Fee
+	}
+	return result.Fee, result.Rebate
+}
+
+// executionRecord builds the position's execution-quality record once it has closed
+func (p *ArbitragePosition) executionRecord(exitSpread, totalProfit float64, closeTime time.Time) storage.ExecutionRecord {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	record := storage.ExecutionRecord{
+		TradeID:         p.TradeID,
+		Strategy:        p.Strategy,
+		ConfigVersion:   p.ConfigVersion,
+		Experiment:      p.Experiment,
+		Variant:         p.Variant,
+		Pair:            p.PairName,
+		SpotExchange:    string(p.spotExchange()),
+		FuturesExchange: string(p.perpExchange()),
+		Reverse:         p.Reverse,
+		DetectedAt:      p.DetectedAt,
+		FirstFillAt:     p.FirstFillAt,
+		DecisionSpread:  p.EntrySpread,
+		RealizedSpread:  p.realizedEntrySpread(),
+		ExitReason:      p.ExitReason,
+		ExitSpread:      exitSpread,
+		Legs:            append([]storage.LegExecution(nil), p.Legs...),
+		TotalProfit:     totalProfit,
+		OpenTime:        p.EntryTime,
+		CloseTime:       closeTime,
+	}
+	if !p.DetectedAt.IsZero() && !p.FirstFillAt.IsZero() {
+		record.DetectToFillMs = float64(p.FirstFillAt.Sub(p.DetectedAt).Microseconds()) / 1000.0
+	}
+	if !common.IsZero(record.RealizedSpread) {
+		record.SpreadCapture = record.RealizedSpread - record.DecisionSpread
+	}
+	for _, leg := range p.Legs {
+		record.Diverged = record.Diverged || leg.Diverged
+		record.MaxDivergence = max(record.MaxDivergence, math.Abs(leg.SlippagePct)*100)
+	}
+	return record
+}
+
+// tradeSummary builds the position's published summary, decomposing where its edge went
+func (p *ArbitragePosition) tradeSummary(exitSpread, spotProfit, futuresProfit, totalProfit float64, closeTime time.Time) redis.TradeSummary {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	summary := redis.TradeSummary{
+		TradeID:                p.TradeID,
+		Strategy:               p.Strategy,
+		ConfigVersion:          p.ConfigVersion,
+		Experiment:             p.Experiment,
+		Variant:                p.Variant,
+		Pair:                   p.PairName,
+		SpotExchange:           string(p.spotExchange()),
+		FuturesExchange:        string(p.perpExchange()),
+		EntrySpread:            p.EntrySpread,
+		ExitSpread:             exitSpread,
+		SpotProfit:             spotProfit,
+		FuturesProfit:          futuresProfit,
+		TotalProfit:            totalProfit,
+		Amount:                 p.AmountUSDT,
+		Duration:               closeTime.Sub(p.EntryTime).Seconds(),
+		OpenTime:               p.EntryTime,
+		CloseTime:              closeTime,
+		TheoreticalEntrySpread: p.EntrySpread,
+		RealizedEntrySpread:    p.realizedEntrySpread(),
+		TotalFees:              p.Fees,
+		TotalRebates:           p.Rebates,
+	}
+
+	// Each leg's slippage is a share of the notional it was expected to fill
+	for _, leg := range p.Legs {
+		cost := leg.SlippagePct / 100.0 * leg.Expected * leg.Quantity
+		if strings.HasPrefix(leg.Leg, "open_") {
+			summary.EntrySlippage += cost
+		} else {
+			summary.ExitSlippage += cost
+		}
+	}
+
+	// Shorts receive positive funding, longs (reverse trades) pay it
+	rate := funding.SettledBetween(string(p.perpExchange()), p.PairName, p.EntryTime, closeTime)
+	if p.Reverse {
+		rate = -rate
+	}
+	notional := p.PerpFill.Notional
+	if common.IsNegativeOrZero(notional) {
+		notional = p.AmountUSDT
+	}
+	summary.Funding = rate * notional
+	return summary
+}
+
+// realizedEntrySpread returns the entry spread achieved by the open fills, or 0 when a leg is missing
+// Callers must hold p.mu.
+func (p *ArbitragePosition) realizedEntrySpread() float64 {
+	var spot, perp float64
+	for _, leg := range p.Legs {
+		switch leg.Leg {
+		case "open_spot":
+			spot = leg.Price
+		case "open_perp":
+			perp = leg.Price
+		}
+	}
+	if common.IsNegativeOrZero(spot) || common.IsNegativeOrZero(perp) {
+		return 0
+	}
+	// Forward sells the perp above spot, reverse sells spot above the perp
+	if p.Reverse {
+		return (spot - perp) / perp * 100.0
+	}
+	return (perp - spot) / spot * 100.0
+}
+
+// slippagePct returns how far a fill landed from the expected price in %, positive when worse
+func slippagePct(result *common.TradeResult, expected float64, isBuy bool) float64 {
+	if common.IsNegativeOrZero(expected) || common.IsNegativeOrZero(result.ExecutedPrice) {
+		return 0
+	}
+	slippage := (result.ExecutedPrice - expected) / expected * 100.0
+	if !isBuy {
+		slippage = -slippage
+	}
+	return slippage
+}
+
+// UpdatePrices is called by the analyzer with the executable close prices of a venue pair: the short
+// leg's best ask and the long leg's best bid
 func UpdatePrices(pairName string, shortExchange string, shortPrice float64, longExchange string, longPrice float64) {
 	positionsMutex.RLock()
 	position, exists := activePositions[pairName]
@@ -50,32 +363,51 @@ func UpdatePrices(pairName string, shortExchange string, shortPrice float64, lon
 	position.mu.Lock()
 	defer position.mu.Unlock()
 
-	// Calculate current spread
+	// Exit spread on the sides the legs close on
 	currentSpread := ((shortPrice - longPrice) / longPrice) * 100.0
+	position.CurrentSpread = currentSpread
+	position.CurrentShort = shortPrice
+	position.CurrentLong = longPrice
 
-	// Calculate spread convergence percentage
-	spreadConvergence := ((position.EntrySpread - currentSpread) / position.EntrySpread) * 100.0
+	// Convergence on what the legs actually filled at, not the nominal amount
+	spreadConvergence := position.convergence(currentSpread, shortPrice, longPrice)
+	expectedProfit := position.expectedProfit(shortPrice, longPrice)
 
 	elapsedTime := time.Since(position.EntryTime).Seconds()
 
 	// Only log every 2 seconds to avoid spam
 	timeSinceLastLog := time.Since(position.LastLogTime).Seconds()
 	if timeSinceLastLog >= 2.0 {
-		log.Printf("[TRACK %s] Entry: %.2f%% | Current: %.2f%% | Convergence: %.1f%% | Time: %.0fs",
-			pairName, position.EntrySpread, currentSpread, spreadConvergence, elapsedTime)
+		log.Printf("[TRACK %s] Entry: %.2f%% | Current: %.2f%% | Convergence: %.1f%% | Expected: %.4f USDT | Time: %.0fs",
+			pairName, position.EntrySpread, currentSpread, spreadConvergence, expectedProfit, elapsedTime)
 		position.LastLogTime = time.Now()
 	}
 
 	// Exit conditions:
-	// 1. Spread has converged by 60% or more (profit target)
-	// 2. Spread has reversed (negative means prices crossed)
-	// 3. Maximum hold time of 60 seconds (safety exit)
+	// 1. Combined PnL fell to STOP_LOSS_USDT (hard stop, see stop_loss.go)
+	// 2. Spread has converged by 60% or more (profit target)
+	// 3. Expected realized profit reached EXIT_PROFIT_USDT (see profit_exit.go)
+	// 4. Spread has reversed (negative means prices crossed)
+	// 5. Maximum hold time of 60 seconds (safety exit)
 	shouldClose := false
 	reason := ""
 
-	if spreadConvergence >= 60.0 {
+	profitTarget := exitProfitTarget()
+	converged := spreadConvergence >= 60.0
+	profitReached := profitTarget > 0 && common.GreaterThanOrEqual(expectedProfit, profitTarget)
+	if profitTarget > 0 && exitProfitWithConvergence() {
+		converged, profitReached = converged && profitReached, false
+	}
+
+	if combined := position.combinedPnL(shortPrice, longPrice); stopLossHit(combined) {
+		shouldClose = true
+		reason = fmt.Sprintf("Stop loss: combined PnL %.4f USDT at or below %.4f USDT", combined, stopLossLimit())
+	} else if converged {
 		shouldClose = true
 		reason = "Spread converged 60%+"
+	} else if profitReached {
+		shouldClose = true
+		reason = fmt.Sprintf("Expected profit %.4f USDT reached %.4f USDT target", expectedProfit, profitTarget)
 	} else if currentSpread <= 0 {
 		shouldClose = true
 		reason = "Spread reversed (prices crossed)"
@@ -83,175 +415,436 @@ func UpdatePrices(pairName string, shortExchange string, shortPrice float64, lon
 		shouldClose = true
 		reason = "Max hold time reached (58s+)"
 		log.Printf("[DEBUG] Triggering close: elapsedTime=%.2f >= 58", elapsedTime)
+	} else if position.partialCloseDue(spreadConvergence) {
+		log.Printf("[PARTIAL %s] Convergence %.1f%% reached partial target %.1f%%", pairName, spreadConvergence, partialCloseAt())
+		position.PartialClosed = true
+		position.partialDone = make(chan struct{})
+		go partialClose(position)
 	}
 
 	if shouldClose {
 		log.Printf("[CLOSE %s] Reason: %s | Held for: %.0fs", pairName, reason, elapsedTime)
-		go closePosition(position)
+		go closePosition(position, reason)
 	}
 }
 
-func closePosition(position *ArbitragePosition) {
+func closePosition(position *ArbitragePosition, reason string) {
+	closeStart := time.Now()
+
 	position.mu.Lock()
 	if !position.IsOpen {
 		position.mu.Unlock()
 		return
 	}
 	position.IsOpen = false
+	position.IsClosing = true
+	position.ExitReason = reason
 	position.mu.Unlock()
+	persistPositions()
 
-	ctx := context.Background()
-	var wg sync.WaitGroup
-	wg.Add(2)
+	// Let an in-flight partial close finish so only what it left is closed
+	position.waitPartialClose()
+
+	position.mu.RLock()
+	closeSpread := position.CurrentSpread
+	closeShort, closeLong := position.CurrentShort, position.CurrentLong
+	position.mu.RUnlock()
+
+	ctx := position.tradeContext()
 
 	spotProfit := 0.00
 	futuresProfit := 0.00
 
-	go func() {
-		defer wg.Done()
-		var err error
-		futuresProfit, err = clients.Execute(ctx, position.ShortExchange, common.CloseFuturesShort, position.PairName, position.AmountUSDT)
-		if err != nil {
-			log.Printf("[ERROR] Failed to close futures short: %v", err)
-		}
-	}()
-
-	go func() {
-		defer wg.Done()
-		var err error
-		spotProfit, err = clients.Execute(ctx, position.LongExchange, common.CloseSpotLong, position.PairName, position.AmountUSDT)
-		if err != nil {
-			log.Printf("[ERROR] Failed to close spot long: %v", err)
+	if position.Reverse {
+		spotProfit, futuresProfit = closeReverseLegs(ctx, position, closeSpread, closeShort, closeLong)
+	} else {
+		var wg sync.WaitGroup
+		var spotMu sync.Mutex
+		routes := position.spotRoutes()
+		wg.Add(1 + len(routes))
+
+		go func() {
+			defer wg.Done()
+			result, profit, err := clients.Execute(ctx, position.ShortExchange, position.perpCloseCommand(), position.PairName, position.AmountUSDT, closeSpread)
+			futuresProfit = profit
+			position.addFill("close_perp", result, closeShort, true)
+			if err != nil {
+				log.Printf("[ERROR] Failed to close futures short: %v", err)
+			}
+		}()
+
+		for _, route := range routes {
+			go func(route storage.SpotRoute) {
+				defer wg.Done()
+				exchange := common.ExchangeType(route.Exchange)
+				result, profit, err := clients.Execute(ctx, exchange, common.CloseSpotLong, position.PairName, route.AmountUSDT, closeSpread)
+				spotMu.Lock()
+				spotProfit += profit
+				spotMu.Unlock()
+				position.addVenueFill("close_spot", exchange, result, closeLong, false)
+				if err != nil {
+					log.Printf("[ERROR] Failed to close spot long on %s: %v", exchange, err)
+				}
+			}(route)
 		}
-	}()
 
-	wg.Wait()
+		wg.Wait()
+	}
+
+	captureBooks(position, "close", closeStart)
 
-	totalProfit := spotProfit + futuresProfit
-	duration := time.Since(position.EntryTime).Seconds()
+	// A partial close's proceeds are already in the close's balance diff (see partial_close.go)
 
-	log.Printf("[💰 RESULT %s] Total Profit: %.4f USDT | Spot: %.4f | Futures: %.4f",
-		position.PairName, totalProfit, spotProfit, futuresProfit)
+	if globalBooks != nil {
+		globalBooks.UnwatchVenues(position.PairName)
+	}
+
+	hedgeProfit := closeOptionHedge(ctx, position)
+	totalProfit := spotProfit + futuresProfit + hedgeProfit
+	closeTime := time.Now()
+	duration := closeTime.Sub(position.EntryTime).Seconds()
+
+	log.Printf("[💰 RESULT %s] %s | Total Profit: %.4f USDT | Spot: %.4f | Futures: %.4f | Options: %.4f",
+		position.PairName, position.TradeID, totalProfit, spotProfit, futuresProfit, hedgeProfit)
+	risk.RecordTradeResult(totalProfit)
+
+	notify.Send(notify.Message{
+		Event: notify.EventSummary,
+		Title: fmt.Sprintf("Closed %s: %+.4f USDT", position.PairName, totalProfit),
+		Body: fmt.Sprintf("Spot %s: %+.4f\nFutures %s: %+.4f\nEntry spread: %.3f%% | Exit spread: %.3f%%\nAmount: %.2f USDT | Held: %.0fs",
+			position.spotExchange(), spotProfit, position.perpExchange(), futuresProfit,
+			position.EntrySpread, closeSpread, position.AmountUSDT, duration),
+	})
 
 	// Publish trade summary to Redis
-	redis.PublishTradeSummary(redis.TradeSummary{
+	redis.PublishTradeSummary(position.tradeSummary(closeSpread, spotProfit, futuresProfit, totalProfit, closeTime))
+
+	position.mu.RLock()
+	paid, rebates, slippage := position.Fees, position.Rebates, position.Slippage
+	position.mu.RUnlock()
+
+	// Trade log feeds the daily report
+	if err := storage.AppendTrade(storage.TradeRecord{
+		TradeID:         position.TradeID,
+		Strategy:        position.Strategy,
+		ConfigVersion:   position.ConfigVersion,
+		Experiment:      position.Experiment,
+		Variant:         position.Variant,
 		Pair:            position.PairName,
-		SpotExchange:    string(position.LongExchange),
-		FuturesExchange: string(position.ShortExchange),
+		SpotExchange:    string(position.spotExchange()),
+		FuturesExchange: string(position.perpExchange()),
 		EntrySpread:     position.EntrySpread,
-		ExitSpread:      0, // Exit spread not tracked in real execution
+		ExitSpread:      closeSpread,
 		SpotProfit:      spotProfit,
 		FuturesProfit:   futuresProfit,
+		HedgeProfit:     hedgeProfit,
 		TotalProfit:     totalProfit,
+		Fees:            paid,
+		Rebates:         rebates,
+		Slippage:        slippage,
 		Amount:          position.AmountUSDT,
-		Duration:        duration,
 		OpenTime:        position.EntryTime,
-		CloseTime:       time.Now(),
-	})
-
-	// Remove from active positions
-	positionsMutex.Lock()
-	delete(activePositions, position.PairName)
-	positionsMutex.Unlock()
+		CloseTime:       closeTime,
+	}); err != nil {
+		log.Printf("[ERROR] Failed to record trade %s: %v", position.PairName, err)
+	}
 
-	// Reset execution flag to allow next trade
-	if globalAnalyzer != nil {
-		globalAnalyzer.ResetExecutionFlag()
+	if err := storage.AppendExecution(position.executionRecord(closeSpread, totalProfit, closeTime)); err != nil {
+		log.Printf("[ERROR] Failed to record execution quality %s: %v", position.PairName, err)
 	}
 
+	// Keep the pair blocked until both venues report flat, so a new entry
+	// cannot start while close orders are still settling
+	closeUntilFlat(ctx, position)
+
 	// Position closed successfully - ready for next trade
 	log.Printf("✅ Position closed successfully. Ready for next opportunity.")
 }
 
 func ConsiderArbitrageOpportunity(ctx context.Context, shortExchange common.ExchangeType, shortPrice float64, longExchange common.ExchangeType,
-	longPrice float64, pairName string, diffPercent float64, amountUSDT float64) {
+	longPrice float64, pairName string, diffPercent float64, amountUSDT float64, detectedAt time.Time, routes []orderbook.SpotRoute) {
 
-	if common.LessThan(diffPercent, 1.5) {
+	if common.LessThan(diffPercent, orderbook.EntrySpreadPct(pairName, string(longExchange), string(shortExchange))) {
 		return
 	}
 
-	// Check if already have an open position for this pair
-	positionsMutex.RLock()
-	_, exists := activePositions[pairName]
-	positionsMutex.RUnlock()
-
-	if exists {
-		log.Printf("[SKIP %s] Position already open", pairName)
-		return
+	// Vary the size so the trade's orders don't repeat the same notional on every venue
+	legs := []entryLeg{{exchange: shortExchange, price: shortPrice, amountUSDT: amountUSDT}}
+	for _, route := range routes {
+		legs = append(legs, entryLeg{exchange: common.ExchangeType(route.Exchange), isSpot: true, price: route.Price, amountUSDT: route.VolumeUSD})
 	}
-
-	log.Printf("[OPEN %s] Short: %s@%.6f | Long: %s@%.6f | Spread: %.2f%%",
-		pairName, shortExchange, shortPrice, longExchange, longPrice, diffPercent)
+	jitter := sizeJitter(pairName, legs...)
+	amountUSDT *= jitter
 
 	// Create position tracking
+	tag := currentStrategy()
+	tag.Experiment, tag.Variant = assignVariant()
+	ctx = common.WithStrategy(ctx, tag)
 	position := &ArbitragePosition{
+		TradeID:         common.TradeID(ctx),
+		Strategy:        tag.Strategy,
+		ConfigVersion:   tag.ConfigVersion,
+		Experiment:      tag.Experiment,
+		Variant:         tag.Variant,
 		PairName:        pairName,
 		ShortExchange:   shortExchange,
 		LongExchange:    longExchange,
 		EntryShortPrice: shortPrice,
 		EntryLongPrice:  longPrice,
 		EntrySpread:     diffPercent,
+		CurrentSpread:   diffPercent,
+		CurrentShort:    shortPrice,
+		CurrentLong:     longPrice,
 		AmountUSDT:      amountUSDT,
 		EntryTime:       time.Now(),
 		LastLogTime:     time.Now(),
 		IsOpen:          true,
+		DetectedAt:      detectedAt,
+		Inverse:         clients.UsesInverse(shortExchange, pairName),
+	}
+	for _, route := range routes {
+		position.SpotRoutes = append(position.SpotRoutes, storage.SpotRoute{Exchange: route.Exchange, Price: route.Price, AmountUSDT: route.VolumeUSD * jitter})
 	}
 
+	// Check and register under one lock so two opportunities cannot both pass the check
 	positionsMutex.Lock()
+	if existing, exists := activePositions[pairName]; exists {
+		positionsMutex.Unlock()
+		existing.mu.RLock()
+		closing := existing.IsClosing
+		existing.mu.RUnlock()
+		if closing {
+			log.Printf("[SKIP %s] Previous position still closing", pairName)
+		} else {
+			log.Printf("[SKIP %s] Position already open", pairName)
+		}
+		return
+	}
 	activePositions[pairName] = position
 	positionsMutex.Unlock()
+	persistPositions()
 
-	// Start a safety timer to force close after 65 seconds if UpdatePrices fails
-	go func() {
-		time.Sleep(65 * time.Second)
-		position.mu.RLock()
-		stillOpen := position.IsOpen
-		position.mu.RUnlock()
+	// Every stage up to submission spends from the latency budget counted from detection
+	budget := newLatencyBudget(pairName, detectedAt)
+	if err := budget.stage("gate"); err != nil {
+		abandonEntry(pairName, err)
+		return
+	}
 
-		if stillOpen {
-			log.Printf("[FORCE CLOSE %s] Safety timer triggered - position held too long", pairName)
-			closePosition(position)
+	if iocEntries() {
+		if err := position.checkIOCEntry(); err != nil {
+			abandonEntry(pairName, err)
+			return
 		}
-	}()
+	}
 
-	var wg sync.WaitGroup
-	wg.Add(2)
+	if err := guardExistingExposure(ctx, position); err != nil {
+		abandonEntry(pairName, err)
+		return
+	}
+	if err := budget.stage("presence"); err != nil {
+		abandonEntry(pairName, err)
+		return
+	}
 
-	go func() {
-		defer wg.Done()
-		_, err := clients.Execute(ctx, shortExchange, common.PutFuturesShort, pairName, amountUSDT)
-		if err != nil {
-			log.Printf("[ERROR] Failed to open futures short: %v", err)
-			position.mu.Lock()
-			position.IsOpen = false
-			position.mu.Unlock()
+	// Lock each leg's USDT so a concurrent opportunity on the same venue can't size against it;
+	// the locks are released once the open legs completed or failed and balances reflect the spend
+	for _, route := range position.spotRoutes() {
+		exchange := common.ExchangeType(route.Exchange)
+		if err := clients.LockCapital(ctx, exchange, "spot", pairName, route.AmountUSDT); err != nil {
+			abandonEntry(pairName, err)
+			return
 		}
-	}()
+		defer clients.UnlockCapital(exchange, "spot", pairName)
+	}
 
-	go func() {
-		defer wg.Done()
-		_, err := clients.Execute(ctx, longExchange, common.PutSpotLong, pairName, amountUSDT)
-		if err != nil {
-			log.Printf("[ERROR] Failed to open spot long: %v", err)
-			position.mu.Lock()
-			position.IsOpen = false
-			position.mu.Unlock()
+	// Coin-margined shorts are backed by coin, checked by the venue client when the order is sized
+	if !position.Inverse {
+		if err := clients.LockCapital(ctx, shortExchange, "futures", pairName, amountUSDT); err != nil {
+			abandonEntry(pairName, err)
+			return
 		}
-	}()
+		defer clients.UnlockCapital(shortExchange, "futures", pairName)
+	}
 
-	wg.Wait()
+	// The venue combination's playbook, or the experiment's variant, decides leg order, modes and checks
+	playbook := position.playbook()
+	ctx = clients.WithPlaybook(ctx, playbook)
+
+	submitStage := "capital"
+	if playbook.Preflight {
+		if err := budget.stage("capital"); err != nil {
+			abandonEntry(pairName, err)
+			return
+		}
+		if err := preflightEntry(ctx, position); err != nil {
+			abandonEntry(pairName, err)
+			return
+		}
+		submitStage = "preflight"
+	}
+	if err := budget.submitted(submitStage); err != nil {
+		abandonEntry(pairName, err)
+		return
+	}
+
+	log.Printf("[OPEN %s] %s | Short: %s@%.6f | Long: %s@%.6f | Spread: %.2f%%",
+		pairName, position.TradeID, shortExchange, shortPrice, longExchange, longPrice, diffPercent)
+	for _, route := range position.SpotRoutes {
+		log.Printf("[ROUTE %s] Spot %s@%.6f for %.2f USDT", pairName, route.Exchange, route.Price, route.AmountUSDT)
+	}
+
+	// Start a safety timer to force close after 65 seconds if UpdatePrices fails
+	startSafetyTimer(position)
+
+	position.openForwardLegs(ctx, playbook, diffPercent)
+	captureBooks(position, "open", detectedAt)
 
 	// If opening failed, clean up
 	position.mu.RLock()
 	isOpen := position.IsOpen
 	position.mu.RUnlock()
 
+	if isOpen && playbook.Verify == clients.VerifyStrict {
+		if err := verifyOpenedLegs(ctx, position); err != nil {
+			log.Printf("[ERROR] %s - %v, unwinding", pairName, err)
+			position.mu.Lock()
+			position.IsOpen = false
+			position.mu.Unlock()
+			isOpen = false
+		}
+	}
+
 	if !isOpen {
-		positionsMutex.Lock()
-		delete(activePositions, pairName)
-		positionsMutex.Unlock()
 		log.Printf("[FAILED %s] Could not open position", pairName)
+
+		// One leg may have filled; close it now and only release the pair once both venues are flat
+		position.mu.Lock()
+		position.IsClosing = true
+		position.mu.Unlock()
+		persistPositions()
+		go func() {
+			retryCloseLegs(position.tradeContext(), position)
+			closeUntilFlat(position.tradeContext(), position)
+		}()
 	} else {
-		log.Printf("[OPENED %s] Position opened successfully, monitoring for exit...", pairName)
+		position.mu.RLock()
+		spotFill, perpFill := position.SpotFill, position.PerpFill
+		position.mu.RUnlock()
+		log.Printf("[OPENED %s] Position opened successfully (spot %.8g @ %.6f = %.2f USDT, perp %.8g @ %.6f = %.2f USDT), monitoring for exit...",
+			pairName, spotFill.Quantity, spotFill.AvgPrice(), spotFill.Notional, perpFill.Quantity, perpFill.AvgPrice(), perpFill.Notional)
+		notify.Send(notify.Message{
+			Event: notify.EventTrade,
+			Title: fmt.Sprintf("Opened %s", pairName),
+			Body: fmt.Sprintf("Long spot %s @ %.6f\nShort perp %s @ %.6f\nSpread: %.3f%% | Amount: %.2f USDT",
+				longExchange, longPrice, shortExchange, shortPrice, diffPercent, amountUSDT),
+		})
+
+		// Track exits on the venues' own books rather than the aggregated signal
+		if globalBooks != nil {
+			globalBooks.WatchVenues(pairName, string(longExchange), string(shortExchange))
+		}
+
+		go hedgeWithOptions(position)
+	}
+}
+
+// abandonEntry drops a registered position before any order was sent and frees the analyzer
+func abandonEntry(pairName string, reason error) {
+	log.Printf("[SKIP %s] %v", pairName, reason)
+
+	positionsMutex.Lock()
+	delete(activePositions, pairName)
+	positionsMutex.Unlock()
+	persistPositions()
+
+	if globalAnalyzer != nil {
+		globalAnalyzer.ResetExecutionFlag()
+	}
+}
+
+// guardExistingExposure refuses an entry while any of its legs' venues already holds exposure in the pair,
+// unless ENTRY_PRESENCE_GUARD is off
+func guardExistingExposure(ctx context.Context, position *ArbitragePosition) error {
+	if !clients.PresenceGuardEnabled() {
+		return nil
+	}
+	return checkExistingExposure(ctx, position)
+}
+
+// checkExistingExposure returns an error while any of the entry's legs' venues holds exposure in the pair
+// Exposure this bot opened but no longer tracks also alerts, since it has to be closed by hand
+func checkExistingExposure(ctx context.Context, position *ArbitragePosition) error {
+	type leg struct {
+		exchange common.ExchangeType
+		market   string
+	}
+	legs := []leg{{position.perpExchange(), position.perpMarket()}}
+	// Reverse positions sell standing inventory, so their spot venue always holds the pair
+	if !position.Reverse {
+		for _, route := range position.spotRoutes() {
+			legs = append(legs, leg{common.ExchangeType(route.Exchange), "spot"})
+		}
+	}
+
+	errs := make([]error, len(legs))
+	var wg sync.WaitGroup
+	for i, l := range legs {
+		wg.Add(1)
+		go func(i int, l leg) {
+			defer wg.Done()
+			exposure, err := clients.ExistingExposure(ctx, l.exchange, l.market, position.PairName)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			if exposure == nil {
+				return
+			}
+			if exposure.Bot {
+				notify.Send(notify.Message{
+					Event: notify.EventAlert,
+					Title: fmt.Sprintf("Untracked %s position on %s %s", position.PairName, l.exchange, l.market),
+					Body:  fmt.Sprintf("%s.\nNew entries on %s are refused until it is closed.", exposure, position.PairName),
+				})
+			}
+			errs[i] = fmt.Errorf("refusing to open: %s", exposure)
+		}(i, l)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// confirmFlat polls both legs' venues, every routed spot venue included, until none holds exposure for the pair
+// A spot venue holds exposure while the position's own residual there is an orderable size (see spotResidual)
+// Reverse positions only check the perp, since the spot venue keeps its standing inventory
+func confirmFlat(ctx context.Context, position *ArbitragePosition) bool {
+	deadline := time.Now().Add(15 * time.Second)
+
+	for {
+		spotFlat, spotErr := true, error(nil)
+		if !position.Reverse {
+			for _, route := range position.spotRoutes() {
+				residual, err := spotResidual(ctx, position, route)
+				if err != nil {
+					spotErr = err
+				}
+				spotFlat = spotFlat && err == nil && common.IsZero(residual)
+			}
+		}
+		futuresFlat, futuresErr := clients.IsFlat(ctx, position.perpExchange(), position.perpMarket(), position.PairName)
+
+		if spotErr == nil && futuresErr == nil && spotFlat && futuresFlat {
+			return true
+		}
+
+		if time.Now().After(deadline) {
+			log.Printf("[FLAT CHECK %s] Spot flat: %v (err: %v) | Futures flat: %v (err: %v)",
+				position.PairName, spotFlat, spotErr, futuresFlat, futuresErr)
+			return false
+		}
+
+		time.Sleep(1 * time.Second)
 	}
 }
@@ -0,0 +1,150 @@
+package backtest
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"arbitrage.trade/config"
+	"arbitrage.trade/orderbook"
+)
+
+// Feed recording
+//
+// With FEED_RECORD_FILE set (default empty, disabled) every venue's top of book is sampled each
+// FEED_RECORD_INTERVAL (default 1s) and appended to it as JSON lines, the input of the parameter
+// sweep (`arbitrage.trade sweep`). A quote is only written when it changed since its last sample.
+
+// Quote is one venue market's top of book at a point in time
+type Quote struct {
+	Time     time.Time `json:"t"`
+	Pair     string    `json:"pair"`
+	Exchange string    `json:"exchange"`
+	Spot     bool      `json:"spot"`
+	Bid      float64   `json:"bid"`
+	BidUSDT  float64   `json:"bid_usdt"`
+	Ask      float64   `json:"ask"`
+	AskUSDT  float64   `json:"ask_usdt"`
+}
+
+// BookSource is where the recorder reads venue books from
+type BookSource interface {
+	GetBook(pairName, exchangeName string, isSpot bool) (*orderbook.OrderBook, bool)
+}
+
+var recordMu sync.Mutex
+
+// StartRecorder samples the pairs' books on the exchanges into FEED_RECORD_FILE in the background
+func StartRecorder(books BookSource, exchanges []string, pairs []string) {
+	path := config.GetString("FEED_RECORD_FILE", "")
+	if path == "" {
+		return
+	}
+
+	interval := config.GetDuration("FEED_RECORD_INTERVAL", time.Second)
+	log.Printf("[RECORD] Recording top of book every %s to %s", interval, path)
+
+	go func() {
+		last := make(map[string]Quote)
+		for {
+			now := time.Now()
+			var quotes []Quote
+			for _, pairName := range pairs {
+				for _, exchange := range exchanges {
+					for _, spot := range []bool{true, false} {
+						quote, ok := sampleQuote(books, pairName, exchange, spot, now)
+						if !ok {
+							continue
+						}
+						key := fmt.Sprintf("%s|%s|%v", pairName, exchange, spot)
+						if prev, seen := last[key]; seen && prev.Bid == quote.Bid && prev.Ask == quote.Ask {
+							continue
+						}
+						last[key] = quote
+						quotes = append(quotes, quote)
+					}
+				}
+			}
+
+			if err := appendQuotes(path, quotes); err != nil {
+				log.Printf("[RECORD] %v", err)
+			}
+			time.Sleep(interval)
+		}
+	}()
+}
+
+// sampleQuote reads a venue market's top of book, false when either side is empty
+func sampleQuote(books BookSource, pairName, exchange string, spot bool, now time.Time) (Quote, bool) {
+	book, ok := books.GetBook(pairName, exchange, spot)
+	if !ok {
+		return Quote{}, false
+	}
+	bid, bidVol, bidOk := book.GetBestBid()
+	ask, askVol, askOk := book.GetBestAsk()
+	if !bidOk || !askOk {
+		return Quote{}, false
+	}
+	return Quote{Time: now, Pair: pairName, Exchange: exchange, Spot: spot, Bid: bid, BidUSDT: bidVol, Ask: ask, AskUSDT: askVol}, true
+}
+
+// appendQuotes appends quotes to the recording
+func appendQuotes(path string, quotes []Quote) error {
+	if len(quotes) == 0 {
+		return nil
+	}
+
+	recordMu.Lock()
+	defer recordMu.Unlock()
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open feed recording: %w", err)
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	for _, quote := range quotes {
+		line, err := json.Marshal(quote)
+		if err != nil {
+			return fmt.Errorf("failed to marshal quote: %w", err)
+		}
+		w.Write(append(line, '\n'))
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to write quotes: %w", err)
+	}
+	return nil
+}
+
+// LoadQuotes returns the recorded quotes grouped by pair, each oldest first
+func LoadQuotes(path string) (map[string][]Quote, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open feed recording: %w", err)
+	}
+	defer f.Close()
+
+	byPair := make(map[string][]Quote)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var quote Quote
+		if err := json.Unmarshal(scanner.Bytes(), &quote); err != nil {
+			continue
+		}
+		byPair[quote.Pair] = append(byPair[quote.Pair], quote)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, quotes := range byPair {
+		sort.SliceStable(quotes, func(i, j int) bool { return quotes[i].Time.Before(quotes[j].Time) })
+	}
+	return byPair, nil
+}
@@ -0,0 +1,130 @@
+package backtest
+
+import (
+	"time"
+)
+
+// Params is one strategy configuration replayed against the recorded quotes
+type Params struct {
+	Threshold   float64       // Entry spread in %, perp bid over spot ask
+	Convergence float64       // % of the entry edge captured that closes the position
+	MaxHold     time.Duration // Safety exit
+	FeePct      float64       // Assumed taker fee of each of the four legs in %
+}
+
+// Result is the outcome of replaying one configuration
+type Result struct {
+	Trades int     `json:"trades"`
+	Wins   int     `json:"wins"`
+	Profit float64 `json:"profit"` // USDT, net of fees
+	Fees   float64 `json:"fees"`
+	Volume float64 `json:"volume"` // Entry notional, USDT
+}
+
+// Add accumulates another run's result
+func (r *Result) Add(other Result) {
+	r.Trades += other.Trades
+	r.Wins += other.Wins
+	r.Profit += other.Profit
+	r.Fees += other.Fees
+	r.Volume += other.Volume
+}
+
+// Quotes older than this are not traded against, the venue's feed was likely down
+const maxQuoteAge = 10 * time.Second
+
+type simPosition struct {
+	spotExchange string
+	perpExchange string
+	quantity     float64
+	spotEntry    float64
+	perpEntry    float64
+	opened       time.Time
+}
+
+// Simulate replays one pair's quotes (oldest first) with the configuration, one position at a time,
+// the way the live loop trades forward: buy spot at its ask and short the perp at its bid on another
+// venue when the spread reaches the threshold, then close on the sides the legs close on once the
+// edge has converged, the spread has crossed or the hold time is up. A position still open when the
+// quotes end is closed at the last prices.
+func Simulate(quotes []Quote, p Params, notional float64) Result {
+	var result Result
+	spot := make(map[string]Quote)
+	perp := make(map[string]Quote)
+	var pos *simPosition
+
+	closeAt := func(spotBid, perpAsk float64) {
+		q := pos.quantity
+		captured := q*(pos.perpEntry-perpAsk) + q*(spotBid-pos.spotEntry)
+		fees := q * (pos.spotEntry + pos.perpEntry + spotBid + perpAsk) * p.FeePct / 100.0
+		profit := captured - fees
+
+		result.Trades++
+		result.Profit += profit
+		result.Fees += fees
+		result.Volume += q * pos.spotEntry
+		if profit > 0 {
+			result.Wins++
+		}
+		pos = nil
+	}
+
+	for _, quote := range quotes {
+		if quote.Spot {
+			spot[quote.Exchange] = quote
+		} else {
+			perp[quote.Exchange] = quote
+		}
+		now := quote.Time
+
+		if pos != nil {
+			spotQ, perpQ := spot[pos.spotExchange], perp[pos.perpExchange]
+			q := pos.quantity
+			edge := q * (pos.perpEntry - pos.spotEntry)
+			captured := q*(pos.perpEntry-perpQ.Ask) + q*(spotQ.Bid-pos.spotEntry)
+			currentSpread := (perpQ.Ask - spotQ.Bid) / spotQ.Bid * 100.0
+
+			if captured/edge*100.0 >= p.Convergence || currentSpread <= 0 || now.Sub(pos.opened) >= p.MaxHold {
+				closeAt(spotQ.Bid, perpQ.Ask)
+			}
+			continue
+		}
+
+		var best *simPosition
+		bestSpread := 0.0
+		for spotEx, spotQ := range spot {
+			if now.Sub(spotQ.Time) > maxQuoteAge {
+				continue
+			}
+			for perpEx, perpQ := range perp {
+				if perpEx == spotEx || now.Sub(perpQ.Time) > maxQuoteAge || perpQ.Bid <= spotQ.Ask {
+					continue
+				}
+				spread := (perpQ.Bid - spotQ.Ask) / spotQ.Ask * 100.0
+				if spread < p.Threshold || spread <= bestSpread {
+					continue
+				}
+
+				volume := min(spotQ.AskUSDT, perpQ.BidUSDT, notional)
+				if volume <= 0 {
+					continue
+				}
+				bestSpread = spread
+				best = &simPosition{
+					spotExchange: spotEx,
+					perpExchange: perpEx,
+					quantity:     volume / spotQ.Ask,
+					spotEntry:    spotQ.Ask,
+					perpEntry:    perpQ.Bid,
+					opened:       now,
+				}
+			}
+		}
+		pos = best
+	}
+
+	if pos != nil {
+		closeAt(spot[pos.spotExchange].Bid, perp[pos.perpExchange].Ask)
+	}
+	return result
+}
@@ -0,0 +1,125 @@
+package backtest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Grid is the set of values each parameter is swept over
+type Grid struct {
+	Thresholds   []float64
+	Convergences []float64
+	MaxHolds     []time.Duration
+	FeePcts      []float64
+}
+
+// Configs returns every combination of the grid's values
+func (g Grid) Configs() []Params {
+	var configs []Params
+	for _, threshold := range g.Thresholds {
+		for _, convergence := range g.Convergences {
+			for _, maxHold := range g.MaxHolds {
+				for _, feePct := range g.FeePcts {
+					configs = append(configs, Params{Threshold: threshold, Convergence: convergence, MaxHold: maxHold, FeePct: feePct})
+				}
+			}
+		}
+	}
+	return configs
+}
+
+// ConfigResult is one configuration's in-sample and out-of-sample outcome
+type ConfigResult struct {
+	Params      Params
+	InSample    Result
+	OutOfSample Result
+}
+
+// PairSweep is one pair's configurations, best in-sample profit first
+type PairSweep struct {
+	Pair    string
+	From    time.Time
+	To      time.Time
+	Results []ConfigResult
+}
+
+// Sweep replays every grid configuration over each pair's quotes with anchored walk-forward splits:
+// the recorded span is cut into folds+1 equal periods and fold k trains on periods [0, k) and tests on
+// period k. In-sample results add up the training runs, out-of-sample ones the (non-overlapping) tests,
+// so a configuration that only fits its training data shows up as a weak out-of-sample profit.
+func Sweep(quotes map[string][]Quote, grid Grid, notional float64, folds int) []PairSweep {
+	if folds < 1 {
+		folds = 1
+	}
+	configs := grid.Configs()
+
+	pairs := make([]string, 0, len(quotes))
+	for pair := range quotes {
+		pairs = append(pairs, pair)
+	}
+	sort.Strings(pairs)
+
+	sweeps := make([]PairSweep, 0, len(pairs))
+	for _, pair := range pairs {
+		pairQuotes := quotes[pair]
+		if len(pairQuotes) == 0 {
+			continue
+		}
+		from, to := pairQuotes[0].Time, pairQuotes[len(pairQuotes)-1].Time
+		period := to.Sub(from) / time.Duration(folds+1)
+
+		// Index of the first quote of each period boundary
+		boundary := func(k int) int {
+			at := from.Add(period * time.Duration(k))
+			return sort.Search(len(pairQuotes), func(i int) bool { return !pairQuotes[i].Time.Before(at) })
+		}
+
+		results := make([]ConfigResult, len(configs))
+		for i, params := range configs {
+			results[i].Params = params
+			for k := 1; k <= folds; k++ {
+				split, end := boundary(k), boundary(k+1)
+				if k == folds {
+					end = len(pairQuotes)
+				}
+				results[i].InSample.Add(Simulate(pairQuotes[:split], params, notional))
+				results[i].OutOfSample.Add(Simulate(pairQuotes[split:end], params, notional))
+			}
+		}
+
+		sort.SliceStable(results, func(i, j int) bool {
+			return results[i].InSample.Profit > results[j].InSample.Profit
+		})
+		sweeps = append(sweeps, PairSweep{Pair: pair, From: from, To: to, Results: results})
+	}
+	return sweeps
+}
+
+// FormatSweep renders each pair's top configurations as a text table
+func FormatSweep(sweeps []PairSweep, top int) string {
+	if len(sweeps) == 0 {
+		return "No recorded quotes\n"
+	}
+
+	var b strings.Builder
+	for _, s := range sweeps {
+		fmt.Fprintf(&b, "%s  %s → %s\n", s.Pair, s.From.Format(time.RFC3339), s.To.Format(time.RFC3339))
+		fmt.Fprintf(&b, "  %-4s %7s %7s %8s %6s | %6s %11s | %6s %6s %11s\n",
+			"#", "Thresh", "Conv%", "MaxHold", "Fee%", "IS #", "IS P&L", "OOS #", "Win%", "OOS P&L")
+		for i, r := range s.Results {
+			if top > 0 && i >= top {
+				break
+			}
+			winRate := 0.0
+			if r.OutOfSample.Trades > 0 {
+				winRate = float64(r.OutOfSample.Wins) / float64(r.OutOfSample.Trades) * 100
+			}
+			fmt.Fprintf(&b, "  %-4d %6.2f%% %7.0f %8s %6.3f | %6d %+11.4f | %6d %6.1f %+11.4f\n",
+				i+1, r.Params.Threshold, r.Params.Convergence, r.Params.MaxHold, r.Params.FeePct,
+				r.InSample.Trades, r.InSample.Profit, r.OutOfSample.Trades, winRate, r.OutOfSample.Profit)
+		}
+	}
+	return b.String()
+}
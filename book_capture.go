@@ -0,0 +1,102 @@
+package main
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"arbitrage.trade/config"
+	"arbitrage.trade/orderbook"
+	"arbitrage.trade/storage"
+)
+
+// Book captures
+//
+// Once a trade's legs opened or closed, the books of its venues from BOOK_CAPTURE_MARGIN (default 2s)
+// before the decision to the same margin after the last fill are copied out of the books' short
+// in-memory history (BOOK_HISTORY_*, see orderbook/history.go) into BOOK_CAPTURES_FILE, so post-trade
+// analysis sees what the books looked like when the trade was decided and when its fills landed
+// (GET /book/captures). BOOK_CAPTURE_ENABLED=false turns it off.
+
+// captureMargin returns how long before the decision and after the last fill books are captured
+func captureMargin() time.Duration {
+	return config.GetDuration("BOOK_CAPTURE_MARGIN", 2*time.Second)
+}
+
+// captureBooks stores the position's venue books around a phase ("open" or "close") decided at decidedAt,
+// in the background once the margin after its last fill has passed
+func captureBooks(position *ArbitragePosition, phase string, decidedAt time.Time) {
+	if globalBooks == nil || !config.GetBool("BOOK_CAPTURE_ENABLED", true) {
+		return
+	}
+
+	position.mu.RLock()
+	lastFill := time.Time{}
+	for _, leg := range position.Legs {
+		if strings.HasPrefix(leg.Leg, phase+"_") && leg.FilledAt.After(lastFill) {
+			lastFill = leg.FilledAt
+		}
+	}
+	position.mu.RUnlock()
+
+	books := []string{"perp:" + string(position.perpExchange())}
+	if position.Reverse {
+		books = append(books, "spot:"+string(position.spotExchange()))
+	} else {
+		for _, route := range position.spotRoutes() {
+			books = append(books, "spot:"+route.Exchange)
+		}
+	}
+
+	margin := captureMargin()
+	from := decidedAt.Add(-margin)
+	to := decidedAt
+	if lastFill.After(to) {
+		to = lastFill
+	}
+	to = to.Add(margin)
+
+	go func() {
+		time.Sleep(time.Until(to))
+
+		snapshots, ok := globalBooks.BookHistory(position.PairName, from, to, books...)
+		if !ok || len(snapshots) == 0 {
+			return
+		}
+
+		capture := storage.BookCapture{
+			TradeID:    position.TradeID,
+			Pair:       position.PairName,
+			Phase:      phase,
+			DecidedAt:  decidedAt,
+			LastFillAt: lastFill,
+			From:       from,
+			To:         to,
+			Frames:     make([]storage.BookFrame, 0, len(snapshots)),
+		}
+		for _, s := range snapshots {
+			capture.Frames = append(capture.Frames, storage.BookFrame{
+				At:         s.At,
+				Exchange:   s.Exchange,
+				Market:     s.Market,
+				Bids:       bookLevels(s.Bids),
+				Asks:       bookLevels(s.Asks),
+				LastUpdate: s.LastUpdate,
+				Latency:    s.Latency,
+			})
+		}
+
+		if err := storage.AppendBookCapture(capture); err != nil {
+			log.Printf("[ERROR] Failed to record %s book capture %s: %v", phase, position.PairName, err)
+		}
+	}()
+}
+
+// bookLevels converts snapshot levels to their stored form
+func bookLevels(levels []orderbook.PriceLevel) []storage.BookLevel {
+	stored := make([]storage.BookLevel, len(levels))
+	for i, level := range levels {
+		stored[i] = storage.BookLevel{Price: level.Price, Quantity: level.Quantity}
+	}
+	return stored
+}
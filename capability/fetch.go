@@ -0,0 +1,541 @@
+package capability
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/clients/cryptocom"
+	"arbitrage.trade/clients/hyperliquid"
+	"arbitrage.trade/clients/kraken"
+)
+
+type fetchFunc func(ctx context.Context, pairs []string) (map[string]PairCapability, error)
+
+var fetchers = map[string]fetchFunc{
+	"binance":     fetchBinance,
+	"okx":         fetchOkx,
+	"bitget":      fetchBitget,
+	"gate":        fetchGate,
+	"whitebit":    fetchWhitebit,
+	"kraken":      fetchKraken,
+	"cryptocom":   fetchCryptocom,
+	"hyperliquid": fetchHyperliquid,
+}
+
+var httpClient = &http.Client{Timeout: 15 * time.Second}
+
+func getJSON(ctx context.Context, url string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+	return doJSON(req, out)
+}
+
+// postJSON is getJSON for venues whose public queries are JSON POSTs
+func postJSON(ctx context.Context, url string, payload, out interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return doJSON(req, out)
+}
+
+func doJSON(req *http.Request, out interface{}) error {
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s %s: status %d: %s", req.Method, req.URL, resp.StatusCode, string(body))
+	}
+
+	return json.Unmarshal(body, out)
+}
+
+func parseFloat(s string) float64 {
+	f, _ := strconv.ParseFloat(s, 64)
+	return f
+}
+
+// precisionStep converts a number of decimal places to the quantity step it allows, 0 when unknown
+func precisionStep(places string) float64 {
+	if places == "" {
+		return 0
+	}
+	return math.Pow(10, -parseFloat(places))
+}
+
+// symbolIndex maps a venue symbol back to the tracked pair name
+// e.g. with no separator and suffix, "BTCUSDT" maps to "btc-usdt"
+func symbolIndex(pairs []string, sep, suffix string) map[string]string {
+	index := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		parts := strings.Split(strings.ToUpper(pair), "-")
+		index[strings.Join(parts, sep)+suffix] = pair
+	}
+	return index
+}
+
+func fetchBinance(ctx context.Context, pairs []string) (map[string]PairCapability, error) {
+	type symbolInfo struct {
+		Symbol       string `json:"symbol"`
+		Status       string `json:"status"`
+		ContractType string `json:"contractType"`
+		Filters      []struct {
+			FilterType  string `json:"filterType"`
+			MinQty      string `json:"minQty"`
+			StepSize    string `json:"stepSize"`
+			MinNotional string `json:"minNotional"`
+			Notional    string `json:"notional"`
+		} `json:"filters"`
+	}
+	var spot, futures struct {
+		Symbols []symbolInfo `json:"symbols"`
+	}
+
+	if err := getJSON(ctx, "https://api.binance.com/api/v3/exchangeInfo", &spot); err != nil {
+		return nil, err
+	}
+	if err := getJSON(ctx, "https://fapi.binance.com/fapi/v1/exchangeInfo", &futures); err != nil {
+		return nil, err
+	}
+
+	toMarket := func(s symbolInfo) Market {
+		m := Market{Tradable: s.Status == "TRADING"}
+		for _, f := range s.Filters {
+			switch f.FilterType {
+			case "LOT_SIZE":
+				m.MinQty = parseFloat(f.MinQty)
+				m.StepSize = parseFloat(f.StepSize)
+			case "NOTIONAL", "MIN_NOTIONAL":
+				if f.MinNotional != "" {
+					m.MinNotional = parseFloat(f.MinNotional)
+				} else {
+					m.MinNotional = parseFloat(f.Notional)
+				}
+			}
+		}
+		return m
+	}
+
+	index := symbolIndex(pairs, "", "")
+	caps := make(map[string]PairCapability)
+	for _, s := range spot.Symbols {
+		if pair, ok := index[s.Symbol]; ok {
+			c := caps[pair]
+			c.Spot = toMarket(s)
+			caps[pair] = c
+		}
+	}
+	// Perps listed under another name (1000PEPEUSDT) are indexed by it, sizes converted to base units
+	futuresIndex := make(map[string]string, len(pairs))
+	for symbol, pair := range index {
+		futuresIndex[symbol] = pair
+	}
+	for _, pair := range pairs {
+		if override, ok := common.FuturesSymbolOverride("binance", pair); ok {
+			delete(futuresIndex, strings.ToUpper(strings.ReplaceAll(pair, "-", "")))
+			futuresIndex[override.Symbol] = pair
+		}
+	}
+	for _, s := range futures.Symbols {
+		if pair, ok := futuresIndex[s.Symbol]; ok && s.ContractType == "PERPETUAL" {
+			m := toMarket(s)
+			multiplier := common.FuturesMultiplier("binance", pair)
+			m.MinQty *= multiplier
+			m.StepSize *= multiplier
+			c := caps[pair]
+			c.Perp = m
+			caps[pair] = c
+		}
+	}
+
+	return caps, nil
+}
+
+func fetchOkx(ctx context.Context, pairs []string) (map[string]PairCapability, error) {
+	type instruments struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data []struct {
+			InstID string `json:"instId"`
+			State  string `json:"state"`
+			MinSz  string `json:"minSz"`
+			LotSz  string `json:"lotSz"`
+			CtVal  string `json:"ctVal"`
+		} `json:"data"`
+	}
+	var spot, swap instruments
+
+	if err := getJSON(ctx, "https://www.okx.com/api/v5/public/instruments?instType=SPOT", &spot); err != nil {
+		return nil, err
+	}
+	if err := getJSON(ctx, "https://www.okx.com/api/v5/public/instruments?instType=SWAP", &swap); err != nil {
+		return nil, err
+	}
+	if spot.Code != "0" || swap.Code != "0" {
+		return nil, fmt.Errorf("okx error: %s %s", spot.Msg, swap.Msg)
+	}
+
+	spotIndex := symbolIndex(pairs, "-", "")
+	swapIndex := symbolIndex(pairs, "-", "-SWAP")
+	caps := make(map[string]PairCapability)
+	for _, inst := range spot.Data {
+		if pair, ok := spotIndex[inst.InstID]; ok {
+			c := caps[pair]
+			c.Spot = Market{Tradable: inst.State == "live", MinQty: parseFloat(inst.MinSz), StepSize: parseFloat(inst.LotSz)}
+			caps[pair] = c
+		}
+	}
+	for _, inst := range swap.Data {
+		if pair, ok := swapIndex[inst.InstID]; ok {
+			// Swap sizes are in contracts of ctVal base units
+			c := caps[pair]
+			ctVal := parseFloat(inst.CtVal)
+			c.Perp = Market{
+				Tradable: inst.State == "live",
+				MinQty:   parseFloat(inst.MinSz) * ctVal,
+				StepSize: parseFloat(inst.LotSz) * ctVal,
+			}
+			caps[pair] = c
+		}
+	}
+
+	return caps, nil
+}
+
+func fetchBitget(ctx context.Context, pairs []string) (map[string]PairCapability, error) {
+	var spot struct {
+		Data []struct {
+			Symbol            string `json:"symbol"`
+			Status            string `json:"status"`
+			MinTradeAmount    string `json:"minTradeAmount"`
+			MinTradeUSDT      string `json:"minTradeUSDT"`
+			QuantityPrecision string `json:"quantityPrecision"`
+		} `json:"data"`
+	}
+	var contracts struct {
+		Data []struct {
+			Symbol         string `json:"symbol"`
+			SymbolStatus   string `json:"symbolStatus"`
+			MinTradeNum    string `json:"minTradeNum"`
+			MinTradeUSDT   string `json:"minTradeUSDT"`
+			SizeMultiplier string `json:"sizeMultiplier"`
+		} `json:"data"`
+	}
+
+	if err := getJSON(ctx, "https://api.bitget.com/api/v2/spot/public/symbols", &spot); err != nil {
+		return nil, err
+	}
+	if err := getJSON(ctx, "https://api.bitget.com/api/v2/mix/market/contracts?productType=USDT-FUTURES", &contracts); err != nil {
+		return nil, err
+	}
+
+	index := symbolIndex(pairs, "", "")
+	caps := make(map[string]PairCapability)
+	for _, s := range spot.Data {
+		if pair, ok := index[s.Symbol]; ok {
+			c := caps[pair]
+			c.Spot = Market{
+				Tradable:    s.Status == "online",
+				MinQty:      parseFloat(s.MinTradeAmount),
+				MinNotional: parseFloat(s.MinTradeUSDT),
+				StepSize:    precisionStep(s.QuantityPrecision),
+			}
+			caps[pair] = c
+		}
+	}
+	for _, s := range contracts.Data {
+		if pair, ok := index[s.Symbol]; ok {
+			c := caps[pair]
+			c.Perp = Market{
+				Tradable:    s.SymbolStatus == "normal",
+				MinQty:      parseFloat(s.MinTradeNum),
+				MinNotional: parseFloat(s.MinTradeUSDT),
+				StepSize:    parseFloat(s.SizeMultiplier),
+			}
+			caps[pair] = c
+		}
+	}
+
+	return caps, nil
+}
+
+func fetchGate(ctx context.Context, pairs []string) (map[string]PairCapability, error) {
+	var spot []struct {
+		ID              string      `json:"id"`
+		TradeStatus     string      `json:"trade_status"`
+		MinBaseAmount   string      `json:"min_base_amount"`
+		MinQuoteAmount  string      `json:"min_quote_amount"`
+		AmountPrecision json.Number `json:"amount_precision"`
+	}
+	var contracts []struct {
+		Name             string `json:"name"`
+		InDelisting      bool   `json:"in_delisting"`
+		QuantoMultiplier string `json:"quanto_multiplier"`
+		OrderSizeMin     int64  `json:"order_size_min"`
+	}
+
+	if err := getJSON(ctx, "https://api.gateio.ws/api/v4/spot/currency_pairs", &spot); err != nil {
+		return nil, err
+	}
+	if err := getJSON(ctx, "https://api.gateio.ws/api/v4/futures/usdt/contracts", &contracts); err != nil {
+		return nil, err
+	}
+
+	index := symbolIndex(pairs, "_", "")
+	caps := make(map[string]PairCapability)
+	for _, s := range spot {
+		if pair, ok := index[s.ID]; ok {
+			c := caps[pair]
+			c.Spot = Market{
+				Tradable:    s.TradeStatus == "tradable",
+				MinQty:      parseFloat(s.MinBaseAmount),
+				MinNotional: parseFloat(s.MinQuoteAmount),
+				StepSize:    precisionStep(s.AmountPrecision.String()),
+			}
+			caps[pair] = c
+		}
+	}
+	for _, s := range contracts {
+		if pair, ok := index[s.Name]; ok {
+			// Contract sizes are in contracts of quanto_multiplier base units
+			c := caps[pair]
+			multiplier := parseFloat(s.QuantoMultiplier)
+			c.Perp = Market{
+				Tradable: !s.InDelisting,
+				MinQty:   float64(s.OrderSizeMin) * multiplier,
+				StepSize: multiplier,
+			}
+			caps[pair] = c
+		}
+	}
+
+	return caps, nil
+}
+
+func fetchWhitebit(ctx context.Context, pairs []string) (map[string]PairCapability, error) {
+	var markets []struct {
+		Name          string `json:"name"`
+		Type          string `json:"type"`
+		TradesEnabled bool   `json:"tradesEnabled"`
+		MinAmount     string `json:"minAmount"`
+		MinTotal      string `json:"minTotal"`
+		StockPrec     string `json:"stockPrec"`
+	}
+
+	if err := getJSON(ctx, "https://whitebit.com/api/v4/public/markets", &markets); err != nil {
+		return nil, err
+	}
+
+	// Perpetuals are listed as BTC_PERP
+	perpPairs := make([]string, len(pairs))
+	for i, pair := range pairs {
+		perpPairs[i] = strings.Replace(pair, "-usdt", "-perp", 1)
+	}
+	spotIndex := symbolIndex(pairs, "_", "")
+	perpIndex := symbolIndex(perpPairs, "_", "")
+
+	caps := make(map[string]PairCapability)
+	for _, m := range markets {
+		market := Market{
+			Tradable:    m.TradesEnabled,
+			MinQty:      parseFloat(m.MinAmount),
+			MinNotional: parseFloat(m.MinTotal),
+			StepSize:    precisionStep(m.StockPrec),
+		}
+
+		if perpPair, ok := perpIndex[m.Name]; ok && m.Type == "futures" {
+			pair := strings.Replace(perpPair, "-perp", "-usdt", 1)
+			c := caps[pair]
+			c.Perp = market
+			caps[pair] = c
+		} else if pair, ok := spotIndex[m.Name]; ok && m.Type == "spot" {
+			c := caps[pair]
+			c.Spot = market
+			caps[pair] = c
+		}
+	}
+
+	return caps, nil
+}
+
+func fetchKraken(ctx context.Context, pairs []string) (map[string]PairCapability, error) {
+	var spot struct {
+		Error  []string `json:"error"`
+		Result map[string]struct {
+			Altname     string `json:"altname"`
+			Status      string `json:"status"`
+			OrderMin    string `json:"ordermin"`
+			CostMin     string `json:"costmin"`
+			LotDecimals int    `json:"lot_decimals"`
+		} `json:"result"`
+	}
+	var futures struct {
+		Instruments []struct {
+			Symbol                      string      `json:"symbol"`
+			Tradeable                   bool        `json:"tradeable"`
+			ContractValueTradePrecision json.Number `json:"contractValueTradePrecision"`
+		} `json:"instruments"`
+	}
+
+	if err := getJSON(ctx, "https://api.kraken.com/0/public/AssetPairs", &spot); err != nil {
+		return nil, err
+	}
+	if len(spot.Error) > 0 {
+		return nil, fmt.Errorf("kraken AssetPairs: %s", strings.Join(spot.Error, ", "))
+	}
+	if err := getJSON(ctx, "https://futures.kraken.com/derivatives/api/v3/instruments", &futures); err != nil {
+		return nil, err
+	}
+
+	// Kraken names BTC as XBT in both markets, so the index is built from its own symbols
+	spotIndex := make(map[string]string, len(pairs))
+	futuresIndex := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		spotIndex[kraken.SpotSymbol(pair)] = pair
+		futuresIndex[kraken.FuturesSymbol(pair)] = pair
+	}
+
+	caps := make(map[string]PairCapability)
+	for _, s := range spot.Result {
+		if pair, ok := spotIndex[s.Altname]; ok {
+			c := caps[pair]
+			c.Spot = Market{
+				Tradable:    s.Status == "online",
+				MinQty:      parseFloat(s.OrderMin),
+				MinNotional: parseFloat(s.CostMin),
+				StepSize:    precisionStep(strconv.Itoa(s.LotDecimals)),
+			}
+			caps[pair] = c
+		}
+	}
+	for _, s := range futures.Instruments {
+		if pair, ok := futuresIndex[s.Symbol]; ok {
+			// Perpetuals are sized in base units to contractValueTradePrecision decimals
+			c := caps[pair]
+			step := precisionStep(s.ContractValueTradePrecision.String())
+			c.Perp = Market{
+				Tradable: s.Tradeable,
+				MinQty:   step,
+				StepSize: step,
+			}
+			caps[pair] = c
+		}
+	}
+
+	return caps, nil
+}
+
+func fetchCryptocom(ctx context.Context, pairs []string) (map[string]PairCapability, error) {
+	var instruments struct {
+		Code   int `json:"code"`
+		Result struct {
+			Data []struct {
+				Symbol      string `json:"symbol"`
+				InstType    string `json:"inst_type"`
+				Tradable    bool   `json:"tradable"`
+				QtyTickSize string `json:"qty_tick_size"`
+			} `json:"data"`
+		} `json:"result"`
+	}
+
+	if err := getJSON(ctx, "https://api.crypto.com/exchange/v1/public/get-instruments", &instruments); err != nil {
+		return nil, err
+	}
+	if instruments.Code != 0 {
+		return nil, fmt.Errorf("cryptocom get-instruments: code %d", instruments.Code)
+	}
+
+	// Perpetuals are USD-settled, e.g. BTCUSD-PERP for btc-usdt
+	spotIndex := make(map[string]string, len(pairs))
+	perpIndex := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		spotIndex[cryptocom.SpotSymbol(pair)] = pair
+		perpIndex[cryptocom.PerpSymbol(pair)] = pair
+	}
+
+	caps := make(map[string]PairCapability)
+	for _, s := range instruments.Result.Data {
+		// Orders are accepted down to one quantity tick
+		market := Market{
+			Tradable: s.Tradable,
+			MinQty:   parseFloat(s.QtyTickSize),
+			StepSize: parseFloat(s.QtyTickSize),
+		}
+
+		if pair, ok := spotIndex[s.Symbol]; ok && s.InstType == "CCY_PAIR" {
+			c := caps[pair]
+			c.Spot = market
+			caps[pair] = c
+		} else if pair, ok := perpIndex[s.Symbol]; ok && s.InstType == "PERPETUAL_SWAP" {
+			c := caps[pair]
+			c.Perp = market
+			caps[pair] = c
+		}
+	}
+
+	return caps, nil
+}
+
+func fetchHyperliquid(ctx context.Context, pairs []string) (map[string]PairCapability, error) {
+	var meta struct {
+		Universe []struct {
+			Name       string `json:"name"`
+			SzDecimals int    `json:"szDecimals"`
+			IsDelisted bool   `json:"isDelisted"`
+		} `json:"universe"`
+	}
+
+	if err := postJSON(ctx, "https://api.hyperliquid.xyz/info", map[string]string{"type": "meta"}, &meta); err != nil {
+		return nil, err
+	}
+
+	// Perps only; coins listed per 1000 units (kPEPE) are scaled back to base units
+	index := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		coin, _ := hyperliquid.Coin(pair)
+		index[strings.ToUpper(coin)] = pair
+	}
+
+	caps := make(map[string]PairCapability)
+	for _, s := range meta.Universe {
+		if pair, ok := index[strings.ToUpper(s.Name)]; ok {
+			_, multiplier := hyperliquid.Coin(pair)
+			step := precisionStep(strconv.Itoa(s.SzDecimals)) * multiplier
+			c := caps[pair]
+			c.Perp = Market{
+				Tradable:    !s.IsDelisted,
+				MinQty:      step,
+				MinNotional: 10, // Hyperliquid rejects orders under $10
+				StepSize:    step,
+			}
+			caps[pair] = c
+		}
+	}
+
+	return caps, nil
+}
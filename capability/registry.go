@@ -0,0 +1,165 @@
+package capability
+
+import (
+	"context"
+	"log"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/config"
+	"arbitrage.trade/scheduler"
+)
+
+// DefaultRefreshInterval is how often exchange info is reloaded
+// Override with CAPABILITY_REFRESH_INTERVAL (e.g. 30m)
+const DefaultRefreshInterval = time.Hour
+
+// Market describes whether a pair trades on one market of a venue and its minimum order size
+type Market struct {
+	Tradable    bool
+	MinQty      float64 // base asset
+	MinNotional float64 // USDT
+	StepSize    float64 // base asset, 0 when the venue doesn't publish one
+	Invalid     string  // Why the pair's configured quantity precision can't trade here, "" when it can
+}
+
+// PairCapability is the spot and perp status of a pair on a venue
+type PairCapability struct {
+	Spot Market
+	Perp Market
+}
+
+// Registry holds venue -> pair -> capability, refreshed from exchange info
+type Registry struct {
+	mu     sync.RWMutex
+	venues map[string]map[string]PairCapability
+	loaded map[string]time.Time
+}
+
+var registry = &Registry{
+	venues: make(map[string]map[string]PairCapability),
+	loaded: make(map[string]time.Time),
+}
+
+// Get returns the capability of a pair on a venue
+// ok is false when the venue's exchange info has never been loaded
+func Get(exchange, pairName string) (PairCapability, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	if _, loaded := registry.loaded[exchange]; !loaded {
+		return PairCapability{}, false
+	}
+	return registry.venues[exchange][pairName], true
+}
+
+// CanTrade reports whether a pair is tradable on a venue market
+// Venues whose exchange info could not be loaded are treated as not tradable
+func CanTrade(exchange, pairName string, isSpot bool) bool {
+	capability, ok := Get(exchange, pairName)
+	if !ok {
+		return false
+	}
+	if isSpot {
+		return capability.Spot.Tradable
+	}
+	return capability.Perp.Tradable
+}
+
+// CanFill reports whether an order of amountUSDT at price meets the venue market minimums
+func CanFill(exchange, pairName string, isSpot bool, price, amountUSDT float64) bool {
+	capability, ok := Get(exchange, pairName)
+	if !ok {
+		return false
+	}
+
+	market := capability.Perp
+	if isSpot {
+		market = capability.Spot
+	}
+	if !market.Tradable {
+		return false
+	}
+
+	if common.LessThan(amountUSDT, market.MinNotional) {
+		return false
+	}
+	if common.IsPositive(price) && common.LessThan(amountUSDT/price, market.MinQty) {
+		return false
+	}
+	return true
+}
+
+// MinOrderVolume returns the smallest USDT order a venue market accepts at price: the largest of the
+// pair's quantity precision, the venue's step size and minimum quantity at that price, and its minimum notional
+// Venues whose exchange info isn't loaded are held to the quantity precision alone
+func MinOrderVolume(exchange, pairName string, isSpot bool, price float64) float64 {
+	volume := common.CalculateMinAchievableVolume(price, pairName)
+
+	capability, ok := Get(exchange, pairName)
+	if !ok {
+		return volume
+	}
+	market := capability.Perp
+	if isSpot {
+		market = capability.Spot
+	}
+
+	volume = math.Max(volume, market.StepSize*price)
+	volume = math.Max(volume, market.MinQty*price)
+	return math.Max(volume, market.MinNotional)
+}
+
+// Refresh reloads exchange info for the given venues, keeping only the tracked pairs
+// A venue that fails keeps its previous data
+func Refresh(ctx context.Context, exchanges []string, pairs []string) {
+	for _, exchange := range exchanges {
+		fetch, ok := fetchers[exchange]
+		if !ok {
+			log.Printf("[CAPABILITY] No exchange info fetcher for %s", exchange)
+			continue
+		}
+
+		caps, err := fetch(ctx, pairs)
+		if err != nil {
+			log.Printf("[CAPABILITY] %s - ERROR: Failed to load exchange info: %v", exchange, err)
+			continue
+		}
+		validateQuantities(exchange, caps)
+
+		registry.mu.Lock()
+		registry.venues[exchange] = caps
+		registry.loaded[exchange] = time.Now()
+		registry.mu.Unlock()
+
+		for _, pair := range pairs {
+			c := caps[pair]
+			if !c.Spot.Tradable || !c.Perp.Tradable {
+				log.Printf("[CAPABILITY] %s %s - spot: %v, perp: %v", exchange, pair, c.Spot.Tradable, c.Perp.Tradable)
+			}
+		}
+	}
+}
+
+// Start loads exchange info once and then refreshes it in the background
+// With PRECISION_STRICT=true a configured pair whose quantity precision breaks a venue's rules aborts startup
+func Start(exchanges []string, pairs []string) {
+	Refresh(context.Background(), exchanges, pairs)
+
+	if invalid := InvalidMarkets(); len(invalid) > 0 && config.GetBool("PRECISION_STRICT", false) {
+		log.Fatalf("[CAPABILITY] Quantity precision violates exchange rules: %s", strings.Join(invalid, "; "))
+	}
+
+	interval := config.GetDuration("CAPABILITY_REFRESH_INTERVAL", DefaultRefreshInterval)
+	scheduler.Register(scheduler.Job{
+		Name:     "capability",
+		Schedule: "@every " + interval.String(),
+		Run: func(ctx context.Context, _ time.Time) error {
+			Refresh(ctx, exchanges, pairs)
+			return nil
+		},
+	})
+}
@@ -0,0 +1,88 @@
+package capability
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"sort"
+
+	"arbitrage.trade/clients/common"
+)
+
+// Quantity precision validation
+//
+// Every time exchange info is loaded, the pair's configured quantity precision (common.PairPrecisions)
+// is checked against each venue market: the precision's step must be a multiple of the venue's step
+// size, and a FormatQuantity/RoundQuantity result above the venue minimum must still satisfy both.
+// A market that fails is marked not tradable with the reason in Market.Invalid. With PRECISION_STRICT=true
+// any failure on the first load aborts startup instead.
+
+// validateQuantities marks markets whose quantity rules the pair's precision can't satisfy as not tradable
+func validateQuantities(exchange string, caps map[string]PairCapability) {
+	for pairName, c := range caps {
+		if reason := checkQuantity(pairName, c.Spot); reason != "" {
+			c.Spot.Tradable = false
+			c.Spot.Invalid = reason
+			log.Printf("[CAPABILITY] %s %s spot - Marked unsupported: %s", exchange, pairName, reason)
+		}
+		if reason := checkQuantity(pairName, c.Perp); reason != "" {
+			c.Perp.Tradable = false
+			c.Perp.Invalid = reason
+			log.Printf("[CAPABILITY] %s %s perp - Marked unsupported: %s", exchange, pairName, reason)
+		}
+		caps[pairName] = c
+	}
+}
+
+// checkQuantity returns why the pair's quantity precision breaks the market's rules, or "" when it doesn't
+func checkQuantity(pairName string, market Market) string {
+	if !market.Tradable {
+		return ""
+	}
+
+	prec := common.GetPrecision(pairName)
+	ours := common.NewDecimal(math.Pow10(-prec.QuantityPrecision))
+	step := common.NewDecimal(market.StepSize)
+
+	if !step.IsZero() && !ours.Rem(step).IsZero() {
+		return fmt.Sprintf("quantity precision %d (step %s) is finer than the venue step %s", prec.QuantityPrecision, ours, step)
+	}
+
+	// Round an awkward quantity just above the venue minimum the way order placement does
+	sample := math.Max(market.MinQty, ours.Float64()) * 3.3333333
+	rounded := common.RoundQuantity(sample, pairName)
+	formatted, err := common.ParseDecimal(common.FormatQuantity(sample, pairName))
+	if err != nil {
+		return fmt.Sprintf("FormatQuantity output unparsable: %v", err)
+	}
+	if formatted != common.NewDecimal(rounded) {
+		return fmt.Sprintf("FormatQuantity %s disagrees with RoundQuantity %s", formatted, common.NewDecimal(rounded))
+	}
+	if !step.IsZero() && !formatted.Rem(step).IsZero() {
+		return fmt.Sprintf("quantity %s is not a multiple of the venue step %s", formatted, step)
+	}
+	if formatted.Cmp(common.NewDecimal(market.MinQty)) < 0 {
+		return fmt.Sprintf("quantity %s is below the venue minimum %s", formatted, common.NewDecimal(market.MinQty))
+	}
+	return ""
+}
+
+// InvalidMarkets lists the venue markets marked unsupported by quantity validation
+func InvalidMarkets() []string {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	var invalid []string
+	for exchange, pairs := range registry.venues {
+		for pairName, c := range pairs {
+			if c.Spot.Invalid != "" {
+				invalid = append(invalid, fmt.Sprintf("%s %s spot: %s", exchange, pairName, c.Spot.Invalid))
+			}
+			if c.Perp.Invalid != "" {
+				invalid = append(invalid, fmt.Sprintf("%s %s perp: %s", exchange, pairName, c.Perp.Invalid))
+			}
+		}
+	}
+	sort.Strings(invalid)
+	return invalid
+}
@@ -0,0 +1,130 @@
+package chaos
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"time"
+
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/config"
+)
+
+// Chaos mode
+//
+// For staging, CHAOS_ENABLED=true injects faults so leg rollback, the reject back-off and stale-book
+// handling can be validated before a change trades production capital:
+//
+//	CHAOS_LATENCY        delay before every order sent to a venue, fixed ("300ms") or a uniform range ("50ms-800ms")
+//	CHAOS_REJECT_RATE    share of orders failed as a venue reject (0-1, default 0)
+//	CHAOS_FEED_GAP_RATE  chance per book update that the venue's feed goes silent (0-1, default 0)
+//	CHAOS_FEED_GAP       how long such a gap lasts (default 5s)
+//	CHAOS_EXCHANGES      venues faults are injected into, comma separated (default all)
+//
+// Paper trading accepts chaos as is; with live keys it is refused unless CHAOS_ALLOW_LIVE=true.
+
+var (
+	gapsMu   sync.Mutex
+	gaps     = make(map[string]time.Time) // exchange -> end of its feed gap
+	warnOnce sync.Once
+)
+
+// Enabled reports whether faults are injected
+func Enabled() bool {
+	if !config.GetBool("CHAOS_ENABLED", false) {
+		return false
+	}
+	if !config.GetBool("PAPER_TRADING", false) && !config.GetBool("CHAOS_ALLOW_LIVE", false) {
+		warnOnce.Do(func() {
+			log.Printf("[CHAOS] CHAOS_ENABLED ignored - live trading needs CHAOS_ALLOW_LIVE=true as well")
+		})
+		return false
+	}
+	return true
+}
+
+// targets reports whether faults are injected into the exchange
+func targets(exchange string) bool {
+	if !Enabled() {
+		return false
+	}
+	list := config.GetString("CHAOS_EXCHANGES", "")
+	if list == "" {
+		return true
+	}
+	for _, e := range strings.Split(list, ",") {
+		if strings.EqualFold(strings.TrimSpace(e), exchange) {
+			return true
+		}
+	}
+	return false
+}
+
+// latency returns the delay to inject, parsed from CHAOS_LATENCY
+func latency() time.Duration {
+	v := config.GetString("CHAOS_LATENCY", "")
+	if v == "" {
+		return 0
+	}
+	lo, hi, isRange := strings.Cut(v, "-")
+	from, err := time.ParseDuration(strings.TrimSpace(lo))
+	if err != nil {
+		return 0
+	}
+	if !isRange {
+		return from
+	}
+	to, err := time.ParseDuration(strings.TrimSpace(hi))
+	if err != nil || to <= from {
+		return from
+	}
+	return from + rand.N(to-from)
+}
+
+// BeforeOrder delays an order to the exchange and may fail it as a reject, when chaos mode targets the venue
+func BeforeOrder(ctx context.Context, exchange string) error {
+	if !targets(exchange) {
+		return nil
+	}
+
+	if delay := latency(); delay > 0 {
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+
+	if rate := config.GetFloat("CHAOS_REJECT_RATE", 0); rate > 0 && rand.Float64() < rate {
+		log.Printf("[CHAOS] %s - Injected order reject", exchange)
+		return fmt.Errorf("chaos: injected reject on %s: %w", exchange, common.ErrOrderFailed)
+	}
+	return nil
+}
+
+// FeedGap reports whether an update to the exchange's book should be dropped, starting gaps at CHAOS_FEED_GAP_RATE
+func FeedGap(exchange string) bool {
+	if !targets(exchange) {
+		return false
+	}
+
+	now := time.Now()
+	gapsMu.Lock()
+	defer gapsMu.Unlock()
+
+	if now.Before(gaps[exchange]) {
+		return true
+	}
+	if rate := config.GetFloat("CHAOS_FEED_GAP_RATE", 0); rate > 0 && rand.Float64() < rate {
+		gap := config.GetDuration("CHAOS_FEED_GAP", 5*time.Second)
+		gaps[exchange] = now.Add(gap)
+		log.Printf("[CHAOS] %s - Injected %s feed gap", exchange, gap)
+		return true
+	}
+	return false
+}
@@ -0,0 +1,342 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"arbitrage.trade/backtest"
+	"arbitrage.trade/clients"
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/clients/conformance"
+	"arbitrage.trade/config"
+	"arbitrage.trade/report"
+)
+
+// runCommand runs a one-shot CLI command instead of the trading loop, reporting whether args named one
+func runCommand(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+
+	switch args[0] {
+	case "leaderboard":
+		fs := flag.NewFlagSet("leaderboard", flag.ExitOnError)
+		days := fs.Int("days", 0, "only include trades closed in the last N days (0 = all time)")
+		fs.Parse(args[1:])
+
+		board, err := report.LoadLeaderboard(*days)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Print(report.FormatLeaderboard(board))
+		return true
+	case "experiment":
+		fs := flag.NewFlagSet("experiment", flag.ExitOnError)
+		name := fs.String("name", config.GetString("EXPERIMENT_NAME", ""), "experiment to compare (default: EXPERIMENT_NAME)")
+		days := fs.Int("days", 0, "only include trades closed in the last N days (0 = all time)")
+		fs.Parse(args[1:])
+
+		if *name == "" {
+			fmt.Fprintln(os.Stderr, "❌ -name is required when EXPERIMENT_NAME is not set")
+			os.Exit(1)
+		}
+		comparison, err := report.LoadExperiment(*name, *days)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Print(report.FormatExperiment(comparison))
+		return true
+	case "fixtures":
+		fs := flag.NewFlagSet("fixtures", flag.ExitOnError)
+		exchange := fs.String("exchange", "", "exchange whose fixtures to replay (default: every exchange with fixtures)")
+		pair := fs.String("pair", "btc-usdt", "pair the replayed calls are made for")
+		fs.Parse(args[1:])
+		os.Exit(replayFixtures(*exchange, *pair))
+		return true
+	case "conformance":
+		fs := flag.NewFlagSet("conformance", flag.ExitOnError)
+		exchange := fs.String("exchange", "", "exchange adapter to check (default: every exchange with fixtures)")
+		pair := fs.String("pair", "btc-usdt", "pair the checks trade")
+		amount := fs.Float64("amount", 20, "USDT notional of the open/close cycle")
+		orders := fs.Bool("orders", false, "run the open/close cycle (places real orders when recording)")
+		fs.Parse(args[1:])
+		os.Exit(runConformance(*exchange, *pair, *amount, *orders))
+		return true
+	case "import-fills":
+		fs := flag.NewFlagSet("import-fills", flag.ExitOnError)
+		from := fs.String("from", "", "start of the imported range, RFC3339 or YYYY-MM-DD (required)")
+		to := fs.String("to", "", "end of the imported range, RFC3339 or YYYY-MM-DD (default: now)")
+		exchange := fs.String("exchange", "", "exchange to import (default: every exchange supporting trade history)")
+		pairs := fs.String("pairs", "", "comma separated pairs to import (default: every known pair)")
+		fs.Parse(args[1:])
+		os.Exit(importFills(*from, *to, *exchange, *pairs))
+		return true
+	case "rebalance-plan":
+		fs := flag.NewFlagSet("rebalance-plan", flag.ExitOnError)
+		exchanges := fs.String("exchanges", "", "comma separated venues (default: every venue with credentials)")
+		fs.Parse(args[1:])
+		os.Exit(planRebalance(*exchanges))
+		return true
+	case "tax-export":
+		fs := flag.NewFlagSet("tax-export", flag.ExitOnError)
+		year := fs.Int("year", time.Now().Year()-1, "tax year of the reported sales (UTC)")
+		out := fs.String("out", "", "CSV file to write (default: stdout)")
+		fs.Parse(args[1:])
+
+		from := time.Date(*year, 1, 1, 0, 0, 0, 0, time.UTC)
+		disposals, err := report.LoadTaxReport(from, from.AddDate(1, 0, 0))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		w := os.Stdout
+		if *out != "" {
+			if w, err = os.Create(*out); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+				os.Exit(1)
+			}
+			defer w.Close()
+		}
+		if err := report.WriteTaxCSV(w, disposals); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		return true
+	case "montecarlo":
+		fs := flag.NewFlagSet("montecarlo", flag.ExitOnError)
+		weeks := fs.Int("weeks", 12, "simulated weeks per run")
+		runs := fs.Int("runs", 10000, "simulated runs")
+		days := fs.Int("days", 0, "only sample trades closed in the last N days (0 = all time)")
+		amount := fs.Float64("amount", config.GetFloat("TARGET_NOTIONAL_USDT", 20), "USDT notional per trade (default TARGET_NOTIONAL_USDT)")
+		seed := fs.Int64("seed", time.Now().UnixNano(), "random seed, fixed for reproducible runs")
+		fs.Parse(args[1:])
+
+		result, err := report.LoadMonteCarlo(*days, report.MonteCarloConfig{Weeks: *weeks, Runs: *runs, Notional: *amount, Seed: *seed})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Print(report.FormatMonteCarlo(result))
+		return true
+	case "sweep":
+		fs := flag.NewFlagSet("sweep", flag.ExitOnError)
+		file := fs.String("file", config.GetString("FEED_RECORD_FILE", "feeds.jsonl"), "feed recording to replay (FEED_RECORD_FILE)")
+		pairs := fs.String("pairs", "", "comma separated pairs to sweep (default: every recorded pair)")
+		thresholds := fs.String("thresholds", "0.3,0.5,1,1.5", "entry spreads in %")
+		convergences := fs.String("convergence", "40,60,80", "convergence targets in %")
+		maxHolds := fs.String("max-hold", "30s,58s,2m", "maximum hold times")
+		feePcts := fs.String("fees", "0.05,0.1", "taker fees per leg in %")
+		amount := fs.Float64("amount", 20, "USDT notional per trade")
+		folds := fs.Int("folds", 3, "walk-forward validation splits")
+		top := fs.Int("top", 5, "configurations shown per pair")
+		fs.Parse(args[1:])
+		os.Exit(runSweep(*file, *pairs, *thresholds, *convergences, *maxHolds, *feePcts, *amount, *folds, *top))
+		return true
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command: %s\nusage: arbitrage.trade [leaderboard [-days N] | experiment [-name E] [-days N] | fixtures [-exchange X] [-pair P] | conformance [-exchange X] [-pair P] [-amount N] [-orders] | import-fills -from T [-to T] [-exchange X] [-pairs P,Q] | rebalance-plan [-exchanges X,Y] | tax-export [-year N] [-out F] | montecarlo [-weeks N] [-runs N] [-days N] [-amount N] | sweep [-file F] [-pairs P,Q] [-thresholds ...] [-convergence ...] [-max-hold ...] [-fees ...] [-folds N]]\n", args[0])
+		os.Exit(2)
+		return true
+	}
+}
+
+// replayFixtures replays the recorded HTTP fixtures of one or every exchange and returns the exit code
+func replayFixtures(exchange, pairName string) int {
+	os.Setenv("HTTP_FIXTURES_MODE", "replay")
+
+	exchanges := []common.ExchangeType{common.ExchangeType(exchange)}
+	if exchange == "" {
+		exchanges = nil
+		for _, e := range clients.Exchanges() {
+			if _, err := os.Stat(common.FixturePath(string(e))); err == nil {
+				exchanges = append(exchanges, e)
+			}
+		}
+		if len(exchanges) == 0 {
+			fmt.Fprintf(os.Stderr, "❌ no fixtures found, record some with HTTP_FIXTURES_MODE=record\n")
+			return 1
+		}
+	}
+
+	failed := 0
+	for _, e := range exchanges {
+		fmt.Printf("%s %s\n", e, pairName)
+		checks, err := clients.ReplayFixtures(context.Background(), e, pairName)
+		if err != nil {
+			fmt.Printf("  ❌ %v\n", err)
+			failed++
+		}
+		for _, check := range checks {
+			if check.Err != nil {
+				fmt.Printf("  ❌ %-22s %v\n", check.Call, check.Err)
+				failed++
+			} else {
+				fmt.Printf("  ✅ %-22s %s\n", check.Call, check.Result)
+			}
+		}
+	}
+
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+// conformanceReporter prints suite results and counts failures
+type conformanceReporter struct {
+	failed int
+}
+
+func (r *conformanceReporter) Errorf(format string, args ...interface{}) {
+	r.failed++
+	fmt.Printf("  ❌ "+format+"\n", args...)
+}
+
+func (r *conformanceReporter) Logf(format string, args ...interface{}) {
+	fmt.Printf("  ✅ "+format+"\n", args...)
+}
+
+// runConformance runs the adapter conformance suite and returns the exit code
+// Replays recorded fixtures unless HTTP_FIXTURES_MODE=record, which runs against the live venue
+func runConformance(exchange, pairName string, amountUSDT float64, orders bool) int {
+	if common.FixturesMode() != "record" {
+		os.Setenv("HTTP_FIXTURES_MODE", "replay")
+	}
+
+	exchanges := []common.ExchangeType{common.ExchangeType(exchange)}
+	if exchange == "" {
+		exchanges = nil
+		for _, e := range clients.Exchanges() {
+			if _, err := os.Stat(common.FixturePath(string(e))); err == nil {
+				exchanges = append(exchanges, e)
+			}
+		}
+		if len(exchanges) == 0 {
+			fmt.Fprintf(os.Stderr, "❌ no fixtures found, record some with HTTP_FIXTURES_MODE=record\n")
+			return 1
+		}
+	}
+
+	reporter := &conformanceReporter{}
+	for _, e := range exchanges {
+		if _, err := clients.FixtureClient(e); err != nil {
+			fmt.Printf("%s\n  ❌ %v\n", e, err)
+			reporter.failed++
+			continue
+		}
+
+		fmt.Printf("%s %s (%s)\n", e, pairName, common.FixturesMode())
+		conformance.Run(context.Background(), reporter, conformance.Suite{
+			Exchange:   string(e),
+			Pair:       pairName,
+			AmountUSDT: amountUSDT,
+			Orders:     orders,
+			New: func() common.ExchangeTradeClient {
+				client, _ := clients.FixtureClient(e)
+				return client
+			},
+		})
+	}
+
+	if reporter.failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+// planRebalance prints the USDT transfers that would bring the venues to their share and returns the exit code
+// Nothing is withdrawn
+func planRebalance(exchangesArg string) int {
+	var exchanges []string
+	for _, exchange := range strings.Split(exchangesArg, ",") {
+		if exchange = strings.TrimSpace(exchange); exchange != "" {
+			exchanges = append(exchanges, exchange)
+		}
+	}
+	if len(exchanges) == 0 {
+		for _, e := range clients.Exchanges() {
+			if len(clients.MissingCredentials(e)) == 0 {
+				exchanges = append(exchanges, string(e))
+			}
+		}
+	}
+
+	plan, err := clients.PlanRebalance(context.Background(), exchanges)
+	if plan != nil {
+		fmt.Print(clients.FormatRebalancePlan(plan))
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// runSweep grid-searches strategy parameters over a feed recording and returns the exit code
+func runSweep(file, pairsArg, thresholds, convergences, maxHolds, feePcts string, amount float64, folds, top int) int {
+	var grid backtest.Grid
+	var err error
+	if grid.Thresholds, err = parseFloatList(thresholds); err == nil {
+		if grid.Convergences, err = parseFloatList(convergences); err == nil {
+			if grid.MaxHolds, err = parseDurationList(maxHolds); err == nil {
+				grid.FeePcts, err = parseFloatList(feePcts)
+			}
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 2
+	}
+
+	quotes, err := backtest.LoadQuotes(file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+	if pairsArg != "" {
+		selected := make(map[string][]backtest.Quote)
+		for _, pair := range strings.Split(pairsArg, ",") {
+			pair = strings.TrimSpace(pair)
+			if q, ok := quotes[pair]; ok {
+				selected[pair] = q
+			}
+		}
+		quotes = selected
+	}
+
+	fmt.Printf("Sweeping %d configurations over %d pairs, %d walk-forward folds\n", len(grid.Configs()), len(quotes), folds)
+	fmt.Print(backtest.FormatSweep(backtest.Sweep(quotes, grid, amount, folds), top))
+	return 0
+}
+
+// parseFloatList parses a comma separated list of numbers
+func parseFloatList(value string) ([]float64, error) {
+	var values []float64
+	for _, field := range strings.Split(value, ",") {
+		v, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", field)
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+// parseDurationList parses a comma separated list of durations
+func parseDurationList(value string) ([]time.Duration, error) {
+	var values []time.Duration
+	for _, field := range strings.Split(value, ",") {
+		v, err := time.ParseDuration(strings.TrimSpace(field))
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration %q", field)
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
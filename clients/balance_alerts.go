@@ -0,0 +1,165 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/config"
+	"arbitrage.trade/notify"
+	"arbitrage.trade/scheduler"
+)
+
+// Balance floor alerts
+//
+// Every BALANCE_CHECK_INTERVAL (default 1m) each venue's free USDT in spot and futures is compared
+// against its floor: BALANCE_FLOOR_USDT_<EXCHANGE>_<MARKET>, else BALANCE_FLOOR_USDT_<EXCHANGE>, else
+// BALANCE_FLOOR_USDT (default 0, disabled). Dropping below the floor sends an alert through the notifier
+// so funds can be moved before orders fail with insufficient balance. With BALANCE_FLOOR_PAUSE=true
+// entries touching a venue with a low balance are also held until it is topped up again.
+
+// LowBalance is a venue market whose free USDT is below its floor
+type LowBalance struct {
+	Exchange string    `json:"exchange"`
+	Market   string    `json:"market"`
+	FreeUSDT float64   `json:"free_usdt"`
+	Floor    float64   `json:"floor_usdt"`
+	Since    time.Time `json:"since"`
+}
+
+var (
+	lowBalances   = make(map[string]*LowBalance) // exchange:market -> low balance
+	lowBalancesMu sync.RWMutex
+)
+
+// balanceFloor returns the configured floor of a venue market, 0 when disabled
+func balanceFloor(exchange, market string) float64 {
+	floor := config.GetFloat("BALANCE_FLOOR_USDT", 0)
+	floor = config.GetFloat(config.Key("BALANCE_FLOOR_USDT", exchange), floor)
+	return config.GetFloat(config.Key("BALANCE_FLOOR_USDT", exchange, market), floor)
+}
+
+// CheckBalanceFloors compares each venue's free spot and futures USDT against its floor
+func CheckBalanceFloors(ctx context.Context, exchanges []string) {
+	for _, exchange := range exchanges {
+		client, err := getOrCreateClient(common.ExchangeType(exchange))
+		if err != nil {
+			continue
+		}
+		balanceClient, ok := client.(common.BalanceClient)
+		if !ok {
+			continue
+		}
+
+		for _, market := range []string{"spot", "futures"} {
+			floor := balanceFloor(exchange, market)
+			if floor <= 0 {
+				continue
+			}
+
+			free, err := balanceClient.GetUSDTBalance(ctx, market)
+			if err != nil {
+				log.Printf("[BALANCE] %s %s - ERROR: %v", exchange, market, err)
+				continue
+			}
+			setBalanceState(exchange, market, free, floor)
+		}
+	}
+}
+
+// setBalanceState records a venue market's balance and alerts when it crosses its floor
+func setBalanceState(exchange, market string, free, floor float64) {
+	key := exchange + ":" + market
+
+	lowBalancesMu.Lock()
+	prev, wasLow := lowBalances[key]
+	isLow := free < floor
+	if isLow {
+		since := time.Now()
+		if wasLow {
+			since = prev.Since
+		}
+		lowBalances[key] = &LowBalance{Exchange: exchange, Market: market, FreeUSDT: free, Floor: floor, Since: since}
+	} else {
+		delete(lowBalances, key)
+	}
+	lowBalancesMu.Unlock()
+
+	switch {
+	case isLow && !wasLow:
+		action := "entries continue"
+		if config.GetBool("BALANCE_FLOOR_PAUSE", false) {
+			action = "new entries on " + exchange + " paused until topped up"
+		}
+		log.Printf("[BALANCE] %s %s free USDT %.2f below floor %.2f - %s", exchange, market, free, floor, action)
+		notify.Send(notify.Message{
+			Event: notify.EventAlert,
+			Title: fmt.Sprintf("%s %s USDT balance low", exchange, market),
+			Body:  fmt.Sprintf("Free: %.2f USDT | Floor: %.2f USDT\nTop up %s %s before orders fail (%s).", free, floor, exchange, market, action),
+		})
+	case !isLow && wasLow:
+		log.Printf("[BALANCE] %s %s free USDT %.2f back above floor %.2f", exchange, market, free, floor)
+	}
+}
+
+// LowBalances returns every venue market currently below its floor
+func LowBalances() []LowBalance {
+	lowBalancesMu.RLock()
+	defer lowBalancesMu.RUnlock()
+
+	low := make([]LowBalance, 0, len(lowBalances))
+	for _, b := range lowBalances {
+		low = append(low, *b)
+	}
+	sort.Slice(low, func(i, j int) bool {
+		if low[i].Exchange != low[j].Exchange {
+			return low[i].Exchange < low[j].Exchange
+		}
+		return low[i].Market < low[j].Market
+	})
+	return low
+}
+
+// BalanceBlock returns why entries touching the venues are held for a low balance, "" when allowed
+// Always "" unless BALANCE_FLOOR_PAUSE=true
+func BalanceBlock(exchanges ...string) string {
+	if !config.GetBool("BALANCE_FLOOR_PAUSE", false) {
+		return ""
+	}
+
+	lowBalancesMu.RLock()
+	defer lowBalancesMu.RUnlock()
+
+	for _, exchange := range exchanges {
+		for _, market := range []string{"spot", "futures"} {
+			if b, ok := lowBalances[exchange+":"+market]; ok {
+				return fmt.Sprintf("%s %s free USDT %.2f below floor %.2f", exchange, market, b.FreeUSDT, b.Floor)
+			}
+		}
+	}
+	return ""
+}
+
+// StartBalanceChecks checks balance floors now and then every BALANCE_CHECK_INTERVAL in the background
+func StartBalanceChecks(exchanges []string) {
+	interval := config.GetDuration("BALANCE_CHECK_INTERVAL", time.Minute)
+
+	if interval <= 0 {
+		go CheckBalanceFloors(context.Background(), exchanges)
+		return
+	}
+
+	scheduler.Register(scheduler.Job{
+		Name:       "balance_check",
+		Schedule:   "@every " + interval.String(),
+		RunAtStart: true,
+		Run: func(ctx context.Context, _ time.Time) error {
+			CheckBalanceFloors(ctx, exchanges)
+			return nil
+		},
+	})
+}
@@ -0,0 +1,21 @@
+package binance
+
+import (
+	"testing"
+
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/clients/conformance"
+	"arbitrage.trade/clients/conformance/conformancetest"
+)
+
+// TestConformance runs the adapter conformance suite against testdata/conformance/binance.json
+func TestConformance(t *testing.T) {
+	conformancetest.Run(t, conformance.Suite{
+		Exchange:   "binance",
+		Pair:       fixturePair,
+		AmountUSDT: 20,
+		New: func() common.ExchangeTradeClient {
+			return NewBinanceClient(fixtureKey, fixtureSecret)
+		},
+	})
+}
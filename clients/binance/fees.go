@@ -0,0 +1,42 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"arbitrage.trade/clients/common"
+)
+
+// GetFeeRate returns the account's commission for the pair, which follows its VIP tier
+func (b *BinanceClient) GetFeeRate(ctx context.Context, market, pairName string) (*common.FeeRate, error) {
+	params := url.Values{}
+	params.Set("symbol", b.normalizePairName(pairName, market == "futures"))
+
+	if market == "futures" {
+		var result struct {
+			MakerCommissionRate string `json:"makerCommissionRate"`
+			TakerCommissionRate string `json:"takerCommissionRate"`
+		}
+		if err := b.signedRequest(ctx, "GET", b.futsBaseURL+"/fapi/v1/commissionRate", params, &result); err != nil {
+			return nil, fmt.Errorf("failed to get futures commission: %w", err)
+		}
+		taker, _ := strconv.ParseFloat(result.TakerCommissionRate, 64)
+		maker, _ := strconv.ParseFloat(result.MakerCommissionRate, 64)
+		return &common.FeeRate{Taker: taker, Maker: maker}, nil
+	}
+
+	var result struct {
+		StandardCommission struct {
+			Maker string `json:"maker"`
+			Taker string `json:"taker"`
+		} `json:"standardCommission"`
+	}
+	if err := b.signedRequest(ctx, "GET", b.spotBaseURL+"/api/v3/account/commission", params, &result); err != nil {
+		return nil, fmt.Errorf("failed to get spot commission: %w", err)
+	}
+	taker, _ := strconv.ParseFloat(result.StandardCommission.Taker, 64)
+	maker, _ := strconv.ParseFloat(result.StandardCommission.Maker, 64)
+	return &common.FeeRate{Taker: taker, Maker: maker}, nil
+}
@@ -0,0 +1,177 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/config"
+	"arbitrage.trade/logging"
+)
+
+// Symbol filters
+//
+// The PRICE_FILTER, LOT_SIZE and (MIN_)NOTIONAL filters of every spot and USDⓈ-M symbol are loaded from
+// exchangeInfo in one request per market, on prewarm or first use, and refreshed every
+// BINANCE_EXCHANGE_INFO_REFRESH (default 1h). Order quantities are floored to the symbol's step
+// and opening orders below its minimum quantity or notional are refused before they are sent,
+// all from memory. A failed refresh keeps serving the previous filters.
+
+// symbolFilter is one symbol's order size rules
+type symbolFilter struct {
+	TickSize    float64
+	StepSize    float64
+	MinQty      float64
+	MinNotional float64
+}
+
+// filterCache holds every symbol's filters per market
+type filterCache struct {
+	spot        map[string]symbolFilter
+	futures     map[string]symbolFilter
+	loadedAt    time.Time
+	attemptedAt time.Time // Last load attempt, failed loads are retried after filterRetry
+}
+
+// filterRetry spaces out load attempts while exchangeInfo is unreachable
+const filterRetry = time.Minute
+
+// exchangeInfo is the subset of /api/v3/exchangeInfo and /fapi/v1/exchangeInfo the filters are read from
+type exchangeInfo struct {
+	Symbols []struct {
+		Symbol  string `json:"symbol"`
+		Filters []struct {
+			FilterType  string `json:"filterType"`
+			MinQty      string `json:"minQty"`
+			StepSize    string `json:"stepSize"`
+			TickSize    string `json:"tickSize"`
+			MinNotional string `json:"minNotional"`
+			Notional    string `json:"notional"`
+		} `json:"filters"`
+	} `json:"symbols"`
+}
+
+// loadFilters fetches one market's exchangeInfo and indexes its filters by symbol
+func (b *BinanceClient) loadFilters(ctx context.Context, url string) (map[string]symbolFilter, error) {
+	resp, err := b.rest.Get(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load exchange info: %w", err)
+	}
+	if resp.Status != http.StatusOK {
+		return nil, fmt.Errorf("exchange info returned status %d", resp.Status)
+	}
+
+	var info exchangeInfo
+	if err := json.Unmarshal(resp.Body, &info); err != nil {
+		return nil, fmt.Errorf("failed to parse exchange info: %w", err)
+	}
+
+	filters := make(map[string]symbolFilter, len(info.Symbols))
+	for _, s := range info.Symbols {
+		var filter symbolFilter
+		for _, f := range s.Filters {
+			switch f.FilterType {
+			case "PRICE_FILTER":
+				filter.TickSize, _ = strconv.ParseFloat(f.TickSize, 64)
+			case "LOT_SIZE":
+				filter.StepSize, _ = strconv.ParseFloat(f.StepSize, 64)
+				filter.MinQty, _ = strconv.ParseFloat(f.MinQty, 64)
+			case "NOTIONAL", "MIN_NOTIONAL":
+				notional := f.MinNotional
+				if notional == "" {
+					notional = f.Notional
+				}
+				filter.MinNotional, _ = strconv.ParseFloat(notional, 64)
+			}
+		}
+		filters[s.Symbol] = filter
+	}
+	return filters, nil
+}
+
+// refreshFilters reloads both markets' filters once the cache is older than the refresh interval
+func (b *BinanceClient) refreshFilters(ctx context.Context) {
+	b.filtersMu.Lock()
+	defer b.filtersMu.Unlock()
+
+	refresh := config.GetDuration("BINANCE_EXCHANGE_INFO_REFRESH", time.Hour)
+	if b.filters.spot != nil && time.Since(b.filters.loadedAt) < refresh {
+		return
+	}
+	if time.Since(b.filters.attemptedAt) < filterRetry {
+		return
+	}
+	b.filters.attemptedAt = time.Now()
+
+	spot, err := b.loadFilters(ctx, b.spotBaseURL+"/api/v3/exchangeInfo")
+	if err != nil {
+		logging.Warnf("binance", "[BINANCE] refreshFilters - spot: %v", err)
+		return
+	}
+	futures, err := b.loadFilters(ctx, b.futsBaseURL+"/fapi/v1/exchangeInfo")
+	if err != nil {
+		logging.Warnf("binance", "[BINANCE] refreshFilters - futures: %v", err)
+		return
+	}
+
+	b.filters.spot, b.filters.futures, b.filters.loadedAt = spot, futures, time.Now()
+	logging.Debugf("binance", "[BINANCE] Loaded filters for %d spot and %d futures symbols", len(spot), len(futures))
+}
+
+// symbolFilter returns a symbol's cached filters, false when they are not known
+func (b *BinanceClient) symbolFilter(ctx context.Context, symbol string, futures bool) (symbolFilter, bool) {
+	b.refreshFilters(ctx)
+
+	b.filtersMu.Lock()
+	defer b.filtersMu.Unlock()
+
+	filters := b.filters.spot
+	if futures {
+		filters = b.filters.futures
+	}
+	filter, ok := filters[symbol]
+	return filter, ok
+}
+
+// stepQuantity floors a quantity to the symbol's step size, unchanged when the filters are unknown
+func (b *BinanceClient) stepQuantity(ctx context.Context, symbol string, futures bool, quantity float64) float64 {
+	filter, ok := b.symbolFilter(ctx, symbol, futures)
+	if !ok || !common.IsPositive(filter.StepSize) {
+		return quantity
+	}
+	return math.Floor(quantity/filter.StepSize+1e-9) * filter.StepSize
+}
+
+// tickPrice rounds a limit price to the symbol's tick size on the side that never makes it worse:
+// down for buys, up for sells. Unchanged when the filters are unknown
+func (b *BinanceClient) tickPrice(ctx context.Context, symbol string, futures bool, price float64, isBuy bool) float64 {
+	filter, ok := b.symbolFilter(ctx, symbol, futures)
+	if !ok || !common.IsPositive(filter.TickSize) {
+		return price
+	}
+	if isBuy {
+		return math.Floor(price/filter.TickSize+1e-9) * filter.TickSize
+	}
+	return math.Ceil(price/filter.TickSize-1e-9) * filter.TickSize
+}
+
+// checkMinimums refuses an opening order below the symbol's minimum quantity or notional
+// A zero quantity skips the quantity check, for orders sized in USDT
+func (b *BinanceClient) checkMinimums(ctx context.Context, symbol string, futures bool, quantity, notional float64) error {
+	filter, ok := b.symbolFilter(ctx, symbol, futures)
+	if !ok {
+		return nil
+	}
+	if common.IsPositive(quantity) && common.LessThan(quantity, filter.MinQty) {
+		return fmt.Errorf("%s quantity %.8f below minimum %.8f: %w", symbol, quantity, filter.MinQty, common.ErrOrderFailed)
+	}
+	if common.LessThan(notional, filter.MinNotional) {
+		return fmt.Errorf("%s notional %.4f below minimum %.4f: %w", symbol, notional, filter.MinNotional, common.ErrOrderFailed)
+	}
+	return nil
+}
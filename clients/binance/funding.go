@@ -0,0 +1,82 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/logging"
+)
+
+// GetFundingRate returns the perp's current funding rate from the public premium index
+func (b *BinanceClient) GetFundingRate(ctx context.Context, pairName string) (*common.FundingRate, error) {
+	url := fmt.Sprintf("%s/fapi/v1/premiumIndex?symbol=%s", b.futsBaseURL, b.normalizePairName(pairName, true))
+
+	resp, err := b.rest.Get(ctx, url)
+	if err != nil {
+		logging.Errorf("binance", "[BINANCE] GetFundingRate - ERROR: HTTP request failed: %v", err)
+		return nil, err
+	}
+
+	var result struct {
+		LastFundingRate string `json:"lastFundingRate"`
+		NextFundingTime int64  `json:"nextFundingTime"`
+	}
+	if err := json.Unmarshal(resp.Body, &result); err != nil {
+		logging.Errorf("binance", "[BINANCE] GetFundingRate - ERROR: JSON decode failed: %v", err)
+		return nil, err
+	}
+
+	rate, err := strconv.ParseFloat(result.LastFundingRate, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid funding rate %q: %w", result.LastFundingRate, err)
+	}
+	return &common.FundingRate{Rate: rate, FundingTime: time.UnixMilli(result.NextFundingTime)}, nil
+}
+
+// Binance returns up to 1000 funding settlements per /fapi/v1/fundingRate query, oldest first
+const fundingHistoryLimit = 1000
+
+// GetFundingHistory returns the perp's funding settlements in [from, to) from the public funding rate history
+func (b *BinanceClient) GetFundingHistory(ctx context.Context, pairName string, from, to time.Time) ([]common.FundingRate, error) {
+	var rates []common.FundingRate
+	seen := make(map[int64]bool)
+	pager := common.NewTimePager(from, to, 0, fundingHistoryLimit)
+	for pager.Next() {
+		w := pager.Window()
+		url := fmt.Sprintf("%s/fapi/v1/fundingRate?symbol=%s&startTime=%d&endTime=%d&limit=%d",
+			b.futsBaseURL, b.normalizePairName(pairName, true), w.From.UnixMilli(), w.EndMs(), fundingHistoryLimit)
+
+		resp, err := b.rest.Get(ctx, url)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get funding history: %w", err)
+		}
+
+		var settlements []struct {
+			FundingRate string `json:"fundingRate"`
+			FundingTime int64  `json:"fundingTime"`
+		}
+		if err := resp.DecodeJSON(&settlements); err != nil {
+			return nil, fmt.Errorf("failed to decode funding history: %w", err)
+		}
+
+		last := w.From
+		for _, s := range settlements {
+			last = time.UnixMilli(s.FundingTime)
+			if seen[s.FundingTime] {
+				continue
+			}
+			seen[s.FundingTime] = true
+			rate, err := strconv.ParseFloat(s.FundingRate, 64)
+			if err != nil {
+				continue
+			}
+			rates = append(rates, common.FundingRate{Rate: rate, FundingTime: last})
+		}
+		pager.Page(len(settlements), last)
+	}
+	return rates, nil
+}
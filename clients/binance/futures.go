@@ -4,55 +4,70 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
-	"net/http"
 	"net/url"
 	"strconv"
-	"time"
 
 	"arbitrage.trade/clients/common"
+	"arbitrage.trade/logging"
 )
 
 func (b *BinanceClient) getFuturesPrice(symbol string) (float64, error) {
 	url := fmt.Sprintf("%s/fapi/v1/ticker/price?symbol=%s", b.futsBaseURL, symbol)
 
-	resp, err := http.Get(url)
+	// Prices size the orders about to be sent, so they are never paced (see isCritical)
+	resp, err := b.rest.Get(context.Background(), url)
 	if err != nil {
-		log.Printf("[BINANCE] getFuturesPrice - ERROR: HTTP request failed: %v", err)
+		logging.Errorf("binance", "[BINANCE] getFuturesPrice - ERROR: HTTP request failed: %v", err)
 		return 0, err
 	}
-	defer resp.Body.Close()
 
 	var result struct {
 		Price string `json:"price"`
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		log.Printf("[BINANCE] getFuturesPrice - ERROR: JSON decode failed: %v", err)
+	if err := json.Unmarshal(resp.Body, &result); err != nil {
+		logging.Errorf("binance", "[BINANCE] getFuturesPrice - ERROR: JSON decode failed: %v", err)
 		return 0, err
 	}
 
 	price, err := strconv.ParseFloat(result.Price, 64)
 	if err != nil {
-		log.Printf("[BINANCE] getFuturesPrice - ERROR: Price parse failed: %v", err)
+		logging.Errorf("binance", "[BINANCE] getFuturesPrice - ERROR: Price parse failed: %v", err)
 		return 0, err
 	}
 
 	return price, nil
 }
 
-func (b *BinanceClient) getFuturesPositionRisk(ctx context.Context, symbol string) (*PositionRisk, error) {
+// getFuturesPositionRisk returns the symbol's position on a side, "LONG" or "SHORT", or its larger
+// side when side is empty; in one-way mode the symbol's single position whatever the side
+func (b *BinanceClient) getFuturesPositionRisk(ctx context.Context, symbol, side string) (*PositionRisk, error) {
 	params := url.Values{}
 	params.Set("symbol", symbol)
-	params.Set("timestamp", strconv.FormatInt(time.Now().UnixMilli(), 10))
 
 	var positions []PositionRisk
 	err := b.signedRequest(ctx, "GET", b.futsBaseURL+"/fapi/v2/positionRisk", params, &positions)
 	if err != nil {
-		log.Printf("[BINANCE] getFuturesPositionRisk - ERROR: Request failed: %v", err)
+		logging.Errorf("binance", "[BINANCE] getFuturesPositionRisk - ERROR: Request failed: %v", err)
 		return nil, err
 	}
 
-	// Find the position for the symbol (BOTH side for hedge mode, or default)
+	// Hedge mode reports a LONG and a SHORT entry per symbol, one-way mode a single BOTH entry
+	if b.isHedgeMode(ctx) {
+		if side == "" {
+			if bothSidesOpen(symbol, positions) {
+				logging.Warnf("binance", "[BINANCE] getFuturesPositionRisk - %s holds both a LONG and a SHORT position", symbol)
+			}
+			larger := largerPositionRisk(symbol, positions)
+			return &larger, nil
+		}
+		for _, pos := range positions {
+			if pos.Symbol == symbol && pos.PositionSide == side {
+				return &pos, nil
+			}
+		}
+		return &PositionRisk{Symbol: symbol, PositionAmt: 0, PositionSide: side}, nil
+	}
+
 	for _, pos := range positions {
 		if pos.Symbol == symbol && common.NotEqual(pos.PositionAmt, 0) {
 			return &pos, nil
@@ -63,9 +78,34 @@ func (b *BinanceClient) getFuturesPositionRisk(ctx context.Context, symbol strin
 	return &PositionRisk{Symbol: symbol, PositionAmt: 0}, nil
 }
 
+// GetPerpPnL returns the pair's USDT-margined position with the unrealized profit positionRisk reports
+func (b *BinanceClient) GetPerpPnL(ctx context.Context, pairName string) (*common.PositionPnL, error) {
+	position, err := b.getFuturesPositionRisk(ctx, b.normalizePairName(pairName, true), "")
+	if err != nil {
+		return nil, err
+	}
+	if common.IsZero(position.PositionAmt) {
+		return nil, nil
+	}
+	return &common.PositionPnL{
+		Size:          position.PositionAmt,
+		EntryPrice:    position.EntryPrice,
+		MarkPrice:     position.MarkPrice,
+		UnrealizedPnL: position.UnrealizedProfit,
+	}, nil
+}
+
 func (b *BinanceClient) getFuturesBalance(ctx context.Context) (float64, error) {
+	balances, err := common.CachedBalances(ctx, b.GetName(), "futures", b.fetchFuturesBalances)
+	if err != nil {
+		return 0, err
+	}
+	return balances["USDT"], nil
+}
+
+// fetchFuturesBalances reads the available balance of every futures asset in one /fapi/v2/balance call
+func (b *BinanceClient) fetchFuturesBalances(ctx context.Context) (common.BalanceSnapshot, error) {
 	params := url.Values{}
-	params.Set("timestamp", strconv.FormatInt(time.Now().UnixMilli(), 10))
 
 	var accountInfo []struct {
 		Asset            string `json:"asset"`
@@ -77,26 +117,22 @@ func (b *BinanceClient) getFuturesBalance(ctx context.Context) (float64, error)
 
 	err := b.signedRequest(ctx, "GET", b.futsBaseURL+"/fapi/v2/balance", params, &accountInfo)
 	if err != nil {
-		log.Printf("[BINANCE] getFuturesBalance - ERROR: Request failed: %v", err)
-		return 0, err
+		logging.Errorf("binance", "[BINANCE] getFuturesBalance - ERROR: Request failed: %v", err)
+		return nil, err
 	}
 
-	// Find USDT balance
+	balances := make(common.BalanceSnapshot, len(accountInfo))
 	for _, asset := range accountInfo {
-		if asset.Asset == "USDT" {
-			balance, _ := strconv.ParseFloat(asset.AvailableBalance, 64)
-			return balance, nil
-		}
+		balance, _ := strconv.ParseFloat(asset.AvailableBalance, 64)
+		balances[asset.Asset] = balance
 	}
-
-	return 0, nil
+	return balances, nil
 }
 
 func (b *BinanceClient) setLeverage(ctx context.Context, symbol string, leverage int) error {
 	params := url.Values{}
 	params.Set("symbol", symbol)
 	params.Set("leverage", strconv.Itoa(leverage))
-	params.Set("timestamp", strconv.FormatInt(time.Now().UnixMilli(), 10))
 
 	var resp struct {
 		Leverage int    `json:"leverage"`
@@ -106,24 +142,33 @@ func (b *BinanceClient) setLeverage(ctx context.Context, symbol string, leverage
 	return b.signedRequest(ctx, "POST", b.futsBaseURL+"/fapi/v1/leverage", params, &resp)
 }
 
+// GetFuturesPosition returns the signed futures position size for the pair
+func (b *BinanceClient) GetFuturesPosition(ctx context.Context, pairName string) (float64, error) {
+	positionRisk, err := b.getFuturesPositionRisk(ctx, b.normalizePairName(pairName, true), "")
+	if err != nil {
+		return 0, err
+	}
+	return positionRisk.PositionAmt * common.FuturesMultiplier(b.GetName(), pairName), nil
+}
+
 func (b *BinanceClient) PutFuturesShort(ctx context.Context, pairName string, amountUSDT float64) (*common.TradeResult, error) {
 	symbol := b.normalizePairName(pairName, true)
 
 	if err := b.setLeverage(ctx, symbol, 1); err != nil {
-		log.Printf("[BINANCE] PutFuturesShort - ERROR: Failed to set leverage: %v", err)
+		logging.Errorf("binance", "[BINANCE] PutFuturesShort - ERROR: Failed to set leverage: %v", err)
 		return nil, fmt.Errorf("failed to set leverage: %w", err)
 	}
 
 	// Get current price to calculate quantity
 	price, err := b.getFuturesPrice(symbol)
 	if err != nil {
-		log.Printf("[BINANCE] PutFuturesShort - ERROR: Failed to get futures price: %v", err)
+		logging.Errorf("binance", "[BINANCE] PutFuturesShort - ERROR: Failed to get futures price: %v", err)
 		return nil, fmt.Errorf("failed to get futures price: %w", err)
 	}
 
 	balance, err := b.getFuturesBalance(ctx)
 	if err != nil {
-		log.Printf("[BINANCE] PutFuturesShort - ERROR: Failed to get USDT balance: %v", err)
+		logging.Errorf("binance", "[BINANCE] PutFuturesShort - ERROR: Failed to get USDT balance: %v", err)
 		return nil, fmt.Errorf("failed to get USDT balance: %w", err)
 	}
 
@@ -131,14 +176,19 @@ func (b *BinanceClient) PutFuturesShort(ctx context.Context, pairName string, am
 
 	quantity := amountUSDT / price
 
-	quantity = common.RoundQuantity(quantity, pairName)
+	quantity = b.stepQuantity(ctx, symbol, true, common.RoundQuantity(quantity, pairName))
+	if err := b.checkMinimums(ctx, symbol, true, quantity, quantity*price); err != nil {
+		logging.Errorf("binance", "[BINANCE] PutFuturesShort - ERROR: %v", err)
+		return nil, err
+	}
 	// Place market sell order (short)
 	params := url.Values{}
 	params.Set("symbol", symbol)
 	params.Set("side", "SELL")
 	params.Set("type", "MARKET")
+	params.Set("newClientOrderId", common.NewClientOrderID())
 	params.Set("quantity", common.FormatQuantity(quantity, pairName))
-	params.Set("timestamp", strconv.FormatInt(time.Now().UnixMilli(), 10))
+	b.setPositionSide(ctx, params, "SHORT", false)
 
 	var orderResp struct {
 		OrderID     int64  `json:"orderId"`
@@ -149,7 +199,7 @@ func (b *BinanceClient) PutFuturesShort(ctx context.Context, pairName string, am
 
 	err = b.signedRequest(ctx, "POST", b.futsBaseURL+"/fapi/v1/order", params, &orderResp)
 	if err != nil {
-		log.Printf("[BINANCE] PutFuturesShort - ERROR: Order failed: %v", err)
+		logging.Errorf("binance", "[BINANCE] PutFuturesShort - ERROR: Order failed: %v", err)
 		return nil, fmt.Errorf("futures short order failed: %w", err)
 	}
 
@@ -170,27 +220,27 @@ func (b *BinanceClient) PutFuturesShort(ctx context.Context, pairName string, am
 	}
 	b.posMutex.Unlock()
 
-	return &common.TradeResult{
+	return common.ToBaseUnits(&common.TradeResult{
 		OrderID:       strconv.FormatInt(orderResp.OrderID, 10),
 		ExecutedPrice: avgPrice,
 		ExecutedQty:   execQty,
 		Fee:           0, // Futures API doesn't return fee in order response
 		Success:       orderResp.Status == "FILLED",
-	}, nil
+	}, common.FuturesMultiplier(b.GetName(), pairName)), nil
 }
 
 func (b *BinanceClient) CloseFuturesShort(ctx context.Context, pairName string) (*common.TradeResult, float64, error) {
 	symbol := b.normalizePairName(pairName, true)
 
 	// Get actual position from Binance API
-	positionRisk, err := b.getFuturesPositionRisk(ctx, symbol)
+	positionRisk, err := b.getFuturesPositionRisk(ctx, symbol, "SHORT")
 	if err != nil {
-		log.Printf("[BINANCE] CloseFuturesShort - ERROR: Failed to get position risk: %v", err)
+		logging.Errorf("binance", "[BINANCE] CloseFuturesShort - ERROR: Failed to get position risk: %v", err)
 		return nil, 0.00, fmt.Errorf("failed to get position risk: %w", err)
 	}
 
 	if common.IsZero(positionRisk.PositionAmt) {
-		log.Printf("[BINANCE] CloseFuturesShort - No open position found on exchange for %s", symbol)
+		logging.Infof("binance", "[BINANCE] CloseFuturesShort - No open position found on exchange for %s", symbol)
 		// Clean up local position tracking
 		b.posMutex.Lock()
 		delete(b.positions, pairName+"_futures")
@@ -207,10 +257,10 @@ func (b *BinanceClient) CloseFuturesShort(ctx context.Context, pairName string)
 	}
 
 	// Round quantity to step size
-	closeQuantity = common.RoundQuantity(closeQuantity, pairName)
+	closeQuantity = b.stepQuantity(ctx, symbol, true, common.RoundQuantity(closeQuantity, pairName))
 
 	if common.IsNegativeOrZero(closeQuantity) {
-		log.Printf("[BINANCE] CloseFuturesShort - ERROR: Calculated quantity is zero or negative: %.8f", closeQuantity)
+		logging.Errorf("binance", "[BINANCE] CloseFuturesShort - ERROR: Calculated quantity is zero or negative: %.8f", closeQuantity)
 		return nil, 0.00, fmt.Errorf("invalid close quantity: %.8f", closeQuantity)
 	}
 
@@ -219,8 +269,9 @@ func (b *BinanceClient) CloseFuturesShort(ctx context.Context, pairName string)
 	params.Set("symbol", symbol)
 	params.Set("side", "BUY")
 	params.Set("type", "MARKET")
+	params.Set("newClientOrderId", common.NewClientOrderID())
 	params.Set("quantity", common.FormatQuantity(closeQuantity, pairName))
-	params.Set("timestamp", strconv.FormatInt(time.Now().UnixMilli(), 10))
+	b.setPositionSide(ctx, params, "SHORT", false)
 
 	var orderResp struct {
 		OrderID     int64  `json:"orderId"`
@@ -231,7 +282,7 @@ func (b *BinanceClient) CloseFuturesShort(ctx context.Context, pairName string)
 
 	err = b.signedRequest(ctx, "POST", b.futsBaseURL+"/fapi/v1/order", params, &orderResp)
 	if err != nil {
-		log.Printf("[BINANCE] CloseFuturesShort - ERROR: Close order failed: %v", err)
+		logging.Errorf("binance", "[BINANCE] CloseFuturesShort - ERROR: Close order failed: %v", err)
 		return nil, 0.00, fmt.Errorf("futures close order failed: %w", err)
 	}
 
@@ -245,7 +296,7 @@ func (b *BinanceClient) CloseFuturesShort(ctx context.Context, pairName string)
 
 	newBalance, err := b.getFuturesBalance(ctx)
 	if err != nil {
-		log.Printf("[BINANCE] CloseFuturesShort - ERROR: Failed to get USDT balance: %v", err)
+		logging.Errorf("binance", "[BINANCE] CloseFuturesShort - ERROR: Failed to get USDT balance: %v", err)
 		return nil, 0.00, fmt.Errorf("failed to get USDT balance: %w", err)
 	}
 
@@ -253,13 +304,13 @@ func (b *BinanceClient) CloseFuturesShort(ctx context.Context, pairName string)
 
 	common.SetBalance(b.GetName(), "futures", "USDT", newBalance)
 
-	profit := newBalance - prevBalance
+	profit := common.Diff(newBalance, prevBalance)
 
-	return &common.TradeResult{
+	return common.ToBaseUnits(&common.TradeResult{
 		OrderID:       strconv.FormatInt(orderResp.OrderID, 10),
 		ExecutedPrice: avgPrice,
 		ExecutedQty:   execQty,
 		Fee:           0,
 		Success:       orderResp.Status == "FILLED",
-	}, profit, nil
+	}, common.FuturesMultiplier(b.GetName(), pairName)), profit, nil
 }
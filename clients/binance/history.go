@@ -0,0 +1,95 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"arbitrage.trade/clients/common"
+)
+
+// Binance caps a trade history query's time window (24h on spot, 7 days on futures) and its size at
+// 1000 trades; full pages continue from the last trade's time, see common.TimePager
+const historyPageLimit = 1000
+
+// GetFills returns the account's spot (/api/v3/myTrades) or futures (/fapi/v1/userTrades) trades of the pair in [from, to)
+// Futures quantities and prices are converted from contracts to base units
+func (b *BinanceClient) GetFills(ctx context.Context, market, pairName string, from, to time.Time) ([]common.AccountFill, error) {
+	isFutures := market == "futures"
+	endpoint := b.spotBaseURL + "/api/v3/myTrades"
+	window := 24 * time.Hour
+	if isFutures {
+		endpoint = b.futsBaseURL + "/fapi/v1/userTrades"
+		window = 7 * 24 * time.Hour
+	}
+	multiplier := 1.0
+	if isFutures {
+		multiplier = common.FuturesMultiplier(b.GetName(), pairName)
+	}
+
+	var fills []common.AccountFill
+	seen := make(map[int64]bool)
+	pager := common.NewTimePager(from, to, window, historyPageLimit)
+	for pager.Next() {
+		w := pager.Window()
+
+		params := url.Values{}
+		params.Set("symbol", b.normalizePairName(pairName, isFutures))
+		params.Set("startTime", strconv.FormatInt(w.From.UnixMilli(), 10))
+		params.Set("endTime", strconv.FormatInt(w.EndMs(), 10))
+		params.Set("limit", strconv.Itoa(historyPageLimit))
+
+		var trades []struct {
+			ID              int64  `json:"id"`
+			OrderID         int64  `json:"orderId"`
+			Price           string `json:"price"`
+			Qty             string `json:"qty"`
+			Commission      string `json:"commission"`
+			CommissionAsset string `json:"commissionAsset"`
+			RealizedPnl     string `json:"realizedPnl"` // Futures only
+			Side            string `json:"side"`        // Futures only
+			IsBuyer         bool   `json:"isBuyer"`     // Spot only
+			Time            int64  `json:"time"`
+		}
+		if err := b.signedRequest(ctx, "GET", endpoint, params, &trades); err != nil {
+			return nil, fmt.Errorf("failed to get %s trade history: %w", market, err)
+		}
+
+		last := w.From
+		for _, trade := range trades {
+			last = time.UnixMilli(trade.Time)
+			if seen[trade.ID] {
+				continue
+			}
+			seen[trade.ID] = true
+
+			price, _ := strconv.ParseFloat(trade.Price, 64)
+			qty, _ := strconv.ParseFloat(trade.Qty, 64)
+			fee, _ := strconv.ParseFloat(trade.Commission, 64)
+			pnl, _ := strconv.ParseFloat(trade.RealizedPnl, 64)
+			side := "sell"
+			if trade.IsBuyer || trade.Side == "BUY" {
+				side = "buy"
+			}
+
+			fills = append(fills, common.AccountFill{
+				Exchange:    b.GetName(),
+				Market:      market,
+				Pair:        pairName,
+				FillID:      strconv.FormatInt(trade.ID, 10),
+				OrderID:     strconv.FormatInt(trade.OrderID, 10),
+				Side:        side,
+				Price:       price / multiplier,
+				Quantity:    qty * multiplier,
+				Fee:         fee,
+				FeeAsset:    trade.CommissionAsset,
+				RealizedPnL: pnl,
+				Time:        last,
+			})
+		}
+		pager.Page(len(trades), last)
+	}
+	return fills, nil
+}
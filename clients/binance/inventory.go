@@ -0,0 +1,229 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/logging"
+)
+
+// Reverse-direction legs for the inventory strategy: sell held spot, long the perp
+
+// placeSpotMarketOrder sends a spot market order for a base quantity
+func (b *BinanceClient) placeSpotMarketOrder(ctx context.Context, pairName, side string, quantity float64) (*common.TradeResult, error) {
+	symbol := b.normalizePairName(pairName, false)
+	quantity = b.stepQuantity(ctx, symbol, false, common.RoundQuantity(quantity, pairName))
+	if common.IsNegativeOrZero(quantity) {
+		return nil, fmt.Errorf("invalid spot quantity: %.8f", quantity)
+	}
+
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	params.Set("side", side)
+	params.Set("type", "MARKET")
+	params.Set("quantity", common.FormatQuantity(quantity, pairName))
+
+	var orderResp struct {
+		OrderID             int64  `json:"orderId"`
+		ExecutedQty         string `json:"executedQty"`
+		CummulativeQuoteQty string `json:"cummulativeQuoteQty"`
+		Status              string `json:"status"`
+		Fills               []Fill `json:"fills"`
+	}
+
+	if err := b.signedRequest(ctx, "POST", b.spotBaseURL+"/api/v3/order", params, &orderResp); err != nil {
+		logging.Errorf("binance", "[BINANCE] placeSpotMarketOrder - ERROR: %s %s failed: %v", side, pairName, err)
+		return nil, fmt.Errorf("spot %s order failed: %w", side, err)
+	}
+
+	grossUSDT, _ := strconv.ParseFloat(orderResp.CummulativeQuoteQty, 64)
+	execQty, _ := strconv.ParseFloat(orderResp.ExecutedQty, 64)
+
+	feeUSDT := b.spotFeeUSDT(pairName, orderResp.Fills)
+
+	avgPrice := 0.0
+	if common.IsPositive(execQty) {
+		avgPrice = grossUSDT / execQty
+	}
+
+	return &common.TradeResult{
+		OrderID:       strconv.FormatInt(orderResp.OrderID, 10),
+		ExecutedPrice: avgPrice,
+		ExecutedQty:   execQty,
+		Fee:           feeUSDT,
+		Success:       orderResp.Status == "FILLED",
+	}, nil
+}
+
+// spotFeeUSDT sums a spot order's commissions in USDT
+// Fees in the base asset are converted to USDT at the fill price
+func (b *BinanceClient) spotFeeUSDT(pairName string, fills []Fill) float64 {
+	var feeUSDT float64
+	for _, fill := range fills {
+		fee, _ := strconv.ParseFloat(fill.Commission, 64)
+		price, _ := strconv.ParseFloat(fill.Price, 64)
+		if fill.CommissionAsset == "USDT" {
+			feeUSDT += fee
+		} else if fill.CommissionAsset == b.getBaseAsset(pairName) {
+			feeUSDT += fee * price
+		}
+	}
+	return feeUSDT
+}
+
+// SellSpot sells quantity of held base asset for USDT
+func (b *BinanceClient) SellSpot(ctx context.Context, pairName string, quantity float64) (*common.TradeResult, error) {
+	return b.placeSpotMarketOrder(ctx, pairName, "SELL", quantity)
+}
+
+// BuySpot buys quantity of the base asset with USDT
+func (b *BinanceClient) BuySpot(ctx context.Context, pairName string, quantity float64) (*common.TradeResult, error) {
+	return b.placeSpotMarketOrder(ctx, pairName, "BUY", quantity)
+}
+
+// placeFuturesMarketOrder sends a futures market order for a quantity in contract units; the result is in base units
+func (b *BinanceClient) placeFuturesMarketOrder(ctx context.Context, symbol, side string, quantity float64, pairName string, reduceOnly bool) (*common.TradeResult, error) {
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	params.Set("side", side)
+	params.Set("type", "MARKET")
+	params.Set("quantity", common.FormatQuantity(quantity, pairName))
+	b.setPositionSide(ctx, params, positionSide(side, reduceOnly), reduceOnly)
+
+	var orderResp struct {
+		OrderID     int64  `json:"orderId"`
+		ExecutedQty string `json:"executedQty"`
+		AvgPrice    string `json:"avgPrice"`
+		Status      string `json:"status"`
+	}
+
+	if err := b.signedRequest(ctx, "POST", b.futsBaseURL+"/fapi/v1/order", params, &orderResp); err != nil {
+		logging.Errorf("binance", "[BINANCE] placeFuturesMarketOrder - ERROR: %s %s failed: %v", side, symbol, err)
+		return nil, fmt.Errorf("futures %s order failed: %w", side, err)
+	}
+
+	execQty, _ := strconv.ParseFloat(orderResp.ExecutedQty, 64)
+	avgPrice, _ := strconv.ParseFloat(orderResp.AvgPrice, 64)
+
+	return common.ToBaseUnits(&common.TradeResult{
+		OrderID:       strconv.FormatInt(orderResp.OrderID, 10),
+		ExecutedPrice: avgPrice,
+		ExecutedQty:   execQty,
+		Fee:           0, // Futures API doesn't return fee in order response
+		Success:       orderResp.Status == "FILLED",
+	}, common.FuturesMultiplier(b.GetName(), pairName)), nil
+}
+
+// PutFuturesLong opens a long perp position worth amountUSDT
+func (b *BinanceClient) PutFuturesLong(ctx context.Context, pairName string, amountUSDT float64) (*common.TradeResult, error) {
+	symbol := b.normalizePairName(pairName, true)
+
+	if err := b.setLeverage(ctx, symbol, 1); err != nil {
+		logging.Errorf("binance", "[BINANCE] PutFuturesLong - ERROR: Failed to set leverage: %v", err)
+		return nil, fmt.Errorf("failed to set leverage: %w", err)
+	}
+
+	price, err := b.getFuturesPrice(symbol)
+	if err != nil {
+		logging.Errorf("binance", "[BINANCE] PutFuturesLong - ERROR: Failed to get futures price: %v", err)
+		return nil, fmt.Errorf("failed to get futures price: %w", err)
+	}
+
+	balance, err := b.getFuturesBalance(ctx)
+	if err != nil {
+		logging.Errorf("binance", "[BINANCE] PutFuturesLong - ERROR: Failed to get USDT balance: %v", err)
+		return nil, fmt.Errorf("failed to get USDT balance: %w", err)
+	}
+	common.SetBalance(b.GetName(), "futures", "USDT", balance)
+
+	quantity := b.stepQuantity(ctx, symbol, true, common.QuantityForNotional(amountUSDT, price, pairName))
+	if common.IsNegativeOrZero(quantity) {
+		return nil, fmt.Errorf("calculated futures quantity is zero")
+	}
+	if err := b.checkMinimums(ctx, symbol, true, quantity, quantity*price); err != nil {
+		return nil, err
+	}
+
+	result, err := b.placeFuturesMarketOrder(ctx, symbol, "BUY", quantity, pairName, false)
+	if err != nil {
+		return nil, err
+	}
+
+	b.posMutex.Lock()
+	b.positions[pairName+"_futures"] = &common.Position{
+		PairName:     pairName,
+		Side:         "long",
+		Market:       "futures",
+		EntryPrice:   result.ExecutedPrice,
+		Quantity:     result.ExecutedQty,
+		AmountUSDT:   amountUSDT,
+		OrderID:      result.OrderID,
+		ExchangeName: b.GetName(),
+	}
+	b.posMutex.Unlock()
+
+	return result, nil
+}
+
+// CloseFuturesLong sells the open long perp position and returns the realized USDT change
+func (b *BinanceClient) CloseFuturesLong(ctx context.Context, pairName string) (*common.TradeResult, float64, error) {
+	symbol := b.normalizePairName(pairName, true)
+
+	positionRisk, err := b.getFuturesPositionRisk(ctx, symbol, "LONG")
+	if err != nil {
+		logging.Errorf("binance", "[BINANCE] CloseFuturesLong - ERROR: Failed to get position risk: %v", err)
+		return nil, 0.00, fmt.Errorf("failed to get position risk: %w", err)
+	}
+
+	if !common.IsPositive(positionRisk.PositionAmt) {
+		b.posMutex.Lock()
+		delete(b.positions, pairName+"_futures")
+		b.posMutex.Unlock()
+		return nil, 0.00, fmt.Errorf("no open long position on exchange")
+	}
+
+	closeQuantity := b.stepQuantity(ctx, symbol, true, common.RoundQuantity(positionRisk.PositionAmt, pairName))
+	if common.IsNegativeOrZero(closeQuantity) {
+		return nil, 0.00, fmt.Errorf("invalid close quantity: %.8f", closeQuantity)
+	}
+
+	result, err := b.placeFuturesMarketOrder(ctx, symbol, "SELL", closeQuantity, pairName, true)
+	if err != nil {
+		return nil, 0.00, err
+	}
+
+	b.posMutex.Lock()
+	delete(b.positions, pairName+"_futures")
+	b.posMutex.Unlock()
+
+	newBalance, err := b.getFuturesBalance(ctx)
+	if err != nil {
+		logging.Errorf("binance", "[BINANCE] CloseFuturesLong - ERROR: Failed to get USDT balance: %v", err)
+		return result, 0.00, fmt.Errorf("failed to get USDT balance: %w", err)
+	}
+
+	prevBalance := common.GetBalance(b.GetName(), "futures", "USDT")
+	common.SetBalance(b.GetName(), "futures", "USDT", newBalance)
+
+	return result, newBalance - prevBalance, nil
+}
+
+// ReduceSpotLong sells quantity of the held spot long, leaving the rest open
+func (b *BinanceClient) ReduceSpotLong(ctx context.Context, pairName string, quantity float64) (*common.TradeResult, error) {
+	return b.placeSpotMarketOrder(ctx, pairName, "SELL", quantity)
+}
+
+// ReduceFuturesShort buys back quantity (base units) of the open short perp position
+func (b *BinanceClient) ReduceFuturesShort(ctx context.Context, pairName string, quantity float64) (*common.TradeResult, error) {
+	symbol := b.normalizePairName(pairName, true)
+
+	contracts := b.stepQuantity(ctx, symbol, true, common.RoundQuantity(quantity/common.FuturesMultiplier(b.GetName(), pairName), pairName))
+	if common.IsNegativeOrZero(contracts) {
+		return nil, fmt.Errorf("invalid reduce quantity: %.8f", contracts)
+	}
+
+	return b.placeFuturesMarketOrder(ctx, symbol, "BUY", contracts, pairName, true)
+}
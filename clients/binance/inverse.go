@@ -0,0 +1,231 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/logging"
+)
+
+// inverseSymbol converts "btc-usdt" to the coin-margined perpetual "BTCUSD_PERP"
+func (b *BinanceClient) inverseSymbol(pairName string) string {
+	return b.getBaseAsset(pairName) + "USD_PERP"
+}
+
+// contractSize returns the USD face value of one coin-margined contract
+func (b *BinanceClient) contractSize(ctx context.Context, symbol string) (float64, error) {
+	b.contractSizesMu.Lock()
+	defer b.contractSizesMu.Unlock()
+
+	if b.contractSizes == nil {
+		var info struct {
+			Symbols []struct {
+				Symbol       string  `json:"symbol"`
+				ContractSize float64 `json:"contractSize"`
+			} `json:"symbols"`
+		}
+		resp, err := b.rest.Get(ctx, b.dapiBaseURL+"/dapi/v1/exchangeInfo")
+		if err != nil {
+			return 0, fmt.Errorf("failed to load coin-margined contracts: %w", err)
+		}
+		if err := json.Unmarshal(resp.Body, &info); err != nil {
+			return 0, fmt.Errorf("failed to parse coin-margined contracts: %w", err)
+		}
+		b.contractSizes = make(map[string]float64, len(info.Symbols))
+		for _, s := range info.Symbols {
+			b.contractSizes[s.Symbol] = s.ContractSize
+		}
+	}
+
+	size, ok := b.contractSizes[symbol]
+	if !ok || common.IsNegativeOrZero(size) {
+		return 0, fmt.Errorf("no coin-margined perpetual %s", symbol)
+	}
+	return size, nil
+}
+
+// getInversePositionRisk returns the one-way coin-margined position of a symbol
+func (b *BinanceClient) getInversePositionRisk(ctx context.Context, symbol string) (*PositionRisk, error) {
+	params := url.Values{}
+
+	var positions []PositionRisk
+	if err := b.signedRequest(ctx, "GET", b.dapiBaseURL+"/dapi/v1/positionRisk", params, &positions); err != nil {
+		logging.Errorf("binance", "[BINANCE] getInversePositionRisk - ERROR: Request failed: %v", err)
+		return nil, err
+	}
+
+	for _, pos := range positions {
+		if pos.Symbol == symbol && common.NotEqual(pos.PositionAmt, 0) {
+			return &pos, nil
+		}
+	}
+	return &PositionRisk{Symbol: symbol, PositionAmt: 0}, nil
+}
+
+// getCoinMargin returns the available balance of a coin in the coin-margined futures wallet
+func (b *BinanceClient) getCoinMargin(ctx context.Context, asset string) (float64, error) {
+	params := url.Values{}
+
+	var balances []struct {
+		Asset            string `json:"asset"`
+		AvailableBalance string `json:"availableBalance"`
+	}
+	if err := b.signedRequest(ctx, "GET", b.dapiBaseURL+"/dapi/v1/balance", params, &balances); err != nil {
+		return 0, err
+	}
+
+	for _, balance := range balances {
+		if balance.Asset == asset {
+			return strconv.ParseFloat(balance.AvailableBalance, 64)
+		}
+	}
+	return 0, nil
+}
+
+// placeInverseMarketOrder sends a coin-margined market order for whole contracts
+// The result is in base units: the coin amount filled at the average price
+func (b *BinanceClient) placeInverseMarketOrder(ctx context.Context, symbol, side string, contracts float64, reduceOnly bool) (*common.TradeResult, error) {
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	params.Set("side", side)
+	params.Set("type", "MARKET")
+	params.Set("quantity", strconv.FormatFloat(contracts, 'f', 0, 64))
+	params.Set("newOrderRespType", "RESULT")
+	if reduceOnly {
+		params.Set("reduceOnly", "true")
+	}
+
+	var orderResp struct {
+		OrderID     int64  `json:"orderId"`
+		ExecutedQty string `json:"executedQty"` // Contracts
+		CumBase     string `json:"cumBase"`     // Coin
+		AvgPrice    string `json:"avgPrice"`
+		Status      string `json:"status"`
+	}
+	if err := b.signedRequest(ctx, "POST", b.dapiBaseURL+"/dapi/v1/order", params, &orderResp); err != nil {
+		logging.Errorf("binance", "[BINANCE] placeInverseMarketOrder - ERROR: %s %s failed: %v", side, symbol, err)
+		return nil, fmt.Errorf("coin-margined %s order failed: %w", side, err)
+	}
+
+	cumBase, _ := strconv.ParseFloat(orderResp.CumBase, 64)
+	avgPrice, _ := strconv.ParseFloat(orderResp.AvgPrice, 64)
+
+	return &common.TradeResult{
+		OrderID:       strconv.FormatInt(orderResp.OrderID, 10),
+		ExecutedPrice: avgPrice,
+		ExecutedQty:   cumBase,
+		Fee:           0, // Futures API doesn't return fee in order response
+		Success:       orderResp.Status == "FILLED",
+	}, nil
+}
+
+// PutInverseShort shorts coin-margined contracts worth amountUSDT, backed by coin already in the COIN-M wallet
+func (b *BinanceClient) PutInverseShort(ctx context.Context, pairName string, amountUSDT float64) (*common.TradeResult, error) {
+	symbol := b.inverseSymbol(pairName)
+
+	size, err := b.contractSize(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	contracts := common.InverseContracts(amountUSDT, size)
+	if common.IsNegativeOrZero(contracts) {
+		return nil, fmt.Errorf("%.2f USDT is below one %s contract (%.0f USD)", amountUSDT, symbol, size)
+	}
+
+	price, err := b.getSpotPrice(b.normalizePairName(pairName, false))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spot price: %w", err)
+	}
+
+	asset := b.getBaseAsset(pairName)
+	margin, err := b.getCoinMargin(ctx, asset)
+	if err != nil {
+		logging.Errorf("binance", "[BINANCE] PutInverseShort - ERROR: Failed to get %s margin: %v", asset, err)
+		return nil, fmt.Errorf("failed to get %s margin: %w", asset, err)
+	}
+	if needed := common.InverseMargin(contracts, size, price); margin < needed {
+		return nil, fmt.Errorf("%w: %.8f %s in COIN-M wallet, %.8f needed for %.0f %s contracts",
+			common.ErrInsufficientBalance, margin, asset, needed, contracts, symbol)
+	}
+
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	params.Set("leverage", "1")
+	var leverage struct {
+		Leverage int `json:"leverage"`
+	}
+	if err := b.signedRequest(ctx, "POST", b.dapiBaseURL+"/dapi/v1/leverage", params, &leverage); err != nil {
+		logging.Errorf("binance", "[BINANCE] PutInverseShort - ERROR: Failed to set leverage: %v", err)
+		return nil, fmt.Errorf("failed to set leverage: %w", err)
+	}
+
+	result, err := b.placeInverseMarketOrder(ctx, symbol, "SELL", contracts, false)
+	if err != nil {
+		return nil, err
+	}
+
+	b.posMutex.Lock()
+	b.positions[pairName+"_inverse"] = &common.Position{
+		PairName:     pairName,
+		Side:         "short",
+		Market:       "inverse",
+		EntryPrice:   result.ExecutedPrice,
+		Quantity:     contracts,
+		AmountUSDT:   amountUSDT,
+		OrderID:      result.OrderID,
+		ExchangeName: b.GetName(),
+	}
+	b.posMutex.Unlock()
+
+	return result, nil
+}
+
+// CloseInverseShort buys back the coin-margined short; the realized coin P&L is converted to USDT at the close price
+func (b *BinanceClient) CloseInverseShort(ctx context.Context, pairName string) (*common.TradeResult, float64, error) {
+	symbol := b.inverseSymbol(pairName)
+
+	positionRisk, err := b.getInversePositionRisk(ctx, symbol)
+	if err != nil {
+		return nil, 0.00, fmt.Errorf("failed to get position risk: %w", err)
+	}
+	if !common.IsNegative(positionRisk.PositionAmt) {
+		b.posMutex.Lock()
+		delete(b.positions, pairName+"_inverse")
+		b.posMutex.Unlock()
+		return nil, 0.00, fmt.Errorf("no open coin-margined short on exchange")
+	}
+
+	size, err := b.contractSize(ctx, symbol)
+	if err != nil {
+		return nil, 0.00, err
+	}
+
+	contracts := -positionRisk.PositionAmt
+	result, err := b.placeInverseMarketOrder(ctx, symbol, "BUY", contracts, true)
+	if err != nil {
+		return nil, 0.00, err
+	}
+
+	b.posMutex.Lock()
+	delete(b.positions, pairName+"_inverse")
+	b.posMutex.Unlock()
+
+	coin, profit := common.InverseShortPnL(contracts, size, positionRisk.EntryPrice, result.ExecutedPrice)
+	logging.Infof("binance", "[BINANCE] CloseInverseShort - %s realized %+.8f %s = %+.4f USDT",
+		symbol, coin, b.getBaseAsset(pairName), profit)
+
+	return result, profit, nil
+}
+
+// GetInversePosition returns the signed coin-margined position in contracts for the pair
+func (b *BinanceClient) GetInversePosition(ctx context.Context, pairName string) (float64, error) {
+	positionRisk, err := b.getInversePositionRisk(ctx, b.inverseSymbol(pairName))
+	if err != nil {
+		return 0, err
+	}
+	return positionRisk.PositionAmt, nil
+}
@@ -0,0 +1,184 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/logging"
+)
+
+// Resting (maker) limit orders
+//
+// Maker legs rest a post-only order, LIMIT_MAKER on spot and LIMIT with timeInForce GTX on USDⓈ-M,
+// which Binance expires instead of letting it take liquidity. Fills are polled from the order; once
+// it is done its commissions are read from the account's trades of the order, so a maker rebate
+// arrives as the negative fee Binance reports. Futures quantities and prices are converted to and
+// from contract units, and in hedge mode the order names the SHORT position, the only one maker
+// legs trade.
+
+// limitOrder is the subset of an order query both markets report
+type limitOrder struct {
+	OrderID             int64  `json:"orderId"`
+	Status              string `json:"status"`
+	ExecutedQty         string `json:"executedQty"`
+	CummulativeQuoteQty string `json:"cummulativeQuoteQty"` // Spot
+	AvgPrice            string `json:"avgPrice"`            // Futures
+}
+
+// limitOrderDone reports whether an order can no longer fill
+func limitOrderDone(status string) bool {
+	switch status {
+	case "FILLED", "CANCELED", "EXPIRED", "EXPIRED_IN_MATCH", "REJECTED":
+		return true
+	default:
+		return false
+	}
+}
+
+// PlaceLimitOrder rests a post-only limit order for a base quantity and returns its order ID
+func (b *BinanceClient) PlaceLimitOrder(ctx context.Context, pairName string, market string, side string, price float64, quantity float64) (string, error) {
+	isFutures := market == "futures"
+	isBuy := side == "buy"
+	symbol := b.normalizePairName(pairName, isFutures)
+
+	multiplier := 1.0
+	if isFutures {
+		multiplier = common.FuturesMultiplier(b.GetName(), pairName)
+	}
+	quantity = b.stepQuantity(ctx, symbol, isFutures, common.RoundQuantity(quantity/multiplier, pairName))
+	price = b.tickPrice(ctx, symbol, isFutures, price*multiplier, isBuy)
+	if common.IsNegativeOrZero(quantity) {
+		return "", fmt.Errorf("invalid limit quantity: %.8f", quantity)
+	}
+	if err := b.checkMinimums(ctx, symbol, isFutures, quantity, quantity*price); err != nil {
+		return "", err
+	}
+
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	params.Set("side", strings.ToUpper(side))
+	params.Set("price", strconv.FormatFloat(price, 'f', -1, 64))
+	params.Set("quantity", common.FormatQuantity(quantity, pairName))
+	params.Set("newClientOrderId", common.NewClientOrderID())
+
+	endpoint := b.spotBaseURL + "/api/v3/order"
+	if isFutures {
+		endpoint = b.futsBaseURL + "/fapi/v1/order"
+		params.Set("type", "LIMIT")
+		params.Set("timeInForce", "GTX")
+		b.setPositionSide(ctx, params, "SHORT", false)
+	} else {
+		params.Set("type", "LIMIT_MAKER")
+	}
+
+	var order limitOrder
+	if err := b.signedRequest(ctx, "POST", endpoint, params, &order); err != nil {
+		logging.Errorf("binance", "[BINANCE] PlaceLimitOrder - ERROR: %s %s %s failed: %v", market, side, symbol, err)
+		return "", fmt.Errorf("%s limit %s order failed: %w", market, side, err)
+	}
+	return strconv.FormatInt(order.OrderID, 10), nil
+}
+
+// GetLimitOrder returns a limit order's fill in base units; its fee is read once the order is done
+func (b *BinanceClient) GetLimitOrder(ctx context.Context, pairName string, market string, orderID string) (*common.TradeResult, bool, error) {
+	isFutures := market == "futures"
+	symbol := b.normalizePairName(pairName, isFutures)
+
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	params.Set("orderId", orderID)
+
+	endpoint := b.spotBaseURL + "/api/v3/order"
+	if isFutures {
+		endpoint = b.futsBaseURL + "/fapi/v1/order"
+	}
+
+	var order limitOrder
+	if err := b.signedRequest(ctx, "GET", endpoint, params, &order); err != nil {
+		return nil, false, fmt.Errorf("failed to get %s order %s: %w", market, orderID, err)
+	}
+
+	execQty, _ := strconv.ParseFloat(order.ExecutedQty, 64)
+	avgPrice, _ := strconv.ParseFloat(order.AvgPrice, 64)
+	if !isFutures && common.IsPositive(execQty) {
+		quoteQty, _ := strconv.ParseFloat(order.CummulativeQuoteQty, 64)
+		avgPrice = quoteQty / execQty
+	}
+
+	done := limitOrderDone(order.Status)
+	result := &common.TradeResult{
+		OrderID:       orderID,
+		ExecutedPrice: avgPrice,
+		ExecutedQty:   execQty,
+		Success:       order.Status == "FILLED",
+	}
+	if done && common.IsPositive(execQty) {
+		fee, err := b.orderFeeUSDT(ctx, pairName, symbol, isFutures, orderID)
+		if err != nil {
+			logging.Warnf("binance", "[BINANCE] GetLimitOrder - fee of %s order %s unknown: %v", market, orderID, err)
+		}
+		result.Fee = fee
+	}
+
+	if isFutures {
+		result = common.ToBaseUnits(result, common.FuturesMultiplier(b.GetName(), pairName))
+	}
+	return result, done, nil
+}
+
+// CancelLimitOrder cancels a resting limit order; one that finished in the meantime is not an error
+func (b *BinanceClient) CancelLimitOrder(ctx context.Context, pairName string, market string, orderID string) error {
+	isFutures := market == "futures"
+
+	params := url.Values{}
+	params.Set("symbol", b.normalizePairName(pairName, isFutures))
+	params.Set("orderId", orderID)
+
+	endpoint := b.spotBaseURL + "/api/v3/order"
+	if isFutures {
+		endpoint = b.futsBaseURL + "/fapi/v1/order"
+	}
+
+	var order limitOrder
+	err := b.signedRequest(ctx, "DELETE", endpoint, params, &order)
+	if err == nil {
+		return nil
+	}
+	if _, done, getErr := b.GetLimitOrder(ctx, pairName, market, orderID); getErr == nil && done {
+		return nil
+	}
+	return fmt.Errorf("failed to cancel %s order %s: %w", market, orderID, err)
+}
+
+// orderFeeUSDT sums an order's commissions in USDT from the account's trades, negative for a rebate
+func (b *BinanceClient) orderFeeUSDT(ctx context.Context, pairName, symbol string, isFutures bool, orderID string) (float64, error) {
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	params.Set("orderId", orderID)
+
+	// Spot trades carry the same fields as an order's fills
+	var trades []Fill
+	endpoint := b.spotBaseURL + "/api/v3/myTrades"
+	if isFutures {
+		endpoint = b.futsBaseURL + "/fapi/v1/userTrades"
+	}
+	if err := b.signedRequest(ctx, "GET", endpoint, params, &trades); err != nil {
+		return 0, err
+	}
+
+	if !isFutures {
+		return b.spotFeeUSDT(pairName, trades), nil
+	}
+	fee := 0.0
+	for _, trade := range trades {
+		if trade.CommissionAsset == "USDT" {
+			commission, _ := strconv.ParseFloat(trade.Commission, 64)
+			fee += commission
+		}
+	}
+	return fee, nil
+}
@@ -1,23 +1,45 @@
 package binance
 
 import (
-	"net/http"
-	"time"
+	"context"
+	"fmt"
 
 	"arbitrage.trade/clients/common"
 )
 
 func NewBinanceClient(apiKey, apiSecret string) *BinanceClient {
-	return &BinanceClient{
+	b := &BinanceClient{
 		apiKey:      apiKey,
 		apiSecret:   apiSecret,
 		spotBaseURL: "https://api.binance.com",
 		futsBaseURL: "https://fapi.binance.com",
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-		positions: make(map[string]*common.Position),
+		dapiBaseURL: "https://dapi.binance.com",
+		rest:        common.NewRESTClient("binance"),
+		positions:   make(map[string]*common.Position),
 	}
+	b.rest.Before, b.rest.After = b.pace, b.observe
+	return b
 }
 
 func (b *BinanceClient) GetName() string { return "binance" }
+
+// Prewarm opens pooled connections to the spot and futures APIs
+func (b *BinanceClient) Prewarm(ctx context.Context) {
+	common.PrewarmConnections(ctx, b.rest.HTTP, b.spotBaseURL+"/api/v3/ping", b.futsBaseURL+"/fapi/v1/ping")
+	b.refreshFilters(ctx)
+	if b.apiKey != "" {
+		b.isHedgeMode(ctx)
+	}
+}
+
+// GetUSDTBalance returns the available USDT on the given market ("spot" or "futures")
+func (b *BinanceClient) GetUSDTBalance(ctx context.Context, market string) (float64, error) {
+	switch market {
+	case "spot":
+		return b.getSpotBalance(ctx, "USDT")
+	case "futures":
+		return b.getFuturesBalance(ctx)
+	default:
+		return 0, fmt.Errorf("no USDT balance for market %s", market)
+	}
+}
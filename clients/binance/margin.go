@@ -0,0 +1,239 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/logging"
+)
+
+// Cross-margin endpoints used to short spot (borrow base asset, sell it, later buy back and repay)
+
+func (b *BinanceClient) getMarginAsset(ctx context.Context, asset string) (*MarginAsset, error) {
+	params := url.Values{}
+
+	var account MarginAccount
+	err := b.signedRequest(ctx, "GET", b.spotBaseURL+"/sapi/v1/margin/account", params, &account)
+	if err != nil {
+		logging.Errorf("binance", "[BINANCE] getMarginAsset - ERROR: Request failed: %v", err)
+		return nil, err
+	}
+
+	for _, a := range account.UserAssets {
+		if a.Asset == asset {
+			return &a, nil
+		}
+	}
+
+	return &MarginAsset{Asset: asset}, nil
+}
+
+func (b *BinanceClient) getMaxBorrowable(ctx context.Context, asset string) (float64, error) {
+	params := url.Values{}
+	params.Set("asset", asset)
+
+	var resp struct {
+		Amount      string `json:"amount"`
+		BorrowLimit string `json:"borrowLimit"`
+	}
+
+	if err := b.signedRequest(ctx, "GET", b.spotBaseURL+"/sapi/v1/margin/maxBorrowable", params, &resp); err != nil {
+		logging.Errorf("binance", "[BINANCE] getMaxBorrowable - ERROR: Request failed: %v", err)
+		return 0, err
+	}
+
+	amount, _ := strconv.ParseFloat(resp.Amount, 64)
+	return amount, nil
+}
+
+// marginBorrowRepay borrows or repays a cross-margin asset (txType "BORROW" or "REPAY")
+func (b *BinanceClient) marginBorrowRepay(ctx context.Context, asset string, amount float64, txType string) error {
+	params := url.Values{}
+	params.Set("asset", asset)
+	params.Set("isIsolated", "FALSE")
+	params.Set("amount", strconv.FormatFloat(amount, 'f', -1, 64))
+	params.Set("type", txType)
+
+	var resp struct {
+		TranID int64 `json:"tranId"`
+	}
+
+	if err := b.signedRequest(ctx, "POST", b.spotBaseURL+"/sapi/v1/margin/borrow-repay", params, &resp); err != nil {
+		logging.Errorf("binance", "[BINANCE] marginBorrowRepay - ERROR: %s %s %.8f failed: %v", txType, asset, amount, err)
+		return err
+	}
+
+	logging.Infof("binance", "[BINANCE] marginBorrowRepay - %s %.8f %s (tranId: %d)", txType, amount, asset, resp.TranID)
+	return nil
+}
+
+func (b *BinanceClient) placeMarginMarketOrder(ctx context.Context, symbol, side string, quantity string) (*MarginOrderResponse, error) {
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	params.Set("isIsolated", "FALSE")
+	params.Set("side", side)
+	params.Set("type", "MARKET")
+	params.Set("quantity", quantity)
+	params.Set("sideEffectType", "NO_SIDE_EFFECT")
+	params.Set("newOrderRespType", "FULL")
+
+	var orderResp MarginOrderResponse
+	if err := b.signedRequest(ctx, "POST", b.spotBaseURL+"/sapi/v1/margin/order", params, &orderResp); err != nil {
+		return nil, err
+	}
+
+	return &orderResp, nil
+}
+
+// PutMarginShort borrows the base asset on cross margin and market-sells it
+func (b *BinanceClient) PutMarginShort(ctx context.Context, pairName string, amountUSDT float64) (*common.TradeResult, error) {
+	symbol := b.normalizePairName(pairName, false)
+	baseAsset := b.getBaseAsset(pairName)
+
+	price, err := b.getSpotPrice(symbol)
+	if err != nil {
+		logging.Errorf("binance", "[BINANCE] PutMarginShort - ERROR: Failed to get spot price: %v", err)
+		return nil, fmt.Errorf("failed to get spot price: %w", err)
+	}
+
+	quantity := b.stepQuantity(ctx, symbol, false, common.QuantityForNotional(amountUSDT, price, pairName))
+	if common.IsNegativeOrZero(quantity) {
+		return nil, fmt.Errorf("calculated margin quantity is zero")
+	}
+	if err := b.checkMinimums(ctx, symbol, false, quantity, quantity*price); err != nil {
+		return nil, err
+	}
+
+	maxBorrowable, err := b.getMaxBorrowable(ctx, baseAsset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get max borrowable: %w", err)
+	}
+	if common.LessThan(maxBorrowable, quantity) {
+		return nil, fmt.Errorf("%w: can borrow %.8f %s, need %.8f", common.ErrInsufficientBalance, maxBorrowable, baseAsset, quantity)
+	}
+
+	usdt, err := b.getMarginAsset(ctx, "USDT")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get margin USDT balance: %w", err)
+	}
+	common.SetBalance(b.GetName(), "margin", "USDT", usdt.Free)
+
+	if err := b.marginBorrowRepay(ctx, baseAsset, quantity, "BORROW"); err != nil {
+		return nil, fmt.Errorf("margin borrow failed: %w", err)
+	}
+
+	orderResp, err := b.placeMarginMarketOrder(ctx, symbol, "SELL", common.FormatQuantity(quantity, pairName))
+	if err != nil {
+		logging.Errorf("binance", "[BINANCE] PutMarginShort - ERROR: Sell failed, repaying loan: %v", err)
+		if repayErr := b.marginBorrowRepay(ctx, baseAsset, quantity, "REPAY"); repayErr != nil {
+			logging.Errorf("binance", "[BINANCE] PutMarginShort - ERROR: Repay after failed sell also failed: %v", repayErr)
+		}
+		return nil, fmt.Errorf("margin sell order failed: %w", err)
+	}
+
+	grossUSDT, _ := strconv.ParseFloat(orderResp.CummulativeQuoteQty, 64)
+	execQty, _ := strconv.ParseFloat(orderResp.ExecutedQty, 64)
+
+	var totalFeeInUSDT float64
+	for _, fill := range orderResp.Fills {
+		fee, _ := strconv.ParseFloat(fill.Commission, 64)
+		fillPrice, _ := strconv.ParseFloat(fill.Price, 64)
+		if fill.CommissionAsset == "USDT" {
+			totalFeeInUSDT += fee
+		} else if fill.CommissionAsset == baseAsset {
+			totalFeeInUSDT += fee * fillPrice
+		}
+	}
+
+	avgPrice := 0.0
+	if common.IsPositive(execQty) {
+		avgPrice = grossUSDT / execQty
+	}
+
+	b.posMutex.Lock()
+	b.positions[pairName+"_margin"] = &common.Position{
+		PairName:     pairName,
+		Side:         "short",
+		Market:       "margin",
+		EntryPrice:   avgPrice,
+		Quantity:     execQty,
+		AmountUSDT:   grossUSDT,
+		OrderID:      strconv.FormatInt(orderResp.OrderID, 10),
+		ExchangeName: b.GetName(),
+	}
+	b.posMutex.Unlock()
+
+	return &common.TradeResult{
+		OrderID:       strconv.FormatInt(orderResp.OrderID, 10),
+		ExecutedPrice: avgPrice,
+		ExecutedQty:   execQty,
+		Fee:           totalFeeInUSDT,
+		Success:       orderResp.Status == "FILLED",
+	}, nil
+}
+
+// CloseMarginShort buys back the borrowed base asset plus interest and repays the loan
+func (b *BinanceClient) CloseMarginShort(ctx context.Context, pairName string) (*common.TradeResult, float64, error) {
+	symbol := b.normalizePairName(pairName, false)
+	baseAsset := b.getBaseAsset(pairName)
+
+	asset, err := b.getMarginAsset(ctx, baseAsset)
+	if err != nil {
+		return nil, 0.00, fmt.Errorf("failed to get margin %s balance: %w", baseAsset, err)
+	}
+
+	debt := asset.Borrowed + asset.Interest
+	if common.IsNegativeOrZero(debt) {
+		b.posMutex.Lock()
+		delete(b.positions, pairName+"_margin")
+		b.posMutex.Unlock()
+		return nil, 0.00, fmt.Errorf("no margin loan open for %s", baseAsset)
+	}
+
+	// Buy enough to cover the debt after any free balance, rounding up to the quantity step
+	// and adding a small buffer for the commission charged in the base asset
+	needed := (debt - asset.Free) * 1.002
+	var orderResp *MarginOrderResponse
+	if common.IsPositive(needed) {
+		buyQty := common.CeilQuantity(needed, pairName)
+		orderResp, err = b.placeMarginMarketOrder(ctx, symbol, "BUY", common.FormatQuantity(buyQty, pairName))
+		if err != nil {
+			logging.Errorf("binance", "[BINANCE] CloseMarginShort - ERROR: Buy back failed: %v", err)
+			return nil, 0.00, fmt.Errorf("margin buy back failed: %w", err)
+		}
+	}
+
+	if err := b.marginBorrowRepay(ctx, baseAsset, debt, "REPAY"); err != nil {
+		return nil, 0.00, fmt.Errorf("margin repay failed: %w", err)
+	}
+
+	b.posMutex.Lock()
+	delete(b.positions, pairName+"_margin")
+	b.posMutex.Unlock()
+
+	usdt, err := b.getMarginAsset(ctx, "USDT")
+	if err != nil {
+		return nil, 0.00, fmt.Errorf("failed to get margin USDT balance: %w", err)
+	}
+
+	prevBalance := common.GetBalance(b.GetName(), "margin", "USDT")
+	common.SetBalance(b.GetName(), "margin", "USDT", usdt.Free)
+	profit := common.Diff(usdt.Free, prevBalance)
+
+	result := &common.TradeResult{Success: true}
+	if orderResp != nil {
+		grossUSDT, _ := strconv.ParseFloat(orderResp.CummulativeQuoteQty, 64)
+		execQty, _ := strconv.ParseFloat(orderResp.ExecutedQty, 64)
+		result.OrderID = strconv.FormatInt(orderResp.OrderID, 10)
+		result.ExecutedQty = execQty
+		if common.IsPositive(execQty) {
+			result.ExecutedPrice = grossUSDT / execQty
+		}
+		result.Success = orderResp.Status == "FILLED"
+	}
+
+	return result, profit, nil
+}
@@ -0,0 +1,128 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/logging"
+)
+
+// HasBotOrders reports whether an order tagged with the bot's client order ID prefix was placed on the pair since the given time
+// Binance limits the query window to 24h on spot and 7 days on futures
+func (b *BinanceClient) HasBotOrders(ctx context.Context, market, pairName string, since time.Time) (bool, error) {
+	isFutures := market == "futures"
+	endpoint := b.spotBaseURL + "/api/v3/allOrders"
+	if isFutures {
+		endpoint = b.futsBaseURL + "/fapi/v1/allOrders"
+	}
+
+	params := url.Values{}
+	params.Set("symbol", b.normalizePairName(pairName, isFutures))
+	params.Set("startTime", strconv.FormatInt(since.UnixMilli(), 10))
+
+	var orders []struct {
+		ClientOrderID string `json:"clientOrderId"`
+	}
+	if err := b.signedRequest(ctx, "GET", endpoint, params, &orders); err != nil {
+		return false, fmt.Errorf("failed to get %s order history: %w", market, err)
+	}
+
+	for _, order := range orders {
+		if common.IsBotOrderID(order.ClientOrderID) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// PlaceIOCOrder sends a LIMIT order with timeInForce IOC; the price is rounded to the tick on the
+// side that never makes it worse and futures quantities are converted to contracts
+func (b *BinanceClient) PlaceIOCOrder(ctx context.Context, pairName string, market string, side string, price float64, quantity float64) (*common.TradeResult, error) {
+	isFutures := market == "futures"
+	isBuy := side == "buy"
+	symbol := b.normalizePairName(pairName, isFutures)
+
+	multiplier := 1.0
+	if isFutures {
+		multiplier = common.FuturesMultiplier(b.GetName(), pairName)
+		quantity /= multiplier
+	}
+	quantity = b.stepQuantity(ctx, symbol, isFutures, common.RoundQuantity(quantity, pairName))
+	price = b.tickPrice(ctx, symbol, isFutures, price, isBuy)
+	if common.IsNegativeOrZero(quantity) {
+		return nil, fmt.Errorf("invalid IOC quantity: %.8f", quantity)
+	}
+	if err := b.checkMinimums(ctx, symbol, isFutures, quantity, quantity*multiplier*price); err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	params.Set("side", strings.ToUpper(side))
+	params.Set("type", "LIMIT")
+	params.Set("timeInForce", "IOC")
+	params.Set("price", strconv.FormatFloat(price, 'f', -1, 64))
+	params.Set("quantity", common.FormatQuantity(quantity, pairName))
+	params.Set("newClientOrderId", common.NewClientOrderID())
+
+	if isFutures {
+		params.Set("newOrderRespType", "RESULT")
+		// IOC orders only open legs
+		b.setPositionSide(ctx, params, positionSide(params.Get("side"), false), false)
+
+		var orderResp struct {
+			OrderID     int64  `json:"orderId"`
+			ExecutedQty string `json:"executedQty"`
+			AvgPrice    string `json:"avgPrice"`
+			Status      string `json:"status"`
+		}
+		if err := b.signedRequest(ctx, "POST", b.futsBaseURL+"/fapi/v1/order", params, &orderResp); err != nil {
+			logging.Errorf("binance", "[BINANCE] PlaceIOCOrder - ERROR: futures %s %s failed: %v", side, symbol, err)
+			return nil, fmt.Errorf("futures IOC %s order failed: %w", side, err)
+		}
+
+		execQty, _ := strconv.ParseFloat(orderResp.ExecutedQty, 64)
+		avgPrice, _ := strconv.ParseFloat(orderResp.AvgPrice, 64)
+		return common.ToBaseUnits(&common.TradeResult{
+			OrderID:       strconv.FormatInt(orderResp.OrderID, 10),
+			ExecutedPrice: avgPrice,
+			ExecutedQty:   execQty,
+			Fee:           0, // Futures API doesn't return fee in order response
+			Success:       orderResp.Status == "FILLED",
+		}, multiplier), nil
+	}
+
+	params.Set("newOrderRespType", "FULL")
+
+	var orderResp struct {
+		OrderID             int64  `json:"orderId"`
+		ExecutedQty         string `json:"executedQty"`
+		CummulativeQuoteQty string `json:"cummulativeQuoteQty"`
+		Status              string `json:"status"`
+		Fills               []Fill `json:"fills"`
+	}
+	if err := b.signedRequest(ctx, "POST", b.spotBaseURL+"/api/v3/order", params, &orderResp); err != nil {
+		logging.Errorf("binance", "[BINANCE] PlaceIOCOrder - ERROR: spot %s %s failed: %v", side, symbol, err)
+		return nil, fmt.Errorf("spot IOC %s order failed: %w", side, err)
+	}
+
+	grossUSDT, _ := strconv.ParseFloat(orderResp.CummulativeQuoteQty, 64)
+	execQty, _ := strconv.ParseFloat(orderResp.ExecutedQty, 64)
+	avgPrice := 0.0
+	if common.IsPositive(execQty) {
+		avgPrice = grossUSDT / execQty
+	}
+
+	return &common.TradeResult{
+		OrderID:       strconv.FormatInt(orderResp.OrderID, 10),
+		ExecutedPrice: avgPrice,
+		ExecutedQty:   execQty,
+		Fee:           b.spotFeeUSDT(pairName, orderResp.Fills),
+		Success:       orderResp.Status == "FILLED",
+	}, nil
+}
@@ -0,0 +1,34 @@
+package binance
+
+import (
+	"context"
+	"net/url"
+
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/logging"
+)
+
+// GetAPIPermissions returns the key's scopes and IP restriction from the API restrictions endpoint
+func (b *BinanceClient) GetAPIPermissions(ctx context.Context) (*common.APIPermissions, error) {
+	params := url.Values{}
+
+	var restrictions struct {
+		IPRestrict                 bool `json:"ipRestrict"`
+		EnableReading              bool `json:"enableReading"`
+		EnableSpotAndMarginTrading bool `json:"enableSpotAndMarginTrading"`
+		EnableFutures              bool `json:"enableFutures"`
+		EnableWithdrawals          bool `json:"enableWithdrawals"`
+	}
+	if err := b.signedRequest(ctx, "GET", b.spotBaseURL+"/sapi/v1/account/apiRestrictions", params, &restrictions); err != nil {
+		logging.Errorf("binance", "[BINANCE] GetAPIPermissions - ERROR: Request failed: %v", err)
+		return nil, err
+	}
+
+	return &common.APIPermissions{
+		Read:         restrictions.EnableReading,
+		SpotTrade:    restrictions.EnableSpotAndMarginTrading,
+		FuturesTrade: restrictions.EnableFutures,
+		Withdraw:     restrictions.EnableWithdrawals,
+		IPRestricted: restrictions.IPRestrict,
+	}, nil
+}
@@ -0,0 +1,130 @@
+package binance
+
+import (
+	"context"
+	"math"
+	"net/url"
+	"time"
+
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/config"
+	"arbitrage.trade/logging"
+)
+
+// Position mode
+//
+// A USDⓈ-M account trades in one-way mode, one BOTH position per symbol that orders move by their
+// side, or in hedge mode (dualSidePosition), separate LONG and SHORT positions that every order must
+// name with positionSide and that reject reduceOnly. The account's setting is read from
+// /fapi/v1/positionSide/dual on prewarm or first use and re-read every BINANCE_POSITION_MODE_REFRESH
+// (default 10m), as it can be switched while no position is open. Hedge-mode orders carry
+// positionSide=SHORT for the short perp leg and LONG for the reverse trades' long leg, and positionRisk's
+// per-side entries are read by the side being closed. Where no side is given (position checks, P&L)
+// the larger side is reported rather than the net, so offsetting LONG and SHORT positions are not
+// mistaken for flat. Until the mode was read once orders are sent as in one-way mode. Coin-margined
+// positions are not covered.
+
+// positionMode caches the account's USDⓈ-M position mode
+type positionMode struct {
+	hedge       bool
+	loadedAt    time.Time
+	attemptedAt time.Time // Last load attempt, failed loads are retried after filterRetry
+}
+
+// isHedgeMode reports whether the USDⓈ-M account is in hedge mode, re-reading it once stale
+// The request is sent outside the lock; orders placed meanwhile use the cached mode
+func (b *BinanceClient) isHedgeMode(ctx context.Context) bool {
+	b.positionModeMu.Lock()
+	hedge := b.positionMode.hedge
+	refresh := config.GetDuration("BINANCE_POSITION_MODE_REFRESH", 10*time.Minute)
+	if !b.positionMode.loadedAt.IsZero() && time.Since(b.positionMode.loadedAt) < refresh {
+		b.positionModeMu.Unlock()
+		return hedge
+	}
+	if time.Since(b.positionMode.attemptedAt) < filterRetry {
+		b.positionModeMu.Unlock()
+		return hedge
+	}
+	b.positionMode.attemptedAt = time.Now()
+	b.positionModeMu.Unlock()
+
+	params := url.Values{}
+
+	var resp struct {
+		DualSidePosition bool `json:"dualSidePosition"`
+	}
+	if err := b.signedRequest(ctx, "GET", b.futsBaseURL+"/fapi/v1/positionSide/dual", params, &resp); err != nil {
+		logging.Warnf("binance", "[BINANCE] isHedgeMode - keeping hedge=%t: %v", hedge, err)
+		return hedge
+	}
+
+	b.positionModeMu.Lock()
+	defer b.positionModeMu.Unlock()
+
+	if resp.DualSidePosition != b.positionMode.hedge || b.positionMode.loadedAt.IsZero() {
+		logging.Infof("binance", "[BINANCE] Futures account in %s mode", positionModeName(resp.DualSidePosition))
+	}
+	b.positionMode.hedge, b.positionMode.loadedAt = resp.DualSidePosition, time.Now()
+	return b.positionMode.hedge
+}
+
+// positionModeName names a position mode for logs
+func positionModeName(hedge bool) string {
+	if hedge {
+		return "hedge"
+	}
+	return "one-way"
+}
+
+// positionSide returns the hedge-mode side an order moves: opening orders the side they build, reducing
+// orders the opposite of their own
+func positionSide(side string, reduceOnly bool) string {
+	if (side == "BUY") != reduceOnly {
+		return "LONG"
+	}
+	return "SHORT"
+}
+
+// setPositionSide adds the order's position parameters for the account's mode: the LONG or SHORT
+// position it moves in hedge mode, reduceOnly when requested in one-way mode
+func (b *BinanceClient) setPositionSide(ctx context.Context, params url.Values, side string, reduceOnly bool) {
+	if b.isHedgeMode(ctx) {
+		params.Set("positionSide", side)
+		return
+	}
+	if reduceOnly {
+		params.Set("reduceOnly", "true")
+	}
+}
+
+// largerPositionRisk returns hedge mode's LONG or SHORT entry of a symbol, whichever holds more, with
+// the unrealized profit of both sides. The sides are not netted: a LONG offsetting a SHORT would read
+// as flat while both are still open, so the symbol only reads flat once each side is
+func largerPositionRisk(symbol string, positions []PositionRisk) PositionRisk {
+	larger := PositionRisk{Symbol: symbol, PositionSide: "BOTH"}
+	unrealized := 0.0
+	for _, pos := range positions {
+		if pos.Symbol != symbol {
+			continue
+		}
+		unrealized += pos.UnrealizedProfit
+		if math.Abs(pos.PositionAmt) > math.Abs(larger.PositionAmt) {
+			larger = pos
+		}
+	}
+	larger.UnrealizedProfit = unrealized
+	return larger
+}
+
+// bothSidesOpen reports whether a symbol holds a LONG and a SHORT position at once in hedge mode
+func bothSidesOpen(symbol string, positions []PositionRisk) bool {
+	long, short := false, false
+	for _, pos := range positions {
+		if pos.Symbol != symbol || common.IsZero(pos.PositionAmt) {
+			continue
+		}
+		long = long || pos.PositionSide == "LONG"
+		short = short || pos.PositionSide == "SHORT"
+	}
+	return long && short
+}
@@ -0,0 +1,63 @@
+package binance
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TestRetriedRequestIsResigned checks a GET retried after a 5xx carries a new timestamp and its own signature
+func TestRetriedRequestIsResigned(t *testing.T) {
+	t.Setenv("HTTP_RETRIES", "1")
+	t.Setenv("HTTP_RETRY_BACKOFF", "5ms")
+	t.Setenv("BALANCE_CACHE_TTL", "0")
+
+	var mu sync.Mutex
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries = append(queries, r.URL.RawQuery)
+		attempt := len(queries)
+		mu.Unlock()
+
+		if attempt == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"balances":[{"asset":"XRP","free":"12.5","locked":"0"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewBinanceClient(fixtureKey, fixtureSecret)
+	client.spotBaseURL = server.URL
+	// Straight to the server, not through the exchange's shared transport other tests replay on
+	client.rest.HTTP = server.Client()
+
+	holding, err := client.GetSpotHolding(context.Background(), fixturePair)
+	if err != nil || holding != 12.5 {
+		t.Fatalf("GetSpotHolding = %v, %v, want 12.5", holding, err)
+	}
+	if len(queries) != 2 {
+		t.Fatalf("sent %d requests, want the failed one and its retry", len(queries))
+	}
+
+	timestamps := make([]string, len(queries))
+	for i, query := range queries {
+		message, signature, _ := strings.Cut(query, "&signature=")
+		mac := hmac.New(sha256.New, []byte(fixtureSecret))
+		mac.Write([]byte(message))
+		if want := hex.EncodeToString(mac.Sum(nil)); signature != want {
+			t.Errorf("attempt %d signature = %s, want %s over %q", i+1, signature, want, message)
+		}
+		_, timestamps[i], _ = strings.Cut(message, "timestamp=")
+	}
+	if timestamps[0] == timestamps[1] {
+		t.Errorf("retry resent timestamp %s, want a fresh one", timestamps[1])
+	}
+}
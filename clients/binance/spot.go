@@ -4,89 +4,102 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
-	"net/http"
 	"net/url"
 	"strconv"
-	"time"
 
 	"arbitrage.trade/clients/common"
+	"arbitrage.trade/logging"
 )
 
 func (b *BinanceClient) getSpotBalance(ctx context.Context, asset string) (float64, error) {
+	balances, err := common.CachedBalances(ctx, b.GetName(), "spot", b.fetchSpotBalances)
+	if err != nil {
+		return 0, err
+	}
+	// Free balance, available to sell
+	return balances[asset], nil
+}
+
+// fetchSpotBalances reads the free balance of every spot asset in one /api/v3/account call
+func (b *BinanceClient) fetchSpotBalances(ctx context.Context) (common.BalanceSnapshot, error) {
 	params := url.Values{}
-	params.Set("timestamp", strconv.FormatInt(time.Now().UnixMilli(), 10))
 
 	var accountInfo AccountInfo
 	err := b.signedRequest(ctx, "GET", b.spotBaseURL+"/api/v3/account", params, &accountInfo)
 	if err != nil {
-		log.Printf("[BINANCE] getSpotBalance - ERROR: Request failed: %v", err)
-		return 0, err
+		logging.Errorf("binance", "[BINANCE] getSpotBalance - ERROR: Request failed: %v", err)
+		return nil, err
 	}
 
-	// Find the balance for the asset
+	balances := make(common.BalanceSnapshot, len(accountInfo.Balances))
 	for _, balance := range accountInfo.Balances {
-		if balance.Asset == asset {
-			free, _ := strconv.ParseFloat(balance.Free, 64)
-			// Return free balance (available to sell)
-			return free, nil
-		}
+		free, _ := strconv.ParseFloat(balance.Free, 64)
+		balances[balance.Asset] = free
 	}
-
-	return 0, nil
+	return balances, nil
 }
 
 func (b *BinanceClient) getSpotPrice(symbol string) (float64, error) {
 	url := fmt.Sprintf("%s/api/v3/ticker/price?symbol=%s", b.spotBaseURL, symbol)
 
-	resp, err := http.Get(url)
+	// Prices size the orders about to be sent, so they are never paced (see isCritical)
+	resp, err := b.rest.Get(context.Background(), url)
 	if err != nil {
-		log.Printf("[BINANCE] getSpotPrice - ERROR: HTTP request failed: %v", err)
+		logging.Errorf("binance", "[BINANCE] getSpotPrice - ERROR: HTTP request failed: %v", err)
 		return 0, err
 	}
-	defer resp.Body.Close()
 
 	var result struct {
 		Price string `json:"price"`
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		log.Printf("[BINANCE] getSpotPrice - ERROR: JSON decode failed: %v", err)
+	if err := json.Unmarshal(resp.Body, &result); err != nil {
+		logging.Errorf("binance", "[BINANCE] getSpotPrice - ERROR: JSON decode failed: %v", err)
 		return 0, err
 	}
 
 	price, err := strconv.ParseFloat(result.Price, 64)
 	if err != nil {
-		log.Printf("[BINANCE] getSpotPrice - ERROR: Price parse failed: %v", err)
+		logging.Errorf("binance", "[BINANCE] getSpotPrice - ERROR: Price parse failed: %v", err)
 		return 0, err
 	}
 
 	return price, nil
 }
 
+// GetSpotHolding returns the free base-asset balance for the pair
+func (b *BinanceClient) GetSpotHolding(ctx context.Context, pairName string) (float64, error) {
+	return b.getSpotBalance(ctx, b.getBaseAsset(pairName))
+}
+
 // BinanceClient implements ExchangeTradeClient for Binance
 func (b *BinanceClient) PutSpotLong(ctx context.Context, pairName string, amountUSDT float64) (*common.TradeResult, error) {
 	symbol := b.normalizePairName(pairName, false)
 	_, err := b.getSpotPrice(symbol)
 	if err != nil {
-		log.Printf("[BINANCE] PutSpotLong - ERROR: Failed to get spot price: %v", err)
+		logging.Errorf("binance", "[BINANCE] PutSpotLong - ERROR: Failed to get spot price: %v", err)
 		return nil, fmt.Errorf("failed to get spot price: %w", err)
 	}
 
 	balance, err := b.getSpotBalance(ctx, "USDT")
 	if err != nil {
-		log.Printf("[BINANCE] PutSpotLong - ERROR: Failed to get USDT balance: %v", err)
+		logging.Errorf("binance", "[BINANCE] PutSpotLong - ERROR: Failed to get USDT balance: %v", err)
 		return nil, fmt.Errorf("failed to get USDT balance: %w", err)
 	}
 
 	common.SetBalance(b.GetName(), "spot", "USDT", balance)
 
+	if err := b.checkMinimums(ctx, symbol, false, 0, amountUSDT); err != nil {
+		logging.Errorf("binance", "[BINANCE] PutSpotLong - ERROR: %v", err)
+		return nil, err
+	}
+
 	// Place market buy order using quoteOrderQty (USDT amount)
 	params := url.Values{}
 	params.Set("symbol", symbol)
 	params.Set("side", "BUY")
 	params.Set("type", "MARKET")
+	params.Set("newClientOrderId", common.NewClientOrderID())
 	params.Set("quoteOrderQty", fmt.Sprintf("%.8f", amountUSDT))
-	params.Set("timestamp", strconv.FormatInt(time.Now().UnixMilli(), 10))
 
 	var orderResp struct {
 		OrderID             int64  `json:"orderId"`
@@ -103,7 +116,7 @@ func (b *BinanceClient) PutSpotLong(ctx context.Context, pairName string, amount
 
 	err = b.signedRequest(ctx, "POST", b.spotBaseURL+"/api/v3/order", params, &orderResp)
 	if err != nil {
-		log.Printf("[BINANCE] PutSpotLong - ERROR: Order failed: %v", err)
+		logging.Errorf("binance", "[BINANCE] PutSpotLong - ERROR: Order failed: %v", err)
 		return nil, fmt.Errorf("spot buy order failed: %w", err)
 	}
 
@@ -167,12 +180,12 @@ func (b *BinanceClient) CloseSpotLong(ctx context.Context, pairName string, amou
 	// Get actual balance from Binance API
 	balance, err := b.getSpotBalance(ctx, baseAsset)
 	if err != nil {
-		log.Printf("[BINANCE] CloseSpotLong - ERROR: Failed to get balance: %v", err)
+		logging.Errorf("binance", "[BINANCE] CloseSpotLong - ERROR: Failed to get balance: %v", err)
 		return nil, 0.00, fmt.Errorf("failed to get balance: %w", err)
 	}
 
 	if common.IsZero(balance) {
-		log.Printf("[BINANCE] CloseSpotLong - No balance found on exchange for %s", baseAsset)
+		logging.Infof("binance", "[BINANCE] CloseSpotLong - No balance found on exchange for %s", baseAsset)
 		// Clean up local position tracking
 		b.posMutex.Lock()
 		delete(b.positions, pairName+"_spot")
@@ -180,9 +193,9 @@ func (b *BinanceClient) CloseSpotLong(ctx context.Context, pairName string, amou
 		return nil, 0.00, fmt.Errorf("no balance on exchange for %s", baseAsset)
 	}
 
-	closeQuantity := common.RoundQuantity(balance, pairName)
+	closeQuantity := b.stepQuantity(ctx, symbol, false, common.RoundQuantity(balance, pairName))
 	if common.IsNegativeOrZero(closeQuantity) {
-		log.Printf("[BINANCE] CloseSpotLong - ERROR: Calculated quantity is zero or negative: %.8f", closeQuantity)
+		logging.Errorf("binance", "[BINANCE] CloseSpotLong - ERROR: Calculated quantity is zero or negative: %.8f", closeQuantity)
 		return nil, 0.00, fmt.Errorf("invalid close quantity: %.8f", closeQuantity)
 	}
 
@@ -193,8 +206,8 @@ func (b *BinanceClient) CloseSpotLong(ctx context.Context, pairName string, amou
 	params.Set("symbol", symbol)
 	params.Set("side", "SELL")
 	params.Set("type", "MARKET")
+	params.Set("newClientOrderId", common.NewClientOrderID())
 	params.Set("quantity", common.FormatQuantity(closeQuantity, pairName))
-	params.Set("timestamp", strconv.FormatInt(time.Now().UnixMilli(), 10))
 
 	var orderResp struct {
 		OrderID             int64  `json:"orderId"`
@@ -206,7 +219,7 @@ func (b *BinanceClient) CloseSpotLong(ctx context.Context, pairName string, amou
 
 	err = b.signedRequest(ctx, "POST", b.spotBaseURL+"/api/v3/order", params, &orderResp)
 	if err != nil {
-		log.Printf("[BINANCE] CloseSpotLong - ERROR: Close order failed: %v", err)
+		logging.Errorf("binance", "[BINANCE] CloseSpotLong - ERROR: Close order failed: %v", err)
 		return nil, 0.00, fmt.Errorf("spot close order failed: %w", err)
 	}
 
@@ -246,7 +259,7 @@ func (b *BinanceClient) CloseSpotLong(ctx context.Context, pairName string, amou
 
 	newBalance, err := b.getSpotBalance(ctx, "USDT")
 	if err != nil {
-		log.Printf("[BINANCE] PutSpotLong - ERROR: Failed to get USDT balance: %v", err)
+		logging.Errorf("binance", "[BINANCE] PutSpotLong - ERROR: Failed to get USDT balance: %v", err)
 		return nil, 0.00, fmt.Errorf("failed to get USDT balance: %w", err)
 	}
 
@@ -254,7 +267,7 @@ func (b *BinanceClient) CloseSpotLong(ctx context.Context, pairName string, amou
 
 	common.SetBalance(b.GetName(), "spot", "USDT", newBalance)
 
-	profit := newBalance - prevBalance
+	profit := common.Diff(newBalance, prevBalance)
 
 	return &common.TradeResult{
 		OrderID:       strconv.FormatInt(orderResp.OrderID, 10),
@@ -1,7 +1,6 @@
 package binance
 
 import (
-	"net/http"
 	"sync"
 
 	"arbitrage.trade/clients/common"
@@ -12,7 +11,21 @@ type BinanceClient struct {
 	apiSecret   string
 	spotBaseURL string
 	futsBaseURL string
-	httpClient  *http.Client
+	dapiBaseURL string // Coin-margined futures
+	rest        *common.RESTClient
+	weights     weightTracker // Used request weight per API, see weight.go
+
+	// Coin-margined contract face values in USD by symbol, loaded on first use
+	contractSizes   map[string]float64
+	contractSizesMu sync.Mutex
+
+	// Every symbol's order size filters from exchangeInfo, see filters.go
+	filters   filterCache
+	filtersMu sync.Mutex
+
+	// USDⓈ-M one-way or hedge mode, see positionmode.go
+	positionMode   positionMode
+	positionModeMu sync.Mutex
 
 	// Track open positions
 	positions map[string]*common.Position
@@ -46,3 +59,38 @@ type PositionRisk struct {
 	Leverage         float64 `json:"leverage,string"`
 	PositionSide     string  `json:"positionSide"`
 }
+
+type MarginAsset struct {
+	Asset    string  `json:"asset"`
+	Free     float64 `json:"free,string"`
+	Locked   float64 `json:"locked,string"`
+	Borrowed float64 `json:"borrowed,string"`
+	Interest float64 `json:"interest,string"`
+	NetAsset float64 `json:"netAsset,string"`
+}
+
+type MarginAccount struct {
+	UserAssets []MarginAsset `json:"userAssets"`
+}
+
+type MarginOrderResponse struct {
+	OrderID             int64  `json:"orderId"`
+	ExecutedQty         string `json:"executedQty"`
+	CummulativeQuoteQty string `json:"cummulativeQuoteQty"`
+	Status              string `json:"status"`
+	Fills               []Fill `json:"fills"`
+}
+
+// CoinConfig is one coin from /sapi/v1/capital/config/getall
+type CoinConfig struct {
+	Coin              string `json:"coin"`
+	DepositAllEnable  bool   `json:"depositAllEnable"`
+	WithdrawAllEnable bool   `json:"withdrawAllEnable"`
+	NetworkList       []struct {
+		Network        string  `json:"network"`
+		DepositEnable  bool    `json:"depositEnable"`
+		WithdrawEnable bool    `json:"withdrawEnable"`
+		WithdrawFee    float64 `json:"withdrawFee,string"`
+		WithdrawMin    float64 `json:"withdrawMin,string"`
+	} `json:"networkList"`
+}
@@ -7,11 +7,14 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
-	"io"
-	"log"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"time"
+
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/logging"
 )
 
 func (b *BinanceClient) getBaseAsset(pairName string) string {
@@ -24,6 +27,13 @@ func (b *BinanceClient) getBaseAsset(pairName string) string {
 }
 
 func (b *BinanceClient) normalizePairName(pairName string, isFutures bool) string {
+	// Some perps are listed under a different name, e.g. "pepe-usdt" as "1000PEPEUSDT"
+	if isFutures {
+		if override, ok := common.FuturesSymbolOverride(b.GetName(), pairName); ok {
+			return override.Symbol
+		}
+	}
+
 	// Convert "btc-usdt" to "BTCUSDT"
 	parts := strings.Split(strings.ToUpper(pairName), "-")
 	symbol := strings.Join(parts, "")
@@ -32,55 +42,59 @@ func (b *BinanceClient) normalizePairName(pairName string, isFutures bool) strin
 }
 
 func (b *BinanceClient) signedRequest(ctx context.Context, method, endpoint string, params url.Values, result interface{}) error {
-	// Sign the request
-	queryString := params.Encode()
-	h := hmac.New(sha256.New, []byte(b.apiSecret))
-	h.Write([]byte(queryString))
-	signature := hex.EncodeToString(h.Sum(nil))
-
-	queryString += "&signature=" + signature
-
-	var req *http.Request
-	var err error
-
-	if method == "POST" {
-		req, err = http.NewRequestWithContext(ctx, method, endpoint, strings.NewReader(queryString))
-		if err != nil {
-			return err
-		}
-		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	} else {
-		req, err = http.NewRequestWithContext(ctx, method, endpoint+"?"+queryString, nil)
-		if err != nil {
-			return err
-		}
+	// Orders, borrows and transfers move balances, whether or not they report success
+	if method != "GET" {
+		defer common.InvalidateBalances(b.GetName())
 	}
 
-	req.Header.Set("X-MBX-APIKEY", b.apiKey)
+	started := time.Now()
+	resp, err := b.rest.Do(ctx, func(ctx context.Context) (*http.Request, error) {
+		// Stamped and signed per attempt, so a retried request is not rejected as outside recvWindow
+		query := make(url.Values, len(params)+1)
+		for key, values := range params {
+			query[key] = values
+		}
+		query.Set("timestamp", strconv.FormatInt(time.Now().UnixMilli(), 10))
+		queryString := query.Encode()
+		h := hmac.New(sha256.New, []byte(b.apiSecret))
+		h.Write([]byte(queryString))
+		queryString += "&signature=" + hex.EncodeToString(h.Sum(nil))
 
-	resp, err := b.httpClient.Do(req)
+		var req *http.Request
+		var err error
+		if method == "POST" {
+			req, err = http.NewRequestWithContext(ctx, method, endpoint, strings.NewReader(queryString))
+			if err != nil {
+				return nil, err
+			}
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		} else {
+			req, err = http.NewRequestWithContext(ctx, method, endpoint+"?"+queryString, nil)
+			if err != nil {
+				return nil, err
+			}
+		}
+		req.Header.Set("X-MBX-APIKEY", b.apiKey)
+		return req, nil
+	})
 	if err != nil {
-		log.Printf("[BINANCE] signedRequest - ERROR: HTTP request failed: %v", err)
+		logging.Errorf("binance", "[BINANCE] signedRequest - ERROR: %v", err)
 		return err
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		log.Printf("[BINANCE] signedRequest - ERROR: Failed to read response body: %v", err)
-		return err
-	}
+	logging.Debugf("binance", "[BINANCE] signedRequest - %s %s -> %d in %dms: %s",
+		method, endpoint, resp.Status, time.Since(started).Milliseconds(), resp.Body)
 
-	if resp.StatusCode != http.StatusOK {
+	if resp.Status != http.StatusOK {
 		var errResp struct {
 			Code int    `json:"code"`
 			Msg  string `json:"msg"`
 		}
-		json.Unmarshal(body, &errResp)
+		json.Unmarshal(resp.Body, &errResp)
 		return fmt.Errorf("binance API error %d: %s", errResp.Code, errResp.Msg)
 	}
 
-	err = json.Unmarshal(body, result)
+	err = json.Unmarshal(resp.Body, result)
 	if err != nil {
 		return err
 	}
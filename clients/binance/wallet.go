@@ -0,0 +1,41 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/logging"
+)
+
+// GetAssetStatus returns the deposit/withdrawal state of an asset from the capital config
+func (b *BinanceClient) GetAssetStatus(ctx context.Context, asset string) (*common.AssetStatus, error) {
+	params := url.Values{}
+
+	var coins []CoinConfig
+	if err := b.signedRequest(ctx, "GET", b.spotBaseURL+"/sapi/v1/capital/config/getall", params, &coins); err != nil {
+		logging.Errorf("binance", "[BINANCE] GetAssetStatus - ERROR: Request failed: %v", err)
+		return nil, err
+	}
+
+	for _, coin := range coins {
+		if coin.Coin != asset {
+			continue
+		}
+
+		networks := make([]common.NetworkStatus, 0, len(coin.NetworkList))
+		for _, n := range coin.NetworkList {
+			networks = append(networks, common.NetworkStatus{
+				Network:     n.Network,
+				CanDeposit:  coin.DepositAllEnable && n.DepositEnable,
+				CanWithdraw: coin.WithdrawAllEnable && n.WithdrawEnable,
+				WithdrawFee: n.WithdrawFee,
+				WithdrawMin: n.WithdrawMin,
+			})
+		}
+		return common.NewAssetStatus(asset, networks), nil
+	}
+
+	return nil, fmt.Errorf("asset %s not listed", asset)
+}
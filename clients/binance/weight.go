@@ -0,0 +1,178 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"arbitrage.trade/config"
+	"arbitrage.trade/logging"
+)
+
+// Request weight
+//
+// Binance caps the request weight an IP may use per minute on each API (spot 6000, sapi 12000,
+// USDT-M and coin-M futures 2400 each, BINANCE_WEIGHT_LIMIT_<SPOT|SAPI|FAPI|DAPI>) and answers every
+// request with the weight and order counts used so far (X-MBX-USED-WEIGHT-1M, X-SAPI-USED-IP-WEIGHT-1M,
+// X-MBX-ORDER-COUNT-10S). Once an API's minute is BINANCE_WEIGHT_PACE_AT (default 0.7) used,
+// non-critical calls (balances, positions, exchangeInfo, history) wait for the next minute so the rest
+// of the budget stays with execution: orders, borrows, transfers and the price lookups sizing them
+// never wait. After a 429 or 418 every call on that API fails fast until its Retry-After has passed,
+// since calls during a ban extend it. Order counts above the pace share of BINANCE_ORDER_LIMIT_10S_<API>
+// (spot 100, fapi 300, dapi 400) are logged.
+
+// weightAPI is one of Binance's separately limited APIs
+type weightAPI struct {
+	name         string
+	weightLimit  int
+	orderLimit   int    // Orders per 10 seconds
+	weightHeader string // Used weight of the current minute
+}
+
+var (
+	spotAPI = weightAPI{name: "spot", weightLimit: 6000, orderLimit: 100, weightHeader: "X-MBX-USED-WEIGHT-1M"}
+	sapiAPI = weightAPI{name: "sapi", weightLimit: 12000, weightHeader: "X-SAPI-USED-IP-WEIGHT-1M"}
+	fapiAPI = weightAPI{name: "fapi", weightLimit: 2400, orderLimit: 300, weightHeader: "X-MBX-USED-WEIGHT-1M"}
+	dapiAPI = weightAPI{name: "dapi", weightLimit: 2400, orderLimit: 400, weightHeader: "X-MBX-USED-WEIGHT-1M"}
+)
+
+// apiFor returns the API a request is counted against
+func apiFor(req *http.Request) weightAPI {
+	switch {
+	case strings.HasPrefix(req.URL.Path, "/sapi/"):
+		return sapiAPI
+	case strings.HasPrefix(req.URL.Path, "/fapi/"):
+		return fapiAPI
+	case strings.HasPrefix(req.URL.Path, "/dapi/"):
+		return dapiAPI
+	default:
+		return spotAPI
+	}
+}
+
+// weightUsage is what Binance last reported for one API
+type weightUsage struct {
+	minute      time.Time // Minute the used weight belongs to
+	used        int
+	warned      time.Time // 10 second order window last warned about
+	bannedUntil time.Time
+}
+
+// weightTracker keeps each API's reported usage
+type weightTracker struct {
+	mu    sync.Mutex
+	usage map[string]*weightUsage
+}
+
+// get returns the API's usage, creating it on first use
+// Callers hold mu
+func (t *weightTracker) get(api weightAPI) *weightUsage {
+	if t.usage == nil {
+		t.usage = make(map[string]*weightUsage)
+	}
+	u, ok := t.usage[api.name]
+	if !ok {
+		u = &weightUsage{}
+		t.usage[api.name] = u
+	}
+	return u
+}
+
+// weightLimit returns the API's per-minute weight limit
+func weightLimit(api weightAPI) int {
+	return config.GetInt(config.Key("BINANCE_WEIGHT_LIMIT", api.name), api.weightLimit)
+}
+
+// paceAt returns the used share of a limit above which non-critical calls wait
+func paceAt() float64 {
+	return config.GetFloat("BINANCE_WEIGHT_PACE_AT", 0.7)
+}
+
+// admit returns how long a call must wait before it is sent, or an error while the API is banned
+func (t *weightTracker) admit(api weightAPI, critical bool, now time.Time) (time.Duration, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	u := t.get(api)
+	if now.Before(u.bannedUntil) {
+		return 0, fmt.Errorf("binance %s API rate limited until %s", api.name, u.bannedUntil.Format(time.RFC3339))
+	}
+	if critical || !u.minute.Equal(now.Truncate(time.Minute)) {
+		return 0, nil
+	}
+	if float64(u.used) < paceAt()*float64(weightLimit(api)) {
+		return 0, nil
+	}
+	return u.minute.Add(time.Minute).Sub(now), nil
+}
+
+// record stores the usage reported by a response
+func (t *weightTracker) record(api weightAPI, resp *http.Response, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	u := t.get(api)
+	if used, err := strconv.Atoi(resp.Header.Get(api.weightHeader)); err == nil {
+		u.minute, u.used = now.Truncate(time.Minute), used
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusTeapot {
+		retryAfter := time.Minute
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			retryAfter = time.Duration(seconds) * time.Second
+		}
+		u.bannedUntil = now.Add(retryAfter)
+		logging.Errorf("binance", "[BINANCE] %s API returned %d at %d used weight - holding calls until %s",
+			api.name, resp.StatusCode, u.used, u.bannedUntil.Format(time.RFC3339))
+	}
+
+	orders, err := strconv.Atoi(resp.Header.Get("X-MBX-ORDER-COUNT-10S"))
+	if err != nil || api.orderLimit == 0 {
+		return
+	}
+	window := now.Truncate(10 * time.Second)
+	limit := config.GetInt(config.Key("BINANCE_ORDER_LIMIT_10S", api.name), api.orderLimit)
+	if float64(orders) >= paceAt()*float64(limit) && !u.warned.Equal(window) {
+		u.warned = window
+		logging.Warnf("binance", "[BINANCE] %s API at %d of %d orders in 10s", api.name, orders, limit)
+	}
+}
+
+// isCritical reports whether a call is part of execution: orders, borrows, transfers and the price
+// lookups sizing them
+func isCritical(req *http.Request) bool {
+	return req.Method != "GET" || strings.HasSuffix(req.URL.Path, "/ticker/price")
+}
+
+// pace holds a call within its API's weight budget: it fails while the API is banned and, once the
+// budget is nearly used, makes non-critical calls wait for the next minute
+func (b *BinanceClient) pace(req *http.Request) error {
+	api := apiFor(req)
+	wait, err := b.weights.admit(api, isCritical(req), time.Now())
+	if err != nil || wait <= 0 {
+		return err
+	}
+	logging.Debugf("binance", "[BINANCE] %s API weight nearly used - pacing %s %s for %s", api.name, req.Method, req.URL.Path, wait)
+	return sleepContext(req.Context(), wait)
+}
+
+// observe records the usage a response reports
+func (b *BinanceClient) observe(resp *http.Response) {
+	b.weights.record(apiFor(resp.Request), resp, time.Now())
+}
+
+// sleepContext waits d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
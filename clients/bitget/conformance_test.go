@@ -0,0 +1,21 @@
+package bitget
+
+import (
+	"testing"
+
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/clients/conformance"
+	"arbitrage.trade/clients/conformance/conformancetest"
+)
+
+// TestConformance runs the adapter conformance suite against testdata/conformance/bitget.json
+func TestConformance(t *testing.T) {
+	conformancetest.Run(t, conformance.Suite{
+		Exchange:   "bitget",
+		Pair:       fixturePair,
+		AmountUSDT: 20,
+		New: func() common.ExchangeTradeClient {
+			return NewBitgetClient(fixtureKey, fixtureSecret, fixturePassphrase)
+		},
+	})
+}
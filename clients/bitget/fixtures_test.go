@@ -0,0 +1,107 @@
+package bitget
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/clients/fixturetest"
+)
+
+const (
+	fixtureKey        = "fixture-key"
+	fixtureSecret     = "fixture-secret"
+	fixturePassphrase = "fixture-passphrase"
+	fixturePair       = "xrp-usdt"
+)
+
+// TestFixtureReplay replays an open/close cycle of both legs from testdata/bitget.json
+func TestFixtureReplay(t *testing.T) {
+	rec := fixturetest.Replay(t, "bitget", "testdata")
+	client := NewBitgetClient(fixtureKey, fixtureSecret, fixturePassphrase)
+	ctx := context.Background()
+
+	if holding, err := client.GetSpotHolding(ctx, fixturePair); err != nil || holding != 0 {
+		t.Errorf("GetSpotHolding = %v, %v, want 0", holding, err)
+	}
+	if balance, err := client.GetUSDTBalance(ctx, "spot"); err != nil || common.NotEqual(balance, 1000.5) {
+		t.Errorf("GetUSDTBalance spot = %v, %v, want 1000.5", balance, err)
+	}
+
+	spot, err := client.PutSpotLong(ctx, fixturePair, 20)
+	fixturetest.CheckResult(t, "PutSpotLong", spot, err, common.TradeResult{
+		OrderID: "1100000000000000001", ExecutedPrice: 0.55, ExecutedQty: 36.3, Success: true,
+	})
+	buy := jsonBody(t, rec.Find(t, "POST", "/api/v2/spot/trade/place-order"))
+	if buy["side"] != "buy" || buy["size"] != "20.0000" {
+		t.Errorf("spot buy order = %v, want buy for 20.0000 USDT", buy)
+	}
+
+	perp, err := client.PutFuturesShort(ctx, fixturePair, 20)
+	fixturetest.CheckResult(t, "PutFuturesShort", perp, err, common.TradeResult{
+		OrderID: "1200000000000000001", ExecutedPrice: 0.5498, ExecutedQty: 36.3, Success: true,
+	})
+	short := jsonBody(t, rec.Find(t, "POST", "/api/v2/mix/order/place-order"))
+	if short["size"] != "36.3" || short["tradeSide"] != "open" || short["holdSide"] != "short" {
+		t.Errorf("futures short order = %v, want open short 36.3", short)
+	}
+
+	if position, err := client.GetFuturesPosition(ctx, fixturePair); err != nil || common.NotEqual(position, -36.3) {
+		t.Errorf("GetFuturesPosition = %v, %v, want -36.3", position, err)
+	}
+
+	perpClose, _, err := client.CloseFuturesShort(ctx, fixturePair)
+	fixturetest.CheckResult(t, "CloseFuturesShort", perpClose, err, common.TradeResult{
+		OrderID: "1200000000000000002", ExecutedPrice: 0.5502, ExecutedQty: 36.3, Success: true,
+	})
+	if position, err := client.GetFuturesPosition(ctx, fixturePair); err != nil || position != 0 {
+		t.Errorf("GetFuturesPosition after close = %v, %v, want 0", position, err)
+	}
+
+	spotClose, _, err := client.CloseSpotLong(ctx, fixturePair, 20)
+	fixturetest.CheckResult(t, "CloseSpotLong", spotClose, err, common.TradeResult{
+		OrderID: "1100000000000000002", ExecutedPrice: 0.5505, ExecutedQty: 36.2, Success: true,
+	})
+
+	checkSignatures(t, rec)
+	fixturetest.CheckReplayed(t, "bitget")
+}
+
+func jsonBody(t *testing.T, req fixturetest.Request) map[string]interface{} {
+	t.Helper()
+	var body map[string]interface{}
+	if err := json.Unmarshal([]byte(req.Body), &body); err != nil {
+		t.Fatalf("%s %s body %q: %v", req.Method, req.URL.Path, req.Body, err)
+	}
+	return body
+}
+
+// checkSignatures verifies every signed request carries the key, passphrase and a base64
+// HMAC-SHA256 of timestamp + method + path with query + body
+func checkSignatures(t *testing.T, rec *fixturetest.Recorder) {
+	t.Helper()
+	signed := 0
+	for _, req := range rec.Requests() {
+		signature := req.Header.Get("ACCESS-SIGN")
+		if signature == "" {
+			continue
+		}
+		signed++
+
+		mac := hmac.New(sha256.New, []byte(fixtureSecret))
+		mac.Write([]byte(req.Header.Get("ACCESS-TIMESTAMP") + req.Method + req.URL.RequestURI() + req.Body))
+		if want := base64.StdEncoding.EncodeToString(mac.Sum(nil)); signature != want {
+			t.Errorf("%s %s signature = %s, want %s", req.Method, req.URL.Path, signature, want)
+		}
+		if req.Header.Get("ACCESS-KEY") != fixtureKey || req.Header.Get("ACCESS-PASSPHRASE") != fixturePassphrase {
+			t.Errorf("%s %s sent without the API key and passphrase", req.Method, req.URL.Path)
+		}
+	}
+	if signed == 0 {
+		t.Error("no signed request was sent")
+	}
+}
@@ -0,0 +1,40 @@
+package bitget
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"arbitrage.trade/clients/common"
+)
+
+// GetFundingRate returns the USDT-margined perp's current funding rate
+func (b *BitgetClient) GetFundingRate(ctx context.Context, pairName string) (*common.FundingRate, error) {
+	url := fmt.Sprintf("%s/api/v2/mix/market/current-fund-rate?symbol=%s&productType=USDT-FUTURES", b.baseURL, b.normalizeSymbol(pairName))
+
+	resp, err := b.rest.Get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	var r struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data []struct {
+			FundingRate string `json:"fundingRate"`
+			NextUpdate  string `json:"nextUpdate"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(resp.Body, &r); err != nil {
+		return nil, err
+	}
+	if r.Code != "00000" || len(r.Data) == 0 {
+		return nil, fmt.Errorf("bitget error code: %s, msg: %s", r.Code, r.Msg)
+	}
+
+	rate, _ := strconv.ParseFloat(r.Data[0].FundingRate, 64)
+	nextMs, _ := strconv.ParseInt(r.Data[0].NextUpdate, 10, 64)
+	return &common.FundingRate{Rate: rate, FundingTime: time.UnixMilli(nextMs)}, nil
+}
@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
-	"net/http"
 	"strconv"
 	"time"
 
@@ -15,11 +14,10 @@ import (
 func (b *BitgetClient) getFuturesTicker(symbol string) (float64, error) {
 	url := fmt.Sprintf("%s/api/v2/mix/market/ticker?symbol=%s&productType=USDT-FUTURES", b.baseURL, symbol)
 
-	resp, err := http.Get(url)
+	resp, err := b.rest.Get(context.Background(), url)
 	if err != nil {
 		return 0, err
 	}
-	defer resp.Body.Close()
 
 	var r struct {
 		Code string `json:"code"`
@@ -27,7 +25,7 @@ func (b *BitgetClient) getFuturesTicker(symbol string) (float64, error) {
 			LastPr string `json:"lastPr"`
 		} `json:"data"`
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+	if err := json.Unmarshal(resp.Body, &r); err != nil {
 		return 0, err
 	}
 	p, _ := strconv.ParseFloat(r.Data[0].LastPr, 64)
@@ -218,6 +216,18 @@ func (b *BitgetClient) getFuturesPositionInfo(ctx context.Context, symbol string
 	return info, nil
 }
 
+// GetFuturesPosition returns the signed short position size for the pair
+func (b *BitgetClient) GetFuturesPosition(ctx context.Context, pairName string) (float64, error) {
+	posInfo, err := b.getFuturesPositionInfo(ctx, b.normalizeSymbol(pairName), "short")
+	if err != nil {
+		return 0, err
+	}
+	if common.IsNegative(posInfo.Total) {
+		return posInfo.Total, nil
+	}
+	return -posInfo.Total, nil
+}
+
 func (b *BitgetClient) CloseFuturesShort(ctx context.Context, pairName string) (*common.TradeResult, float64, error) {
 	symbol := b.normalizeSymbol(pairName)
 
@@ -274,6 +284,12 @@ func (b *BitgetClient) CloseFuturesShort(ctx context.Context, pairName string) (
 	delete(b.positions, pairName+"_futures")
 	b.mu.Unlock()
 
+	// The order response carries no fill price, so the close is priced at the ticker like the open
+	price, err := b.getFuturesTicker(symbol)
+	if err != nil {
+		log.Printf("[BITGET] CloseFuturesShort - ticker error: %v", err)
+	}
+
 	newBalance, err := b.getFuturesBalance(ctx)
 	if err != nil {
 		log.Printf("[BITGET] CloseFuturesShort - ERROR: Failed to get USDT balance: %v", err)
@@ -285,8 +301,9 @@ func (b *BitgetClient) CloseFuturesShort(ctx context.Context, pairName string) (
 	common.SetBalance(b.GetName(), "futures", "USDT", newBalance)
 
 	return &common.TradeResult{
-		OrderID:     resp.Data.OrderID,
-		ExecutedQty: closeQty,
-		Success:     true,
+		OrderID:       resp.Data.OrderID,
+		ExecutedPrice: price,
+		ExecutedQty:   closeQty,
+		Success:       true,
 	}, newBalance - prevBalance, nil
 }
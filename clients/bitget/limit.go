@@ -0,0 +1,179 @@
+package bitget
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+
+	"arbitrage.trade/clients/common"
+)
+
+// Resting (maker) limit orders
+//
+// Maker legs rest a post_only order, which Bitget cancels instead of letting it take liquidity. On
+// USDT-FUTURES the order opens or closes the short position (side sell, tradeSide open or close, as
+// the market legs do); spot and futures sizes are both in base units. Bitget reports fees as negative
+// amounts, so a maker rebate arrives as a positive one and is reported as a negative fee.
+
+// PlaceLimitOrder rests a post-only limit order for a base quantity and returns its order ID
+func (b *BitgetClient) PlaceLimitOrder(ctx context.Context, pairName string, market string, side string, price float64, quantity float64) (string, error) {
+	quantity = common.RoundQuantity(quantity, pairName)
+	if common.IsNegativeOrZero(quantity) {
+		return "", fmt.Errorf("invalid limit quantity: %.8f", quantity)
+	}
+
+	path := "/api/v2/spot/trade/place-order"
+	body := map[string]interface{}{
+		"symbol":    b.normalizeSymbol(pairName),
+		"side":      side,
+		"orderType": "limit",
+		"force":     "post_only",
+		"price":     common.FormatPrice(price, pairName),
+		"size":      common.FormatQuantity(quantity, pairName),
+		"clientOid": common.NewClientOrderID(),
+	}
+	if market == "futures" {
+		path = "/api/v2/mix/order/place-order"
+		tradeSide := "open"
+		if side == "buy" {
+			tradeSide = "close"
+		}
+		body["productType"] = "USDT-FUTURES"
+		body["marginMode"] = "crossed"
+		body["marginCoin"] = "USDT"
+		body["side"] = "sell"
+		body["tradeSide"] = tradeSide
+		body["holdSide"] = "short"
+	}
+
+	var resp struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data struct {
+			OrderID string `json:"orderId"`
+		} `json:"data"`
+	}
+	if err := b.signedRequest(ctx, "POST", path, body, &resp); err != nil {
+		return "", err
+	}
+	if resp.Code != "00000" {
+		return "", fmt.Errorf("bitget error: %s - %s", resp.Code, resp.Msg)
+	}
+	return resp.Data.OrderID, nil
+}
+
+// limitOrderState is the subset of a spot orderInfo or a futures order detail the fill is read from
+type limitOrderState struct {
+	Status    string `json:"status"` // Spot
+	State     string `json:"state"`  // Futures
+	BaseVol   string `json:"baseVolume"`
+	PriceAvg  string `json:"priceAvg"`
+	Fee       string `json:"fee"`       // Futures, in USDT
+	FeeDetail string `json:"feeDetail"` // Spot, JSON with the total fee in newFees.t
+}
+
+// GetLimitOrder returns a limit order's fill and its fee in USDT
+func (b *BitgetClient) GetLimitOrder(ctx context.Context, pairName string, market string, orderID string) (*common.TradeResult, bool, error) {
+	var order limitOrderState
+	if market == "futures" {
+		var r struct {
+			Code string          `json:"code"`
+			Msg  string          `json:"msg"`
+			Data limitOrderState `json:"data"`
+		}
+		query := map[string]interface{}{"symbol": b.normalizeSymbol(pairName), "productType": "USDT-FUTURES", "orderId": orderID}
+		if err := b.signedRequest(ctx, "GET", "/api/v2/mix/order/detail", query, &r); err != nil {
+			return nil, false, err
+		}
+		if r.Code != "00000" {
+			return nil, false, fmt.Errorf("bitget error: %s - %s", r.Code, r.Msg)
+		}
+		order = r.Data
+		order.Status = order.State
+	} else {
+		var r struct {
+			Code string            `json:"code"`
+			Msg  string            `json:"msg"`
+			Data []limitOrderState `json:"data"`
+		}
+		query := map[string]interface{}{"orderId": orderID}
+		if err := b.signedRequest(ctx, "GET", "/api/v2/spot/trade/orderInfo", query, &r); err != nil {
+			return nil, false, err
+		}
+		if r.Code != "00000" || len(r.Data) == 0 {
+			return nil, false, fmt.Errorf("bitget error: %s - %s", r.Code, r.Msg)
+		}
+		order = r.Data[0]
+	}
+
+	filled, _ := strconv.ParseFloat(order.BaseVol, 64)
+	avgPrice, _ := strconv.ParseFloat(order.PriceAvg, 64)
+	fee := -limitOrderFee(order)
+	if market == "spot" && math.Abs(fee) > 0 && order.Fee == "" {
+		// Spot buys are charged in the base asset
+		fee = b.spotFeeUSDT(fee, filled, avgPrice)
+	}
+
+	done := order.Status == "filled" || order.Status == "cancelled" || order.Status == "canceled"
+	return &common.TradeResult{
+		OrderID:       orderID,
+		ExecutedPrice: avgPrice,
+		ExecutedQty:   filled,
+		Fee:           fee,
+		Success:       order.Status == "filled",
+	}, done, nil
+}
+
+// limitOrderFee returns an order's signed fee as Bitget reports it, negative when charged
+func limitOrderFee(order limitOrderState) float64 {
+	if order.Fee != "" {
+		fee, _ := strconv.ParseFloat(order.Fee, 64)
+		return fee
+	}
+	var detail struct {
+		NewFees struct {
+			T float64 `json:"t"`
+		} `json:"newFees"`
+	}
+	if json.Unmarshal([]byte(order.FeeDetail), &detail) != nil {
+		return 0
+	}
+	return detail.NewFees.T
+}
+
+// spotFeeUSDT converts a spot fee charged in the base asset to USDT at the fill price
+// A fee larger than the fill's notional is taken to be in USDT already
+func (b *BitgetClient) spotFeeUSDT(fee, filled, avgPrice float64) float64 {
+	if math.Abs(fee) < filled {
+		return fee * avgPrice
+	}
+	return fee
+}
+
+// CancelLimitOrder cancels a resting limit order; one that finished in the meantime is not an error
+func (b *BitgetClient) CancelLimitOrder(ctx context.Context, pairName string, market string, orderID string) error {
+	path := "/api/v2/spot/trade/cancel-order"
+	body := map[string]interface{}{"symbol": b.normalizeSymbol(pairName), "orderId": orderID}
+	if market == "futures" {
+		path = "/api/v2/mix/order/cancel-order"
+		body["productType"] = "USDT-FUTURES"
+	}
+
+	var resp struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+	}
+	err := b.signedRequest(ctx, "POST", path, body, &resp)
+	if err == nil && resp.Code == "00000" {
+		return nil
+	}
+	if _, done, getErr := b.GetLimitOrder(ctx, pairName, market, orderID); getErr == nil && done {
+		return nil
+	}
+	if err == nil {
+		err = fmt.Errorf("bitget error: %s - %s", resp.Code, resp.Msg)
+	}
+	return fmt.Errorf("failed to cancel order %s: %w", orderID, err)
+}
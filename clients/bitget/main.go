@@ -1,8 +1,8 @@
 package bitget
 
 import (
-	"net/http"
-	"time"
+	"context"
+	"fmt"
 
 	"arbitrage.trade/clients/common"
 )
@@ -13,9 +13,26 @@ func NewBitgetClient(apiKey, apiSecret, passphrase string) *BitgetClient {
 		apiSecret:  apiSecret,
 		passphrase: passphrase,
 		baseURL:    "https://api.bitget.com",
-		httpClient: &http.Client{Timeout: 30 * time.Second},
+		rest:       common.NewRESTClient("bitget"),
 		positions:  make(map[string]*common.Position),
 	}
 }
 
 func (b *BitgetClient) GetName() string { return "bitget" }
+
+// Prewarm opens a pooled connection to the API
+func (b *BitgetClient) Prewarm(ctx context.Context) {
+	common.PrewarmConnections(ctx, b.rest.HTTP, b.baseURL+"/api/v2/public/time")
+}
+
+// GetUSDTBalance returns the available USDT on the given market ("spot" or "futures")
+func (b *BitgetClient) GetUSDTBalance(ctx context.Context, market string) (float64, error) {
+	switch market {
+	case "spot":
+		return b.getSpotAssetBalance(ctx, "USDT")
+	case "futures":
+		return b.getFuturesBalance(ctx)
+	default:
+		return 0, fmt.Errorf("no USDT balance for market %s", market)
+	}
+}
@@ -0,0 +1,42 @@
+package bitget
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"arbitrage.trade/clients/common"
+)
+
+// GetAPIPermissions returns the key's authorities and IP binding from the spot account info
+// Keys report either the combined "trade"/"readonly" authorities or per-product ones
+// ("stow" spot trade write, "coow" contract order write, "wtxw" withdraw)
+func (b *BitgetClient) GetAPIPermissions(ctx context.Context) (*common.APIPermissions, error) {
+	var response struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data struct {
+			IPs         string   `json:"ips"`
+			Authorities []string `json:"authorities"`
+		} `json:"data"`
+	}
+	if err := b.signedRequest(ctx, "GET", "/api/v2/spot/account/info", nil, &response); err != nil {
+		return nil, err
+	}
+	if response.Code != "00000" {
+		return nil, fmt.Errorf("bitget account info error: %s", response.Msg)
+	}
+
+	authorities := make(map[string]bool)
+	for _, a := range response.Data.Authorities {
+		authorities[strings.ToLower(a)] = true
+	}
+
+	return &common.APIPermissions{
+		Read:         true, // The request above only succeeds with read access
+		SpotTrade:    authorities["trade"] || authorities["stow"],
+		FuturesTrade: authorities["trade"] || authorities["coow"],
+		Withdraw:     authorities["withdraw"] || authorities["wtxw"],
+		IPRestricted: strings.TrimSpace(response.Data.IPs) != "",
+	}, nil
+}
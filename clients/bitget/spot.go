@@ -33,6 +33,12 @@ func (b *BitgetClient) getSpotAssetBalance(ctx context.Context, asset string) (f
 	return 0, nil
 }
 
+// GetSpotHolding returns the available base-asset balance for the pair
+func (b *BitgetClient) GetSpotHolding(ctx context.Context, pairName string) (float64, error) {
+	asset := strings.TrimSuffix(b.normalizeSymbol(pairName), "USDT")
+	return b.getSpotAssetBalance(ctx, asset)
+}
+
 func (b *BitgetClient) PutSpotLong(ctx context.Context, pairName string, amountUSDT float64) (*common.TradeResult, error) {
 	symbol := b.normalizeSymbol(pairName)
 
@@ -165,6 +171,12 @@ func (b *BitgetClient) CloseSpotLong(ctx context.Context, pairName string, amoun
 	delete(b.positions, pairName+"_spot")
 	b.mu.Unlock()
 
+	// The order response carries no fill price, so the close is priced at the ticker like the open
+	price, err := b.getSpotTicker(ctx, symbol)
+	if err != nil {
+		log.Printf("[BITGET] CloseSpotLong - ticker error: %v", err)
+	}
+
 	newBalance, err := b.getSpotAssetBalance(ctx, "USDT")
 	if err != nil {
 		log.Printf("[BITGET] CloseSpotLong - ERROR: Failed to get USDT balance: %v", err)
@@ -176,8 +188,9 @@ func (b *BitgetClient) CloseSpotLong(ctx context.Context, pairName string, amoun
 	common.SetBalance(b.GetName(), "spot", "USDT", newBalance)
 
 	return &common.TradeResult{
-		OrderID:     resp.Data.OrderID,
-		ExecutedQty: qty,
-		Success:     true,
+		OrderID:       resp.Data.OrderID,
+		ExecutedPrice: price,
+		ExecutedQty:   qty,
+		Success:       true,
 	}, newBalance - prevBalance, nil
 }
@@ -1,7 +1,6 @@
 package bitget
 
 import (
-	"net/http"
 	"sync"
 
 	"arbitrage.trade/clients/common"
@@ -12,7 +11,7 @@ type BitgetClient struct {
 	apiSecret  string
 	passphrase string
 	baseURL    string
-	httpClient *http.Client
+	rest       *common.RESTClient
 	positions  map[string]*common.Position
 	mu         sync.RWMutex
 }
@@ -7,7 +7,6 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	"io"
 	"log"
 	"net/http"
 	"strconv"
@@ -18,11 +17,10 @@ import (
 func (b *BitgetClient) getSpotTicker(ctx context.Context, symbol string) (float64, error) {
 	url := fmt.Sprintf("%s/api/v2/spot/market/tickers?symbol=%s", b.baseURL, symbol)
 
-	resp, err := http.Get(url)
+	resp, err := b.rest.Get(ctx, url)
 	if err != nil {
 		return 0, err
 	}
-	defer resp.Body.Close()
 
 	var r struct {
 		Code string `json:"code"`
@@ -30,7 +28,7 @@ func (b *BitgetClient) getSpotTicker(ctx context.Context, symbol string) (float6
 			LastPr string `json:"lastPr"`
 		} `json:"data"`
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+	if err := json.Unmarshal(resp.Body, &r); err != nil {
 		return 0, err
 	}
 	if len(r.Data) == 0 {
@@ -47,8 +45,6 @@ func (b *BitgetClient) normalizeSymbol(pairName string) string {
 }
 
 func (b *BitgetClient) signedRequest(ctx context.Context, method, path string, body interface{}, out interface{}) error {
-	timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
-
 	var bodyStr string
 	if body != nil {
 		if method == "GET" {
@@ -70,50 +66,43 @@ func (b *BitgetClient) signedRequest(ctx context.Context, method, path string, b
 		}
 	}
 
-	// Bitget signature format: timestamp + method + path + body
-	preHash := timestamp + method + path + bodyStr
+	resp, err := b.rest.Do(ctx, func(ctx context.Context) (*http.Request, error) {
+		timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
 
-	mac := hmac.New(sha256.New, []byte(b.apiSecret))
-	mac.Write([]byte(preHash))
-	signature := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+		// Bitget signature format: timestamp + method + path + body
+		preHash := timestamp + method + path + bodyStr
 
-	url := b.baseURL + path
-	var req *http.Request
-	var err error
+		mac := hmac.New(sha256.New, []byte(b.apiSecret))
+		mac.Write([]byte(preHash))
+		signature := base64.StdEncoding.EncodeToString(mac.Sum(nil))
 
-	if method == "GET" || bodyStr == "" {
-		req, err = http.NewRequestWithContext(ctx, method, url, nil)
-	} else {
-		req, err = http.NewRequestWithContext(ctx, method, url, strings.NewReader(bodyStr))
-	}
+		url := b.baseURL + path
+		var req *http.Request
+		var err error
 
-	if err != nil {
-		return err
-	}
+		if method == "GET" || bodyStr == "" {
+			req, err = http.NewRequestWithContext(ctx, method, url, nil)
+		} else {
+			req, err = http.NewRequestWithContext(ctx, method, url, strings.NewReader(bodyStr))
+		}
 
-	// Bitget v2 API headers
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("ACCESS-KEY", b.apiKey)
-	req.Header.Set("ACCESS-SIGN", signature)
-	req.Header.Set("ACCESS-TIMESTAMP", timestamp)
-	req.Header.Set("ACCESS-PASSPHRASE", b.passphrase)
-	req.Header.Set("locale", "en-US")
+		if err != nil {
+			return nil, err
+		}
 
-	resp, err := b.httpClient.Do(req)
+		// Bitget v2 API headers
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("ACCESS-KEY", b.apiKey)
+		req.Header.Set("ACCESS-SIGN", signature)
+		req.Header.Set("ACCESS-TIMESTAMP", timestamp)
+		req.Header.Set("ACCESS-PASSPHRASE", b.passphrase)
+		req.Header.Set("locale", "en-US")
+		return req, nil
+	})
 	if err != nil {
 		log.Printf("[BITGET] signedRequest - HTTP error: %v", err)
 		return err
 	}
-	defer resp.Body.Close()
-
-	respBody, _ := io.ReadAll(resp.Body)
-
-	if resp.StatusCode >= 400 {
-		return fmt.Errorf("bitget api error: status %d, body: %s", resp.StatusCode, string(respBody))
-	}
 
-	if out != nil {
-		return json.Unmarshal(respBody, out)
-	}
-	return nil
+	return resp.DecodeJSON(out)
 }
@@ -0,0 +1,58 @@
+package bitget
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"arbitrage.trade/clients/common"
+)
+
+// GetAssetStatus returns the deposit/withdrawal state of a coin across its chains
+func (b *BitgetClient) GetAssetStatus(ctx context.Context, asset string) (*common.AssetStatus, error) {
+	url := fmt.Sprintf("%s/api/v2/spot/public/coins?coin=%s", b.baseURL, asset)
+
+	resp, err := b.rest.Get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	var r struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data []struct {
+			Coin   string `json:"coin"`
+			Chains []struct {
+				Chain             string `json:"chain"`
+				Withdrawable      string `json:"withdrawable"`
+				Rechargeable      string `json:"rechargeable"`
+				WithdrawFee       string `json:"withdrawFee"`
+				MinWithdrawAmount string `json:"minWithdrawAmount"`
+			} `json:"chains"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(resp.Body, &r); err != nil {
+		return nil, err
+	}
+	if r.Code != "00000" {
+		return nil, fmt.Errorf("bitget error code: %s, msg: %s", r.Code, r.Msg)
+	}
+	if len(r.Data) == 0 {
+		return nil, fmt.Errorf("asset %s not listed", asset)
+	}
+
+	networks := make([]common.NetworkStatus, 0, len(r.Data[0].Chains))
+	for _, c := range r.Data[0].Chains {
+		fee, _ := strconv.ParseFloat(c.WithdrawFee, 64)
+		minWd, _ := strconv.ParseFloat(c.MinWithdrawAmount, 64)
+		networks = append(networks, common.NetworkStatus{
+			Network:     c.Chain,
+			CanDeposit:  c.Rechargeable == "true",
+			CanWithdraw: c.Withdrawable == "true",
+			WithdrawFee: fee,
+			WithdrawMin: minWd,
+		})
+	}
+	return common.NewAssetStatus(asset, networks), nil
+}
@@ -0,0 +1,34 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/risk"
+)
+
+// LockCapital locks amountUSDT of a venue market's free USDT for a pair before its legs are sent
+func LockCapital(ctx context.Context, exchange common.ExchangeType, market, pairName string, amountUSDT float64) error {
+	client, err := getOrCreateClient(exchange)
+	if err != nil {
+		return err
+	}
+
+	balanceClient, ok := client.(common.BalanceClient)
+	if !ok {
+		return fmt.Errorf("%s does not report USDT balances", exchange)
+	}
+
+	free, err := balanceClient.GetUSDTBalance(ctx, market)
+	if err != nil {
+		return fmt.Errorf("failed to get %s %s USDT balance: %w", exchange, market, err)
+	}
+
+	return risk.LockCapital(string(exchange), market, pairName, amountUSDT, free)
+}
+
+// UnlockCapital releases a pair's locked capital on a venue market
+func UnlockCapital(exchange common.ExchangeType, market, pairName string) {
+	risk.UnlockCapital(string(exchange), market, pairName)
+}
@@ -0,0 +1,126 @@
+package common
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"arbitrage.trade/config"
+)
+
+// Balance snapshots
+//
+// Venue clients read a market's balances as one bulk snapshot of every asset, kept for
+// BALANCE_CACHE_TTL (default 2s, 0 disables) so the several balance checks of one trade share a
+// single signed call. Concurrent readers wait for the same fetch instead of each sending one.
+// Any order, borrow or transfer a client sends invalidates its exchange's snapshots, so balances
+// read after a fill are always fresh. When a fetch fails, e.g. on a rate limit, a snapshot no
+// older than BALANCE_CACHE_MAX_STALE (default 30s) that no order has invalidated is served instead.
+
+// BalanceSnapshot is the free balance per asset of one venue market
+type BalanceSnapshot map[string]float64
+
+type balanceEntry struct {
+	mu        sync.Mutex
+	assets    BalanceSnapshot
+	fetchedAt time.Time
+	valid     bool // False once an order may have changed the balances
+}
+
+var (
+	balanceCache   = make(map[string]*balanceEntry) // exchange:market -> snapshot
+	balanceCacheMu sync.Mutex
+)
+
+func balanceCacheEntry(exchange, market string) *balanceEntry {
+	balanceCacheMu.Lock()
+	defer balanceCacheMu.Unlock()
+
+	key := exchange + ":" + market
+	entry, ok := balanceCache[key]
+	if !ok {
+		entry = &balanceEntry{}
+		balanceCache[key] = entry
+	}
+	return entry
+}
+
+// CachedBalances returns the market's balance snapshot, calling fetch when it is missing, expired or invalidated
+func CachedBalances(ctx context.Context, exchange, market string, fetch func(ctx context.Context) (BalanceSnapshot, error)) (BalanceSnapshot, error) {
+	ttl := config.GetDuration("BALANCE_CACHE_TTL", 2*time.Second)
+	if ttl <= 0 {
+		return fetch(ctx)
+	}
+
+	entry := balanceCacheEntry(exchange, market)
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	if entry.valid && time.Since(entry.fetchedAt) < ttl {
+		return entry.assets, nil
+	}
+
+	assets, err := fetch(ctx)
+	if err != nil {
+		maxStale := config.GetDuration("BALANCE_CACHE_MAX_STALE", 30*time.Second)
+		if entry.valid && time.Since(entry.fetchedAt) < maxStale {
+			log.Printf("[BALANCES] %s %s refresh failed, using %s old snapshot: %v",
+				exchange, market, time.Since(entry.fetchedAt).Round(time.Millisecond), err)
+			return entry.assets, nil
+		}
+		return nil, err
+	}
+
+	entry.assets, entry.fetchedAt, entry.valid = assets, time.Now(), true
+	return assets, nil
+}
+
+// InvalidateBalances drops every cached snapshot of the exchange, called after anything that moves its balances
+func InvalidateBalances(exchange string) {
+	balanceCacheMu.Lock()
+	entries := make([]*balanceEntry, 0, 2)
+	for key, entry := range balanceCache {
+		if len(key) > len(exchange) && key[:len(exchange)+1] == exchange+":" {
+			entries = append(entries, entry)
+		}
+	}
+	balanceCacheMu.Unlock()
+
+	for _, entry := range entries {
+		entry.mu.Lock()
+		entry.valid = false
+		entry.mu.Unlock()
+	}
+}
+
+// CachedBalanceSnapshot is a market's cached balances as served for diagnostics
+type CachedBalanceSnapshot struct {
+	Assets    BalanceSnapshot `json:"assets"`
+	FetchedAt time.Time       `json:"fetched_at"`
+	Valid     bool            `json:"valid"`
+}
+
+// BalanceSnapshots returns a copy of every cached balance snapshot keyed by exchange:market
+func BalanceSnapshots() map[string]CachedBalanceSnapshot {
+	balanceCacheMu.Lock()
+	entries := make(map[string]*balanceEntry, len(balanceCache))
+	for key, entry := range balanceCache {
+		entries[key] = entry
+	}
+	balanceCacheMu.Unlock()
+
+	result := make(map[string]CachedBalanceSnapshot, len(entries))
+	for key, entry := range entries {
+		entry.mu.Lock()
+		assets := make(BalanceSnapshot, len(entry.assets))
+		for asset, amount := range entry.assets {
+			if amount != 0 {
+				assets[asset] = amount
+			}
+		}
+		result[key] = CachedBalanceSnapshot{Assets: assets, FetchedAt: entry.fetchedAt, Valid: entry.valid}
+		entry.mu.Unlock()
+	}
+	return result
+}
@@ -0,0 +1,331 @@
+package common
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Decimal is a scale-aware fixed-point number: an int64 coefficient and its count of fractional digits
+// Prices, quantities and USDT amounts are rounded and compared as Decimals so that values like 0.29
+// floor to 0.29 rather than 0.28, and balance deltas carry no float noise. A Decimal keeps as many
+// fractional digits as its value has, up to MaxDecimalPlaces, so sub-cent prices (1000PEPE, WOJAK)
+// are not quantized. Decimals are kept in canonical form, without trailing fractional zeros, so
+// equal values compare equal with ==.
+// float64 stays the type at API, storage and exchange-response boundaries, and for ratios such as
+// spread percentages.
+type Decimal struct {
+	coef  int64
+	scale int
+}
+
+// MaxDecimalPlaces is the most fractional digits a Decimal holds; digits past it, or past what the
+// coefficient can hold next to the integer part, are rounded half away from zero
+const MaxDecimalPlaces = 18
+
+var pow10 = func() (p [MaxDecimalPlaces + 1]int64) {
+	p[0] = 1
+	for i := 1; i <= MaxDecimalPlaces; i++ {
+		p[i] = p[i-1] * 10
+	}
+	return p
+}()
+
+var maxDecimal = Decimal{coef: math.MaxInt64}
+
+// NewDecimal converts a float using its shortest decimal representation
+// NaN converts to 0 and values beyond the Decimal range saturate
+func NewDecimal(f float64) Decimal {
+	if math.IsNaN(f) {
+		return Decimal{}
+	}
+	if f >= math.MaxInt64 {
+		return maxDecimal
+	}
+	if f <= -math.MaxInt64 {
+		return maxDecimal.Neg()
+	}
+	d, _ := ParseDecimal(strconv.FormatFloat(f, 'f', -1, 64))
+	return d
+}
+
+// ParseDecimal parses a plain decimal string such as "-12.345"
+// Fractional digits that do not fit are rounded half away from zero; an integer part beyond the
+// Decimal range is an error
+func ParseDecimal(s string) (Decimal, error) {
+	v := strings.TrimSpace(s)
+	negative := strings.HasPrefix(v, "-")
+	v = strings.TrimPrefix(strings.TrimPrefix(v, "-"), "+")
+
+	intPart, fracPart, _ := strings.Cut(v, ".")
+	if intPart == "" && fracPart == "" {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	digits := intPart + fracPart
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+		}
+	}
+	if negative {
+		digits = "-" + digits
+	}
+
+	// Common case: every digit fits the coefficient
+	if len(intPart)+len(fracPart) <= MaxDecimalPlaces && len(fracPart) <= MaxDecimalPlaces {
+		coef, err := strconv.ParseInt(digits, 10, 64)
+		if err != nil {
+			return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+		}
+		return newDecimal(coef, len(fracPart)), nil
+	}
+
+	coef, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	d, ok := fromBig(coef, len(fracPart))
+	if !ok {
+		return Decimal{}, fmt.Errorf("decimal %q overflows the Decimal range", s)
+	}
+	return d, nil
+}
+
+// newDecimal returns coef×10^-scale in canonical form
+func newDecimal(coef int64, scale int) Decimal {
+	for scale > 0 && coef%10 == 0 {
+		coef /= 10
+		scale--
+	}
+	if coef == 0 {
+		scale = 0
+	}
+	return Decimal{coef: coef, scale: scale}
+}
+
+// fromBig returns v×10^-scale with the most fractional digits that fit, rounded half away from zero
+// ok is false when not even the integer part fits
+func fromBig(v *big.Int, scale int) (Decimal, bool) {
+	for drop := max(0, scale-MaxDecimalPlaces); drop <= scale; drop++ {
+		// Each attempt rounds from v itself so digits are never rounded twice
+		if rounded := divRound(v, bigPow10(drop)); rounded.IsInt64() {
+			return newDecimal(rounded.Int64(), scale-drop), true
+		}
+	}
+	return Decimal{}, false
+}
+
+// saturate returns fromBig's result, or the Decimal range's limit of v's sign on overflow
+func saturate(v *big.Int, scale int) Decimal {
+	if d, ok := fromBig(v, scale); ok {
+		return d
+	}
+	if v.Sign() < 0 {
+		return maxDecimal.Neg()
+	}
+	return maxDecimal
+}
+
+// divRound returns a/b rounded half away from zero
+func divRound(a, b *big.Int) *big.Int {
+	q, r := new(big.Int).QuoRem(a, b, new(big.Int))
+	if r.Sign() == 0 {
+		return q
+	}
+	r.Abs(r).Lsh(r, 1)
+	if r.CmpAbs(b) >= 0 {
+		if (a.Sign() < 0) != (b.Sign() < 0) {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return q
+}
+
+func bigPow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// scaled returns the decimal's coefficient brought to the given scale, at least its own
+func (d Decimal) scaled(scale int) *big.Int {
+	v := big.NewInt(d.coef)
+	if scale > d.scale {
+		v.Mul(v, bigPow10(scale-d.scale))
+	}
+	return v
+}
+
+// Float64 returns the nearest float to the decimal
+func (d Decimal) Float64() float64 {
+	if d.scale == 0 {
+		return float64(d.coef)
+	}
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+func (d Decimal) Add(o Decimal) Decimal {
+	scale := max(d.scale, o.scale)
+	return saturate(new(big.Int).Add(d.scaled(scale), o.scaled(scale)), scale)
+}
+
+func (d Decimal) Sub(o Decimal) Decimal {
+	scale := max(d.scale, o.scale)
+	return saturate(new(big.Int).Sub(d.scaled(scale), o.scaled(scale)), scale)
+}
+
+// Mul returns d*o, rounded half away from zero past what fits
+func (d Decimal) Mul(o Decimal) Decimal {
+	return saturate(new(big.Int).Mul(big.NewInt(d.coef), big.NewInt(o.coef)), d.scale+o.scale)
+}
+
+// Div returns d/o rounded half away from zero to MaxDecimalPlaces, or 0 when o is zero
+func (d Decimal) Div(o Decimal) Decimal {
+	if o.coef == 0 {
+		return Decimal{}
+	}
+	num := d.scaled(d.scale + o.scale + MaxDecimalPlaces)
+	den := new(big.Int).Mul(big.NewInt(o.coef), bigPow10(d.scale))
+	return saturate(divRound(num, den), MaxDecimalPlaces)
+}
+
+// Rem returns the remainder of d divided by o, with the sign of d, or 0 when o is zero
+func (d Decimal) Rem(o Decimal) Decimal {
+	if o.coef == 0 {
+		return Decimal{}
+	}
+	scale := max(d.scale, o.scale)
+	return saturate(new(big.Int).Rem(d.scaled(scale), o.scaled(scale)), scale)
+}
+
+func (d Decimal) Neg() Decimal {
+	if d.coef == math.MinInt64 {
+		return maxDecimal
+	}
+	return Decimal{coef: -d.coef, scale: d.scale}
+}
+
+func (d Decimal) Abs() Decimal {
+	if d.coef < 0 {
+		return d.Neg()
+	}
+	return d
+}
+
+func (d Decimal) IsZero() bool { return d.coef == 0 }
+
+// Cmp returns -1, 0 or 1 as d is less than, equal to or greater than o
+func (d Decimal) Cmp(o Decimal) int {
+	if d.scale == o.scale {
+		switch {
+		case d.coef < o.coef:
+			return -1
+		case d.coef > o.coef:
+			return 1
+		}
+		return 0
+	}
+	scale := max(d.scale, o.scale)
+	return d.scaled(scale).Cmp(o.scaled(scale))
+}
+
+// Truncate drops digits past the given number of places, rounding toward zero
+func (d Decimal) Truncate(places int) Decimal {
+	places = clampPlaces(places)
+	if d.scale <= places {
+		return d
+	}
+	return newDecimal(d.coef/pow10[d.scale-places], places)
+}
+
+// Round rounds half away from zero to the given number of places
+func (d Decimal) Round(places int) Decimal {
+	places = clampPlaces(places)
+	if d.scale <= places {
+		return d
+	}
+	unit := pow10[d.scale-places]
+	q, r := d.coef/unit, d.coef%unit
+	if r < 0 {
+		r = -r
+	}
+	if r*2 >= unit {
+		q += sign(d.coef)
+	}
+	return newDecimal(q, places)
+}
+
+// RoundUp rounds away from zero to the given number of places
+func (d Decimal) RoundUp(places int) Decimal {
+	places = clampPlaces(places)
+	if d.scale <= places {
+		return d
+	}
+	unit := pow10[d.scale-places]
+	q := d.coef / unit
+	if d.coef%unit != 0 {
+		q += sign(d.coef)
+	}
+	return newDecimal(q, places)
+}
+
+// StringFixed formats the decimal with exactly the given number of places, truncating extra digits
+func (d Decimal) StringFixed(places int) string {
+	places = clampPlaces(places)
+	t := d.Truncate(places)
+
+	sign := ""
+	u := uint64(t.coef)
+	if t.coef < 0 {
+		sign = "-"
+		u = uint64(-t.coef)
+	}
+	digits := strconv.FormatUint(u, 10)
+	if places == 0 {
+		return sign + digits
+	}
+	if len(digits) <= t.scale {
+		digits = strings.Repeat("0", t.scale-len(digits)+1) + digits
+	}
+	whole, frac := digits[:len(digits)-t.scale], digits[len(digits)-t.scale:]
+	return sign + whole + "." + frac + strings.Repeat("0", places-t.scale)
+}
+
+// String formats the decimal without trailing zeros
+func (d Decimal) String() string {
+	return d.StringFixed(d.scale)
+}
+
+// Diff returns a-b computed in fixed point, e.g. a balance change without float noise
+func Diff(a, b float64) float64 {
+	return NewDecimal(a).Sub(NewDecimal(b)).Float64()
+}
+
+// SpreadPct returns how far price is above base in %
+// A ratio rather than an amount, so it is computed in float64: its resolution then does not depend
+// on how many digits the prices have, and the analyzer calls it for every book update
+func SpreadPct(price, base float64) float64 {
+	if IsZero(base) {
+		return 0
+	}
+	return (price - base) / base * 100
+}
+
+// placeUnit returns one unit in the last of the given places
+func placeUnit(places int) Decimal {
+	return Decimal{coef: 1, scale: clampPlaces(places)}
+}
+
+func clampPlaces(places int) int {
+	return max(0, min(places, MaxDecimalPlaces))
+}
+
+func sign(v int64) int64 {
+	if v < 0 {
+		return -1
+	}
+	return 1
+}
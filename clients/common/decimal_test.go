@@ -0,0 +1,193 @@
+package common
+
+import (
+	"strings"
+	"testing"
+)
+
+// mustParse parses a decimal the table writes out, failing the test on a typo
+func mustParse(t *testing.T, s string) Decimal {
+	t.Helper()
+	d, err := ParseDecimal(s)
+	if err != nil {
+		t.Fatalf("ParseDecimal(%q): %v", s, err)
+	}
+	return d
+}
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		in, want string
+		wantErr  string
+	}{
+		{in: "12.345", want: "12.345"},
+		{in: "-12.345", want: "-12.345"},
+		{in: "+1.50", want: "1.5"},
+		{in: " 0.29 ", want: "0.29"},
+		{in: "0.000", want: "0"},
+		{in: "-0.0", want: "0"},
+		{in: ".5", want: "0.5"},
+		{in: "5.", want: "5"},
+		{in: "0.000000000000000001", want: "0.000000000000000001"},
+		// Past MaxDecimalPlaces the last digit is rounded half away from zero
+		{in: "0.1234567890123456789", want: "0.123456789012345679"},
+		{in: "-0.0000000000000000005", want: "-0.000000000000000001"},
+		{in: "0.0000000000000000004", want: "0"},
+		// Fractional digits give way to the integer part when both do not fit the coefficient
+		{in: "12345678901.123456789", want: "12345678901.12345679"},
+		{in: "9223372036854775807", want: "9223372036854775807"},
+		{in: "-9223372036854775807", want: "-9223372036854775807"},
+		{in: "92233720368547758080", wantErr: "overflows the Decimal range"},
+		{in: "-92233720368547758080.5", wantErr: "overflows the Decimal range"},
+		{in: "", wantErr: "invalid decimal"},
+		{in: "-", wantErr: "invalid decimal"},
+		{in: "1e5", wantErr: "invalid decimal"},
+		{in: "1.2.3", wantErr: "invalid decimal"},
+		{in: "--1", wantErr: "invalid decimal"},
+	}
+	for _, tt := range tests {
+		d, err := ParseDecimal(tt.in)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseDecimal(%q) = %v, %v, want an error containing %q", tt.in, d, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || d.String() != tt.want {
+			t.Errorf("ParseDecimal(%q) = %v, %v, want %s", tt.in, d, err, tt.want)
+		}
+	}
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	tests := []struct {
+		op, a, b, want string
+	}{
+		{"add", "0.1", "0.2", "0.3"},
+		{"add", "1.005", "2", "3.005"},
+		{"add", "0.29", "-0.09", "0.2"},
+		{"add", "-0.000001", "1000", "999.999999"},
+		// Overflow saturates at the Decimal range
+		{"add", "9223372036854775807", "1", "9223372036854775807"},
+		{"sub", "1", "0.001", "0.999"},
+		{"sub", "1.5", "1.50", "0"},
+		{"sub", "-2.25", "0.75", "-3"},
+		{"sub", "0.3", "0.29999", "0.00001"},
+		{"sub", "-9223372036854775807", "2", "-9223372036854775807"},
+		{"mul", "0.5", "250", "125"},
+		{"mul", "1.25", "0.04", "0.05"},
+		{"mul", "-0.003", "1.1", "-0.0033"},
+		{"mul", "-0.5", "-0.5", "0.25"},
+		// Digits past MaxDecimalPlaces round half away from zero
+		{"mul", "0.000000005", "0.0000000001", "0.000000000000000001"},
+		{"mul", "0.000000001", "0.0000000001", "0"},
+		{"div", "1", "3", "0.333333333333333333"},
+		{"div", "2", "3", "0.666666666666666667"},
+		{"div", "-2", "3", "-0.666666666666666667"},
+		{"div", "2", "-3", "-0.666666666666666667"},
+		{"div", "10", "4", "2.5"},
+		{"div", "0.29", "0.01", "29"},
+		{"div", "1.5", "0.0001", "15000"},
+		{"div", "1", "0", "0"},
+		{"div", "0", "0", "0"},
+		{"rem", "10", "3", "1"},
+		{"rem", "0.29", "0.1", "0.09"},
+		{"rem", "40.16", "0.1", "0.06"},
+		// The remainder takes the sign of the dividend
+		{"rem", "-7.5", "2", "-1.5"},
+		{"rem", "7.5", "-2", "1.5"},
+		{"rem", "1", "0", "0"},
+	}
+	for _, tt := range tests {
+		a, b := mustParse(t, tt.a), mustParse(t, tt.b)
+		var got Decimal
+		switch tt.op {
+		case "add":
+			got = a.Add(b)
+		case "sub":
+			got = a.Sub(b)
+		case "mul":
+			got = a.Mul(b)
+		case "div":
+			got = a.Div(b)
+		case "rem":
+			got = a.Rem(b)
+		}
+		// Canonical form: the result compares equal to the expected value with ==
+		if want := mustParse(t, tt.want); got != want {
+			t.Errorf("%s %s %s = %v, want %v", tt.a, tt.op, tt.b, got, want)
+		}
+	}
+}
+
+func TestDecimalRounding(t *testing.T) {
+	tests := []struct {
+		op, in string
+		places int
+		want   string
+	}{
+		{"round", "1.005", 2, "1.01"},
+		{"round", "-1.005", 2, "-1.01"},
+		{"round", "1.0049", 2, "1"},
+		{"round", "0.5", 0, "1"},
+		{"round", "1.5", 4, "1.5"},
+		{"roundup", "1.001", 2, "1.01"},
+		{"roundup", "-1.001", 2, "-1.01"},
+		{"roundup", "1.1", 0, "2"},
+		{"roundup", "1.10", 1, "1.1"},
+		{"truncate", "0.299", 2, "0.29"},
+		{"truncate", "-0.299", 2, "-0.29"},
+		{"truncate", "0.09", 1, "0"},
+		{"truncate", "40.16", -1, "40"},
+	}
+	for _, tt := range tests {
+		d := mustParse(t, tt.in)
+		var got Decimal
+		switch tt.op {
+		case "round":
+			got = d.Round(tt.places)
+		case "roundup":
+			got = d.RoundUp(tt.places)
+		case "truncate":
+			got = d.Truncate(tt.places)
+		}
+		if want := mustParse(t, tt.want); got != want {
+			t.Errorf("%s(%s, %d) = %v, want %v", tt.op, tt.in, tt.places, got, want)
+		}
+	}
+}
+
+func TestDecimalFormatting(t *testing.T) {
+	tests := []struct {
+		in     string
+		places int // -1 formats with String
+		want   string
+	}{
+		{"1.500", -1, "1.5"},
+		{"100", -1, "100"},
+		{"0.000100", -1, "0.0001"},
+		{"-0.050", -1, "-0.05"},
+		{"0", -1, "0"},
+		{"1.5", 4, "1.5000"},
+		{"100", 0, "100"},
+		{"100", 2, "100.00"},
+		{"0.05", 3, "0.050"},
+		{"-0.05", 3, "-0.050"},
+		// StringFixed truncates extra digits rather than rounding them
+		{"12.3456", 2, "12.34"},
+		{"0.0001", 2, "0.00"},
+		{"-0.05", 1, "0.0"},
+	}
+	for _, tt := range tests {
+		d := mustParse(t, tt.in)
+		if tt.places < 0 {
+			if got := d.String(); got != tt.want {
+				t.Errorf("%s.String() = %q, want %q", tt.in, got, tt.want)
+			}
+			continue
+		}
+		if got := d.StringFixed(tt.places); got != tt.want {
+			t.Errorf("%s.StringFixed(%d) = %q, want %q", tt.in, tt.places, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"arbitrage.trade/config"
+)
+
+// Endpoint failover and DNS pinning for exchange REST APIs
+//
+// <EXCHANGE>_API_HOSTS lists groups of interchangeable hosts, groups separated by ";"
+// (e.g. BINANCE_API_HOSTS=api.binance.com,api1.binance.com,api2.binance.com;fapi.binance.com).
+// Requests to any host in a group go to the group's active host; a host that can't be
+// dialed is put on cooldown and the request moves to the next one. Only dial failures
+// are retried, so an order is never sent twice.
+//
+// <EXCHANGE>_PIN_IPS maps hosts to static IPs (e.g. BINANCE_PIN_IPS=api.binance.com=1.2.3.4)
+// to skip DNS resolution; TLS still verifies against the hostname.
+
+// failedHostCooldown is how long a host that failed to dial is skipped
+const failedHostCooldown = 30 * time.Second
+
+var defaultAPIHosts = map[string]string{
+	"binance": "api.binance.com,api1.binance.com,api2.binance.com,api3.binance.com,api4.binance.com",
+}
+
+type endpointGroup struct {
+	mu          sync.Mutex
+	hosts       []string
+	active      int
+	failedUntil map[string]time.Time
+}
+
+func (g *endpointGroup) contains(host string) bool {
+	for _, h := range g.hosts {
+		if h == host {
+			return true
+		}
+	}
+	return false
+}
+
+// current returns the active host, skipping hosts on cooldown
+func (g *endpointGroup) current() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := time.Now()
+	for i := 0; i < len(g.hosts); i++ {
+		idx := (g.active + i) % len(g.hosts)
+		if now.After(g.failedUntil[g.hosts[idx]]) {
+			g.active = idx
+			return g.hosts[idx]
+		}
+	}
+	return g.hosts[g.active]
+}
+
+func (g *endpointGroup) markFailed(host string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.failedUntil[host] = time.Now().Add(failedHostCooldown)
+}
+
+func (g *endpointGroup) setActive(host string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for i, h := range g.hosts {
+		if h == host {
+			changed := i != g.active
+			g.active = i
+			delete(g.failedUntil, host)
+			return changed
+		}
+	}
+	return false
+}
+
+func parseHostGroups(spec string) []*endpointGroup {
+	var groups []*endpointGroup
+	for _, part := range strings.Split(spec, ";") {
+		var hosts []string
+		for _, h := range strings.Split(part, ",") {
+			if h = strings.TrimSpace(h); h != "" {
+				hosts = append(hosts, h)
+			}
+		}
+		if len(hosts) > 0 {
+			groups = append(groups, &endpointGroup{hosts: hosts, failedUntil: make(map[string]time.Time)})
+		}
+	}
+	return groups
+}
+
+func parsePinnedIPs(spec string) map[string]string {
+	pins := make(map[string]string)
+	for _, pair := range strings.Split(spec, ",") {
+		host, ip, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if ok && host != "" && ip != "" {
+			pins[host] = ip
+		}
+	}
+	return pins
+}
+
+// pinnedDialer resolves pinned hosts to their static IP before dialing
+func pinnedDialer(dial func(ctx context.Context, network, addr string) (net.Conn, error), pins map[string]string) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	if len(pins) == 0 {
+		return dial
+	}
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if host, port, err := net.SplitHostPort(addr); err == nil {
+			if ip, ok := pins[host]; ok {
+				addr = net.JoinHostPort(ip, port)
+			}
+		}
+		return dial(ctx, network, addr)
+	}
+}
+
+// failoverTransport routes requests to the active host of their endpoint group
+type failoverTransport struct {
+	exchange string
+	base     http.RoundTripper
+	groups   []*endpointGroup
+}
+
+var (
+	failoverTransports   = make(map[string]*failoverTransport)
+	failoverTransportsMu sync.Mutex
+)
+
+// RoundTripper returns the exchange's shared transport wrapped with endpoint failover
+func RoundTripper(exchange string) http.RoundTripper {
+	failoverTransportsMu.Lock()
+	defer failoverTransportsMu.Unlock()
+
+	if t, ok := failoverTransports[exchange]; ok {
+		return t
+	}
+
+	t := &failoverTransport{
+		exchange: exchange,
+		base:     withFixtures(exchange, Transport(exchange)),
+		groups:   parseHostGroups(config.GetString(config.Key(exchange, "API_HOSTS"), defaultAPIHosts[exchange])),
+	}
+	failoverTransports[exchange] = t
+	return t
+}
+
+func (t *failoverTransport) groupFor(host string) *endpointGroup {
+	for _, g := range t.groups {
+		if g.contains(host) {
+			return g
+		}
+	}
+	return nil
+}
+
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+func (t *failoverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	group := t.groupFor(req.URL.Host)
+	if group == nil {
+		return t.base.RoundTrip(req)
+	}
+
+	for attempt := 0; ; attempt++ {
+		host := group.current()
+
+		r := req.Clone(req.Context())
+		r.URL.Host = host
+		r.Host = host
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			r.Body = body
+		}
+
+		resp, err := t.base.RoundTrip(r)
+		if err == nil || !isDialError(err) {
+			return resp, err
+		}
+
+		group.markFailed(host)
+		log.Printf("[HTTP] %s - host %s unreachable, failing over: %v", strings.ToUpper(t.exchange), host, err)
+
+		if attempt+1 >= len(group.hosts) || (req.Body != nil && req.GetBody == nil) {
+			return nil, err
+		}
+	}
+}
+
+// CheckEndpoints probes every host of the exchange's endpoint groups and makes the
+// fastest reachable one active
+func CheckEndpoints(ctx context.Context, exchange string) {
+	failoverTransportsMu.Lock()
+	t, ok := failoverTransports[exchange]
+	failoverTransportsMu.Unlock()
+	if !ok {
+		return
+	}
+
+	client := &http.Client{Timeout: 5 * time.Second, Transport: t.base}
+
+	for _, group := range t.groups {
+		if len(group.hosts) < 2 {
+			continue
+		}
+
+		best := ""
+		bestLatency := time.Duration(0)
+		for _, host := range group.hosts {
+			req, err := http.NewRequestWithContext(ctx, "GET", "https://"+host+"/", nil)
+			if err != nil {
+				continue
+			}
+
+			start := time.Now()
+			resp, err := client.Do(req)
+			if err != nil {
+				group.markFailed(host)
+				continue
+			}
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+
+			// Any HTTP response means the host is reachable
+			if latency := time.Since(start); best == "" || latency < bestLatency {
+				best, bestLatency = host, latency
+			}
+		}
+
+		if best != "" && group.setActive(best) {
+			log.Printf("[HTTP] %s - switched to %s (%dms)", strings.ToUpper(exchange), best, bestLatency.Milliseconds())
+		}
+	}
+}
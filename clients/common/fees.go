@@ -0,0 +1,15 @@
+package common
+
+import "context"
+
+// FeeRate is an account's current trading fee on a venue market, as fractions of notional
+type FeeRate struct {
+	Taker float64
+	Maker float64
+}
+
+// FeeRateClient is implemented by venues that report the account's current fee tier rates
+type FeeRateClient interface {
+	// GetFeeRate returns the account's fee rate for the pair on market ("spot" or "futures")
+	GetFeeRate(ctx context.Context, market, pairName string) (*FeeRate, error)
+}
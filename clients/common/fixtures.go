@@ -0,0 +1,466 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"arbitrage.trade/config"
+)
+
+// Recorded HTTP fixtures
+//
+// With HTTP_FIXTURES_MODE=record every exchange REST call is passed through and appended to
+// HTTP_FIXTURES_DIR/<exchange>.json (default dir fixtures). With HTTP_FIXTURES_MODE=replay no request
+// leaves the process: each one is answered from the recorded interaction with the same method, path,
+// query and body, ignoring volatile signing parameters (timestamps, signatures, nonces). Replay also
+// checks a request is signed wherever its recording was, so signature building, JSON parsing and
+// precision handling can be exercised offline (see the "fixtures" and "conformance" commands); the
+// cassettes the adapters' tests replay are synthetic rather than captured (see clients/fixturetest). SetFixtureFault and InjectFixtureFault make replayed requests fail, all of
+// them or a single one, to exercise error handling and retries.
+
+// volatileParams are query/body fields that change on every request and are ignored when matching
+// Crypto.com's request id counts up per client, so it depends on what the client sent before
+var volatileParams = map[string]bool{
+	"timestamp": true, "signature": true, "recvwindow": true, "sign": true, "nonce": true, "sig": true,
+	"newclientorderid": true, "clordid": true, "clientoid": true, "starttime": true, "begin": true, "id": true,
+}
+
+// signatureHeaders carry request signatures, recorded only to check replayed requests are still signed
+var signatureHeaders = []string{
+	"OK-ACCESS-SIGN", "ACCESS-SIGN", "SIGN", "X-TXC-SIGNATURE", "API-SIGN", "AUTHENT",
+}
+
+// signatureParams carry request signatures in the query string or body
+var signatureParams = []string{"signature", "sig"}
+
+// FixtureInteraction is one recorded request and its response
+type FixtureInteraction struct {
+	Method   string   `json:"method"`
+	URL      string   `json:"url"` // Scheme, host and path with volatile query params removed, params sorted
+	Body     string   `json:"body,omitempty"`
+	Signed   []string `json:"signed,omitempty"` // Signature headers or params present on the request
+	Status   int      `json:"status"`
+	Response string   `json:"response"`
+}
+
+// Cassette is the recorded interactions of one exchange
+type Cassette struct {
+	mu           sync.Mutex
+	path         string
+	Interactions []FixtureInteraction `json:"interactions"`
+	used         []bool
+	unrecorded   []string // Replayed requests no unused interaction matched
+}
+
+var (
+	cassettes   = make(map[string]*Cassette)
+	cassettesMu sync.Mutex
+)
+
+// FixtureFault is a failure replayed requests of an exchange get instead of their recording
+type FixtureFault struct {
+	Status     int // 0 fails the request on the network instead of answering it
+	Body       string
+	RetryAfter int    // Seconds sent in a Retry-After header, none when 0
+	Method     string // Only requests with this method and URL path fail, any when empty
+	Path       string
+	Once       bool // Only the first matching request fails, later ones replay as recorded
+}
+
+// matches reports whether the fault applies to the request
+func (f FixtureFault) matches(req *http.Request) bool {
+	return (f.Method == "" || f.Method == req.Method) && (f.Path == "" || f.Path == req.URL.Path)
+}
+
+type fixtureFault struct {
+	FixtureFault
+	hits int
+}
+
+// errFixtureNetwork is the error of a request failed by a fault without a status
+var errFixtureNetwork = errors.New("fixture fault: connection reset")
+
+var (
+	fixtureFaults   = make(map[string]*fixtureFault)
+	fixtureFaultsMu sync.Mutex
+)
+
+// FixtureObserver receives every request replayed for an exchange with its body, as it was sent
+type FixtureObserver func(req *http.Request, body []byte)
+
+var (
+	fixtureObservers   = make(map[string]FixtureObserver)
+	fixtureObserversMu sync.Mutex
+)
+
+// SetFixtureObserver hands every replayed request of the exchange to observe, nil to stop, and returns
+// the observer it replaced so a caller can chain to it and restore it
+// Lets tests check how requests were built and signed
+func SetFixtureObserver(exchange string, observe FixtureObserver) FixtureObserver {
+	fixtureObserversMu.Lock()
+	defer fixtureObserversMu.Unlock()
+	previous := fixtureObservers[exchange]
+	if observe == nil {
+		delete(fixtureObservers, exchange)
+		return previous
+	}
+	fixtureObservers[exchange] = observe
+	return previous
+}
+
+// observeFixtureRequest passes a replayed request to the exchange's observer, if any
+func observeFixtureRequest(exchange string, req *http.Request, body []byte) {
+	fixtureObserversMu.Lock()
+	observe := fixtureObservers[exchange]
+	fixtureObserversMu.Unlock()
+	if observe != nil {
+		observe(req, body)
+	}
+}
+
+// SetFixtureFault answers every replayed request of the exchange with status and body until cleared
+func SetFixtureFault(exchange string, status int, body string) {
+	InjectFixtureFault(exchange, FixtureFault{Status: status, Body: body})
+}
+
+// InjectFixtureFault fails the exchange's replayed requests the fault matches until cleared
+func InjectFixtureFault(exchange string, fault FixtureFault) {
+	fixtureFaultsMu.Lock()
+	defer fixtureFaultsMu.Unlock()
+	fixtureFaults[exchange] = &fixtureFault{FixtureFault: fault}
+}
+
+// ClearFixtureFault removes the exchange's fault and returns how many requests it answered
+func ClearFixtureFault(exchange string) int {
+	fixtureFaultsMu.Lock()
+	defer fixtureFaultsMu.Unlock()
+
+	fault, ok := fixtureFaults[exchange]
+	if !ok {
+		return 0
+	}
+	delete(fixtureFaults, exchange)
+	return fault.hits
+}
+
+// faultResponse returns the injected response or network error for a replayed request, both nil
+// when no fault applies
+func faultResponse(exchange string, req *http.Request) (*http.Response, error) {
+	fixtureFaultsMu.Lock()
+	defer fixtureFaultsMu.Unlock()
+
+	fault, ok := fixtureFaults[exchange]
+	if !ok || !fault.matches(req) || (fault.Once && fault.hits > 0) {
+		return nil, nil
+	}
+	fault.hits++
+	if fault.Status == 0 {
+		return nil, errFixtureNetwork
+	}
+
+	header := http.Header{"Content-Type": []string{"application/json"}}
+	if fault.RetryAfter > 0 {
+		header.Set("Retry-After", strconv.Itoa(fault.RetryAfter))
+	}
+	return &http.Response{
+		StatusCode: fault.Status,
+		Status:     fmt.Sprintf("%d %s", fault.Status, http.StatusText(fault.Status)),
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader(fault.Body)),
+		Request:    req,
+	}, nil
+}
+
+// FixturesMode returns "record", "replay" or "" when fixtures are disabled
+func FixturesMode() string {
+	return strings.ToLower(config.GetString("HTTP_FIXTURES_MODE", ""))
+}
+
+// FixturePath returns the cassette file of an exchange
+func FixturePath(exchange string) string {
+	return filepath.Join(config.GetString("HTTP_FIXTURES_DIR", "fixtures"), exchange+".json")
+}
+
+// ResetCassettes drops every loaded cassette, so the next replay reads it from disk again and starts
+// from its first interaction
+func ResetCassettes() {
+	cassettesMu.Lock()
+	defer cassettesMu.Unlock()
+	cassettes = make(map[string]*Cassette)
+}
+
+// LoadCassette returns the exchange's cassette, reading it from disk on first use
+func LoadCassette(exchange string) (*Cassette, error) {
+	cassettesMu.Lock()
+	defer cassettesMu.Unlock()
+
+	if c, ok := cassettes[exchange]; ok {
+		return c, nil
+	}
+
+	c := &Cassette{path: FixturePath(exchange)}
+	data, err := os.ReadFile(c.path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read fixtures: %w", err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, c); err != nil {
+			return nil, fmt.Errorf("failed to parse fixtures %s: %w", c.path, err)
+		}
+	}
+	c.used = make([]bool, len(c.Interactions))
+	cassettes[exchange] = c
+	return c, nil
+}
+
+// Unused returns the recorded interactions no replayed request matched
+func (c *Cassette) Unused() []FixtureInteraction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var unused []FixtureInteraction
+	for i, used := range c.used {
+		if !used {
+			unused = append(unused, c.Interactions[i])
+		}
+	}
+	return unused
+}
+
+// Unrecorded returns the replayed requests no unused interaction matched, whether they were answered
+// again by an already replayed interaction or not at all
+func (c *Cassette) Unrecorded() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.unrecorded...)
+}
+
+func (c *Cassette) record(interaction FixtureInteraction) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.Interactions = append(c.Interactions, interaction)
+	c.used = append(c.used, true)
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		log.Printf("[FIXTURES] record - ERROR: %v", err)
+		return
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		log.Printf("[FIXTURES] record - ERROR: %v", err)
+		return
+	}
+	if err := os.WriteFile(c.path, data, 0644); err != nil {
+		log.Printf("[FIXTURES] record - ERROR: %v", err)
+	}
+}
+
+// match returns the first unused interaction for the request, or the last used one when all were replayed
+func (c *Cassette) match(method, url, body string) (FixtureInteraction, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	found := -1
+	for i, in := range c.Interactions {
+		if in.Method != method || in.URL != url || in.Body != body {
+			continue
+		}
+		if !c.used[i] {
+			c.used[i] = true
+			return in, true
+		}
+		found = i
+	}
+	c.unrecorded = append(c.unrecorded, strings.TrimSpace(method+" "+url+" "+body))
+	if found < 0 {
+		return FixtureInteraction{}, false
+	}
+	return c.Interactions[found], true
+}
+
+// fixtureTransport records or replays an exchange's requests
+type fixtureTransport struct {
+	exchange string
+	base     http.RoundTripper
+	replay   bool
+}
+
+// withFixtures wraps a transport according to HTTP_FIXTURES_MODE
+func withFixtures(exchange string, base http.RoundTripper) http.RoundTripper {
+	switch FixturesMode() {
+	case "record":
+		return &fixtureTransport{exchange: exchange, base: base}
+	case "replay":
+		return &fixtureTransport{exchange: exchange, replay: true}
+	}
+	return base
+}
+
+func (t *fixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	cassette, err := LoadCassette(t.exchange)
+	if err != nil {
+		return nil, err
+	}
+
+	var body []byte
+	if req.Body != nil {
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	key := normalizeFixtureURL(req.URL)
+	normalizedBody := normalizeFixtureBody(body)
+	signed := signatureFields(req, body)
+
+	if t.replay {
+		observeFixtureRequest(t.exchange, req, body)
+		if resp, err := faultResponse(t.exchange, req); resp != nil || err != nil {
+			return resp, err
+		}
+		in, ok := cassette.match(req.Method, key, normalizedBody)
+		if !ok {
+			if normalizedBody != "" {
+				return nil, fmt.Errorf("no recorded %s fixture for %s %s with body %s", t.exchange, req.Method, key, normalizedBody)
+			}
+			return nil, fmt.Errorf("no recorded %s fixture for %s %s", t.exchange, req.Method, key)
+		}
+		if len(in.Signed) > 0 && len(signed) == 0 {
+			return nil, fmt.Errorf("%s %s was signed (%s) when recorded but is unsigned now", req.Method, key, strings.Join(in.Signed, ", "))
+		}
+		return &http.Response{
+			StatusCode: in.Status,
+			Status:     fmt.Sprintf("%d %s", in.Status, http.StatusText(in.Status)),
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(in.Response)),
+			Request:    req,
+		}, nil
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	cassette.record(FixtureInteraction{
+		Method:   req.Method,
+		URL:      key,
+		Body:     normalizedBody,
+		Signed:   signed,
+		Status:   resp.StatusCode,
+		Response: string(respBody),
+	})
+	return resp, nil
+}
+
+// normalizeFixtureURL drops volatile query params and sorts the rest
+func normalizeFixtureURL(u *url.URL) string {
+	q := u.Query()
+	for name := range q {
+		if volatileParams[strings.ToLower(name)] {
+			q.Del(name)
+		}
+	}
+	normalized := u.Scheme + "://" + u.Host + u.Path
+	if encoded := q.Encode(); encoded != "" {
+		normalized += "?" + encoded
+	}
+	return normalized
+}
+
+// normalizeFixtureBody drops volatile fields from JSON or form bodies so they match across runs
+func normalizeFixtureBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	var fields map[string]interface{}
+	if json.Unmarshal(body, &fields) == nil {
+		for name := range fields {
+			if volatileParams[strings.ToLower(name)] {
+				delete(fields, name)
+			}
+		}
+		out, _ := json.Marshal(fields) // Map keys marshal sorted
+		return string(out)
+	}
+
+	if form, err := url.ParseQuery(string(body)); err == nil {
+		for name := range form {
+			if volatileParams[strings.ToLower(name)] {
+				form.Del(name)
+			}
+		}
+		return form.Encode()
+	}
+	return string(body)
+}
+
+// signatureFields lists the signature headers and params present on a request
+func signatureFields(req *http.Request, body []byte) []string {
+	var fields []string
+	for field := range signatureValues(req, body) {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// FixtureSignature returns a request's signature headers and params with their values, "" when unsigned
+// Lets tests check a request sent again was signed again
+func FixtureSignature(req *http.Request, body []byte) string {
+	values := signatureValues(req, body)
+	fields := make([]string, 0, len(values))
+	for field, value := range values {
+		fields = append(fields, field+"="+value)
+	}
+	sort.Strings(fields)
+	return strings.Join(fields, "&")
+}
+
+// signatureValues returns the signature headers and params present on a request by name
+func signatureValues(req *http.Request, body []byte) map[string]string {
+	values := make(map[string]string)
+	for _, h := range signatureHeaders {
+		if v := req.Header.Get(h); v != "" {
+			values[h] = v
+		}
+	}
+
+	var jsonBody map[string]interface{}
+	json.Unmarshal(body, &jsonBody)
+	form, _ := url.ParseQuery(string(body))
+	for _, p := range signatureParams {
+		switch {
+		case req.URL.Query().Get(p) != "":
+			values[p] = req.URL.Query().Get(p)
+		case form.Get(p) != "":
+			values[p] = form.Get(p)
+		case jsonBody[p] != nil:
+			values[p] = fmt.Sprint(jsonBody[p])
+		}
+	}
+	return values
+}
@@ -0,0 +1,24 @@
+package common
+
+import (
+	"context"
+	"time"
+)
+
+// FundingRate is a perp's current funding rate
+type FundingRate struct {
+	Rate        float64   // Fraction of notional per interval, positive when longs pay shorts
+	FundingTime time.Time // Settlement the rate applies to
+}
+
+// FundingClient is implemented by venues that publish perp funding rates
+type FundingClient interface {
+	// GetFundingRate returns the pair's current perp funding rate
+	GetFundingRate(ctx context.Context, pairName string) (*FundingRate, error)
+}
+
+// FundingHistoryClient is implemented by venues that publish their perps' past funding settlements
+type FundingHistoryClient interface {
+	// GetFundingHistory returns the pair's perp funding settlements in [from, to), oldest first
+	GetFundingHistory(ctx context.Context, pairName string, from, to time.Time) ([]FundingRate, error)
+}
@@ -0,0 +1,29 @@
+package common
+
+import (
+	"context"
+	"time"
+)
+
+// AccountFill is one execution on a venue account as its trade history reports it
+type AccountFill struct {
+	Exchange      string
+	Market        string // "spot" or "futures"
+	Pair          string
+	FillID        string // Venue trade ID, unique within the venue market
+	OrderID       string
+	ClientOrderID string // "" when the venue's trade history doesn't carry it
+	Side          string // "buy" or "sell"
+	Price         float64
+	Quantity      float64 // Base units
+	Fee           float64 // Charged in FeeAsset, negative for rebates
+	FeeAsset      string
+	RealizedPnL   float64 // Futures only, as the venue reports it
+	Time          time.Time
+}
+
+// TradeHistoryClient is implemented by venues whose account trade history can be downloaded
+type TradeHistoryClient interface {
+	// GetFills returns the account's fills of the pair on market ("spot" or "futures") in [from, to), oldest first
+	GetFills(ctx context.Context, market, pairName string, from, to time.Time) ([]AccountFill, error)
+}
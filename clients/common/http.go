@@ -0,0 +1,98 @@
+package common
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"arbitrage.trade/config"
+)
+
+// Shared per-exchange transports so REST calls reuse keepalive connections instead of
+// paying a TCP + TLS handshake per order. Tunable with HTTP_MAX_IDLE_PER_HOST,
+// HTTP_IDLE_CONN_TIMEOUT, HTTP_KEEPALIVE and HTTP2, each overridable per exchange
+// (e.g. HTTP2_WHITEBIT=false)
+var (
+	transports   = make(map[string]*http.Transport)
+	transportsMu sync.Mutex
+)
+
+func exchangeSetting(name, exchange string) string {
+	return config.Key(name, exchange)
+}
+
+// Transport returns the shared transport for an exchange, creating it on first use
+func Transport(exchange string) *http.Transport {
+	transportsMu.Lock()
+	defer transportsMu.Unlock()
+
+	if t, ok := transports[exchange]; ok {
+		return t
+	}
+
+	keepAlive := config.GetDuration(exchangeSetting("HTTP_KEEPALIVE", exchange), config.GetDuration("HTTP_KEEPALIVE", 30*time.Second))
+	idleTimeout := config.GetDuration(exchangeSetting("HTTP_IDLE_CONN_TIMEOUT", exchange), config.GetDuration("HTTP_IDLE_CONN_TIMEOUT", 120*time.Second))
+	maxIdlePerHost := config.GetInt(exchangeSetting("HTTP_MAX_IDLE_PER_HOST", exchange), config.GetInt("HTTP_MAX_IDLE_PER_HOST", 16))
+	http2 := config.GetBool(exchangeSetting("HTTP2", exchange), config.GetBool("HTTP2", true))
+
+	t := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: pinnedDialer((&net.Dialer{
+			Timeout:   5 * time.Second,
+			KeepAlive: keepAlive,
+		}).DialContext, parsePinnedIPs(config.GetString(config.Key(exchange, "PIN_IPS"), ""))),
+		MaxIdleConns:          maxIdlePerHost * 4,
+		MaxIdleConnsPerHost:   maxIdlePerHost,
+		IdleConnTimeout:       idleTimeout,
+		TLSHandshakeTimeout:   5 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+		ForceAttemptHTTP2:     http2,
+	}
+	if !http2 {
+		// A non-nil empty map disables HTTP/2 negotiation
+		t.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+	}
+
+	transports[exchange] = t
+	return t
+}
+
+// NewHTTPClient returns an http.Client on the exchange's shared transport with endpoint failover
+func NewHTTPClient(exchange string) *http.Client {
+	return &http.Client{
+		Timeout:   30 * time.Second,
+		Transport: RoundTripper(exchange),
+	}
+}
+
+// PrewarmConnections issues lightweight requests to the given URLs in parallel so
+// pooled connections are already established before the first order
+func PrewarmConnections(ctx context.Context, client *http.Client, urls ...string) {
+	var wg sync.WaitGroup
+	for _, url := range urls {
+		wg.Add(1)
+		go func(url string) {
+			defer wg.Done()
+
+			req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+			if err != nil {
+				return
+			}
+
+			resp, err := client.Do(req)
+			if err != nil {
+				log.Printf("[HTTP] Prewarm %s - ERROR: %v", url, err)
+				return
+			}
+			// Drain so the connection goes back to the pool
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}(url)
+	}
+	wg.Wait()
+}
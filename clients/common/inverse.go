@@ -0,0 +1,73 @@
+package common
+
+import (
+	"context"
+	"math"
+	"strings"
+
+	"arbitrage.trade/config"
+)
+
+// Inverse (coin-margined) perpetuals
+//
+// On venues listed in INVERSE_PERP_VENUES (e.g. "binance", or "binance:btc-usdt" for single pairs)
+// forward trades hedge the spot long with a coin-margined perp short instead of the USDT perp. Inverse
+// contracts have a fixed USD face value and are margined and settled in the base coin, so the order
+// is sized in whole contracts from the USDT amount and the venue's coin-margin wallet must already
+// hold enough of the base coin to back it at 1x. Fills and realized P&L are reported in base units
+// and USDT like every other leg, converted at the close fill price.
+//
+// The coin collateral itself moves with the price; only the trade's own P&L is reported here, so
+// standing collateral should be hedged separately.
+
+// InverseClient is implemented by venues that list coin-margined perpetuals
+type InverseClient interface {
+	// PutInverseShort shorts coin-margined contracts worth amountUSDT of face value
+	PutInverseShort(ctx context.Context, pairName string, amountUSDT float64) (*TradeResult, error)
+
+	// CloseInverseShort buys back the short and returns the realized P&L converted to USDT
+	CloseInverseShort(ctx context.Context, pairName string) (*TradeResult, float64, error)
+
+	// GetInversePosition returns the signed coin-margined position in contracts (negative = short)
+	GetInversePosition(ctx context.Context, pairName string) (float64, error)
+}
+
+// InverseHedge reports whether forward trades on the exchange hedge the pair with an inverse perp
+func InverseHedge(exchange, pairName string) bool {
+	for _, entry := range strings.Split(config.GetString("INVERSE_PERP_VENUES", ""), ",") {
+		venue, pair, scoped := strings.Cut(strings.TrimSpace(entry), ":")
+		if !strings.EqualFold(venue, exchange) {
+			continue
+		}
+		if !scoped || strings.EqualFold(pair, pairName) {
+			return true
+		}
+	}
+	return false
+}
+
+// InverseContracts returns the whole contracts of contractSize USD face value that fit in amountUSD
+func InverseContracts(amountUSD, contractSize float64) float64 {
+	if !IsPositive(contractSize) {
+		return 0
+	}
+	return math.Floor(amountUSD/contractSize + Epsilon)
+}
+
+// InverseMargin returns the base coin needed to back contracts at 1x leverage at price
+func InverseMargin(contracts, contractSize, price float64) float64 {
+	if !IsPositive(price) {
+		return 0
+	}
+	return contracts * contractSize / price
+}
+
+// InverseShortPnL returns a short's realized P&L in the base coin and converted to USDT at the exit price
+// A short of N USD face value gains N × (1/exit − 1/entry) coins
+func InverseShortPnL(contracts, contractSize, entryPrice, exitPrice float64) (coin, usdt float64) {
+	if !IsPositive(entryPrice) || !IsPositive(exitPrice) {
+		return 0, 0
+	}
+	coin = contracts * contractSize * (1/exitPrice - 1/entryPrice)
+	return coin, coin * exitPrice
+}
@@ -0,0 +1,39 @@
+package common
+
+import (
+	"context"
+	"time"
+)
+
+type OptionType string
+
+const (
+	Call OptionType = "call"
+	Put  OptionType = "put"
+)
+
+// OptionQuote is a priced protective option covering a notional of the underlying
+type OptionQuote struct {
+	Instrument string     `json:"instrument"`
+	Type       OptionType `json:"type"`
+	Strike     float64    `json:"strike"`
+	Expiry     time.Time  `json:"expiry"`
+	Contracts  float64    `json:"contracts"`
+	Price      float64    `json:"price"`   // Ask per contract in USDT
+	Premium    float64    `json:"premium"` // Contracts × Price in USDT
+	Covered    float64    `json:"covered"` // Underlying notional covered in USDT
+}
+
+// OptionsClient is implemented by venues that list options usable as a tail hedge
+// Fills are reported per contract with prices and fees converted to USDT
+type OptionsClient interface {
+	// QuoteProtectiveOption picks the nearest-expiry option at least otmPct out of the money,
+	// expiring within maxExpiry, and sizes it to cover notionalUSDT of the pair's underlying
+	QuoteProtectiveOption(ctx context.Context, pairName string, optionType OptionType, notionalUSDT, otmPct float64, maxExpiry time.Duration) (*OptionQuote, error)
+
+	// BuyOption buys the quoted contracts at no worse than the quoted price
+	BuyOption(ctx context.Context, quote *OptionQuote) (*TradeResult, error)
+
+	// SellOption sells held contracts of an option back at the bid
+	SellOption(ctx context.Context, instrument string, contracts float64) (*TradeResult, error)
+}
@@ -0,0 +1,99 @@
+package common
+
+import (
+	"context"
+	"hash/fnv"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"arbitrage.trade/config"
+)
+
+var orderSeq atomic.Uint64
+
+// ClientOrderPrefix returns the prefix of the client order IDs this bot tags its orders with
+// (CLIENT_ORDER_PREFIX, default "arb"), letters and digits only so every venue accepts it
+func ClientOrderPrefix() string {
+	return config.GetString("CLIENT_ORDER_PREFIX", "arb")
+}
+
+// randomOrderIDs reports whether client order IDs drop the fixed prefix for randomized formats
+// (CLIENT_ORDER_ID_STYLE=random, default prefixed) so the bot's orders don't share an obvious tag
+func randomOrderIDs() bool {
+	return config.GetString("CLIENT_ORDER_ID_STYLE", "prefixed") == "random"
+}
+
+// orderIDFormat is one shape a randomized client order ID takes
+type orderIDFormat struct {
+	alphabet string
+	checksum int // Trailing characters signing the rest, sized for a ~1e-6 false match
+}
+
+var orderIDFormats = []orderIDFormat{
+	{alphabet: "0123456789abcdef", checksum: 5},
+	{alphabet: "0123456789abcdefghijklmnopqrstuvwxyz", checksum: 4},
+	{alphabet: "0123456789", checksum: 6},
+}
+
+// orderIDChecksum signs an ID body with CLIENT_ORDER_SECRET (default the prefix) in the format's alphabet
+func orderIDChecksum(body string, format orderIDFormat) string {
+	h := fnv.New64a()
+	h.Write([]byte(config.GetString("CLIENT_ORDER_SECRET", ClientOrderPrefix())))
+	h.Write([]byte(body))
+	sum := h.Sum64()
+
+	base := uint64(len(format.alphabet))
+	out := make([]byte, format.checksum)
+	for i := range out {
+		out[i] = format.alphabet[sum%base]
+		sum /= base
+	}
+	return string(out)
+}
+
+// NewClientOrderID returns a unique client order ID carrying the bot's prefix, at most 24 characters
+// With CLIENT_ORDER_ID_STYLE=random it is instead 16-24 random hex, base36 or digit characters whose
+// tail is a keyed checksum, which IsBotOrderID still recognizes
+func NewClientOrderID() string {
+	seq := orderSeq.Add(1) % 1296
+	if !randomOrderIDs() {
+		return ClientOrderPrefix() + strconv.FormatInt(time.Now().UnixNano(), 36) + strconv.FormatUint(seq, 36)
+	}
+
+	format := orderIDFormats[rand.Intn(len(orderIDFormats))]
+	body := make([]byte, 16+rand.Intn(9)-format.checksum)
+	for i := range body {
+		body[i] = format.alphabet[rand.Intn(len(format.alphabet))]
+	}
+	return string(body) + orderIDChecksum(string(body), format)
+}
+
+// IsBotOrderID reports whether a client order ID was generated by NewClientOrderID, in either style
+func IsBotOrderID(id string) bool {
+	if id == "" {
+		return false
+	}
+	if strings.HasPrefix(id, ClientOrderPrefix()) {
+		return true
+	}
+
+	for _, format := range orderIDFormats {
+		if len(id) <= format.checksum || strings.Trim(id, format.alphabet) != "" {
+			continue
+		}
+		split := len(id) - format.checksum
+		if id[split:] == orderIDChecksum(id[:split], format) {
+			return true
+		}
+	}
+	return false
+}
+
+// OrderHistoryClient is implemented by venues whose order history exposes client order IDs
+type OrderHistoryClient interface {
+	// HasBotOrders reports whether an order tagged by this bot was placed on the pair's market ("spot" or "futures") since the given time
+	HasBotOrders(ctx context.Context, market, pairName string, since time.Time) (bool, error)
+}
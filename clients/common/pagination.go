@@ -0,0 +1,122 @@
+package common
+
+import "time"
+
+// History pagination
+//
+// Venue history endpoints (account fills, funding settlements, income) cap how much one request
+// returns in two ways: a longest time range per request and a page size. Venues then continue a full
+// page either by time (the next request starts at the page's last record) or by an opaque cursor (a
+// record ID the next request continues after). TimePager and CursorPager walk both kinds so clients
+// only build the request and parse the page:
+//
+//	pager := common.NewTimePager(from, to, 24*time.Hour, 1000)
+//	for pager.Next() {
+//		window := pager.Window()
+//		... request [window.From, window.To), parse records ...
+//		pager.Page(len(records), lastRecordTime)
+//	}
+//
+// A time-continued page re-reads its last record's millisecond, so callers dedupe records by ID.
+
+// TimeWindow is a [From, To) slice of a history range
+type TimeWindow struct {
+	From time.Time
+	To   time.Time
+}
+
+// EndMs returns the window's last included millisecond, for venues whose end time is inclusive
+func (w TimeWindow) EndMs() int64 {
+	return w.To.UnixMilli() - 1
+}
+
+// TimePager walks [from, to) in windows of at most the venue's longest range, continuing a full page
+// from its last record's time
+type TimePager struct {
+	to       time.Time
+	size     time.Duration
+	pageSize int
+	window   TimeWindow
+	started  bool
+}
+
+// NewTimePager creates a pager over [from, to); windowSize <= 0 requests the whole range at once
+func NewTimePager(from, to time.Time, windowSize time.Duration, pageSize int) *TimePager {
+	return &TimePager{
+		to:       to,
+		size:     windowSize,
+		pageSize: pageSize,
+		window:   TimeWindow{From: from, To: from},
+	}
+}
+
+// Next moves to the next request and reports whether there is one
+func (p *TimePager) Next() bool {
+	if !p.started {
+		p.started = true
+		p.window = p.windowFrom(p.window.From)
+	}
+	return p.window.From.Before(p.to)
+}
+
+// Window returns the range of the current request
+func (p *TimePager) Window() TimeWindow {
+	return p.window
+}
+
+// Page records the current request's result: how many records it returned and the time of its last one
+// A full page continues from that time unless the whole page shared the window's start, otherwise the
+// next window follows
+func (p *TimePager) Page(count int, last time.Time) {
+	if p.pageSize > 0 && count >= p.pageSize && last.After(p.window.From) {
+		p.window = TimeWindow{From: last, To: p.window.To}
+		return
+	}
+	p.window = p.windowFrom(p.window.To)
+}
+
+// windowFrom returns the window of at most the pager's size starting at start
+func (p *TimePager) windowFrom(start time.Time) TimeWindow {
+	end := p.to
+	if p.size > 0 && start.Add(p.size).Before(p.to) {
+		end = start.Add(p.size)
+	}
+	return TimeWindow{From: start, To: end}
+}
+
+// CursorPager walks a history endpoint that continues full pages after a record ID
+type CursorPager struct {
+	pageSize int
+	cursor   string
+	started  bool
+	done     bool
+}
+
+// NewCursorPager creates a pager over pages of pageSize records
+func NewCursorPager(pageSize int) *CursorPager {
+	return &CursorPager{pageSize: pageSize}
+}
+
+// Next moves to the next request and reports whether there is one
+func (p *CursorPager) Next() bool {
+	if !p.started {
+		p.started = true
+		return true
+	}
+	return !p.done
+}
+
+// Cursor returns the ID the current request continues after, "" for the first page
+func (p *CursorPager) Cursor() string {
+	return p.cursor
+}
+
+// Page records the current request's result: how many records it returned and the cursor of its last one
+// A short page or a missing cursor ends the walk
+func (p *CursorPager) Page(count int, next string) {
+	if count < p.pageSize || next == "" || next == p.cursor {
+		p.done = true
+		return
+	}
+	p.cursor = next
+}
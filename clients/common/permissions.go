@@ -0,0 +1,46 @@
+package common
+
+import "context"
+
+// Scopes a key needs to run both legs on a venue
+const (
+	ScopeRead         = "read"
+	ScopeSpotTrade    = "spot_trade"
+	ScopeFuturesTrade = "futures_trade"
+)
+
+// APIPermissions is what a venue reports an API key may do
+type APIPermissions struct {
+	Read         bool
+	SpotTrade    bool
+	FuturesTrade bool
+	Withdraw     bool
+	IPRestricted bool     // Key only works from whitelisted IPs
+	Unverified   []string // Scopes the venue's key-info endpoint doesn't report
+}
+
+// PermissionClient is implemented by clients that can look up their API key's permissions
+type PermissionClient interface {
+	GetAPIPermissions(ctx context.Context) (*APIPermissions, error)
+}
+
+// Missing returns the required scopes the key is known not to have
+func (p *APIPermissions) Missing() []string {
+	unverified := make(map[string]bool, len(p.Unverified))
+	for _, scope := range p.Unverified {
+		unverified[scope] = true
+	}
+
+	granted := []struct {
+		scope string
+		ok    bool
+	}{{ScopeRead, p.Read}, {ScopeSpotTrade, p.SpotTrade}, {ScopeFuturesTrade, p.FuturesTrade}}
+
+	var missing []string
+	for _, g := range granted {
+		if !g.ok && !unverified[g.scope] {
+			missing = append(missing, g.scope)
+		}
+	}
+	return missing
+}
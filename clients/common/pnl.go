@@ -0,0 +1,17 @@
+package common
+
+import "context"
+
+// PositionPnL is a venue's own view of an open perp position
+type PositionPnL struct {
+	Size          float64 // Signed position in the venue's units (negative = short)
+	EntryPrice    float64
+	MarkPrice     float64
+	UnrealizedPnL float64 // USDT at the mark price
+}
+
+// PositionPnLClient is implemented by venues that report their perp positions' unrealized PnL
+type PositionPnLClient interface {
+	// GetPerpPnL returns the pair's open perp position as the venue values it, nil when flat
+	GetPerpPnL(ctx context.Context, pairName string) (*PositionPnL, error)
+}
@@ -1,10 +1,5 @@
 package common
 
-import (
-	"fmt"
-	"math"
-)
-
 type PairPrecision struct {
 	QuantityPrecision int
 	PricePrecision    int
@@ -59,20 +54,35 @@ func GetPrecision(pairName string) PairPrecision {
 	return PairPrecision{QuantityPrecision: 8, PricePrecision: 8}
 }
 
+// FormatQuantity formats a quantity truncated to the pair's quantity precision, never rounding up past what is held
 func FormatQuantity(qty float64, pairName string) string {
 	prec := GetPrecision(pairName)
-	return fmt.Sprintf("%.*f", prec.QuantityPrecision, qty)
+	return NewDecimal(qty).StringFixed(prec.QuantityPrecision)
 }
 
 func FormatPrice(price float64, pairName string) string {
 	prec := GetPrecision(pairName)
-	return fmt.Sprintf("%.*f", prec.PricePrecision, price)
+	return NewDecimal(price).Round(prec.PricePrecision).StringFixed(prec.PricePrecision)
 }
 
+// RoundQuantity truncates a quantity to the pair's quantity precision
 func RoundQuantity(qty float64, pairName string) float64 {
-	prec := GetPrecision(pairName)
-	multiplier := math.Pow(10, float64(prec.QuantityPrecision))
-	return math.Floor(qty*multiplier) / multiplier
+	return RoundQuantityDecimal(NewDecimal(qty), pairName).Float64()
+}
+
+// RoundQuantityDecimal truncates a quantity to the pair's quantity precision
+func RoundQuantityDecimal(qty Decimal, pairName string) Decimal {
+	return qty.Truncate(GetPrecision(pairName).QuantityPrecision)
+}
+
+// CeilQuantity rounds a quantity up to the pair's quantity precision
+func CeilQuantity(qty float64, pairName string) float64 {
+	return NewDecimal(qty).RoundUp(GetPrecision(pairName).QuantityPrecision).Float64()
+}
+
+// QuantityForNotional returns the quantity buying amountUSDT at price, truncated to the pair's precision
+func QuantityForNotional(amountUSDT, price float64, pairName string) float64 {
+	return RoundQuantityDecimal(NewDecimal(amountUSDT).Div(NewDecimal(price)), pairName).Float64()
 }
 
 // CalculateMinAchievableVolume calculates the minimum USDT volume achievable
@@ -82,8 +92,8 @@ func RoundQuantity(qty float64, pairName string) float64 {
 //   - Minimum volume = 1 * $40 = $40
 func CalculateMinAchievableVolume(price float64, pairName string) float64 {
 	prec := GetPrecision(pairName)
-	minQuantity := 1.0 / math.Pow(10, float64(prec.QuantityPrecision))
-	return minQuantity * price
+	minQuantity := placeUnit(prec.QuantityPrecision)
+	return minQuantity.Mul(NewDecimal(price)).Float64()
 }
 
 // CanAchieveVolume checks if a target USDT volume can be achieved
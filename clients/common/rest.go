@@ -0,0 +1,302 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"arbitrage.trade/config"
+)
+
+// REST transport
+//
+// Every venue client sends its REST calls through a RESTClient, which owns what they have in common:
+// sending on the venue's shared transport, retrying reads, mapping non-2xx answers to an *APIError,
+// capturing rate-limit headers and counting per-venue metrics (GET /http/stats). Venue clients only
+// build and sign the request and decode their envelope. A read (GET) that fails on the network, with
+// a 429 or a 5xx is built, signed and sent again up to HTTP_RETRIES times (default 2, per venue e.g.
+// HTTP_RETRIES_OKX=0) after HTTP_RETRY_BACKOFF (default 200ms, doubling) or the venue's Retry-After
+// when it is at most HTTP_RETRY_MAX_WAIT (default 5s). Orders and other writes are sent once, so an
+// order is never placed twice.
+
+// RESTClient sends one venue's REST calls
+type RESTClient struct {
+	Exchange string
+	HTTP     *http.Client
+
+	// Before runs ahead of every attempt; an error ends the call unsent, e.g. while rate limited
+	Before func(req *http.Request) error
+	// After sees every response before it is read, e.g. to track used rate limits
+	After func(resp *http.Response)
+}
+
+// NewRESTClient returns a RESTClient on the exchange's shared transport
+func NewRESTClient(exchange string) *RESTClient {
+	return &RESTClient{Exchange: exchange, HTTP: NewHTTPClient(exchange)}
+}
+
+// RESTResponse is a read response
+type RESTResponse struct {
+	Exchange string
+	Status   int
+	Header   http.Header
+	Body     []byte
+}
+
+// APIError is a non-2xx answer from a venue
+type APIError struct {
+	Exchange   string
+	Status     int
+	Body       string
+	RetryAfter time.Duration // From the Retry-After header, 0 when absent
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s api error: status %d, body: %s", e.Exchange, e.Status, e.Body)
+}
+
+// RateLimited reports whether the venue refused the call for its rate limits
+func (e *APIError) RateLimited() bool {
+	return e.Status == http.StatusTooManyRequests || e.Status == http.StatusTeapot
+}
+
+// IsRateLimited reports whether err is a venue's rate-limit answer
+func IsRateLimited(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.RateLimited()
+}
+
+// OK reports whether the response has a 2xx status
+func (r *RESTResponse) OK() bool {
+	return r.Status >= 200 && r.Status < 300
+}
+
+// Err returns the response as an *APIError unless it has a 2xx status
+func (r *RESTResponse) Err() error {
+	if r.OK() {
+		return nil
+	}
+	return &APIError{Exchange: r.Exchange, Status: r.Status, Body: string(r.Body), RetryAfter: retryAfter(r.Header)}
+}
+
+// DecodeJSON unmarshals a 2xx response into result (skipped when nil), otherwise returns its *APIError
+func (r *RESTResponse) DecodeJSON(result interface{}) error {
+	if err := r.Err(); err != nil {
+		return err
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(r.Body, result); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return nil
+}
+
+// Get sends an unsigned GET
+func (c *RESTClient) Get(ctx context.Context, url string) (*RESTResponse, error) {
+	return c.Do(ctx, func(ctx context.Context) (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, "GET", url, nil)
+	})
+}
+
+// Do sends the request build returns and reads its response; build runs again for every retry so
+// timestamps, nonces and signatures are fresh
+// The error is only set when no response was read, a non-2xx status is left to the caller's decoding
+func (c *RESTClient) Do(ctx context.Context, build func(ctx context.Context) (*http.Request, error)) (*RESTResponse, error) {
+	retries := ReadRetries(c.Exchange)
+	backoff := config.GetDuration("HTTP_RETRY_BACKOFF", 200*time.Millisecond)
+	maxWait := config.GetDuration("HTTP_RETRY_MAX_WAIT", 5*time.Second)
+
+	for attempt := 0; ; attempt++ {
+		req, err := build(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		if c.Before != nil {
+			if err := c.Before(req); err != nil {
+				return nil, err
+			}
+		}
+		retryable := req.Method == "GET" && attempt < retries
+
+		resp, err := c.send(req)
+		wait := backoff << attempt
+		switch {
+		case err != nil && (!retryable || ctx.Err() != nil):
+			return nil, err
+		case err == nil && (!retryable || !retryStatus(resp.Status)):
+			return resp, nil
+		case err == nil:
+			if after := retryAfter(resp.Header); after > maxWait {
+				return resp, nil
+			} else if after > 0 {
+				wait = after
+			}
+		}
+
+		recordRetry(c.Exchange)
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			if err != nil {
+				return nil, err
+			}
+			return resp, nil
+		}
+	}
+}
+
+// ReadRetries returns how many times a read to the exchange that failed is sent again
+func ReadRetries(exchange string) int {
+	return config.GetInt(config.Key("HTTP_RETRIES", exchange), config.GetInt("HTTP_RETRIES", 2))
+}
+
+// send performs one attempt and records its metrics
+func (c *RESTClient) send(req *http.Request) (*RESTResponse, error) {
+	started := time.Now()
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		recordCall(c.Exchange, 0, nil, time.Since(started))
+		return nil, fmt.Errorf("http request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if c.After != nil {
+		c.After(resp)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	recordCall(c.Exchange, resp.StatusCode, resp.Header, time.Since(started))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return &RESTResponse{Exchange: c.Exchange, Status: resp.StatusCode, Header: resp.Header, Body: body}, nil
+}
+
+// retryStatus reports whether a read answered with status is worth sending again
+func retryStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+// retryAfter parses a Retry-After header given in seconds, 0 when absent
+func retryAfter(header http.Header) time.Duration {
+	seconds, err := strconv.Atoi(header.Get("Retry-After"))
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// HTTPStats are one venue's REST call metrics since start
+type HTTPStats struct {
+	Exchange      string            `json:"exchange"`
+	Requests      int               `json:"requests"`
+	Retries       int               `json:"retries"`
+	NetworkErrors int               `json:"network_errors"`
+	ClientErrors  int               `json:"client_errors"` // 4xx, rate limits included
+	ServerErrors  int               `json:"server_errors"`
+	RateLimited   int               `json:"rate_limited"` // 429 and 418
+	LastLimitedAt time.Time         `json:"last_rate_limited,omitempty"`
+	AvgLatencyMs  float64           `json:"avg_latency_ms"`
+	MaxLatencyMs  float64           `json:"max_latency_ms"`
+	LimitHeaders  map[string]string `json:"rate_limit_headers,omitempty"` // Last value of each rate-limit header
+	totalLatency  time.Duration
+}
+
+var (
+	httpStats   = make(map[string]*HTTPStats)
+	httpStatsMu sync.Mutex
+)
+
+// statsFor returns the exchange's metrics, creating them on first use
+// Callers hold httpStatsMu
+func statsFor(exchange string) *HTTPStats {
+	s, ok := httpStats[exchange]
+	if !ok {
+		s = &HTTPStats{Exchange: exchange}
+		httpStats[exchange] = s
+	}
+	return s
+}
+
+// recordCall counts one attempt, status 0 when it failed on the network
+func recordCall(exchange string, status int, header http.Header, latency time.Duration) {
+	httpStatsMu.Lock()
+	defer httpStatsMu.Unlock()
+
+	s := statsFor(exchange)
+	s.Requests++
+	s.totalLatency += latency
+	s.AvgLatencyMs = float64(s.totalLatency.Microseconds()) / 1000 / float64(s.Requests)
+	s.MaxLatencyMs = max(s.MaxLatencyMs, float64(latency.Microseconds())/1000)
+
+	switch {
+	case status == 0:
+		s.NetworkErrors++
+	case status >= 500:
+		s.ServerErrors++
+	case status >= 400:
+		s.ClientErrors++
+	}
+	if status == http.StatusTooManyRequests || status == http.StatusTeapot {
+		s.RateLimited++
+		s.LastLimitedAt = time.Now()
+	}
+
+	for name, values := range header {
+		if len(values) > 0 && isLimitHeader(name) {
+			if s.LimitHeaders == nil {
+				s.LimitHeaders = make(map[string]string)
+			}
+			s.LimitHeaders[name] = values[0]
+		}
+	}
+}
+
+// recordRetry counts a call sent again
+func recordRetry(exchange string) {
+	httpStatsMu.Lock()
+	defer httpStatsMu.Unlock()
+
+	statsFor(exchange).Retries++
+}
+
+// isLimitHeader reports whether a response header carries rate-limit usage, e.g. X-MBX-USED-WEIGHT-1M,
+// X-RateLimit-Remaining or Retry-After
+func isLimitHeader(name string) bool {
+	name = strings.ToLower(name)
+	for _, marker := range []string{"ratelimit", "rate-limit", "used-weight", "order-count", "retry-after"} {
+		if strings.Contains(name, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// RESTStats returns every venue's REST call metrics sorted by exchange
+func RESTStats() []HTTPStats {
+	httpStatsMu.Lock()
+	defer httpStatsMu.Unlock()
+
+	stats := make([]HTTPStats, 0, len(httpStats))
+	for _, s := range httpStats {
+		copied := *s
+		if s.LimitHeaders != nil {
+			copied.LimitHeaders = make(map[string]string, len(s.LimitHeaders))
+			for k, v := range s.LimitHeaders {
+				copied.LimitHeaders[k] = v
+			}
+		}
+		stats = append(stats, copied)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Exchange < stats[j].Exchange })
+	return stats
+}
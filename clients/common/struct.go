@@ -19,10 +19,51 @@ type ExchangeTradeClient interface {
 	// CloseFuturesShort closes the short futures position
 	CloseFuturesShort(ctx context.Context, pairName string) (*TradeResult, float64, error)
 
+	// GetSpotHolding returns the free base-asset balance held on the spot market for the pair
+	GetSpotHolding(ctx context.Context, pairName string) (float64, error)
+
+	// GetFuturesPosition returns the signed open futures position size for the pair in base-asset
+	// units, converted from contracts on venues that size in contracts (negative = short)
+	GetFuturesPosition(ctx context.Context, pairName string) (float64, error)
+
 	// GetName returns the exchange name
 	GetName() string
 }
 
+// Prewarmer is implemented by clients that can open pooled connections ahead of the first order
+type Prewarmer interface {
+	Prewarm(ctx context.Context)
+}
+
+// BalanceClient is implemented by clients that report their free USDT per market
+type BalanceClient interface {
+	// GetUSDTBalance returns the available USDT on the given market ("spot" or "futures")
+	GetUSDTBalance(ctx context.Context, market string) (float64, error)
+}
+
+// LimitOrderClient is implemented by venues that support resting (maker) limit orders
+type LimitOrderClient interface {
+	BalanceClient
+
+	// PlaceLimitOrder places a post-only limit order and returns its order ID
+	PlaceLimitOrder(ctx context.Context, pairName string, market string, side string, price float64, quantity float64) (string, error)
+
+	// GetLimitOrder returns the fill state of a limit order; done is true once it is filled or cancelled
+	GetLimitOrder(ctx context.Context, pairName string, market string, orderID string) (result *TradeResult, done bool, err error)
+
+	// CancelLimitOrder cancels a resting limit order
+	CancelLimitOrder(ctx context.Context, pairName string, market string, orderID string) error
+}
+
+// IOCOrderClient is implemented by venues that support immediate-or-cancel limit orders
+type IOCOrderClient interface {
+	BalanceClient
+
+	// PlaceIOCOrder sends a limit order that fills what it can at price or better at once and
+	// cancels the rest; the result holds the filled part, which may be nothing
+	PlaceIOCOrder(ctx context.Context, pairName string, market string, side string, price float64, quantity float64) (*TradeResult, error)
+}
+
 // TradeResult contains the result of a trade operation
 type TradeResult struct {
 	OrderID       string  // Exchange's order ID
@@ -30,6 +71,8 @@ type TradeResult struct {
 	ExecutedQty   float64 // Quantity executed
 	Fee           float64 // Trading fee paid
 	Success       bool    // Whether the trade was successful
+	MakerQty      float64 // Part of ExecutedQty filled by a resting maker order
+	Rebate        float64 // Maker rebate the venue credited on the fill, in USDT, not netted from Fee
 }
 
 // Position tracks an open position
@@ -44,14 +87,54 @@ type Position struct {
 	ExchangeName string
 }
 
+// MarginShortClient is implemented by clients that can short spot by borrowing on margin
+type MarginShortClient interface {
+	// PutMarginShort borrows the base asset and sells it for USDT
+	PutMarginShort(ctx context.Context, pairName string, amountUSDT float64) (*TradeResult, error)
+
+	// CloseMarginShort buys back the borrowed asset and repays the loan
+	CloseMarginShort(ctx context.Context, pairName string) (*TradeResult, float64, error)
+}
+
+// InventoryClient is implemented by venues that can trade the reverse direction against
+// standing spot inventory: sell held spot and go long the perp
+type InventoryClient interface {
+	// SellSpot sells quantity of the base asset for USDT
+	SellSpot(ctx context.Context, pairName string, quantity float64) (*TradeResult, error)
+
+	// BuySpot buys quantity of the base asset with USDT
+	BuySpot(ctx context.Context, pairName string, quantity float64) (*TradeResult, error)
+
+	// PutFuturesLong opens a long position in the futures/perpetual market
+	PutFuturesLong(ctx context.Context, pairName string, amountUSDT float64) (*TradeResult, error)
+
+	// CloseFuturesLong closes the long futures position
+	CloseFuturesLong(ctx context.Context, pairName string) (*TradeResult, float64, error)
+}
+
+// PartialCloseClient is implemented by venues that can close part of a forward position
+// by a base quantity, leaving the rest open
+// Reduce orders must not move the USDT balance snapshot (SetBalance) taken on open, so the final
+// close's profit covers the whole position
+type PartialCloseClient interface {
+	// ReduceSpotLong sells quantity of the held spot long
+	ReduceSpotLong(ctx context.Context, pairName string, quantity float64) (*TradeResult, error)
+
+	// ReduceFuturesShort buys back quantity of the futures short with a reduce-only order
+	ReduceFuturesShort(ctx context.Context, pairName string, quantity float64) (*TradeResult, error)
+}
+
 type ExchangeType string
 
 const (
-	Binance  ExchangeType = "binance"
-	Bitget   ExchangeType = "bitget"
-	Whitebit ExchangeType = "whitebit"
-	Gate     ExchangeType = "gate"
-	Okx      ExchangeType = "okx"
+	Binance     ExchangeType = "binance"
+	Bitget      ExchangeType = "bitget"
+	Whitebit    ExchangeType = "whitebit"
+	Gate        ExchangeType = "gate"
+	Okx         ExchangeType = "okx"
+	Kraken      ExchangeType = "kraken"
+	Cryptocom   ExchangeType = "cryptocom"
+	Hyperliquid ExchangeType = "hyperliquid"
 )
 
 type OrderType string
@@ -61,6 +144,14 @@ const (
 	CloseSpotLong     OrderType = "CloseSpotLong"
 	PutFuturesShort   OrderType = "PutFuturesShort"
 	CloseFuturesShort OrderType = "CloseFuturesShort"
+	PutMarginShort    OrderType = "PutMarginShort"
+	CloseMarginShort  OrderType = "CloseMarginShort"
+	SellSpotInventory OrderType = "SellSpotInventory"
+	BuySpotInventory  OrderType = "BuySpotInventory"
+	PutFuturesLong    OrderType = "PutFuturesLong"
+	CloseFuturesLong  OrderType = "CloseFuturesLong"
+	PutInverseShort   OrderType = "PutInverseShort"
+	CloseInverseShort OrderType = "CloseInverseShort"
 )
 
 var (
@@ -0,0 +1,82 @@
+package common
+
+import (
+	"strconv"
+	"strings"
+
+	"arbitrage.trade/config"
+)
+
+// Futures symbol overrides
+//
+// Most venues list a pair's perp under the spot name, but some contracts differ: Binance quotes
+// low-priced assets per 1000 units (1000PEPEUSDT) and renamed ones under a new ticker (LUNA2USDT).
+// Defaults cover the known cases; FUTURES_SYMBOLS_<EXCHANGE> adds or replaces entries as a comma
+// separated list of pair=SYMBOL[:multiplier], e.g. "pepe-usdt=1000PEPEUSDT:1000,luna-usdt=LUNA2USDT".
+// The multiplier is how many base units one contract unit stands for; clients and feeds divide
+// prices and multiply quantities by it so the rest of the system keeps working in base units.
+
+// FuturesSymbol is a venue's perp symbol for a pair
+type FuturesSymbol struct {
+	Symbol     string
+	Multiplier float64
+}
+
+var defaultFuturesSymbols = map[string]map[string]FuturesSymbol{
+	"binance": {
+		"pepe-usdt":  {Symbol: "1000PEPEUSDT", Multiplier: 1000},
+		"shib-usdt":  {Symbol: "1000SHIBUSDT", Multiplier: 1000},
+		"floki-usdt": {Symbol: "1000FLOKIUSDT", Multiplier: 1000},
+		"bonk-usdt":  {Symbol: "1000BONKUSDT", Multiplier: 1000},
+		"sats-usdt":  {Symbol: "1000SATSUSDT", Multiplier: 1000},
+		"lunc-usdt":  {Symbol: "1000LUNCUSDT", Multiplier: 1000},
+		"xec-usdt":   {Symbol: "1000XECUSDT", Multiplier: 1000},
+		"luna-usdt":  {Symbol: "LUNA2USDT", Multiplier: 1},
+	},
+	"hyperliquid": {
+		"pepe-usdt":  {Symbol: "kPEPE", Multiplier: 1000},
+		"shib-usdt":  {Symbol: "kSHIB", Multiplier: 1000},
+		"floki-usdt": {Symbol: "kFLOKI", Multiplier: 1000},
+		"bonk-usdt":  {Symbol: "kBONK", Multiplier: 1000},
+		"lunc-usdt":  {Symbol: "kLUNC", Multiplier: 1000},
+	},
+}
+
+// FuturesSymbolOverride returns the venue's perp symbol for a pair when it differs from the spot naming
+func FuturesSymbolOverride(exchange, pairName string) (FuturesSymbol, bool) {
+	pairName = strings.ToLower(pairName)
+
+	for _, entry := range strings.Split(config.GetString(config.Key("FUTURES_SYMBOLS", exchange), ""), ",") {
+		pair, value, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || !strings.EqualFold(strings.TrimSpace(pair), pairName) {
+			continue
+		}
+		symbol, multiplier, _ := strings.Cut(strings.TrimSpace(value), ":")
+		m, err := strconv.ParseFloat(multiplier, 64)
+		if err != nil || !IsPositive(m) {
+			m = 1
+		}
+		return FuturesSymbol{Symbol: strings.ToUpper(symbol), Multiplier: m}, true
+	}
+
+	override, ok := defaultFuturesSymbols[exchange][pairName]
+	return override, ok
+}
+
+// FuturesMultiplier returns how many base units one unit of the venue's perp stands for, 1 without an override
+func FuturesMultiplier(exchange, pairName string) float64 {
+	if override, ok := FuturesSymbolOverride(exchange, pairName); ok {
+		return override.Multiplier
+	}
+	return 1
+}
+
+// ToBaseUnits converts a perp fill quoted in contract units to base units
+func ToBaseUnits(result *TradeResult, multiplier float64) *TradeResult {
+	if result == nil || multiplier == 1 || !IsPositive(multiplier) {
+		return result
+	}
+	result.ExecutedQty *= multiplier
+	result.ExecutedPrice /= multiplier
+	return result
+}
@@ -0,0 +1,64 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+var tradeSeq atomic.Uint64
+
+type tradeIDKey struct{}
+
+// NewTradeID returns a unique ID for an opportunity detected at the given time, e.g. "20261016T120102.345Z-01f"
+// It is carried from detection through the position's orders, Redis events and stored records
+func NewTradeID(at time.Time) string {
+	return fmt.Sprintf("%s-%03x", at.UTC().Format("20060102T150405.000Z"), tradeSeq.Add(1)%0x1000)
+}
+
+// WithTradeID returns a context carrying the trade ID, so orders placed under it can be traced back
+func WithTradeID(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, tradeIDKey{}, id)
+}
+
+// TradeID returns the trade ID the context carries, "" when none
+func TradeID(ctx context.Context) string {
+	id, _ := ctx.Value(tradeIDKey{}).(string)
+	return id
+}
+
+type strategyKey struct{}
+
+// StrategyTag is the strategy and config version a trade runs under, so its P&L can be attributed per variant
+type StrategyTag struct {
+	Strategy      string
+	ConfigVersion string
+	Experiment    string // A/B experiment the trade was assigned in, "" outside one
+	Variant       string // Execution configuration the experiment assigned
+}
+
+// String returns the tag as "strategy" or "strategy@version", "" when untagged
+func (t StrategyTag) String() string {
+	if t.ConfigVersion == "" {
+		return t.Strategy
+	}
+	return t.Strategy + "@" + t.ConfigVersion
+}
+
+// WithStrategy returns a context carrying the strategy tag, so orders placed under it are attributed to it
+func WithStrategy(ctx context.Context, tag StrategyTag) context.Context {
+	if tag == (StrategyTag{}) {
+		return ctx
+	}
+	return context.WithValue(ctx, strategyKey{}, tag)
+}
+
+// Strategy returns the strategy tag the context carries, empty when none
+func Strategy(ctx context.Context) StrategyTag {
+	tag, _ := ctx.Value(strategyKey{}).(StrategyTag)
+	return tag
+}
@@ -0,0 +1,36 @@
+package common
+
+import "context"
+
+// NetworkStatus is whether one chain accepts deposits and withdrawals of an asset
+type NetworkStatus struct {
+	Network     string
+	CanDeposit  bool
+	CanWithdraw bool
+	WithdrawFee float64 // In the asset, 0 when the venue doesn't report it
+	WithdrawMin float64 // In the asset, 0 when the venue doesn't report it
+}
+
+// AssetStatus is a venue's deposit/withdrawal state for one asset
+type AssetStatus struct {
+	Asset       string
+	CanDeposit  bool // At least one network accepts deposits
+	CanWithdraw bool // At least one network allows withdrawals
+	Networks    []NetworkStatus
+}
+
+// WalletStatusClient is implemented by clients that can report currency deposit/withdrawal status
+type WalletStatusClient interface {
+	// GetAssetStatus returns whether the asset (e.g. "USDT", "BTC") can be deposited and withdrawn
+	GetAssetStatus(ctx context.Context, asset string) (*AssetStatus, error)
+}
+
+// NewAssetStatus derives an asset's overall status from its networks
+func NewAssetStatus(asset string, networks []NetworkStatus) *AssetStatus {
+	status := &AssetStatus{Asset: asset, Networks: networks}
+	for _, n := range networks {
+		status.CanDeposit = status.CanDeposit || n.CanDeposit
+		status.CanWithdraw = status.CanWithdraw || n.CanWithdraw
+	}
+	return status
+}
@@ -0,0 +1,78 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"arbitrage.trade/config"
+)
+
+// WebSocket guards
+//
+// Every WebSocket consumer (the signal feed of each pair, direct venue feeds, the legacy main feed)
+// caps what one connection may send: messages above WS_READ_LIMIT bytes (default 1MB) and more than
+// WS_MAX_MESSAGES_PER_SEC messages in a second (default 0, unlimited) drop the connection, which then
+// waits WS_ABUSE_COOLDOWN (default 30s) before reconnecting instead of the usual quick retry. Each
+// setting can be overridden per consumer with a _SIGNAL, _VENUE or _MAIN suffix, e.g.
+// WS_READ_LIMIT_SIGNAL=4194304.
+
+// ErrWSAbuse marks a connection dropped for exceeding its limits
+var ErrWSAbuse = errors.New("websocket limits exceeded")
+
+// WSLimits are one WebSocket consumer's protective limits
+type WSLimits struct {
+	Consumer      string
+	ReadLimit     int64         // Largest message in bytes
+	MaxPerSecond  int           // Messages per second, 0 unlimited
+	AbuseCooldown time.Duration // Wait before reconnecting after a limit was exceeded
+}
+
+// WSLimitsFor returns the limits of a consumer ("signal", "venue", "main")
+func WSLimitsFor(consumer string) WSLimits {
+	readLimit := config.GetInt("WS_READ_LIMIT", 1<<20)
+	maxPerSecond := config.GetInt("WS_MAX_MESSAGES_PER_SEC", 0)
+	cooldown := config.GetDuration("WS_ABUSE_COOLDOWN", 30*time.Second)
+
+	return WSLimits{
+		Consumer:      consumer,
+		ReadLimit:     int64(config.GetInt(config.Key("WS_READ_LIMIT", consumer), readLimit)),
+		MaxPerSecond:  config.GetInt(config.Key("WS_MAX_MESSAGES_PER_SEC", consumer), maxPerSecond),
+		AbuseCooldown: config.GetDuration(config.Key("WS_ABUSE_COOLDOWN", consumer), cooldown),
+	}
+}
+
+// WSRateGuard counts a connection's messages per second
+type WSRateGuard struct {
+	limits      WSLimits
+	windowStart time.Time
+	count       int
+}
+
+// NewRateGuard returns a message counter for one connection
+func (l WSLimits) NewRateGuard() *WSRateGuard {
+	return &WSRateGuard{limits: l}
+}
+
+// Received counts a message and returns an ErrWSAbuse error once the second's count exceeds the limit
+func (g *WSRateGuard) Received(now time.Time) error {
+	if g.limits.MaxPerSecond <= 0 {
+		return nil
+	}
+	if now.Sub(g.windowStart) >= time.Second {
+		g.windowStart, g.count = now, 0
+	}
+	g.count++
+	if g.count > g.limits.MaxPerSecond {
+		return fmt.Errorf("%w: more than %d messages per second", ErrWSAbuse, g.limits.MaxPerSecond)
+	}
+	return nil
+}
+
+// ReconnectDelay returns how long to wait before reconnecting after a connection ended with err
+func (l WSLimits) ReconnectDelay(err error, normal time.Duration) time.Duration {
+	if errors.Is(err, ErrWSAbuse) && l.AbuseCooldown > normal {
+		return l.AbuseCooldown
+	}
+	return normal
+}
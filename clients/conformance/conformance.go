@@ -0,0 +1,375 @@
+package conformance
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"sync"
+
+	"arbitrage.trade/clients/common"
+)
+
+// Exchange adapter conformance suite
+//
+// Checks an ExchangeTradeClient against the behaviour the executor relies on, using a cassette of the
+// exchange's HTTP interactions as the mock exchange: under HTTP_FIXTURES_MODE=replay every request is
+// answered from the cassette and unmatched requests fail, so changed rounding or request building
+// shows up as a missing fixture. Running the suite with HTTP_FIXTURES_MODE=record against the live
+// venue (flat account, real orders when Orders is set) produces a cassette that replays in order.
+//
+// Cases:
+//   - balances: spot holding, perp position and USDT balances parse to finite values
+//   - open_close: an open/close cycle of both legs returns complete, finite fills, and perp and
+//     spot-sell quantities respect the pair precision (only with Orders); perp-only venues must
+//     reject the spot leg instead
+//   - reduce_only: closing legs that are already closed fails instead of trading (only with Orders)
+//   - error_mapping: HTTP errors and malformed bodies surface as errors, never as zero values
+//     (replay only, through injected faults)
+//   - retries: a read failing once with a 503 is built, signed and sent again when the venue reads
+//     over GET, and a perp order lost on the network is sent once and fails (replay only)
+//
+// What differs between adapters beyond their client is kept in the venues table. Run is called from
+// the "conformance" command, or from an adapter's Go test through conformancetest.Run.
+
+// Reporter receives the suite's results; *testing.T implements it
+type Reporter interface {
+	Errorf(format string, args ...interface{})
+	Logf(format string, args ...interface{})
+}
+
+// Suite describes the adapter under test
+type Suite struct {
+	Exchange   string
+	Pair       string
+	AmountUSDT float64                           // Notional of the cassette's open/close cycle
+	Orders     bool                              // Whether the cassette covers an open/close cycle
+	New        func() common.ExchangeTradeClient // Builds a fresh client, so cached state does not leak between cases
+}
+
+// venue is what the suite knows of an adapter besides its client
+type venue struct {
+	perpOnly      bool   // Trades only the perp leg
+	perpOrderPath string // URL path perp orders are sent to, where the retries case loses one
+}
+
+var venues = map[string]venue{
+	"binance":     {perpOrderPath: "/fapi/v1/order"},
+	"bitget":      {perpOrderPath: "/api/v2/mix/order/place-order"},
+	"cryptocom":   {perpOrderPath: "/exchange/v1/private/create-order"},
+	"gate":        {perpOrderPath: "/api/v4/futures/usdt/orders"},
+	"hyperliquid": {perpOnly: true, perpOrderPath: "/exchange"},
+	"kraken":      {perpOrderPath: "/derivatives/api/v3/sendorder"},
+	"okx":         {perpOrderPath: "/api/v5/trade/order"},
+	"whitebit":    {perpOrderPath: "/api/v4/order/collateral/market"},
+}
+
+// faults are the injected responses every call must turn into an error
+var faults = []struct {
+	name   string
+	status int
+	body   string
+}{
+	{"http 500", http.StatusInternalServerError, `{"code":-1,"msg":"conformance fault"}`},
+	{"http 400", http.StatusBadRequest, `{"code":-1,"msg":"conformance fault"}`},
+	{"malformed body", http.StatusOK, `conformance fault`},
+}
+
+// Run executes every case of the suite
+func Run(ctx context.Context, r Reporter, s Suite) {
+	checkBalances(ctx, r, s)
+
+	if s.Orders {
+		checkOpenClose(ctx, r, s)
+	}
+
+	if common.FixturesMode() == "replay" {
+		checkErrorMapping(ctx, r, s)
+		checkRetries(ctx, r, s)
+	}
+}
+
+func checkBalances(ctx context.Context, r Reporter, s Suite) {
+	client := s.New()
+
+	holding, err := client.GetSpotHolding(ctx, s.Pair)
+	checkValue(r, "balances", "GetSpotHolding", holding, err, false)
+
+	position, err := client.GetFuturesPosition(ctx, s.Pair)
+	checkValue(r, "balances", "GetFuturesPosition", position, err, true)
+
+	balanceClient, ok := client.(common.BalanceClient)
+	if !ok {
+		r.Logf("balances: %s does not report USDT balances", s.Exchange)
+		return
+	}
+	for _, market := range []string{"spot", "futures"} {
+		balance, err := balanceClient.GetUSDTBalance(ctx, market)
+		if err != nil {
+			// Venues without a market (perp-only DEXes) reject it
+			r.Logf("balances: GetUSDTBalance %s: %v", market, err)
+			continue
+		}
+		checkValue(r, "balances", "GetUSDTBalance "+market, balance, nil, false)
+	}
+}
+
+func checkOpenClose(ctx context.Context, r Reporter, s Suite) {
+	client := s.New()
+
+	spotOpened := false
+	if venues[s.Exchange].perpOnly {
+		if result, err := client.PutSpotLong(ctx, s.Pair, s.AmountUSDT); err == nil || result != nil {
+			r.Errorf("open_close: PutSpotLong on a perp-only venue returned %+v, %v, want an error", result, err)
+		}
+	} else {
+		spotOpen, err := client.PutSpotLong(ctx, s.Pair, s.AmountUSDT)
+		spotOpened = checkFill(r, "PutSpotLong", spotOpen, err, s.Pair, false)
+	}
+
+	perpOpen, err := client.PutFuturesShort(ctx, s.Pair, s.AmountUSDT)
+	perpOpened := checkFill(r, "PutFuturesShort", perpOpen, err, s.Pair, true)
+
+	if perpOpened {
+		position, err := client.GetFuturesPosition(ctx, s.Pair)
+		if err != nil || !common.IsNegative(position) {
+			r.Errorf("open_close: GetFuturesPosition after opening the short = %v, %v, want a negative size", position, err)
+		}
+
+		perpClose, _, err := client.CloseFuturesShort(ctx, s.Pair)
+		checkFill(r, "CloseFuturesShort", perpClose, err, s.Pair, true)
+
+		position, err = client.GetFuturesPosition(ctx, s.Pair)
+		if err != nil || common.NotEqual(position, 0) {
+			r.Errorf("open_close: GetFuturesPosition after closing = %v, %v, want 0", position, err)
+		}
+	}
+
+	if spotOpened {
+		spotClose, _, err := client.CloseSpotLong(ctx, s.Pair, s.AmountUSDT)
+		checkFill(r, "CloseSpotLong", spotClose, err, s.Pair, true)
+	}
+
+	// Close retries must be idempotent: a leg that is already flat is never traded again
+	if result, _, err := client.CloseFuturesShort(ctx, s.Pair); err == nil || result != nil {
+		r.Errorf("reduce_only: CloseFuturesShort on a flat position returned %+v, %v, want an error and no fill", result, err)
+	}
+	if result, _, err := client.CloseSpotLong(ctx, s.Pair, s.AmountUSDT); err == nil || result != nil {
+		r.Errorf("reduce_only: CloseSpotLong on a closed position returned %+v, %v, want an error and no fill", result, err)
+	}
+}
+
+func checkErrorMapping(ctx context.Context, r Reporter, s Suite) {
+	for _, fault := range faults {
+		client := s.New()
+		failures := 0
+
+		// expectError runs one call under the fault; calls that sent no request may succeed
+		expectError := func(call string, run func() error) {
+			common.SetFixtureFault(s.Exchange, fault.status, fault.body)
+			err := run()
+			hits := common.ClearFixtureFault(s.Exchange)
+			if err == nil && hits > 0 {
+				r.Errorf("error_mapping: %s with %s returned no error", call, fault.name)
+				failures++
+			}
+		}
+
+		expectError("GetSpotHolding", func() error {
+			_, err := client.GetSpotHolding(ctx, s.Pair)
+			return err
+		})
+		expectError("GetFuturesPosition", func() error {
+			_, err := client.GetFuturesPosition(ctx, s.Pair)
+			return err
+		})
+		if balanceClient, ok := client.(common.BalanceClient); ok {
+			expectError("GetUSDTBalance", func() error {
+				_, err := balanceClient.GetUSDTBalance(ctx, "futures")
+				return err
+			})
+		}
+		expectError("PutSpotLong", func() error {
+			result, err := client.PutSpotLong(ctx, s.Pair, s.AmountUSDT)
+			if err == nil && result != nil && !result.Success {
+				return errUnsuccessful
+			}
+			return err
+		})
+		expectError("PutFuturesShort", func() error {
+			result, err := client.PutFuturesShort(ctx, s.Pair, s.AmountUSDT)
+			if err == nil && result != nil && !result.Success {
+				return errUnsuccessful
+			}
+			return err
+		})
+
+		if failures == 0 {
+			r.Logf("error_mapping: %s surfaces as errors", fault.name)
+		}
+	}
+}
+
+// retryFault fails a read once with a 503 asking for a retry a second later, so venues signing
+// whole-second timestamps sign the retry differently too
+var retryFault = common.FixtureFault{
+	Status:     http.StatusServiceUnavailable,
+	Body:       `{"code":-1,"msg":"conformance fault"}`,
+	RetryAfter: 1,
+	Once:       true,
+}
+
+// sentRequest is a request the retries case saw replayed
+type sentRequest struct {
+	method, path, signature string
+}
+
+func checkRetries(ctx context.Context, r Reporter, s Suite) {
+	var mu sync.Mutex
+	var sent []sentRequest
+	var previous common.FixtureObserver
+	previous = common.SetFixtureObserver(s.Exchange, func(req *http.Request, body []byte) {
+		mu.Lock()
+		sent = append(sent, sentRequest{req.Method, req.URL.Path, common.FixtureSignature(req, body)})
+		mu.Unlock()
+		if previous != nil {
+			previous(req, body)
+		}
+	})
+	defer common.SetFixtureObserver(s.Exchange, previous)
+
+	// sentDuring returns the requests run sent
+	sentDuring := func(run func()) []sentRequest {
+		mu.Lock()
+		from := len(sent)
+		mu.Unlock()
+		run()
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]sentRequest(nil), sent[from:]...)
+	}
+
+	client := s.New()
+
+	var position float64
+	var err error
+	read := sentDuring(func() {
+		common.InjectFixtureFault(s.Exchange, retryFault)
+		position, err = client.GetFuturesPosition(ctx, s.Pair)
+		common.ClearFixtureFault(s.Exchange)
+	})
+	switch {
+	case len(read) == 0:
+		r.Logf("retries: GetFuturesPosition sent no request")
+	case read[0].method != "GET":
+		// Only GETs are retried; a read sent as POST fails like a write
+		if err == nil {
+			r.Errorf("retries: GetFuturesPosition returned %v through a failed %s %s", position, read[0].method, read[0].path)
+		}
+		if countSent(read[1:], read[0].method, read[0].path) > 0 {
+			r.Errorf("retries: %s %s was sent again", read[0].method, read[0].path)
+		} else if err != nil {
+			r.Logf("retries: %s %s is read over %s, not sent again", s.Exchange, read[0].path, read[0].method)
+		}
+	case common.ReadRetries(s.Exchange) == 0:
+		r.Logf("retries: reads are not retried with HTTP_RETRIES=0")
+	case countSent(read, read[0].method, read[0].path) < 2:
+		r.Errorf("retries: GET %s was not sent again after a 503", read[0].path)
+	case read[0].signature != "" && read[1].signature == read[0].signature:
+		r.Errorf("retries: GET %s was sent again with its first signature %s", read[0].path, read[0].signature)
+	default:
+		checkValue(r, "retries", "GetFuturesPosition after a retried read", position, err, true)
+	}
+
+	path := venues[s.Exchange].perpOrderPath
+	if !s.Orders || path == "" {
+		return
+	}
+
+	var result *common.TradeResult
+	var hits int
+	orders := countSent(sentDuring(func() {
+		common.InjectFixtureFault(s.Exchange, common.FixtureFault{Method: "POST", Path: path, Once: true})
+		result, err = client.PutFuturesShort(ctx, s.Pair, s.AmountUSDT)
+		hits = common.ClearFixtureFault(s.Exchange)
+	}), "POST", path)
+	switch {
+	case hits == 0:
+		r.Errorf("retries: PutFuturesShort sent no order to %s", path)
+	case orders > 1:
+		r.Errorf("retries: PutFuturesShort sent its order %d times after a network error, want once", orders)
+	case err == nil && result != nil && result.Success:
+		r.Errorf("retries: PutFuturesShort returned %+v for an order lost on the network, want an error", *result)
+	default:
+		r.Logf("retries: an order lost on the network is sent once and fails")
+	}
+}
+
+// countSent counts the requests with the method and path
+func countSent(requests []sentRequest, method, path string) int {
+	n := 0
+	for _, req := range requests {
+		if req.method == method && req.path == path {
+			n++
+		}
+	}
+	return n
+}
+
+// errUnsuccessful marks a call that reported failure through TradeResult.Success instead of an error
+var errUnsuccessful = errors.New("unsuccessful result")
+
+// checkValue reports a read call that failed or returned a non-finite (or unexpectedly negative) value
+func checkValue(r Reporter, name, call string, v float64, err error, allowNegative bool) {
+	switch {
+	case err != nil:
+		r.Errorf("%s: %s: %v", name, call, err)
+	case math.IsNaN(v) || math.IsInf(v, 0):
+		r.Errorf("%s: %s = %v, want a finite value", name, call, v)
+	case !allowNegative && common.IsNegative(v):
+		r.Errorf("%s: %s = %v, want >= 0", name, call, v)
+	default:
+		r.Logf("%s: %s = %v", name, call, v)
+	}
+}
+
+// checkFill reports an order call whose fill is missing, incomplete or off the pair's precision
+// Returns whether the order filled, so the cycle can close what was opened
+func checkFill(r Reporter, call string, result *common.TradeResult, err error, pair string, rounded bool) bool {
+	if err != nil {
+		r.Errorf("open_close: %s: %v", call, err)
+		return false
+	}
+	if result == nil {
+		r.Errorf("open_close: %s returned no fill", call)
+		return false
+	}
+
+	finite := func(v float64) bool { return !math.IsNaN(v) && !math.IsInf(v, 0) }
+	ok := true
+	if !result.Success {
+		r.Errorf("open_close: %s was not successful", call)
+		ok = false
+	}
+	if !finite(result.ExecutedQty) || !common.IsPositive(result.ExecutedQty) {
+		r.Errorf("open_close: %s executed quantity %v, want > 0", call, result.ExecutedQty)
+		ok = false
+	}
+	if !finite(result.ExecutedPrice) || !common.IsPositive(result.ExecutedPrice) {
+		r.Errorf("open_close: %s executed price %v, want > 0", call, result.ExecutedPrice)
+		ok = false
+	}
+	if !finite(result.Fee) || common.IsNegative(result.Fee) {
+		r.Errorf("open_close: %s fee %v, want >= 0", call, result.Fee)
+		ok = false
+	}
+	if rounded && common.NotEqual(common.RoundQuantity(result.ExecutedQty, pair), result.ExecutedQty) {
+		r.Errorf("open_close: %s executed quantity %v is off the %s precision", call, result.ExecutedQty, pair)
+		ok = false
+	}
+
+	if ok {
+		r.Logf("open_close: %s filled %v @ %v, fee %v", call, result.ExecutedQty, result.ExecutedPrice, result.Fee)
+	}
+	return result.ExecutedQty > 0
+}
@@ -0,0 +1,32 @@
+package conformancetest
+
+import (
+	"context"
+	"testing"
+
+	"arbitrage.trade/clients/conformance"
+	"arbitrage.trade/clients/fixturetest"
+)
+
+// Conformance suite as a Go test
+//
+// Run replays an adapter's conformance cassette, testdata/conformance/<exchange>.json, as the mock
+// exchange: the suite's requests are answered from it in order, a request it has no interaction for
+// fails the case that sent it, and an interaction the suite never requested fails the test. Each
+// adapter package calls it from its own conformance_test.go with what is particular to it, its client
+// and the notional it trades; the cassette covers the whole suite, orders included. Like the other
+// testdata cassettes it is synthetic (see clients/fixturetest), so the suite checks an adapter against
+// a model of its venue; recording the cassette against the live venue checks the venue itself.
+
+// Run runs every case of the suite against the exchange's cassette
+func Run(t *testing.T, s conformance.Suite) {
+	t.Helper()
+	fixturetest.Replay(t, s.Exchange, "testdata/conformance")
+	// The retries case needs failed reads sent again; no cassette response asks for a retry
+	t.Setenv("HTTP_RETRIES", "1")
+	t.Setenv("HTTP_RETRY_BACKOFF", "1ms")
+
+	s.Orders = true
+	conformance.Run(context.Background(), t, s)
+	fixturetest.CheckReplayed(t, s.Exchange)
+}
@@ -0,0 +1,122 @@
+package cryptocom
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/clients/fixturetest"
+)
+
+const (
+	fixtureKey    = "fixture-key"
+	fixtureSecret = "fixture-secret"
+	fixturePair   = "xrp-usdt"
+)
+
+// TestFixtureReplay replays an open/close cycle of both legs from testdata/cryptocom.json
+func TestFixtureReplay(t *testing.T) {
+	rec := fixturetest.Replay(t, "cryptocom", "testdata")
+	client := NewCryptocomClient(fixtureKey, fixtureSecret)
+	ctx := context.Background()
+
+	if holding, err := client.GetSpotHolding(ctx, fixturePair); err != nil || holding != 0 {
+		t.Errorf("GetSpotHolding = %v, %v, want 0", holding, err)
+	}
+	if balance, err := client.GetUSDTBalance(ctx, "spot"); err != nil || common.NotEqual(balance, 1000.5) {
+		t.Errorf("GetUSDTBalance spot = %v, %v, want 1000.5", balance, err)
+	}
+
+	// The buy's fee is charged in XRP and reported in USDT
+	spot, err := client.PutSpotLong(ctx, fixturePair, 20)
+	fixturetest.CheckResult(t, "PutSpotLong", spot, err, common.TradeResult{
+		OrderID: "6530219599901166471", ExecutedPrice: 0.55, ExecutedQty: 36.3, Fee: 0.019965, Success: true,
+	})
+
+	perp, err := client.PutFuturesShort(ctx, fixturePair, 20)
+	fixturetest.CheckResult(t, "PutFuturesShort", perp, err, common.TradeResult{
+		OrderID: "6530219599901166472", ExecutedPrice: 0.5498, ExecutedQty: 36.3, Fee: 0.00997887, Success: true,
+	})
+
+	if position, err := client.GetFuturesPosition(ctx, fixturePair); err != nil || common.NotEqual(position, -36.3) {
+		t.Errorf("GetFuturesPosition = %v, %v, want -36.3", position, err)
+	}
+
+	// Profit comes from the position's entry and the closing fill, less the closing fee
+	perpClose, profit, err := client.CloseFuturesShort(ctx, fixturePair)
+	fixturetest.CheckResult(t, "CloseFuturesShort", perpClose, err, common.TradeResult{
+		OrderID: "6530219599901166473", ExecutedPrice: 0.5502, ExecutedQty: 36.3, Fee: 0.00998613, Success: true,
+	})
+	if want := (0.5498-0.5502)*36.3 - 0.00998613; common.NotEqual(profit, want) {
+		t.Errorf("CloseFuturesShort profit = %v, want %v", profit, want)
+	}
+	if position, err := client.GetFuturesPosition(ctx, fixturePair); err != nil || position != 0 {
+		t.Errorf("GetFuturesPosition after close = %v, %v, want 0", position, err)
+	}
+
+	spotClose, _, err := client.CloseSpotLong(ctx, fixturePair, 20)
+	fixturetest.CheckResult(t, "CloseSpotLong", spotClose, err, common.TradeResult{
+		OrderID: "6530219599901166474", ExecutedPrice: 0.5505, ExecutedQty: 36.2, Fee: 0.0199281, Success: true,
+	})
+
+	checkSignatures(t, rec)
+	fixturetest.CheckReplayed(t, "cryptocom")
+}
+
+// checkSignatures verifies every private request carries the key, a strictly increasing id and a hex
+// HMAC-SHA256 of method + id + api_key + sorted params + nonce
+func checkSignatures(t *testing.T, rec *fixturetest.Recorder) {
+	t.Helper()
+	signed, lastID := 0, int64(0)
+	for _, req := range rec.Requests() {
+		if req.Method != "POST" {
+			continue
+		}
+		signed++
+
+		var body struct {
+			ID     int64             `json:"id"`
+			Method string            `json:"method"`
+			APIKey string            `json:"api_key"`
+			Params map[string]string `json:"params"`
+			Nonce  int64             `json:"nonce"`
+			Sig    string            `json:"sig"`
+		}
+		if err := json.Unmarshal([]byte(req.Body), &body); err != nil {
+			t.Fatalf("%s body %q: %v", req.URL.Path, req.Body, err)
+		}
+		if body.APIKey != fixtureKey || !strings.HasSuffix(req.URL.Path, "/"+body.Method) {
+			t.Errorf("%s body %s has the wrong key or method", req.URL.Path, req.Body)
+		}
+		if body.ID <= lastID {
+			t.Errorf("%s request id %d does not follow %d", req.URL.Path, body.ID, lastID)
+		}
+		lastID = body.ID
+
+		keys := make([]string, 0, len(body.Params))
+		for k := range body.Params {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		params := ""
+		for _, k := range keys {
+			params += k + body.Params[k]
+		}
+
+		mac := hmac.New(sha256.New, []byte(fixtureSecret))
+		mac.Write([]byte(body.Method + strconv.FormatInt(body.ID, 10) + body.APIKey + params + strconv.FormatInt(body.Nonce, 10)))
+		if want := hex.EncodeToString(mac.Sum(nil)); body.Sig != want {
+			t.Errorf("%s sig = %s, want %s", req.URL.Path, body.Sig, want)
+		}
+	}
+	if signed == 0 {
+		t.Error("no signed request was sent")
+	}
+}
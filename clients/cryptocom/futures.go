@@ -0,0 +1,159 @@
+package cryptocom
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+
+	"arbitrage.trade/clients/common"
+)
+
+func (c *CryptocomClient) getFuturesBalance(ctx context.Context) (float64, error) {
+	balance, err := c.getUserBalance(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get futures balance: %w", err)
+	}
+
+	available, _ := strconv.ParseFloat(balance.TotalAvailableBalance, 64)
+	return available, nil
+}
+
+func (c *CryptocomClient) getFuturesPosition(ctx context.Context, instrument string) (*FuturesPosition, error) {
+	var positions struct {
+		Data []FuturesPosition `json:"data"`
+	}
+	if err := c.signedRequest(ctx, "private/get-positions", map[string]interface{}{"instrument_name": instrument}, &positions); err != nil {
+		return nil, err
+	}
+
+	for _, pos := range positions.Data {
+		quantity, _ := strconv.ParseFloat(pos.Quantity, 64)
+		if pos.InstrumentName == instrument && common.NotEqual(quantity, 0) {
+			return &pos, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// GetFuturesPosition returns the signed position size (in base units) for the pair
+func (c *CryptocomClient) GetFuturesPosition(ctx context.Context, pairName string) (float64, error) {
+	position, err := c.getFuturesPosition(ctx, PerpSymbol(pairName))
+	if err != nil {
+		return 0, err
+	}
+	if position == nil {
+		return 0, nil
+	}
+	quantity, _ := strconv.ParseFloat(position.Quantity, 64)
+	return quantity, nil
+}
+
+func (c *CryptocomClient) PutFuturesShort(ctx context.Context, pairName string, amountUSDT float64) (*common.TradeResult, error) {
+	instrument := PerpSymbol(pairName)
+
+	balance, err := c.getFuturesBalance(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get futures balance: %w", err)
+	}
+
+	common.SetBalance(c.GetName(), "futures", "USDT", balance)
+
+	price, err := c.getPrice(ctx, instrument)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price: %w", err)
+	}
+
+	quantity := common.QuantityForNotional(amountUSDT, price, pairName)
+	if common.IsNegativeOrZero(quantity) {
+		return nil, fmt.Errorf("order size %.2f USDT is below the quantity precision at %.8f", amountUSDT, price)
+	}
+
+	orderID, err := c.placeMarketOrder(ctx, instrument, "SELL", map[string]interface{}{
+		"quantity": common.FormatQuantity(quantity, pairName),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("market order failed: %w", err)
+	}
+
+	order := c.awaitFill(ctx, orderID)
+	actualSize, fillPrice, value, fee := c.orderFill(order, c.baseAsset(pairName))
+
+	c.mu.Lock()
+	c.positions[pairName+"_futures"] = &common.Position{
+		PairName:     pairName,
+		Side:         "short",
+		Market:       "futures",
+		EntryPrice:   fillPrice,
+		Quantity:     actualSize,
+		AmountUSDT:   value,
+		OrderID:      orderID,
+		ExchangeName: c.GetName(),
+	}
+	c.mu.Unlock()
+
+	return &common.TradeResult{
+		OrderID:       orderID,
+		ExecutedPrice: fillPrice,
+		ExecutedQty:   actualSize,
+		Fee:           fee,
+		Success:       order.Status == "FILLED",
+	}, nil
+}
+
+// CloseFuturesShort buys back the open short
+// Spot and perpetuals share one wallet, so a balance diff would mix in the spot leg's proceeds;
+// profit is computed from the position's average entry and the closing fill instead
+func (c *CryptocomClient) CloseFuturesShort(ctx context.Context, pairName string) (*common.TradeResult, float64, error) {
+	instrument := PerpSymbol(pairName)
+
+	position, err := c.getFuturesPosition(ctx, instrument)
+	if err != nil {
+		return nil, 0.0, fmt.Errorf("failed to get position: %w", err)
+	}
+
+	quantity := 0.0
+	if position != nil {
+		quantity, _ = strconv.ParseFloat(position.Quantity, 64)
+	}
+
+	if !common.IsNegative(quantity) {
+		c.mu.Lock()
+		delete(c.positions, pairName+"_futures")
+		c.mu.Unlock()
+		return nil, 0.0, fmt.Errorf("no open position on exchange")
+	}
+
+	size := -quantity
+	cost, _ := strconv.ParseFloat(position.Cost, 64)
+	entryPrice := math.Abs(cost) / size
+
+	orderID, err := c.placeMarketOrder(ctx, instrument, "BUY", map[string]interface{}{
+		"quantity": common.FormatQuantity(size, pairName),
+	})
+	if err != nil {
+		return nil, 0.0, fmt.Errorf("close order failed: %w", err)
+	}
+
+	order := c.awaitFill(ctx, orderID)
+
+	c.mu.Lock()
+	delete(c.positions, pairName+"_futures")
+	c.mu.Unlock()
+
+	actualSize, fillPrice, _, fee := c.orderFill(order, c.baseAsset(pairName))
+	profit := (entryPrice-fillPrice)*actualSize - fee
+
+	if newBalance, err := c.getFuturesBalance(ctx); err == nil {
+		common.SetBalance(c.GetName(), "futures", "USDT", newBalance)
+	}
+
+	return &common.TradeResult{
+		OrderID:       orderID,
+		ExecutedPrice: fillPrice,
+		ExecutedQty:   actualSize,
+		Fee:           fee,
+		Success:       order.Status == "FILLED",
+	}, profit, nil
+}
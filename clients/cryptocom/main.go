@@ -0,0 +1,40 @@
+package cryptocom
+
+import (
+	"context"
+	"fmt"
+
+	"arbitrage.trade/clients/common"
+)
+
+func NewCryptocomClient(apiKey, apiSecret string) *CryptocomClient {
+	return &CryptocomClient{
+		apiKey:    apiKey,
+		apiSecret: apiSecret,
+		baseURL:   "https://api.crypto.com/exchange/v1",
+		rest:      common.NewRESTClient("cryptocom"),
+		positions: make(map[string]*common.Position),
+	}
+}
+
+func (c *CryptocomClient) GetName() string {
+	return "cryptocom"
+}
+
+// Prewarm opens a pooled connection to the API
+func (c *CryptocomClient) Prewarm(ctx context.Context) {
+	common.PrewarmConnections(ctx, c.rest.HTTP, c.baseURL+"/public/get-tickers?instrument_name=BTC_USDT")
+}
+
+// GetUSDTBalance returns the available USDT on the given market ("spot" or "futures")
+// Spot and perpetuals share one unified wallet; perpetual margin is the wallet's available USD value
+func (c *CryptocomClient) GetUSDTBalance(ctx context.Context, market string) (float64, error) {
+	switch market {
+	case "spot":
+		return c.getSpotBalance(ctx, "USDT")
+	case "futures":
+		return c.getFuturesBalance(ctx)
+	default:
+		return 0, fmt.Errorf("no USDT balance for market %s", market)
+	}
+}
@@ -0,0 +1,91 @@
+package cryptocom
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"arbitrage.trade/clients/common"
+)
+
+// Market orders are acknowledged before they fill, so fills are polled from
+// private/get-order-detail until the order reaches a final status
+const (
+	fillPollInterval = 100 * time.Millisecond
+	fillPollTimeout  = 3 * time.Second
+)
+
+// placeMarketOrder sends a market order and returns its id
+// Spot buys are sized by notional (USDT), everything else by base quantity
+func (c *CryptocomClient) placeMarketOrder(ctx context.Context, instrument, side string, params map[string]interface{}) (string, error) {
+	params["instrument_name"] = instrument
+	params["side"] = side
+	params["type"] = "MARKET"
+
+	var result CreateOrderResult
+	if err := c.signedRequest(ctx, "private/create-order", params, &result); err != nil {
+		return "", err
+	}
+	if result.OrderID == "" {
+		return "", fmt.Errorf("no order id in order response")
+	}
+
+	return result.OrderID, nil
+}
+
+// awaitFill polls an order until it finishes and returns its final state
+// Falls back to the last known state if the order is still open when the timeout expires
+func (c *CryptocomClient) awaitFill(ctx context.Context, orderID string) *OrderDetail {
+	deadline := time.Now().Add(fillPollTimeout)
+	order := &OrderDetail{OrderID: orderID}
+
+	for order.Status == "" || order.Status == "NEW" || order.Status == "PENDING" || order.Status == "ACTIVE" {
+		if time.Now().After(deadline) {
+			log.Printf("[CRYPTOCOM] awaitFill - ERROR: Order %s still %q after %s, using partial fill", orderID, order.Status, fillPollTimeout)
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return order
+		case <-time.After(fillPollInterval):
+		}
+
+		var polled OrderDetail
+		if err := c.signedRequest(ctx, "private/get-order-detail", map[string]interface{}{"order_id": orderID}, &polled); err != nil {
+			log.Printf("[CRYPTOCOM] awaitFill - ERROR: Failed to poll order %s: %v", orderID, err)
+			continue
+		}
+		order = &polled
+	}
+
+	return order
+}
+
+// orderFill returns the executed base quantity, average price, quote filled and fee in USDT of an order
+func (c *CryptocomClient) orderFill(order *OrderDetail, baseAsset string) (float64, float64, float64, float64) {
+	amount, _ := strconv.ParseFloat(order.CumulativeQuantity, 64)
+	value, _ := strconv.ParseFloat(order.CumulativeValue, 64)
+	avgPrice, _ := strconv.ParseFloat(order.AvgPrice, 64)
+	fee, _ := strconv.ParseFloat(order.CumulativeFee, 64)
+
+	if common.IsZero(avgPrice) && common.IsPositive(amount) {
+		avgPrice = value / amount
+	}
+
+	// Fees are negative when charged; spot buys pay them in the base asset
+	if common.IsNegative(fee) {
+		fee = -fee
+	}
+	switch order.FeeInstrumentName {
+	case "USDT", "USD":
+	case baseAsset:
+		fee *= avgPrice
+	default:
+		fee = 0
+	}
+
+	return amount, avgPrice, value, fee
+}
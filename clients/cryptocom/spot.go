@@ -0,0 +1,146 @@
+package cryptocom
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"arbitrage.trade/clients/common"
+)
+
+func (c *CryptocomClient) getUserBalance(ctx context.Context) (*UserBalance, error) {
+	var balances struct {
+		Data []UserBalance `json:"data"`
+	}
+	if err := c.signedRequest(ctx, "private/user-balance", nil, &balances); err != nil {
+		return nil, err
+	}
+	if len(balances.Data) == 0 {
+		return nil, fmt.Errorf("no balance data")
+	}
+
+	return &balances.Data[0], nil
+}
+
+// getSpotBalance returns the wallet quantity of an asset that is not reserved by open orders
+func (c *CryptocomClient) getSpotBalance(ctx context.Context, currency string) (float64, error) {
+	balance, err := c.getUserBalance(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get spot balance: %w", err)
+	}
+
+	for _, bal := range balance.PositionBalances {
+		if bal.InstrumentName == currency {
+			quantity, _ := strconv.ParseFloat(bal.Quantity, 64)
+			reserved, _ := strconv.ParseFloat(bal.ReservedQty, 64)
+			return quantity - reserved, nil
+		}
+	}
+
+	return 0, nil
+}
+
+// GetSpotHolding returns the available base-asset balance for the pair
+func (c *CryptocomClient) GetSpotHolding(ctx context.Context, pairName string) (float64, error) {
+	return c.getSpotBalance(ctx, c.baseAsset(pairName))
+}
+
+func (c *CryptocomClient) PutSpotLong(ctx context.Context, pairName string, amountUSDT float64) (*common.TradeResult, error) {
+	symbol := SpotSymbol(pairName)
+
+	balance, err := c.getSpotBalance(ctx, "USDT")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get USDT balance: %w", err)
+	}
+
+	common.SetBalance(c.GetName(), "spot", "USDT", balance)
+
+	orderID, err := c.placeMarketOrder(ctx, symbol, "BUY", map[string]interface{}{
+		"notional": strconv.FormatFloat(amountUSDT, 'f', 2, 64),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("market order failed: %w", err)
+	}
+
+	order := c.awaitFill(ctx, orderID)
+	amount, avgPrice, value, fee := c.orderFill(order, c.baseAsset(pairName))
+
+	c.mu.Lock()
+	c.positions[pairName+"_spot"] = &common.Position{
+		PairName:     pairName,
+		Side:         "long",
+		Market:       "spot",
+		EntryPrice:   avgPrice,
+		Quantity:     amount,
+		AmountUSDT:   value,
+		OrderID:      orderID,
+		ExchangeName: c.GetName(),
+	}
+	c.mu.Unlock()
+
+	return &common.TradeResult{
+		OrderID:       orderID,
+		ExecutedPrice: avgPrice,
+		ExecutedQty:   amount,
+		Fee:           fee,
+		Success:       order.Status == "FILLED",
+	}, nil
+}
+
+func (c *CryptocomClient) CloseSpotLong(ctx context.Context, pairName string, amountUSDT float64) (*common.TradeResult, float64, error) {
+	symbol := SpotSymbol(pairName)
+
+	c.mu.RLock()
+	_, exists := c.positions[pairName+"_spot"]
+	c.mu.RUnlock()
+
+	if !exists {
+		return nil, 0.0, fmt.Errorf("no position found for %s", pairName)
+	}
+
+	baseAsset := c.baseAsset(pairName)
+	balance, err := c.getSpotBalance(ctx, baseAsset)
+	if err != nil {
+		return nil, 0.0, fmt.Errorf("failed to get %s balance: %w", baseAsset, err)
+	}
+
+	if common.IsNegativeOrZero(balance) {
+		return nil, 0.0, fmt.Errorf("no %s balance to sell", baseAsset)
+	}
+
+	sellQuantity := common.RoundQuantity(balance, pairName)
+
+	orderID, err := c.placeMarketOrder(ctx, symbol, "SELL", map[string]interface{}{
+		"quantity": common.FormatQuantity(sellQuantity, pairName),
+	})
+	if err != nil {
+		return nil, 0.0, fmt.Errorf("market order failed: %w", err)
+	}
+
+	// Settle the fill before reading the USDT balance the profit is taken from
+	order := c.awaitFill(ctx, orderID)
+
+	c.mu.Lock()
+	delete(c.positions, pairName+"_spot")
+	c.mu.Unlock()
+
+	newBalance, err := c.getSpotBalance(ctx, "USDT")
+	if err != nil {
+		return nil, 0.0, fmt.Errorf("failed to get USDT balance: %w", err)
+	}
+
+	prevBalance := common.GetBalance(c.GetName(), "spot", "USDT")
+	common.SetBalance(c.GetName(), "spot", "USDT", newBalance)
+
+	profit := common.Diff(newBalance, prevBalance)
+
+	amount, avgPrice, _, fee := c.orderFill(order, baseAsset)
+
+	return &common.TradeResult{
+		OrderID:       orderID,
+		ExecutedPrice: avgPrice,
+		ExecutedQty:   amount,
+		Fee:           fee,
+		Success:       order.Status == "FILLED",
+	}, profit, nil
+}
@@ -0,0 +1,66 @@
+package cryptocom
+
+import (
+	"encoding/json"
+	"sync"
+
+	"arbitrage.trade/clients/common"
+)
+
+type CryptocomClient struct {
+	apiKey    string
+	apiSecret string
+	baseURL   string
+	rest      *common.RESTClient
+
+	requestID int64
+
+	positions map[string]*common.Position
+	mu        sync.RWMutex
+}
+
+// APIResponse is the envelope of every v1 REST response
+type APIResponse struct {
+	ID      int64           `json:"id"`
+	Method  string          `json:"method"`
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Result  json.RawMessage `json:"result"`
+}
+
+type UserBalance struct {
+	TotalAvailableBalance string `json:"total_available_balance"` // USD value usable as margin
+	PositionBalances      []struct {
+		InstrumentName string `json:"instrument_name"`
+		Quantity       string `json:"quantity"`
+		ReservedQty    string `json:"reserved_qty"`
+	} `json:"position_balances"`
+}
+
+type CreateOrderResult struct {
+	OrderID   string `json:"order_id"`
+	ClientOID string `json:"client_oid"`
+}
+
+type OrderDetail struct {
+	OrderID            string `json:"order_id"`
+	Status             string `json:"status"` // NEW, PENDING, ACTIVE, FILLED, CANCELED, REJECTED, EXPIRED
+	CumulativeQuantity string `json:"cumulative_quantity"`
+	CumulativeValue    string `json:"cumulative_value"`
+	AvgPrice           string `json:"avg_price"`
+	CumulativeFee      string `json:"cumulative_fee"`
+	FeeInstrumentName  string `json:"fee_instrument_name"`
+}
+
+type FuturesPosition struct {
+	InstrumentName string `json:"instrument_name"`
+	Quantity       string `json:"quantity"` // Signed, negative for shorts
+	Cost           string `json:"cost"`
+}
+
+type Ticker struct {
+	InstrumentName string `json:"i"`
+	Last           string `json:"a"`
+	Bid            string `json:"b"`
+	Ask            string `json:"k"`
+}
@@ -0,0 +1,162 @@
+package cryptocom
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"arbitrage.trade/clients/common"
+)
+
+// SpotSymbol converts "btc-usdt" to "BTC_USDT"
+func SpotSymbol(pairName string) string {
+	parts := strings.Split(strings.ToUpper(pairName), "-")
+	return strings.Join(parts, "_")
+}
+
+// PerpSymbol converts "btc-usdt" to the USD-settled perpetual "BTCUSD-PERP"
+func PerpSymbol(pairName string) string {
+	base := strings.Split(strings.ToUpper(pairName), "-")[0]
+	return base + "USD-PERP"
+}
+
+func (c *CryptocomClient) baseAsset(pairName string) string {
+	return strings.Split(strings.ToUpper(pairName), "-")[0]
+}
+
+// paramString flattens request params the way the signature expects: keys sorted,
+// each key followed by its value, lists concatenated and nil written as "null"
+func paramString(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		var sb strings.Builder
+		for _, k := range keys {
+			sb.WriteString(k)
+			sb.WriteString(paramString(val[k]))
+		}
+		return sb.String()
+	case []interface{}:
+		var sb strings.Builder
+		for _, item := range val {
+			sb.WriteString(paramString(item))
+		}
+		return sb.String()
+	case []string:
+		return strings.Join(val, "")
+	case string:
+		return val
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+// signedRequest calls a private method (e.g. "private/create-order") and decodes its result
+func (c *CryptocomClient) signedRequest(ctx context.Context, method string, params map[string]interface{}, result interface{}) error {
+	if params == nil {
+		params = map[string]interface{}{}
+	}
+
+	resp, err := c.rest.Do(ctx, func(ctx context.Context) (*http.Request, error) {
+		id := atomic.AddInt64(&c.requestID, 1)
+		nonce := time.Now().UnixMilli()
+
+		// Crypto.com signature: HMAC-SHA256(method + id + api_key + paramString + nonce), hex encoded
+		payload := method + strconv.FormatInt(id, 10) + c.apiKey + paramString(params) + strconv.FormatInt(nonce, 10)
+		h := hmac.New(sha256.New, []byte(c.apiSecret))
+		h.Write([]byte(payload))
+
+		body, err := json.Marshal(map[string]interface{}{
+			"id":      id,
+			"method":  method,
+			"api_key": c.apiKey,
+			"params":  params,
+			"nonce":   nonce,
+			"sig":     hex.EncodeToString(h.Sum(nil)),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %w", err)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/"+method, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return decodeEnvelope(resp, result)
+}
+
+// publicRequest calls a public method (e.g. "public/get-tickers") and decodes its result
+func (c *CryptocomClient) publicRequest(ctx context.Context, method string, params url.Values, result interface{}) error {
+	endpoint := c.baseURL + "/" + method
+	if len(params) > 0 {
+		endpoint += "?" + params.Encode()
+	}
+
+	resp, err := c.rest.Get(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+
+	return decodeEnvelope(resp, result)
+}
+
+// decodeEnvelope unwraps the {"code":0,"result":{}} envelope of a response
+func decodeEnvelope(resp *common.RESTResponse, result interface{}) error {
+	var envelope APIResponse
+	if err := json.Unmarshal(resp.Body, &envelope); err != nil {
+		return fmt.Errorf("cryptocom api error: status %d, body: %s", resp.Status, string(resp.Body))
+	}
+
+	if resp.Status != http.StatusOK || envelope.Code != 0 {
+		return fmt.Errorf("cryptocom api error: status %d, code %d: %s", resp.Status, envelope.Code, envelope.Message)
+	}
+
+	if result != nil {
+		if err := json.Unmarshal(envelope.Result, result); err != nil {
+			return fmt.Errorf("failed to unmarshal result: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (c *CryptocomClient) getPrice(ctx context.Context, instrument string) (float64, error) {
+	var tickers struct {
+		Data []Ticker `json:"data"`
+	}
+	if err := c.publicRequest(ctx, "public/get-tickers", url.Values{"instrument_name": {instrument}}, &tickers); err != nil {
+		return 0, err
+	}
+
+	if len(tickers.Data) == 0 {
+		return 0, fmt.Errorf("no ticker data for %s", instrument)
+	}
+
+	price, _ := strconv.ParseFloat(tickers.Data[0].Last, 64)
+	return price, nil
+}
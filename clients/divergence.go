@@ -0,0 +1,116 @@
+package clients
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"arbitrage.trade/config"
+	"arbitrage.trade/notify"
+)
+
+// Price divergence limit
+//
+// Every leg's fill is compared with the price the trade was decided at. A fill more than
+// EXEC_DIVERGENCE_LIMIT_BPS (default 50, 0 disables) away from it, either way, flags the trade in the
+// execution log: the signal and the venue disagreed, through a stale book, a thin one or a bad quote.
+// With EXEC_DIVERGENCE_PAUSE set (e.g. 30m, default 0 = flag only) the spot:perp venue combination
+// that produced it also gets no new entries for that long and an alert is sent.
+
+// DivergencePause is a spot:perp venue combination held back from new entries after a diverged fill
+type DivergencePause struct {
+	SpotExchange string    `json:"spot_exchange"`
+	PerpExchange string    `json:"perp_exchange"`
+	Detail       string    `json:"detail"`
+	Since        time.Time `json:"since"`
+	Until        time.Time `json:"until"`
+}
+
+var (
+	divergencePauses   = make(map[string]*DivergencePause) // "spot:perp" -> pause
+	divergencePausesMu sync.Mutex
+)
+
+// DivergenceLimitBps returns how far in bps a fill may land from its decision price, 0 when unlimited
+func DivergenceLimitBps() float64 {
+	return config.GetFloat("EXEC_DIVERGENCE_LIMIT_BPS", 50)
+}
+
+// PauseDivergedCombination holds new entries between the venues for EXEC_DIVERGENCE_PAUSE after a fill
+// diverged from its decision price; no-op when the pause is 0 or already active
+func PauseDivergedCombination(spotExchange, perpExchange, detail string) {
+	duration := config.GetDuration("EXEC_DIVERGENCE_PAUSE", 0)
+	if duration <= 0 {
+		return
+	}
+	now := time.Now()
+	key := spotExchange + ":" + perpExchange
+
+	divergencePausesMu.Lock()
+	if pause, ok := divergencePauses[key]; ok && now.Before(pause.Until) {
+		divergencePausesMu.Unlock()
+		return
+	}
+	pause := &DivergencePause{
+		SpotExchange: spotExchange,
+		PerpExchange: perpExchange,
+		Detail:       detail,
+		Since:        now,
+		Until:        now.Add(duration),
+	}
+	divergencePauses[key] = pause
+	divergencePausesMu.Unlock()
+
+	log.Printf("[DIVERGENCE] %s spot / %s perp paused until %s: %s", spotExchange, perpExchange, pause.Until.Format(time.RFC3339), detail)
+	notify.Send(notify.Message{
+		Event: notify.EventAlert,
+		Title: fmt.Sprintf("%s:%s paused after a diverged fill", spotExchange, perpExchange),
+		Body: fmt.Sprintf("%s\nNo new entries between %s spot and %s perp until %s.",
+			detail, spotExchange, perpExchange, pause.Until.Format(time.RFC3339)),
+	})
+}
+
+// DivergenceBlock returns why new entries between the spot and perp venues are held after a diverged fill,
+// "" when allowed
+func DivergenceBlock(spotExchange, perpExchange string) string {
+	key := spotExchange + ":" + perpExchange
+	now := time.Now()
+
+	divergencePausesMu.Lock()
+	defer divergencePausesMu.Unlock()
+
+	pause, ok := divergencePauses[key]
+	if !ok {
+		return ""
+	}
+	if now.After(pause.Until) {
+		delete(divergencePauses, key)
+		log.Printf("[DIVERGENCE] %s spot / %s perp pause over - entering again", spotExchange, perpExchange)
+		return ""
+	}
+	return fmt.Sprintf("%s spot / %s perp paused until %s after a diverged fill", spotExchange, perpExchange, pause.Until.Format(time.RFC3339))
+}
+
+// DivergencePauses returns every venue combination currently paused after a diverged fill
+func DivergencePauses() []DivergencePause {
+	now := time.Now()
+
+	divergencePausesMu.Lock()
+	defer divergencePausesMu.Unlock()
+
+	pauses := make([]DivergencePause, 0, len(divergencePauses))
+	for _, pause := range divergencePauses {
+		if now.Before(pause.Until) {
+			pauses = append(pauses, *pause)
+		}
+	}
+	sort.Slice(pauses, func(i, j int) bool {
+		if pauses[i].SpotExchange != pauses[j].SpotExchange {
+			return pauses[i].SpotExchange < pauses[j].SpotExchange
+		}
+		return pauses[i].PerpExchange < pauses[j].PerpExchange
+	})
+	return pauses
+}
@@ -0,0 +1,118 @@
+package clients
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/config"
+	"arbitrage.trade/notify"
+)
+
+// Venue entry shutdown
+//
+// ENTRY_DISABLED_<EXCHANGE>=true keeps new legs off a venue, e.g. while it shows withdrawal issues or
+// solvency rumors: entries touching it are skipped and opening orders on it refused, while the
+// positions already open there keep being monitored and closed as usual. The operator API toggles
+// it at runtime (POST /venues/entry?exchange=X&disabled=true&reason=...); a runtime toggle overrides
+// the config flag until the process restarts.
+
+// EntryDisable is a venue no new legs are routed to
+type EntryDisable struct {
+	Exchange string    `json:"exchange"`
+	Reason   string    `json:"reason,omitempty"`
+	Source   string    `json:"source"` // "config" or "operator"
+	Since    time.Time `json:"since"`
+}
+
+var (
+	entryOverrides   = make(map[string]*EntryDisable) // exchange -> operator toggle, nil entry means enabled
+	entryConfigSince = make(map[string]time.Time)     // exchange -> when the config flag was first seen
+	entryMu          sync.Mutex
+)
+
+// entryDisable returns the venue's shutdown, nil when new legs are allowed
+// Callers hold entryMu
+func entryDisable(exchange string) *EntryDisable {
+	if override, ok := entryOverrides[exchange]; ok {
+		return override
+	}
+	if !config.GetBool(config.Key("ENTRY_DISABLED", exchange), false) {
+		delete(entryConfigSince, exchange)
+		return nil
+	}
+	since, ok := entryConfigSince[exchange]
+	if !ok {
+		since = time.Now()
+		entryConfigSince[exchange] = since
+	}
+	return &EntryDisable{Exchange: exchange, Reason: config.Key("ENTRY_DISABLED", exchange), Source: "config", Since: since}
+}
+
+// EntryBlock returns why new legs on the venues are refused by an entry shutdown, "" when allowed
+func EntryBlock(exchanges ...string) string {
+	entryMu.Lock()
+	defer entryMu.Unlock()
+
+	for _, exchange := range exchanges {
+		if disable := entryDisable(exchange); disable != nil {
+			if disable.Reason == "" {
+				return fmt.Sprintf("%s entries disabled by %s", exchange, disable.Source)
+			}
+			return fmt.Sprintf("%s entries disabled by %s: %s", exchange, disable.Source, disable.Reason)
+		}
+	}
+	return ""
+}
+
+// SetEntryDisabled stops or resumes new legs on the venue until restart, overriding ENTRY_DISABLED_<EXCHANGE>
+func SetEntryDisabled(exchange string, disabled bool, reason string) error {
+	if _, ok := exchangeRegistry[common.ExchangeType(exchange)]; !ok {
+		return fmt.Errorf("unknown exchange: %s", exchange)
+	}
+
+	entryMu.Lock()
+	wasDisabled := entryDisable(exchange) != nil
+	if disabled {
+		entryOverrides[exchange] = &EntryDisable{Exchange: exchange, Reason: reason, Source: "operator", Since: time.Now()}
+	} else {
+		entryOverrides[exchange] = nil
+	}
+	entryMu.Unlock()
+
+	if disabled == wasDisabled {
+		return nil
+	}
+	if disabled {
+		log.Printf("[ENTRY] %s disabled for new legs: %s", exchange, reason)
+		notify.Send(notify.Message{
+			Event: notify.EventAlert,
+			Title: fmt.Sprintf("%s disabled for new entries", exchange),
+			Body:  fmt.Sprintf("Reason: %s\nOpen positions on %s are still managed and closed as usual.", reason, exchange),
+		})
+		return nil
+	}
+	log.Printf("[ENTRY] %s enabled for new legs again", exchange)
+	notify.Send(notify.Message{
+		Event: notify.EventAlert,
+		Title: fmt.Sprintf("%s enabled for new entries", exchange),
+		Body:  fmt.Sprintf("New legs are routed to %s again.", exchange),
+	})
+	return nil
+}
+
+// EntryDisables returns every venue currently closed to new legs
+func EntryDisables() []EntryDisable {
+	entryMu.Lock()
+	defer entryMu.Unlock()
+
+	disables := make([]EntryDisable, 0)
+	for _, exchange := range Exchanges() {
+		if disable := entryDisable(string(exchange)); disable != nil {
+			disables = append(disables, *disable)
+		}
+	}
+	return disables
+}
@@ -0,0 +1,354 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/clients/paper"
+	"arbitrage.trade/config"
+	"arbitrage.trade/logging"
+)
+
+// ExecMode selects how a leg is executed on a venue
+// Configure per venue and leg with EXEC_MODE_<EXCHANGE>_<SPOT|PERP> (e.g. EXEC_MODE_BINANCE_SPOT=maker),
+// falling back to EXEC_MODE_<SPOT|PERP> and then EXEC_MODE
+type ExecMode string
+
+const (
+	// ExecTaker always crosses the spread with a market order
+	ExecTaker ExecMode = "taker"
+	// ExecMaker rests a post-only limit order first and takes whatever is left after EXEC_MAKER_TIMEOUT
+	ExecMaker ExecMode = "maker"
+	// ExecTakerAbove goes taker when the spread is at or above EXEC_TAKER_ABOVE_SPREAD, maker otherwise
+	ExecTakerAbove ExecMode = "taker_above"
+	// ExecAuto goes maker when the venue's maker rate, rebates included, undercuts its taker rate by at
+	// least EXEC_MAKER_MIN_SAVING_PCT (default 0.02), taker otherwise
+	ExecAuto ExecMode = "auto"
+)
+
+// FeeSource reports venues' current fee rates in %, a negative maker rate being a rebate
+type FeeSource interface {
+	TakerPct(exchange, market string) float64
+	MakerPct(exchange, market string) float64
+}
+
+var feeSource FeeSource
+
+// SetFeeSource sets the fee model the auto execution mode decides by
+func SetFeeSource(source FeeSource) {
+	feeSource = source
+}
+
+const makerPollInterval = 100 * time.Millisecond
+
+type limitPriceKey struct{}
+
+// WithLimitPrice makes the opening legs executed with ctx limit immediate-or-cancel orders at price
+// or better; a leg that does not fill in full fails, returning what did fill
+func WithLimitPrice(ctx context.Context, price float64) context.Context {
+	return context.WithValue(ctx, limitPriceKey{}, price)
+}
+
+// limitPrice returns the IOC limit price carried by ctx, if any
+func limitPrice(ctx context.Context) (float64, bool) {
+	price, ok := ctx.Value(limitPriceKey{}).(float64)
+	return price, ok && common.IsPositive(price)
+}
+
+var bookSource paper.BookSource
+
+// SetBookSource sets the live orderbooks used to price maker orders and to simulate paper fills
+func SetBookSource(source paper.BookSource) {
+	bookSource = source
+	paper.SetBookSource(source)
+}
+
+// legName maps a market to the leg name used in config keys
+func legName(market string) string {
+	if market == "futures" {
+		return "perp"
+	}
+	return market
+}
+
+// LegExecMode returns the configured execution mode and taker threshold for a venue leg
+func LegExecMode(exchange common.ExchangeType, market string) (ExecMode, float64) {
+	leg := legName(market)
+
+	mode := config.GetString(config.Key("EXEC_MODE", leg), config.GetString("EXEC_MODE", string(ExecTaker)))
+	mode = config.GetString(config.Key("EXEC_MODE", string(exchange), leg), mode)
+
+	threshold := config.GetFloat(config.Key("EXEC_TAKER_ABOVE_SPREAD", leg), config.GetFloat("EXEC_TAKER_ABOVE_SPREAD", 1.0))
+	threshold = config.GetFloat(config.Key("EXEC_TAKER_ABOVE_SPREAD", string(exchange), leg), threshold)
+
+	return ExecMode(strings.ToLower(mode)), threshold
+}
+
+// useMaker reports whether a leg should attempt a maker order first at the given spread
+// A playbook carried by ctx overrides the configured mode
+func useMaker(ctx context.Context, exchange common.ExchangeType, market string, spreadPct float64) bool {
+	mode, threshold := LegExecMode(exchange, market)
+	if playbook, ok := playbookFrom(ctx); ok && playbook.legMode(market) != "" {
+		mode = playbook.legMode(market)
+	}
+
+	switch mode {
+	case ExecMaker:
+		return true
+	case ExecTakerAbove:
+		return common.LessThan(spreadPct, threshold)
+	case ExecAuto:
+		if feeSource == nil {
+			return false
+		}
+		saving := feeSource.TakerPct(string(exchange), market) - feeSource.MakerPct(string(exchange), market)
+		return common.GreaterThanOrEqual(saving, config.GetFloat("EXEC_MAKER_MIN_SAVING_PCT", 0.02))
+	default:
+		return false
+	}
+}
+
+// executeMakerFirst rests a post-only order at the passive best price, waits up to
+// EXEC_MAKER_TIMEOUT for fills and sends the unfilled remainder as a taker order
+func executeMakerFirst(ctx context.Context, client common.ExchangeTradeClient, limitClient common.LimitOrderClient,
+	exchange common.ExchangeType, command common.OrderType, pairName string, amountUSDT float64) (*common.TradeResult, float64, error) {
+
+	var market, side string
+	isOpen := command == common.PutSpotLong || command == common.PutFuturesShort
+	switch command {
+	case common.PutSpotLong:
+		market, side = "spot", "buy"
+	case common.CloseSpotLong:
+		market, side = "spot", "sell"
+	case common.PutFuturesShort:
+		market, side = "futures", "sell"
+	case common.CloseFuturesShort:
+		market, side = "futures", "buy"
+	default:
+		return nil, 0.00, fmt.Errorf("maker execution not supported for %s", command)
+	}
+
+	price, err := passivePrice(exchange, pairName, market == "spot", side == "buy")
+	if err != nil {
+		logging.Warnf("executor", "%s - Maker unavailable, using taker: %s", logTag(ctx, exchange, command), err)
+		return executeTaker(ctx, client, command, pairName, amountUSDT)
+	}
+
+	quantity, err := makerQuantity(ctx, client, command, pairName, amountUSDT, price)
+	if err != nil {
+		return nil, 0.00, err
+	}
+	if common.IsNegativeOrZero(quantity) {
+		return executeTaker(ctx, client, command, pairName, amountUSDT)
+	}
+
+	// Balance snapshot that close profit is measured against, as the taker paths do
+	balanceBefore, err := limitClient.GetUSDTBalance(ctx, market)
+	if err != nil {
+		return nil, 0.00, fmt.Errorf("failed to get %s USDT balance: %w", market, err)
+	}
+	if isOpen {
+		common.SetBalance(client.GetName(), market, "USDT", balanceBefore)
+	}
+
+	orderID, err := limitClient.PlaceLimitOrder(ctx, pairName, market, side, price, quantity)
+	if err != nil {
+		logging.Warnf("executor", "%s - Maker order rejected, using taker: %s", logTag(ctx, exchange, command), err)
+		return executeTaker(ctx, client, command, pairName, amountUSDT)
+	}
+
+	result, err := awaitLimitOrder(ctx, limitClient, pairName, market, orderID)
+	if err != nil {
+		return nil, 0.00, err
+	}
+
+	filled := result.ExecutedQty
+	result.MakerQty = filled
+	// Venues report a maker rebate as a negative fee; keep it apart from the taker remainder's fee
+	if common.IsNegative(result.Fee) {
+		result.Rebate, result.Fee = -result.Fee, 0
+	}
+	logging.Infof("executor", "%s - Maker filled %s / %s @ %s", logTag(ctx, exchange, command),
+		common.FormatQuantity(filled, pairName), common.FormatQuantity(quantity, pairName), common.FormatPrice(price, pairName))
+
+	if isOpen {
+		remainingUSDT := amountUSDT - filled*price
+		if common.IsPositive(filled) && !common.CanAchieveVolume(remainingUSDT, price, pairName) {
+			return result, 0.00, nil
+		}
+
+		takerResult, _, err := executeTaker(ctx, client, command, pairName, remainingUSDT)
+
+		// The taker open re-snapshots after the maker fill; keep the pre-maker balance
+		common.SetBalance(client.GetName(), market, "USDT", balanceBefore)
+		return mergeResults(result, takerResult), 0.00, err
+	}
+
+	if common.LessThan(filled, quantity) {
+		// The taker close flattens what is left and measures profit from the open snapshot
+		takerResult, profit, err := executeTaker(ctx, client, command, pairName, amountUSDT)
+		return mergeResults(result, takerResult), profit, err
+	}
+
+	balanceAfter, err := limitClient.GetUSDTBalance(ctx, market)
+	if err != nil {
+		return result, 0.00, fmt.Errorf("failed to get %s USDT balance: %w", market, err)
+	}
+	prevBalance := common.GetBalance(client.GetName(), market, "USDT")
+	common.SetBalance(client.GetName(), market, "USDT", balanceAfter)
+
+	return result, balanceAfter - prevBalance, nil
+}
+
+// executeTaker sends a leg as a market order through the client's standard path
+func executeTaker(ctx context.Context, client common.ExchangeTradeClient, command common.OrderType, pairName string, amountUSDT float64) (*common.TradeResult, float64, error) {
+	var result *common.TradeResult
+	var profit float64
+	var err error
+
+	switch command {
+	case common.PutSpotLong:
+		result, err = client.PutSpotLong(ctx, pairName, amountUSDT)
+	case common.CloseSpotLong:
+		result, profit, err = client.CloseSpotLong(ctx, pairName, amountUSDT)
+	case common.PutFuturesShort:
+		result, err = client.PutFuturesShort(ctx, pairName, amountUSDT)
+	case common.CloseFuturesShort:
+		result, profit, err = client.CloseFuturesShort(ctx, pairName)
+	default:
+		return nil, 0.00, fmt.Errorf("unknown command: %s", command)
+	}
+
+	return result, profit, err
+}
+
+// mergeResults combines a maker fill and its taker remainder into one volume-weighted result
+func mergeResults(maker, taker *common.TradeResult) *common.TradeResult {
+	if taker == nil {
+		return maker
+	}
+	if maker == nil || common.IsZero(maker.ExecutedQty) {
+		return taker
+	}
+
+	qty := maker.ExecutedQty + taker.ExecutedQty
+	return &common.TradeResult{
+		OrderID:       maker.OrderID + "," + taker.OrderID,
+		ExecutedPrice: (maker.ExecutedPrice*maker.ExecutedQty + taker.ExecutedPrice*taker.ExecutedQty) / qty,
+		ExecutedQty:   qty,
+		Fee:           maker.Fee + taker.Fee,
+		Success:       taker.Success,
+		MakerQty:      maker.MakerQty + taker.MakerQty,
+		Rebate:        maker.Rebate + taker.Rebate,
+	}
+}
+
+// passivePrice returns the best price on our own side of the book (bid to buy, ask to sell)
+func passivePrice(exchange common.ExchangeType, pairName string, isSpot bool, isBuy bool) (float64, error) {
+	if bookSource == nil {
+		return 0, fmt.Errorf("no book source set")
+	}
+
+	book, ok := bookSource.GetBook(pairName, string(exchange), isSpot)
+	if !ok {
+		return 0, fmt.Errorf("no book for %s on %s", pairName, exchange)
+	}
+
+	var price float64
+	if isBuy {
+		price, _, ok = book.GetBestBid()
+	} else {
+		price, _, ok = book.GetBestAsk()
+	}
+	if !ok {
+		return 0, fmt.Errorf("empty book for %s on %s", pairName, exchange)
+	}
+	return price, nil
+}
+
+// makerQuantity sizes an opening order from amountUSDT and a closing order from the venue's holding,
+// both in base units
+func makerQuantity(ctx context.Context, client common.ExchangeTradeClient, command common.OrderType, pairName string, amountUSDT float64, price float64) (float64, error) {
+	switch command {
+	case common.CloseSpotLong:
+		holding, err := client.GetSpotHolding(ctx, pairName)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get spot holding: %w", err)
+		}
+		return common.RoundQuantity(holding, pairName), nil
+	case common.CloseFuturesShort:
+		size, err := client.GetFuturesPosition(ctx, pairName)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get futures position: %w", err)
+		}
+		return math.Abs(size), nil
+	default:
+		return common.QuantityForNotional(amountUSDT, price, pairName), nil
+	}
+}
+
+// awaitLimitOrder polls a limit order until it completes or EXEC_MAKER_TIMEOUT passes, then cancels it
+func awaitLimitOrder(ctx context.Context, limitClient common.LimitOrderClient, pairName, market, orderID string) (*common.TradeResult, error) {
+	deadline := time.Now().Add(config.GetDuration("EXEC_MAKER_TIMEOUT", 2*time.Second))
+
+	for time.Now().Before(deadline) {
+		result, done, err := limitClient.GetLimitOrder(ctx, pairName, market, orderID)
+		if err != nil {
+			return nil, err
+		}
+		if done {
+			return result, nil
+		}
+		time.Sleep(makerPollInterval)
+	}
+
+	if err := limitClient.CancelLimitOrder(ctx, pairName, market, orderID); err != nil {
+		return nil, fmt.Errorf("failed to cancel maker order %s: %w", orderID, err)
+	}
+
+	result, _, err := limitClient.GetLimitOrder(ctx, pairName, market, orderID)
+	return result, err
+}
+
+// executeIOC sends an opening leg as a limit immediate-or-cancel order for amountUSDT at price
+// A partial or empty fill is an error; the result still reports what filled so it can be unwound
+func executeIOC(ctx context.Context, client common.ExchangeTradeClient, iocClient common.IOCOrderClient,
+	exchange common.ExchangeType, command common.OrderType, pairName string, amountUSDT float64, price float64) (*common.TradeResult, error) {
+
+	market, side := "spot", "buy"
+	if command == common.PutFuturesShort {
+		market, side = "futures", "sell"
+	}
+
+	quantity := common.QuantityForNotional(amountUSDT, price, pairName)
+	if common.IsNegativeOrZero(quantity) {
+		return nil, fmt.Errorf("IOC quantity for %.2f USDT @ %s is zero", amountUSDT, common.FormatPrice(price, pairName))
+	}
+
+	// Balance snapshot that close profit is measured against, as the taker paths do
+	balanceBefore, err := iocClient.GetUSDTBalance(ctx, market)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s USDT balance: %w", market, err)
+	}
+	common.SetBalance(client.GetName(), market, "USDT", balanceBefore)
+
+	result, err := iocClient.PlaceIOCOrder(ctx, pairName, market, side, price, quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	logging.Infof("executor", "%s - IOC filled %s / %s @ %s (limit %s)", logTag(ctx, exchange, command),
+		common.FormatQuantity(result.ExecutedQty, pairName), common.FormatQuantity(quantity, pairName),
+		common.FormatPrice(result.ExecutedPrice, pairName), common.FormatPrice(price, pairName))
+
+	if !result.Success {
+		return result, fmt.Errorf("IOC %s %s filled %s of %s at %s: %w", market, side,
+			common.FormatQuantity(result.ExecutedQty, pairName), common.FormatQuantity(quantity, pairName),
+			common.FormatPrice(price, pairName), common.ErrOrderFailed)
+	}
+	return result, nil
+}
@@ -2,19 +2,28 @@ package clients
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"os"
+	"log"
 	"strings"
 	"sync"
 	"time"
 
+	"arbitrage.trade/chaos"
 	"arbitrage.trade/clients/binance"
 	"arbitrage.trade/clients/bitget"
 	"arbitrage.trade/clients/common"
+	"arbitrage.trade/clients/cryptocom"
 	"arbitrage.trade/clients/gate"
+	"arbitrage.trade/clients/hyperliquid"
+	"arbitrage.trade/clients/kraken"
 	"arbitrage.trade/clients/okx"
+	"arbitrage.trade/clients/paper"
 	"arbitrage.trade/clients/whitebit"
+	"arbitrage.trade/config"
+	"arbitrage.trade/logging"
 	"arbitrage.trade/redis"
+	"arbitrage.trade/risk"
 )
 
 var (
@@ -23,24 +32,51 @@ var (
 	clientMutex     sync.RWMutex
 )
 
-var exchangeRegistry = map[common.ExchangeType]func(string, string) common.ExchangeTradeClient{
-	common.Binance: func(key, secret string) common.ExchangeTradeClient {
-		return binance.NewBinanceClient(key, secret)
+// exchangeRegistry builds each exchange's client from its account credentials (see config.ExchangeCredentials)
+var exchangeRegistry = map[common.ExchangeType]func(config.Credentials) common.ExchangeTradeClient{
+	common.Binance: func(creds config.Credentials) common.ExchangeTradeClient {
+		return binance.NewBinanceClient(creds.Key, creds.Secret)
 	},
-	common.Bitget: func(key, secret string) common.ExchangeTradeClient {
-		passphrase := os.Getenv("BITGET_PASSPHRASE")
-		return bitget.NewBitgetClient(key, secret, passphrase)
+	common.Bitget: func(creds config.Credentials) common.ExchangeTradeClient {
+		return bitget.NewBitgetClient(creds.Key, creds.Secret, creds.Passphrase)
 	},
-	common.Whitebit: func(key, secret string) common.ExchangeTradeClient {
-		return whitebit.NewWhitebitClient(key, secret)
+	common.Whitebit: func(creds config.Credentials) common.ExchangeTradeClient {
+		return whitebit.NewWhitebitClient(creds.Key, creds.Secret)
 	},
-	common.Gate: func(key, secret string) common.ExchangeTradeClient {
-		return gate.NewGateClient(key, secret)
+	common.Gate: func(creds config.Credentials) common.ExchangeTradeClient {
+		return gate.NewGateClient(creds.Key, creds.Secret)
 	},
-	common.Okx: func(key, secret string) common.ExchangeTradeClient {
-		passphrase := os.Getenv("OKX_PASSPHRASE")
-		return okx.NewOkxClient(key, secret, passphrase)
+	common.Okx: func(creds config.Credentials) common.ExchangeTradeClient {
+		return okx.NewOkxClient(creds.Key, creds.Secret, creds.Passphrase)
 	},
+	common.Kraken: func(creds config.Credentials) common.ExchangeTradeClient {
+		// Kraken Futures is a separate platform with its own keys
+		futures := config.ExchangeCredentials("kraken-futures")
+		return kraken.NewKrakenClient(creds.Key, creds.Secret, futures.Key, futures.Secret)
+	},
+	common.Cryptocom: func(creds config.Credentials) common.ExchangeTradeClient {
+		return cryptocom.NewCryptocomClient(creds.Key, creds.Secret)
+	},
+	common.Hyperliquid: func(creds config.Credentials) common.ExchangeTradeClient {
+		// Key is the account address, secret the wallet private key
+		return hyperliquid.NewHyperliquidClient(creds.Key, creds.Secret)
+	},
+}
+
+// optInExchanges need an explicit enable flag before orders are routed to them,
+// since their signing and nonce handling differ from the CEX adapters
+var optInExchanges = map[common.ExchangeType]string{
+	common.Hyperliquid: "HYPERLIQUID_ENABLED",
+}
+
+// MissingCredentials lists the credential env vars an exchange needs for live trading that are unset
+func MissingCredentials(exchange common.ExchangeType) []string {
+	return config.ExchangeCredentials(string(exchange)).Missing()
+}
+
+// IsPaperTrading reports whether orders are simulated instead of sent to exchanges (PAPER_TRADING=true)
+func IsPaperTrading() bool {
+	return config.GetBool("PAPER_TRADING", false)
 }
 
 // getOrCreateClient returns a singleton client instance for the given exchange
@@ -66,78 +102,458 @@ func getOrCreateClient(exchange common.ExchangeType) (common.ExchangeTradeClient
 		return nil, fmt.Errorf("unknown exchange: %s", exchange)
 	}
 
-	keyEnv := fmt.Sprintf("%s_API_KEY", strings.ToUpper(string(exchange)))
-	secretEnv := fmt.Sprintf("%s_API_SECRET", strings.ToUpper(string(exchange)))
+	if flag, ok := optInExchanges[exchange]; ok && !config.GetBool(flag, false) {
+		return nil, fmt.Errorf("%s is disabled, set %s=true to trade it", exchange, flag)
+	}
 
-	apiKey := os.Getenv(keyEnv)
-	apiSecret := os.Getenv(secretEnv)
+	// Paper mode simulates every venue against live books, no credentials needed
+	if IsPaperTrading() {
+		client := paper.NewPaperClient(string(exchange))
+		clientInstances[exchange] = client
+		return client, nil
+	}
 
-	if apiKey == "" || apiSecret == "" {
-		return nil, fmt.Errorf("missing API credentials for %s", exchange)
+	creds := config.ExchangeCredentials(string(exchange))
+	if missing := creds.Missing(); len(missing) > 0 {
+		return nil, fmt.Errorf("missing API credentials for %s: %s unset", exchange, strings.Join(missing, ", "))
 	}
 
-	client := constructor(apiKey, apiSecret)
+	client := constructor(creds)
 	clientInstances[exchange] = client
 	return client, nil
 }
 
-func Execute(ctx context.Context, exchange common.ExchangeType, command common.OrderType, pairName string, amountUSDT float64) (float64, error) {
-	fmt.Printf("[%s] |%s| - Starting\n", exchange, command)
+// Execute runs one leg on an exchange; spreadPct is the current spread used to pick taker or maker execution
+// Returns the fill (nil if the venue reported none) and, for closing legs, the realized USDT profit
+func Execute(ctx context.Context, exchange common.ExchangeType, command common.OrderType, pairName string, amountUSDT float64, spreadPct float64) (*common.TradeResult, float64, error) {
+	logging.Infof("executor", "%s - Starting", logTag(ctx, exchange, command))
 
 	client, err := getOrCreateClient(exchange)
 	profit := 0.00
 
 	if err != nil {
-		return 0.00, err
+		return nil, 0.00, err
 	}
 
 	// Determine trade details for Redis publishing
-	var side, action string
+	var side, action, market string
 	switch command {
 	case common.PutSpotLong:
 		side = "spot_long"
 		action = "open"
+		market = "spot"
 	case common.CloseSpotLong:
 		side = "spot_long"
 		action = "close"
+		market = "spot"
 	case common.PutFuturesShort:
 		side = "futures_short"
 		action = "open"
+		market = "futures"
 	case common.CloseFuturesShort:
 		side = "futures_short"
 		action = "close"
+		market = "futures"
+	case common.PutMarginShort:
+		side = "margin_short"
+		action = "open"
+		market = "margin"
+	case common.CloseMarginShort:
+		side = "margin_short"
+		action = "close"
+		market = "margin"
+	case common.PutFuturesLong:
+		side = "futures_long"
+		action = "open"
+		market = "futures"
+	case common.CloseFuturesLong:
+		side = "futures_long"
+		action = "close"
+		market = "futures"
+	case common.PutInverseShort:
+		side = "inverse_short"
+		action = "open"
+		market = "inverse"
+	case common.CloseInverseShort:
+		side = "inverse_short"
+		action = "close"
+		market = "inverse"
+	}
+
+	marginClient, hasMargin := client.(common.MarginShortClient)
+	if market == "margin" && !hasMargin {
+		return nil, 0.00, fmt.Errorf("%s does not support margin shorting", exchange)
+	}
+
+	inverseClient, hasInverse := client.(common.InverseClient)
+	if market == "inverse" && !hasInverse {
+		return nil, 0.00, fmt.Errorf("%s does not support coin-margined perpetuals", exchange)
+	}
+
+	inventoryClient, hasInventory := client.(common.InventoryClient)
+	isLongLeg := command == common.PutFuturesLong || command == common.CloseFuturesLong
+	if isLongLeg && !hasInventory {
+		return nil, 0.00, fmt.Errorf("%s does not support futures longs", exchange)
+	}
+
+	// Opening legs must reserve venue notional before any order is placed
+	if action == "open" {
+		if block := EntryBlock(string(exchange)); block != "" {
+			logging.Warnf("executor", "%s - Refused by venue entry shutdown: %s", logTag(ctx, exchange, command), block)
+			return nil, 0.00, errors.New(block)
+		}
+		if block := RejectBlock(string(exchange)); block != "" {
+			logging.Warnf("executor", "%s - Held by reject back-off: %s", logTag(ctx, exchange, command), block)
+			return nil, 0.00, errors.New(block)
+		}
+		if err := risk.Reserve(string(exchange), market, pairName, amountUSDT); err != nil {
+			logging.Warnf("executor", "%s - Rejected by risk ledger: %s", logTag(ctx, exchange, command), err)
+			return nil, 0.00, err
+		}
+	}
+
+	limitClient, hasLimit := client.(common.LimitOrderClient)
+	maker := market != "margin" && market != "inverse" && !isLongLeg && useMaker(ctx, exchange, market, spreadPct)
+	if maker && !hasLimit {
+		logging.Warnf("executor", "%s - Maker mode configured but venue has no limit order support, using taker", logTag(ctx, exchange, command))
+	}
+
+	iocPrice, ioc := limitPrice(ctx)
+	ioc = ioc && (command == common.PutSpotLong || command == common.PutFuturesShort)
+	iocClient, hasIOC := client.(common.IOCOrderClient)
+
+	var result *common.TradeResult
+	submittedAt := time.Now()
+
+	// Chaos mode delays and rejects orders the way a struggling venue would
+	err = chaos.BeforeOrder(ctx, string(exchange))
+
+	switch {
+	case err != nil:
+	case ioc && !hasIOC:
+		err = fmt.Errorf("%s does not support IOC orders: %w", exchange, common.ErrOrderFailed)
+	case ioc:
+		result, err = executeIOC(ctx, client, iocClient, exchange, command, pairName, amountUSDT, iocPrice)
+	case command == common.PutMarginShort:
+		result, err = marginClient.PutMarginShort(ctx, pairName, amountUSDT)
+	case command == common.CloseMarginShort:
+		result, profit, err = marginClient.CloseMarginShort(ctx, pairName)
+	case command == common.PutFuturesLong:
+		result, err = inventoryClient.PutFuturesLong(ctx, pairName, amountUSDT)
+	case command == common.CloseFuturesLong:
+		result, profit, err = inventoryClient.CloseFuturesLong(ctx, pairName)
+	case command == common.PutInverseShort:
+		result, err = inverseClient.PutInverseShort(ctx, pairName, amountUSDT)
+	case command == common.CloseInverseShort:
+		result, profit, err = inverseClient.CloseInverseShort(ctx, pairName)
+	case maker && hasLimit:
+		result, profit, err = executeMakerFirst(ctx, client, limitClient, exchange, command, pairName, amountUSDT)
+	default:
+		result, profit, err = executeTaker(ctx, client, command, pairName, amountUSDT)
+	}
+
+	completedAt := time.Now()
+	recordOrder(string(exchange), err)
+
+	if err != nil {
+		logging.Errorf("executor", "%s - Failed: %s", logTag(ctx, exchange, command), err)
+
+		// A failed open adds no exposure; a failed close keeps its reservation
+		// since the exchange may still hold the position
+		if action == "open" {
+			risk.Release(string(exchange), market, pairName)
+		}
+	} else {
+		logging.Infof("executor", "%s - Succeeded in %dms", logTag(ctx, exchange, command), completedAt.Sub(submittedAt).Milliseconds())
+
+		if action == "close" {
+			risk.Release(string(exchange), market, pairName)
+		}
+
+		publishExecution(ctx, exchange, pairName, side, action, amountUSDT, spreadPct, profit, result, submittedAt, completedAt)
+	}
+
+	return result, profit, err
+}
+
+// TradeInventory buys or sells a base quantity of spot inventory on an exchange
+// action is recorded on the published execution ("open"/"close" for reverse-trade legs, "rebalance" otherwise)
+func TradeInventory(ctx context.Context, exchange common.ExchangeType, command common.OrderType, pairName string, quantity float64, action string, spreadPct float64) (*common.TradeResult, error) {
+	logging.Infof("executor", "%s - Starting", logTag(ctx, exchange, command))
+
+	client, err := getOrCreateClient(exchange)
+	if err != nil {
+		return nil, err
+	}
+
+	inventoryClient, ok := client.(common.InventoryClient)
+	if !ok {
+		return nil, fmt.Errorf("%s does not support inventory trading", exchange)
+	}
+
+	var result *common.TradeResult
+	submittedAt := time.Now()
+
+	if err := chaos.BeforeOrder(ctx, string(exchange)); err != nil {
+		recordOrder(string(exchange), err)
+		logging.Errorf("executor", "%s - Failed: %s", logTag(ctx, exchange, command), err)
+		return nil, err
+	}
+
+	switch command {
+	case common.SellSpotInventory:
+		result, err = inventoryClient.SellSpot(ctx, pairName, quantity)
+	case common.BuySpotInventory:
+		result, err = inventoryClient.BuySpot(ctx, pairName, quantity)
+	default:
+		return nil, fmt.Errorf("unknown inventory command: %s", command)
+	}
+
+	completedAt := time.Now()
+	recordOrder(string(exchange), err)
+	if err != nil {
+		logging.Errorf("executor", "%s - Failed: %s", logTag(ctx, exchange, command), err)
+		return nil, err
+	}
+
+	logging.Infof("executor", "%s - Succeeded in %dms", logTag(ctx, exchange, command), completedAt.Sub(submittedAt).Milliseconds())
+
+	amountUSDT := 0.0
+	if result != nil {
+		amountUSDT = result.ExecutedQty * result.ExecutedPrice
+	}
+	publishExecution(ctx, exchange, pairName, "spot_inventory", action, amountUSDT, spreadPct, 0.00, result, submittedAt, completedAt)
+
+	return result, nil
+}
+
+// Reduce closes a base quantity of a forward position's leg, leaving the rest open
+// command is CloseSpotLong or CloseFuturesShort; the leg keeps its risk reservation until fully closed
+func Reduce(ctx context.Context, exchange common.ExchangeType, command common.OrderType, pairName string, quantity float64, spreadPct float64) (*common.TradeResult, error) {
+	logging.Infof("executor", "%s - Starting partial close", logTag(ctx, exchange, command))
+
+	client, err := getOrCreateClient(exchange)
+	if err != nil {
+		return nil, err
+	}
+
+	partialClient, ok := client.(common.PartialCloseClient)
+	if !ok {
+		return nil, fmt.Errorf("%s does not support partial closes", exchange)
+	}
+
+	var result *common.TradeResult
+	var side string
+	submittedAt := time.Now()
+
+	if err := chaos.BeforeOrder(ctx, string(exchange)); err != nil {
+		recordOrder(string(exchange), err)
+		logging.Errorf("executor", "%s - Partial close failed: %s", logTag(ctx, exchange, command), err)
+		return nil, err
 	}
 
 	switch command {
-	case common.PutSpotLong:
-		_, err = client.PutSpotLong(ctx, pairName, amountUSDT)
 	case common.CloseSpotLong:
-		_, profit, err = client.CloseSpotLong(ctx, pairName, amountUSDT)
-	case common.PutFuturesShort:
-		_, err = client.PutFuturesShort(ctx, pairName, amountUSDT)
+		side = "spot_long"
+		result, err = partialClient.ReduceSpotLong(ctx, pairName, quantity)
 	case common.CloseFuturesShort:
-		_, profit, err = client.CloseFuturesShort(ctx, pairName)
+		side = "futures_short"
+		result, err = partialClient.ReduceFuturesShort(ctx, pairName, quantity)
 	default:
-		return 0.00, fmt.Errorf("unknown command: %s", command)
+		return nil, fmt.Errorf("unknown partial close command: %s", command)
 	}
 
+	completedAt := time.Now()
+	recordOrder(string(exchange), err)
 	if err != nil {
-		fmt.Printf("[%s] |%s| - Failed: %s\n", exchange, command, err)
-	} else {
-		fmt.Printf("[%s] |%s| - Succeeded\n", exchange, command)
-
-		// Publish successful trade execution to Redis
-		redis.PublishTradeExecution(redis.TradeExecution{
-			Exchange:  string(exchange),
-			Pair:      pairName,
-			Side:      side,
-			Action:    action,
-			Amount:    amountUSDT,
-			Price:     0, // Price will be added from position context if needed
-			SpreadPct: 0, // Spread will be added from position context if needed
-			Timestamp: time.Now(),
-		})
-	}
-
-	return profit, err
+		logging.Errorf("executor", "%s - Partial close failed: %s", logTag(ctx, exchange, command), err)
+		return nil, err
+	}
+
+	logging.Infof("executor", "%s - Partial close succeeded in %dms", logTag(ctx, exchange, command), completedAt.Sub(submittedAt).Milliseconds())
+
+	amountUSDT := 0.0
+	if result != nil {
+		amountUSDT = result.ExecutedQty * result.ExecutedPrice
+	}
+	publishExecution(ctx, exchange, pairName, side, "close", amountUSDT, spreadPct, 0.00, result, submittedAt, completedAt)
+
+	return result, nil
+}
+
+// SupportsPartialClose reports whether the exchange's client can close part of a position
+func SupportsPartialClose(exchange common.ExchangeType) bool {
+	client, err := getOrCreateClient(exchange)
+	if err != nil {
+		return false
+	}
+	_, ok := client.(common.PartialCloseClient)
+	return ok
+}
+
+// SupportsIOC reports whether the exchange's client can send immediate-or-cancel limit orders
+func SupportsIOC(exchange common.ExchangeType) bool {
+	client, err := getOrCreateClient(exchange)
+	if err != nil {
+		return false
+	}
+	_, ok := client.(common.IOCOrderClient)
+	return ok
+}
+
+// logTag prefixes an order's log lines with its venue, command and the trade ID it belongs to
+func logTag(ctx context.Context, exchange common.ExchangeType, command common.OrderType) string {
+	if id := common.TradeID(ctx); id != "" {
+		return fmt.Sprintf("[%s] |%s| %s", exchange, command, id)
+	}
+	return fmt.Sprintf("[%s] |%s|", exchange, command)
+}
+
+// publishExecution publishes a completed leg to Redis
+func publishExecution(ctx context.Context, exchange common.ExchangeType, pairName, side, action string, amountUSDT, spreadPct, profit float64,
+	result *common.TradeResult, submittedAt, completedAt time.Time) {
+
+	tag := common.Strategy(ctx)
+	trade := redis.TradeExecution{
+		TradeID:       common.TradeID(ctx),
+		Strategy:      tag.Strategy,
+		ConfigVersion: tag.ConfigVersion,
+		Experiment:    tag.Experiment,
+		Variant:       tag.Variant,
+		Exchange:      string(exchange),
+		Pair:          pairName,
+		Side:          side,
+		Action:        action,
+		Amount:        amountUSDT,
+		SpreadPct:     spreadPct,
+		Profit:        profit,
+		SubmittedAt:   submittedAt,
+		CompletedAt:   completedAt,
+		LatencyMs:     float64(completedAt.Sub(submittedAt).Microseconds()) / 1000.0,
+		Timestamp:     completedAt,
+	}
+	if result != nil {
+		trade.Price = result.ExecutedPrice
+		trade.Quantity = result.ExecutedQty
+		trade.Fee = result.Fee
+		trade.OrderID = result.OrderID
+		trade.Filled = result.Success
+	}
+
+	redis.PublishTradeExecution(trade)
+}
+
+// PrewarmConnections creates the clients for the given exchanges and opens their pooled
+// HTTP connections, then health-checks failover hosts and re-touches the pools every
+// HTTP_KEEPWARM_INTERVAL so idle connections don't expire
+func PrewarmConnections(exchanges []string) {
+	prewarmers := make(map[string]common.Prewarmer)
+	for _, exchange := range exchanges {
+		client, err := getOrCreateClient(common.ExchangeType(exchange))
+		if err != nil {
+			log.Printf("[HTTP] Prewarm %s skipped: %v", exchange, err)
+			continue
+		}
+		if p, ok := client.(common.Prewarmer); ok {
+			prewarmers[exchange] = p
+		}
+	}
+
+	// Health-check failover hosts first so the pools are warmed on the active ones
+	warm := func() {
+		var wg sync.WaitGroup
+		for exchange, p := range prewarmers {
+			wg.Add(1)
+			go func(exchange string, p common.Prewarmer) {
+				defer wg.Done()
+				ctx := context.Background()
+				common.CheckEndpoints(ctx, exchange)
+				p.Prewarm(ctx)
+			}(exchange, p)
+		}
+		wg.Wait()
+	}
+	warm()
+	log.Printf("[HTTP] Prewarmed %d exchange connection pools", len(prewarmers))
+
+	interval := config.GetDuration("HTTP_KEEPWARM_INTERVAL", 30*time.Second)
+	if interval <= 0 || len(prewarmers) == 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			warm()
+		}
+	}()
+}
+
+// IsFlat reports whether the exchange holds no tradable exposure for the pair on the given market
+// Spot dust below the pair's quantity precision counts as flat
+func IsFlat(ctx context.Context, exchange common.ExchangeType, market string, pairName string) (bool, error) {
+	client, err := getOrCreateClient(exchange)
+	if err != nil {
+		return false, err
+	}
+
+	switch market {
+	case "spot":
+		holding, err := client.GetSpotHolding(ctx, pairName)
+		if err != nil {
+			return false, err
+		}
+		return common.IsZero(common.RoundQuantity(holding, pairName)), nil
+	case "futures":
+		size, err := client.GetFuturesPosition(ctx, pairName)
+		if err != nil {
+			return false, err
+		}
+		return common.IsZero(size), nil
+	case "inverse":
+		inverseClient, ok := client.(common.InverseClient)
+		if !ok {
+			return false, fmt.Errorf("%s does not support coin-margined perpetuals", exchange)
+		}
+		contracts, err := inverseClient.GetInversePosition(ctx, pairName)
+		if err != nil {
+			return false, err
+		}
+		return common.IsZero(contracts), nil
+	default:
+		return false, fmt.Errorf("unknown market: %s", market)
+	}
+}
+
+// SpotHolding returns the free base-asset balance an exchange holds for the pair
+func SpotHolding(ctx context.Context, exchange common.ExchangeType, pairName string) (float64, error) {
+	client, err := getOrCreateClient(exchange)
+	if err != nil {
+		return 0, err
+	}
+	return client.GetSpotHolding(ctx, pairName)
+}
+
+// UsesInverse reports whether forward trades on the exchange hedge the pair with a coin-margined perp
+func UsesInverse(exchange common.ExchangeType, pairName string) bool {
+	if !common.InverseHedge(string(exchange), pairName) {
+		return false
+	}
+	client, err := getOrCreateClient(exchange)
+	if err != nil {
+		return false
+	}
+	_, ok := client.(common.InverseClient)
+	return ok
+}
+
+// SupportsInventory reports whether an exchange can trade the reverse (inventory) direction
+func SupportsInventory(exchange common.ExchangeType) bool {
+	client, err := getOrCreateClient(exchange)
+	if err != nil {
+		return false
+	}
+	_, ok := client.(common.InventoryClient)
+	return ok
 }
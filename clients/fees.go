@@ -0,0 +1,23 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+
+	"arbitrage.trade/clients/common"
+)
+
+// FeeRate returns the account's current fee rate for a pair on an exchange market ("spot" or "futures")
+// Paper mode has no account to ask, so it always errors there
+func FeeRate(ctx context.Context, exchange common.ExchangeType, market, pairName string) (*common.FeeRate, error) {
+	client, err := getOrCreateClient(exchange)
+	if err != nil {
+		return nil, err
+	}
+
+	feeClient, ok := client.(common.FeeRateClient)
+	if !ok {
+		return nil, fmt.Errorf("%s does not report fee rates", exchange)
+	}
+	return feeClient.GetFeeRate(ctx, market, pairName)
+}
@@ -0,0 +1,67 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/config"
+)
+
+var (
+	// Credential-less clients for public market data in paper mode
+	publicClients   = make(map[common.ExchangeType]common.ExchangeTradeClient)
+	publicClientsMu sync.Mutex
+)
+
+// marketDataClient returns a client for an exchange's public endpoints
+// Paper mode uses a credential-less live client, since the paper client only simulates orders
+func marketDataClient(exchange common.ExchangeType) (common.ExchangeTradeClient, error) {
+	if !IsPaperTrading() {
+		return getOrCreateClient(exchange)
+	}
+
+	publicClientsMu.Lock()
+	defer publicClientsMu.Unlock()
+
+	if client, ok := publicClients[exchange]; ok {
+		return client, nil
+	}
+	constructor, ok := exchangeRegistry[exchange]
+	if !ok {
+		return nil, fmt.Errorf("unknown exchange: %s", exchange)
+	}
+	client := constructor(config.Credentials{Exchange: string(exchange)})
+	publicClients[exchange] = client
+	return client, nil
+}
+
+// FundingRate returns the current perp funding rate of a pair on an exchange
+func FundingRate(ctx context.Context, exchange common.ExchangeType, pairName string) (*common.FundingRate, error) {
+	client, err := marketDataClient(exchange)
+	if err != nil {
+		return nil, err
+	}
+
+	fundingClient, ok := client.(common.FundingClient)
+	if !ok {
+		return nil, fmt.Errorf("%s does not publish funding rates", exchange)
+	}
+	return fundingClient.GetFundingRate(ctx, pairName)
+}
+
+// FundingHistory returns the settled perp funding rates of a pair on an exchange in [from, to), oldest first
+func FundingHistory(ctx context.Context, exchange common.ExchangeType, pairName string, from, to time.Time) ([]common.FundingRate, error) {
+	client, err := marketDataClient(exchange)
+	if err != nil {
+		return nil, err
+	}
+
+	historyClient, ok := client.(common.FundingHistoryClient)
+	if !ok {
+		return nil, fmt.Errorf("%s does not publish funding history", exchange)
+	}
+	return historyClient.GetFundingHistory(ctx, pairName, from, to)
+}
@@ -0,0 +1,111 @@
+package gate
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/clients/fixturetest"
+)
+
+const (
+	fixtureKey    = "fixture-key"
+	fixtureSecret = "fixture-secret"
+	fixturePair   = "xrp-usdt"
+)
+
+// TestFixtureReplay replays an open/close cycle of both legs from testdata/gate.json
+func TestFixtureReplay(t *testing.T) {
+	rec := fixturetest.Replay(t, "gate", "testdata")
+	client := NewGateClient(fixtureKey, fixtureSecret)
+	ctx := context.Background()
+
+	if holding, err := client.GetSpotHolding(ctx, fixturePair); err != nil || holding != 0 {
+		t.Errorf("GetSpotHolding = %v, %v, want 0", holding, err)
+	}
+	if balance, err := client.GetUSDTBalance(ctx, "spot"); err != nil || common.NotEqual(balance, 1000.5) {
+		t.Errorf("GetUSDTBalance spot = %v, %v, want 1000.5", balance, err)
+	}
+
+	// The buy is polled until closed; its XRP fee is converted to USDT at the fill price
+	spot, err := client.PutSpotLong(ctx, fixturePair, 20)
+	fixturetest.CheckResult(t, "PutSpotLong", spot, err, common.TradeResult{
+		OrderID: "600001", ExecutedPrice: 0.55, ExecutedQty: 36.3, Fee: 0.019965, Success: true,
+	})
+	buy := jsonBody(t, rec.Find(t, "POST", "/api/v4/spot/orders"))
+	if buy["side"] != "buy" || buy["amount"] != "20.00000000" {
+		t.Errorf("spot buy order = %v, want buy for 20.00000000 USDT", buy)
+	}
+
+	perp, err := client.PutFuturesShort(ctx, fixturePair, 20)
+	fixturetest.CheckResult(t, "PutFuturesShort", perp, err, common.TradeResult{
+		OrderID: "700001", ExecutedPrice: 0.5498, ExecutedQty: 36, Fee: 0.0098964, Success: true,
+	})
+	if short := jsonBody(t, rec.Find(t, "POST", "/api/v4/futures/usdt/orders")); short["size"] != -36.0 || short["reduce_only"] != false {
+		t.Errorf("futures short order = %v, want size -36", short)
+	}
+
+	if position, err := client.GetFuturesPosition(ctx, fixturePair); err != nil || common.NotEqual(position, -36) {
+		t.Errorf("GetFuturesPosition = %v, %v, want -36", position, err)
+	}
+
+	perpClose, _, err := client.CloseFuturesShort(ctx, fixturePair)
+	fixturetest.CheckResult(t, "CloseFuturesShort", perpClose, err, common.TradeResult{
+		OrderID: "700002", ExecutedPrice: 0.5502, ExecutedQty: 36, Fee: 0.0099036, Success: true,
+	})
+	if position, err := client.GetFuturesPosition(ctx, fixturePair); err != nil || position != 0 {
+		t.Errorf("GetFuturesPosition after close = %v, %v, want 0", position, err)
+	}
+
+	spotClose, _, err := client.CloseSpotLong(ctx, fixturePair, 20)
+	fixturetest.CheckResult(t, "CloseSpotLong", spotClose, err, common.TradeResult{
+		OrderID: "600002", ExecutedPrice: 0.5505, ExecutedQty: 36.2, Fee: 0.0199281, Success: true,
+	})
+
+	checkSignatures(t, rec)
+	fixturetest.CheckReplayed(t, "gate")
+}
+
+func jsonBody(t *testing.T, req fixturetest.Request) map[string]interface{} {
+	t.Helper()
+	var body map[string]interface{}
+	if err := json.Unmarshal([]byte(req.Body), &body); err != nil {
+		t.Fatalf("%s %s body %q: %v", req.Method, req.URL.Path, req.Body, err)
+	}
+	return body
+}
+
+// checkSignatures verifies every signed request carries the key and a hex HMAC-SHA512 of
+// method, path, query, body hash and timestamp
+func checkSignatures(t *testing.T, rec *fixturetest.Recorder) {
+	t.Helper()
+	signed := 0
+	for _, req := range rec.Requests() {
+		signature := req.Header.Get("SIGN")
+		if signature == "" {
+			continue
+		}
+		signed++
+
+		bodyHash := sha512.Sum512([]byte(req.Body))
+		message := strings.Join([]string{
+			req.Method, req.URL.Path, req.URL.RawQuery, hex.EncodeToString(bodyHash[:]), req.Header.Get("Timestamp"),
+		}, "\n")
+		mac := hmac.New(sha512.New, []byte(fixtureSecret))
+		mac.Write([]byte(message))
+		if want := hex.EncodeToString(mac.Sum(nil)); signature != want {
+			t.Errorf("%s %s signature = %s, want %s", req.Method, req.URL.Path, signature, want)
+		}
+		if req.Header.Get("KEY") != fixtureKey {
+			t.Errorf("%s %s sent without the API key", req.Method, req.URL.Path)
+		}
+	}
+	if signed == 0 {
+		t.Error("no signed request was sent")
+	}
+}
@@ -0,0 +1,37 @@
+package gate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"arbitrage.trade/clients/common"
+)
+
+// GetFundingRate returns the USDT-settled contract's current funding rate
+func (g *GateClient) GetFundingRate(ctx context.Context, pairName string) (*common.FundingRate, error) {
+	url := fmt.Sprintf("%s/api/v4/futures/usdt/contracts/%s", g.baseURL, g.normalizeSymbolFutures(pairName))
+
+	resp, err := g.rest.Get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Status != http.StatusOK {
+		return nil, fmt.Errorf("gate contract %s: status %d", pairName, resp.Status)
+	}
+
+	var contract struct {
+		FundingRate      string `json:"funding_rate"`
+		FundingNextApply int64  `json:"funding_next_apply"`
+	}
+	if err := json.Unmarshal(resp.Body, &contract); err != nil {
+		return nil, err
+	}
+
+	rate, _ := strconv.ParseFloat(contract.FundingRate, 64)
+	return &common.FundingRate{Rate: rate, FundingTime: time.Unix(contract.FundingNextApply, 0)}, nil
+}
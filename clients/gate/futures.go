@@ -3,25 +3,24 @@ package gate
 import (
 	"context"
 	"fmt"
+	"log"
 	"strconv"
 
 	"arbitrage.trade/clients/common"
 )
 
 func (g *GateClient) getFuturesBalance(ctx context.Context) (float64, error) {
-	var balances []FuturesBalance
-	if err := g.signedRequest(ctx, "GET", "/api/v4/futures/usdt/accounts", "", &balances); err != nil {
+	// The settle currency's account is a single object, not a list
+	var balance FuturesBalance
+	if err := g.signedRequest(ctx, "GET", "/api/v4/futures/usdt/accounts", "", &balance); err != nil {
 		return 0, fmt.Errorf("failed to get futures balance: %w", err)
 	}
 
-	for _, bal := range balances {
-		if bal.Currency == "USDT" {
-			available, _ := strconv.ParseFloat(bal.Available, 64)
-			return available, nil
-		}
+	if balance.Currency != "USDT" {
+		return 0, nil
 	}
-
-	return 0, nil
+	available, _ := strconv.ParseFloat(balance.Available, 64)
+	return available, nil
 }
 
 func (g *GateClient) getFuturesPosition(ctx context.Context, contract string) (*FuturesPosition, error) {
@@ -39,9 +38,26 @@ func (g *GateClient) getFuturesPosition(ctx context.Context, contract string) (*
 	return nil, nil
 }
 
+// GetFuturesPosition returns the signed position size for the pair, converted from contracts to base units
+func (g *GateClient) GetFuturesPosition(ctx context.Context, pairName string) (float64, error) {
+	position, err := g.getFuturesPosition(ctx, g.normalizeSymbolFutures(pairName))
+	if err != nil {
+		return 0, err
+	}
+	if position == nil {
+		return 0, nil
+	}
+	return float64(position.Size) * common.FuturesMultiplier(g.GetName(), pairName), nil
+}
+
 func (g *GateClient) PutFuturesShort(ctx context.Context, pairName string, amountUSDT float64) (*common.TradeResult, error) {
 	contract := g.normalizeSymbolFutures(pairName)
 
+	if err := g.setupContract(ctx, contract); err != nil {
+		log.Printf("[GATE] PutFuturesShort - ERROR: %v", err)
+		return nil, err
+	}
+
 	balance, err := g.getFuturesBalance(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get futures balance: %w", err)
@@ -139,7 +155,7 @@ func (g *GateClient) CloseFuturesShort(ctx context.Context, pairName string) (*c
 	prevBalance := common.GetBalance(g.GetName(), "futures", "USDT")
 	common.SetBalance(g.GetName(), "futures", "USDT", newBalance)
 
-	profit := newBalance - prevBalance
+	profit := common.Diff(newBalance, prevBalance)
 
 	fillPrice, _ := strconv.ParseFloat(response.FillPrice, 64)
 	actualSize := float64(response.Size)
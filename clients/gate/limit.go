@@ -0,0 +1,118 @@
+package gate
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+
+	"arbitrage.trade/clients/common"
+)
+
+// Resting (maker) limit orders
+//
+// Maker legs rest a post-only order (time in force "poc"), which Gate cancels instead of letting it
+// take liquidity. Futures sizes are whole contracts, negative to sell, converted with the pair's
+// perp multiplier; a futures fill's fee is its notional at the order's maker rate, negative for a
+// rebate. Fills are reported in base units.
+
+// PlaceLimitOrder rests a post-only limit order for a base quantity and returns its order ID
+func (g *GateClient) PlaceLimitOrder(ctx context.Context, pairName string, market string, side string, price float64, quantity float64) (string, error) {
+	if market == "futures" {
+		contract := g.normalizeSymbolFutures(pairName)
+		multiplier := common.FuturesMultiplier(g.GetName(), pairName)
+		size := int64(math.Floor(quantity/multiplier + 1e-9))
+		if size < 1 {
+			return "", fmt.Errorf("%s quantity %.8f is less than one contract", contract, quantity)
+		}
+		if side == "sell" {
+			size = -size
+		}
+
+		// Buys only ever close the short leg
+		orderBody := fmt.Sprintf(`{
+			"contract": "%s",
+			"size": %d,
+			"price": "%s",
+			"tif": "poc",
+			"reduce_only": %t
+		}`, contract, size, common.FormatPrice(price*multiplier, pairName), side == "buy")
+
+		var response FuturesOrderResponse
+		if err := g.signedRequest(ctx, "POST", "/api/v4/futures/usdt/orders", orderBody, &response); err != nil {
+			return "", fmt.Errorf("limit order failed: %w", err)
+		}
+		return strconv.FormatInt(response.ID, 10), nil
+	}
+
+	orderBody := fmt.Sprintf(`{
+		"currency_pair": "%s",
+		"side": "%s",
+		"amount": "%s",
+		"price": "%s",
+		"type": "limit",
+		"time_in_force": "poc"
+	}`, g.normalizeSymbol(pairName), side, common.FormatQuantity(common.RoundQuantity(quantity, pairName), pairName), common.FormatPrice(price, pairName))
+
+	var response SpotOrderResponse
+	if err := g.signedRequest(ctx, "POST", "/api/v4/spot/orders", orderBody, &response); err != nil {
+		return "", fmt.Errorf("limit order failed: %w", err)
+	}
+	return response.ID, nil
+}
+
+// GetLimitOrder returns a limit order's fill in base units and its fee in USDT
+func (g *GateClient) GetLimitOrder(ctx context.Context, pairName string, market string, orderID string) (*common.TradeResult, bool, error) {
+	if market == "futures" {
+		var order FuturesOrderResponse
+		if err := g.signedRequest(ctx, "GET", "/api/v4/futures/usdt/orders/"+orderID, "", &order); err != nil {
+			return nil, false, fmt.Errorf("failed to get order %s: %w", orderID, err)
+		}
+
+		multiplier := common.FuturesMultiplier(g.GetName(), pairName)
+		filled := math.Abs(float64(order.Size - order.Left))
+		fillPrice, _ := strconv.ParseFloat(order.FillPrice, 64)
+		makerRate, _ := strconv.ParseFloat(order.Mkfr, 64)
+
+		return common.ToBaseUnits(&common.TradeResult{
+			OrderID:       orderID,
+			ExecutedPrice: fillPrice,
+			ExecutedQty:   filled,
+			Fee:           filled * fillPrice * makerRate,
+			Success:       order.Status == "finished" && order.Left == 0,
+		}, multiplier), order.Status == "finished", nil
+	}
+
+	var order SpotOrderResponse
+	endpoint := fmt.Sprintf("/api/v4/spot/orders/%s?currency_pair=%s", orderID, g.normalizeSymbol(pairName))
+	if err := g.signedRequest(ctx, "GET", endpoint, "", &order); err != nil {
+		return nil, false, fmt.Errorf("failed to get order %s: %w", orderID, err)
+	}
+
+	amount, avgPrice, fee := g.spotFill(&order, pairName)
+	return &common.TradeResult{
+		OrderID:       orderID,
+		ExecutedPrice: avgPrice,
+		ExecutedQty:   amount,
+		Fee:           fee,
+		Success:       order.Status == "closed",
+	}, order.Status != "open", nil
+}
+
+// CancelLimitOrder cancels a resting limit order; one that finished in the meantime is not an error
+func (g *GateClient) CancelLimitOrder(ctx context.Context, pairName string, market string, orderID string) error {
+	endpoint := fmt.Sprintf("/api/v4/spot/orders/%s?currency_pair=%s", orderID, g.normalizeSymbol(pairName))
+	if market == "futures" {
+		endpoint = "/api/v4/futures/usdt/orders/" + orderID
+	}
+
+	var response map[string]interface{}
+	err := g.signedRequest(ctx, "DELETE", endpoint, "", &response)
+	if err == nil {
+		return nil
+	}
+	if _, done, getErr := g.GetLimitOrder(ctx, pairName, market, orderID); getErr == nil && done {
+		return nil
+	}
+	return fmt.Errorf("failed to cancel order %s: %w", orderID, err)
+}
@@ -1,24 +1,44 @@
 package gate
 
 import (
-	"net/http"
-	"time"
+	"context"
+	"fmt"
+	"log"
 
 	"arbitrage.trade/clients/common"
 )
 
 func NewGateClient(apiKey, apiSecret string) *GateClient {
 	return &GateClient{
-		apiKey:    apiKey,
-		apiSecret: apiSecret,
-		baseURL:   "https://api.gateio.ws",
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-		positions: make(map[string]*common.Position),
+		apiKey:      apiKey,
+		apiSecret:   apiSecret,
+		baseURL:     "https://api.gateio.ws",
+		rest:        common.NewRESTClient("gate"),
+		positions:   make(map[string]*common.Position),
+		leverageSet: make(map[string]bool),
 	}
 }
 
 func (g *GateClient) GetName() string {
 	return "gate"
 }
+
+// Prewarm opens a pooled connection to the API and brings the futures account to single position mode
+func (g *GateClient) Prewarm(ctx context.Context) {
+	common.PrewarmConnections(ctx, g.rest.HTTP, g.baseURL+"/api/v4/spot/time")
+	if err := g.setupFuturesAccount(ctx); err != nil {
+		log.Printf("[GATE] Prewarm - ERROR: %v", err)
+	}
+}
+
+// GetUSDTBalance returns the available USDT on the given market ("spot" or "futures")
+func (g *GateClient) GetUSDTBalance(ctx context.Context, market string) (float64, error) {
+	switch market {
+	case "spot":
+		return g.getSpotBalance(ctx, "USDT")
+	case "futures":
+		return g.getFuturesBalance(ctx)
+	default:
+		return 0, fmt.Errorf("no USDT balance for market %s", market)
+	}
+}
@@ -0,0 +1,94 @@
+package gate
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"arbitrage.trade/config"
+)
+
+// Futures leverage and margin mode
+//
+// A Gate USDT futures account keeps whatever leverage and margin mode were last set on each contract,
+// so the client sets them itself instead of inheriting them. GATE_MARGIN_MODE picks "isolated" (default)
+// or "cross" and GATE_FUTURES_LEVERAGE the leverage (default 1). Orders are signed sizes on a single
+// position per contract, so an account in dual (hedge) mode is switched to single mode at startup, and
+// futures orders are refused while that is not possible (Gate only allows it with no open positions).
+
+// futuresMarginMode returns GATE_MARGIN_MODE, "isolated" or "cross"
+func futuresMarginMode() string {
+	mode := strings.ToLower(config.GetString("GATE_MARGIN_MODE", "isolated"))
+	if mode != "cross" && mode != "isolated" {
+		log.Printf("[GATE] Unknown GATE_MARGIN_MODE %q, using isolated", mode)
+		return "isolated"
+	}
+	return mode
+}
+
+// futuresLeverage returns GATE_FUTURES_LEVERAGE, at least 1
+func futuresLeverage() int {
+	return max(config.GetInt("GATE_FUTURES_LEVERAGE", 1), 1)
+}
+
+// setupFuturesAccount makes sure the account is in single position mode, once per client
+func (g *GateClient) setupFuturesAccount(ctx context.Context) error {
+	g.mu.RLock()
+	ready := g.accountReady
+	g.mu.RUnlock()
+	if ready {
+		return nil
+	}
+
+	var account struct {
+		InDualMode bool `json:"in_dual_mode"`
+	}
+	if err := g.signedRequest(ctx, "GET", "/api/v4/futures/usdt/accounts", "", &account); err != nil {
+		return fmt.Errorf("failed to get futures account: %w", err)
+	}
+
+	if account.InDualMode {
+		log.Printf("[GATE] Futures account is in dual mode, switching to single position mode")
+		if err := g.signedRequest(ctx, "POST", "/api/v4/futures/usdt/dual_mode?dual_mode=false", "", nil); err != nil {
+			return fmt.Errorf("futures account is in dual mode and switching it off failed (close open positions first): %w", err)
+		}
+	}
+
+	g.mu.Lock()
+	g.accountReady = true
+	g.mu.Unlock()
+	return nil
+}
+
+// setupContract sets the configured margin mode and leverage on the contract, once per client
+// Gate encodes cross margin as leverage 0, with the leverage going in cross_leverage_limit
+func (g *GateClient) setupContract(ctx context.Context, contract string) error {
+	if err := g.setupFuturesAccount(ctx); err != nil {
+		return err
+	}
+
+	g.mu.RLock()
+	done := g.leverageSet[contract]
+	g.mu.RUnlock()
+	if done {
+		return nil
+	}
+
+	mode, leverage := futuresMarginMode(), futuresLeverage()
+	endpoint := fmt.Sprintf("/api/v4/futures/usdt/positions/%s/leverage?leverage=%d", contract, leverage)
+	if mode == "cross" {
+		endpoint = fmt.Sprintf("/api/v4/futures/usdt/positions/%s/leverage?leverage=0&cross_leverage_limit=%d", contract, leverage)
+	}
+
+	var position FuturesPosition
+	if err := g.signedRequest(ctx, "POST", endpoint, "", &position); err != nil {
+		return fmt.Errorf("failed to set %s leverage %dx on %s: %w", mode, leverage, contract, err)
+	}
+
+	g.mu.Lock()
+	g.leverageSet[contract] = true
+	g.mu.Unlock()
+	log.Printf("[GATE] %s futures set to %s margin, %dx leverage", contract, mode, leverage)
+	return nil
+}
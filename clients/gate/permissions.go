@@ -0,0 +1,24 @@
+package gate
+
+import (
+	"context"
+
+	"arbitrage.trade/clients/common"
+)
+
+// GetAPIPermissions returns the key's IP whitelist from the account detail
+// Gate doesn't report a key's scopes, so only read access is verified
+func (g *GateClient) GetAPIPermissions(ctx context.Context) (*common.APIPermissions, error) {
+	var detail struct {
+		IPWhitelist []string `json:"ip_whitelist"`
+	}
+	if err := g.signedRequest(ctx, "GET", "/api/v4/account/detail", "", &detail); err != nil {
+		return nil, err
+	}
+
+	return &common.APIPermissions{
+		Read:         true, // The request above only succeeds with read access
+		IPRestricted: len(detail.IPWhitelist) > 0,
+		Unverified:   []string{common.ScopeSpotTrade, common.ScopeFuturesTrade},
+	}, nil
+}
@@ -3,8 +3,10 @@ package gate
 import (
 	"context"
 	"fmt"
+	"log"
 	"strconv"
 	"strings"
+	"time"
 
 	"arbitrage.trade/clients/common"
 )
@@ -25,6 +27,72 @@ func (g *GateClient) getSpotBalance(ctx context.Context, currency string) (float
 	return 0, nil
 }
 
+// Market order responses can come back before the order has finished filling, so fills are
+// polled from /api/v4/spot/orders/{id} until the order is closed or cancelled
+const (
+	spotFillPollInterval = 100 * time.Millisecond
+	spotFillPollTimeout  = 3 * time.Second
+)
+
+// awaitSpotFill polls a spot order until it finishes and returns its final state
+// Falls back to the last known state if the order is still open when the timeout expires
+func (g *GateClient) awaitSpotFill(ctx context.Context, symbol string, order *SpotOrderResponse) *SpotOrderResponse {
+	deadline := time.Now().Add(spotFillPollTimeout)
+	endpoint := fmt.Sprintf("/api/v4/spot/orders/%s?currency_pair=%s", order.ID, symbol)
+
+	for order.Status == "open" || order.Status == "" {
+		if time.Now().After(deadline) {
+			log.Printf("[GATE] awaitSpotFill - ERROR: Order %s still %q after %s, using partial fill", order.ID, order.Status, spotFillPollTimeout)
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return order
+		case <-time.After(spotFillPollInterval):
+		}
+
+		var polled SpotOrderResponse
+		if err := g.signedRequest(ctx, "GET", endpoint, "", &polled); err != nil {
+			log.Printf("[GATE] awaitSpotFill - ERROR: Failed to poll order %s: %v", order.ID, err)
+			continue
+		}
+		order = &polled
+	}
+
+	return order
+}
+
+// spotFill returns the executed base quantity, average price and fee in USDT of a finished spot order
+func (g *GateClient) spotFill(order *SpotOrderResponse, pairName string) (float64, float64, float64) {
+	filledTotal, _ := strconv.ParseFloat(order.FilledTotal, 64)
+	filledAmount, _ := strconv.ParseFloat(order.FilledAmount, 64)
+	avgPrice, _ := strconv.ParseFloat(order.AvgDealPrice, 64)
+	fee, _ := strconv.ParseFloat(order.Fee, 64)
+
+	if common.IsZero(filledAmount) && common.IsPositive(avgPrice) {
+		filledAmount = filledTotal / avgPrice
+	}
+
+	// Buys pay the fee in the base asset, sells in USDT; GT/point deductions are not counted
+	baseAsset := strings.Split(g.normalizeSymbol(pairName), "_")[0]
+	switch order.FeeCurrency {
+	case "USDT":
+	case baseAsset:
+		fee *= avgPrice
+	default:
+		fee = 0
+	}
+
+	return filledAmount, avgPrice, fee
+}
+
+// GetSpotHolding returns the available base-asset balance for the pair
+func (g *GateClient) GetSpotHolding(ctx context.Context, pairName string) (float64, error) {
+	baseAsset := strings.Split(g.normalizeSymbol(pairName), "_")[0]
+	return g.getSpotBalance(ctx, baseAsset)
+}
+
 func (g *GateClient) PutSpotLong(ctx context.Context, pairName string, amountUSDT float64) (*common.TradeResult, error) {
 	symbol := g.normalizeSymbol(pairName)
 
@@ -47,10 +115,9 @@ func (g *GateClient) PutSpotLong(ctx context.Context, pairName string, amountUSD
 		return nil, fmt.Errorf("market order failed: %w", err)
 	}
 
-	filledTotal, _ := strconv.ParseFloat(response.FilledTotal, 64)
-	amount, _ := strconv.ParseFloat(response.Amount, 64)
-	avgPrice, _ := strconv.ParseFloat(response.AvgDealPrice, 64)
-	fee, _ := strconv.ParseFloat(response.Fee, 64)
+	order := g.awaitSpotFill(ctx, symbol, &response)
+	amount, avgPrice, fee := g.spotFill(order, pairName)
+	filledTotal, _ := strconv.ParseFloat(order.FilledTotal, 64)
 
 	g.mu.Lock()
 	g.positions[pairName+"_spot"] = &common.Position{
@@ -60,17 +127,17 @@ func (g *GateClient) PutSpotLong(ctx context.Context, pairName string, amountUSD
 		EntryPrice:   avgPrice,
 		Quantity:     amount,
 		AmountUSDT:   filledTotal,
-		OrderID:      response.ID,
+		OrderID:      order.ID,
 		ExchangeName: g.GetName(),
 	}
 	g.mu.Unlock()
 
 	return &common.TradeResult{
-		OrderID:       response.ID,
+		OrderID:       order.ID,
 		ExecutedPrice: avgPrice,
 		ExecutedQty:   amount,
 		Fee:           fee,
-		Success:       response.Status == "closed",
+		Success:       order.Status == "closed",
 	}, nil
 }
 
@@ -109,6 +176,9 @@ func (g *GateClient) CloseSpotLong(ctx context.Context, pairName string, amountU
 		return nil, 0.0, fmt.Errorf("market order failed: %w", err)
 	}
 
+	// Settle the fill before reading the USDT balance the profit is taken from
+	order := g.awaitSpotFill(ctx, symbol, &response)
+
 	g.mu.Lock()
 	delete(g.positions, pairName+"_spot")
 	g.mu.Unlock()
@@ -121,17 +191,15 @@ func (g *GateClient) CloseSpotLong(ctx context.Context, pairName string, amountU
 	prevBalance := common.GetBalance(g.GetName(), "spot", "USDT")
 	common.SetBalance(g.GetName(), "spot", "USDT", newBalance)
 
-	profit := newBalance - prevBalance
+	profit := common.Diff(newBalance, prevBalance)
 
-	amount, _ := strconv.ParseFloat(response.Amount, 64)
-	avgPrice, _ := strconv.ParseFloat(response.AvgDealPrice, 64)
-	fee, _ := strconv.ParseFloat(response.Fee, 64)
+	amount, avgPrice, fee := g.spotFill(order, pairName)
 
 	return &common.TradeResult{
-		OrderID:       response.ID,
+		OrderID:       order.ID,
 		ExecutedPrice: avgPrice,
 		ExecutedQty:   amount,
 		Fee:           fee,
-		Success:       response.Status == "closed",
+		Success:       order.Status == "closed",
 	}, profit, nil
 }
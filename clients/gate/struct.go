@@ -1,20 +1,21 @@
 package gate
 
 import (
-	"net/http"
 	"sync"
 
 	"arbitrage.trade/clients/common"
 )
 
 type GateClient struct {
-	apiKey     string
-	apiSecret  string
-	baseURL    string
-	httpClient *http.Client
+	apiKey    string
+	apiSecret string
+	baseURL   string
+	rest      *common.RESTClient
 
-	positions map[string]*common.Position
-	mu        sync.RWMutex
+	positions    map[string]*common.Position
+	accountReady bool            // Futures account checked for single position mode
+	leverageSet  map[string]bool // Contracts with margin mode and leverage set
+	mu           sync.RWMutex
 }
 
 type SpotBalance struct {
@@ -30,12 +31,15 @@ type SpotOrderResponse struct {
 	Status       string `json:"status"`
 	Type         string `json:"type"`
 	Side         string `json:"side"`
-	Amount       string `json:"amount"`
+	Amount       string `json:"amount"` // Market buys: USDT to spend, otherwise base quantity
 	Price        string `json:"price"`
-	FilledTotal  string `json:"filled_total"`
+	Left         string `json:"left"`
+	FilledAmount string `json:"filled_amount"` // Base quantity filled
+	FilledTotal  string `json:"filled_total"`  // USDT filled
 	AvgDealPrice string `json:"avg_deal_price"`
 	Fee          string `json:"fee"`
 	FeeCurrency  string `json:"fee_currency"`
+	FinishAs     string `json:"finish_as"`
 	CreateTime   string `json:"create_time"`
 	CreateTimeMs string `json:"create_time_ms"`
 }
@@ -59,14 +63,16 @@ type FuturesPosition struct {
 }
 
 type FuturesOrderResponse struct {
-	ID         int64  `json:"id"`
-	Contract   string `json:"contract"`
-	Size       int64  `json:"size"`
-	Price      string `json:"price"`
-	Status     string `json:"status"`
-	FillPrice  string `json:"fill_price"`
-	Left       int64  `json:"left"`
-	TkfFee     string `json:"tkf_fee"`
-	CreateTime string `json:"create_time"`
-	FinishTime string `json:"finish_time"`
+	ID         int64   `json:"id"`
+	Contract   string  `json:"contract"`
+	Size       int64   `json:"size"`
+	Price      string  `json:"price"`
+	Status     string  `json:"status"`
+	FillPrice  string  `json:"fill_price"`
+	Left       int64   `json:"left"`
+	TkfFee     string  `json:"tkf_fee"`
+	Mkfr       string  `json:"mkfr"` // Maker fee rate, negative for a rebate
+	FinishAs   string  `json:"finish_as"`
+	CreateTime float64 `json:"create_time"` // Unix seconds with fractional milliseconds
+	FinishTime float64 `json:"finish_time"`
 }
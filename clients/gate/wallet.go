@@ -0,0 +1,57 @@
+package gate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"arbitrage.trade/clients/common"
+)
+
+// GetAssetStatus returns the deposit/withdrawal state of a currency across its chains
+func (g *GateClient) GetAssetStatus(ctx context.Context, asset string) (*common.AssetStatus, error) {
+	url := fmt.Sprintf("%s/api/v4/spot/currencies/%s", g.baseURL, asset)
+
+	resp, err := g.rest.Get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Status != http.StatusOK {
+		return nil, fmt.Errorf("gate currency %s: status %d", asset, resp.Status)
+	}
+
+	var currency struct {
+		Currency         string `json:"currency"`
+		Delisted         bool   `json:"delisted"`
+		DepositDisabled  bool   `json:"deposit_disabled"`
+		WithdrawDisabled bool   `json:"withdraw_disabled"`
+		Chains           []struct {
+			Name             string `json:"name"`
+			DepositDisabled  bool   `json:"deposit_disabled"`
+			WithdrawDisabled bool   `json:"withdraw_disabled"`
+		} `json:"chains"`
+	}
+	if err := json.Unmarshal(resp.Body, &currency); err != nil {
+		return nil, err
+	}
+
+	if len(currency.Chains) == 0 {
+		return &common.AssetStatus{
+			Asset:       asset,
+			CanDeposit:  !currency.Delisted && !currency.DepositDisabled,
+			CanWithdraw: !currency.Delisted && !currency.WithdrawDisabled,
+		}, nil
+	}
+
+	networks := make([]common.NetworkStatus, 0, len(currency.Chains))
+	for _, c := range currency.Chains {
+		networks = append(networks, common.NetworkStatus{
+			Network:     c.Name,
+			CanDeposit:  !currency.DepositDisabled && !c.DepositDisabled,
+			CanWithdraw: !currency.WithdrawDisabled && !c.WithdrawDisabled,
+		})
+	}
+	return common.NewAssetStatus(asset, networks), nil
+}
@@ -0,0 +1,33 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/config"
+)
+
+// AccountFills downloads the venue account's fills of the pair on market in [from, to), oldest first
+func AccountFills(ctx context.Context, exchange common.ExchangeType, market, pairName string, from, to time.Time) ([]common.AccountFill, error) {
+	client, err := getOrCreateClient(exchange)
+	if err != nil {
+		return nil, err
+	}
+	historyClient, ok := client.(common.TradeHistoryClient)
+	if !ok {
+		return nil, fmt.Errorf("%s does not support trade history import", exchange)
+	}
+	return historyClient.GetFills(ctx, market, pairName, from, to)
+}
+
+// SupportsTradeHistory reports whether the exchange's client can download account fills
+func SupportsTradeHistory(exchange common.ExchangeType) bool {
+	constructor, ok := exchangeRegistry[exchange]
+	if !ok {
+		return false
+	}
+	_, ok = constructor(config.Credentials{Exchange: string(exchange)}).(common.TradeHistoryClient)
+	return ok
+}
@@ -0,0 +1,142 @@
+package hyperliquid
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/clients/fixturetest"
+)
+
+const (
+	fixtureAddress    = "0x8c3e7a1f5b2d4e6a9c0b1d2e3f4a5b6c7d8e9f01"
+	fixturePrivateKey = "0x0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	fixturePair       = "xrp-usdt"
+)
+
+// TestFixtureReplay replays an open/close cycle of the perp leg from testdata/hyperliquid.json
+func TestFixtureReplay(t *testing.T) {
+	rec := fixturetest.Replay(t, "hyperliquid", "testdata")
+	client := NewHyperliquidClient(fixtureAddress, fixturePrivateKey)
+	ctx := context.Background()
+
+	if balance, err := client.GetUSDTBalance(ctx, "futures"); err != nil || common.NotEqual(balance, 500) {
+		t.Errorf("GetUSDTBalance futures = %v, %v, want 500", balance, err)
+	}
+	if _, err := client.PutSpotLong(ctx, fixturePair, 20); err == nil {
+		t.Error("PutSpotLong succeeded on a perp-only venue")
+	}
+
+	// XRP sizes have no decimals, so 20 USDT at 0.5498 sells 36
+	perp, err := client.PutFuturesShort(ctx, fixturePair, 20)
+	fixturetest.CheckResult(t, "PutFuturesShort", perp, err, common.TradeResult{
+		OrderID: "77738308", ExecutedPrice: 0.5498, ExecutedQty: 36, Success: true,
+	})
+
+	if position, err := client.GetFuturesPosition(ctx, fixturePair); err != nil || common.NotEqual(position, -36) {
+		t.Errorf("GetFuturesPosition = %v, %v, want -36", position, err)
+	}
+
+	perpClose, profit, err := client.CloseFuturesShort(ctx, fixturePair)
+	fixturetest.CheckResult(t, "CloseFuturesShort", perpClose, err, common.TradeResult{
+		OrderID: "77738309", ExecutedPrice: 0.5502, ExecutedQty: 36, Success: true,
+	})
+	if common.NotEqual(profit, -0.03) {
+		t.Errorf("CloseFuturesShort profit = %v, want -0.03", profit)
+	}
+	if position, err := client.GetFuturesPosition(ctx, fixturePair); err != nil || position != 0 {
+		t.Errorf("GetFuturesPosition after close = %v, %v, want 0", position, err)
+	}
+
+	orders := rec.FindAll("POST", "/exchange")
+	want := []OrderWire{
+		{Asset: 2, IsBuy: false, Price: "0.52231", Size: "36", ReduceOnly: false},
+		{Asset: 2, IsBuy: true, Price: "0.57771", Size: "36", ReduceOnly: true},
+	}
+	if len(orders) != len(want) {
+		t.Fatalf("sent %d orders, want %d", len(orders), len(want))
+	}
+	for i, req := range orders {
+		var body struct {
+			Action OrderAction `json:"action"`
+		}
+		if err := json.Unmarshal([]byte(req.Body), &body); err != nil || len(body.Action.Orders) != 1 {
+			t.Fatalf("order body %q: %v", req.Body, err)
+		}
+		got := body.Action.Orders[0]
+		if got.Asset != want[i].Asset || got.IsBuy != want[i].IsBuy || got.Price != want[i].Price ||
+			got.Size != want[i].Size || got.ReduceOnly != want[i].ReduceOnly {
+			t.Errorf("order %d = %+v, want %+v", i, got, want[i])
+		}
+	}
+
+	checkSignatures(t, rec)
+	fixturetest.CheckReplayed(t, "hyperliquid")
+}
+
+// checkSignatures verifies every exchange request is an ECDSA signature by the fixture key over the
+// EIP-712 digest of the action and nonce it was sent with
+func checkSignatures(t *testing.T, rec *fixturetest.Recorder) {
+	t.Helper()
+	priv, err := parsePrivateKey(fixturePrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	public := scalarBaseMult(priv)
+
+	requests := rec.FindAll("POST", "/exchange")
+	if len(requests) == 0 {
+		t.Fatal("no exchange request was sent")
+	}
+	for _, req := range requests {
+		var body struct {
+			Action    OrderAction `json:"action"`
+			Nonce     int64       `json:"nonce"`
+			Signature Signature   `json:"signature"`
+		}
+		if err := json.Unmarshal([]byte(req.Body), &body); err != nil {
+			t.Fatalf("exchange body %q: %v", req.Body, err)
+		}
+
+		connectionID, err := actionHash(body.Action, body.Nonce)
+		if err != nil {
+			t.Fatal(err)
+		}
+		digest := agentDigest("a", connectionID)
+		if !verifySignature(public, digest, body.Signature) {
+			t.Errorf("exchange request %s is not signed by the fixture key", req.Body)
+		}
+	}
+}
+
+// verifySignature checks r and s against the public key and v against the parity of the signing point
+func verifySignature(public curvePoint, digest []byte, sig Signature) bool {
+	r, okR := new(big.Int).SetString(strings.TrimPrefix(sig.R, "0x"), 16)
+	s, okS := new(big.Int).SetString(strings.TrimPrefix(sig.S, "0x"), 16)
+	if !okR || !okS || s.Cmp(secpHalfN) > 0 {
+		return false
+	}
+
+	w := new(big.Int).ModInverse(s, secpN)
+	u1 := new(big.Int).Mul(new(big.Int).SetBytes(digest), w)
+	u2 := new(big.Int).Mul(r, w)
+	point := pointAdd(scalarBaseMult(u1.Mod(u1, secpN)), scalarMult(public, u2.Mod(u2, secpN)))
+	if point.infinity() || new(big.Int).Mod(point.x, secpN).Cmp(r) != 0 {
+		return false
+	}
+	return sig.V == 27+int(point.y.Bit(0))
+}
+
+func scalarMult(pt curvePoint, k *big.Int) curvePoint {
+	result := curvePoint{}
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		result = pointAdd(result, result)
+		if k.Bit(i) == 1 {
+			result = pointAdd(result, pt)
+		}
+	}
+	return result
+}
@@ -0,0 +1,219 @@
+package hyperliquid
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/config"
+)
+
+// Hyperliquid has no market orders; they are IOC limits priced HYPERLIQUID_SLIPPAGE
+// (default 5%) through the mid, like the official SDK does
+func slippage() float64 {
+	return config.GetFloat("HYPERLIQUID_SLIPPAGE", 0.05)
+}
+
+func (h *HyperliquidClient) getState(ctx context.Context) (*ClearinghouseState, error) {
+	var state ClearinghouseState
+	if err := h.infoRequest(ctx, map[string]string{"type": "clearinghouseState", "user": h.address}, &state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+func (h *HyperliquidClient) getFuturesBalance(ctx context.Context) (float64, error) {
+	state, err := h.getState(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get futures balance: %w", err)
+	}
+
+	withdrawable, _ := strconv.ParseFloat(state.Withdrawable, 64)
+	return withdrawable, nil
+}
+
+// getPositionSize returns the signed position size of a coin in coin units
+func (h *HyperliquidClient) getPositionSize(ctx context.Context, coin string) (float64, error) {
+	state, err := h.getState(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, asset := range state.AssetPositions {
+		if asset.Position.Coin == coin {
+			size, _ := strconv.ParseFloat(asset.Position.Szi, 64)
+			return size, nil
+		}
+	}
+
+	return 0, nil
+}
+
+// placeIOC sends an aggressive IOC limit order and returns its fill
+func (h *HyperliquidClient) placeIOC(ctx context.Context, asset AssetInfo, isBuy bool, size, mid float64, reduceOnly bool) (*OrderStatus, error) {
+	price := mid * (1 - slippage())
+	if isBuy {
+		price = mid * (1 + slippage())
+	}
+
+	action := OrderAction{
+		Type: "order",
+		Orders: []OrderWire{{
+			Asset:      asset.Index,
+			IsBuy:      isBuy,
+			Price:      formatPrice(price, asset.SzDecimals),
+			Size:       formatSize(size, asset.SzDecimals),
+			ReduceOnly: reduceOnly,
+			OrderType:  OrderType{Limit: LimitOrder{Tif: "Ioc"}},
+		}},
+		Grouping: "na",
+	}
+
+	raw, err := h.exchangeRequest(ctx, action)
+	if err != nil {
+		return nil, err
+	}
+
+	var response OrderResponse
+	if err := json.Unmarshal(raw, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal order response: %w", err)
+	}
+	if len(response.Data.Statuses) == 0 {
+		return nil, fmt.Errorf("no order status in response")
+	}
+
+	status := response.Data.Statuses[0]
+	if status.Error != "" {
+		return nil, fmt.Errorf("order rejected: %s", status.Error)
+	}
+	if status.Filled == nil {
+		return nil, fmt.Errorf("IOC order was not filled")
+	}
+
+	return &status, nil
+}
+
+// tradeResult converts a fill to base units
+// Fees are not part of the order response; they are deducted from margin and land in the balance diff
+func (h *HyperliquidClient) tradeResult(status *OrderStatus, multiplier float64) *common.TradeResult {
+	size, _ := strconv.ParseFloat(status.Filled.TotalSz, 64)
+	avgPrice, _ := strconv.ParseFloat(status.Filled.AvgPx, 64)
+
+	return common.ToBaseUnits(&common.TradeResult{
+		OrderID:       strconv.FormatInt(status.Filled.Oid, 10),
+		ExecutedPrice: avgPrice,
+		ExecutedQty:   size,
+		Success:       common.IsPositive(size),
+	}, multiplier)
+}
+
+// GetFuturesPosition returns the signed position size (in base units) for the pair
+func (h *HyperliquidClient) GetFuturesPosition(ctx context.Context, pairName string) (float64, error) {
+	coin, multiplier := Coin(pairName)
+	asset, err := h.assetInfo(ctx, coin)
+	if err != nil {
+		return 0, err
+	}
+
+	size, err := h.getPositionSize(ctx, asset.Name)
+	if err != nil {
+		return 0, err
+	}
+	return size * multiplier, nil
+}
+
+func (h *HyperliquidClient) PutFuturesShort(ctx context.Context, pairName string, amountUSDT float64) (*common.TradeResult, error) {
+	coin, multiplier := Coin(pairName)
+	asset, err := h.assetInfo(ctx, coin)
+	if err != nil {
+		return nil, err
+	}
+
+	balance, err := h.getFuturesBalance(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get futures balance: %w", err)
+	}
+
+	common.SetBalance(h.GetName(), "futures", "USDT", balance)
+
+	mid, err := h.getMid(ctx, asset.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price: %w", err)
+	}
+
+	size := amountUSDT / mid
+	if common.IsNegativeOrZero(size) {
+		return nil, fmt.Errorf("invalid order size for %.2f USDT at %.8f", amountUSDT, mid)
+	}
+
+	status, err := h.placeIOC(ctx, asset, false, size, mid, false)
+	if err != nil {
+		return nil, fmt.Errorf("market order failed: %w", err)
+	}
+
+	result := h.tradeResult(status, multiplier)
+
+	h.mu.Lock()
+	h.positions[pairName+"_futures"] = &common.Position{
+		PairName:     pairName,
+		Side:         "short",
+		Market:       "futures",
+		EntryPrice:   result.ExecutedPrice,
+		Quantity:     result.ExecutedQty,
+		AmountUSDT:   result.ExecutedQty * result.ExecutedPrice,
+		OrderID:      result.OrderID,
+		ExchangeName: h.GetName(),
+	}
+	h.mu.Unlock()
+
+	return result, nil
+}
+
+func (h *HyperliquidClient) CloseFuturesShort(ctx context.Context, pairName string) (*common.TradeResult, float64, error) {
+	coin, multiplier := Coin(pairName)
+	asset, err := h.assetInfo(ctx, coin)
+	if err != nil {
+		return nil, 0.0, err
+	}
+
+	size, err := h.getPositionSize(ctx, asset.Name)
+	if err != nil {
+		return nil, 0.0, fmt.Errorf("failed to get position: %w", err)
+	}
+
+	if !common.IsNegative(size) {
+		h.mu.Lock()
+		delete(h.positions, pairName+"_futures")
+		h.mu.Unlock()
+		return nil, 0.0, fmt.Errorf("no open position on exchange")
+	}
+
+	mid, err := h.getMid(ctx, asset.Name)
+	if err != nil {
+		return nil, 0.0, fmt.Errorf("failed to get price: %w", err)
+	}
+
+	status, err := h.placeIOC(ctx, asset, true, math.Abs(size), mid, true)
+	if err != nil {
+		return nil, 0.0, fmt.Errorf("close order failed: %w", err)
+	}
+
+	h.mu.Lock()
+	delete(h.positions, pairName+"_futures")
+	h.mu.Unlock()
+
+	newBalance, err := h.getFuturesBalance(ctx)
+	if err != nil {
+		return nil, 0.0, fmt.Errorf("failed to get futures balance: %w", err)
+	}
+
+	prevBalance := common.GetBalance(h.GetName(), "futures", "USDT")
+	common.SetBalance(h.GetName(), "futures", "USDT", newBalance)
+
+	profit := common.Diff(newBalance, prevBalance)
+
+	return h.tradeResult(status, multiplier), profit, nil
+}
@@ -0,0 +1,64 @@
+package hyperliquid
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/config"
+)
+
+// Hyperliquid is an on-chain perp DEX, so it only trades the short leg.
+// HYPERLIQUID_API_KEY is the account address and HYPERLIQUID_API_SECRET the hex private key
+// of that account or of an API wallet approved for it; requests are signed with EIP-712
+// instead of an HMAC. HYPERLIQUID_TESTNET=true targets the testnet. Orders are only routed
+// here with HYPERLIQUID_ENABLED=true. Collateral is USDC and is treated as USDT.
+func NewHyperliquidClient(address, privateKey string) *HyperliquidClient {
+	mainnet := !config.GetBool("HYPERLIQUID_TESTNET", false)
+	baseURL := "https://api.hyperliquid.xyz"
+	if !mainnet {
+		baseURL = "https://api.hyperliquid-testnet.xyz"
+	}
+
+	client := &HyperliquidClient{
+		address:   address,
+		mainnet:   mainnet,
+		baseURL:   baseURL,
+		rest:      common.NewRESTClient("hyperliquid"),
+		positions: make(map[string]*common.Position),
+	}
+
+	// Without credentials the client only serves public market data
+	if privateKey == "" {
+		return client
+	}
+
+	key, err := parsePrivateKey(privateKey)
+	if err != nil {
+		log.Printf("[HYPERLIQUID] NewHyperliquidClient - ERROR: Invalid HYPERLIQUID_API_SECRET, orders will fail: %v", err)
+		return client
+	}
+	client.privateKey = key
+
+	return client
+}
+
+func (h *HyperliquidClient) GetName() string {
+	return "hyperliquid"
+}
+
+// Prewarm opens a pooled connection to the API
+func (h *HyperliquidClient) Prewarm(ctx context.Context) {
+	common.PrewarmConnections(ctx, h.rest.HTTP, h.baseURL+"/info")
+}
+
+// GetUSDTBalance returns the withdrawable USDC margin; Hyperliquid has no spot leg here
+func (h *HyperliquidClient) GetUSDTBalance(ctx context.Context, market string) (float64, error) {
+	switch market {
+	case "futures":
+		return h.getFuturesBalance(ctx)
+	default:
+		return 0, fmt.Errorf("no USDT balance for market %s", market)
+	}
+}
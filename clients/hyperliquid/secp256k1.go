@@ -0,0 +1,159 @@
+package hyperliquid
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+)
+
+// Minimal secp256k1 signing for EIP-712 signatures; the standard library only ships NIST curves.
+// Nonces follow RFC 6979, so signatures are deterministic and match Ethereum tooling.
+
+var (
+	secpP, _  = new(big.Int).SetString("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEFFFFFC2F", 16)
+	secpN, _  = new(big.Int).SetString("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEBAAEDCE6AF48A03BBFD25E8CD0364141", 16)
+	secpGx, _ = new(big.Int).SetString("79BE667EF9DCBBAC55A06295CE870B07029BFCDB2DCE28D959F2815B16F81798", 16)
+	secpGy, _ = new(big.Int).SetString("483ADA7726A3C4655DA4FBFC0E1108A8FD17B448A68554199C47D08FFB10D4B8", 16)
+	secpHalfN = new(big.Int).Rsh(secpN, 1)
+)
+
+// curvePoint is an affine point; nil coordinates are the point at infinity
+type curvePoint struct {
+	x, y *big.Int
+}
+
+func (pt curvePoint) infinity() bool {
+	return pt.x == nil
+}
+
+func pointAdd(a, b curvePoint) curvePoint {
+	if a.infinity() {
+		return b
+	}
+	if b.infinity() {
+		return a
+	}
+
+	var lambda *big.Int
+	if a.x.Cmp(b.x) == 0 {
+		sum := new(big.Int).Add(a.y, b.y)
+		if sum.Mod(sum, secpP).Sign() == 0 {
+			return curvePoint{}
+		}
+		// Doubling: lambda = 3x^2 / 2y
+		num := new(big.Int).Mul(a.x, a.x)
+		num.Mul(num, big.NewInt(3))
+		den := new(big.Int).Lsh(a.y, 1)
+		den.Mod(den, secpP)
+		lambda = num.Mul(num, new(big.Int).ModInverse(den, secpP))
+	} else {
+		// Addition: lambda = (y2 - y1) / (x2 - x1)
+		num := new(big.Int).Sub(b.y, a.y)
+		den := new(big.Int).Sub(b.x, a.x)
+		den.Mod(den, secpP)
+		lambda = num.Mul(num, new(big.Int).ModInverse(den, secpP))
+	}
+	lambda.Mod(lambda, secpP)
+
+	x := new(big.Int).Mul(lambda, lambda)
+	x.Sub(x, a.x).Sub(x, b.x).Mod(x, secpP)
+	y := new(big.Int).Sub(a.x, x)
+	y.Mul(y, lambda).Sub(y, a.y).Mod(y, secpP)
+	return curvePoint{x: x, y: y}
+}
+
+func scalarBaseMult(k *big.Int) curvePoint {
+	result := curvePoint{}
+	addend := curvePoint{x: secpGx, y: secpGy}
+	for i := 0; i < k.BitLen(); i++ {
+		if k.Bit(i) == 1 {
+			result = pointAdd(result, addend)
+		}
+		addend = pointAdd(addend, addend)
+	}
+	return result
+}
+
+// int2octets encodes v as a 32-byte big-endian integer
+func int2octets(v *big.Int) []byte {
+	out := make([]byte, 32)
+	v.FillBytes(out)
+	return out
+}
+
+// rfc6979Nonce derives the deterministic signing nonce for a private key and 32-byte digest
+func rfc6979Nonce(priv *big.Int, digest []byte, attempt int) *big.Int {
+	h1 := new(big.Int).SetBytes(digest)
+	h1.Mod(h1, secpN)
+
+	v := make([]byte, 32)
+	for i := range v {
+		v[i] = 0x01
+	}
+	k := make([]byte, 32)
+
+	mac := func(key []byte, parts ...[]byte) []byte {
+		m := hmac.New(sha256.New, key)
+		for _, p := range parts {
+			m.Write(p)
+		}
+		return m.Sum(nil)
+	}
+
+	k = mac(k, v, []byte{0x00}, int2octets(priv), int2octets(h1))
+	v = mac(k, v)
+	k = mac(k, v, []byte{0x01}, int2octets(priv), int2octets(h1))
+	v = mac(k, v)
+
+	for {
+		v = mac(k, v)
+		candidate := new(big.Int).SetBytes(v)
+		if candidate.Sign() > 0 && candidate.Cmp(secpN) < 0 {
+			if attempt == 0 {
+				return candidate
+			}
+			attempt--
+		}
+		k = mac(k, v, []byte{0x00})
+		v = mac(k, v)
+	}
+}
+
+// signDigest signs a 32-byte digest and returns r, s (low-s form) and the recovery id
+func signDigest(priv *big.Int, digest []byte) (*big.Int, *big.Int, byte, error) {
+	if priv.Sign() <= 0 || priv.Cmp(secpN) >= 0 {
+		return nil, nil, 0, fmt.Errorf("invalid private key")
+	}
+
+	e := new(big.Int).SetBytes(digest)
+	for attempt := 0; ; attempt++ {
+		k := rfc6979Nonce(priv, digest, attempt)
+		point := scalarBaseMult(k)
+
+		r := new(big.Int).Mod(point.x, secpN)
+		if r.Sign() == 0 {
+			continue
+		}
+
+		s := new(big.Int).Mul(r, priv)
+		s.Add(s, e)
+		s.Mul(s, new(big.Int).ModInverse(k, secpN))
+		s.Mod(s, secpN)
+		if s.Sign() == 0 {
+			continue
+		}
+
+		recovery := byte(point.y.Bit(0))
+		if point.x.Cmp(secpN) >= 0 {
+			recovery |= 2
+		}
+		// Ethereum only accepts the low-s form; negating s flips the recovery parity
+		if s.Cmp(secpHalfN) > 0 {
+			s.Sub(secpN, s)
+			recovery ^= 1
+		}
+
+		return r, s, recovery, nil
+	}
+}
@@ -0,0 +1,23 @@
+package hyperliquid
+
+import (
+	"context"
+	"fmt"
+
+	"arbitrage.trade/clients/common"
+)
+
+// Hyperliquid is only used for the perp short; the spot leg stays on a CEX
+
+func (h *HyperliquidClient) PutSpotLong(ctx context.Context, pairName string, amountUSDT float64) (*common.TradeResult, error) {
+	return nil, fmt.Errorf("hyperliquid only trades the perp leg")
+}
+
+func (h *HyperliquidClient) CloseSpotLong(ctx context.Context, pairName string, amountUSDT float64) (*common.TradeResult, float64, error) {
+	return nil, 0.0, fmt.Errorf("hyperliquid only trades the perp leg")
+}
+
+// GetSpotHolding always reports no spot balance
+func (h *HyperliquidClient) GetSpotHolding(ctx context.Context, pairName string) (float64, error) {
+	return 0, nil
+}
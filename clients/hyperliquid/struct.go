@@ -0,0 +1,102 @@
+package hyperliquid
+
+import (
+	"encoding/json"
+	"math/big"
+	"sync"
+
+	"arbitrage.trade/clients/common"
+)
+
+type HyperliquidClient struct {
+	address    string   // Account the positions belong to
+	privateKey *big.Int // Key of the account or of an API wallet approved for it
+	mainnet    bool
+	baseURL    string
+	rest       *common.RESTClient
+
+	lastNonce int64 // Nonces must be unique per signer; millisecond timestamps are bumped on collision
+
+	assets   map[string]AssetInfo // Perp universe by coin name, loaded from meta
+	assetsMu sync.Mutex
+
+	positions map[string]*common.Position
+	mu        sync.RWMutex
+}
+
+// AssetInfo is a perp's index in the universe (used as the order's asset id) and size precision
+type AssetInfo struct {
+	Name       string
+	Index      int
+	SzDecimals int
+}
+
+type Meta struct {
+	Universe []struct {
+		Name        string `json:"name"`
+		SzDecimals  int    `json:"szDecimals"`
+		MaxLeverage int    `json:"maxLeverage"`
+		IsDelisted  bool   `json:"isDelisted"`
+	} `json:"universe"`
+}
+
+type ClearinghouseState struct {
+	Withdrawable   string `json:"withdrawable"`
+	AssetPositions []struct {
+		Position struct {
+			Coin          string `json:"coin"`
+			Szi           string `json:"szi"` // Signed size, negative for shorts
+			EntryPx       string `json:"entryPx"`
+			UnrealizedPnl string `json:"unrealizedPnl"`
+		} `json:"position"`
+	} `json:"assetPositions"`
+}
+
+// OrderWire is one order of an order action; field order is part of the signed msgpack encoding
+type OrderWire struct {
+	Asset      int       `json:"a" msgpack:"a"`
+	IsBuy      bool      `json:"b" msgpack:"b"`
+	Price      string    `json:"p" msgpack:"p"`
+	Size       string    `json:"s" msgpack:"s"`
+	ReduceOnly bool      `json:"r" msgpack:"r"`
+	OrderType  OrderType `json:"t" msgpack:"t"`
+}
+
+type OrderType struct {
+	Limit LimitOrder `json:"limit" msgpack:"limit"`
+}
+
+type LimitOrder struct {
+	Tif string `json:"tif" msgpack:"tif"` // Alo, Ioc or Gtc
+}
+
+type OrderAction struct {
+	Type     string      `json:"type" msgpack:"type"`
+	Orders   []OrderWire `json:"orders" msgpack:"orders"`
+	Grouping string      `json:"grouping" msgpack:"grouping"`
+}
+
+// ExchangeResponse is {"status":"ok","response":{...}} or {"status":"err","response":"message"}
+type ExchangeResponse struct {
+	Status   string          `json:"status"`
+	Response json.RawMessage `json:"response"`
+}
+
+type OrderResponse struct {
+	Type string `json:"type"`
+	Data struct {
+		Statuses []OrderStatus `json:"statuses"`
+	} `json:"data"`
+}
+
+type OrderStatus struct {
+	Filled *struct {
+		TotalSz string `json:"totalSz"`
+		AvgPx   string `json:"avgPx"`
+		Oid     int64  `json:"oid"`
+	} `json:"filled"`
+	Resting *struct {
+		Oid int64 `json:"oid"`
+	} `json:"resting"`
+	Error string `json:"error"`
+}
@@ -0,0 +1,172 @@
+package hyperliquid
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"arbitrage.trade/clients/common"
+)
+
+// Coin returns the perp coin for a pair ("btc-usdt" -> "BTC") and how many base units one
+// coin unit stands for; low-priced assets such as kPEPE are listed per 1000 units and are
+// configured through FUTURES_SYMBOLS_HYPERLIQUID
+func Coin(pairName string) (string, float64) {
+	if override, ok := common.FuturesSymbolOverride("hyperliquid", pairName); ok {
+		return override.Symbol, override.Multiplier
+	}
+	return strings.Split(strings.ToUpper(pairName), "-")[0], 1
+}
+
+// nonce returns a unique millisecond nonce for the signer
+func (h *HyperliquidClient) nonce() int64 {
+	for {
+		last := atomic.LoadInt64(&h.lastNonce)
+		next := time.Now().UnixMilli()
+		if next <= last {
+			next = last + 1
+		}
+		if atomic.CompareAndSwapInt64(&h.lastNonce, last, next) {
+			return next
+		}
+	}
+}
+
+func (h *HyperliquidClient) post(ctx context.Context, endpoint string, payload interface{}) ([]byte, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	resp, err := h.rest.Do(ctx, func(ctx context.Context) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", h.baseURL+endpoint, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Status != http.StatusOK {
+		return nil, resp.Err()
+	}
+	return resp.Body, nil
+}
+
+// infoRequest queries the public /info endpoint, e.g. {"type":"meta"}
+func (h *HyperliquidClient) infoRequest(ctx context.Context, payload interface{}, result interface{}) error {
+	body, err := h.post(ctx, "/info", payload)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(body, result); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return nil
+}
+
+// exchangeRequest signs and sends an L1 action to /exchange and returns its response payload
+func (h *HyperliquidClient) exchangeRequest(ctx context.Context, action interface{}) (json.RawMessage, error) {
+	if h.privateKey == nil {
+		return nil, fmt.Errorf("no valid signing key configured")
+	}
+
+	nonce := h.nonce()
+	signature, err := signL1Action(h.privateKey, action, nonce, h.mainnet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign action: %w", err)
+	}
+
+	body, err := h.post(ctx, "/exchange", map[string]interface{}{
+		"action":       action,
+		"nonce":        nonce,
+		"signature":    signature,
+		"vaultAddress": nil,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var response ExchangeResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	if response.Status != "ok" {
+		return nil, fmt.Errorf("hyperliquid api error: %s", string(response.Response))
+	}
+
+	return response.Response, nil
+}
+
+// assetInfo resolves a coin to its universe index, loading the perp meta on first use
+// Coin names are matched case-insensitively since overrides are upper-cased (kPEPE vs KPEPE)
+func (h *HyperliquidClient) assetInfo(ctx context.Context, coin string) (AssetInfo, error) {
+	h.assetsMu.Lock()
+	defer h.assetsMu.Unlock()
+
+	if h.assets == nil {
+		var meta Meta
+		if err := h.infoRequest(ctx, map[string]string{"type": "meta"}, &meta); err != nil {
+			return AssetInfo{}, fmt.Errorf("failed to load meta: %w", err)
+		}
+
+		assets := make(map[string]AssetInfo, len(meta.Universe))
+		for i, asset := range meta.Universe {
+			assets[strings.ToUpper(asset.Name)] = AssetInfo{Name: asset.Name, Index: i, SzDecimals: asset.SzDecimals}
+		}
+		h.assets = assets
+	}
+
+	info, ok := h.assets[strings.ToUpper(coin)]
+	if !ok {
+		return AssetInfo{}, fmt.Errorf("unknown coin %s", coin)
+	}
+	return info, nil
+}
+
+func (h *HyperliquidClient) getMid(ctx context.Context, coin string) (float64, error) {
+	var mids map[string]string
+	if err := h.infoRequest(ctx, map[string]string{"type": "allMids"}, &mids); err != nil {
+		return 0, err
+	}
+
+	mid, ok := mids[coin]
+	if !ok {
+		return 0, fmt.Errorf("no mid price for %s", coin)
+	}
+	return strconv.ParseFloat(mid, 64)
+}
+
+// floatToWire formats a number the way the API signs it: at most 8 decimals, no trailing zeros
+func floatToWire(v float64) string {
+	s := strconv.FormatFloat(v, 'f', 8, 64)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	if s == "-0" {
+		return "0"
+	}
+	return s
+}
+
+// formatPrice rounds a perp price to 5 significant figures and at most 6 - szDecimals decimals
+func formatPrice(price float64, szDecimals int) string {
+	significant, _ := strconv.ParseFloat(strconv.FormatFloat(price, 'g', 5, 64), 64)
+	scale := math.Pow(10, float64(6-szDecimals))
+	return floatToWire(math.Round(significant*scale) / scale)
+}
+
+// formatSize rounds a size down to the coin's size decimals
+func formatSize(size float64, szDecimals int) string {
+	scale := math.Pow(10, float64(szDecimals))
+	return floatToWire(math.Floor(size*scale+1e-9) / scale)
+}
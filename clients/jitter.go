@@ -0,0 +1,30 @@
+package clients
+
+import (
+	"math/rand"
+
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/config"
+)
+
+// sizeJitterPct returns the venue's order-size jitter band in %: ORDER_SIZE_JITTER_PCT_<EXCHANGE>,
+// falling back to ORDER_SIZE_JITTER_PCT (default 0, disabled)
+func sizeJitterPct(exchange common.ExchangeType) float64 {
+	fallback := config.GetFloat("ORDER_SIZE_JITTER_PCT", 0)
+	return config.GetFloat("ORDER_SIZE_JITTER_PCT_"+config.Key(string(exchange)), fallback)
+}
+
+// SizeJitter returns a random factor in (1 - band, 1] to scale a trade's legs by, so its orders don't
+// repeat the same size; band is the widest of the venues' jitter bands
+// All legs of a trade take the same factor to stay hedged, and it only shrinks sizes so the trade
+// stays within the book volume and capital it was sized against
+func SizeJitter(exchanges ...common.ExchangeType) float64 {
+	band := 0.0
+	for _, exchange := range exchanges {
+		band = max(band, sizeJitterPct(exchange))
+	}
+	if band <= 0 {
+		return 1
+	}
+	return 1 - rand.Float64()*min(band, 50)/100
+}
@@ -0,0 +1,128 @@
+package kraken
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"net/url"
+	"strings"
+	"testing"
+
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/clients/fixturetest"
+)
+
+// Secrets are base64, as Kraken issues them
+const (
+	fixtureKey           = "fixture-key"
+	fixtureSecret        = "Zml4dHVyZS1zZWNyZXQ="
+	fixtureFuturesKey    = "fixture-futures-key"
+	fixtureFuturesSecret = "Zml4dHVyZS1mdXR1cmVzLXNlY3JldA=="
+	fixturePair          = "xrp-usdt"
+)
+
+// TestFixtureReplay replays an open/close cycle of both legs from testdata/kraken.json
+func TestFixtureReplay(t *testing.T) {
+	rec := fixturetest.Replay(t, "kraken", "testdata")
+	client := NewKrakenClient(fixtureKey, fixtureSecret, fixtureFuturesKey, fixtureFuturesSecret)
+	ctx := context.Background()
+
+	if holding, err := client.GetSpotHolding(ctx, fixturePair); err != nil || holding != 0 {
+		t.Errorf("GetSpotHolding = %v, %v, want 0", holding, err)
+	}
+	if balance, err := client.GetUSDTBalance(ctx, "spot"); err != nil || common.NotEqual(balance, 1000.5) {
+		t.Errorf("GetUSDTBalance spot = %v, %v, want 1000.5", balance, err)
+	}
+
+	// The USDT amount is converted to a base volume at the last price, then the fill is polled
+	spot, err := client.PutSpotLong(ctx, fixturePair, 20)
+	fixturetest.CheckResult(t, "PutSpotLong", spot, err, common.TradeResult{
+		OrderID: "OQCLML-BW3P3-BUCMWZ", ExecutedPrice: 0.55, ExecutedQty: 36.3, Fee: 0.04991, Success: true,
+	})
+
+	perp, err := client.PutFuturesShort(ctx, fixturePair, 20)
+	fixturetest.CheckResult(t, "PutFuturesShort", perp, err, common.TradeResult{
+		OrderID: "c8f5d2a4-3e0b-4a1c-9d1e-6f2b7a9c0d11", ExecutedPrice: 0.5498, ExecutedQty: 36.3, Success: true,
+	})
+
+	if position, err := client.GetFuturesPosition(ctx, fixturePair); err != nil || common.NotEqual(position, -36.3) {
+		t.Errorf("GetFuturesPosition = %v, %v, want -36.3", position, err)
+	}
+
+	perpClose, _, err := client.CloseFuturesShort(ctx, fixturePair)
+	fixturetest.CheckResult(t, "CloseFuturesShort", perpClose, err, common.TradeResult{
+		OrderID: "0a6b1d7e-9c2f-4e3a-8b5d-1f4c6e8a2b33", ExecutedPrice: 0.5502, ExecutedQty: 36.3, Success: true,
+	})
+	if position, err := client.GetFuturesPosition(ctx, fixturePair); err != nil || position != 0 {
+		t.Errorf("GetFuturesPosition after close = %v, %v, want 0", position, err)
+	}
+
+	spotClose, _, err := client.CloseSpotLong(ctx, fixturePair, 20)
+	fixturetest.CheckResult(t, "CloseSpotLong", spotClose, err, common.TradeResult{
+		OrderID: "O5TYA6-EC2HN-KJ65ZG", ExecutedPrice: 0.5505, ExecutedQty: 36.3, Fee: 0.04996, Success: true,
+	})
+
+	orders := rec.FindAll("POST", "/derivatives/api/v3/sendorder")
+	if len(orders) != 2 {
+		t.Fatalf("%d futures orders sent, want 2", len(orders))
+	}
+	if open := fixturetest.Form(orders[0]); open.Get("side") != "sell" || open.Get("size") != "36.3" || open.Has("reduceOnly") {
+		t.Errorf("futures short order = %v, want sell 36.3", open)
+	}
+	if closing := fixturetest.Form(orders[1]); closing.Get("side") != "buy" || closing.Get("reduceOnly") != "true" {
+		t.Errorf("futures close order = %v, want a reduce-only buy", closing)
+	}
+
+	checkSignatures(t, rec)
+	fixturetest.CheckReplayed(t, "kraken")
+}
+
+// checkSignatures verifies the spot and futures signing schemes on every private request
+func checkSignatures(t *testing.T, rec *fixturetest.Recorder) {
+	t.Helper()
+	spotSecret, _ := base64.StdEncoding.DecodeString(fixtureSecret)
+	futuresSecret, _ := base64.StdEncoding.DecodeString(fixtureFuturesSecret)
+
+	signed := 0
+	for _, req := range rec.Requests() {
+		switch {
+		case req.Header.Get("API-Sign") != "":
+			// HMAC-SHA512(path + SHA256(nonce + postdata))
+			signed++
+			form, _ := url.ParseQuery(req.Body)
+			digest := sha256.Sum256([]byte(form.Get("nonce") + req.Body))
+			mac := hmac.New(sha512.New, spotSecret)
+			mac.Write([]byte(req.URL.Path))
+			mac.Write(digest[:])
+			if want := base64.StdEncoding.EncodeToString(mac.Sum(nil)); req.Header.Get("API-Sign") != want {
+				t.Errorf("%s signature = %s, want %s", req.URL.Path, req.Header.Get("API-Sign"), want)
+			}
+			if req.Header.Get("API-Key") != fixtureKey {
+				t.Errorf("%s sent without the spot API key", req.URL.Path)
+			}
+
+		case req.Header.Get("Authent") != "":
+			// HMAC-SHA512(SHA256(postdata + nonce + path without /derivatives))
+			signed++
+			postData := req.Body
+			if req.Method == "GET" {
+				postData = req.URL.RawQuery
+			}
+			endpoint := strings.TrimPrefix(req.URL.Path, "/derivatives")
+			digest := sha256.Sum256([]byte(postData + req.Header.Get("Nonce") + endpoint))
+			mac := hmac.New(sha512.New, futuresSecret)
+			mac.Write(digest[:])
+			if want := base64.StdEncoding.EncodeToString(mac.Sum(nil)); req.Header.Get("Authent") != want {
+				t.Errorf("%s %s signature = %s, want %s", req.Method, req.URL.Path, req.Header.Get("Authent"), want)
+			}
+			if req.Header.Get("APIKey") != fixtureFuturesKey {
+				t.Errorf("%s %s sent without the futures API key", req.Method, req.URL.Path)
+			}
+		}
+	}
+	if signed == 0 {
+		t.Error("no signed request was sent")
+	}
+}
@@ -0,0 +1,199 @@
+package kraken
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"arbitrage.trade/clients/common"
+)
+
+func (k *KrakenClient) getFuturesBalance(ctx context.Context) (float64, error) {
+	var accounts FuturesAccounts
+	if err := k.futuresRequest(ctx, "GET", "/api/v3/accounts", nil, true, &accounts); err != nil {
+		return 0, fmt.Errorf("failed to get futures balance: %w", err)
+	}
+
+	// Perpetuals margin from the multi-collateral flex account
+	return accounts.Accounts.Flex.AvailableMargin, nil
+}
+
+func (k *KrakenClient) getFuturesPosition(ctx context.Context, symbol string) (*FuturesPosition, error) {
+	var positions FuturesOpenPositions
+	if err := k.futuresRequest(ctx, "GET", "/api/v3/openpositions", nil, true, &positions); err != nil {
+		return nil, err
+	}
+
+	for _, pos := range positions.OpenPositions {
+		if strings.EqualFold(pos.Symbol, symbol) && common.NotEqual(pos.Size, 0) {
+			return &pos, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (k *KrakenClient) getFuturesPrice(ctx context.Context, symbol string) (float64, error) {
+	var ticker FuturesTicker
+	if err := k.futuresRequest(ctx, "GET", "/api/v3/tickers/"+symbol, nil, false, &ticker); err != nil {
+		return 0, err
+	}
+
+	if common.IsPositive(ticker.Ticker.Last) {
+		return ticker.Ticker.Last, nil
+	}
+	if common.IsPositive(ticker.Ticker.MarkPrice) {
+		return ticker.Ticker.MarkPrice, nil
+	}
+
+	return 0, fmt.Errorf("no ticker data for %s", symbol)
+}
+
+// placeFuturesMarket sends a market order and returns the response with its executions
+func (k *KrakenClient) placeFuturesMarket(ctx context.Context, symbol, side string, size float64, reduceOnly bool, pairName string) (*FuturesSendOrderResponse, error) {
+	params := url.Values{
+		"orderType": {"mkt"},
+		"symbol":    {symbol},
+		"side":      {side},
+		"size":      {common.FormatQuantity(size, pairName)},
+	}
+	if reduceOnly {
+		params.Set("reduceOnly", "true")
+	}
+
+	var response FuturesSendOrderResponse
+	if err := k.futuresRequest(ctx, "POST", "/api/v3/sendorder", params, true, &response); err != nil {
+		return nil, err
+	}
+	if response.SendStatus.Status != "placed" {
+		return nil, fmt.Errorf("order rejected: %s", response.SendStatus.Status)
+	}
+
+	return &response, nil
+}
+
+// futuresFill returns the executed base quantity and average price from an order's execution events
+// sendorder does not report fees; they are charged to the flex account and land in the balance diff
+func (k *KrakenClient) futuresFill(response *FuturesSendOrderResponse) (float64, float64) {
+	quantity, notional := 0.0, 0.0
+	for _, event := range response.SendStatus.OrderEvents {
+		if event.Type != "EXECUTION" {
+			continue
+		}
+		quantity += event.Amount
+		notional += event.Amount * event.Price
+	}
+
+	if common.IsZero(quantity) {
+		return 0, 0
+	}
+	return quantity, notional / quantity
+}
+
+// GetFuturesPosition returns the signed position size (in base units) for the pair
+func (k *KrakenClient) GetFuturesPosition(ctx context.Context, pairName string) (float64, error) {
+	position, err := k.getFuturesPosition(ctx, FuturesSymbol(pairName))
+	if err != nil {
+		return 0, err
+	}
+	if position == nil {
+		return 0, nil
+	}
+	if position.Side == "short" {
+		return -position.Size, nil
+	}
+	return position.Size, nil
+}
+
+func (k *KrakenClient) PutFuturesShort(ctx context.Context, pairName string, amountUSDT float64) (*common.TradeResult, error) {
+	symbol := FuturesSymbol(pairName)
+
+	balance, err := k.getFuturesBalance(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get futures balance: %w", err)
+	}
+
+	common.SetBalance(k.GetName(), "futures", "USDT", balance)
+
+	price, err := k.getFuturesPrice(ctx, symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price: %w", err)
+	}
+
+	quantity := common.QuantityForNotional(amountUSDT, price, pairName)
+	if common.IsNegativeOrZero(quantity) {
+		return nil, fmt.Errorf("order size %.2f USDT is below the quantity precision at %.8f", amountUSDT, price)
+	}
+
+	response, err := k.placeFuturesMarket(ctx, symbol, "sell", quantity, false, pairName)
+	if err != nil {
+		return nil, fmt.Errorf("market order failed: %w", err)
+	}
+
+	actualSize, fillPrice := k.futuresFill(response)
+
+	k.mu.Lock()
+	k.positions[pairName+"_futures"] = &common.Position{
+		PairName:     pairName,
+		Side:         "short",
+		Market:       "futures",
+		EntryPrice:   fillPrice,
+		Quantity:     actualSize,
+		AmountUSDT:   actualSize * fillPrice,
+		OrderID:      response.SendStatus.OrderID,
+		ExchangeName: k.GetName(),
+	}
+	k.mu.Unlock()
+
+	return &common.TradeResult{
+		OrderID:       response.SendStatus.OrderID,
+		ExecutedPrice: fillPrice,
+		ExecutedQty:   actualSize,
+		Success:       common.IsPositive(actualSize),
+	}, nil
+}
+
+func (k *KrakenClient) CloseFuturesShort(ctx context.Context, pairName string) (*common.TradeResult, float64, error) {
+	symbol := FuturesSymbol(pairName)
+
+	position, err := k.getFuturesPosition(ctx, symbol)
+	if err != nil {
+		return nil, 0.0, fmt.Errorf("failed to get position: %w", err)
+	}
+
+	if position == nil || position.Side != "short" {
+		k.mu.Lock()
+		delete(k.positions, pairName+"_futures")
+		k.mu.Unlock()
+		return nil, 0.0, fmt.Errorf("no open position on exchange")
+	}
+
+	response, err := k.placeFuturesMarket(ctx, symbol, "buy", position.Size, true, pairName)
+	if err != nil {
+		return nil, 0.0, fmt.Errorf("close order failed: %w", err)
+	}
+
+	k.mu.Lock()
+	delete(k.positions, pairName+"_futures")
+	k.mu.Unlock()
+
+	newBalance, err := k.getFuturesBalance(ctx)
+	if err != nil {
+		return nil, 0.0, fmt.Errorf("failed to get futures balance: %w", err)
+	}
+
+	prevBalance := common.GetBalance(k.GetName(), "futures", "USDT")
+	common.SetBalance(k.GetName(), "futures", "USDT", newBalance)
+
+	profit := common.Diff(newBalance, prevBalance)
+
+	actualSize, fillPrice := k.futuresFill(response)
+
+	return &common.TradeResult{
+		OrderID:       response.SendStatus.OrderID,
+		ExecutedPrice: fillPrice,
+		ExecutedQty:   actualSize,
+		Success:       common.IsPositive(actualSize),
+	}, profit, nil
+}
@@ -0,0 +1,47 @@
+package kraken
+
+import (
+	"context"
+	"fmt"
+
+	"arbitrage.trade/clients/common"
+)
+
+// Kraken runs spot and derivatives as separate platforms with their own API keys and
+// signing schemes: spot uses KRAKEN_API_KEY/KRAKEN_API_SECRET, perpetuals use
+// KRAKEN_FUTURES_API_KEY/KRAKEN_FUTURES_API_SECRET from futures.kraken.com. Without
+// futures credentials the client can only trade the spot leg.
+func NewKrakenClient(apiKey, apiSecret, futuresKey, futuresSecret string) *KrakenClient {
+	return &KrakenClient{
+		apiKey:         apiKey,
+		apiSecret:      apiSecret,
+		futuresKey:     futuresKey,
+		futuresSecret:  futuresSecret,
+		baseURL:        "https://api.kraken.com",
+		futuresBaseURL: "https://futures.kraken.com/derivatives",
+		rest:           common.NewRESTClient("kraken"),
+		positions:      make(map[string]*common.Position),
+	}
+}
+
+func (k *KrakenClient) GetName() string {
+	return "kraken"
+}
+
+// Prewarm opens pooled connections to both APIs
+func (k *KrakenClient) Prewarm(ctx context.Context) {
+	common.PrewarmConnections(ctx, k.rest.HTTP, k.baseURL+"/0/public/Time", k.futuresBaseURL+"/api/v3/instruments/status")
+}
+
+// GetUSDTBalance returns the available USDT on the given market ("spot" or "futures")
+// Futures collateral is reported by Kraken in USD and is treated as USDT
+func (k *KrakenClient) GetUSDTBalance(ctx context.Context, market string) (float64, error) {
+	switch market {
+	case "spot":
+		return k.getSpotBalance(ctx, "USDT")
+	case "futures":
+		return k.getFuturesBalance(ctx)
+	default:
+		return 0, fmt.Errorf("no USDT balance for market %s", market)
+	}
+}
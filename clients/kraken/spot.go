@@ -0,0 +1,216 @@
+package kraken
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"time"
+
+	"arbitrage.trade/clients/common"
+)
+
+// getSpotBalance returns the balance of an asset that is not held by open orders
+func (k *KrakenClient) getSpotBalance(ctx context.Context, currency string) (float64, error) {
+	var balances map[string]SpotBalance
+	if err := k.signedRequest(ctx, "/0/private/BalanceEx", nil, &balances); err != nil {
+		return 0, fmt.Errorf("failed to get spot balance: %w", err)
+	}
+
+	available := 0.0
+	for asset, bal := range balances {
+		if normalizeBalanceAsset(asset) != currency {
+			continue
+		}
+		total, _ := strconv.ParseFloat(bal.Balance, 64)
+		held, _ := strconv.ParseFloat(bal.HoldTrade, 64)
+		available += total - held
+	}
+
+	return available, nil
+}
+
+// Market orders are accepted before they fill, so fills are polled from
+// /0/private/QueryOrders until the order is closed or cancelled
+const (
+	spotFillPollInterval = 100 * time.Millisecond
+	spotFillPollTimeout  = 3 * time.Second
+)
+
+// awaitSpotFill polls a spot order until it finishes and returns its final state
+// Falls back to the last known state if the order is still open when the timeout expires
+func (k *KrakenClient) awaitSpotFill(ctx context.Context, txid string) *SpotOrder {
+	deadline := time.Now().Add(spotFillPollTimeout)
+	order := &SpotOrder{}
+
+	for order.Status == "" || order.Status == "pending" || order.Status == "open" {
+		if time.Now().After(deadline) {
+			log.Printf("[KRAKEN] awaitSpotFill - ERROR: Order %s still %q after %s, using partial fill", txid, order.Status, spotFillPollTimeout)
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return order
+		case <-time.After(spotFillPollInterval):
+		}
+
+		var orders map[string]SpotOrder
+		if err := k.signedRequest(ctx, "/0/private/QueryOrders", url.Values{"txid": {txid}}, &orders); err != nil {
+			log.Printf("[KRAKEN] awaitSpotFill - ERROR: Failed to poll order %s: %v", txid, err)
+			continue
+		}
+		if polled, ok := orders[txid]; ok {
+			order = &polled
+		}
+	}
+
+	return order
+}
+
+// spotFill returns the executed base quantity, average price, quote filled and fee in USDT of a finished order
+func (k *KrakenClient) spotFill(order *SpotOrder) (float64, float64, float64, float64) {
+	amount, _ := strconv.ParseFloat(order.VolExec, 64)
+	avgPrice, _ := strconv.ParseFloat(order.Price, 64)
+	cost, _ := strconv.ParseFloat(order.Cost, 64)
+	fee, _ := strconv.ParseFloat(order.Fee, 64)
+
+	if common.IsZero(avgPrice) && common.IsPositive(amount) {
+		avgPrice = cost / amount
+	}
+
+	return amount, avgPrice, cost, fee
+}
+
+// placeSpotMarket sends a market order for a base quantity and returns its transaction id
+func (k *KrakenClient) placeSpotMarket(ctx context.Context, symbol, side string, quantity float64, pairName string) (string, error) {
+	params := url.Values{
+		"pair":      {symbol},
+		"type":      {side},
+		"ordertype": {"market"},
+		"volume":    {common.FormatQuantity(quantity, pairName)},
+	}
+
+	var result SpotAddOrderResult
+	if err := k.signedRequest(ctx, "/0/private/AddOrder", params, &result); err != nil {
+		return "", err
+	}
+	if len(result.TxID) == 0 {
+		return "", fmt.Errorf("no transaction id in order response")
+	}
+
+	return result.TxID[0], nil
+}
+
+// GetSpotHolding returns the available base-asset balance for the pair
+func (k *KrakenClient) GetSpotHolding(ctx context.Context, pairName string) (float64, error) {
+	return k.getSpotBalance(ctx, k.baseAsset(pairName))
+}
+
+func (k *KrakenClient) PutSpotLong(ctx context.Context, pairName string, amountUSDT float64) (*common.TradeResult, error) {
+	symbol := SpotSymbol(pairName)
+
+	balance, err := k.getSpotBalance(ctx, "USDT")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get USDT balance: %w", err)
+	}
+
+	common.SetBalance(k.GetName(), "spot", "USDT", balance)
+
+	// Market orders take a base volume, so the USDT amount is converted at the last price
+	price, err := k.getPrice(ctx, symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price: %w", err)
+	}
+
+	quantity := common.QuantityForNotional(amountUSDT, price, pairName)
+	if common.IsNegativeOrZero(quantity) {
+		return nil, fmt.Errorf("order size %.2f USDT is below the quantity precision at %.8f", amountUSDT, price)
+	}
+
+	txid, err := k.placeSpotMarket(ctx, symbol, "buy", quantity, pairName)
+	if err != nil {
+		return nil, fmt.Errorf("market order failed: %w", err)
+	}
+
+	order := k.awaitSpotFill(ctx, txid)
+	amount, avgPrice, cost, fee := k.spotFill(order)
+
+	k.mu.Lock()
+	k.positions[pairName+"_spot"] = &common.Position{
+		PairName:     pairName,
+		Side:         "long",
+		Market:       "spot",
+		EntryPrice:   avgPrice,
+		Quantity:     amount,
+		AmountUSDT:   cost,
+		OrderID:      txid,
+		ExchangeName: k.GetName(),
+	}
+	k.mu.Unlock()
+
+	return &common.TradeResult{
+		OrderID:       txid,
+		ExecutedPrice: avgPrice,
+		ExecutedQty:   amount,
+		Fee:           fee,
+		Success:       order.Status == "closed",
+	}, nil
+}
+
+func (k *KrakenClient) CloseSpotLong(ctx context.Context, pairName string, amountUSDT float64) (*common.TradeResult, float64, error) {
+	symbol := SpotSymbol(pairName)
+
+	k.mu.RLock()
+	_, exists := k.positions[pairName+"_spot"]
+	k.mu.RUnlock()
+
+	if !exists {
+		return nil, 0.0, fmt.Errorf("no position found for %s", pairName)
+	}
+
+	baseAsset := k.baseAsset(pairName)
+	balance, err := k.getSpotBalance(ctx, baseAsset)
+	if err != nil {
+		return nil, 0.0, fmt.Errorf("failed to get %s balance: %w", baseAsset, err)
+	}
+
+	if common.IsNegativeOrZero(balance) {
+		return nil, 0.0, fmt.Errorf("no %s balance to sell", baseAsset)
+	}
+
+	sellQuantity := common.RoundQuantity(balance, pairName)
+
+	txid, err := k.placeSpotMarket(ctx, symbol, "sell", sellQuantity, pairName)
+	if err != nil {
+		return nil, 0.0, fmt.Errorf("market order failed: %w", err)
+	}
+
+	// Settle the fill before reading the USDT balance the profit is taken from
+	order := k.awaitSpotFill(ctx, txid)
+
+	k.mu.Lock()
+	delete(k.positions, pairName+"_spot")
+	k.mu.Unlock()
+
+	newBalance, err := k.getSpotBalance(ctx, "USDT")
+	if err != nil {
+		return nil, 0.0, fmt.Errorf("failed to get USDT balance: %w", err)
+	}
+
+	prevBalance := common.GetBalance(k.GetName(), "spot", "USDT")
+	common.SetBalance(k.GetName(), "spot", "USDT", newBalance)
+
+	profit := common.Diff(newBalance, prevBalance)
+
+	amount, avgPrice, _, fee := k.spotFill(order)
+
+	return &common.TradeResult{
+		OrderID:       txid,
+		ExecutedPrice: avgPrice,
+		ExecutedQty:   amount,
+		Fee:           fee,
+		Success:       order.Status == "closed",
+	}, profit, nil
+}
@@ -0,0 +1,91 @@
+package kraken
+
+import (
+	"encoding/json"
+	"sync"
+
+	"arbitrage.trade/clients/common"
+)
+
+type KrakenClient struct {
+	apiKey         string
+	apiSecret      string
+	futuresKey     string
+	futuresSecret  string
+	baseURL        string
+	futuresBaseURL string
+	rest           *common.RESTClient
+
+	lastNonce int64 // Spot nonces must strictly increase per API key
+
+	positions map[string]*common.Position
+	mu        sync.RWMutex
+}
+
+// SpotResponse is the envelope of every spot REST response
+type SpotResponse struct {
+	Error  []string        `json:"error"`
+	Result json.RawMessage `json:"result"`
+}
+
+type SpotBalance struct {
+	Balance   string `json:"balance"`
+	HoldTrade string `json:"hold_trade"`
+}
+
+type SpotAddOrderResult struct {
+	TxID []string `json:"txid"`
+}
+
+type SpotOrder struct {
+	Status  string `json:"status"` // pending, open, closed, canceled, expired
+	Volume  string `json:"vol"`
+	VolExec string `json:"vol_exec"` // Base quantity filled
+	Cost    string `json:"cost"`     // Quote filled
+	Fee     string `json:"fee"`      // Quote currency unless the order asked otherwise
+	Price   string `json:"price"`    // Average fill price
+}
+
+type SpotTicker struct {
+	Last []string `json:"c"` // [price, lot volume]
+}
+
+type FuturesAccounts struct {
+	Accounts struct {
+		Flex struct {
+			AvailableMargin float64 `json:"availableMargin"`
+			PortfolioValue  float64 `json:"portfolioValue"`
+		} `json:"flex"`
+	} `json:"accounts"`
+}
+
+type FuturesPosition struct {
+	Side   string  `json:"side"` // long or short
+	Symbol string  `json:"symbol"`
+	Price  float64 `json:"price"`
+	Size   float64 `json:"size"` // Base units, always positive
+}
+
+type FuturesOpenPositions struct {
+	OpenPositions []FuturesPosition `json:"openPositions"`
+}
+
+type FuturesSendOrderResponse struct {
+	SendStatus struct {
+		OrderID     string `json:"order_id"`
+		Status      string `json:"status"` // placed, insufficientAvailableFunds, ...
+		OrderEvents []struct {
+			Type   string  `json:"type"` // EXECUTION, PLACE, CANCEL, ...
+			Price  float64 `json:"price"`
+			Amount float64 `json:"amount"`
+		} `json:"orderEvents"`
+	} `json:"sendStatus"`
+}
+
+type FuturesTicker struct {
+	Ticker struct {
+		Symbol    string  `json:"symbol"`
+		Last      float64 `json:"last"`
+		MarkPrice float64 `json:"markPrice"`
+	} `json:"ticker"`
+}
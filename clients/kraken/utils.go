@@ -0,0 +1,266 @@
+package kraken
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"arbitrage.trade/clients/common"
+)
+
+// Kraken still uses its legacy asset codes in symbols: BTC is XBT and DOGE is XDG
+var assetCodes = map[string]string{
+	"BTC":  "XBT",
+	"DOGE": "XDG",
+}
+
+// balanceAliases maps the prefixed legacy codes returned by the balance endpoint
+// (e.g. XXBT, ZUSD) to the plain codes used in symbols
+var balanceAliases = map[string]string{
+	"XXBT": "XBT",
+	"XXDG": "XDG",
+	"XETH": "ETH",
+	"XXRP": "XRP",
+	"XLTC": "LTC",
+	"XXLM": "XLM",
+	"XETC": "ETC",
+	"XZEC": "ZEC",
+	"XXMR": "XMR",
+	"ZUSD": "USD",
+	"ZEUR": "EUR",
+	"ZGBP": "GBP",
+}
+
+// AssetCode returns Kraken's code for an asset, e.g. "BTC" -> "XBT"
+func AssetCode(asset string) string {
+	asset = strings.ToUpper(asset)
+	if code, ok := assetCodes[asset]; ok {
+		return code
+	}
+	return asset
+}
+
+// SpotSymbol converts "btc-usdt" to Kraken's REST pair name "XBTUSDT"
+func SpotSymbol(pairName string) string {
+	parts := strings.Split(strings.ToUpper(pairName), "-")
+	if len(parts) != 2 {
+		return strings.ToUpper(pairName)
+	}
+	return AssetCode(parts[0]) + AssetCode(parts[1])
+}
+
+// FuturesSymbol converts "btc-usdt" to the multi-collateral perpetual "PF_XBTUSD"
+// Kraken perpetuals are quoted in USD, which is treated as USDT
+func FuturesSymbol(pairName string) string {
+	base := strings.Split(strings.ToUpper(pairName), "-")[0]
+	return "PF_" + AssetCode(base) + "USD"
+}
+
+// normalizeBalanceAsset maps a balance key to its symbol code
+// Auto-earn balances (".F") are tradable and count towards the plain asset
+func normalizeBalanceAsset(asset string) string {
+	asset = strings.TrimSuffix(asset, ".F")
+	if code, ok := balanceAliases[asset]; ok {
+		return code
+	}
+	return asset
+}
+
+func (k *KrakenClient) baseAsset(pairName string) string {
+	return AssetCode(strings.Split(pairName, "-")[0])
+}
+
+// nonce returns a strictly increasing nonce, even for calls within the same microsecond
+func (k *KrakenClient) nonce() string {
+	for {
+		last := atomic.LoadInt64(&k.lastNonce)
+		next := time.Now().UnixMicro()
+		if next <= last {
+			next = last + 1
+		}
+		if atomic.CompareAndSwapInt64(&k.lastNonce, last, next) {
+			return strconv.FormatInt(next, 10)
+		}
+	}
+}
+
+// signedRequest calls a private spot endpoint and decodes its result
+func (k *KrakenClient) signedRequest(ctx context.Context, path string, params url.Values, result interface{}) error {
+	if params == nil {
+		params = url.Values{}
+	}
+	secret, err := base64.StdEncoding.DecodeString(k.apiSecret)
+	if err != nil {
+		return fmt.Errorf("invalid api secret: %w", err)
+	}
+
+	resp, err := k.rest.Do(ctx, func(ctx context.Context) (*http.Request, error) {
+		nonce := k.nonce()
+		params.Set("nonce", nonce)
+		postData := params.Encode()
+
+		// Kraken spot signature: HMAC-SHA512(path + SHA256(nonce + postdata)) keyed with the base64-decoded secret
+		digest := sha256.Sum256([]byte(nonce + postData))
+		h := hmac.New(sha512.New, secret)
+		h.Write([]byte(path))
+		h.Write(digest[:])
+		signature := base64.StdEncoding.EncodeToString(h.Sum(nil))
+
+		req, err := http.NewRequestWithContext(ctx, "POST", k.baseURL+path, strings.NewReader(postData))
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("API-Key", k.apiKey)
+		req.Header.Set("API-Sign", signature)
+		return req, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return decodeSpot(resp, result)
+}
+
+// publicRequest calls a public spot endpoint and decodes its result
+func (k *KrakenClient) publicRequest(ctx context.Context, path string, params url.Values, result interface{}) error {
+	endpoint := k.baseURL + path
+	if len(params) > 0 {
+		endpoint += "?" + params.Encode()
+	}
+
+	resp, err := k.rest.Get(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+
+	return decodeSpot(resp, result)
+}
+
+// decodeSpot unwraps the {"error":[],"result":{}} envelope of a spot response
+func decodeSpot(resp *common.RESTResponse, result interface{}) error {
+	if resp.Status != http.StatusOK {
+		return resp.Err()
+	}
+
+	var envelope SpotResponse
+	if err := json.Unmarshal(resp.Body, &envelope); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	if len(envelope.Error) > 0 {
+		return fmt.Errorf("kraken api error: %s", strings.Join(envelope.Error, ", "))
+	}
+
+	if result != nil {
+		if err := json.Unmarshal(envelope.Result, result); err != nil {
+			return fmt.Errorf("failed to unmarshal result: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// futuresRequest calls a Kraken Futures endpoint, signing it when private is set
+// endpoint is relative to /derivatives, e.g. "/api/v3/sendorder"
+func (k *KrakenClient) futuresRequest(ctx context.Context, method, endpoint string, params url.Values, private bool, result interface{}) error {
+	postData := params.Encode()
+
+	target := k.futuresBaseURL + endpoint
+	if method == "GET" && postData != "" {
+		target += "?" + postData
+	}
+
+	var secret []byte
+	if private {
+		var err error
+		if secret, err = base64.StdEncoding.DecodeString(k.futuresSecret); err != nil {
+			return fmt.Errorf("invalid futures api secret: %w", err)
+		}
+	}
+
+	resp, err := k.rest.Do(ctx, func(ctx context.Context) (*http.Request, error) {
+		var body io.Reader
+		if method != "GET" {
+			body = strings.NewReader(postData)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, target, body)
+		if err != nil {
+			return nil, err
+		}
+
+		if method != "GET" {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+
+		if private {
+			// Kraken Futures signature: HMAC-SHA512(SHA256(postData + nonce + endpoint)) keyed with the
+			// base64-decoded secret, where endpoint excludes the /derivatives prefix
+			nonce := k.nonce()
+			digest := sha256.Sum256([]byte(postData + nonce + endpoint))
+			h := hmac.New(sha512.New, secret)
+			h.Write(digest[:])
+
+			req.Header.Set("APIKey", k.futuresKey)
+			req.Header.Set("Nonce", nonce)
+			req.Header.Set("Authent", base64.StdEncoding.EncodeToString(h.Sum(nil)))
+		}
+		return req, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if resp.Status != http.StatusOK {
+		return fmt.Errorf("kraken futures api error: status %d, body: %s", resp.Status, string(resp.Body))
+	}
+
+	// Errors come back with HTTP 200 and {"result":"error","error":"..."}
+	var status struct {
+		Result string `json:"result"`
+		Error  string `json:"error"`
+	}
+	if err := json.Unmarshal(resp.Body, &status); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	if status.Result == "error" {
+		return fmt.Errorf("kraken futures api error: %s", status.Error)
+	}
+
+	if result != nil {
+		if err := json.Unmarshal(resp.Body, result); err != nil {
+			return fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (k *KrakenClient) getPrice(ctx context.Context, symbol string) (float64, error) {
+	var tickers map[string]SpotTicker
+	if err := k.publicRequest(ctx, "/0/public/Ticker", url.Values{"pair": {symbol}}, &tickers); err != nil {
+		return 0, err
+	}
+
+	// Results are keyed by Kraken's internal pair name, which may differ from the requested one
+	for _, ticker := range tickers {
+		if len(ticker.Last) > 0 {
+			price, _ := strconv.ParseFloat(ticker.Last[0], 64)
+			return price, nil
+		}
+	}
+
+	return 0, fmt.Errorf("no ticker data for %s", symbol)
+}
@@ -0,0 +1,120 @@
+package okx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+)
+
+// OKX account levels (acctLv) reported by /api/v5/account/config
+const (
+	acctLvSpot          = "1" // Spot mode, no derivatives
+	acctLvSpotFutures   = "2" // Spot and futures mode (single-currency margin)
+	acctLvMultiCurrency = "3" // Multi-currency margin
+	acctLvPortfolio     = "4" // Portfolio margin
+)
+
+var ErrIncompatibleAccountMode = errors.New("incompatible okx account mode")
+
+func accountModeName(acctLv string) string {
+	switch acctLv {
+	case acctLvSpot:
+		return "spot"
+	case acctLvSpotFutures:
+		return "spot and futures"
+	case acctLvMultiCurrency:
+		return "multi-currency margin"
+	case acctLvPortfolio:
+		return "portfolio margin"
+	default:
+		return "unknown (" + acctLv + ")"
+	}
+}
+
+// getAccountConfig returns the cached account config, fetching it on first use
+func (o *OkxClient) getAccountConfig(ctx context.Context) (*AccountConfig, error) {
+	o.accountMu.Lock()
+	defer o.accountMu.Unlock()
+
+	if o.account != nil {
+		return o.account, nil
+	}
+
+	var result struct {
+		Code string          `json:"code"`
+		Msg  string          `json:"msg"`
+		Data []AccountConfig `json:"data"`
+	}
+
+	if err := o.signedRequest(ctx, "GET", "/api/v5/account/config", "", &result); err != nil {
+		return nil, fmt.Errorf("failed to get account config: %w", err)
+	}
+
+	if result.Code != "0" {
+		return nil, fmt.Errorf("okx error code: %s, msg: %s", result.Code, result.Msg)
+	}
+
+	if len(result.Data) == 0 {
+		return nil, fmt.Errorf("account config response empty")
+	}
+
+	o.account = &result.Data[0]
+	log.Printf("[OKX] Account mode: %s, position mode: %s, auto loan: %v",
+		accountModeName(o.account.AcctLv), o.account.PosMode, o.account.AutoLoan)
+
+	return o.account, nil
+}
+
+// spotTdMode returns the trade mode for spot orders. Spot legs always trade as
+// non-margin cash, but margin modes with auto-borrow enabled would let a cash-sized
+// sell silently open a loan, so they are rejected.
+func (o *OkxClient) spotTdMode(ctx context.Context) (string, error) {
+	account, err := o.getAccountConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	switch account.AcctLv {
+	case acctLvSpot, acctLvSpotFutures:
+		return "cash", nil
+	case acctLvMultiCurrency, acctLvPortfolio:
+		if account.AutoLoan {
+			return "", fmt.Errorf("%w: %s with auto borrow enabled", ErrIncompatibleAccountMode, accountModeName(account.AcctLv))
+		}
+		return "cash", nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrIncompatibleAccountMode, accountModeName(account.AcctLv))
+	}
+}
+
+// futuresTdMode returns the trade mode for swap orders, rejecting accounts that
+// cannot hold derivatives or that use hedge position mode
+func (o *OkxClient) futuresTdMode(ctx context.Context) (string, error) {
+	account, err := o.getAccountConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	switch account.AcctLv {
+	case acctLvSpotFutures, acctLvMultiCurrency, acctLvPortfolio:
+	default:
+		return "", fmt.Errorf("%w: %s does not support swaps", ErrIncompatibleAccountMode, accountModeName(account.AcctLv))
+	}
+
+	if account.PosMode != "net_mode" {
+		return "", fmt.Errorf("%w: position mode %s, net_mode required", ErrIncompatibleAccountMode, account.PosMode)
+	}
+
+	return "cross", nil
+}
+
+// isSharedCollateral reports whether margin is pooled across currencies, in which
+// case futures buying power is the account-level adjusted equity
+func (o *OkxClient) isSharedCollateral(ctx context.Context) bool {
+	account, err := o.getAccountConfig(ctx)
+	if err != nil {
+		return false
+	}
+	return account.AcctLv == acctLvMultiCurrency || account.AcctLv == acctLvPortfolio
+}
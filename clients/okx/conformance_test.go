@@ -0,0 +1,21 @@
+package okx
+
+import (
+	"testing"
+
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/clients/conformance"
+	"arbitrage.trade/clients/conformance/conformancetest"
+)
+
+// TestConformance runs the adapter conformance suite against testdata/conformance/okx.json
+func TestConformance(t *testing.T) {
+	conformancetest.Run(t, conformance.Suite{
+		Exchange:   "okx",
+		Pair:       fixturePair,
+		AmountUSDT: 120, // One XRP swap contract is 100 XRP
+		New: func() common.ExchangeTradeClient {
+			return NewOkxClient(fixtureKey, fixtureSecret, fixturePassphrase)
+		},
+	})
+}
@@ -0,0 +1,46 @@
+package okx
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"arbitrage.trade/clients/common"
+)
+
+// GetFeeRate returns the account's fee rate for the pair at its current fee level
+// OKX reports charged fees as negative rates and rebates as positive
+func (o *OkxClient) GetFeeRate(ctx context.Context, market, pairName string) (*common.FeeRate, error) {
+	endpoint := "/api/v5/account/trade-fee?instType=SPOT&instId=" + strings.ToUpper(pairName)
+	if market == "futures" {
+		endpoint = "/api/v5/account/trade-fee?instType=SWAP&instFamily=" + strings.ToUpper(pairName)
+	}
+
+	var result struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data []struct {
+			Level  string `json:"level"`
+			Taker  string `json:"taker"`
+			Maker  string `json:"maker"`
+			TakerU string `json:"takerU"` // USDT-margined contracts
+			MakerU string `json:"makerU"`
+		} `json:"data"`
+	}
+	if err := o.signedRequest(ctx, "GET", endpoint, "", &result); err != nil {
+		return nil, err
+	}
+	if result.Code != "0" || len(result.Data) == 0 {
+		return nil, fmt.Errorf("okx error code: %s, msg: %s", result.Code, result.Msg)
+	}
+
+	data := result.Data[0]
+	taker, maker := data.Taker, data.Maker
+	if market == "futures" {
+		taker, maker = data.TakerU, data.MakerU
+	}
+	takerRate, _ := strconv.ParseFloat(taker, 64)
+	makerRate, _ := strconv.ParseFloat(maker, 64)
+	return &common.FeeRate{Taker: -takerRate, Maker: -makerRate}, nil
+}
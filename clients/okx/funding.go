@@ -0,0 +1,100 @@
+package okx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"arbitrage.trade/clients/common"
+)
+
+// GetFundingRate returns the swap's current funding rate
+func (o *OkxClient) GetFundingRate(ctx context.Context, pairName string) (*common.FundingRate, error) {
+	url := fmt.Sprintf("%s/api/v5/public/funding-rate?instId=%s", o.baseURL, o.normalizeSymbolFutures(pairName))
+
+	resp, err := o.rest.Get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data []struct {
+			FundingRate string `json:"fundingRate"`
+			FundingTime string `json:"fundingTime"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(resp.Body, &result); err != nil {
+		return nil, err
+	}
+	if result.Code != "0" || len(result.Data) == 0 {
+		return nil, fmt.Errorf("okx error code: %s, msg: %s", result.Code, result.Msg)
+	}
+
+	rate, _ := strconv.ParseFloat(result.Data[0].FundingRate, 64)
+	fundingMs, _ := strconv.ParseInt(result.Data[0].FundingTime, 10, 64)
+	return &common.FundingRate{Rate: rate, FundingTime: time.UnixMilli(fundingMs)}, nil
+}
+
+// OKX returns swap funding settlements newest first, 100 per page, paged backwards after a fundingTime
+const fundingHistoryLimit = 100
+
+// GetFundingHistory returns the swap's funding settlements in [from, to) from /api/v5/public/funding-rate-history
+func (o *OkxClient) GetFundingHistory(ctx context.Context, pairName string, from, to time.Time) ([]common.FundingRate, error) {
+	var rates []common.FundingRate
+	pager := common.NewCursorPager(fundingHistoryLimit)
+	for pager.Next() {
+		after := pager.Cursor()
+		if after == "" {
+			after = strconv.FormatInt(to.UnixMilli(), 10)
+		}
+		url := fmt.Sprintf("%s/api/v5/public/funding-rate-history?instId=%s&after=%s&limit=%d",
+			o.baseURL, o.normalizeSymbolFutures(pairName), after, fundingHistoryLimit)
+
+		resp, err := o.rest.Get(ctx, url)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get funding history: %w", err)
+		}
+
+		var result struct {
+			Code string `json:"code"`
+			Msg  string `json:"msg"`
+			Data []struct {
+				RealizedRate string `json:"realizedRate"`
+				FundingTime  string `json:"fundingTime"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(resp.Body, &result); err != nil {
+			return nil, fmt.Errorf("failed to decode funding history: %w", err)
+		}
+		if result.Code != "0" {
+			return nil, fmt.Errorf("okx error code: %s, msg: %s", result.Code, result.Msg)
+		}
+
+		next := ""
+		for _, s := range result.Data {
+			fundingMs, _ := strconv.ParseInt(s.FundingTime, 10, 64)
+			fundingTime := time.UnixMilli(fundingMs)
+			if fundingTime.Before(from) {
+				next = "" // Past the range, nothing older is needed
+				break
+			}
+			next = s.FundingTime
+			rate, err := strconv.ParseFloat(s.RealizedRate, 64)
+			if err != nil {
+				continue
+			}
+			rates = append(rates, common.FundingRate{Rate: rate, FundingTime: fundingTime})
+		}
+		pager.Page(len(result.Data), next)
+	}
+
+	// Oldest first
+	for i, j := 0, len(rates)-1; i < j; i, j = i+1, j-1 {
+		rates[i], rates[j] = rates[j], rates[i]
+	}
+	return rates, nil
+}
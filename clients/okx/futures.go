@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"strconv"
 	"time"
 
@@ -16,6 +17,7 @@ func (o *OkxClient) getFuturesBalance(ctx context.Context) (float64, error) {
 		Msg  string `json:"msg"`
 		Data []struct {
 			TotalEq string `json:"totalEq"`
+			AdjEq   string `json:"adjEq"`
 			Details []struct {
 				Ccy           string `json:"ccy"`
 				AvailBal      string `json:"availBal"`
@@ -38,6 +40,13 @@ func (o *OkxClient) getFuturesBalance(ctx context.Context) (float64, error) {
 		return 0, fmt.Errorf("okx error code: %s, msg: %s", result.Code, result.Msg)
 	}
 
+	// Multi-currency and portfolio margin pool collateral across currencies,
+	// so the per-currency USDT fields understate buying power
+	if len(result.Data) > 0 && o.isSharedCollateral(ctx) && result.Data[0].AdjEq != "" {
+		adjEq, _ := strconv.ParseFloat(result.Data[0].AdjEq, 64)
+		return adjEq, nil
+	}
+
 	if len(result.Data) > 0 && len(result.Data[0].Details) > 0 {
 		for _, detail := range result.Data[0].Details {
 			if detail.Ccy == "USDT" {
@@ -84,14 +93,50 @@ func (o *OkxClient) getFuturesPosition(ctx context.Context, instId string) (*Pos
 	return nil, nil
 }
 
+// GetFuturesPosition returns the signed swap position size for the pair, converted from contracts to base units
+func (o *OkxClient) GetFuturesPosition(ctx context.Context, pairName string) (float64, error) {
+	instId := o.normalizeSymbolFutures(pairName)
+	position, err := o.getFuturesPosition(ctx, instId)
+	if err != nil {
+		return 0, err
+	}
+	if position == nil {
+		return 0, nil
+	}
+	pos, _ := strconv.ParseFloat(position.Pos, 64)
+	ctVal, err := o.swapContractValue(ctx, instId)
+	if err != nil {
+		return 0, err
+	}
+	return pos * ctVal, nil
+}
+
+// GetPerpPnL returns the pair's swap position with the unrealized profit (upl) OKX reports
+func (o *OkxClient) GetPerpPnL(ctx context.Context, pairName string) (*common.PositionPnL, error) {
+	position, err := o.getFuturesPosition(ctx, o.normalizeSymbolFutures(pairName))
+	if err != nil || position == nil {
+		return nil, err
+	}
+	size, _ := strconv.ParseFloat(position.Pos, 64)
+	entry, _ := strconv.ParseFloat(position.AvgPx, 64)
+	mark, _ := strconv.ParseFloat(position.MarkPx, 64)
+	upl, _ := strconv.ParseFloat(position.Upl, 64)
+	return &common.PositionPnL{Size: size, EntryPrice: entry, MarkPrice: mark, UnrealizedPnL: upl}, nil
+}
+
 func (o *OkxClient) PutFuturesShort(ctx context.Context, pairName string, amountUSDT float64) (*common.TradeResult, error) {
 	instId := o.normalizeSymbolFutures(pairName)
 
+	tdMode, err := o.futuresTdMode(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	// Set leverage to 10x for this instrument
 	leverageReq := map[string]interface{}{
 		"instId":  instId,
 		"lever":   "10",
-		"mgnMode": "cross",
+		"mgnMode": tdMode,
 	}
 	leverageBody, _ := json.Marshal(leverageReq)
 
@@ -110,19 +155,28 @@ func (o *OkxClient) PutFuturesShort(ctx context.Context, pairName string, amount
 
 	common.SetBalance(o.GetName(), "futures", "USDT", balance)
 
-	// OKX SWAP contracts use USDT as the contract size
-	// For most USDT perpetuals, 1 contract = 1 USDT
-	quantity := amountUSDT
-	if quantity < 1 {
-		quantity = 1
+	price, err := o.getPrice(ctx, instId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price: %w", err)
+	}
+	ctVal, err := o.swapContractValue(ctx, instId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Swap orders are sized in whole contracts of ctVal base units each
+	contracts := math.Floor(amountUSDT/price/ctVal + 1e-9)
+	if contracts < 1 {
+		return nil, fmt.Errorf("%.2f USDT is below one %s contract of %g", amountUSDT, instId, ctVal)
 	}
 
 	orderReq := map[string]interface{}{
 		"instId":  instId,
-		"tdMode":  "cross",
+		"tdMode":  tdMode,
 		"side":    "sell",
 		"ordType": "market",
-		"sz":      fmt.Sprintf("%.0f", quantity),
+		"clOrdId": common.NewClientOrderID(),
+		"sz":      fmt.Sprintf("%.0f", contracts),
 	}
 
 	body, _ := json.Marshal(orderReq)
@@ -175,7 +229,9 @@ func (o *OkxClient) PutFuturesShort(ctx context.Context, pairName string, amount
 
 	avgPx, _ := strconv.ParseFloat(orderData.AvgPx, 64)
 	fillSz, _ := strconv.ParseFloat(orderData.AccFillSz, 64)
+	fillSz *= ctVal // Contracts to base units; avgPx is already per base unit
 	fee, _ := strconv.ParseFloat(orderData.Fee, 64)
+	fee = -fee // OKX reports fees as negative amounts, rebates as positive ones
 
 	o.mu.Lock()
 	o.positions[pairName+"_futures"] = &common.Position{
@@ -224,13 +280,23 @@ func (o *OkxClient) CloseFuturesShort(ctx context.Context, pairName string) (*co
 		return nil, 0.0, fmt.Errorf("no position to close")
 	}
 
+	tdMode, err := o.futuresTdMode(ctx)
+	if err != nil {
+		return nil, 0.0, err
+	}
+	ctVal, err := o.swapContractValue(ctx, instId)
+	if err != nil {
+		return nil, 0.0, err
+	}
+
 	prevBalance := common.GetBalance(o.GetName(), "futures", "USDT")
 
 	orderReq := map[string]interface{}{
 		"instId":  instId,
-		"tdMode":  "cross",
+		"tdMode":  tdMode,
 		"side":    "buy",
 		"ordType": "market",
+		"clOrdId": common.NewClientOrderID(),
 		"sz":      fmt.Sprintf("%.0f", closeQuantity),
 	}
 
@@ -285,14 +351,12 @@ func (o *OkxClient) CloseFuturesShort(ctx context.Context, pairName string) (*co
 
 	avgPx, _ := strconv.ParseFloat(orderData.AvgPx, 64)
 	fillSz, _ := strconv.ParseFloat(orderData.AccFillSz, 64)
+	fillSz *= ctVal // Contracts to base units
 	fee, _ := strconv.ParseFloat(orderData.Fee, 64)
-
-	if common.IsNegative(fee) {
-		fee = -fee
-	}
+	fee = -fee // OKX reports fees as negative amounts, rebates as positive ones
 
 	newBalance, _ := o.getFuturesBalance(ctx)
-	profit := newBalance - prevBalance
+	profit := common.Diff(newBalance, prevBalance)
 
 	o.mu.Lock()
 	delete(o.positions, pairName+"_futures")
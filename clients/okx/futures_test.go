@@ -0,0 +1,42 @@
+package okx
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/clients/fixturetest"
+)
+
+// TestSwapContractSizing replays market swap orders from testdata/contracts/okx.json
+// A USDT amount is floored to whole 100 XRP contracts, an amount under one contract is refused
+// before anything is sent, and fills are reported in XRP
+func TestSwapContractSizing(t *testing.T) {
+	rec := fixturetest.Replay(t, "okx", "testdata/contracts")
+	client := NewOkxClient(fixtureKey, fixtureSecret, fixturePassphrase)
+	ctx := context.Background()
+
+	if _, err := client.PutFuturesShort(ctx, fixturePair, 50); err == nil || !strings.Contains(err.Error(), "below one XRP-USDT-SWAP contract") {
+		t.Errorf("PutFuturesShort under one contract = %v, want the contract size error", err)
+	}
+	if orders := rec.FindAll("POST", "/api/v5/trade/order"); len(orders) != 0 {
+		t.Fatalf("%d orders sent for an amount under one contract, want none", len(orders))
+	}
+
+	perp, err := client.PutFuturesShort(ctx, fixturePair, 250)
+	fixturetest.CheckResult(t, "PutFuturesShort", perp, err, common.TradeResult{
+		OrderID: "900020", ExecutedPrice: 0.5498, ExecutedQty: 400, Fee: 0.10996, Success: true,
+	})
+
+	perpClose, profit, err := client.CloseFuturesShort(ctx, fixturePair)
+	fixturetest.CheckResult(t, "CloseFuturesShort", perpClose, err, common.TradeResult{
+		OrderID: "900021", ExecutedPrice: 0.55, ExecutedQty: 400, Fee: 0.11, Success: true,
+	})
+	// 400 XRP × (0.5498 − 0.55) less both fees
+	if common.NotEqual(profit, -0.3) {
+		t.Errorf("CloseFuturesShort profit = %v, want -0.3", profit)
+	}
+
+	fixturetest.CheckReplayed(t, "okx")
+}
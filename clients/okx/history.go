@@ -0,0 +1,140 @@
+package okx
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"arbitrage.trade/clients/common"
+)
+
+// OKX returns fills newest first, 100 per page, paged backwards after the last page's billId (common.CursorPager)
+const historyPageLimit = 100
+
+type fillHistory struct {
+	InstID  string `json:"instId"`
+	TradeID string `json:"tradeId"`
+	OrdID   string `json:"ordId"`
+	ClOrdID string `json:"clOrdId"`
+	BillID  string `json:"billId"`
+	Side    string `json:"side"`
+	FillPx  string `json:"fillPx"`
+	FillSz  string `json:"fillSz"`
+	FillPnl string `json:"fillPnl"`
+	Fee     string `json:"fee"`
+	FeeCcy  string `json:"feeCcy"`
+	Ts      string `json:"ts"`
+}
+
+// GetFills returns the account's spot or swap fills of the pair in [from, to) from /api/v5/trade/fills-history
+// The endpoint covers the last 3 months; swap sizes are converted from contracts to base units
+func (o *OkxClient) GetFills(ctx context.Context, market, pairName string, from, to time.Time) ([]common.AccountFill, error) {
+	instType, instID := "SPOT", o.normalizeSymbol(pairName)
+	contractValue := 1.0
+	if market == "futures" {
+		instType, instID = "SWAP", o.normalizeSymbolFutures(pairName)
+		ctVal, err := o.swapContractValue(ctx, instID)
+		if err != nil {
+			return nil, err
+		}
+		contractValue = ctVal
+	}
+
+	var fills []common.AccountFill
+	pager := common.NewCursorPager(historyPageLimit)
+	for pager.Next() {
+		query := url.Values{}
+		query.Set("instType", instType)
+		query.Set("instId", instID)
+		query.Set("begin", strconv.FormatInt(from.UnixMilli(), 10))
+		query.Set("end", strconv.FormatInt(to.UnixMilli(), 10))
+		query.Set("limit", strconv.Itoa(historyPageLimit))
+		if after := pager.Cursor(); after != "" {
+			query.Set("after", after)
+		}
+
+		var result struct {
+			Code string        `json:"code"`
+			Msg  string        `json:"msg"`
+			Data []fillHistory `json:"data"`
+		}
+		if err := o.signedRequest(ctx, "GET", "/api/v5/trade/fills-history?"+query.Encode(), "", &result); err != nil {
+			return nil, fmt.Errorf("failed to get %s fill history: %w", market, err)
+		}
+		if result.Code != "0" {
+			return nil, fmt.Errorf("okx error code: %s, msg: %s", result.Code, result.Msg)
+		}
+
+		for _, f := range result.Data {
+			price, _ := strconv.ParseFloat(f.FillPx, 64)
+			size, _ := strconv.ParseFloat(f.FillSz, 64)
+			fee, _ := strconv.ParseFloat(f.Fee, 64)
+			pnl, _ := strconv.ParseFloat(f.FillPnl, 64)
+			ts, _ := strconv.ParseInt(f.Ts, 10, 64)
+
+			fills = append(fills, common.AccountFill{
+				Exchange:      o.GetName(),
+				Market:        market,
+				Pair:          pairName,
+				FillID:        f.TradeID,
+				OrderID:       f.OrdID,
+				ClientOrderID: f.ClOrdID,
+				Side:          f.Side,
+				Price:         price,
+				Quantity:      size * contractValue,
+				Fee:           -fee, // OKX reports charges as negative amounts
+				FeeAsset:      f.FeeCcy,
+				RealizedPnL:   pnl,
+				Time:          time.UnixMilli(ts),
+			})
+		}
+
+		next := ""
+		if len(result.Data) > 0 {
+			next = result.Data[len(result.Data)-1].BillID
+		}
+		pager.Page(len(result.Data), next)
+	}
+
+	// Oldest first
+	for i, j := 0, len(fills)-1; i < j; i, j = i+1, j-1 {
+		fills[i], fills[j] = fills[j], fills[i]
+	}
+	return fills, nil
+}
+
+// swapContractValue returns the base quantity one contract of the swap instrument represents, loaded once per instrument
+func (o *OkxClient) swapContractValue(ctx context.Context, instID string) (float64, error) {
+	o.instrumentsMu.Lock()
+	defer o.instrumentsMu.Unlock()
+
+	if ctVal, ok := o.contractValues[instID]; ok {
+		return ctVal, nil
+	}
+
+	var result struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data []struct {
+			CtVal string `json:"ctVal"`
+		} `json:"data"`
+	}
+	if err := o.signedRequest(ctx, "GET", "/api/v5/public/instruments?instType=SWAP&instId="+instID, "", &result); err != nil {
+		return 0, fmt.Errorf("failed to get instrument %s: %w", instID, err)
+	}
+	if result.Code != "0" || len(result.Data) == 0 {
+		return 0, fmt.Errorf("no instrument %s: %s", instID, result.Msg)
+	}
+	ctVal, err := strconv.ParseFloat(result.Data[0].CtVal, 64)
+	if err != nil || ctVal <= 0 {
+		return 0, fmt.Errorf("invalid contract value %q for %s", result.Data[0].CtVal, instID)
+	}
+
+	if o.contractValues == nil {
+		o.contractValues = make(map[string]float64)
+	}
+	o.contractValues[instID] = ctVal
+	return ctVal, nil
+}
@@ -0,0 +1,93 @@
+package okx
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+
+	"arbitrage.trade/clients/common"
+)
+
+// Spot lot rules and fee currencies
+//
+// OKX charges a spot buy's fee in the base asset it bought, so the holding left to sell is the
+// filled size less that fee. Buys record the net quantity, closing sells are sized from the
+// holding OKX reports and floored to the instrument's lot size, and fees are reported in USDT
+// whichever currency they were charged in.
+
+// spotInstrument is a spot instrument's order size rules from /api/v5/public/instruments
+type spotInstrument struct {
+	LotSz float64
+	MinSz float64
+}
+
+// spotInstrument returns the instrument's lot rules, loaded once per instrument
+func (o *OkxClient) spotInstrument(ctx context.Context, instId string) (spotInstrument, error) {
+	o.instrumentsMu.Lock()
+	defer o.instrumentsMu.Unlock()
+
+	if inst, ok := o.instruments[instId]; ok {
+		return inst, nil
+	}
+
+	var result struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data []struct {
+			LotSz string `json:"lotSz"`
+			MinSz string `json:"minSz"`
+		} `json:"data"`
+	}
+	if err := o.signedRequest(ctx, "GET", "/api/v5/public/instruments?instType=SPOT&instId="+instId, "", &result); err != nil {
+		return spotInstrument{}, fmt.Errorf("failed to get instrument %s: %w", instId, err)
+	}
+	if result.Code != "0" || len(result.Data) == 0 {
+		return spotInstrument{}, fmt.Errorf("no instrument %s: %s", instId, result.Msg)
+	}
+
+	inst := spotInstrument{}
+	inst.LotSz, _ = strconv.ParseFloat(result.Data[0].LotSz, 64)
+	inst.MinSz, _ = strconv.ParseFloat(result.Data[0].MinSz, 64)
+
+	if o.instruments == nil {
+		o.instruments = make(map[string]spotInstrument)
+	}
+	o.instruments[instId] = inst
+	return inst, nil
+}
+
+// lotQuantity floors a spot quantity to the instrument's lot size and the pair's precision
+// Falls back to the pair's precision alone when the instrument can't be loaded
+func (o *OkxClient) lotQuantity(ctx context.Context, instId, pairName string, quantity float64) (float64, error) {
+	quantity = common.RoundQuantity(quantity, pairName)
+
+	inst, err := o.spotInstrument(ctx, instId)
+	if err != nil || !common.IsPositive(inst.LotSz) {
+		return quantity, nil
+	}
+
+	quantity = common.RoundQuantity(math.Floor(quantity/inst.LotSz+1e-9)*inst.LotSz, pairName)
+	if common.LessThan(quantity, inst.MinSz) {
+		return 0, fmt.Errorf("%s quantity %.8f below minimum %.8f", instId, quantity, inst.MinSz)
+	}
+	return quantity, nil
+}
+
+// spotFill converts an order's fill to the base quantity it leaves held and its fee in USDT
+// OKX reports fees as negative amounts of feeCcy, rebates as positive ones
+func spotFill(side, baseAsset, feeCcy string, fillSz, avgPx, fee float64) (net, feeUSDT float64) {
+	charged := -fee
+	net = fillSz
+
+	switch feeCcy {
+	case baseAsset:
+		feeUSDT = charged * avgPx
+		if side == "buy" {
+			net = fillSz - charged
+		}
+	default:
+		feeUSDT = charged
+	}
+	return net, feeUSDT
+}
@@ -0,0 +1,149 @@
+package okx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"arbitrage.trade/clients/common"
+)
+
+// Resting (maker) limit orders
+//
+// Maker legs rest a post_only order, which OKX cancels instead of letting it take liquidity. Spot
+// sizes are floored to the instrument's lot size and swap sizes converted to whole contracts of the
+// instrument's contract value; fills are reported back in base units with their fee in USDT, a
+// maker rebate (a positive fee on OKX) as a negative one.
+
+// limitInstrument returns the instrument ID and trade mode of a market's orders and the base quantity
+// one unit of its size stands for
+func (o *OkxClient) limitInstrument(ctx context.Context, pairName, market string) (instId, tdMode string, unit float64, err error) {
+	if market != "futures" {
+		tdMode, err = o.spotTdMode(ctx)
+		return o.normalizeSymbol(pairName), tdMode, 1, err
+	}
+	instId = o.normalizeSymbolFutures(pairName)
+	if tdMode, err = o.futuresTdMode(ctx); err != nil {
+		return instId, "", 0, err
+	}
+	unit, err = o.swapContractValue(ctx, instId)
+	return instId, tdMode, unit, err
+}
+
+// PlaceLimitOrder rests a post-only limit order for a base quantity and returns its order ID
+func (o *OkxClient) PlaceLimitOrder(ctx context.Context, pairName string, market string, side string, price float64, quantity float64) (string, error) {
+	instId, tdMode, unit, err := o.limitInstrument(ctx, pairName, market)
+	if err != nil {
+		return "", err
+	}
+
+	size := ""
+	if market == "futures" {
+		contracts := math.Floor(quantity/unit + 1e-9)
+		if contracts < 1 {
+			return "", fmt.Errorf("%s quantity %.8f is less than one contract of %.8f", instId, quantity, unit)
+		}
+		size = strconv.FormatFloat(contracts, 'f', -1, 64)
+	} else {
+		if quantity, err = o.lotQuantity(ctx, instId, pairName, quantity); err != nil {
+			return "", err
+		}
+		size = common.FormatQuantity(quantity, pairName)
+	}
+
+	orderReq := map[string]interface{}{
+		"instId":  instId,
+		"tdMode":  tdMode,
+		"side":    side,
+		"ordType": "post_only",
+		"clOrdId": common.NewClientOrderID(),
+		"px":      common.FormatPrice(price, pairName),
+		"sz":      size,
+	}
+	body, _ := json.Marshal(orderReq)
+
+	var result struct {
+		Code string          `json:"code"`
+		Msg  string          `json:"msg"`
+		Data []OrderResponse `json:"data"`
+	}
+	if err := o.signedRequest(ctx, "POST", "/api/v5/trade/order", string(body), &result); err != nil {
+		return "", fmt.Errorf("limit order failed: %w", err)
+	}
+	if result.Code != "0" || len(result.Data) == 0 {
+		msg := result.Msg
+		if len(result.Data) > 0 && result.Data[0].SMsg != "" {
+			msg = result.Data[0].SMsg
+		}
+		return "", fmt.Errorf("order failed: code %s, msg: %s", result.Code, msg)
+	}
+	return result.Data[0].OrdId, nil
+}
+
+// GetLimitOrder returns a limit order's fill in base units and its fee in USDT
+func (o *OkxClient) GetLimitOrder(ctx context.Context, pairName string, market string, orderID string) (*common.TradeResult, bool, error) {
+	instId, _, unit, err := o.limitInstrument(ctx, pairName, market)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var result struct {
+		Code string          `json:"code"`
+		Msg  string          `json:"msg"`
+		Data []OrderResponse `json:"data"`
+	}
+	endpoint := fmt.Sprintf("/api/v5/trade/order?instId=%s&ordId=%s", instId, orderID)
+	if err := o.signedRequest(ctx, "GET", endpoint, "", &result); err != nil {
+		return nil, false, fmt.Errorf("failed to get order %s: %w", orderID, err)
+	}
+	if result.Code != "0" || len(result.Data) == 0 {
+		return nil, false, fmt.Errorf("okx error code: %s, msg: %s", result.Code, result.Msg)
+	}
+
+	order := result.Data[0]
+	avgPx, _ := strconv.ParseFloat(order.AvgPx, 64)
+	fillSz, _ := strconv.ParseFloat(order.AccFillSz, 64)
+	fee, _ := strconv.ParseFloat(order.Fee, 64)
+
+	quantity, feeUSDT := fillSz*unit, -fee
+	if market != "futures" {
+		_, feeUSDT = spotFill(order.Side, strings.Split(instId, "-")[0], order.FeeCcy, fillSz, avgPx, fee)
+	}
+
+	return &common.TradeResult{
+		OrderID:       orderID,
+		ExecutedPrice: avgPx,
+		ExecutedQty:   quantity,
+		Fee:           feeUSDT,
+		Success:       order.State == "filled",
+	}, order.State != "live" && order.State != "partially_filled", nil
+}
+
+// CancelLimitOrder cancels a resting limit order; one that finished in the meantime is not an error
+func (o *OkxClient) CancelLimitOrder(ctx context.Context, pairName string, market string, orderID string) error {
+	instId, _, _, err := o.limitInstrument(ctx, pairName, market)
+	if err != nil {
+		return err
+	}
+
+	body, _ := json.Marshal(map[string]interface{}{"instId": instId, "ordId": orderID})
+	var result struct {
+		Code string          `json:"code"`
+		Msg  string          `json:"msg"`
+		Data []OrderResponse `json:"data"`
+	}
+	err = o.signedRequest(ctx, "POST", "/api/v5/trade/cancel-order", string(body), &result)
+	if err == nil && result.Code == "0" {
+		return nil
+	}
+	if _, done, getErr := o.GetLimitOrder(ctx, pairName, market, orderID); getErr == nil && done {
+		return nil
+	}
+	if err == nil {
+		err = fmt.Errorf("okx error code: %s, msg: %s", result.Code, result.Msg)
+	}
+	return fmt.Errorf("failed to cancel order %s: %w", orderID, err)
+}
@@ -0,0 +1,60 @@
+package okx
+
+import (
+	"context"
+	"testing"
+
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/clients/fixturetest"
+)
+
+// TestLimitOrders replays the maker order cycle from testdata/limit/okx.json, which needs no taker
+// order: swap orders are sized in whole contracts on the wire and their fills read back in XRP, and a
+// maker rebate comes back as a negative fee
+func TestLimitOrders(t *testing.T) {
+	rec := fixturetest.Replay(t, "okx", "testdata/limit")
+	client := NewOkxClient(fixtureKey, fixtureSecret, fixturePassphrase)
+	ctx := context.Background()
+
+	orderID, err := client.PlaceLimitOrder(ctx, fixturePair, "futures", "sell", 0.55, 250)
+	if err != nil || orderID != "900010" {
+		t.Fatalf("PlaceLimitOrder futures = %q, %v, want 900010", orderID, err)
+	}
+
+	result, done, err := client.GetLimitOrder(ctx, fixturePair, "futures", orderID)
+	if done {
+		t.Error("GetLimitOrder reported a partially filled order done")
+	}
+	fixturetest.CheckResult(t, "GetLimitOrder futures", result, err, common.TradeResult{
+		OrderID: "900010", ExecutedPrice: 0.55, ExecutedQty: 100, Fee: -0.011,
+	})
+
+	if err := client.CancelLimitOrder(ctx, fixturePair, "futures", orderID); err != nil {
+		t.Errorf("CancelLimitOrder: %v", err)
+	}
+	result, done, err = client.GetLimitOrder(ctx, fixturePair, "futures", orderID)
+	if !done {
+		t.Error("GetLimitOrder reported a cancelled order still resting")
+	}
+	fixturetest.CheckResult(t, "GetLimitOrder futures cancelled", result, err, common.TradeResult{
+		OrderID: "900010", ExecutedPrice: 0.55, ExecutedQty: 100, Fee: -0.011,
+	})
+
+	orderID, err = client.PlaceLimitOrder(ctx, fixturePair, "spot", "buy", 0.5, 40.16)
+	if err != nil || orderID != "800010" {
+		t.Fatalf("PlaceLimitOrder spot = %q, %v, want 800010", orderID, err)
+	}
+	result, done, err = client.GetLimitOrder(ctx, fixturePair, "spot", orderID)
+	if !done {
+		t.Error("GetLimitOrder reported a filled order still resting")
+	}
+	fixturetest.CheckResult(t, "GetLimitOrder spot", result, err, common.TradeResult{
+		OrderID: "800010", ExecutedPrice: 0.5, ExecutedQty: 40.1, Fee: -0.002005, Success: true,
+	})
+
+	if orders := rec.FindAll("POST", "/api/v5/trade/order"); len(orders) != 2 {
+		t.Errorf("%d orders sent, want 2", len(orders))
+	}
+	checkSignatures(t, rec)
+	fixturetest.CheckReplayed(t, "okx")
+}
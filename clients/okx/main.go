@@ -3,9 +3,8 @@ package okx
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
-	"net/http"
-	"time"
 
 	"arbitrage.trade/clients/common"
 )
@@ -16,17 +15,20 @@ func NewOkxClient(apiKey, apiSecret, passphrase string) *OkxClient {
 		apiSecret:  apiSecret,
 		passphrase: passphrase,
 		baseURL:    "https://www.okx.com",
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-		positions: make(map[string]*common.Position),
+		rest:       common.NewRESTClient("okx"),
+		positions:  make(map[string]*common.Position),
+	}
+
+	// Without credentials the client only serves public market data
+	if apiKey == "" {
+		return client
 	}
 
 	// Initialize account settings
 	ctx := context.Background()
 	if err := client.initializeAccount(ctx); err != nil {
 		log.Printf("⚠️  [OKX] Failed to initialize account settings: %v", err)
-		log.Printf("💡 [OKX] Please manually configure: Account Mode = Single-currency margin, Position Mode = Net mode")
+		log.Printf("💡 [OKX] Please manually configure: Account Mode = Spot and futures or Multi-currency margin (auto borrow off), Position Mode = Net mode")
 	}
 
 	return client
@@ -60,9 +62,40 @@ func (o *OkxClient) initializeAccount(ctx context.Context) error {
 		}
 	}
 
+	// Detect the account mode so order tdMode and balance parsing match it
+	account, err := o.getAccountConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	if _, err := o.futuresTdMode(ctx); err != nil {
+		return err
+	}
+	if _, err := o.spotTdMode(ctx); err != nil {
+		return err
+	}
+
+	log.Printf("✅ [OKX] Account mode %s is compatible", accountModeName(account.AcctLv))
 	return nil
 }
 
 func (o *OkxClient) GetName() string {
 	return "okx"
 }
+
+// Prewarm opens a pooled connection to the API
+func (o *OkxClient) Prewarm(ctx context.Context) {
+	common.PrewarmConnections(ctx, o.rest.HTTP, o.baseURL+"/api/v5/public/time")
+}
+
+// GetUSDTBalance returns the available USDT on the given market ("spot" or "futures")
+func (o *OkxClient) GetUSDTBalance(ctx context.Context, market string) (float64, error) {
+	switch market {
+	case "spot":
+		return o.getSpotBalance(ctx, "USDT")
+	case "futures":
+		return o.getFuturesBalance(ctx)
+	default:
+		return 0, fmt.Errorf("no USDT balance for market %s", market)
+	}
+}
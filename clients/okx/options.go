@@ -0,0 +1,293 @@
+package okx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"arbitrage.trade/clients/common"
+)
+
+// OKX options are coin-margined: premiums, prices and fees are quoted in the underlying
+// (BTC for BTC-USD), so buying them needs a balance of the base asset on the trading account.
+// Everything reported back is converted to USDT at the spot price.
+
+type OptionInstrument struct {
+	InstId  string `json:"instId"`
+	Stk     string `json:"stk"`
+	ExpTime string `json:"expTime"`
+	OptType string `json:"optType"`
+	CtVal   string `json:"ctVal"`
+	CtMult  string `json:"ctMult"`
+	LotSz   string `json:"lotSz"`
+	MinSz   string `json:"minSz"`
+	State   string `json:"state"`
+}
+
+// optionFamily converts "btc-usdt" to the options family "BTC-USD"
+func (o *OkxClient) optionFamily(pairName string) string {
+	return strings.ToUpper(strings.Split(pairName, "-")[0]) + "-USD"
+}
+
+func (o *OkxClient) getOptionInstruments(ctx context.Context, family string) ([]OptionInstrument, error) {
+	var result struct {
+		Code string             `json:"code"`
+		Msg  string             `json:"msg"`
+		Data []OptionInstrument `json:"data"`
+	}
+
+	endpoint := "/api/v5/public/instruments?instType=OPTION&instFamily=" + family
+	if err := o.signedRequest(ctx, "GET", endpoint, "", &result); err != nil {
+		return nil, fmt.Errorf("failed to get option instruments: %w", err)
+	}
+	if result.Code != "0" {
+		return nil, fmt.Errorf("okx error code: %s, msg: %s", result.Code, result.Msg)
+	}
+	return result.Data, nil
+}
+
+// getOptionBook returns the best bid and ask of an option in the underlying coin
+func (o *OkxClient) getOptionBook(ctx context.Context, instId string) (float64, float64, error) {
+	var result struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data []struct {
+			BidPx string `json:"bidPx"`
+			AskPx string `json:"askPx"`
+		} `json:"data"`
+	}
+
+	if err := o.signedRequest(ctx, "GET", "/api/v5/market/ticker?instId="+instId, "", &result); err != nil {
+		return 0, 0, fmt.Errorf("failed to get option ticker: %w", err)
+	}
+	if result.Code != "0" || len(result.Data) == 0 {
+		return 0, 0, fmt.Errorf("no ticker data for %s", instId)
+	}
+
+	bid, _ := strconv.ParseFloat(result.Data[0].BidPx, 64)
+	ask, _ := strconv.ParseFloat(result.Data[0].AskPx, 64)
+	return bid, ask, nil
+}
+
+// QuoteProtectiveOption picks the nearest-expiry live option at least otmPct out of the money
+// and sizes it in whole lots to cover notionalUSDT of the underlying
+func (o *OkxClient) QuoteProtectiveOption(ctx context.Context, pairName string, optionType common.OptionType,
+	notionalUSDT, otmPct float64, maxExpiry time.Duration) (*common.OptionQuote, error) {
+
+	family := o.optionFamily(pairName)
+	instruments, err := o.getOptionInstruments(ctx, family)
+	if err != nil {
+		return nil, err
+	}
+	if len(instruments) == 0 {
+		return nil, fmt.Errorf("no %s options listed: %w", family, common.ErrInvalidPair)
+	}
+
+	spot, err := o.getPrice(ctx, o.normalizeSymbol(pairName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spot price: %w", err)
+	}
+
+	optType := "C"
+	target := spot * (1 + otmPct/100)
+	if optionType == common.Put {
+		optType = "P"
+		target = spot * (1 - otmPct/100)
+	}
+
+	now := time.Now()
+	var best *OptionInstrument
+	var bestExpiry time.Time
+	var bestStrike float64
+
+	for i := range instruments {
+		inst := &instruments[i]
+		if inst.State != "live" || inst.OptType != optType {
+			continue
+		}
+
+		expMs, _ := strconv.ParseInt(inst.ExpTime, 10, 64)
+		expiry := time.UnixMilli(expMs)
+		if !expiry.After(now) || expiry.Sub(now) > maxExpiry {
+			continue
+		}
+
+		strike, _ := strconv.ParseFloat(inst.Stk, 64)
+		if (optType == "C" && strike < target) || (optType == "P" && strike > target) {
+			continue
+		}
+
+		// Nearest expiry first, then the strike closest to the target
+		if best == nil || expiry.Before(bestExpiry) ||
+			(expiry.Equal(bestExpiry) && math.Abs(strike-target) < math.Abs(bestStrike-target)) {
+			best, bestExpiry, bestStrike = inst, expiry, strike
+		}
+	}
+
+	if best == nil {
+		return nil, fmt.Errorf("no %s %s option %.1f%% out of the money within %s", family, optionType, otmPct, maxExpiry)
+	}
+
+	ctVal, _ := strconv.ParseFloat(best.CtVal, 64)
+	ctMult, _ := strconv.ParseFloat(best.CtMult, 64)
+	lotSz, _ := strconv.ParseFloat(best.LotSz, 64)
+	minSz, _ := strconv.ParseFloat(best.MinSz, 64)
+	if common.IsZero(ctMult) {
+		ctMult = 1
+	}
+	underlyingPerContract := ctVal * ctMult
+	if common.IsNegativeOrZero(underlyingPerContract) || common.IsNegativeOrZero(lotSz) {
+		return nil, fmt.Errorf("invalid contract spec for %s", best.InstId)
+	}
+
+	contracts := math.Ceil(notionalUSDT/(underlyingPerContract*spot)/lotSz) * lotSz
+	if contracts < minSz {
+		contracts = minSz
+	}
+
+	_, ask, err := o.getOptionBook(ctx, best.InstId)
+	if err != nil {
+		return nil, err
+	}
+	if common.IsNegativeOrZero(ask) {
+		return nil, fmt.Errorf("no ask for %s", best.InstId)
+	}
+
+	price := ask * underlyingPerContract * spot
+	return &common.OptionQuote{
+		Instrument: best.InstId,
+		Type:       optionType,
+		Strike:     bestStrike,
+		Expiry:     bestExpiry,
+		Contracts:  contracts,
+		Price:      price,
+		Premium:    contracts * price,
+		Covered:    contracts * underlyingPerContract * spot,
+	}, nil
+}
+
+// BuyOption buys the quoted contracts immediate-or-cancel at the quoted ask
+func (o *OkxClient) BuyOption(ctx context.Context, quote *common.OptionQuote) (*common.TradeResult, error) {
+	_, ask, err := o.getOptionBook(ctx, quote.Instrument)
+	if err != nil {
+		return nil, err
+	}
+	return o.placeOptionOrder(ctx, quote.Instrument, "buy", ask, quote.Contracts)
+}
+
+// SellOption sells held contracts immediate-or-cancel at the bid
+func (o *OkxClient) SellOption(ctx context.Context, instrument string, contracts float64) (*common.TradeResult, error) {
+	bid, _, err := o.getOptionBook(ctx, instrument)
+	if err != nil {
+		return nil, err
+	}
+	if common.IsNegativeOrZero(bid) {
+		return nil, fmt.Errorf("no bid for %s: %w", instrument, common.ErrOrderFailed)
+	}
+	return o.placeOptionOrder(ctx, instrument, "sell", bid, contracts)
+}
+
+// placeOptionOrder sends an IOC option order priced in the underlying coin and reports the fill in USDT
+func (o *OkxClient) placeOptionOrder(ctx context.Context, instId, side string, price, contracts float64) (*common.TradeResult, error) {
+	tdMode, err := o.futuresTdMode(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	orderReq := map[string]interface{}{
+		"instId":  instId,
+		"tdMode":  tdMode,
+		"side":    side,
+		"ordType": "ioc",
+		"px":      strconv.FormatFloat(price, 'f', -1, 64),
+		"sz":      strconv.FormatFloat(contracts, 'f', -1, 64),
+	}
+
+	body, _ := json.Marshal(orderReq)
+
+	var result struct {
+		Code string          `json:"code"`
+		Msg  string          `json:"msg"`
+		Data []OrderResponse `json:"data"`
+	}
+
+	if err := o.signedRequest(ctx, "POST", "/api/v5/trade/order", string(body), &result); err != nil {
+		log.Printf("[OKX] placeOptionOrder - ERROR: %s %s failed: %v", side, instId, err)
+		return nil, fmt.Errorf("option order failed: %w", err)
+	}
+
+	if result.Code != "0" {
+		msg := result.Msg
+		if len(result.Data) > 0 && result.Data[0].SMsg != "" {
+			msg = result.Data[0].SMsg
+		}
+		return nil, fmt.Errorf("option order failed: code %s, msg: %s", result.Code, msg)
+	}
+
+	if len(result.Data) == 0 {
+		return nil, fmt.Errorf("order response empty")
+	}
+
+	orderData := result.Data[0]
+
+	// IOC orders settle asynchronously, query for fill details
+	time.Sleep(200 * time.Millisecond)
+
+	var orderQueryResult struct {
+		Code string          `json:"code"`
+		Data []OrderResponse `json:"data"`
+	}
+
+	queryEndpoint := fmt.Sprintf("/api/v5/trade/order?instId=%s&ordId=%s", instId, orderData.OrdId)
+	if err := o.signedRequest(ctx, "GET", queryEndpoint, "", &orderQueryResult); err == nil && len(orderQueryResult.Data) > 0 {
+		orderData.AvgPx = orderQueryResult.Data[0].AvgPx
+		orderData.AccFillSz = orderQueryResult.Data[0].AccFillSz
+		orderData.Fee = orderQueryResult.Data[0].Fee
+		orderData.State = orderQueryResult.Data[0].State
+	}
+
+	fillSz, _ := strconv.ParseFloat(orderData.AccFillSz, 64)
+	if common.IsZero(fillSz) {
+		return nil, fmt.Errorf("option %s %s not filled at %g: %w", side, instId, price, common.ErrOrderFailed)
+	}
+
+	avgPx, _ := strconv.ParseFloat(orderData.AvgPx, 64)
+	fee, _ := strconv.ParseFloat(orderData.Fee, 64)
+
+	// Convert coin-quoted premium and fee to USDT
+	family := strings.Join(strings.SplitN(instId, "-", 3)[:2], "-")
+	spot, err := o.getPrice(ctx, strings.TrimSuffix(family, "-USD")+"-USDT")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spot price: %w", err)
+	}
+
+	var contractSpec struct {
+		Code string             `json:"code"`
+		Data []OptionInstrument `json:"data"`
+	}
+	underlyingPerContract := 0.0
+	specEndpoint := fmt.Sprintf("/api/v5/public/instruments?instType=OPTION&instFamily=%s&instId=%s", family, instId)
+	if err := o.signedRequest(ctx, "GET", specEndpoint, "", &contractSpec); err == nil && len(contractSpec.Data) > 0 {
+		ctVal, _ := strconv.ParseFloat(contractSpec.Data[0].CtVal, 64)
+		ctMult, _ := strconv.ParseFloat(contractSpec.Data[0].CtMult, 64)
+		if common.IsZero(ctMult) {
+			ctMult = 1
+		}
+		underlyingPerContract = ctVal * ctMult
+	} else {
+		log.Printf("[OKX] placeOptionOrder - ERROR: Failed to get contract spec for %s, fill price unknown: %v", instId, err)
+	}
+
+	return &common.TradeResult{
+		OrderID:       orderData.OrdId,
+		ExecutedPrice: avgPx * underlyingPerContract * spot,
+		ExecutedQty:   fillSz,
+		Fee:           math.Abs(fee) * spot,
+		Success:       orderData.State == "filled",
+	}, nil
+}
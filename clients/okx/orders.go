@@ -0,0 +1,39 @@
+package okx
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"arbitrage.trade/clients/common"
+)
+
+// HasBotOrders reports whether an order tagged with the bot's client order ID prefix was placed on the pair since the given time
+// The order history endpoint covers the last 7 days
+func (o *OkxClient) HasBotOrders(ctx context.Context, market, pairName string, since time.Time) (bool, error) {
+	endpoint := fmt.Sprintf("/api/v5/trade/orders-history?instType=SPOT&instId=%s&begin=%d",
+		o.normalizeSymbol(pairName), since.UnixMilli())
+	if market == "futures" {
+		endpoint = fmt.Sprintf("/api/v5/trade/orders-history?instType=SWAP&instId=%s&begin=%d",
+			o.normalizeSymbolFutures(pairName), since.UnixMilli())
+	}
+
+	var result struct {
+		Code string          `json:"code"`
+		Msg  string          `json:"msg"`
+		Data []OrderResponse `json:"data"`
+	}
+	if err := o.signedRequest(ctx, "GET", endpoint, "", &result); err != nil {
+		return false, err
+	}
+	if result.Code != "0" {
+		return false, fmt.Errorf("okx error code: %s, msg: %s", result.Code, result.Msg)
+	}
+
+	for _, order := range result.Data {
+		if common.IsBotOrderID(order.ClOrdId) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
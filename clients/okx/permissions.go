@@ -0,0 +1,41 @@
+package okx
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"arbitrage.trade/clients/common"
+)
+
+// GetAPIPermissions returns the key's scopes and IP binding from the account configuration
+// OKX grants one "trade" scope for every instrument type
+func (o *OkxClient) GetAPIPermissions(ctx context.Context) (*common.APIPermissions, error) {
+	var response struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data []struct {
+			Perm string `json:"perm"` // e.g. "read_only,trade"
+			IP   string `json:"ip"`
+		} `json:"data"`
+	}
+	if err := o.signedRequest(ctx, "GET", "/api/v5/account/config", "", &response); err != nil {
+		return nil, err
+	}
+	if response.Code != "0" || len(response.Data) == 0 {
+		return nil, fmt.Errorf("okx account config error: %s", response.Msg)
+	}
+
+	perms := make(map[string]bool)
+	for _, p := range strings.Split(response.Data[0].Perm, ",") {
+		perms[strings.TrimSpace(p)] = true
+	}
+
+	return &common.APIPermissions{
+		Read:         true, // The request above only succeeds with read access
+		SpotTrade:    perms["trade"],
+		FuturesTrade: perms["trade"],
+		Withdraw:     perms["withdraw"],
+		IPRestricted: strings.TrimSpace(response.Data[0].IP) != "",
+	}, nil
+}
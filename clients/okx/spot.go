@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log"
 	"strconv"
 	"strings"
 	"time"
@@ -12,6 +13,15 @@ import (
 )
 
 func (o *OkxClient) getSpotBalance(ctx context.Context, ccy string) (float64, error) {
+	balances, err := common.CachedBalances(ctx, o.GetName(), "spot", o.fetchSpotBalances)
+	if err != nil {
+		return 0, err
+	}
+	return balances[ccy], nil
+}
+
+// fetchSpotBalances reads the available balance of every currency in one account balance call
+func (o *OkxClient) fetchSpotBalances(ctx context.Context) (common.BalanceSnapshot, error) {
 	var result struct {
 		Code string `json:"code"`
 		Msg  string `json:"msg"`
@@ -20,25 +30,29 @@ func (o *OkxClient) getSpotBalance(ctx context.Context, ccy string) (float64, er
 		} `json:"data"`
 	}
 
-	// OKX unified account endpoint - gets balance with details array
-	if err := o.signedRequest(ctx, "GET", "/api/v5/account/balance?ccy="+ccy, "", &result); err != nil {
-		return 0, fmt.Errorf("failed to get balance: %w", err)
+	// OKX unified account endpoint - without ccy it lists every currency held
+	if err := o.signedRequest(ctx, "GET", "/api/v5/account/balance", "", &result); err != nil {
+		return nil, fmt.Errorf("failed to get balance: %w", err)
 	}
 
 	if result.Code != "0" {
-		return 0, fmt.Errorf("okx error code: %s, msg: %s", result.Code, result.Msg)
+		return nil, fmt.Errorf("okx error code: %s, msg: %s", result.Code, result.Msg)
 	}
 
-	if len(result.Data) > 0 && len(result.Data[0].Details) > 0 {
+	balances := make(common.BalanceSnapshot)
+	if len(result.Data) > 0 {
 		for _, detail := range result.Data[0].Details {
-			if detail.Ccy == ccy {
-				available, _ := strconv.ParseFloat(detail.AvailBal, 64)
-				return available, nil
-			}
+			available, _ := strconv.ParseFloat(detail.AvailBal, 64)
+			balances[detail.Ccy] = available
 		}
 	}
+	return balances, nil
+}
 
-	return 0, nil
+// GetSpotHolding returns the available base-asset balance for the pair
+func (o *OkxClient) GetSpotHolding(ctx context.Context, pairName string) (float64, error) {
+	baseAsset := strings.Split(o.normalizeSymbol(pairName), "-")[0]
+	return o.getSpotBalance(ctx, baseAsset)
 }
 
 func (o *OkxClient) PutSpotLong(ctx context.Context, pairName string, amountUSDT float64) (*common.TradeResult, error) {
@@ -51,11 +65,17 @@ func (o *OkxClient) PutSpotLong(ctx context.Context, pairName string, amountUSDT
 
 	common.SetBalance(o.GetName(), "spot", "USDT", balance)
 
+	tdMode, err := o.spotTdMode(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	orderReq := map[string]interface{}{
 		"instId":  instId,
-		"tdMode":  "cash",
+		"tdMode":  tdMode,
 		"side":    "buy",
 		"ordType": "market",
+		"clOrdId": common.NewClientOrderID(),
 		"sz":      fmt.Sprintf("%.8f", amountUSDT),
 		"tgtCcy":  "quote_ccy",
 	}
@@ -96,6 +116,7 @@ func (o *OkxClient) PutSpotLong(ctx context.Context, pairName string, amountUSDT
 			AvgPx     string `json:"avgPx"`
 			AccFillSz string `json:"accFillSz"`
 			Fee       string `json:"fee"`
+			FeeCcy    string `json:"feeCcy"`
 			State     string `json:"state"`
 		} `json:"data"`
 	}
@@ -105,6 +126,7 @@ func (o *OkxClient) PutSpotLong(ctx context.Context, pairName string, amountUSDT
 		orderData.AvgPx = orderQueryResult.Data[0].AvgPx
 		orderData.AccFillSz = orderQueryResult.Data[0].AccFillSz
 		orderData.Fee = orderQueryResult.Data[0].Fee
+		orderData.FeeCcy = orderQueryResult.Data[0].FeeCcy
 		orderData.State = orderQueryResult.Data[0].State
 	}
 
@@ -112,13 +134,17 @@ func (o *OkxClient) PutSpotLong(ctx context.Context, pairName string, amountUSDT
 	fillSz, _ := strconv.ParseFloat(orderData.AccFillSz, 64)
 	fee, _ := strconv.ParseFloat(orderData.Fee, 64)
 
+	// The fee comes out of the bought base asset, only the net quantity is left to sell
+	baseAsset := strings.Split(instId, "-")[0]
+	held, feeUSDT := spotFill("buy", baseAsset, orderData.FeeCcy, fillSz, avgPx, fee)
+
 	o.mu.Lock()
 	o.positions[pairName+"_spot"] = &common.Position{
 		PairName:     pairName,
 		Side:         "long",
 		Market:       "spot",
 		EntryPrice:   avgPx,
-		Quantity:     fillSz,
+		Quantity:     held,
 		AmountUSDT:   amountUSDT,
 		OrderID:      orderId,
 		ExchangeName: o.GetName(),
@@ -129,7 +155,7 @@ func (o *OkxClient) PutSpotLong(ctx context.Context, pairName string, amountUSDT
 		OrderID:       orderId,
 		ExecutedPrice: avgPx,
 		ExecutedQty:   fillSz,
-		Fee:           fee,
+		Fee:           feeUSDT,
 		Success:       orderData.State == "filled",
 	}, nil
 }
@@ -138,7 +164,7 @@ func (o *OkxClient) CloseSpotLong(ctx context.Context, pairName string, amountUS
 	instId := o.normalizeSymbol(pairName)
 
 	o.mu.RLock()
-	_, exists := o.positions[pairName+"_spot"]
+	position, exists := o.positions[pairName+"_spot"]
 	o.mu.RUnlock()
 
 	if !exists {
@@ -155,13 +181,26 @@ func (o *OkxClient) CloseSpotLong(ctx context.Context, pairName string, amountUS
 		return nil, 0.0, fmt.Errorf("no %s balance to sell", baseAsset)
 	}
 
-	sellQuantity := common.RoundQuantity(balance, pairName)
+	// Sized from the holding OKX reports, which already has the buy's base-asset fee taken out
+	if common.LessThan(balance, position.Quantity) {
+		log.Printf("⚠️  [OKX] CloseSpotLong - %s holding %.8f below the %.8f bought net of fees", baseAsset, balance, position.Quantity)
+	}
+	sellQuantity, err := o.lotQuantity(ctx, instId, pairName, balance)
+	if err != nil {
+		return nil, 0.0, err
+	}
+
+	tdMode, err := o.spotTdMode(ctx)
+	if err != nil {
+		return nil, 0.0, err
+	}
 
 	orderReq := map[string]interface{}{
 		"instId":  instId,
-		"tdMode":  "cash",
+		"tdMode":  tdMode,
 		"side":    "sell",
 		"ordType": "market",
+		"clOrdId": common.NewClientOrderID(),
 		"sz":      common.FormatQuantity(sellQuantity, pairName),
 	}
 
@@ -201,6 +240,7 @@ func (o *OkxClient) CloseSpotLong(ctx context.Context, pairName string, amountUS
 			AvgPx     string `json:"avgPx"`
 			AccFillSz string `json:"accFillSz"`
 			Fee       string `json:"fee"`
+			FeeCcy    string `json:"feeCcy"`
 			State     string `json:"state"`
 		} `json:"data"`
 	}
@@ -210,12 +250,14 @@ func (o *OkxClient) CloseSpotLong(ctx context.Context, pairName string, amountUS
 		orderData.AvgPx = orderQueryResult.Data[0].AvgPx
 		orderData.AccFillSz = orderQueryResult.Data[0].AccFillSz
 		orderData.Fee = orderQueryResult.Data[0].Fee
+		orderData.FeeCcy = orderQueryResult.Data[0].FeeCcy
 		orderData.State = orderQueryResult.Data[0].State
 	}
 
 	avgPx, _ := strconv.ParseFloat(orderData.AvgPx, 64)
 	fillSz, _ := strconv.ParseFloat(orderData.AccFillSz, 64)
 	fee, _ := strconv.ParseFloat(orderData.Fee, 64)
+	_, feeUSDT := spotFill("sell", baseAsset, orderData.FeeCcy, fillSz, avgPx, fee)
 
 	o.mu.Lock()
 	delete(o.positions, pairName+"_spot")
@@ -229,13 +271,13 @@ func (o *OkxClient) CloseSpotLong(ctx context.Context, pairName string, amountUS
 	prevBalance := common.GetBalance(o.GetName(), "spot", "USDT")
 	common.SetBalance(o.GetName(), "spot", "USDT", newBalance)
 
-	profit := newBalance - prevBalance
+	profit := common.Diff(newBalance, prevBalance)
 
 	return &common.TradeResult{
 		OrderID:       orderId,
 		ExecutedPrice: avgPx,
 		ExecutedQty:   fillSz,
-		Fee:           fee,
+		Fee:           feeUSDT,
 		Success:       orderData.State == "filled",
 	}, profit, nil
 }
@@ -1,7 +1,6 @@
 package okx
 
 import (
-	"net/http"
 	"sync"
 
 	"arbitrage.trade/clients/common"
@@ -12,10 +11,19 @@ type OkxClient struct {
 	apiSecret  string
 	passphrase string
 	baseURL    string
-	httpClient *http.Client
+	rest       *common.RESTClient
 
 	positions map[string]*common.Position
 	mu        sync.RWMutex
+
+	// Detected account mode, loaded lazily from /api/v5/account/config
+	account   *AccountConfig
+	accountMu sync.Mutex
+
+	// Spot lot rules and swap contract values by instrument, loaded on first use
+	instruments    map[string]spotInstrument
+	contractValues map[string]float64
+	instrumentsMu  sync.Mutex
 }
 
 type OkxResponse struct {
@@ -34,12 +42,14 @@ type Balance struct {
 type OrderResponse struct {
 	OrdId     string `json:"ordId"`
 	ClOrdId   string `json:"clOrdId"`
+	Side      string `json:"side"`
 	Tag       string `json:"tag"`
 	SCode     string `json:"sCode"`
 	SMsg      string `json:"sMsg"`
 	AvgPx     string `json:"avgPx"`
 	AccFillSz string `json:"accFillSz"`
 	Fee       string `json:"fee"`
+	FeeCcy    string `json:"feeCcy"`
 	State     string `json:"state"`
 }
 
@@ -53,3 +63,9 @@ type PositionData struct {
 	PosSide  string `json:"posSide"`
 	Lever    string `json:"lever"`
 }
+
+type AccountConfig struct {
+	AcctLv   string `json:"acctLv"`
+	PosMode  string `json:"posMode"`
+	AutoLoan bool   `json:"autoLoan"`
+}
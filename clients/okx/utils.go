@@ -7,11 +7,12 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
+
+	"arbitrage.trade/clients/common"
 )
 
 func (o *OkxClient) normalizeSymbol(pairName string) string {
@@ -25,59 +26,47 @@ func (o *OkxClient) normalizeSymbolFutures(pairName string) string {
 }
 
 func (o *OkxClient) signedRequest(ctx context.Context, method, endpoint, body string, result interface{}) error {
-	timestamp := time.Now().UTC().Format("2006-01-02T15:04:05.999Z")
-
-	// OKX signature: base64(HMAC-SHA256(timestamp + method + endpoint + body, secret))
-	preHash := timestamp + method + endpoint + body
-
-	h := hmac.New(sha256.New, []byte(o.apiSecret))
-	h.Write([]byte(preHash))
-	signature := base64.StdEncoding.EncodeToString(h.Sum(nil))
-
-	url := o.baseURL + endpoint
-	req, err := http.NewRequestWithContext(ctx, method, url, strings.NewReader(body))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+	// Orders, borrows and transfers move balances, whether or not they report success
+	if method != "GET" {
+		defer common.InvalidateBalances(o.GetName())
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("OK-ACCESS-KEY", o.apiKey)
-	req.Header.Set("OK-ACCESS-SIGN", signature)
-	req.Header.Set("OK-ACCESS-TIMESTAMP", timestamp)
-	req.Header.Set("OK-ACCESS-PASSPHRASE", o.passphrase)
+	resp, err := o.rest.Do(ctx, func(ctx context.Context) (*http.Request, error) {
+		timestamp := time.Now().UTC().Format("2006-01-02T15:04:05.999Z")
 
-	resp, err := o.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("http request failed: %w", err)
-	}
-	defer resp.Body.Close()
+		// OKX signature: base64(HMAC-SHA256(timestamp + method + endpoint + body, secret))
+		preHash := timestamp + method + endpoint + body
 
-	responseBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read response: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("okx api error: status %d, body: %s", resp.StatusCode, string(responseBody))
-	}
+		h := hmac.New(sha256.New, []byte(o.apiSecret))
+		h.Write([]byte(preHash))
+		signature := base64.StdEncoding.EncodeToString(h.Sum(nil))
 
-	if result != nil {
-		if err := json.Unmarshal(responseBody, result); err != nil {
-			return fmt.Errorf("failed to unmarshal response: %w", err)
+		req, err := http.NewRequestWithContext(ctx, method, o.baseURL+endpoint, strings.NewReader(body))
+		if err != nil {
+			return nil, err
 		}
+
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("OK-ACCESS-KEY", o.apiKey)
+		req.Header.Set("OK-ACCESS-SIGN", signature)
+		req.Header.Set("OK-ACCESS-TIMESTAMP", timestamp)
+		req.Header.Set("OK-ACCESS-PASSPHRASE", o.passphrase)
+		return req, nil
+	})
+	if err != nil {
+		return err
 	}
 
-	return nil
+	return resp.DecodeJSON(result)
 }
 
 func (o *OkxClient) getPrice(ctx context.Context, instId string) (float64, error) {
 	url := fmt.Sprintf("%s/api/v5/market/ticker?instId=%s", o.baseURL, instId)
 
-	resp, err := http.Get(url)
+	resp, err := o.rest.Get(ctx, url)
 	if err != nil {
 		return 0, err
 	}
-	defer resp.Body.Close()
 
 	var result struct {
 		Data []struct {
@@ -85,7 +74,7 @@ func (o *OkxClient) getPrice(ctx context.Context, instId string) (float64, error
 		} `json:"data"`
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+	if err := json.Unmarshal(resp.Body, &result); err != nil {
 		return 0, err
 	}
 
@@ -0,0 +1,49 @@
+package okx
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"arbitrage.trade/clients/common"
+)
+
+// GetAssetStatus returns the deposit/withdrawal state of a currency across its chains
+func (o *OkxClient) GetAssetStatus(ctx context.Context, asset string) (*common.AssetStatus, error) {
+	var result struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data []struct {
+			Ccy    string `json:"ccy"`
+			Chain  string `json:"chain"`
+			CanDep bool   `json:"canDep"`
+			CanWd  bool   `json:"canWd"`
+			MinFee string `json:"minFee"`
+			MinWd  string `json:"minWd"`
+		} `json:"data"`
+	}
+
+	if err := o.signedRequest(ctx, "GET", "/api/v5/asset/currencies?ccy="+asset, "", &result); err != nil {
+		return nil, fmt.Errorf("failed to get currencies: %w", err)
+	}
+	if result.Code != "0" {
+		return nil, fmt.Errorf("okx error code: %s, msg: %s", result.Code, result.Msg)
+	}
+	if len(result.Data) == 0 {
+		return nil, fmt.Errorf("asset %s not listed", asset)
+	}
+
+	networks := make([]common.NetworkStatus, 0, len(result.Data))
+	for _, c := range result.Data {
+		fee, _ := strconv.ParseFloat(c.MinFee, 64)
+		minWd, _ := strconv.ParseFloat(c.MinWd, 64)
+		networks = append(networks, common.NetworkStatus{
+			Network:     c.Chain,
+			CanDeposit:  c.CanDep,
+			CanWithdraw: c.CanWd,
+			WithdrawFee: fee,
+			WithdrawMin: minWd,
+		})
+	}
+	return common.NewAssetStatus(asset, networks), nil
+}
@@ -0,0 +1,62 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"arbitrage.trade/clients/common"
+)
+
+// optionsClient returns an exchange's options extension
+func optionsClient(exchange common.ExchangeType) (common.OptionsClient, error) {
+	client, err := getOrCreateClient(exchange)
+	if err != nil {
+		return nil, err
+	}
+
+	optionsClient, ok := client.(common.OptionsClient)
+	if !ok {
+		return nil, fmt.Errorf("%s does not support options", exchange)
+	}
+	return optionsClient, nil
+}
+
+// QuoteProtectiveOption prices a short-dated out-of-the-money option covering notionalUSDT of the pair
+func QuoteProtectiveOption(ctx context.Context, exchange common.ExchangeType, pairName string, optionType common.OptionType,
+	notionalUSDT, otmPct float64, maxExpiry time.Duration) (*common.OptionQuote, error) {
+
+	client, err := optionsClient(exchange)
+	if err != nil {
+		return nil, err
+	}
+	return client.QuoteProtectiveOption(ctx, pairName, optionType, notionalUSDT, otmPct, maxExpiry)
+}
+
+// TradeOption buys a quoted option (action "open") or sells it back (action "close") and publishes the fill
+func TradeOption(ctx context.Context, exchange common.ExchangeType, pairName string, quote *common.OptionQuote, action string, contracts float64) (*common.TradeResult, error) {
+	client, err := optionsClient(exchange)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *common.TradeResult
+	submittedAt := time.Now()
+
+	if action == "open" {
+		result, err = client.BuyOption(ctx, quote)
+	} else {
+		result, err = client.SellOption(ctx, quote.Instrument, contracts)
+	}
+
+	completedAt := time.Now()
+	if err != nil {
+		fmt.Printf("[%s] |option %s| - Failed: %s\n", exchange, action, err)
+		return nil, err
+	}
+
+	amountUSDT := result.ExecutedQty * result.ExecutedPrice
+	publishExecution(ctx, exchange, pairName, "option_"+string(quote.Type), action, amountUSDT, 0, 0.00, result, submittedAt, completedAt)
+
+	return result, nil
+}
@@ -0,0 +1,260 @@
+package paper
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/config"
+)
+
+// Queue model for simulated maker orders
+//
+// A resting order joins the back of the queue at its price, so everything displayed
+// at that level when it is placed is ahead of it. On each book evaluation:
+//   - if the opposite side reaches our price, or our level vanishes while the best
+//     price moves through it (trade-through), the remainder fills
+//   - otherwise a decrease in displayed size at our level is split into trades
+//     (PAPER_QUEUE_TRADE_SHARE) and cancels. Trades consume the queue ahead of us
+//     first and then fill us; cancels are assumed spread evenly across the queue,
+//     so only the share ahead of us advances our position
+//   - size added at our level joins behind us and changes nothing
+const evaluateInterval = 50 * time.Millisecond
+
+func (p *PaperClient) newOrderID() string {
+	p.nextOrderID++
+	return fmt.Sprintf("paper_%s_%d", p.exchange, p.nextOrderID)
+}
+
+// PlaceLimitOrder places a simulated post-only limit order
+func (p *PaperClient) PlaceLimitOrder(ctx context.Context, pairName string, market string, side string, price float64, quantity float64) (string, error) {
+	if err := p.simulatedOutage(); err != nil {
+		return "", err
+	}
+
+	isSpot := market == "spot"
+	book, err := p.getBook(pairName, isSpot)
+	if err != nil {
+		return "", err
+	}
+
+	isBuy := side == "buy"
+
+	// Post-only: reject orders that would take liquidity
+	if isBuy {
+		if bestAsk, _, ok := book.GetBestAsk(); ok && common.GreaterThanOrEqual(price, bestAsk) {
+			return "", fmt.Errorf("post-only buy at %.8f would cross ask %.8f: %w", price, bestAsk, common.ErrOrderFailed)
+		}
+	} else {
+		if bestBid, _, ok := book.GetBestBid(); ok && common.LessThanOrEqual(price, bestBid) {
+			return "", fmt.Errorf("post-only sell at %.8f would cross bid %.8f: %w", price, bestBid, common.ErrOrderFailed)
+		}
+	}
+
+	levelQty := book.LevelVolume(isBuy, price) / price
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	order := &LimitOrder{
+		ID:           p.newOrderID(),
+		PairName:     pairName,
+		Market:       market,
+		Side:         side,
+		Price:        price,
+		Quantity:     quantity,
+		Status:       "open",
+		CreatedAt:    time.Now(),
+		queueAhead:   levelQty,
+		lastLevelQty: levelQty,
+	}
+	p.orders[order.ID] = order
+
+	p.engineOnce.Do(func() { go p.runFillEngine() })
+
+	log.Printf("[PAPER %s] Limit %s %s %s %.8f @ %.8f - queue ahead: %.8f",
+		p.exchange, side, market, pairName, quantity, price, levelQty)
+
+	return order.ID, nil
+}
+
+// GetLimitOrder returns the simulated fill state of a limit order
+func (p *PaperClient) GetLimitOrder(ctx context.Context, pairName string, market string, orderID string) (*common.TradeResult, bool, error) {
+	if err := p.simulatedOutage(); err != nil {
+		return nil, false, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	order, ok := p.orders[orderID]
+	if !ok {
+		return nil, false, fmt.Errorf("order %s: %w", orderID, common.ErrPositionNotFound)
+	}
+
+	return &common.TradeResult{
+		OrderID:       order.ID,
+		ExecutedPrice: order.Price,
+		ExecutedQty:   order.Filled,
+		Fee:           order.Fee,
+		Success:       order.Status == "filled",
+	}, order.Status != "open", nil
+}
+
+// CancelLimitOrder cancels a simulated limit order, keeping any partial fill
+func (p *PaperClient) CancelLimitOrder(ctx context.Context, pairName string, market string, orderID string) error {
+	if err := p.simulatedOutage(); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	order, ok := p.orders[orderID]
+	if !ok {
+		return fmt.Errorf("order %s: %w", orderID, common.ErrPositionNotFound)
+	}
+	if order.Status == "open" {
+		order.Status = "cancelled"
+	}
+	return nil
+}
+
+// PlaceIOCOrder fills a limit order against the book at price or better and cancels the rest at once
+func (p *PaperClient) PlaceIOCOrder(ctx context.Context, pairName string, market string, side string, price float64, quantity float64) (*common.TradeResult, error) {
+	if err := p.simulatedOutage(); err != nil {
+		return nil, err
+	}
+
+	book, err := p.getBook(pairName, market == "spot")
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	isBuy := side == "buy"
+	filled, avgPrice := simulateLimitFill(book, isBuy, p.partialFill(quantity), price)
+
+	fee := filled * avgPrice * p.takerFee
+	if market == "spot" && isBuy && filled*avgPrice+fee > p.spotUSDT {
+		return nil, fmt.Errorf("paper spot balance %.2f < %.2f: %w", p.spotUSDT, filled*avgPrice+fee, common.ErrInsufficientBalance)
+	}
+
+	orderID := p.newOrderID()
+	if common.IsPositive(filled) {
+		p.applyFill(pairName, market, side, filled, avgPrice, fee)
+	}
+
+	log.Printf("[PAPER %s] IOC %s %s %s filled %.8f / %.8f @ %.8f (limit %.8f), fee: %.6f",
+		p.exchange, side, market, pairName, filled, quantity, avgPrice, price, fee)
+
+	return &common.TradeResult{
+		OrderID:       orderID,
+		ExecutedPrice: avgPrice,
+		ExecutedQty:   filled,
+		Fee:           fee,
+		Success:       common.Equal(filled, quantity),
+	}, nil
+}
+
+// GetUSDTBalance returns the simulated USDT balance of a market
+func (p *PaperClient) GetUSDTBalance(ctx context.Context, market string) (float64, error) {
+	if err := p.simulatedOutage(); err != nil {
+		return 0, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if market == "spot" {
+		return p.spotUSDT, nil
+	}
+	return p.futuresUSDT, nil
+}
+
+// runFillEngine evaluates all open limit orders against the live books
+func (p *PaperClient) runFillEngine() {
+	ticker := time.NewTicker(evaluateInterval)
+	defer ticker.Stop()
+
+	tradeShare := config.GetFloat("PAPER_QUEUE_TRADE_SHARE", 0.5)
+
+	for range ticker.C {
+		p.mu.Lock()
+		for _, order := range p.orders {
+			if order.Status != "open" {
+				continue
+			}
+			p.evaluateOrder(order, tradeShare)
+		}
+		p.mu.Unlock()
+	}
+}
+
+// evaluateOrder advances one order's queue position and fills. Callers must hold p.mu.
+func (p *PaperClient) evaluateOrder(order *LimitOrder, tradeShare float64) {
+	book, err := p.getBook(order.PairName, order.Market == "spot")
+	if err != nil {
+		return
+	}
+
+	isBuy := order.Side == "buy"
+	remaining := order.Quantity - order.Filled
+	levelQty := book.LevelVolume(isBuy, order.Price) / order.Price
+
+	fill := 0.0
+
+	bestBid, _, bidOk := book.GetBestBid()
+	bestAsk, _, askOk := book.GetBestAsk()
+
+	crossed := (isBuy && askOk && common.LessThanOrEqual(bestAsk, order.Price)) ||
+		(!isBuy && bidOk && common.GreaterThanOrEqual(bestBid, order.Price))
+	tradedThrough := common.IsZero(levelQty) && common.IsPositive(order.lastLevelQty) &&
+		((isBuy && bidOk && common.LessThan(bestBid, order.Price)) ||
+			(!isBuy && askOk && common.GreaterThan(bestAsk, order.Price)))
+
+	if crossed || tradedThrough {
+		fill = remaining
+	} else if decrease := order.lastLevelQty - levelQty; common.IsPositive(decrease) {
+		trades := decrease * tradeShare
+		cancels := decrease - trades
+
+		if common.IsPositive(order.lastLevelQty) {
+			order.queueAhead -= cancels * (order.queueAhead / order.lastLevelQty)
+		}
+
+		if trades <= order.queueAhead {
+			order.queueAhead -= trades
+		} else {
+			fill = trades - order.queueAhead
+			order.queueAhead = 0
+		}
+	}
+	order.lastLevelQty = levelQty
+
+	if fill > remaining {
+		fill = remaining
+	}
+	if common.IsNegativeOrZero(fill) {
+		return
+	}
+
+	fee := fill * order.Price * p.makerFee
+	p.applyFill(order.PairName, order.Market, order.Side, fill, order.Price, fee)
+	order.Filled += fill
+	order.Fee += fee
+
+	if common.GreaterThanOrEqual(order.Filled, order.Quantity) {
+		order.Status = "filled"
+	}
+
+	log.Printf("[PAPER %s] Limit %s filled %s / %s @ %.8f (crossed: %v, traded through: %v)",
+		p.exchange, order.ID,
+		strconv.FormatFloat(order.Filled, 'f', -1, 64), strconv.FormatFloat(order.Quantity, 'f', -1, 64),
+		order.Price, crossed, tradedThrough)
+}
@@ -0,0 +1,169 @@
+package paper
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+
+	"arbitrage.trade/clients/common"
+)
+
+// GetFuturesPosition returns the simulated signed futures position for the pair
+func (p *PaperClient) GetFuturesPosition(ctx context.Context, pairName string) (float64, error) {
+	if err := p.simulatedOutage(); err != nil {
+		return 0, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if short, ok := p.shorts[pairName]; ok {
+		return -short.Quantity, nil
+	}
+	if long, ok := p.longs[pairName]; ok {
+		return long.Quantity, nil
+	}
+	return 0, nil
+}
+
+func (p *PaperClient) PutFuturesShort(ctx context.Context, pairName string, amountUSDT float64) (*common.TradeResult, error) {
+	if err := p.simulatedOutage(); err != nil {
+		return nil, err
+	}
+
+	book, err := p.getBook(pairName, false)
+	if err != nil {
+		return nil, err
+	}
+
+	bestBid, _, ok := book.GetBestBid()
+	if !ok {
+		return nil, fmt.Errorf("no bid liquidity for %s on %s: %w", pairName, p.exchange, common.ErrOrderFailed)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	common.SetBalance(p.GetName(), "futures", "USDT", p.futuresUSDT)
+
+	quantity := common.QuantityForNotional(amountUSDT, bestBid, pairName)
+	if common.IsNegativeOrZero(quantity) {
+		return nil, fmt.Errorf("calculated futures quantity is zero")
+	}
+
+	filled, avgPrice := simulateFill(book, false, p.partialFill(quantity))
+	if common.IsZero(filled) {
+		return nil, fmt.Errorf("no bid liquidity for %s on %s: %w", pairName, p.exchange, common.ErrOrderFailed)
+	}
+
+	fee := filled * avgPrice * p.takerFee
+	p.applyFill(pairName, "futures", "sell", filled, avgPrice, fee)
+
+	orderID := p.newOrderID()
+	p.positions[pairName+"_futures"] = &common.Position{
+		PairName:     pairName,
+		Side:         "short",
+		Market:       "futures",
+		EntryPrice:   avgPrice,
+		Quantity:     filled,
+		AmountUSDT:   filled * avgPrice,
+		OrderID:      orderID,
+		ExchangeName: p.GetName(),
+	}
+
+	log.Printf("[PAPER %s] PutFuturesShort %s - qty: %.8f @ %.8f, fee: %.6f", p.exchange, pairName, filled, avgPrice, fee)
+
+	return &common.TradeResult{
+		OrderID:       orderID,
+		ExecutedPrice: avgPrice,
+		ExecutedQty:   filled,
+		Fee:           fee,
+		Success:       common.Equal(filled, quantity),
+	}, nil
+}
+
+func (p *PaperClient) CloseFuturesShort(ctx context.Context, pairName string) (*common.TradeResult, float64, error) {
+	if err := p.simulatedOutage(); err != nil {
+		return nil, 0.00, err
+	}
+
+	book, err := p.getBook(pairName, false)
+	if err != nil {
+		return nil, 0.00, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	short, ok := p.shorts[pairName]
+	if !ok || common.IsNegativeOrZero(short.Quantity) {
+		delete(p.positions, pairName+"_futures")
+		return nil, 0.00, fmt.Errorf("no open position on exchange")
+	}
+
+	quantity, avgPrice := simulateFill(book, true, p.partialFill(short.Quantity))
+	if common.IsZero(quantity) {
+		return nil, 0.00, fmt.Errorf("no ask liquidity for %s on %s: %w", pairName, p.exchange, common.ErrOrderFailed)
+	}
+
+	fee := quantity * avgPrice * p.takerFee
+	p.applyFill(pairName, "futures", "buy", quantity, avgPrice, fee)
+	delete(p.positions, pairName+"_futures")
+
+	prevBalance := common.GetBalance(p.GetName(), "futures", "USDT")
+	common.SetBalance(p.GetName(), "futures", "USDT", p.futuresUSDT)
+
+	log.Printf("[PAPER %s] CloseFuturesShort %s - qty: %.8f @ %.8f, fee: %.6f", p.exchange, pairName, quantity, avgPrice, fee)
+
+	_, stillOpen := p.shorts[pairName]
+	return &common.TradeResult{
+		OrderID:       p.newOrderID(),
+		ExecutedPrice: avgPrice,
+		ExecutedQty:   quantity,
+		Fee:           fee,
+		Success:       !stillOpen,
+	}, p.futuresUSDT - prevBalance, nil
+}
+
+// ReduceFuturesShort buys back quantity of the simulated short against the asks
+func (p *PaperClient) ReduceFuturesShort(ctx context.Context, pairName string, quantity float64) (*common.TradeResult, error) {
+	if err := p.simulatedOutage(); err != nil {
+		return nil, err
+	}
+
+	book, err := p.getBook(pairName, false)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	short, ok := p.shorts[pairName]
+	if !ok || common.IsNegativeOrZero(short.Quantity) {
+		return nil, fmt.Errorf("no open position on exchange")
+	}
+	quantity = common.RoundQuantity(math.Min(quantity, short.Quantity), pairName)
+	if common.IsNegativeOrZero(quantity) {
+		return nil, fmt.Errorf("invalid reduce quantity: %.8f", quantity)
+	}
+
+	filled, avgPrice := simulateFill(book, true, p.partialFill(quantity))
+	if common.IsZero(filled) {
+		return nil, fmt.Errorf("no ask liquidity for %s on %s: %w", pairName, p.exchange, common.ErrOrderFailed)
+	}
+
+	fee := filled * avgPrice * p.takerFee
+	p.applyFill(pairName, "futures", "buy", filled, avgPrice, fee)
+
+	log.Printf("[PAPER %s] ReduceFuturesShort %s - qty: %.8f @ %.8f, fee: %.6f", p.exchange, pairName, filled, avgPrice, fee)
+
+	return &common.TradeResult{
+		OrderID:       p.newOrderID(),
+		ExecutedPrice: avgPrice,
+		ExecutedQty:   filled,
+		Fee:           fee,
+		Success:       common.Equal(filled, quantity),
+	}, nil
+}
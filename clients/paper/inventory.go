@@ -0,0 +1,152 @@
+package paper
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"arbitrage.trade/clients/common"
+)
+
+// SellSpot sells quantity of the simulated base holding against the bids
+func (p *PaperClient) SellSpot(ctx context.Context, pairName string, quantity float64) (*common.TradeResult, error) {
+	return p.tradeSpot(pairName, "sell", quantity)
+}
+
+// BuySpot buys quantity of the base asset against the asks
+func (p *PaperClient) BuySpot(ctx context.Context, pairName string, quantity float64) (*common.TradeResult, error) {
+	return p.tradeSpot(pairName, "buy", quantity)
+}
+
+// ReduceSpotLong sells quantity of the simulated spot long against the bids
+func (p *PaperClient) ReduceSpotLong(ctx context.Context, pairName string, quantity float64) (*common.TradeResult, error) {
+	return p.tradeSpot(pairName, "sell", quantity)
+}
+
+func (p *PaperClient) tradeSpot(pairName, side string, quantity float64) (*common.TradeResult, error) {
+	if err := p.simulatedOutage(); err != nil {
+		return nil, err
+	}
+
+	book, err := p.getBook(pairName, true)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	quantity = common.RoundQuantity(quantity, pairName)
+	if common.IsNegativeOrZero(quantity) {
+		return nil, fmt.Errorf("invalid spot quantity: %.8f", quantity)
+	}
+	if side == "sell" && quantity > p.holdings[pairName] {
+		return nil, fmt.Errorf("paper %s holding %.8f < %.8f: %w", pairName, p.holdings[pairName], quantity, common.ErrInsufficientBalance)
+	}
+
+	filled, avgPrice := simulateFill(book, side == "buy", p.partialFill(quantity))
+	if common.IsZero(filled) {
+		return nil, fmt.Errorf("no liquidity to %s %s on %s: %w", side, pairName, p.exchange, common.ErrOrderFailed)
+	}
+
+	fee := filled * avgPrice * p.takerFee
+	if side == "buy" && filled*avgPrice+fee > p.spotUSDT {
+		return nil, fmt.Errorf("paper spot balance %.2f < %.2f: %w", p.spotUSDT, filled*avgPrice+fee, common.ErrInsufficientBalance)
+	}
+	p.applyFill(pairName, "spot", side, filled, avgPrice, fee)
+
+	log.Printf("[PAPER %s] %s spot %s - qty: %.8f @ %.8f, fee: %.6f", p.exchange, side, pairName, filled, avgPrice, fee)
+
+	return &common.TradeResult{
+		OrderID:       p.newOrderID(),
+		ExecutedPrice: avgPrice,
+		ExecutedQty:   filled,
+		Fee:           fee,
+		Success:       common.Equal(filled, quantity),
+	}, nil
+}
+
+func (p *PaperClient) PutFuturesLong(ctx context.Context, pairName string, amountUSDT float64) (*common.TradeResult, error) {
+	if err := p.simulatedOutage(); err != nil {
+		return nil, err
+	}
+
+	book, err := p.getBook(pairName, false)
+	if err != nil {
+		return nil, err
+	}
+
+	bestAsk, _, ok := book.GetBestAsk()
+	if !ok {
+		return nil, fmt.Errorf("no ask liquidity for %s on %s: %w", pairName, p.exchange, common.ErrOrderFailed)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	common.SetBalance(p.GetName(), "futures", "USDT", p.futuresUSDT)
+
+	quantity := common.QuantityForNotional(amountUSDT, bestAsk, pairName)
+	if common.IsNegativeOrZero(quantity) {
+		return nil, fmt.Errorf("calculated futures quantity is zero")
+	}
+
+	filled, avgPrice := simulateFill(book, true, p.partialFill(quantity))
+	if common.IsZero(filled) {
+		return nil, fmt.Errorf("no ask liquidity for %s on %s: %w", pairName, p.exchange, common.ErrOrderFailed)
+	}
+
+	fee := filled * avgPrice * p.takerFee
+	p.applyFill(pairName, "futures", "buy", filled, avgPrice, fee)
+
+	log.Printf("[PAPER %s] PutFuturesLong %s - qty: %.8f @ %.8f, fee: %.6f", p.exchange, pairName, filled, avgPrice, fee)
+
+	return &common.TradeResult{
+		OrderID:       p.newOrderID(),
+		ExecutedPrice: avgPrice,
+		ExecutedQty:   filled,
+		Fee:           fee,
+		Success:       common.Equal(filled, quantity),
+	}, nil
+}
+
+func (p *PaperClient) CloseFuturesLong(ctx context.Context, pairName string) (*common.TradeResult, float64, error) {
+	if err := p.simulatedOutage(); err != nil {
+		return nil, 0.00, err
+	}
+
+	book, err := p.getBook(pairName, false)
+	if err != nil {
+		return nil, 0.00, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	long, ok := p.longs[pairName]
+	if !ok || common.IsNegativeOrZero(long.Quantity) {
+		return nil, 0.00, fmt.Errorf("no open long position on exchange")
+	}
+
+	quantity, avgPrice := simulateFill(book, false, p.partialFill(long.Quantity))
+	if common.IsZero(quantity) {
+		return nil, 0.00, fmt.Errorf("no bid liquidity for %s on %s: %w", pairName, p.exchange, common.ErrOrderFailed)
+	}
+
+	fee := quantity * avgPrice * p.takerFee
+	p.applyFill(pairName, "futures", "sell", quantity, avgPrice, fee)
+
+	prevBalance := common.GetBalance(p.GetName(), "futures", "USDT")
+	common.SetBalance(p.GetName(), "futures", "USDT", p.futuresUSDT)
+
+	log.Printf("[PAPER %s] CloseFuturesLong %s - qty: %.8f @ %.8f, fee: %.6f", p.exchange, pairName, quantity, avgPrice, fee)
+
+	_, stillOpen := p.longs[pairName]
+	return &common.TradeResult{
+		OrderID:       p.newOrderID(),
+		ExecutedPrice: avgPrice,
+		ExecutedQty:   quantity,
+		Fee:           fee,
+		Success:       !stillOpen,
+	}, p.futuresUSDT - prevBalance, nil
+}
@@ -0,0 +1,34 @@
+package paper
+
+import (
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/config"
+)
+
+var bookSource BookSource
+
+// SetBookSource sets the orderbook source used by all paper clients
+func SetBookSource(source BookSource) {
+	bookSource = source
+}
+
+// NewPaperClient creates a simulated account for the named exchange
+// Starting balances and fees come from PAPER_START_BALANCE, PAPER_TAKER_FEE and PAPER_MAKER_FEE
+func NewPaperClient(exchange string) *PaperClient {
+	startBalance := config.GetFloat("PAPER_START_BALANCE", 1000)
+
+	return &PaperClient{
+		exchange:    exchange,
+		takerFee:    config.GetFloat("PAPER_TAKER_FEE", 0.001),
+		makerFee:    config.GetFloat("PAPER_MAKER_FEE", 0.0002),
+		spotUSDT:    startBalance,
+		futuresUSDT: startBalance,
+		holdings:    make(map[string]float64),
+		shorts:      make(map[string]*futuresPosition),
+		longs:       make(map[string]*futuresPosition),
+		orders:      make(map[string]*LimitOrder),
+		positions:   make(map[string]*common.Position),
+	}
+}
+
+func (p *PaperClient) GetName() string { return p.exchange }
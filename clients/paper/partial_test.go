@@ -0,0 +1,83 @@
+package paper
+
+import (
+	"context"
+	"testing"
+
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/orderbook"
+)
+
+// testBooks serves one fixed spot and perp book per pair, whatever the venue
+type testBooks struct {
+	spot, perp *orderbook.OrderBook
+}
+
+func (b testBooks) GetBook(pairName, exchangeName string, isSpot bool) (*orderbook.OrderBook, bool) {
+	if isSpot {
+		return b.spot, true
+	}
+	return b.perp, true
+}
+
+// book returns a one-level book, volumes in USDT like the signal feed
+func book(bid, ask float64) *orderbook.OrderBook {
+	ob := orderbook.NewOrderBook()
+	ob.Replace(map[float64]float64{bid: 1e6}, map[float64]float64{ask: 1e6}, 0, 0)
+	return ob
+}
+
+// TestPartialCloseKeepsTotalProfit checks a position's realized P&L, the final closes' balance diffs,
+// is the same whether or not part of it was reduced before the close
+func TestPartialCloseKeepsTotalProfit(t *testing.T) {
+	t.Setenv("PAPER_TAKER_FEE", "0.001")
+	const pair = "xrp-usdt"
+	ctx := context.Background()
+
+	// Opens at 0.5 on both legs and closes at 0.55 spot / 0.45 perp, so both legs gain 0.05 per XRP
+	run := func(exchange string, reduce bool) (spotProfit, futuresProfit float64) {
+		t.Helper()
+		client := NewPaperClient(exchange)
+
+		SetBookSource(testBooks{spot: book(0.49, 0.5), perp: book(0.5, 0.51)})
+		if _, err := client.PutSpotLong(ctx, pair, 20.02); err != nil {
+			t.Fatalf("%s PutSpotLong: %v", exchange, err)
+		}
+		if _, err := client.PutFuturesShort(ctx, pair, 20); err != nil {
+			t.Fatalf("%s PutFuturesShort: %v", exchange, err)
+		}
+
+		SetBookSource(testBooks{spot: book(0.55, 0.56), perp: book(0.44, 0.45)})
+		if reduce {
+			if _, err := client.ReduceSpotLong(ctx, pair, 20); err != nil {
+				t.Fatalf("%s ReduceSpotLong: %v", exchange, err)
+			}
+			if _, err := client.ReduceFuturesShort(ctx, pair, 20); err != nil {
+				t.Fatalf("%s ReduceFuturesShort: %v", exchange, err)
+			}
+		}
+
+		_, spotProfit, err := client.CloseSpotLong(ctx, pair, 20)
+		if err != nil {
+			t.Fatalf("%s CloseSpotLong: %v", exchange, err)
+		}
+		_, futuresProfit, err = client.CloseFuturesShort(ctx, pair)
+		if err != nil {
+			t.Fatalf("%s CloseFuturesShort: %v", exchange, err)
+		}
+		return spotProfit, futuresProfit
+	}
+	defer SetBookSource(nil)
+
+	wholeSpot, wholeFutures := run("paper-whole", false)
+	partialSpot, partialFutures := run("paper-partial", true)
+
+	// 40 XRP each way: spot 40×0.55 − 0.022 fee − 20.02 spent, perp 40×0.05 − 0.02 − 0.018 fees
+	if common.NotEqual(wholeSpot, 1.958) || common.NotEqual(wholeFutures, 1.962) {
+		t.Errorf("whole close profit = spot %v, futures %v, want 1.958, 1.962", wholeSpot, wholeFutures)
+	}
+	if common.NotEqual(partialSpot, wholeSpot) || common.NotEqual(partialFutures, wholeFutures) {
+		t.Errorf("profit with a partial close = spot %v, futures %v, want spot %v, futures %v as without",
+			partialSpot, partialFutures, wholeSpot, wholeFutures)
+	}
+}
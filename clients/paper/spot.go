@@ -0,0 +1,115 @@
+package paper
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"arbitrage.trade/clients/common"
+)
+
+// GetSpotHolding returns the simulated base-asset holding for the pair
+func (p *PaperClient) GetSpotHolding(ctx context.Context, pairName string) (float64, error) {
+	if err := p.simulatedOutage(); err != nil {
+		return 0, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.holdings[pairName], nil
+}
+
+func (p *PaperClient) PutSpotLong(ctx context.Context, pairName string, amountUSDT float64) (*common.TradeResult, error) {
+	if err := p.simulatedOutage(); err != nil {
+		return nil, err
+	}
+
+	book, err := p.getBook(pairName, true)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	common.SetBalance(p.GetName(), "spot", "USDT", p.spotUSDT)
+
+	if amountUSDT > p.spotUSDT {
+		return nil, fmt.Errorf("paper spot balance %.2f < %.2f: %w", p.spotUSDT, amountUSDT, common.ErrInsufficientBalance)
+	}
+
+	// Fee is charged in USDT on top of the traded notional
+	spend := p.partialFill(amountUSDT)
+	quantity, avgPrice := simulateBuyQuote(book, spend/(1+p.takerFee))
+	if common.IsZero(quantity) {
+		return nil, fmt.Errorf("no ask liquidity for %s on %s: %w", pairName, p.exchange, common.ErrOrderFailed)
+	}
+
+	fee := quantity * avgPrice * p.takerFee
+	p.applyFill(pairName, "spot", "buy", quantity, avgPrice, fee)
+
+	orderID := p.newOrderID()
+	p.positions[pairName+"_spot"] = &common.Position{
+		PairName:     pairName,
+		Side:         "long",
+		Market:       "spot",
+		EntryPrice:   avgPrice,
+		Quantity:     quantity,
+		AmountUSDT:   quantity*avgPrice + fee,
+		OrderID:      orderID,
+		ExchangeName: p.GetName(),
+	}
+
+	log.Printf("[PAPER %s] PutSpotLong %s - qty: %.8f @ %.8f, fee: %.6f", p.exchange, pairName, quantity, avgPrice, fee)
+
+	return &common.TradeResult{
+		OrderID:       orderID,
+		ExecutedPrice: avgPrice,
+		ExecutedQty:   quantity,
+		Fee:           fee,
+		Success:       common.Equal(spend, amountUSDT),
+	}, nil
+}
+
+func (p *PaperClient) CloseSpotLong(ctx context.Context, pairName string, amountUSDT float64) (*common.TradeResult, float64, error) {
+	if err := p.simulatedOutage(); err != nil {
+		return nil, 0.00, err
+	}
+
+	book, err := p.getBook(pairName, true)
+	if err != nil {
+		return nil, 0.00, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	holding := common.RoundQuantity(p.holdings[pairName], pairName)
+	if common.IsNegativeOrZero(holding) {
+		delete(p.positions, pairName+"_spot")
+		return nil, 0.00, fmt.Errorf("no balance on exchange for %s", pairName)
+	}
+
+	quantity, avgPrice := simulateFill(book, false, p.partialFill(holding))
+	if common.IsZero(quantity) {
+		return nil, 0.00, fmt.Errorf("no bid liquidity for %s on %s: %w", pairName, p.exchange, common.ErrOrderFailed)
+	}
+
+	fee := quantity * avgPrice * p.takerFee
+	p.applyFill(pairName, "spot", "sell", quantity, avgPrice, fee)
+	delete(p.positions, pairName+"_spot")
+
+	prevBalance := common.GetBalance(p.GetName(), "spot", "USDT")
+	common.SetBalance(p.GetName(), "spot", "USDT", p.spotUSDT)
+
+	log.Printf("[PAPER %s] CloseSpotLong %s - qty: %.8f @ %.8f, fee: %.6f", p.exchange, pairName, quantity, avgPrice, fee)
+
+	return &common.TradeResult{
+		OrderID:       p.newOrderID(),
+		ExecutedPrice: avgPrice,
+		ExecutedQty:   quantity,
+		Fee:           fee,
+		Success:       common.Equal(quantity, holding),
+	}, p.spotUSDT - prevBalance, nil
+}
@@ -0,0 +1,55 @@
+package paper
+
+import (
+	"sync"
+	"time"
+
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/orderbook"
+)
+
+// BookSource provides the live orderbooks paper fills are simulated against
+type BookSource interface {
+	GetBook(pairName, exchangeName string, isSpot bool) (*orderbook.OrderBook, bool)
+}
+
+// PaperClient simulates an exchange account against live orderbook data
+type PaperClient struct {
+	exchange string
+	takerFee float64
+	makerFee float64
+
+	mu          sync.Mutex
+	spotUSDT    float64
+	futuresUSDT float64
+	holdings    map[string]float64          // pair -> base quantity held on spot
+	shorts      map[string]*futuresPosition // pair -> open futures short
+	longs       map[string]*futuresPosition // pair -> open futures long (inventory strategy)
+	orders      map[string]*LimitOrder      // order ID -> resting limit order
+	nextOrderID int64
+	engineOnce  sync.Once
+
+	positions map[string]*common.Position
+}
+
+type futuresPosition struct {
+	Quantity   float64
+	EntryPrice float64
+}
+
+// LimitOrder is a simulated resting order with a modeled queue position
+type LimitOrder struct {
+	ID        string
+	PairName  string
+	Market    string // "spot" or "futures"
+	Side      string // "buy" or "sell"
+	Price     float64
+	Quantity  float64
+	Filled    float64
+	Fee       float64
+	Status    string // "open", "filled", "cancelled"
+	CreatedAt time.Time
+
+	queueAhead   float64 // base quantity resting ahead of us at our price
+	lastLevelQty float64 // base quantity seen at our price on the previous evaluation
+}
@@ -0,0 +1,157 @@
+package paper
+
+import (
+	"fmt"
+
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/orderbook"
+	"arbitrage.trade/outage"
+)
+
+func (p *PaperClient) getBook(pairName string, isSpot bool) (*orderbook.OrderBook, error) {
+	if bookSource == nil {
+		return nil, fmt.Errorf("paper book source not set: %w", common.ErrConnectionFailed)
+	}
+
+	book, ok := bookSource.GetBook(pairName, p.exchange, isSpot)
+	if !ok {
+		market := "spot"
+		if !isSpot {
+			market = "perp"
+		}
+		return nil, fmt.Errorf("no %s book for %s on %s: %w", market, pairName, p.exchange, common.ErrConnectionFailed)
+	}
+	return book, nil
+}
+
+// simulatedOutage returns the error a REST call fails with while a 5xx outage is simulated on the venue
+func (p *PaperClient) simulatedOutage() error {
+	if outage.Active(p.exchange, outage.REST5xx) {
+		return fmt.Errorf("simulated %s outage: 503 Service Unavailable: %w", p.exchange, common.ErrConnectionFailed)
+	}
+	return nil
+}
+
+// partialFill cuts an order's size while partial fills are simulated on the venue
+func (p *PaperClient) partialFill(size float64) float64 {
+	if outage.Active(p.exchange, outage.PartialFills) {
+		return size * outage.FillRatio()
+	}
+	return size
+}
+
+// simulateBuyQuote walks the asks spending up to amountUSDT and returns the base quantity and VWAP
+// Book volumes are in USDT (quantity × price), matching the signal feed
+func simulateBuyQuote(book *orderbook.OrderBook, amountUSDT float64) (float64, float64) {
+	_, asks, _ := book.GetSnapshot()
+
+	remaining := amountUSDT
+	quantity := 0.0
+	spent := 0.0
+	for _, level := range asks {
+		if common.IsNegativeOrZero(remaining) {
+			break
+		}
+		take := level.Quantity
+		if take > remaining {
+			take = remaining
+		}
+		quantity += take / level.Price
+		spent += take
+		remaining -= take
+	}
+
+	if common.IsZero(quantity) {
+		return 0, 0
+	}
+	return quantity, spent / quantity
+}
+
+// simulateFill walks one side of the book for a base quantity and returns the filled quantity and VWAP
+func simulateFill(book *orderbook.OrderBook, isBuy bool, quantity float64) (float64, float64) {
+	return simulateLimitFill(book, isBuy, quantity, 0)
+}
+
+// simulateLimitFill walks one side of the book like simulateFill, stopping at levels worse than limit
+// A zero limit takes every level
+func simulateLimitFill(book *orderbook.OrderBook, isBuy bool, quantity, limit float64) (float64, float64) {
+	bids, asks, _ := book.GetSnapshot()
+	levels := bids
+	if isBuy {
+		levels = asks
+	}
+
+	remaining := quantity
+	filled := 0.0
+	notional := 0.0
+	for _, level := range levels {
+		if common.IsNegativeOrZero(remaining) {
+			break
+		}
+		if common.IsPositive(limit) && ((isBuy && common.GreaterThan(level.Price, limit)) || (!isBuy && common.LessThan(level.Price, limit))) {
+			break
+		}
+		levelQty := level.Quantity / level.Price
+		take := levelQty
+		if take > remaining {
+			take = remaining
+		}
+		filled += take
+		notional += take * level.Price
+		remaining -= take
+	}
+
+	if common.IsZero(filled) {
+		return 0, 0
+	}
+	return filled, notional / filled
+}
+
+// applyFill books a fill against the simulated balances. Callers must hold p.mu.
+func (p *PaperClient) applyFill(pairName, market, side string, quantity, price, fee float64) {
+	notional := quantity * price
+
+	switch {
+	case market == "spot" && side == "buy":
+		p.spotUSDT -= notional + fee
+		p.holdings[pairName] += quantity
+	case market == "spot" && side == "sell":
+		p.spotUSDT += notional - fee
+		p.holdings[pairName] -= quantity
+	case market == "futures" && side == "sell":
+		if long, ok := p.longs[pairName]; ok {
+			p.futuresUSDT += (price-long.EntryPrice)*quantity - fee
+			long.Quantity -= quantity
+			if common.IsNegativeOrZero(long.Quantity) {
+				delete(p.longs, pairName)
+			}
+			return
+		}
+		addFuturesPosition(p.shorts, pairName, quantity, price)
+		p.futuresUSDT -= fee
+	case market == "futures" && side == "buy":
+		short, ok := p.shorts[pairName]
+		if !ok {
+			addFuturesPosition(p.longs, pairName, quantity, price)
+			p.futuresUSDT -= fee
+			return
+		}
+		p.futuresUSDT += (short.EntryPrice-price)*quantity - fee
+		short.Quantity -= quantity
+		if common.IsNegativeOrZero(short.Quantity) {
+			delete(p.shorts, pairName)
+		}
+	}
+}
+
+// addFuturesPosition adds a fill to a position, averaging its entry price
+func addFuturesPosition(positions map[string]*futuresPosition, pairName string, quantity, price float64) {
+	position, ok := positions[pairName]
+	if !ok {
+		position = &futuresPosition{}
+		positions[pairName] = position
+	}
+	total := position.Quantity + quantity
+	position.EntryPrice = (position.EntryPrice*position.Quantity + price*quantity) / total
+	position.Quantity = total
+}
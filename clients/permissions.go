@@ -0,0 +1,66 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"arbitrage.trade/clients/common"
+)
+
+// API key permission check
+//
+// Before trading starts each venue's key-info endpoint is queried and the key's read, spot trade and
+// futures trade scopes verified, so a key missing a scope fails at startup instead of mid-trade with
+// one leg filled. Keys that can withdraw or aren't IP-restricted are only warned about. Venues whose
+// client can't look up its key (or whose endpoint doesn't report a scope) are logged as unverified.
+// Disabled with API_PERMISSION_CHECK=false.
+
+// CheckAPIPermissions verifies every venue's API key has the scopes trading needs
+// Returns one error listing each venue's missing scopes or lookup failure
+func CheckAPIPermissions(ctx context.Context, exchanges []string) error {
+	if IsPaperTrading() {
+		return nil
+	}
+
+	var problems []string
+	for _, exchange := range exchanges {
+		client, err := getOrCreateClient(common.ExchangeType(exchange))
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", exchange, err))
+			continue
+		}
+		permissionClient, ok := client.(common.PermissionClient)
+		if !ok {
+			log.Printf("[PERMISSIONS] %s - key permissions can't be verified, skipping", exchange)
+			continue
+		}
+
+		perms, err := permissionClient.GetAPIPermissions(ctx)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: key lookup failed: %v", exchange, err))
+			continue
+		}
+
+		if missing := perms.Missing(); len(missing) > 0 {
+			problems = append(problems, fmt.Sprintf("%s: missing %s", exchange, strings.Join(missing, ", ")))
+			continue
+		}
+		if len(perms.Unverified) > 0 {
+			log.Printf("[PERMISSIONS] %s - %s not reported by the exchange, unverified", exchange, strings.Join(perms.Unverified, ", "))
+		}
+		if perms.Withdraw {
+			log.Printf("[PERMISSIONS] %s - WARNING: key can withdraw, trading only needs read and trade", exchange)
+		}
+		if !perms.IPRestricted {
+			log.Printf("[PERMISSIONS] %s - WARNING: key is not restricted to whitelisted IPs", exchange)
+		}
+		log.Printf("[PERMISSIONS] %s - OK", exchange)
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("API key permissions insufficient:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/config"
+	"arbitrage.trade/logging"
+)
+
+// Execution playbooks
+//
+// How a trade's legs are best executed depends on the venue combination: a venue that fills
+// unreliably goes second, a thin book is worked as maker, a venue with doubtful fill reports gets
+// its holdings checked. EXEC_PLAYBOOK_<NAME> defines a playbook as comma separated fields:
+//
+//	order=parallel|spot_first|perp_first  legs sent together (default), or one side first and the
+//	                                      other sized to what it filled, and not sent if it failed
+//	spot=<mode>, perp=<mode>              the leg's execution mode (taker, maker, taker_above, auto),
+//	                                      overriding EXEC_MODE_* for the combination
+//	preflight=true                        re-check the books' spread and the venues for existing
+//	                                      exposure right before submitting
+//	verify=reported|strict                trust the venues' fill reports (default), or confirm the
+//	                                      holdings on the venues after opening and unwind otherwise
+//
+// EXEC_PLAYBOOKS assigns playbooks to "<spot venue>:<perp venue>=<name>" combinations, comma
+// separated, either venue "*"; the most specific entry wins, the perp venue weighing most:
+//
+//	EXEC_PLAYBOOK_CAREFUL=order=perp_first,spot=maker,verify=strict
+//	EXEC_PLAYBOOKS=*:whitebit=careful
+//
+// Combinations without a playbook keep the default: parallel legs, EXEC_MODE_*, reported fills.
+
+// LegOrder is the sequence a trade's opening legs are sent in
+type LegOrder string
+
+const (
+	OrderParallel  LegOrder = "parallel"
+	OrderSpotFirst LegOrder = "spot_first"
+	OrderPerpFirst LegOrder = "perp_first"
+)
+
+// Post-trade verification strictness
+const (
+	VerifyReported = "reported" // The venues' fill reports are trusted
+	VerifyStrict   = "strict"   // Holdings are confirmed on the venues after opening
+)
+
+// Playbook is how the legs of a trade between a spot and a perp venue are executed
+type Playbook struct {
+	Name      string   `json:"name"`
+	Order     LegOrder `json:"order"`
+	SpotMode  ExecMode `json:"spot_mode,omitempty"` // "" keeps EXEC_MODE_*
+	PerpMode  ExecMode `json:"perp_mode,omitempty"`
+	Preflight bool     `json:"preflight"`
+	Verify    string   `json:"verify"`
+}
+
+// DefaultPlaybook is used for combinations EXEC_PLAYBOOKS doesn't assign
+func DefaultPlaybook() Playbook {
+	return Playbook{Name: "default", Order: OrderParallel, Verify: VerifyReported}
+}
+
+// legMode returns the playbook's execution mode for a market's leg, "" when it doesn't set one
+func (p Playbook) legMode(market string) ExecMode {
+	if legName(market) == "spot" {
+		return p.SpotMode
+	}
+	return p.PerpMode
+}
+
+// parsePlaybook parses an EXEC_PLAYBOOK_<NAME> definition
+func parsePlaybook(name, spec string) (Playbook, error) {
+	playbook := DefaultPlaybook()
+	playbook.Name = name
+
+	for _, field := range strings.Split(spec, ",") {
+		if strings.TrimSpace(field) == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return playbook, fmt.Errorf("missing =<value> in %q", field)
+		}
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.ToLower(strings.TrimSpace(value))
+
+		switch key {
+		case "order":
+			switch order := LegOrder(value); order {
+			case OrderParallel, OrderSpotFirst, OrderPerpFirst:
+				playbook.Order = order
+			default:
+				return playbook, fmt.Errorf("unknown order %q", value)
+			}
+		case "spot", "perp":
+			switch mode := ExecMode(value); mode {
+			case ExecTaker, ExecMaker, ExecTakerAbove, ExecAuto:
+				if key == "spot" {
+					playbook.SpotMode = mode
+				} else {
+					playbook.PerpMode = mode
+				}
+			default:
+				return playbook, fmt.Errorf("unknown %s mode %q", key, value)
+			}
+		case "preflight":
+			preflight, err := strconv.ParseBool(value)
+			if err != nil {
+				return playbook, fmt.Errorf("invalid preflight %q", value)
+			}
+			playbook.Preflight = preflight
+		case "verify":
+			if value != VerifyReported && value != VerifyStrict {
+				return playbook, fmt.Errorf("unknown verify %q", value)
+			}
+			playbook.Verify = value
+		default:
+			return playbook, fmt.Errorf("unknown field %q", key)
+		}
+	}
+	return playbook, nil
+}
+
+// PlaybookNamed returns the playbook EXEC_PLAYBOOK_<NAME> defines, "default" being the default one
+func PlaybookNamed(name string) (Playbook, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "default" {
+		return DefaultPlaybook(), nil
+	}
+	spec := config.GetString(config.Key("EXEC_PLAYBOOK", name), "")
+	if spec == "" {
+		return Playbook{}, fmt.Errorf("%s is not set", config.Key("EXEC_PLAYBOOK", name))
+	}
+	return parsePlaybook(name, spec)
+}
+
+// playbookRule is one EXEC_PLAYBOOKS entry, "" venues match any
+type playbookRule struct {
+	spot, perp string
+	playbook   Playbook
+}
+
+var (
+	playbooksMu  sync.Mutex
+	playbooksRaw string
+	playbookList []playbookRule
+)
+
+// playbookRules returns the parsed EXEC_PLAYBOOKS, logging malformed entries once
+func playbookRules() []playbookRule {
+	raw := config.GetString("EXEC_PLAYBOOKS", "")
+
+	playbooksMu.Lock()
+	defer playbooksMu.Unlock()
+
+	if raw == playbooksRaw {
+		return playbookList
+	}
+	playbooksRaw, playbookList = raw, nil
+	for _, entry := range strings.Split(raw, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		venues, name, ok := strings.Cut(entry, "=")
+		spot, perp, pairOk := strings.Cut(strings.ToLower(strings.TrimSpace(venues)), ":")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !ok || !pairOk || name == "" {
+			logging.Warnf("executor", "[PLAYBOOK] Ignoring EXEC_PLAYBOOKS entry %q: want <spot venue>:<perp venue>=<name>", entry)
+			continue
+		}
+
+		spec := config.GetString(config.Key("EXEC_PLAYBOOK", name), "")
+		if spec == "" {
+			logging.Warnf("executor", "[PLAYBOOK] Ignoring EXEC_PLAYBOOKS entry %q: %s is not set", entry, config.Key("EXEC_PLAYBOOK", name))
+			continue
+		}
+		playbook, err := parsePlaybook(name, spec)
+		if err != nil {
+			logging.Warnf("executor", "[PLAYBOOK] Ignoring playbook %s: %v", name, err)
+			continue
+		}
+
+		rule := playbookRule{spot: strings.TrimSpace(spot), perp: strings.TrimSpace(perp), playbook: playbook}
+		if rule.spot == "*" {
+			rule.spot = ""
+		}
+		if rule.perp == "*" {
+			rule.perp = ""
+		}
+		playbookList = append(playbookList, rule)
+	}
+	return playbookList
+}
+
+// PlaybookFor returns the playbook of a trade between the spot and perp venues
+func PlaybookFor(spotExchange, perpExchange common.ExchangeType) Playbook {
+	playbook, best := DefaultPlaybook(), -1
+	for _, rule := range playbookRules() {
+		if (rule.spot != "" && rule.spot != string(spotExchange)) || (rule.perp != "" && rule.perp != string(perpExchange)) {
+			continue
+		}
+		rank := 0
+		if rule.spot != "" {
+			rank++
+		}
+		if rule.perp != "" {
+			rank += 2
+		}
+		if rank >= best {
+			playbook, best = rule.playbook, rank
+		}
+	}
+	return playbook
+}
+
+type playbookKey struct{}
+
+// WithPlaybook makes the legs executed with ctx follow the playbook's execution modes
+func WithPlaybook(ctx context.Context, playbook Playbook) context.Context {
+	return context.WithValue(ctx, playbookKey{}, playbook)
+}
+
+// playbookFrom returns the playbook carried by ctx, if any
+func playbookFrom(ctx context.Context) (Playbook, bool) {
+	playbook, ok := ctx.Value(playbookKey{}).(Playbook)
+	return playbook, ok
+}
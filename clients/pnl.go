@@ -0,0 +1,22 @@
+package clients
+
+import (
+	"context"
+
+	"arbitrage.trade/clients/common"
+)
+
+// PerpPnL returns the pair's open perp position on an exchange as the venue values it
+// nil when the venue doesn't report position PnL (or trades on paper) or holds nothing in the pair
+func PerpPnL(ctx context.Context, exchange common.ExchangeType, pairName string) (*common.PositionPnL, error) {
+	client, err := getOrCreateClient(exchange)
+	if err != nil {
+		return nil, err
+	}
+
+	pnlClient, ok := client.(common.PositionPnLClient)
+	if !ok {
+		return nil, nil
+	}
+	return pnlClient.GetPerpPnL(ctx, pairName)
+}
@@ -0,0 +1,73 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/config"
+)
+
+// Entry presence guard
+//
+// With ENTRY_PRESENCE_GUARD (default true) every leg's venue is asked for exposure in the pair before
+// a position opens, so a crash that lost the tracked positions seconds after an entry cannot lead to
+// a second entry on top of the first. Orders are tagged with client order IDs starting with
+// CLIENT_ORDER_PREFIX (default "arb"); venues that expose them in their order history (binance, okx)
+// tell whether exposure was opened by this bot within ENTRY_PRESENCE_LOOKBACK (default 24h).
+// Any exposure blocks the entry, since closing the new position would also close what was there.
+
+// Exposure is a venue market already holding a position or balance in a pair
+type Exposure struct {
+	Exchange   common.ExchangeType
+	Market     string
+	Attributed bool // The venue's order history was checked for the bot's client order IDs
+	Bot        bool // Orders tagged by this bot were placed within the lookback
+}
+
+func (e *Exposure) String() string {
+	switch {
+	case e.Bot:
+		return fmt.Sprintf("%s %s holds an untracked position opened by this bot", e.Exchange, e.Market)
+	case e.Attributed:
+		return fmt.Sprintf("%s %s holds a position not opened by this bot", e.Exchange, e.Market)
+	}
+	return fmt.Sprintf("%s %s holds an untracked position", e.Exchange, e.Market)
+}
+
+// PresenceGuardEnabled reports whether entries check their venues for existing exposure (ENTRY_PRESENCE_GUARD)
+func PresenceGuardEnabled() bool {
+	return config.GetBool("ENTRY_PRESENCE_GUARD", true)
+}
+
+// ExistingExposure returns the venue market's exposure in the pair, nil when it is flat
+func ExistingExposure(ctx context.Context, exchange common.ExchangeType, market, pairName string) (*Exposure, error) {
+	flat, err := IsFlat(ctx, exchange, market, pairName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check %s %s for existing exposure: %w", exchange, market, err)
+	}
+	if flat {
+		return nil, nil
+	}
+
+	exposure := &Exposure{Exchange: exchange, Market: market}
+
+	client, err := getOrCreateClient(exchange)
+	if err != nil {
+		return exposure, nil
+	}
+	historyClient, ok := client.(common.OrderHistoryClient)
+	if !ok || market == "inverse" {
+		return exposure, nil
+	}
+
+	since := time.Now().Add(-config.GetDuration("ENTRY_PRESENCE_LOOKBACK", 24*time.Hour))
+	bot, err := historyClient.HasBotOrders(ctx, market, pairName, since)
+	if err != nil {
+		return exposure, nil
+	}
+	exposure.Attributed = true
+	exposure.Bot = bot
+	return exposure, nil
+}
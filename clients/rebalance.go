@@ -0,0 +1,375 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/config"
+	"arbitrage.trade/notify"
+	"arbitrage.trade/scheduler"
+)
+
+// Rebalance planner
+//
+// Trading drains USDT from the venues that keep buying spot into the ones that keep shorting, so it
+// has to be moved back by withdrawal. Until the bot withdraws by itself the planner only works out the
+// transfers: each venue's spot plus futures USDT is compared to its share of the total, weighted by
+// REBALANCE_WEIGHT_<EXCHANGE> (default 1), and the venues more than REBALANCE_MIN_USDT (default 100)
+// over their share send to those under it, largest first, over the cheapest network open for
+// withdrawal on the sender and deposit on the receiver. Venues whose USDT wallet is suspended keep
+// their balance. `arbitrage.trade rebalance-plan` prints the plan for the operator to execute by hand;
+// with REBALANCE_MODE=plan the bot also computes it every REBALANCE_PLAN_INTERVAL (default 6h), serves
+// it on GET /rebalance/plan and sends plans with transfers through the summary notifier.
+
+// RebalanceVenue is a venue's USDT against its share of the total
+type RebalanceVenue struct {
+	Exchange    string  `json:"exchange"`
+	SpotUSDT    float64 `json:"spot_usdt"`
+	FuturesUSDT float64 `json:"futures_usdt"`
+	TotalUSDT   float64 `json:"total_usdt"`
+	TargetUSDT  float64 `json:"target_usdt"`
+	Usable      bool    `json:"usable"` // Balances known and USDT deposits and withdrawals open
+}
+
+// RebalanceTransfer is one withdrawal the operator should make
+type RebalanceTransfer struct {
+	From             string  `json:"from"`
+	To               string  `json:"to"`
+	Asset            string  `json:"asset"`
+	Network          string  `json:"network"`
+	Amount           float64 `json:"amount"`       // Withdrawn from the sender's spot wallet
+	FromFutures      float64 `json:"from_futures"` // To move from the sender's futures to its spot wallet first
+	Fee              float64 `json:"fee"`
+	FeeEstimated     bool    `json:"fee_estimated"` // The venue doesn't report fees, typical fee for the network
+	Received         float64 `json:"received"`
+	EstimatedMinutes int     `json:"estimated_minutes"`
+}
+
+// RebalancePlan is the set of transfers that brings every usable venue to its share
+type RebalancePlan struct {
+	GeneratedAt time.Time           `json:"generated_at"`
+	TotalUSDT   float64             `json:"total_usdt"`
+	Venues      []RebalanceVenue    `json:"venues"`
+	Transfers   []RebalanceTransfer `json:"transfers"`
+	TotalFees   float64             `json:"total_fees"`
+	Notes       []string            `json:"notes,omitempty"`
+}
+
+// usdtNetworks are the typical USDT withdrawal fee and deposit arrival time of each network
+var usdtNetworks = map[string]struct {
+	fee     float64
+	minutes int
+}{
+	"TRC20":    {1, 3},
+	"BEP20":    {0.3, 2},
+	"ERC20":    {5, 10},
+	"ARBITRUM": {0.8, 3},
+	"OPTIMISM": {0.8, 3},
+	"POLYGON":  {0.8, 5},
+	"SOL":      {1, 2},
+	"AVAXC":    {1, 2},
+	"TON":      {0.5, 2},
+}
+
+// networkAliases maps the venues' chain names to one name per network
+var networkAliases = map[string]string{
+	"TRX": "TRC20", "TRON": "TRC20",
+	"BSC": "BEP20", "BEP20(BSC)": "BEP20", "BNB SMART CHAIN": "BEP20",
+	"ETH": "ERC20", "ETHEREUM": "ERC20",
+	"ARB": "ARBITRUM", "ARBONE": "ARBITRUM", "ARBEVM": "ARBITRUM", "ARBITRUM ONE": "ARBITRUM", "ARBITRUMONE": "ARBITRUM",
+	"OP": "OPTIMISM", "OPETH": "OPTIMISM",
+	"MATIC": "POLYGON", "POLYGON POS": "POLYGON",
+	"SOLANA": "SOL", "SPL": "SOL",
+	"AVAX": "AVAXC", "AVAX C-CHAIN": "AVAXC", "AVAX_C": "AVAXC", "CAVAX": "AVAXC",
+	"TONCOIN": "TON",
+}
+
+// canonicalNetwork returns the network's common name, "USDT-TRC20" (OKX) and "TRX" alike become "TRC20"
+func canonicalNetwork(asset, network string) string {
+	name := strings.ToUpper(strings.TrimSpace(network))
+	name = strings.TrimPrefix(name, strings.ToUpper(asset)+"-")
+	if alias, ok := networkAliases[name]; ok {
+		return alias
+	}
+	return name
+}
+
+// rebalanceRoute picks the cheapest network open for withdrawal on the sender and deposit on the receiver
+func rebalanceRoute(asset string, from, to *common.AssetStatus, amount float64) (route RebalanceTransfer, ok bool) {
+	deposits := make(map[string]bool)
+	for _, n := range to.Networks {
+		if n.CanDeposit {
+			deposits[canonicalNetwork(asset, n.Network)] = true
+		}
+	}
+
+	for _, n := range from.Networks {
+		network := canonicalNetwork(asset, n.Network)
+		if !n.CanWithdraw || !deposits[network] || amount < n.WithdrawMin {
+			continue
+		}
+
+		typical, known := usdtNetworks[network]
+		fee, estimated := n.WithdrawFee, false
+		if fee <= 0 && known {
+			fee, estimated = typical.fee, true
+		}
+		if fee >= amount {
+			continue
+		}
+		candidate := RebalanceTransfer{Network: network, Fee: fee, FeeEstimated: estimated, EstimatedMinutes: typical.minutes}
+
+		if !ok || candidate.Fee < route.Fee ||
+			(candidate.Fee == route.Fee && candidate.EstimatedMinutes < route.EstimatedMinutes) {
+			route, ok = candidate, true
+		}
+	}
+	return route, ok
+}
+
+// PlanRebalance computes the USDT transfers that bring the venues to their share of the total
+// Nothing is withdrawn, the plan is for the operator to execute
+func PlanRebalance(ctx context.Context, exchanges []string) (*RebalancePlan, error) {
+	plan := &RebalancePlan{GeneratedAt: time.Now()}
+	statuses := make(map[string]*common.AssetStatus)
+
+	for _, exchange := range exchanges {
+		venue := RebalanceVenue{Exchange: exchange}
+		client, err := getOrCreateClient(common.ExchangeType(exchange))
+		if err != nil {
+			plan.Notes = append(plan.Notes, fmt.Sprintf("%s skipped: %v", exchange, err))
+			continue
+		}
+		balanceClient, hasBalance := client.(common.BalanceClient)
+		statusClient, hasStatus := client.(common.WalletStatusClient)
+		if !hasBalance || !hasStatus {
+			plan.Notes = append(plan.Notes, fmt.Sprintf("%s skipped: balances or wallet status not supported", exchange))
+			continue
+		}
+
+		spot, spotErr := balanceClient.GetUSDTBalance(ctx, "spot")
+		futures, futuresErr := balanceClient.GetUSDTBalance(ctx, "futures")
+		if spotErr != nil || futuresErr != nil {
+			plan.Notes = append(plan.Notes, fmt.Sprintf("%s skipped: balance unavailable (spot: %v, futures: %v)", exchange, spotErr, futuresErr))
+			continue
+		}
+		venue.SpotUSDT, venue.FuturesUSDT, venue.TotalUSDT = spot, futures, spot+futures
+		venue.TargetUSDT = venue.TotalUSDT
+		plan.TotalUSDT += venue.TotalUSDT
+
+		status, err := statusClient.GetAssetStatus(ctx, "USDT")
+		switch {
+		case err != nil:
+			plan.Notes = append(plan.Notes, fmt.Sprintf("%s keeps its balance: wallet status unavailable: %v", exchange, err))
+		case !status.CanDeposit || !status.CanWithdraw:
+			plan.Notes = append(plan.Notes, fmt.Sprintf("%s keeps its balance: USDT deposits %s, withdrawals %s",
+				exchange, enabledLabel(status.CanDeposit), enabledLabel(status.CanWithdraw)))
+		default:
+			venue.Usable = true
+			statuses[exchange] = status
+		}
+		plan.Venues = append(plan.Venues, venue)
+	}
+	if len(statuses) < 2 {
+		return plan, fmt.Errorf("need at least 2 venues with known balances and open USDT wallets, have %d", len(statuses))
+	}
+
+	// Shares of the usable venues' total, by weight
+	usableTotal, weights := 0.0, 0.0
+	for _, v := range plan.Venues {
+		if v.Usable {
+			usableTotal += v.TotalUSDT
+			weights += config.GetFloat(config.Key("REBALANCE_WEIGHT", v.Exchange), 1)
+		}
+	}
+	if weights <= 0 {
+		return plan, fmt.Errorf("REBALANCE_WEIGHT_* of the usable venues sum to %.2f", weights)
+	}
+	for i, v := range plan.Venues {
+		if v.Usable {
+			plan.Venues[i].TargetUSDT = usableTotal * config.GetFloat(config.Key("REBALANCE_WEIGHT", v.Exchange), 1) / weights
+		}
+	}
+
+	// Largest surplus to largest deficit first, which keeps the number of transfers low
+	minTransfer := config.GetFloat("REBALANCE_MIN_USDT", 100)
+	var senders, receivers []*RebalanceVenue
+	for i := range plan.Venues {
+		v := &plan.Venues[i]
+		switch {
+		case !v.Usable:
+		case v.TotalUSDT-v.TargetUSDT >= minTransfer:
+			senders = append(senders, v)
+		case v.TargetUSDT-v.TotalUSDT >= minTransfer:
+			receivers = append(receivers, v)
+		}
+	}
+	sort.Slice(senders, func(i, j int) bool {
+		return senders[i].TotalUSDT-senders[i].TargetUSDT > senders[j].TotalUSDT-senders[j].TargetUSDT
+	})
+	sort.Slice(receivers, func(i, j int) bool {
+		return receivers[i].TargetUSDT-receivers[i].TotalUSDT > receivers[j].TargetUSDT-receivers[j].TotalUSDT
+	})
+
+	surplus := make(map[string]float64)
+	spotLeft := make(map[string]float64)
+	for _, s := range senders {
+		surplus[s.Exchange] = s.TotalUSDT - s.TargetUSDT
+		spotLeft[s.Exchange] = s.SpotUSDT
+	}
+
+	for _, r := range receivers {
+		deficit := r.TargetUSDT - r.TotalUSDT
+		for _, s := range senders {
+			amount := min(deficit, surplus[s.Exchange])
+			if amount < minTransfer {
+				continue
+			}
+			route, ok := rebalanceRoute("USDT", statuses[s.Exchange], statuses[r.Exchange], amount)
+			if !ok {
+				plan.Notes = append(plan.Notes, fmt.Sprintf("no common USDT network open from %s to %s", s.Exchange, r.Exchange))
+				continue
+			}
+
+			route.From, route.To, route.Asset, route.Amount = s.Exchange, r.Exchange, "USDT", amount
+			route.FromFutures = max(amount-spotLeft[s.Exchange], 0)
+			route.Received = amount - route.Fee
+			spotLeft[s.Exchange] = max(spotLeft[s.Exchange]-amount, 0)
+			plan.Transfers = append(plan.Transfers, route)
+			plan.TotalFees += route.Fee
+
+			surplus[s.Exchange] -= amount
+			deficit -= amount
+			if deficit < minTransfer {
+				break
+			}
+		}
+	}
+	return plan, nil
+}
+
+// FormatRebalancePlan renders the plan as text, one numbered step per transfer
+func FormatRebalancePlan(plan *RebalancePlan) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Rebalance plan %s, %.2f USDT across %d venues\n\n", plan.GeneratedAt.Format(time.RFC3339), plan.TotalUSDT, len(plan.Venues))
+
+	fmt.Fprintf(&b, "%-12s %12s %12s %12s %12s\n", "Venue", "Spot", "Futures", "Total", "Target")
+	for _, v := range plan.Venues {
+		target := fmt.Sprintf("%12.2f", v.TargetUSDT)
+		if !v.Usable {
+			target = fmt.Sprintf("%12s", "(kept)")
+		}
+		fmt.Fprintf(&b, "%-12s %12.2f %12.2f %12.2f %s\n", v.Exchange, v.SpotUSDT, v.FuturesUSDT, v.TotalUSDT, target)
+	}
+
+	b.WriteString("\n")
+	if len(plan.Transfers) == 0 {
+		b.WriteString("No transfers needed\n")
+	}
+	for i, t := range plan.Transfers {
+		fee := fmt.Sprintf("fee %.2f", t.Fee)
+		if t.FeeEstimated {
+			fee = fmt.Sprintf("fee ~%.2f (typical)", t.Fee)
+		}
+		eta := ""
+		if t.EstimatedMinutes > 0 {
+			eta = fmt.Sprintf(", ~%d min", t.EstimatedMinutes)
+		}
+		fmt.Fprintf(&b, "%d. %s → %s: withdraw %.2f %s via %s (%s%s), %s receives %.2f\n",
+			i+1, t.From, t.To, t.Amount, t.Asset, t.Network, fee, eta, t.To, t.Received)
+		if t.FromFutures > 0 {
+			fmt.Fprintf(&b, "   first move %.2f %s from %s futures to spot\n", t.FromFutures, t.Asset, t.From)
+		}
+	}
+	if len(plan.Transfers) > 0 {
+		fmt.Fprintf(&b, "Total fees: %.2f USDT\n", plan.TotalFees)
+	}
+
+	for _, note := range plan.Notes {
+		fmt.Fprintf(&b, "Note: %s\n", note)
+	}
+	return b.String()
+}
+
+var (
+	lastPlan      *RebalancePlan
+	planExchanges []string
+	lastPlanMu    sync.RWMutex
+)
+
+// refreshRebalancePlan computes a plan, keeps it for GET /rebalance/plan and sends it when it has transfers
+func refreshRebalancePlan(ctx context.Context, exchanges []string) (*RebalancePlan, error) {
+	plan, err := PlanRebalance(ctx, exchanges)
+	if err != nil {
+		log.Printf("[REBALANCE] Plan - ERROR: %v", err)
+		return nil, err
+	}
+
+	lastPlanMu.Lock()
+	lastPlan = plan
+	lastPlanMu.Unlock()
+
+	if len(plan.Transfers) > 0 {
+		log.Printf("[REBALANCE] Plan has %d transfers, %.2f USDT in fees", len(plan.Transfers), plan.TotalFees)
+		notify.Send(notify.Message{
+			Event: notify.EventSummary,
+			Title: fmt.Sprintf("Rebalance plan: %d transfers", len(plan.Transfers)),
+			Body:  FormatRebalancePlan(plan),
+		})
+	}
+	return plan, nil
+}
+
+// RebalancePlanSnapshot returns the last computed plan, recomputing it when refresh is set
+// Fails unless the planner runs (REBALANCE_MODE=plan)
+func RebalancePlanSnapshot(ctx context.Context, refresh bool) (*RebalancePlan, error) {
+	lastPlanMu.RLock()
+	plan, exchanges := lastPlan, planExchanges
+	lastPlanMu.RUnlock()
+
+	if exchanges == nil {
+		return nil, fmt.Errorf("rebalance planner not running, set REBALANCE_MODE=plan")
+	}
+	if refresh || plan == nil {
+		return refreshRebalancePlan(ctx, exchanges)
+	}
+	return plan, nil
+}
+
+// StartRebalancePlanner computes the rebalance plan every REBALANCE_PLAN_INTERVAL when REBALANCE_MODE=plan
+func StartRebalancePlanner(exchanges []string) {
+	switch mode := config.GetString("REBALANCE_MODE", "off"); mode {
+	case "off":
+		return
+	case "plan":
+	default:
+		log.Printf("[REBALANCE] Unsupported REBALANCE_MODE %q, only plan is available, planner not started", mode)
+		return
+	}
+
+	lastPlanMu.Lock()
+	planExchanges = exchanges
+	lastPlanMu.Unlock()
+
+	interval := config.GetDuration("REBALANCE_PLAN_INTERVAL", 6*time.Hour)
+	if interval <= 0 {
+		go refreshRebalancePlan(context.Background(), exchanges)
+		return
+	}
+
+	scheduler.Register(scheduler.Job{
+		Name:       "rebalance_plan",
+		Schedule:   "@every " + interval.String(),
+		RunAtStart: true,
+		Run: func(ctx context.Context, _ time.Time) error {
+			_, err := refreshRebalancePlan(ctx, exchanges)
+			return err
+		},
+	})
+}
@@ -0,0 +1,139 @@
+package clients
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"arbitrage.trade/config"
+	"arbitrage.trade/notify"
+)
+
+// Reject back-off
+//
+// Every order a venue client sends is recorded as filled or rejected. When, over the last
+// REJECT_WINDOW (default 10m), at least REJECT_MIN_ORDERS orders (default 5) went to a venue and
+// the share rejected reaches REJECT_RATE_THRESHOLD (default 0.5, 0 disables), the venue is backed
+// off for REJECT_COOLDOWN (default 15m): no new legs are opened on it and an alert is sent. A burst
+// of rejects usually means a ban, an empty balance or drifted order parameters. Closing legs are
+// never held back.
+
+// RejectBackoff is a venue held back from new legs after a spike in rejected orders
+type RejectBackoff struct {
+	Exchange string    `json:"exchange"`
+	Orders   int       `json:"orders"`
+	Rejected int       `json:"rejected"`
+	LastErr  string    `json:"last_error"`
+	Since    time.Time `json:"since"`
+	Until    time.Time `json:"until"`
+}
+
+// orderOutcome is one order sent to a venue
+type orderOutcome struct {
+	at       time.Time
+	rejected bool
+}
+
+var (
+	orderOutcomes  = make(map[string][]orderOutcome) // exchange -> orders within the window
+	rejectBackoffs = make(map[string]*RejectBackoff)
+	rejectsMu      sync.Mutex
+)
+
+// recordOrder adds an order's outcome to its venue's window and backs the venue off when its reject rate spikes
+func recordOrder(exchange string, err error) {
+	threshold := config.GetFloat("REJECT_RATE_THRESHOLD", 0.5)
+	if threshold <= 0 {
+		return
+	}
+	window := config.GetDuration("REJECT_WINDOW", 10*time.Minute)
+	minOrders := config.GetInt("REJECT_MIN_ORDERS", 5)
+	now := time.Now()
+
+	rejectsMu.Lock()
+	outcomes := append(orderOutcomes[exchange], orderOutcome{at: now, rejected: err != nil})
+	cutoff := now.Add(-window)
+	for len(outcomes) > 0 && outcomes[0].at.Before(cutoff) {
+		outcomes = outcomes[1:]
+	}
+	orderOutcomes[exchange] = outcomes
+
+	rejected := 0
+	for _, outcome := range outcomes {
+		if outcome.rejected {
+			rejected++
+		}
+	}
+
+	_, backedOff := rejectBackoffs[exchange]
+	if err == nil || backedOff || len(outcomes) < minOrders || float64(rejected)/float64(len(outcomes)) < threshold {
+		rejectsMu.Unlock()
+		return
+	}
+
+	backoff := &RejectBackoff{
+		Exchange: exchange,
+		Orders:   len(outcomes),
+		Rejected: rejected,
+		LastErr:  err.Error(),
+		Since:    now,
+		Until:    now.Add(config.GetDuration("REJECT_COOLDOWN", 15*time.Minute)),
+	}
+	rejectBackoffs[exchange] = backoff
+	// The cooldown starts a fresh window so the rejects that caused it don't trip it again
+	delete(orderOutcomes, exchange)
+	rejectsMu.Unlock()
+
+	log.Printf("[REJECTS] %s rejected %d of %d orders in %s - new legs held until %s",
+		exchange, rejected, len(outcomes), window, backoff.Until.Format(time.RFC3339))
+	notify.Send(notify.Message{
+		Event: notify.EventAlert,
+		Title: fmt.Sprintf("%s backed off: %d of %d orders rejected", exchange, rejected, len(outcomes)),
+		Body: fmt.Sprintf("Last error: %s\nNo new legs are routed to %s until %s. Check for a ban, low balance or changed order rules.",
+			backoff.LastErr, exchange, backoff.Until.Format(time.RFC3339)),
+	})
+}
+
+// RejectBlock returns why new legs on the venues are held after a reject spike, "" when allowed
+func RejectBlock(exchanges ...string) string {
+	now := time.Now()
+
+	rejectsMu.Lock()
+	defer rejectsMu.Unlock()
+
+	for _, exchange := range exchanges {
+		backoff, ok := rejectBackoffs[exchange]
+		if !ok {
+			continue
+		}
+		if now.After(backoff.Until) {
+			delete(rejectBackoffs, exchange)
+			log.Printf("[REJECTS] %s cooldown over - routing new legs again", exchange)
+			continue
+		}
+		return fmt.Sprintf("%s backed off until %s after %d of %d orders rejected",
+			exchange, backoff.Until.Format(time.RFC3339), backoff.Rejected, backoff.Orders)
+	}
+	return ""
+}
+
+// RejectBackoffs returns every venue currently backed off after a reject spike
+func RejectBackoffs() []RejectBackoff {
+	now := time.Now()
+
+	rejectsMu.Lock()
+	defer rejectsMu.Unlock()
+
+	backoffs := make([]RejectBackoff, 0, len(rejectBackoffs))
+	for _, backoff := range rejectBackoffs {
+		if now.Before(backoff.Until) {
+			backoffs = append(backoffs, *backoff)
+		}
+	}
+	sort.Slice(backoffs, func(i, j int) bool {
+		return backoffs[i].Exchange < backoffs[j].Exchange
+	})
+	return backoffs
+}
@@ -0,0 +1,152 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/config"
+	"arbitrage.trade/notify"
+	"arbitrage.trade/scheduler"
+)
+
+// Wallet health
+//
+// Funds on a venue are only usable for rebalancing while both deposits and withdrawals
+// of the asset are open; otherwise they could get stuck there. USDT and every pair's base
+// asset are checked on each venue every WALLET_CHECK_INTERVAL (default 10m) and venues
+// whose wallets become suspended are flagged through the alert notifier.
+
+var (
+	walletStatus   = make(map[string]map[string]*common.AssetStatus) // exchange -> asset -> status
+	walletStatusMu sync.RWMutex
+)
+
+// walletAssets returns USDT plus the base asset of every pair
+func walletAssets(pairs []string) []string {
+	assets := []string{"USDT"}
+	seen := map[string]bool{"USDT": true}
+	for _, pair := range pairs {
+		base := strings.ToUpper(strings.Split(pair, "-")[0])
+		if !seen[base] {
+			seen[base] = true
+			assets = append(assets, base)
+		}
+	}
+	return assets
+}
+
+// CheckWallets refreshes deposit/withdrawal status for USDT and the pairs' base assets on each venue
+func CheckWallets(ctx context.Context, exchanges []string, pairs []string) {
+	assets := walletAssets(pairs)
+
+	for _, exchange := range exchanges {
+		client, err := getOrCreateClient(common.ExchangeType(exchange))
+		if err != nil {
+			continue
+		}
+		statusClient, ok := client.(common.WalletStatusClient)
+		if !ok {
+			continue
+		}
+
+		for _, asset := range assets {
+			status, err := statusClient.GetAssetStatus(ctx, asset)
+			if err != nil {
+				log.Printf("[WALLET] %s %s - ERROR: %v", exchange, asset, err)
+				continue
+			}
+			setWalletStatus(exchange, status)
+		}
+	}
+}
+
+// setWalletStatus stores a status and flags venues whose wallet just became suspended
+func setWalletStatus(exchange string, status *common.AssetStatus) {
+	walletStatusMu.Lock()
+	if _, ok := walletStatus[exchange]; !ok {
+		walletStatus[exchange] = make(map[string]*common.AssetStatus)
+	}
+	prev := walletStatus[exchange][status.Asset]
+	walletStatus[exchange][status.Asset] = status
+	walletStatusMu.Unlock()
+
+	healthy := status.CanDeposit && status.CanWithdraw
+	wasHealthy := prev == nil || (prev.CanDeposit && prev.CanWithdraw)
+
+	switch {
+	case !healthy && wasHealthy:
+		log.Printf("[WALLET] %s %s suspended (deposit: %v, withdraw: %v) - balance not usable for rebalancing",
+			exchange, status.Asset, status.CanDeposit, status.CanWithdraw)
+		notify.Send(notify.Message{
+			Event: notify.EventAlert,
+			Title: fmt.Sprintf("%s %s wallet suspended", exchange, status.Asset),
+			Body: fmt.Sprintf("Deposits: %s | Withdrawals: %s\nFunds on %s could become stuck; excluded from rebalancing.",
+				enabledLabel(status.CanDeposit), enabledLabel(status.CanWithdraw), exchange),
+		})
+	case healthy && !wasHealthy:
+		log.Printf("[WALLET] %s %s deposits and withdrawals restored", exchange, status.Asset)
+	}
+}
+
+func enabledLabel(enabled bool) string {
+	if enabled {
+		return "open"
+	}
+	return "suspended"
+}
+
+// WalletStatus returns the last known deposit/withdrawal status of an asset on a venue
+func WalletStatus(exchange, asset string) (*common.AssetStatus, bool) {
+	walletStatusMu.RLock()
+	defer walletStatusMu.RUnlock()
+
+	status, ok := walletStatus[exchange][strings.ToUpper(asset)]
+	return status, ok
+}
+
+// UsableForRebalance reports whether a venue's balance of an asset can be moved in and out
+// Venues whose status is unknown are not counted as usable
+func UsableForRebalance(exchange, asset string) bool {
+	status, ok := WalletStatus(exchange, asset)
+	return ok && status.CanDeposit && status.CanWithdraw
+}
+
+// StartWalletChecks checks wallet health now and then every WALLET_CHECK_INTERVAL in the background
+func StartWalletChecks(exchanges []string, pairs []string) {
+	interval := config.GetDuration("WALLET_CHECK_INTERVAL", 10*time.Minute)
+
+	if interval <= 0 {
+		go CheckWallets(context.Background(), exchanges, pairs)
+		return
+	}
+
+	scheduler.Register(scheduler.Job{
+		Name:       "wallet_check",
+		Schedule:   "@every " + interval.String(),
+		RunAtStart: true,
+		Run: func(ctx context.Context, _ time.Time) error {
+			CheckWallets(ctx, exchanges, pairs)
+			return nil
+		},
+	})
+}
+
+// WalletSnapshot returns a copy of every known wallet status keyed by exchange and asset
+func WalletSnapshot() map[string]map[string]common.AssetStatus {
+	walletStatusMu.RLock()
+	defer walletStatusMu.RUnlock()
+
+	snapshot := make(map[string]map[string]common.AssetStatus, len(walletStatus))
+	for exchange, assets := range walletStatus {
+		snapshot[exchange] = make(map[string]common.AssetStatus, len(assets))
+		for asset, status := range assets {
+			snapshot[exchange][asset] = *status
+		}
+	}
+	return snapshot
+}
@@ -0,0 +1,118 @@
+package whitebit
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/clients/fixturetest"
+)
+
+const (
+	fixtureKey    = "fixture-key"
+	fixtureSecret = "fixture-secret"
+	fixturePair   = "xrp-usdt"
+)
+
+// TestFixtureReplay replays an open/close cycle of both legs from testdata/whitebit.json
+func TestFixtureReplay(t *testing.T) {
+	rec := fixturetest.Replay(t, "whitebit", "testdata")
+	client := NewWhitebitClient(fixtureKey, fixtureSecret)
+	ctx := context.Background()
+
+	if holding, err := client.GetSpotHolding(ctx, fixturePair); err != nil || holding != 0 {
+		t.Errorf("GetSpotHolding = %v, %v, want 0", holding, err)
+	}
+	if balance, err := client.GetUSDTBalance(ctx, "spot"); err != nil || common.NotEqual(balance, 1000.5) {
+		t.Errorf("GetUSDTBalance spot = %v, %v, want 1000.5", balance, err)
+	}
+
+	// Fill prices are derived from the dealt money and stock
+	spot, err := client.PutSpotLong(ctx, fixturePair, 20)
+	fixturetest.CheckResult(t, "PutSpotLong", spot, err, common.TradeResult{
+		OrderID: "4001", ExecutedPrice: 0.55, ExecutedQty: 36.3, Fee: 0.019965, Success: true,
+	})
+
+	// The short is read back from the open collateral position
+	perp, err := client.PutFuturesShort(ctx, fixturePair, 20)
+	fixturetest.CheckResult(t, "PutFuturesShort", perp, err, common.TradeResult{
+		OrderID: "4101", ExecutedPrice: 0.5498, ExecutedQty: 36.3, Success: true,
+	})
+
+	if position, err := client.GetFuturesPosition(ctx, fixturePair); err != nil || common.NotEqual(position, -36.3) {
+		t.Errorf("GetFuturesPosition = %v, %v, want -36.3", position, err)
+	}
+
+	perpClose, _, err := client.CloseFuturesShort(ctx, fixturePair)
+	fixturetest.CheckResult(t, "CloseFuturesShort", perpClose, err, common.TradeResult{
+		OrderID: "4102", ExecutedPrice: 0.5502, ExecutedQty: 36.3, Success: true,
+	})
+	if position, err := client.GetFuturesPosition(ctx, fixturePair); err != nil || position != 0 {
+		t.Errorf("GetFuturesPosition after close = %v, %v, want 0", position, err)
+	}
+
+	spotClose, _, err := client.CloseSpotLong(ctx, fixturePair, 20)
+	fixturetest.CheckResult(t, "CloseSpotLong", spotClose, err, common.TradeResult{
+		OrderID: "4002", ExecutedPrice: 0.5505, ExecutedQty: 36.2, Fee: 0.0199281, Success: true,
+	})
+
+	orders := rec.FindAll("POST", "/api/v4/order/collateral/market")
+	if len(orders) != 2 {
+		t.Fatalf("%d collateral orders sent, want 2", len(orders))
+	}
+	for i, want := range []string{"sell", "buy"} {
+		var body map[string]interface{}
+		if err := json.Unmarshal([]byte(orders[i].Body), &body); err != nil {
+			t.Fatalf("collateral order %d body %q: %v", i, orders[i].Body, err)
+		}
+		if body["side"] != want || body["market"] != "XRP_PERP" {
+			t.Errorf("collateral order %d = %v, want %s XRP_PERP", i, body, want)
+		}
+	}
+
+	checkSignatures(t, rec)
+	fixturetest.CheckReplayed(t, "whitebit")
+}
+
+// checkSignatures verifies every signed request carries the key, its body as the base64 payload
+// and a hex HMAC-SHA512 of that payload
+func checkSignatures(t *testing.T, rec *fixturetest.Recorder) {
+	t.Helper()
+	signed := 0
+	for _, req := range rec.Requests() {
+		signature := req.Header.Get("X-TXC-SIGNATURE")
+		if signature == "" {
+			continue
+		}
+		signed++
+
+		payload := req.Header.Get("X-TXC-PAYLOAD")
+		if payload != base64.StdEncoding.EncodeToString([]byte(req.Body)) {
+			t.Errorf("%s payload does not encode the body %s", req.URL.Path, req.Body)
+		}
+		mac := hmac.New(sha512.New, []byte(fixtureSecret))
+		mac.Write([]byte(payload))
+		if want := hex.EncodeToString(mac.Sum(nil)); signature != want {
+			t.Errorf("%s signature = %s, want %s", req.URL.Path, signature, want)
+		}
+		if req.Header.Get("X-TXC-APIKEY") != fixtureKey {
+			t.Errorf("%s sent without the API key", req.URL.Path)
+		}
+
+		var body struct {
+			Request string `json:"request"`
+			Nonce   int64  `json:"nonce"`
+		}
+		if err := json.Unmarshal([]byte(req.Body), &body); err != nil || body.Request != req.URL.Path || body.Nonce == 0 {
+			t.Errorf("%s body %s lacks the request path or nonce", req.URL.Path, req.Body)
+		}
+	}
+	if signed == 0 {
+		t.Error("no signed request was sent")
+	}
+}
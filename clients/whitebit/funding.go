@@ -0,0 +1,42 @@
+package whitebit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"arbitrage.trade/clients/common"
+)
+
+// GetFundingRate returns the perp's current funding rate from the public futures list
+func (w *WhitebitClient) GetFundingRate(ctx context.Context, pairName string) (*common.FundingRate, error) {
+	resp, err := w.rest.Get(ctx, w.baseURL+"/api/v4/public/futures")
+	if err != nil {
+		return nil, err
+	}
+
+	var r struct {
+		Success bool `json:"success"`
+		Result  []struct {
+			TickerID                 string `json:"ticker_id"`
+			FundingRate              string `json:"funding_rate"`
+			NextFundingRateTimestamp string `json:"next_funding_rate_timestamp"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(resp.Body, &r); err != nil {
+		return nil, err
+	}
+
+	market := w.normalizeSymbolFutures(pairName)
+	for _, f := range r.Result {
+		if f.TickerID != market {
+			continue
+		}
+		rate, _ := strconv.ParseFloat(f.FundingRate, 64)
+		nextMs, _ := strconv.ParseInt(f.NextFundingRateTimestamp, 10, 64)
+		return &common.FundingRate{Rate: rate, FundingTime: time.UnixMilli(nextMs)}, nil
+	}
+	return nil, fmt.Errorf("market %s not found", market)
+}
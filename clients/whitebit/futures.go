@@ -98,6 +98,19 @@ func (w *WhitebitClient) getOpenPosition(ctx context.Context, market string) (*C
 	return nil, nil
 }
 
+// GetFuturesPosition returns the signed collateral position size for the pair
+func (w *WhitebitClient) GetFuturesPosition(ctx context.Context, pairName string) (float64, error) {
+	position, err := w.getOpenPosition(ctx, w.normalizeSymbolFutures(pairName))
+	if err != nil {
+		return 0, err
+	}
+	if position == nil {
+		return 0, nil
+	}
+	amount, _ := strconv.ParseFloat(position.Amount, 64)
+	return amount, nil
+}
+
 func (w *WhitebitClient) PutFuturesShort(ctx context.Context, pairName string, amountUSDT float64) (*common.TradeResult, error) {
 	market := w.normalizeSymbolFutures(pairName)
 
@@ -238,7 +251,7 @@ func (w *WhitebitClient) CloseFuturesShort(ctx context.Context, pairName string)
 	prevBalance := common.GetBalance(w.GetName(), "futures", "USDT")
 	common.SetBalance(w.GetName(), "futures", "USDT", newBalance)
 
-	profit := newBalance - prevBalance
+	profit := common.Diff(newBalance, prevBalance)
 
 	// Use the close order response data
 	dealStock, _ := strconv.ParseFloat(response.DealStock, 64)
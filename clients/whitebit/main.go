@@ -1,8 +1,8 @@
 package whitebit
 
 import (
-	"net/http"
-	"time"
+	"context"
+	"fmt"
 
 	"arbitrage.trade/clients/common"
 )
@@ -12,12 +12,10 @@ func NewWhitebitClient(apiKey, apiSecret string) *WhitebitClient {
 	rateLimiter <- struct{}{}
 
 	return &WhitebitClient{
-		apiKey:    apiKey,
-		apiSecret: apiSecret,
-		baseURL:   "https://whitebit.com",
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		apiKey:      apiKey,
+		apiSecret:   apiSecret,
+		baseURL:     "https://whitebit.com",
+		rest:        common.NewRESTClient("whitebit"),
 		positions:   make(map[string]*common.Position),
 		rateLimiter: rateLimiter,
 	}
@@ -26,3 +24,20 @@ func NewWhitebitClient(apiKey, apiSecret string) *WhitebitClient {
 func (w *WhitebitClient) GetName() string {
 	return "whitebit"
 }
+
+// Prewarm opens a pooled connection to the API
+func (w *WhitebitClient) Prewarm(ctx context.Context) {
+	common.PrewarmConnections(ctx, w.rest.HTTP, w.baseURL+"/api/v4/public/ping")
+}
+
+// GetUSDTBalance returns the available USDT on the given market ("spot" or "futures")
+func (w *WhitebitClient) GetUSDTBalance(ctx context.Context, market string) (float64, error) {
+	switch market {
+	case "spot":
+		return w.getSpotBalance(ctx, "USDT")
+	case "futures":
+		return w.getCollateralBalance(ctx)
+	default:
+		return 0, fmt.Errorf("no USDT balance for market %s", market)
+	}
+}
@@ -24,6 +24,12 @@ func (w *WhitebitClient) getSpotBalance(ctx context.Context, ticker string) (flo
 	return available, nil
 }
 
+// GetSpotHolding returns the available base-asset balance for the pair
+func (w *WhitebitClient) GetSpotHolding(ctx context.Context, pairName string) (float64, error) {
+	baseAsset := strings.Split(w.normalizeSymbol(pairName), "_")[0]
+	return w.getSpotBalance(ctx, baseAsset)
+}
+
 func (w *WhitebitClient) PutSpotLong(ctx context.Context, pairName string, amountUSDT float64) (*common.TradeResult, error) {
 	market := w.normalizeSymbol(pairName)
 
@@ -129,7 +135,7 @@ func (w *WhitebitClient) CloseSpotLong(ctx context.Context, pairName string, amo
 	prevBalance := common.GetBalance(w.GetName(), "spot", "USDT")
 	common.SetBalance(w.GetName(), "spot", "USDT", newBalance)
 
-	profit := newBalance - prevBalance
+	profit := common.Diff(newBalance, prevBalance)
 
 	dealStock, _ := strconv.ParseFloat(response.DealStock, 64)
 	dealMoney, _ := strconv.ParseFloat(response.DealMoney, 64)
@@ -1,17 +1,16 @@
 package whitebit
 
 import (
-	"net/http"
 	"sync"
 
 	"arbitrage.trade/clients/common"
 )
 
 type WhitebitClient struct {
-	apiKey     string
-	apiSecret  string
-	baseURL    string
-	httpClient *http.Client
+	apiKey    string
+	apiSecret string
+	baseURL   string
+	rest      *common.RESTClient
 
 	positions map[string]*common.Position
 	mu        sync.RWMutex
@@ -8,7 +8,6 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
-	"io"
 	"log"
 	"net/http"
 	"strings"
@@ -37,78 +36,54 @@ func (w *WhitebitClient) signedRequest(ctx context.Context, endpoint string, par
 		w.rateLimiter <- struct{}{}
 	}()
 
-	nonce := time.Now().UnixMilli()
-
 	params["request"] = endpoint
-	params["nonce"] = nonce
 
-	bodyBytes, err := json.Marshal(params)
-	if err != nil {
-		return fmt.Errorf("failed to marshal params: %w", err)
-	}
+	resp, err := w.rest.Do(ctx, func(ctx context.Context) (*http.Request, error) {
+		params["nonce"] = time.Now().UnixMilli()
 
-	payload := base64.StdEncoding.EncodeToString(bodyBytes)
+		bodyBytes, err := json.Marshal(params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal params: %w", err)
+		}
 
-	h := hmac.New(sha512.New, []byte(w.apiSecret))
-	h.Write([]byte(payload))
-	signature := hex.EncodeToString(h.Sum(nil))
+		payload := base64.StdEncoding.EncodeToString(bodyBytes)
 
-	req, err := http.NewRequestWithContext(ctx, "POST", w.baseURL+endpoint, strings.NewReader(string(bodyBytes)))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
+		h := hmac.New(sha512.New, []byte(w.apiSecret))
+		h.Write([]byte(payload))
+		signature := hex.EncodeToString(h.Sum(nil))
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-TXC-APIKEY", w.apiKey)
-	req.Header.Set("X-TXC-PAYLOAD", payload)
-	req.Header.Set("X-TXC-SIGNATURE", signature)
+		req, err := http.NewRequestWithContext(ctx, "POST", w.baseURL+endpoint, strings.NewReader(string(bodyBytes)))
+		if err != nil {
+			return nil, err
+		}
 
-	resp, err := w.httpClient.Do(req)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-TXC-APIKEY", w.apiKey)
+		req.Header.Set("X-TXC-PAYLOAD", payload)
+		req.Header.Set("X-TXC-SIGNATURE", signature)
+		return req, nil
+	})
 	if err != nil {
 		log.Printf("[WHITEBIT] signedRequest - HTTP error: %v", err)
-		return fmt.Errorf("http request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read response: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("whitebit api error: status %d, body: %s", resp.StatusCode, string(body))
-	}
-
-	if result != nil {
-		// var prettyJson bytes.Buffer
-		// if err := json.Indent(&prettyJson, body, "", "  "); err != nil {
-		// 	return fmt.Errorf("failed to indent json: %w", err)
-		// }
-
-		// fmt.Println(prettyJson.String())
-
-		if err := json.Unmarshal(body, result); err != nil {
-			return fmt.Errorf("failed to unmarshal response: %w", err)
-		}
+		return err
 	}
 
-	return nil
+	return resp.DecodeJSON(result)
 }
 
 func (w *WhitebitClient) getPrice(ctx context.Context, market string) (float64, error) {
 	url := fmt.Sprintf("%s/api/v4/public/ticker", w.baseURL)
 
-	resp, err := http.Get(url)
+	resp, err := w.rest.Get(ctx, url)
 	if err != nil {
 		return 0, err
 	}
-	defer resp.Body.Close()
 
 	var tickers map[string]struct {
 		LastPrice string `json:"last_price"`
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&tickers); err != nil {
+	if err := json.Unmarshal(resp.Body, &tickers); err != nil {
 		return 0, err
 	}
 
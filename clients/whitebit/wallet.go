@@ -0,0 +1,58 @@
+package whitebit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"arbitrage.trade/clients/common"
+)
+
+// GetAssetStatus returns the deposit/withdrawal state of an asset from the public asset list
+func (w *WhitebitClient) GetAssetStatus(ctx context.Context, asset string) (*common.AssetStatus, error) {
+	resp, err := w.rest.Get(ctx, w.baseURL+"/api/v4/public/assets")
+	if err != nil {
+		return nil, err
+	}
+
+	var assets map[string]struct {
+		CanDeposit  bool `json:"can_deposit"`
+		CanWithdraw bool `json:"can_withdraw"`
+		Networks    struct {
+			Deposits  []string `json:"deposits"`
+			Withdraws []string `json:"withdraws"`
+		} `json:"networks"`
+	}
+	if err := json.Unmarshal(resp.Body, &assets); err != nil {
+		return nil, err
+	}
+
+	a, ok := assets[asset]
+	if !ok {
+		return nil, fmt.Errorf("asset %s not listed", asset)
+	}
+
+	// Networks list the chains currently open in each direction
+	var networks []common.NetworkStatus
+	index := make(map[string]int)
+	network := func(name string) *common.NetworkStatus {
+		i, ok := index[name]
+		if !ok {
+			i = len(networks)
+			index[name] = i
+			networks = append(networks, common.NetworkStatus{Network: name})
+		}
+		return &networks[i]
+	}
+	for _, name := range a.Networks.Deposits {
+		network(name).CanDeposit = a.CanDeposit
+	}
+	for _, name := range a.Networks.Withdraws {
+		network(name).CanWithdraw = a.CanWithdraw
+	}
+
+	if len(networks) == 0 {
+		return &common.AssetStatus{Asset: asset, CanDeposit: a.CanDeposit, CanWithdraw: a.CanWithdraw}, nil
+	}
+	return common.NewAssetStatus(asset, networks), nil
+}
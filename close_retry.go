@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"arbitrage.trade/capability"
+	"arbitrage.trade/clients"
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/config"
+	"arbitrage.trade/notify"
+	"arbitrage.trade/storage"
+)
+
+// Close retry
+//
+// A closing position (or a failed entry with one leg filled) stays tracked until both venues
+// report flat. Legs still holding exposure are re-closed with exponential backoff starting at
+// CLOSE_RETRY_BACKOFF (default 2s) and capped at CLOSE_RETRY_MAX_BACKOFF (default 2m). Every
+// CLOSE_RETRY_ALERT_AFTER failed rounds (default 5) an error alert is sent. The pair stays
+// blocked for new entries until it is flat; other pairs keep trading.
+//
+// A spot venue is flat once the position's own remaining quantity there is below the venue's minimum
+// order, so dust and standing inventory on the venue never hold the pair. That residual is retried
+// with a reduce order for exactly its size; venues without partial closes fall back to a full close.
+
+// closeUntilFlat blocks until the position's venues report flat, re-closing exposed legs, then releases the pair
+func closeUntilFlat(ctx context.Context, position *ArbitragePosition) {
+	if confirmFlat(ctx, position) {
+		releasePosition(position)
+		return
+	}
+
+	log.Printf("[BLOCKED %s] Exchange exposure not confirmed flat - retrying close, new entries on this pair stay suppressed", position.PairName)
+
+	// Let other pairs trade while this one settles
+	if globalAnalyzer != nil {
+		globalAnalyzer.ResetExecutionFlag()
+	}
+
+	backoff := config.GetDuration("CLOSE_RETRY_BACKOFF", 2*time.Second)
+	maxBackoff := config.GetDuration("CLOSE_RETRY_MAX_BACKOFF", 2*time.Minute)
+	alertAfter := config.GetInt("CLOSE_RETRY_ALERT_AFTER", 5)
+	if alertAfter < 1 {
+		alertAfter = 1
+	}
+	alerted := false
+
+	for attempt := 1; ; attempt++ {
+		time.Sleep(backoff)
+
+		retryCloseLegs(ctx, position)
+		if confirmFlat(ctx, position) {
+			log.Printf("[RESOLVED %s] Flat after %d close retries", position.PairName, attempt)
+			if alerted {
+				notify.Send(notify.Message{
+					Event: notify.EventAlert,
+					Title: fmt.Sprintf("%s flat after %d close retries", position.PairName, attempt),
+					Body:  fmt.Sprintf("Spot %s / futures %s confirmed flat. New entries on %s resume.", position.spotExchange(), position.perpExchange(), position.PairName),
+				})
+			}
+			releasePosition(position)
+			return
+		}
+
+		log.Printf("[RETRY CLOSE %s] Attempt %d still not flat, next retry in %s", position.PairName, attempt, backoff)
+
+		if attempt%alertAfter == 0 {
+			alerted = true
+			notify.Send(notify.Message{
+				Event: notify.EventError,
+				Title: fmt.Sprintf("%s still not flat after %d close retries", position.PairName, attempt),
+				Body: fmt.Sprintf("Spot %s / futures %s still report exposure. Retrying every %s; new entries on %s stay blocked.",
+					position.spotExchange(), position.perpExchange(), backoff, position.PairName),
+			})
+		}
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// retryCloseLegs re-sends the close order for the perp while its venue reports exposure and sells
+// what is left of the position on every spot venue
+// Reverse positions only re-close the perp; the spot venue keeps its standing inventory
+func retryCloseLegs(ctx context.Context, position *ArbitragePosition) {
+	position.mu.RLock()
+	spread, closeLong := position.CurrentSpread, position.CurrentLong
+	position.mu.RUnlock()
+
+	perpCommand := position.perpCloseCommand()
+
+	if flat, err := clients.IsFlat(ctx, position.perpExchange(), position.perpMarket(), position.PairName); err != nil {
+		log.Printf("[RETRY CLOSE %s] ERROR: Failed to check %s %s: %v", position.PairName, position.perpExchange(), position.perpMarket(), err)
+	} else if !flat {
+		_, profit, err := clients.Execute(ctx, position.perpExchange(), perpCommand, position.PairName, position.AmountUSDT, spread)
+		if err != nil {
+			log.Printf("[RETRY CLOSE %s] ERROR: %s on %s failed: %v", position.PairName, perpCommand, position.perpExchange(), err)
+		} else {
+			log.Printf("[RETRY CLOSE %s] %s on %s done, realized %+.4f USDT", position.PairName, perpCommand, position.perpExchange(), profit)
+		}
+	}
+
+	if position.Reverse {
+		return
+	}
+
+	for _, route := range position.spotRoutes() {
+		exchange := common.ExchangeType(route.Exchange)
+		residual, err := spotResidual(ctx, position, route)
+		if err != nil {
+			log.Printf("[RETRY CLOSE %s] ERROR: Failed to check %s spot: %v", position.PairName, exchange, err)
+			continue
+		}
+		if common.IsZero(residual) {
+			continue
+		}
+
+		if !clients.SupportsPartialClose(exchange) {
+			result, profit, err := clients.Execute(ctx, exchange, common.CloseSpotLong, position.PairName, route.AmountUSDT, spread)
+			if err != nil {
+				log.Printf("[RETRY CLOSE %s] ERROR: CloseSpotLong on %s failed: %v", position.PairName, exchange, err)
+				continue
+			}
+			position.addVenueFill("close_spot", exchange, result, closeLong, false)
+			log.Printf("[RETRY CLOSE %s] CloseSpotLong on %s done, realized %+.4f USDT", position.PairName, exchange, profit)
+			continue
+		}
+
+		result, err := clients.Reduce(ctx, exchange, common.CloseSpotLong, position.PairName, residual, spread)
+		if err != nil {
+			log.Printf("[RETRY CLOSE %s] ERROR: Selling the %s residual on %s failed: %v",
+				position.PairName, common.FormatQuantity(residual, position.PairName), exchange, err)
+			continue
+		}
+		position.addVenueFill("close_spot", exchange, result, closeLong, false)
+		log.Printf("[RETRY CLOSE %s] Sold the %s residual on %s", position.PairName, common.FormatQuantity(residual, position.PairName), exchange)
+	}
+}
+
+// spotResidual returns what is left of the position's spot long on a route's venue, 0 once it is below
+// the venue's minimum order: what its open fills bought there less what its closes sold, capped at the
+// venue's holding. A position that recorded no fills at all counts the venue's whole holding.
+func spotResidual(ctx context.Context, position *ArbitragePosition, route storage.SpotRoute) (float64, error) {
+	holding, err := clients.SpotHolding(ctx, common.ExchangeType(route.Exchange), position.PairName)
+	if err != nil {
+		return 0, err
+	}
+
+	position.mu.RLock()
+	opened, closed := 0.0, 0.0
+	for _, leg := range position.Legs {
+		if leg.Exchange != route.Exchange {
+			continue
+		}
+		switch leg.Leg {
+		case "open_spot":
+			opened += leg.Quantity
+		case "close_spot":
+			closed += leg.Quantity
+		}
+	}
+	tracked, price := len(position.Legs) > 0, position.CurrentLong
+	position.mu.RUnlock()
+
+	residual := holding
+	if tracked {
+		residual = math.Min(math.Max(opened-closed, 0), holding)
+	}
+	residual = common.RoundQuantity(residual, position.PairName)
+
+	if common.IsNegativeOrZero(price) {
+		price = route.Price
+	}
+	if common.LessThan(residual*price, capability.MinOrderVolume(route.Exchange, position.PairName, true, price)) {
+		return 0, nil
+	}
+	return residual, nil
+}
+
+// releasePosition stops tracking a flat position and frees the analyzer for the next trade
+func releasePosition(position *ArbitragePosition) {
+	positionsMutex.Lock()
+	delete(activePositions, position.PairName)
+	positionsMutex.Unlock()
+	persistPositions()
+
+	if globalAnalyzer != nil {
+		globalAnalyzer.ResetExecutionFlag()
+	}
+}
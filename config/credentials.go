@@ -0,0 +1,51 @@
+package config
+
+import "os"
+
+// Exchange credentials
+//
+// Every exchange account reads its credentials from <EXCHANGE>_API_KEY, <EXCHANGE>_API_SECRET,
+// <EXCHANGE>_PASSPHRASE and <EXCHANGE>_SUBACCOUNT, with the exchange name upper-cased as in Key
+// (e.g. KRAKEN_FUTURES_API_KEY for the "kraken-futures" account). Client constructors receive the
+// resulting Credentials instead of reading the environment themselves.
+
+// Credentials is one exchange account's API credentials
+type Credentials struct {
+	Exchange   string
+	Key        string
+	Secret     string
+	Passphrase string // Signing passphrase, required by the exchanges in passphraseExchanges
+	Subaccount string // Sub-account the key trades for, "" for the main account
+}
+
+// passphraseExchanges sign requests with a passphrase chosen when the key was created
+var passphraseExchanges = map[string]bool{
+	"bitget": true,
+	"okx":    true,
+}
+
+// ExchangeCredentials reads the exchange's credentials from the environment
+func ExchangeCredentials(exchange string) Credentials {
+	return Credentials{
+		Exchange:   exchange,
+		Key:        os.Getenv(Key(exchange, "API_KEY")),
+		Secret:     os.Getenv(Key(exchange, "API_SECRET")),
+		Passphrase: os.Getenv(Key(exchange, "PASSPHRASE")),
+		Subaccount: os.Getenv(Key(exchange, "SUBACCOUNT")),
+	}
+}
+
+// Missing lists the env vars of credentials the exchange requires that are unset
+func (c Credentials) Missing() []string {
+	var missing []string
+	if c.Key == "" {
+		missing = append(missing, Key(c.Exchange, "API_KEY"))
+	}
+	if c.Secret == "" {
+		missing = append(missing, Key(c.Exchange, "API_SECRET"))
+	}
+	if c.Passphrase == "" && passphraseExchanges[c.Exchange] {
+		missing = append(missing, Key(c.Exchange, "PASSPHRASE"))
+	}
+	return missing
+}
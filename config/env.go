@@ -0,0 +1,65 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GetString returns the environment variable or the default when unset
+func GetString(name, def string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return def
+}
+
+// GetFloat returns the environment variable parsed as float64 or the default
+func GetFloat(name string, def float64) float64 {
+	if v := os.Getenv(name); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	}
+	return def
+}
+
+// GetInt returns the environment variable parsed as int or the default
+func GetInt(name string, def int) int {
+	if v := os.Getenv(name); v != "" {
+		if i, err := strconv.Atoi(v); err == nil {
+			return i
+		}
+	}
+	return def
+}
+
+// GetBool returns the environment variable parsed as bool or the default
+func GetBool(name string, def bool) bool {
+	if v := os.Getenv(name); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+	return def
+}
+
+// GetDuration returns the environment variable parsed as a duration ("500ms", "2s") or the default
+func GetDuration(name string, def time.Duration) time.Duration {
+	if v := os.Getenv(name); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+	}
+	return def
+}
+
+// Key builds an environment variable name from parts, e.g. Key("MAX_NOTIONAL", "binance", "spot") -> "MAX_NOTIONAL_BINANCE_SPOT"
+func Key(parts ...string) string {
+	upper := make([]string, 0, len(parts))
+	for _, p := range parts {
+		upper = append(upper, strings.ToUpper(strings.ReplaceAll(p, "-", "_")))
+	}
+	return strings.Join(upper, "_")
+}
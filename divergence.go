@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"strings"
+
+	"arbitrage.trade/clients"
+	"arbitrage.trade/clients/common"
+)
+
+// checkDivergence reports whether a leg's fill landed beyond EXEC_DIVERGENCE_LIMIT_BPS from its decision
+// price, and if so logs it and pauses the spot:perp combination that produced it when configured
+func (p *ArbitragePosition) checkDivergence(leg string, exchange common.ExchangeType, slippagePct float64) bool {
+	limit := clients.DivergenceLimitBps()
+	divergence := math.Abs(slippagePct) * 100
+	if limit <= 0 || divergence <= limit {
+		return false
+	}
+
+	// A diverged spot leg blames its own venue, a diverged perp leg the position's main spot venue
+	spot := p.spotExchange()
+	if strings.HasSuffix(leg, "_spot") {
+		spot = exchange
+	}
+	detail := fmt.Sprintf("%s %s %s filled %.1f bps from its decision price (limit %.0f bps)",
+		p.PairName, p.TradeID, leg, divergence, limit)
+	log.Printf("[DIVERGENCE %s] %s", p.PairName, detail)
+
+	clients.PauseDivergedCombination(string(spot), string(p.perpExchange()), detail)
+	return true
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"log"
+	"math/rand/v2"
+	"strings"
+
+	"arbitrage.trade/clients"
+	"arbitrage.trade/config"
+)
+
+// A/B execution experiments
+//
+// With EXPERIMENT_NAME set, forward entries are split at random between the two execution playbooks
+// EXPERIMENT_VARIANTS names ("<a>,<b>", each "default" or an EXEC_PLAYBOOK_<NAME> definition, see
+// clients/playbook.go), EXPERIMENT_SPLIT (default 0.5) of them going to the second. The assigned
+// playbook replaces EXEC_PLAYBOOKS for the trade's opening and closing legs, and the experiment and
+// variant are tagged on the position alongside its strategy tag (see strategy.go), so its events and
+// stored records carry them. GET /trades/experiment and the `experiment` command compare the
+// variants' closed trades: P&L, win rate, slippage, spread capture and fill latency, with a Welch
+// t-statistic on the difference in profit per trade. Reverse entries are not part of an experiment.
+
+// experimentVariants returns the running experiment's name and its two playbook names, ok false when
+// none runs or it is misconfigured
+func experimentVariants() (name string, variants []string, ok bool) {
+	name = strings.TrimSpace(config.GetString("EXPERIMENT_NAME", ""))
+	if name == "" {
+		return "", nil, false
+	}
+	for _, variant := range strings.Split(config.GetString("EXPERIMENT_VARIANTS", ""), ",") {
+		if variant = strings.ToLower(strings.TrimSpace(variant)); variant != "" {
+			variants = append(variants, variant)
+		}
+	}
+	if len(variants) != 2 || variants[0] == variants[1] {
+		return name, variants, false
+	}
+	for _, variant := range variants {
+		if _, err := clients.PlaybookNamed(variant); err != nil {
+			return name, variants, false
+		}
+	}
+	return name, variants, true
+}
+
+// startExperiment logs the running experiment, or why it isn't running
+func startExperiment() {
+	name, variants, ok := experimentVariants()
+	switch {
+	case name == "":
+		return
+	case !ok:
+		log.Printf("[EXPERIMENT] %s not started: EXPERIMENT_VARIANTS must name two different playbooks, got %q", name, variants)
+		for _, variant := range variants {
+			if _, err := clients.PlaybookNamed(variant); err != nil {
+				log.Printf("[EXPERIMENT] Playbook %s: %v", variant, err)
+			}
+		}
+	default:
+		log.Printf("[EXPERIMENT] %s: %s vs %s, %.0f%% of forward entries to %s",
+			name, variants[0], variants[1], experimentSplit()*100, variants[1])
+	}
+}
+
+// experimentSplit returns the share of entries assigned to the second variant
+func experimentSplit() float64 {
+	return min(max(config.GetFloat("EXPERIMENT_SPLIT", 0.5), 0), 1)
+}
+
+// assignVariant draws a forward entry's variant, "" for both when no experiment runs
+func assignVariant() (experiment, variant string) {
+	name, variants, ok := experimentVariants()
+	if !ok {
+		return "", ""
+	}
+	if rand.Float64() < experimentSplit() {
+		return name, variants[1]
+	}
+	return name, variants[0]
+}
+
+// playbook returns how the position's legs are executed: its experiment variant's playbook when it
+// was assigned one, otherwise its venue combination's
+func (p *ArbitragePosition) playbook() clients.Playbook {
+	if p.Variant != "" {
+		if playbook, err := clients.PlaybookNamed(p.Variant); err == nil {
+			return playbook
+		}
+		log.Printf("[EXPERIMENT %s] Playbook %s of variant no longer defined, using the venues' playbook", p.PairName, p.Variant)
+	}
+	return clients.PlaybookFor(p.spotExchange(), p.perpExchange())
+}
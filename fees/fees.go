@@ -0,0 +1,296 @@
+package fees
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"arbitrage.trade/capability"
+	"arbitrage.trade/clients"
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/config"
+	"arbitrage.trade/logging"
+	"arbitrage.trade/notify"
+	"arbitrage.trade/scheduler"
+	"arbitrage.trade/storage"
+)
+
+// Fee-tier tracker
+//
+// Every FEE_REFRESH_INTERVAL (default 1h, 0 disables) each venue's traded volume per market over the
+// last 30 days is summed from the execution log and matched against the venue's tier schedule,
+// FEE_TIERS_<EXCHANGE>_<MARKET>: comma separated "<30d volume USDT>:<taker %>[:<maker %>]" steps,
+// e.g. FEE_TIERS_BINANCE_SPOT=0:0.1:0.1,1000000:0.09:0.09. Venues whose API reports the account's
+// current rate (binance, okx) use that instead, since it also counts volume traded outside this bot
+// and any discounts. Crossing a tier boundary logs and sends an alert.
+//
+// The analyzer subtracts both legs' round-trip taker fees from an opportunity's score. With
+// MIN_NET_SPREAD_PCT set, entries also need the spread net of those fees to reach it, so the
+// entry threshold moves with the tier.
+//
+// A negative maker rate is a rebate. The rebate a venue reports on a maker fill (a negative fee on
+// the order) is recorded per leg in the execution log and netted from the trade's fees in its P&L,
+// and the rebates each venue market paid over the last 30 days are reported with its rates, along
+// with the rate they work out to on the maker volume. The auto execution mode (EXEC_MODE=auto) rests
+// maker orders only where the maker rate undercuts the taker rate enough to be worth the fill risk.
+
+// volumeWindow is the trailing period venues rank fee tiers by
+const volumeWindow = 30 * 24 * time.Hour
+
+// Tier is one step of a venue's fee schedule
+type Tier struct {
+	Volume   float64 // 30-day USDT volume from which the tier applies
+	TakerPct float64
+	MakerPct float64
+}
+
+// Stat is the fee model of one venue market
+type Stat struct {
+	Exchange   string    `json:"exchange"`
+	Market     string    `json:"market"`
+	VolumeUSDT float64   `json:"volume_30d_usdt"` // Traded by this bot
+	MakerUSDT  float64   `json:"maker_volume_30d_usdt"`
+	Rebates    float64   `json:"rebates_30d_usdt"` // Reported by the venue on maker fills
+	RebatePct  float64   `json:"rebate_pct"`       // Rebates over maker volume, the rate actually paid
+	Tier       int       `json:"tier"`             // Index into the configured schedule, -1 without one
+	TakerPct   float64   `json:"taker_pct"`
+	MakerPct   float64   `json:"maker_pct"`
+	Source     string    `json:"source"` // "venue" when reported by the exchange, "tiers" from the schedule
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+var (
+	rates   = make(map[string]Stat) // exchange:market -> fee model
+	ratesMu sync.RWMutex
+)
+
+func key(exchange, market string) string {
+	return exchange + ":" + market
+}
+
+// Start refreshes the fee model now and then every FEE_REFRESH_INTERVAL in the background
+func Start(exchanges []string, pairs []string) {
+	interval := config.GetDuration("FEE_REFRESH_INTERVAL", time.Hour)
+	if interval <= 0 {
+		return
+	}
+
+	scheduler.Register(scheduler.Job{
+		Name:       "fees",
+		Schedule:   "@every " + interval.String(),
+		RunAtStart: true,
+		Run: func(ctx context.Context, _ time.Time) error {
+			return Refresh(ctx, exchanges, pairs)
+		},
+	})
+}
+
+// ParseTiers parses a "<volume>:<taker %>[:<maker %>],..." schedule, lowest volume first
+func ParseTiers(spec string) ([]Tier, error) {
+	var tiers []Tier
+	for _, step := range strings.Split(spec, ",") {
+		step = strings.TrimSpace(step)
+		if step == "" {
+			continue
+		}
+
+		parts := strings.Split(step, ":")
+		if len(parts) < 2 || len(parts) > 3 {
+			return nil, fmt.Errorf("invalid fee tier %q, want volume:taker[:maker]", step)
+		}
+		values := make([]float64, len(parts))
+		for i, part := range parts {
+			value, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid fee tier %q: %w", step, err)
+			}
+			values[i] = value
+		}
+
+		tier := Tier{Volume: values[0], TakerPct: values[1], MakerPct: values[1]}
+		if len(values) == 3 {
+			tier.MakerPct = values[2]
+		}
+		tiers = append(tiers, tier)
+	}
+
+	sort.Slice(tiers, func(i, j int) bool {
+		return tiers[i].Volume < tiers[j].Volume
+	})
+	return tiers, nil
+}
+
+// tierFor returns the index of the highest tier the volume qualifies for, -1 when below all of them
+func tierFor(tiers []Tier, volume float64) int {
+	index := -1
+	for i, tier := range tiers {
+		if volume >= tier.Volume {
+			index = i
+		}
+	}
+	return index
+}
+
+// traded is one venue market's fills over the volume window
+type traded struct {
+	volume  float64
+	maker   float64
+	rebates float64
+}
+
+// tradedVolumes returns the USDT volume and rebates per exchange:market filled over the volume window
+func tradedVolumes(now time.Time) (map[string]traded, error) {
+	records, err := storage.LoadExecutions(now.Add(-volumeWindow), now)
+	if err != nil {
+		return nil, err
+	}
+
+	volumes := make(map[string]traded)
+	for _, record := range records {
+		for _, leg := range record.Legs {
+			market := "spot"
+			if strings.HasSuffix(leg.Leg, "_perp") {
+				market = "futures"
+			}
+			t := volumes[key(leg.Exchange, market)]
+			t.volume += leg.Price * leg.Quantity
+			t.maker += leg.Price * leg.MakerQty
+			t.rebates += leg.Rebate
+			volumes[key(leg.Exchange, market)] = t
+		}
+	}
+	return volumes, nil
+}
+
+// Refresh recomputes every venue market's fee rate from its volume and tier schedule or the venue itself
+func Refresh(ctx context.Context, exchanges []string, pairs []string) error {
+	now := time.Now()
+	volumes, err := tradedVolumes(now)
+	if err != nil {
+		return fmt.Errorf("failed to load traded volume: %w", err)
+	}
+
+	for _, exchange := range exchanges {
+		for _, market := range []string{"spot", "futures"} {
+			t := volumes[key(exchange, market)]
+			stat := Stat{
+				Exchange:   exchange,
+				Market:     market,
+				VolumeUSDT: t.volume,
+				MakerUSDT:  t.maker,
+				Rebates:    t.rebates,
+				Tier:       -1,
+				UpdatedAt:  now,
+			}
+			if common.IsPositive(t.maker) {
+				stat.RebatePct = t.rebates / t.maker * 100.0
+			}
+
+			tiers, err := ParseTiers(config.GetString(config.Key("FEE_TIERS", exchange, market), ""))
+			if err != nil {
+				log.Printf("[FEES] %s %s - ERROR: %v", exchange, market, err)
+			}
+			if index := tierFor(tiers, stat.VolumeUSDT); index >= 0 {
+				stat.Tier = index
+				stat.TakerPct = tiers[index].TakerPct
+				stat.MakerPct = tiers[index].MakerPct
+				stat.Source = "tiers"
+			}
+
+			if rate, err := venueRate(ctx, exchange, market, pairs); err != nil {
+				logging.Debugf("fees", "[FEES] %s %s - no venue rate: %v", exchange, market, err)
+			} else {
+				stat.TakerPct = rate.Taker * 100.0
+				stat.MakerPct = rate.Maker * 100.0
+				stat.Source = "venue"
+			}
+
+			if stat.Source != "" {
+				update(stat)
+			}
+		}
+	}
+	return nil
+}
+
+// venueRate asks the venue for the account's rate, using the first pair it lists on the market
+func venueRate(ctx context.Context, exchange, market string, pairs []string) (*common.FeeRate, error) {
+	if clients.IsPaperTrading() {
+		return nil, fmt.Errorf("paper trading")
+	}
+	for _, pairName := range pairs {
+		if capability.CanTrade(exchange, pairName, market == "spot") {
+			return clients.FeeRate(ctx, common.ExchangeType(exchange), market, pairName)
+		}
+	}
+	return nil, fmt.Errorf("no tradable pair")
+}
+
+// update stores a venue market's fee model and alerts when its rate moved to another tier
+func update(stat Stat) {
+	ratesMu.Lock()
+	prev, known := rates[key(stat.Exchange, stat.Market)]
+	rates[key(stat.Exchange, stat.Market)] = stat
+	ratesMu.Unlock()
+
+	if !known {
+		log.Printf("[FEES] %s %s taker %.4f%% maker %.4f%% (%s, 30d volume %.2f USDT)",
+			stat.Exchange, stat.Market, stat.TakerPct, stat.MakerPct, stat.Source, stat.VolumeUSDT)
+		return
+	}
+	if common.Equal(prev.TakerPct, stat.TakerPct) && common.Equal(prev.MakerPct, stat.MakerPct) {
+		return
+	}
+
+	log.Printf("[FEES] %s %s fee tier changed: taker %.4f%% -> %.4f%%, maker %.4f%% -> %.4f%% (%s, 30d volume %.2f USDT)",
+		stat.Exchange, stat.Market, prev.TakerPct, stat.TakerPct, prev.MakerPct, stat.MakerPct, stat.Source, stat.VolumeUSDT)
+	notify.Send(notify.Message{
+		Event: notify.EventAlert,
+		Title: fmt.Sprintf("%s %s fee tier changed", stat.Exchange, stat.Market),
+		Body: fmt.Sprintf("Taker: %.4f%% -> %.4f%%\nMaker: %.4f%% -> %.4f%%\n30d volume: %.2f USDT (%s)",
+			prev.TakerPct, stat.TakerPct, prev.MakerPct, stat.MakerPct, stat.VolumeUSDT, stat.Source),
+	})
+}
+
+// TakerPct returns a venue market's current taker fee in %, 0 when unknown
+func TakerPct(exchange, market string) float64 {
+	ratesMu.RLock()
+	defer ratesMu.RUnlock()
+	return rates[key(exchange, market)].TakerPct
+}
+
+// MakerPct returns a venue market's current maker fee in %, negative for a rebate, 0 when unknown
+func MakerPct(exchange, market string) float64 {
+	ratesMu.RLock()
+	defer ratesMu.RUnlock()
+	return rates[key(exchange, market)].MakerPct
+}
+
+// Snapshot returns the fee model of every venue market
+func Snapshot() []Stat {
+	ratesMu.RLock()
+	stats := make([]Stat, 0, len(rates))
+	for _, stat := range rates {
+		stats = append(stats, stat)
+	}
+	ratesMu.RUnlock()
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Exchange != stats[j].Exchange {
+			return stats[i].Exchange < stats[j].Exchange
+		}
+		return stats[i].Market < stats[j].Market
+	})
+	return stats
+}
+
+// Source exposes the fee model to the analyzer and the executor
+type Source struct{}
+
+func (Source) TakerPct(exchange, market string) float64 { return TakerPct(exchange, market) }
+func (Source) MakerPct(exchange, market string) float64 { return MakerPct(exchange, market) }
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"arbitrage.trade/clients"
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/storage"
+)
+
+// Historical fill import
+//
+// `arbitrage.trade import-fills -from 2025-01-01` downloads the account's spot and futures fills
+// from each venue that exposes its trade history (Binance, OKX) into FILLS_FILE (default
+// fills.jsonl), covering periods the bot didn't track so P&L can be reconciled against exchange
+// statements. Fills already imported are skipped, so overlapping ranges can be re-run; fills
+// whose client order ID carries the bot's prefix are flagged as the bot's own.
+
+// parseImportTime parses an RFC3339 timestamp or a YYYY-MM-DD date (UTC midnight)
+func parseImportTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+// importFills imports the account fills of [from, to) and returns the exit code
+func importFills(fromArg, toArg, exchange, pairsArg string) int {
+	if fromArg == "" {
+		fmt.Fprintf(os.Stderr, "❌ -from is required\n")
+		return 2
+	}
+	from, err := parseImportTime(fromArg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ invalid -from %q: %v\n", fromArg, err)
+		return 2
+	}
+	to := time.Now()
+	if toArg != "" {
+		if to, err = parseImportTime(toArg); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ invalid -to %q: %v\n", toArg, err)
+			return 2
+		}
+	}
+	if !from.Before(to) {
+		fmt.Fprintf(os.Stderr, "❌ -from must be before -to\n")
+		return 2
+	}
+
+	exchanges := []common.ExchangeType{common.ExchangeType(exchange)}
+	if exchange == "" {
+		exchanges = nil
+		for _, e := range clients.Exchanges() {
+			if clients.SupportsTradeHistory(e) {
+				exchanges = append(exchanges, e)
+			}
+		}
+	}
+
+	var pairs []string
+	if pairsArg != "" {
+		for _, pair := range strings.Split(pairsArg, ",") {
+			if pair = strings.TrimSpace(pair); pair != "" {
+				pairs = append(pairs, pair)
+			}
+		}
+	} else {
+		for pair := range common.PairPrecisions {
+			pairs = append(pairs, pair)
+		}
+		sort.Strings(pairs)
+	}
+
+	existing, err := storage.LoadFills(from, to)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+	imported := make(map[string]bool, len(existing))
+	for _, record := range existing {
+		imported[record.Key()] = true
+	}
+
+	ctx := context.Background()
+	failed, total := 0, 0
+	for _, e := range exchanges {
+		fmt.Printf("%s %s → %s\n", e, from.Format(time.RFC3339), to.Format(time.RFC3339))
+		for _, market := range []string{"spot", "futures"} {
+			for _, pair := range pairs {
+				fills, err := clients.AccountFills(ctx, e, market, pair, from, to)
+				if err != nil {
+					fmt.Printf("  ❌ %-8s %-12s %v\n", market, pair, err)
+					failed++
+					continue
+				}
+
+				now := time.Now()
+				var records []storage.FillRecord
+				for _, fill := range fills {
+					record := storage.FillRecord{
+						Exchange:      fill.Exchange,
+						Market:        fill.Market,
+						Pair:          fill.Pair,
+						FillID:        fill.FillID,
+						OrderID:       fill.OrderID,
+						ClientOrderID: fill.ClientOrderID,
+						Bot:           common.IsBotOrderID(fill.ClientOrderID),
+						Side:          fill.Side,
+						Price:         fill.Price,
+						Quantity:      fill.Quantity,
+						Fee:           fill.Fee,
+						FeeAsset:      fill.FeeAsset,
+						RealizedPnL:   fill.RealizedPnL,
+						Time:          fill.Time,
+						ImportedAt:    now,
+					}
+					if imported[record.Key()] {
+						continue
+					}
+					imported[record.Key()] = true
+					records = append(records, record)
+				}
+				if len(fills) == 0 {
+					continue
+				}
+
+				if err := storage.AppendFills(records); err != nil {
+					fmt.Printf("  ❌ %-8s %-12s %v\n", market, pair, err)
+					failed++
+					continue
+				}
+				total += len(records)
+				fmt.Printf("  ✅ %-8s %-12s %d fills, %d new\n", market, pair, len(fills), len(records))
+			}
+		}
+	}
+
+	fmt.Printf("imported %d fills\n", total)
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,263 @@
+package funding
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"arbitrage.trade/capability"
+	"arbitrage.trade/clients"
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/config"
+	"arbitrage.trade/scheduler"
+	"arbitrage.trade/storage"
+)
+
+// Funding-rate collector
+//
+// Every FUNDING_COLLECT_INTERVAL (default 1h, 0 disables) the current funding rate of each pair's
+// perp on every venue listing it is appended to FUNDING_FILE. The average over FUNDING_LOOKBACK
+// (default 168h), counting each settlement once, is the venue's funding bias: positive means shorts
+// have been receiving funding. The analyzer tries perp venues with the best bias first for forward
+// trades (short perp) and the worst first for reverse trades (long perp).
+//
+// On the first collection, venues that publish their funding history (Binance, OKX) backfill the
+// settlements of the lookback the store is missing, so a restart or a new pair starts with a full bias.
+
+// Stat is a venue's funding history for one pair
+type Stat struct {
+	Exchange    string    `json:"exchange"`
+	Pair        string    `json:"pair"`
+	AvgRatePct  float64   `json:"avg_rate_pct"` // Mean per settlement, positive when shorts receive
+	Settlements int       `json:"settlements"`
+	LastRatePct float64   `json:"last_rate_pct"`
+	LastTime    time.Time `json:"last_funding_time"`
+}
+
+var (
+	history   = make(map[string]map[int64]float64) // exchange:pair -> settlement unix time -> rate
+	historyMu sync.RWMutex
+)
+
+func key(exchange, pairName string) string {
+	return exchange + ":" + pairName
+}
+
+func lookback() time.Duration {
+	return config.GetDuration("FUNDING_LOOKBACK", 7*24*time.Hour)
+}
+
+// Start loads the stored funding history and collects new rates in the background
+func Start(exchanges []string, pairs []string) {
+	interval := config.GetDuration("FUNDING_COLLECT_INTERVAL", time.Hour)
+	if interval <= 0 {
+		return
+	}
+
+	records, err := storage.LoadFunding(time.Now().Add(-lookback()))
+	if err != nil {
+		log.Printf("[FUNDING] ERROR: Failed to load funding history: %v", err)
+	}
+	for _, r := range records {
+		record(r)
+	}
+	log.Printf("[FUNDING] Loaded %d stored funding rates", len(records))
+
+	var backfilled sync.Once
+	scheduler.Register(scheduler.Job{
+		Name:       "funding",
+		Schedule:   "@every " + interval.String(),
+		RunAtStart: true,
+		Run: func(ctx context.Context, _ time.Time) error {
+			backfilled.Do(func() { backfill(ctx, exchanges, pairs) })
+			collect(ctx, exchanges, pairs)
+			return nil
+		},
+	})
+}
+
+// collect fetches the current funding rate of every pair on every perp venue and stores it
+func collect(ctx context.Context, exchanges []string, pairs []string) {
+	now := time.Now()
+	var records []storage.FundingRecord
+
+	for _, exchange := range exchanges {
+		for _, pairName := range pairs {
+			if !capability.CanTrade(exchange, pairName, false) {
+				continue
+			}
+
+			rate, err := clients.FundingRate(ctx, common.ExchangeType(exchange), pairName)
+			if err != nil {
+				log.Printf("[FUNDING] %s %s - ERROR: Failed to get funding rate: %v", exchange, pairName, err)
+				continue
+			}
+
+			r := storage.FundingRecord{
+				Exchange:    exchange,
+				Pair:        pairName,
+				Rate:        rate.Rate,
+				FundingTime: rate.FundingTime,
+				CollectedAt: now,
+			}
+			record(r)
+			records = append(records, r)
+		}
+	}
+
+	if err := storage.AppendFunding(records); err != nil {
+		log.Printf("[FUNDING] ERROR: Failed to store funding rates: %v", err)
+	}
+	trim(now.Add(-lookback()))
+}
+
+// backfill stores the lookback's settled rates missing from the history of every pair on the venues publishing them
+func backfill(ctx context.Context, exchanges []string, pairs []string) {
+	to := time.Now()
+	from := to.Add(-lookback())
+	var records []storage.FundingRecord
+
+	for _, exchange := range exchanges {
+		for _, pairName := range pairs {
+			if !capability.CanTrade(exchange, pairName, false) {
+				continue
+			}
+
+			rates, err := clients.FundingHistory(ctx, common.ExchangeType(exchange), pairName, from, to)
+			if err != nil {
+				// Venues without a history endpoint keep collecting forward only
+				continue
+			}
+
+			historyMu.RLock()
+			known := history[key(exchange, pairName)]
+			missing := make([]storage.FundingRecord, 0, len(rates))
+			for _, rate := range rates {
+				if _, ok := known[rate.FundingTime.Unix()]; ok {
+					continue
+				}
+				missing = append(missing, storage.FundingRecord{
+					Exchange:    exchange,
+					Pair:        pairName,
+					Rate:        rate.Rate,
+					FundingTime: rate.FundingTime,
+					CollectedAt: to,
+				})
+			}
+			historyMu.RUnlock()
+
+			for _, r := range missing {
+				record(r)
+			}
+			records = append(records, missing...)
+		}
+	}
+
+	if len(records) == 0 {
+		return
+	}
+	if err := storage.AppendFunding(records); err != nil {
+		log.Printf("[FUNDING] ERROR: Failed to store backfilled funding rates: %v", err)
+		return
+	}
+	log.Printf("[FUNDING] Backfilled %d funding settlements", len(records))
+}
+
+// record keeps the latest observed rate for a settlement
+func record(r storage.FundingRecord) {
+	historyMu.Lock()
+	defer historyMu.Unlock()
+
+	k := key(r.Exchange, r.Pair)
+	if history[k] == nil {
+		history[k] = make(map[int64]float64)
+	}
+	history[k][r.FundingTime.Unix()] = r.Rate
+}
+
+// trim drops settlements older than the lookback
+func trim(cutoff time.Time) {
+	historyMu.Lock()
+	defer historyMu.Unlock()
+
+	for _, settlements := range history {
+		for t := range settlements {
+			if t < cutoff.Unix() {
+				delete(settlements, t)
+			}
+		}
+	}
+}
+
+// ShortBias returns the average funding in % per settlement a perp short on the venue received (negative = paid)
+func ShortBias(exchange, pairName string) float64 {
+	historyMu.RLock()
+	defer historyMu.RUnlock()
+
+	settlements := history[key(exchange, pairName)]
+	if len(settlements) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, rate := range settlements {
+		sum += rate
+	}
+	return sum / float64(len(settlements)) * 100.0
+}
+
+// SettledBetween returns the summed funding rate (fraction, positive when shorts receive) of the venue's
+// settlements in (from, to], as far as they were collected
+func SettledBetween(exchange, pairName string, from, to time.Time) float64 {
+	historyMu.RLock()
+	defer historyMu.RUnlock()
+
+	sum := 0.0
+	for t, rate := range history[key(exchange, pairName)] {
+		if t > from.Unix() && t <= to.Unix() {
+			sum += rate
+		}
+	}
+	return sum
+}
+
+// Snapshot returns the funding history of every venue and pair, best short bias first
+func Snapshot() []Stat {
+	historyMu.RLock()
+	stats := make([]Stat, 0, len(history))
+	for k, settlements := range history {
+		if len(settlements) == 0 {
+			continue
+		}
+		exchange, pairName, _ := strings.Cut(k, ":")
+		stat := Stat{Exchange: exchange, Pair: pairName, Settlements: len(settlements)}
+		var last int64
+		sum := 0.0
+		for t, rate := range settlements {
+			sum += rate
+			if t > last {
+				last = t
+				stat.LastRatePct = rate * 100.0
+			}
+		}
+		stat.AvgRatePct = sum / float64(len(settlements)) * 100.0
+		stat.LastTime = time.Unix(last, 0)
+		stats = append(stats, stat)
+	}
+	historyMu.RUnlock()
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Pair != stats[j].Pair {
+			return stats[i].Pair < stats[j].Pair
+		}
+		return stats[i].AvgRatePct > stats[j].AvgRatePct
+	})
+	return stats
+}
+
+// Source exposes the funding bias to the analyzer
+type Source struct{}
+
+func (Source) ShortBias(exchange, pairName string) float64 { return ShortBias(exchange, pairName) }
@@ -0,0 +1,163 @@
+package fx
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/config"
+)
+
+// Reporting currency
+//
+// Trading, fees and P&L are all in USDT. REPORT_CURRENCY (default USDT, no conversion) names the
+// operator's accounting currency, e.g. EUR or USD, which the daily report and the equity curve are
+// also given in. REPORT_FX_SOURCE sets where the USDT -> currency rate comes from:
+//
+//	fixed    REPORT_FX_RATE units of the currency per USDT (default source)
+//	binance  Binance's <CURRENCY>USDT spot price, inverted (EURUSDT, TRYUSDT, ...)
+//	kraken   Kraken's USDT<CURRENCY> spot price (USDTUSD, USDTEUR, ...)
+//	url      a JSON endpoint, REPORT_FX_URL, whose REPORT_FX_FIELD (dotted path, numbers index
+//	         arrays) holds the currency per USDT; REPORT_FX_INVERT=true when it holds USDT per unit
+//
+// A fetched rate is reused for REPORT_FX_TTL (default 1h). When a refresh fails the last rate is kept,
+// and REPORT_FX_RATE is used until one was fetched. Amounts are converted at the rate of the moment
+// they are reported, not of the day each trade closed.
+
+// Rate is the USDT -> reporting currency conversion
+type Rate struct {
+	Currency string    `json:"currency"`
+	Rate     float64   `json:"rate"` // Units of the currency per USDT
+	Source   string    `json:"source"`
+	At       time.Time `json:"at"`
+}
+
+// Convert returns a USDT amount in the reporting currency
+func (r Rate) Convert(usdt float64) float64 {
+	return usdt * r.Rate
+}
+
+var (
+	cached   Rate
+	cachedMu sync.Mutex
+	rest     = common.NewRESTClient("fx")
+)
+
+// Currency returns the reporting currency, upper case
+func Currency() string {
+	return strings.ToUpper(strings.TrimSpace(config.GetString("REPORT_CURRENCY", "USDT")))
+}
+
+// Enabled reports whether amounts are reported in a currency other than USDT
+func Enabled() bool {
+	return Currency() != "USDT"
+}
+
+// Current returns the USDT -> reporting currency rate, fetching it when the cached one is stale
+// The error is set when no usable rate is known
+func Current(ctx context.Context) (Rate, error) {
+	currency := Currency()
+	if currency == "USDT" {
+		return Rate{Currency: currency, Rate: 1, Source: "identity", At: time.Now()}, nil
+	}
+	source := strings.ToLower(config.GetString("REPORT_FX_SOURCE", "fixed"))
+
+	cachedMu.Lock()
+	defer cachedMu.Unlock()
+
+	if cached.Currency == currency && cached.Source == source && time.Since(cached.At) < config.GetDuration("REPORT_FX_TTL", time.Hour) {
+		return cached, nil
+	}
+
+	rate, err := fetch(ctx, source, currency)
+	if err == nil && rate > 0 {
+		cached = Rate{Currency: currency, Rate: rate, Source: source, At: time.Now()}
+		return cached, nil
+	}
+	if err == nil {
+		err = fmt.Errorf("non-positive rate %g", rate)
+	}
+	log.Printf("[FX] Failed to fetch USDT/%s rate from %s: %v", currency, source, err)
+
+	if cached.Currency == currency && cached.Source == source {
+		return cached, nil
+	}
+	if fallback := config.GetFloat("REPORT_FX_RATE", 0); fallback > 0 {
+		return Rate{Currency: currency, Rate: fallback, Source: "fixed", At: time.Now()}, nil
+	}
+	return Rate{}, fmt.Errorf("no USDT/%s rate: %w", currency, err)
+}
+
+// fetch returns the units of currency per USDT from the source
+func fetch(ctx context.Context, source, currency string) (float64, error) {
+	switch source {
+	case "fixed":
+		rate := config.GetFloat("REPORT_FX_RATE", 0)
+		if rate <= 0 {
+			return 0, fmt.Errorf("REPORT_FX_RATE is not set")
+		}
+		return rate, nil
+	case "binance":
+		price, err := fetchField(ctx, "https://api.binance.com/api/v3/ticker/price?symbol="+currency+"USDT", "price")
+		if err != nil || price <= 0 {
+			return 0, err
+		}
+		return 1 / price, nil
+	case "kraken":
+		pair := "USDT" + currency
+		return fetchField(ctx, "https://api.kraken.com/0/public/Ticker?pair="+pair, "result."+pair+".c.0")
+	case "url":
+		url := config.GetString("REPORT_FX_URL", "")
+		if url == "" {
+			return 0, fmt.Errorf("REPORT_FX_URL is not set")
+		}
+		rate, err := fetchField(ctx, url, config.GetString("REPORT_FX_FIELD", "rate"))
+		if err != nil || rate <= 0 || !config.GetBool("REPORT_FX_INVERT", false) {
+			return rate, err
+		}
+		return 1 / rate, nil
+	default:
+		return 0, fmt.Errorf("unknown REPORT_FX_SOURCE %q", source)
+	}
+}
+
+// fetchField reads the number at a dotted path of a JSON response, given as a number or a string
+func fetchField(ctx context.Context, url, path string) (float64, error) {
+	resp, err := rest.Get(ctx, url)
+	if err != nil {
+		return 0, err
+	}
+
+	var value interface{}
+	if err := resp.DecodeJSON(&value); err != nil {
+		return 0, err
+	}
+	for _, part := range strings.Split(path, ".") {
+		switch node := value.(type) {
+		case map[string]interface{}:
+			value = node[part]
+		case []interface{}:
+			i, err := strconv.Atoi(part)
+			if err != nil || i < 0 || i >= len(node) {
+				return 0, fmt.Errorf("no index %q in %s", part, path)
+			}
+			value = node[i]
+		default:
+			return 0, fmt.Errorf("no %q in %s", part, path)
+		}
+	}
+
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case string:
+		return strconv.ParseFloat(v, 64)
+	default:
+		return 0, fmt.Errorf("%s is not a number", path)
+	}
+}
@@ -0,0 +1,303 @@
+package inventory
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"arbitrage.trade/capability"
+	"arbitrage.trade/clients"
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/config"
+	"arbitrage.trade/orderbook"
+)
+
+// Base-asset inventory strategy
+//
+// With INVENTORY_ENABLED=true the bot keeps a standing base-asset inventory on the spot venues
+// in INVENTORY_VENUES so it can sell spot as soon as the reverse spread (spot bid > perp ask)
+// appears, hedging with a perp long. Targets are base quantities: INVENTORY_TARGET_<ASSET>, or
+// per venue INVENTORY_TARGET_<EXCHANGE>_<ASSET> (e.g. INVENTORY_TARGET_BINANCE_XRP=100).
+// Reverse trades never take a holding below target × (1 - INVENTORY_BAND_PCT/100), and every
+// INVENTORY_REBALANCE_INTERVAL holdings outside target ± INVENTORY_BAND_PCT are traded back to target.
+
+// BookSource provides the spot books inventory is marked against
+type BookSource interface {
+	GetBook(pairName, exchangeName string, isSpot bool) (*orderbook.OrderBook, bool)
+}
+
+// Holding is one venue's marked-to-market inventory of a pair's base asset
+type Holding struct {
+	Exchange   string  `json:"exchange"`
+	Pair       string  `json:"pair"`
+	Quantity   float64 `json:"quantity"`
+	Target     float64 `json:"target"`
+	Min        float64 `json:"min"`
+	Max        float64 `json:"max"`
+	AvgCost    float64 `json:"avg_cost"`
+	Price      float64 `json:"price"` // Spot mid
+	Value      float64 `json:"value"` // Quantity × Price in USDT
+	Unrealized float64 `json:"unrealized_pnl"`
+	Realized   float64 `json:"realized_pnl"`
+}
+
+type position struct {
+	quantity float64
+	avgCost  float64
+	realized float64
+}
+
+var (
+	positions   = make(map[string]*position) // exchange:pair -> tracked inventory
+	positionsMu sync.Mutex
+	books       BookSource
+)
+
+func key(exchange, pairName string) string {
+	return exchange + ":" + pairName
+}
+
+func baseAsset(pairName string) string {
+	return strings.ToUpper(strings.Split(pairName, "-")[0])
+}
+
+// Enabled reports whether the inventory strategy is on (INVENTORY_ENABLED)
+func Enabled() bool {
+	return config.GetBool("INVENTORY_ENABLED", false)
+}
+
+// IsVenue reports whether an exchange holds inventory
+func IsVenue(exchange string) bool {
+	for _, v := range strings.Split(config.GetString("INVENTORY_VENUES", ""), ",") {
+		if strings.EqualFold(strings.TrimSpace(v), exchange) {
+			return true
+		}
+	}
+	return false
+}
+
+// Limits returns the target base quantity for a venue and pair and the band it may move within
+func Limits(exchange, pairName string) (target, min, max float64) {
+	asset := baseAsset(pairName)
+	target = config.GetFloat(config.Key("INVENTORY_TARGET", exchange, asset), config.GetFloat(config.Key("INVENTORY_TARGET", asset), 0))
+	band := config.GetFloat("INVENTORY_BAND_PCT", 50) / 100
+	return target, target * (1 - band), target * (1 + band)
+}
+
+// Sellable returns the base quantity a reverse trade may sell on a venue without breaching its lower limit
+func Sellable(exchange, pairName string) float64 {
+	if !Enabled() || !IsVenue(exchange) {
+		return 0
+	}
+
+	_, min, _ := Limits(exchange, pairName)
+
+	positionsMu.Lock()
+	defer positionsMu.Unlock()
+
+	p, ok := positions[key(exchange, pairName)]
+	if !ok || p.quantity <= min {
+		return 0
+	}
+	return p.quantity - min
+}
+
+// Record books a fill against the tracked inventory (positive quantity buys, negative sells)
+func Record(exchange, pairName string, quantity, price float64) {
+	positionsMu.Lock()
+	defer positionsMu.Unlock()
+
+	p := getPosition(exchange, pairName)
+	if quantity > 0 {
+		total := p.quantity + quantity
+		p.avgCost = (p.avgCost*p.quantity + price*quantity) / total
+		p.quantity = total
+		return
+	}
+
+	p.realized += (price - p.avgCost) * -quantity
+	p.quantity += quantity
+	if p.quantity < 0 {
+		p.quantity = 0
+	}
+}
+
+// syncHolding replaces the tracked quantity with the exchange's reported holding
+func syncHolding(exchange, pairName string, quantity, price float64) {
+	positionsMu.Lock()
+	defer positionsMu.Unlock()
+
+	p := getPosition(exchange, pairName)
+	if common.IsZero(p.avgCost) {
+		p.avgCost = price
+	}
+	p.quantity = quantity
+}
+
+// getPosition returns the tracked inventory for a venue and pair. Callers must hold positionsMu.
+func getPosition(exchange, pairName string) *position {
+	k := key(exchange, pairName)
+	p, ok := positions[k]
+	if !ok {
+		p = &position{}
+		positions[k] = p
+	}
+	return p
+}
+
+// markPrice returns the spot mid on a venue, or 0 when its book is unavailable
+func markPrice(exchange, pairName string) float64 {
+	if books == nil {
+		return 0
+	}
+	book, ok := books.GetBook(pairName, exchange, true)
+	if !ok {
+		return 0
+	}
+	bid, _, bidOk := book.GetBestBid()
+	ask, _, askOk := book.GetBestAsk()
+	if !bidOk || !askOk {
+		return 0
+	}
+	return (bid + ask) / 2
+}
+
+// Snapshot returns every tracked holding marked to market
+func Snapshot() []Holding {
+	positionsMu.Lock()
+	keys := make([]string, 0, len(positions))
+	tracked := make(map[string]position, len(positions))
+	for k, p := range positions {
+		keys = append(keys, k)
+		tracked[k] = *p
+	}
+	positionsMu.Unlock()
+	sort.Strings(keys)
+
+	holdings := make([]Holding, 0, len(keys))
+	for _, k := range keys {
+		exchange, pairName, _ := strings.Cut(k, ":")
+		p := tracked[k]
+		target, min, max := Limits(exchange, pairName)
+		price := markPrice(exchange, pairName)
+
+		holding := Holding{
+			Exchange: exchange,
+			Pair:     pairName,
+			Quantity: p.quantity,
+			Target:   target,
+			Min:      min,
+			Max:      max,
+			AvgCost:  p.avgCost,
+			Price:    price,
+			Realized: p.realized,
+		}
+		if price > 0 {
+			holding.Value = p.quantity * price
+			holding.Unrealized = (price - p.avgCost) * p.quantity
+		}
+		holdings = append(holdings, holding)
+	}
+	return holdings
+}
+
+// Start syncs inventory from the exchanges and rebalances it back to target in the background
+// busy reports pairs with an open position, whose inventory is left alone until it closes
+func Start(source BookSource, exchanges []string, pairs []string, busy func(pairName string) bool) {
+	books = source
+	if !Enabled() {
+		return
+	}
+
+	var venues []string
+	for _, exchange := range exchanges {
+		if IsVenue(exchange) {
+			venues = append(venues, exchange)
+		}
+	}
+	log.Printf("[INVENTORY] Holding inventory on %s", strings.Join(venues, ", "))
+
+	interval := config.GetDuration("INVENTORY_REBALANCE_INTERVAL", 5*time.Minute)
+	go func() {
+		for {
+			rebalance(context.Background(), venues, pairs, busy)
+			if interval <= 0 {
+				return
+			}
+			time.Sleep(interval)
+		}
+	}()
+}
+
+// rebalance syncs each venue's holdings and trades those outside their band back to target
+func rebalance(ctx context.Context, venues []string, pairs []string, busy func(pairName string) bool) {
+	for _, exchange := range venues {
+		for _, pairName := range pairs {
+			target, min, max := Limits(exchange, pairName)
+			if common.IsNegativeOrZero(target) || busy(pairName) {
+				continue
+			}
+
+			holding, err := clients.SpotHolding(ctx, common.ExchangeType(exchange), pairName)
+			if err != nil {
+				log.Printf("[INVENTORY] %s %s - ERROR: Failed to get holding: %v", exchange, pairName, err)
+				continue
+			}
+
+			price := markPrice(exchange, pairName)
+			syncHolding(exchange, pairName, holding, price)
+
+			if holding >= min && holding <= max {
+				continue
+			}
+			if common.IsZero(price) {
+				log.Printf("[INVENTORY] %s %s - Out of band but no book to price the rebalance", exchange, pairName)
+				continue
+			}
+
+			command := common.BuySpotInventory
+			quantity := target - holding
+			if quantity < 0 {
+				command = common.SellSpotInventory
+				quantity = -quantity
+			}
+			if !capability.CanFill(exchange, pairName, true, price, quantity*price) {
+				continue
+			}
+
+			log.Printf("[INVENTORY] %s %s - Holding %.8f outside [%.8f, %.8f], rebalancing to %.8f",
+				exchange, pairName, holding, min, max, target)
+
+			result, err := clients.TradeInventory(ctx, common.ExchangeType(exchange), command, pairName, quantity, "rebalance", 0)
+			if err != nil {
+				log.Printf("[INVENTORY] %s %s - ERROR: Rebalance failed: %v", exchange, pairName, err)
+				continue
+			}
+			if result != nil {
+				filled := result.ExecutedQty
+				if command == common.SellSpotInventory {
+					filled = -filled
+				}
+				Record(exchange, pairName, filled, result.ExecutedPrice)
+			}
+		}
+	}
+}
+
+// Holds reports whether a venue keeps standing inventory of a pair's base asset
+func Holds(exchange, pairName string) bool {
+	if !Enabled() || !IsVenue(exchange) {
+		return false
+	}
+	target, _, _ := Limits(exchange, pairName)
+	return common.IsPositive(target)
+}
+
+// Source exposes the inventory to the analyzer
+type Source struct{}
+
+func (Source) Holds(exchange, pairName string) bool       { return Holds(exchange, pairName) }
+func (Source) Sellable(exchange, pairName string) float64 { return Sellable(exchange, pairName) }
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"arbitrage.trade/clients"
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/inventory"
+	"arbitrage.trade/notify"
+	"arbitrage.trade/orderbook"
+)
+
+// ConsiderReverseOpportunity sells spot inventory at the bid and longs the perp at the ask
+// when spot trades above the perp; the spot is bought back into inventory on close
+func ConsiderReverseOpportunity(ctx context.Context, spotExchange common.ExchangeType, spotBid float64, perpExchange common.ExchangeType,
+	perpAsk float64, pairName string, diffPercent float64, amountUSDT float64, detectedAt time.Time) {
+
+	if common.LessThan(diffPercent, orderbook.EntrySpreadPct(pairName, string(spotExchange), string(perpExchange))) {
+		return
+	}
+
+	// Vary the size so the trade's orders don't repeat the same notional on every venue
+	amountUSDT *= sizeJitter(pairName,
+		entryLeg{exchange: spotExchange, isSpot: true, price: spotBid, amountUSDT: amountUSDT},
+		entryLeg{exchange: perpExchange, price: perpAsk, amountUSDT: amountUSDT})
+
+	// The spot leg is the short side, the perp the long side
+	tag := currentStrategy()
+	ctx = common.WithStrategy(ctx, tag)
+	position := &ArbitragePosition{
+		TradeID:         common.TradeID(ctx),
+		Strategy:        tag.Strategy,
+		ConfigVersion:   tag.ConfigVersion,
+		Experiment:      tag.Experiment,
+		Variant:         tag.Variant,
+		PairName:        pairName,
+		ShortExchange:   spotExchange,
+		LongExchange:    perpExchange,
+		EntryShortPrice: spotBid,
+		EntryLongPrice:  perpAsk,
+		EntrySpread:     diffPercent,
+		CurrentSpread:   diffPercent,
+		CurrentShort:    spotBid,
+		CurrentLong:     perpAsk,
+		AmountUSDT:      amountUSDT,
+		EntryTime:       time.Now(),
+		LastLogTime:     time.Now(),
+		IsOpen:          true,
+		Reverse:         true,
+		DetectedAt:      detectedAt,
+	}
+
+	positionsMutex.Lock()
+	if _, exists := activePositions[pairName]; exists {
+		positionsMutex.Unlock()
+		log.Printf("[SKIP %s] Position already open or closing", pairName)
+		return
+	}
+	activePositions[pairName] = position
+	positionsMutex.Unlock()
+	persistPositions()
+
+	budget := newLatencyBudget(pairName, detectedAt)
+	if err := budget.stage("gate"); err != nil {
+		abandonEntry(pairName, err)
+		return
+	}
+
+	if err := guardExistingExposure(ctx, position); err != nil {
+		abandonEntry(pairName, err)
+		return
+	}
+	if err := budget.stage("presence"); err != nil {
+		abandonEntry(pairName, err)
+		return
+	}
+
+	if !clients.SupportsInventory(spotExchange) || !clients.SupportsInventory(perpExchange) {
+		abandonEntry(pairName, fmt.Errorf("%s or %s cannot trade the reverse direction", spotExchange, perpExchange))
+		return
+	}
+
+	quantity := common.QuantityForNotional(amountUSDT, spotBid, pairName)
+	if sellable := inventory.Sellable(string(spotExchange), pairName); quantity > sellable {
+		abandonEntry(pairName, fmt.Errorf("inventory on %s can only sell %.8f of %.8f", spotExchange, sellable, quantity))
+		return
+	}
+
+	// Only the perp leg spends USDT
+	if err := clients.LockCapital(ctx, perpExchange, "futures", pairName, amountUSDT); err != nil {
+		abandonEntry(pairName, err)
+		return
+	}
+	defer clients.UnlockCapital(perpExchange, "futures", pairName)
+
+	if err := budget.submitted("capital"); err != nil {
+		abandonEntry(pairName, err)
+		return
+	}
+
+	log.Printf("[OPEN REVERSE %s] %s | Sell spot: %s@%.6f | Long perp: %s@%.6f | Spread: %.2f%%",
+		pairName, position.TradeID, spotExchange, spotBid, perpExchange, perpAsk, diffPercent)
+
+	startSafetyTimer(position)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	var spotErr, perpErr error
+
+	go func() {
+		defer wg.Done()
+		var result *common.TradeResult
+		result, spotErr = clients.TradeInventory(ctx, spotExchange, common.SellSpotInventory, pairName, quantity, "open", diffPercent)
+		if spotErr != nil {
+			log.Printf("[ERROR] Failed to sell spot inventory: %v", spotErr)
+			return
+		}
+		position.addFill("open_spot", result, spotBid, false)
+		inventory.Record(string(spotExchange), pairName, -result.ExecutedQty, result.ExecutedPrice)
+
+		position.mu.Lock()
+		position.SpotQuantity = result.ExecutedQty
+		position.SpotProceeds = result.ExecutedQty*result.ExecutedPrice - result.Fee
+		position.mu.Unlock()
+		persistPositions()
+	}()
+
+	go func() {
+		defer wg.Done()
+		var result *common.TradeResult
+		result, _, perpErr = clients.Execute(ctx, perpExchange, common.PutFuturesLong, pairName, amountUSDT, diffPercent)
+		position.addFill("open_perp", result, perpAsk, true)
+		if perpErr != nil {
+			log.Printf("[ERROR] Failed to open futures long: %v", perpErr)
+		}
+	}()
+
+	wg.Wait()
+	captureBooks(position, "open", detectedAt)
+
+	if spotErr == nil && perpErr == nil {
+		log.Printf("[OPENED %s] Reverse position opened successfully, monitoring for exit...", pairName)
+		notify.Send(notify.Message{
+			Event: notify.EventTrade,
+			Title: fmt.Sprintf("Opened reverse %s", pairName),
+			Body: fmt.Sprintf("Sold spot inventory %s @ %.6f\nLong perp %s @ %.6f\nSpread: %.3f%% | Amount: %.2f USDT",
+				spotExchange, spotBid, perpExchange, perpAsk, diffPercent, amountUSDT),
+		})
+
+		if globalBooks != nil {
+			globalBooks.WatchVenues(pairName, string(spotExchange), string(perpExchange))
+		}
+
+		go hedgeWithOptions(position)
+		return
+	}
+
+	log.Printf("[FAILED %s] Could not open reverse position", pairName)
+	position.mu.Lock()
+	position.IsOpen = false
+	position.IsClosing = true
+	position.mu.Unlock()
+	persistPositions()
+
+	// Put back any inventory that was sold without its hedge
+	if spotErr == nil {
+		buyBackInventory(ctx, position, diffPercent)
+	}
+
+	go func() {
+		retryCloseLegs(position.tradeContext(), position)
+		closeUntilFlat(position.tradeContext(), position)
+	}()
+}
+
+// closeReverseLegs closes the perp long and buys the sold spot back into inventory
+// Returns the spot (sale proceeds minus buyback cost) and futures profit
+func closeReverseLegs(ctx context.Context, position *ArbitragePosition, closeSpread, closeShort, closeLong float64) (float64, float64) {
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	spotProfit := 0.00
+	futuresProfit := 0.00
+
+	go func() {
+		defer wg.Done()
+		result, profit, err := clients.Execute(ctx, position.LongExchange, common.CloseFuturesLong, position.PairName, position.AmountUSDT, closeSpread)
+		futuresProfit = profit
+		position.addFill("close_perp", result, closeLong, false)
+		if err != nil {
+			log.Printf("[ERROR] Failed to close futures long: %v", err)
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		cost := buyBackInventory(ctx, position, closeSpread)
+		if cost == nil {
+			return
+		}
+		position.addFill("close_spot", cost, closeShort, true)
+
+		position.mu.RLock()
+		proceeds := position.SpotProceeds
+		position.mu.RUnlock()
+		spotProfit = proceeds - (cost.ExecutedQty*cost.ExecutedPrice + cost.Fee)
+	}()
+
+	wg.Wait()
+	return spotProfit, futuresProfit
+}
+
+// buyBackInventory restores the spot quantity a reverse position sold
+func buyBackInventory(ctx context.Context, position *ArbitragePosition, spreadPct float64) *common.TradeResult {
+	position.mu.RLock()
+	quantity := position.SpotQuantity
+	position.mu.RUnlock()
+
+	if common.IsNegativeOrZero(quantity) {
+		return nil
+	}
+
+	result, err := clients.TradeInventory(ctx, position.ShortExchange, common.BuySpotInventory, position.PairName, quantity, "close", spreadPct)
+	if err != nil {
+		log.Printf("[ERROR] Failed to buy back spot inventory: %v", err)
+		notify.Send(notify.Message{
+			Event: notify.EventError,
+			Title: fmt.Sprintf("%s inventory buyback failed", position.PairName),
+			Body: fmt.Sprintf("%.8f %s sold on %s was not bought back: %v. The rebalancer will restore the target.",
+				quantity, position.PairName, position.ShortExchange, err),
+		})
+		return nil
+	}
+
+	inventory.Record(string(position.ShortExchange), position.PairName, result.ExecutedQty, result.ExecutedPrice)
+	return result
+}
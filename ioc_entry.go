@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"arbitrage.trade/clients"
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/config"
+)
+
+// IOC entries
+//
+// With ENTRY_MODE=ioc (default market) both legs of a forward entry are sent as limit
+// immediate-or-cancel orders at the prices the analyzer decided on, widened by ENTRY_IOC_BUFFER_PCT
+// (default 0.05): the spot buy at most that much above its route price, the perp short at least
+// that much below its bid. A leg that does not fill in full fails the entry and whatever did fill
+// is unwound, so an opened position's entry spread is never worse than computed less the buffers.
+// Entries touching a venue without IOC support, or shorting a coin-margined perp, are skipped.
+
+// iocEntries reports whether forward entries are sent as limit-IOC orders
+func iocEntries() bool {
+	return strings.ToLower(config.GetString("ENTRY_MODE", "market")) == "ioc"
+}
+
+// iocLimit widens a decision price by the IOC buffer, up for buys and down for sells
+func iocLimit(price float64, isBuy bool) float64 {
+	buffer := config.GetFloat("ENTRY_IOC_BUFFER_PCT", 0.05) / 100.0
+	if isBuy {
+		return price * (1 + buffer)
+	}
+	return price * (1 - buffer)
+}
+
+// checkIOCEntry refuses an IOC entry whose legs can't all be sent as limit-IOC orders
+func (p *ArbitragePosition) checkIOCEntry() error {
+	if p.Inverse {
+		return fmt.Errorf("IOC entry: coin-margined short on %s has no IOC path", p.ShortExchange)
+	}
+	for _, exchange := range append([]common.ExchangeType{p.ShortExchange}, routeExchanges(p.spotRoutes())...) {
+		if !clients.SupportsIOC(exchange) {
+			return fmt.Errorf("IOC entry: %s does not support IOC orders", exchange)
+		}
+	}
+	return nil
+}
+
+// legContext caps an opening leg at its IOC limit when IOC entries are on
+func legContext(ctx context.Context, price float64, isBuy bool) context.Context {
+	if !iocEntries() {
+		return ctx
+	}
+	return clients.WithLimitPrice(ctx, iocLimit(price, isBuy))
+}
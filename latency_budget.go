@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"arbitrage.trade/config"
+	"arbitrage.trade/logging"
+)
+
+// Latency budget
+//
+// LATENCY_BUDGET_MS (default 0 disables) caps the time from the analyzer detecting an opportunity
+// to its orders being submitted. Each stage of the entry path (gating, presence check, capital
+// lock) is timed, and once the budget is spent the entry is abandoned before any order goes out:
+// the book it was priced on is stale by then and a late submission mostly buys negative slippage.
+// The stage breakdown is logged with every abort.
+
+// latencyStage is how long one stage of the entry path took
+type latencyStage struct {
+	name     string
+	duration time.Duration
+}
+
+// latencyBudget times an entry's stages against the budget counted from detection
+type latencyBudget struct {
+	pair       string
+	detectedAt time.Time
+	limit      time.Duration
+	last       time.Time
+	stages     []latencyStage
+}
+
+// newLatencyBudget starts timing an entry detected at detectedAt
+func newLatencyBudget(pair string, detectedAt time.Time) *latencyBudget {
+	if detectedAt.IsZero() {
+		detectedAt = time.Now()
+	}
+	return &latencyBudget{
+		pair:       pair,
+		detectedAt: detectedAt,
+		limit:      time.Duration(config.GetInt("LATENCY_BUDGET_MS", 0)) * time.Millisecond,
+		last:       detectedAt,
+	}
+}
+
+// stage records the stage that just finished and returns an error once the budget is exhausted
+func (b *latencyBudget) stage(name string) error {
+	now := time.Now()
+	b.stages = append(b.stages, latencyStage{name: name, duration: now.Sub(b.last)})
+	b.last = now
+
+	elapsed := now.Sub(b.detectedAt)
+	if b.limit <= 0 || elapsed <= b.limit {
+		return nil
+	}
+
+	log.Printf("[LATENCY %s] Budget %dms exhausted after %s: %s", b.pair, b.limit.Milliseconds(), name, b)
+	return fmt.Errorf("latency budget exhausted: %.1fms since detection > %dms (%s)",
+		float64(elapsed.Microseconds())/1000.0, b.limit.Milliseconds(), b)
+}
+
+// submitted records the last stage before the orders are sent
+func (b *latencyBudget) submitted(name string) error {
+	if err := b.stage(name); err != nil {
+		return err
+	}
+	logging.Debugf("executor", "[LATENCY %s] Submitting after %s", b.pair, b)
+	return nil
+}
+
+// String returns the stage breakdown, e.g. "gate 0.4ms, presence 85.2ms"
+func (b *latencyBudget) String() string {
+	parts := make([]string, 0, len(b.stages))
+	for _, stage := range b.stages {
+		parts = append(parts, fmt.Sprintf("%s %.1fms", stage.name, float64(stage.duration.Microseconds())/1000.0))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"arbitrage.trade/clients"
+	"arbitrage.trade/config"
+	"arbitrage.trade/redis"
+	"arbitrage.trade/scheduler"
+	"arbitrage.trade/storage"
+)
+
+// Live PnL
+//
+// While a position is open its unrealized PnL is refreshed every LIVE_PNL_INTERVAL (default 10s, 0
+// disables): the perp leg as its venue reports it (Binance positionRisk unRealizedProfit, OKX upl), or
+// marked to its book where the venue reports none, trades on paper or the perp is coin-margined; the
+// spot leg marked to its book (best bid of a held long, best ask of a reverse buy-back). With what
+// partial closes realized and the opening fees it adds up to the position's live total, served with
+// the position in /debug/state and on GET /positions/pnl and published on arbitrage-position-pnl. A
+// total at or below the STOP_LOSS_USDT stop closes the position (see stop_loss.go).
+
+// startLivePnL registers the live PnL refresh of open positions
+func startLivePnL() {
+	interval := config.GetDuration("LIVE_PNL_INTERVAL", 10*time.Second)
+	if interval <= 0 {
+		return
+	}
+
+	scheduler.Register(scheduler.Job{
+		Name:     "live_pnl",
+		Schedule: "@every " + interval.String(),
+		Run: func(ctx context.Context, at time.Time) error {
+			positionsMutex.RLock()
+			positions := make([]*ArbitragePosition, 0, len(activePositions))
+			for _, position := range activePositions {
+				positions = append(positions, position)
+			}
+			positionsMutex.RUnlock()
+
+			for _, position := range positions {
+				position.refreshLivePnL(ctx)
+			}
+			return nil
+		},
+	})
+}
+
+// refreshLivePnL values the open position's legs and publishes the result; closing positions are skipped
+func (p *ArbitragePosition) refreshLivePnL(ctx context.Context) {
+	p.mu.RLock()
+	if !p.IsOpen || p.IsClosing {
+		p.mu.RUnlock()
+		return
+	}
+	spotFill, perpFill := p.SpotFill, p.PerpFill
+	currentShort, currentLong, currentSpread := p.CurrentShort, p.CurrentLong, p.CurrentSpread
+	realized := p.PartialSpotProfit + p.PartialFuturesProfit
+	fees := 0.0
+	for _, leg := range p.Legs {
+		if strings.HasPrefix(leg.Leg, "open_") {
+			fees += leg.Fee - leg.Rebate
+		}
+	}
+	p.mu.RUnlock()
+
+	// The short leg's book is the perp on a forward trade and the spot on a reverse one
+	pnl := storage.LivePnL{Realized: realized, Fees: fees, PerpSource: "book", At: time.Now()}
+	if p.Reverse {
+		pnl.SpotMark, pnl.PerpMark = currentShort, currentLong
+		pnl.SpotPnL = spotFill.Quantity * (spotFill.AvgPrice() - currentShort)
+		pnl.PerpPnL = perpFill.Quantity * (currentLong - perpFill.AvgPrice())
+	} else {
+		pnl.SpotMark, pnl.PerpMark = currentLong, currentShort
+		pnl.SpotPnL = spotFill.Quantity * (currentLong - spotFill.AvgPrice())
+		pnl.PerpPnL = perpFill.Quantity * (perpFill.AvgPrice() - currentShort)
+	}
+
+	if !p.Inverse {
+		reported, err := clients.PerpPnL(ctx, p.perpExchange(), p.PairName)
+		if err != nil {
+			log.Printf("[LIVE PNL %s] %s position PnL unavailable, marking to book: %v", p.PairName, p.perpExchange(), err)
+		} else if reported != nil {
+			pnl.PerpPnL, pnl.PerpMark, pnl.PerpSource = reported.UnrealizedPnL, reported.MarkPrice, "venue"
+		}
+	}
+	pnl.Total = pnl.SpotPnL + pnl.PerpPnL + pnl.Realized - pnl.Fees
+
+	p.mu.Lock()
+	p.LivePnL = &pnl
+	stop := stopLossHit(pnl.Total) && p.IsOpen && !p.IsClosing
+	p.mu.Unlock()
+
+	if stop {
+		reason := fmt.Sprintf("Stop loss: live PnL %.4f USDT at or below %.4f USDT", pnl.Total, stopLossLimit())
+		log.Printf("[CLOSE %s] Reason: %s", p.PairName, reason)
+		go closePosition(p, reason)
+	}
+
+	redis.PublishPositionPnL(redis.PositionPnL{
+		TradeID:       p.TradeID,
+		Strategy:      p.Strategy,
+		ConfigVersion: p.ConfigVersion,
+		Experiment:    p.Experiment,
+		Variant:       p.Variant,
+		Pair:          p.PairName,
+		SpotExchange:  string(p.spotExchange()),
+		PerpExchange:  string(p.perpExchange()),
+		Reverse:       p.Reverse,
+		SpotPnL:       pnl.SpotPnL,
+		PerpPnL:       pnl.PerpPnL,
+		PerpSource:    pnl.PerpSource,
+		Realized:      pnl.Realized,
+		Fees:          pnl.Fees,
+		Total:         pnl.Total,
+		EntrySpread:   p.EntrySpread,
+		CurrentSpread: currentSpread,
+		Timestamp:     pnl.At,
+	})
+}
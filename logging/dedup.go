@@ -0,0 +1,103 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"arbitrage.trade/config"
+)
+
+// Duplicate-line suppression
+//
+// Installed on the standard logger, so it covers every log line in the process. A line identical to
+// one already written within LOG_DEDUP_WINDOW (default 10s, 0 disables) is dropped; once the window
+// ends a single "repeated N times" summary is written in its place. A misbehaving exchange then
+// costs one error line plus one summary per window instead of thousands of identical lines.
+
+const timestampLayout = "2006/01/02 15:04:05 "
+
+// repeat tracks one message's window
+type repeat struct {
+	first      time.Time
+	suppressed int
+}
+
+// dedupWriter writes log lines, collapsing repeats of the same message within a window
+type dedupWriter struct {
+	mu      sync.Mutex
+	out     io.Writer
+	window  time.Duration
+	repeats map[string]*repeat
+}
+
+// Deduplicate routes the standard logger through duplicate suppression
+// The writer stamps lines itself so identical messages compare equal regardless of when they were logged
+func Deduplicate() {
+	window := config.GetDuration("LOG_DEDUP_WINDOW", 10*time.Second)
+	if window <= 0 {
+		return
+	}
+
+	w := &dedupWriter{out: os.Stderr, window: window, repeats: make(map[string]*repeat)}
+	log.SetFlags(0)
+	log.SetOutput(w)
+
+	go func() {
+		ticker := time.NewTicker(window)
+		defer ticker.Stop()
+		for now := range ticker.C {
+			w.flush(now)
+		}
+	}()
+}
+
+func (w *dedupWriter) Write(p []byte) (int, error) {
+	now := time.Now()
+	message := string(p)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if r, ok := w.repeats[message]; ok && now.Sub(r.first) < w.window {
+		r.suppressed++
+		return len(p), nil
+	}
+
+	// The previous window of this message ended; summarize it before starting a new one
+	if r, ok := w.repeats[message]; ok {
+		w.writeSummary(now, message, r)
+	}
+	w.repeats[message] = &repeat{first: now}
+
+	if _, err := io.WriteString(w.out, now.Format(timestampLayout)+message); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// flush summarizes and drops every message whose window has ended
+func (w *dedupWriter) flush(now time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for message, r := range w.repeats {
+		if now.Sub(r.first) < w.window {
+			continue
+		}
+		w.writeSummary(now, message, r)
+		delete(w.repeats, message)
+	}
+}
+
+// writeSummary writes the "repeated N times" line of a window with suppressed lines
+func (w *dedupWriter) writeSummary(now time.Time, message string, r *repeat) {
+	if r.suppressed == 0 {
+		return
+	}
+	fmt.Fprintf(w.out, "%s[LOG] last message repeated %d times in %s: %s",
+		now.Format(timestampLayout), r.suppressed, now.Sub(r.first).Round(time.Second), message)
+}
@@ -0,0 +1,135 @@
+package logging
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+
+	"arbitrage.trade/config"
+)
+
+// Per-module log levels
+//
+// Each module logs through Debugf/Infof/Warnf/Errorf with its name (orderbook, analyzer, executor,
+// binance, ...). A module's level comes from, in order: a runtime override set through the control API
+// (POST /control/log-level?module=binance&level=debug), LOG_LEVEL_<MODULE>, LOG_LEVEL (default info).
+// Levels: debug, info, warn, error.
+
+// Level is a log verbosity
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+var levelNames = map[Level]string{
+	LevelDebug: "debug",
+	LevelInfo:  "info",
+	LevelWarn:  "warn",
+	LevelError: "error",
+}
+
+func (l Level) String() string {
+	return levelNames[l]
+}
+
+// ParseLevel parses "debug", "info", "warn" or "error"
+func ParseLevel(s string) (Level, error) {
+	for level, name := range levelNames {
+		if strings.EqualFold(strings.TrimSpace(s), name) {
+			return level, nil
+		}
+	}
+	return LevelInfo, fmt.Errorf("unknown log level %q", s)
+}
+
+var (
+	overrides = make(map[string]Level) // module -> runtime level
+	modules   = make(map[string]bool)  // modules that have logged, for listing
+	mu        sync.RWMutex
+)
+
+// LevelOf returns the module's effective level
+func LevelOf(module string) Level {
+	mu.RLock()
+	level, ok := overrides[module]
+	mu.RUnlock()
+	if ok {
+		return level
+	}
+
+	if v := config.GetString(config.Key("LOG_LEVEL", module), ""); v != "" {
+		if level, err := ParseLevel(v); err == nil {
+			return level
+		}
+	}
+	level, _ = ParseLevel(config.GetString("LOG_LEVEL", "info"))
+	return level
+}
+
+// SetLevel overrides a module's level until cleared or the process restarts
+func SetLevel(module string, level Level) {
+	mu.Lock()
+	overrides[module] = level
+	modules[module] = true
+	mu.Unlock()
+	log.Printf("[LOGGING] %s log level set to %s", module, level)
+}
+
+// ClearLevel drops a module's runtime override, falling back to the environment
+func ClearLevel(module string) {
+	mu.Lock()
+	delete(overrides, module)
+	mu.Unlock()
+	log.Printf("[LOGGING] %s log level override cleared (now %s)", module, LevelOf(module))
+}
+
+// Enabled reports whether the module logs at the level
+func Enabled(module string, level Level) bool {
+	return level >= LevelOf(module)
+}
+
+// Levels returns the effective level of every module seen so far
+func Levels() map[string]string {
+	mu.RLock()
+	names := make([]string, 0, len(modules))
+	for module := range modules {
+		names = append(names, module)
+	}
+	mu.RUnlock()
+	sort.Strings(names)
+
+	levels := make(map[string]string, len(names))
+	for _, module := range names {
+		levels[module] = LevelOf(module).String()
+	}
+	return levels
+}
+
+func logf(module string, level Level, format string, args ...interface{}) {
+	mu.RLock()
+	seen := modules[module]
+	mu.RUnlock()
+	if !seen {
+		mu.Lock()
+		modules[module] = true
+		mu.Unlock()
+	}
+
+	if Enabled(module, level) {
+		log.Printf(format, args...)
+	}
+}
+
+func Debugf(module, format string, args ...interface{}) { logf(module, LevelDebug, format, args...) }
+
+func Infof(module, format string, args ...interface{}) { logf(module, LevelInfo, format, args...) }
+
+func Warnf(module, format string, args ...interface{}) { logf(module, LevelWarn, format, args...) }
+
+func Errorf(module, format string, args ...interface{}) { logf(module, LevelError, format, args...) }
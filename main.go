@@ -7,9 +7,23 @@ import (
 	"os"
 	"time"
 
+	"arbitrage.trade/api"
+	"arbitrage.trade/backtest"
+	"arbitrage.trade/capability"
+	"arbitrage.trade/chaos"
+	"arbitrage.trade/clients"
 	"arbitrage.trade/clients/common"
+	"arbitrage.trade/config"
+	"arbitrage.trade/fees"
+	"arbitrage.trade/funding"
+	"arbitrage.trade/inventory"
+	"arbitrage.trade/logging"
 	"arbitrage.trade/orderbook"
+	"arbitrage.trade/outage"
 	"arbitrage.trade/redis"
+	"arbitrage.trade/report"
+	"arbitrage.trade/risk"
+	"arbitrage.trade/webhook"
 	"github.com/gorilla/websocket"
 	"github.com/joho/godotenv"
 	"github.com/vmihailenco/msgpack/v5"
@@ -67,6 +81,9 @@ var supportedExchanges = map[string]bool{
 	"whitebit": true,
 	// "gate":     true,
 	"okx": true,
+	// "kraken":   true,
+	// "cryptocom": true,
+	// "hyperliquid": true,
 }
 
 func getReliability(p PairExchange) Reliability {
@@ -101,6 +118,13 @@ func main() {
 		log.Println("⚠️  No .env file found, using default values")
 	}
 
+	// Collapse identical log lines flooding in from a misbehaving exchange
+	logging.Deduplicate()
+
+	if runCommand(os.Args[1:]) {
+		return
+	}
+
 	// Get WebSocket URL from environment variable
 	orderbookSignalURL = os.Getenv("SIGNAL_WS_URL")
 	if orderbookSignalURL == "" {
@@ -115,13 +139,24 @@ func main() {
 
 	// Initialize Redis for trade notifications
 	if err := redis.InitRedis(); err != nil {
-		log.Println("⚠️  Redis unavailable - trade notifications disabled")
+		log.Println("⚠️  Redis unavailable - trade events are spooled until it recovers (REDIS_SPOOL_MAX_EVENTS=0 disables)")
 	}
 	defer redis.CloseRedis()
 
+	if webhook.Enabled() {
+		log.Println("🪝 Trade events will also be delivered to WEBHOOK_URLS")
+	}
+
+	// Scheduled paper venue outages (PAPER_OUTAGES) are timed from startup
+	outage.Start()
+
+	if chaos.Enabled() {
+		log.Println("🐒 CHAOS_ENABLED - injecting order latency, rejects and feed gaps")
+	}
+
 	// Initialize global orderbook manager
 	log.Println("📊 Initializing orderbook manager...")
-	obManager := orderbook.NewGlobalManager(orderbookSignalURL)
+	obManager := orderbook.NewGlobalManager(orderbook.NewSignalSource(orderbookSignalURL))
 
 	// Add trading pairs to monitor
 	tradingPairs := []string{
@@ -145,6 +180,53 @@ func main() {
 		}
 	}
 
+	// Load which venues list each pair on spot and perp before analysis starts
+	venues := make([]string, 0, len(supportedExchanges))
+	for exchange, enabled := range supportedExchanges {
+		if enabled {
+			venues = append(venues, exchange)
+		}
+	}
+	log.Println("🗂️  Loading exchange capabilities...")
+	capability.Start(venues, tradingPairs)
+
+	// One report of what each pair and venue is missing before any order can be placed
+	if problems := checkReadiness(tradingPairs, venues); problems > 0 && config.GetBool("READINESS_STRICT", false) {
+		log.Fatalf("❌ Startup self-check found %d problem(s), see the readiness report", problems)
+	}
+
+	// Open pooled exchange connections before the first order needs them
+	if !clients.IsPaperTrading() {
+		// Fail fast on keys missing a trading scope rather than with one leg filled
+		if config.GetBool("API_PERMISSION_CHECK", true) {
+			log.Println("🔑 Checking API key permissions...")
+			if err := clients.CheckAPIPermissions(context.Background(), venues); err != nil {
+				log.Fatalf("❌ %v", err)
+			}
+		}
+
+		log.Println("🔌 Prewarming exchange connections...")
+		clients.PrewarmConnections(venues)
+
+		// Flag venues where deposits/withdrawals are suspended and funds could get stuck
+		clients.StartWalletChecks(venues, tradingPairs)
+
+		// Alert on venues whose free USDT falls below its floor before orders start failing
+		clients.StartBalanceChecks(venues)
+
+		// Work out USDT transfers between venues for the operator (REBALANCE_MODE=plan)
+		clients.StartRebalancePlanner(venues)
+	}
+
+	// Live books price maker orders and back paper fills
+	clients.SetBookSource(obManager)
+	if clients.IsPaperTrading() {
+		log.Println("🧪 PAPER_TRADING enabled - orders are simulated against live orderbooks")
+	}
+
+	// Daily P&L report from the closed-trade log
+	report.StartDailyReport()
+
 	log.Println("✅ Orderbook manager started for all pairs")
 	log.Println("💡 Each pair has separate WebSocket connections for spot and perpetual")
 
@@ -156,16 +238,101 @@ func main() {
 
 	// Set global analyzer reference for resetting execution flag after trades
 	globalAnalyzer = analyzer
+	globalBooks = obManager
+
+	// Where opportunities show up, for external dashboards
+	analyzer.StartHeatmapExport()
+
+	// Operator API (leaderboard and controls)
+	api.SetBookSource(obManager)
+	api.SetHeatmapSource(analyzer)
+	api.SetPositionSource(positionSource{})
+	api.SetPortfolioSource(portfolioSource{})
+	api.Start()
+
+	// Standing spot inventory enables the reverse direction (sell spot, long perp)
+	if inventory.Enabled() {
+		analyzer.SetInventorySource(inventory.Source{})
+	}
+	inventory.Start(obManager, venues, tradingPairs, func(pairName string) bool {
+		positionsMutex.RLock()
+		defer positionsMutex.RUnlock()
+		_, busy := activePositions[pairName]
+		return busy
+	})
+
+	risk.StartVolatilitySampling(obManager, venues, tradingPairs)
+	backtest.StartRecorder(obManager, venues, tradingPairs)
+	risk.StartStateWatch()
+
+	// Funding history biases perp venue selection towards venues paying shorts
+	funding.Start(venues, tradingPairs)
+	analyzer.SetFundingSource(funding.Source{})
+
+	// The fee model follows each venue's 30-day volume tier
+	fees.Start(venues, tradingPairs)
+	analyzer.SetFeeSource(fees.Source{})
+	clients.SetFeeSource(fees.Source{})
+
+	// Per-pair capital budgets cap how much of each pair a position may take
+	analyzer.SetBudgetSource(risk.BudgetSource{})
 
 	// Set up price update callback for position tracking
 	analyzer.SetPriceUpdateCallback(func(pairName string, shortExchange string, shortPrice float64, longExchange string, longPrice float64) {
 		UpdatePrices(pairName, shortExchange, shortPrice, longExchange, longPrice)
 	})
 
+	// Pick up positions that were open or closing when the process last stopped
+	resumePositions()
+
+	// Exchange-reported and book-marked PnL of open positions while they are held
+	startLivePnL()
+
+	// Random split of forward entries between two execution playbooks
+	startExperiment()
+
+	// Net delta per asset and gross notional per venue across positions and inventory
+	startPortfolio()
+
 	// Set up execution callback for live trading
 	analyzer.SetExecutionCallback(func(ctx context.Context, opp *orderbook.Opportunity) bool {
-		log.Printf("🚀 EXECUTING TRADE: %s | Spot: %s @ $%.6f | Perp: %s @ $%.6f | Spread: %.2f%% | Volume: $%.2f",
-			opp.Pair, opp.SpotExchange, opp.SpotAskPrice, opp.PerpExchange, opp.PerpBidPrice, opp.SpreadPct, opp.UsableVolumeUSD)
+		// Pauses (operator, circuit breaker, maintenance calendar, kill switch), the volatility
+		// regime, low venue balances, venue entry shutdowns and diverged venue combinations gate new
+		// entries only; open positions keep being managed
+		if err := risk.EntryAllowed(opp.Pair); err != nil {
+			return false
+		}
+		if clients.BalanceBlock(opp.SpotExchange, opp.PerpExchange) != "" || clients.RejectBlock(opp.SpotExchange, opp.PerpExchange) != "" ||
+			clients.EntryBlock(opp.SpotExchange, opp.PerpExchange) != "" || clients.DivergenceBlock(opp.SpotExchange, opp.PerpExchange) != "" {
+			return false
+		}
+		for _, route := range opp.SpotRoutes {
+			if clients.BalanceBlock(route.Exchange) != "" || clients.RejectBlock(route.Exchange) != "" || clients.EntryBlock(route.Exchange) != "" ||
+				clients.DivergenceBlock(route.Exchange, opp.PerpExchange) != "" {
+				return false
+			}
+		}
+
+		// Reverse: sell spot inventory, buy perp (long)
+		if opp.Reverse {
+			log.Printf("🚀 EXECUTING REVERSE TRADE: %s %s | Spot: %s @ $%.6f | Perp: %s @ $%.6f | Spread: %.2f%% | Volume: $%.2f",
+				opp.Pair, opp.ID, opp.SpotExchange, opp.SpotBidPrice, opp.PerpExchange, opp.PerpAskPrice, opp.SpreadPct, opp.UsableVolumeUSD)
+			ConsiderReverseOpportunity(
+				ctx,
+				common.ExchangeType(opp.SpotExchange),
+				opp.SpotBidPrice,
+				common.ExchangeType(opp.PerpExchange),
+				opp.PerpAskPrice,
+				opp.Pair,
+				opp.SpreadPct,
+				opp.UsableVolumeUSD,
+				opp.Timestamp,
+			)
+			return true
+		}
+
+		log.Printf("🚀 EXECUTING TRADE: %s %s | Spot: %s @ $%.6f | Perp: %s @ $%.6f | Spread: %.2f%% | Funding bias: %+.4f%% | Volume: $%.2f",
+			opp.Pair, opp.ID, opp.SpotExchange, opp.SpotAskPrice, opp.PerpExchange, opp.PerpBidPrice, opp.SpreadPct, opp.FundingBias, opp.UsableVolumeUSD)
 
 		// Execute the arbitrage trade
 		// Buy spot (long), sell perp (short)
@@ -178,6 +345,8 @@ func main() {
 			opp.Pair,
 			opp.SpreadPct,
 			opp.UsableVolumeUSD, // Use the synchronized volume from orderbook analysis
+			opp.Timestamp,
+			opp.SpotRoutes, // Spot buy split across venues, nil for a single venue
 		)
 
 		return true // Trade executed successfully
@@ -202,7 +371,9 @@ func main() {
 	}
 	defer conn.Close()
 
-	conn.SetReadLimit(1 << 20)
+	limits := common.WSLimitsFor("main")
+	conn.SetReadLimit(limits.ReadLimit)
+	guard := limits.NewRateGuard()
 
 	// Cleanup on exit
 	defer obManager.StopAll()
@@ -213,6 +384,10 @@ func main() {
 			log.Println("Read error:", err)
 			break
 		}
+		if err := guard.Received(time.Now()); err != nil {
+			log.Println("Feed dropped:", err)
+			break
+		}
 
 		var parsed map[string]interface{}
 
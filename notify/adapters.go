@@ -0,0 +1,97 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+func postJSON(ctx context.Context, url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("status %d: %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
+
+var eventEmoji = map[Event]string{
+	EventError:   "🚨",
+	EventTrade:   "🔁",
+	EventSummary: "💰",
+	EventAlert:   "⚠️",
+}
+
+// Telegram posts to a chat through the Bot API
+type Telegram struct {
+	Token  string
+	ChatID string
+}
+
+func (t *Telegram) Name() string { return "telegram" }
+
+func (t *Telegram) Notify(ctx context.Context, msg Message) error {
+	return postJSON(ctx, "https://api.telegram.org/bot"+t.Token+"/sendMessage", map[string]interface{}{
+		"chat_id": t.ChatID,
+		"text":    fmt.Sprintf("%s %s\n\n%s", eventEmoji[msg.Event], msg.Title, msg.Body),
+	})
+}
+
+// Discord posts to a channel webhook as an embed
+type Discord struct {
+	WebhookURL string
+}
+
+var discordColors = map[Event]int{
+	EventError:   0xE74C3C,
+	EventTrade:   0x3498DB,
+	EventSummary: 0x2ECC71,
+	EventAlert:   0xF1C40F,
+}
+
+func (d *Discord) Name() string { return "discord" }
+
+func (d *Discord) Notify(ctx context.Context, msg Message) error {
+	return postJSON(ctx, d.WebhookURL, map[string]interface{}{
+		"embeds": []map[string]interface{}{{
+			"title":       fmt.Sprintf("%s %s", eventEmoji[msg.Event], msg.Title),
+			"description": "```\n" + msg.Body + "\n```",
+			"color":       discordColors[msg.Event],
+		}},
+	})
+}
+
+// Slack posts to an incoming webhook
+type Slack struct {
+	WebhookURL string
+}
+
+func (s *Slack) Name() string { return "slack" }
+
+func (s *Slack) Notify(ctx context.Context, msg Message) error {
+	return postJSON(ctx, s.WebhookURL, map[string]interface{}{
+		"text": fmt.Sprintf("%s *%s*\n```%s```", eventEmoji[msg.Event], msg.Title, msg.Body),
+	})
+}
@@ -0,0 +1,124 @@
+package notify
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"arbitrage.trade/config"
+)
+
+// Event is the kind of notification, used to route messages to channels
+type Event string
+
+const (
+	EventError   Event = "error"   // Failed legs, unconfirmed flats, anything needing a human
+	EventTrade   Event = "trade"   // Positions opened and closed
+	EventSummary Event = "summary" // P&L summaries and reports
+	EventAlert   Event = "alert"   // Threshold alerts (balances, health)
+)
+
+var allEvents = []Event{EventError, EventTrade, EventSummary, EventAlert}
+
+// Message is a notification independent of the destination's formatting
+type Message struct {
+	Event Event
+	Title string
+	Body  string
+}
+
+// Notifier delivers messages to one destination (a chat, channel or webhook)
+type Notifier interface {
+	Name() string
+	Notify(ctx context.Context, msg Message) error
+}
+
+// Routing
+//
+// Adapters are enabled by their credentials: TELEGRAM_BOT_TOKEN + TELEGRAM_CHAT_ID,
+// DISCORD_WEBHOOK_URL, SLACK_WEBHOOK_URL. Each destination can be overridden per
+// event, e.g. DISCORD_WEBHOOK_URL_ERROR or TELEGRAM_CHAT_ID_SUMMARY, so errors and
+// P&L go to different channels. NOTIFY_<EVENT> (e.g. NOTIFY_TRADE=slack) restricts
+// which adapters receive an event; by default every configured adapter does.
+
+var (
+	routes     map[Event][]Notifier
+	routesOnce sync.Once
+)
+
+// adapters build a notifier for one event, or nil when the adapter isn't configured
+var adapters = map[string]func(event Event) Notifier{
+	"telegram": func(event Event) Notifier {
+		token := config.GetString("TELEGRAM_BOT_TOKEN", "")
+		chatID := config.GetString(config.Key("TELEGRAM_CHAT_ID", string(event)), config.GetString("TELEGRAM_CHAT_ID", ""))
+		if token == "" || chatID == "" {
+			return nil
+		}
+		return &Telegram{Token: token, ChatID: chatID}
+	},
+	"discord": func(event Event) Notifier {
+		url := config.GetString(config.Key("DISCORD_WEBHOOK_URL", string(event)), config.GetString("DISCORD_WEBHOOK_URL", ""))
+		if url == "" {
+			return nil
+		}
+		return &Discord{WebhookURL: url}
+	},
+	"slack": func(event Event) Notifier {
+		url := config.GetString(config.Key("SLACK_WEBHOOK_URL", string(event)), config.GetString("SLACK_WEBHOOK_URL", ""))
+		if url == "" {
+			return nil
+		}
+		return &Slack{WebhookURL: url}
+	},
+}
+
+func buildRoutes() map[Event][]Notifier {
+	built := make(map[Event][]Notifier)
+
+	for _, event := range allEvents {
+		names := config.GetString(config.Key("NOTIFY", string(event)), "telegram,discord,slack")
+		for _, name := range strings.Split(names, ",") {
+			build, ok := adapters[strings.TrimSpace(strings.ToLower(name))]
+			if !ok {
+				continue
+			}
+			if n := build(event); n != nil {
+				built[event] = append(built[event], n)
+			}
+		}
+	}
+
+	return built
+}
+
+func getRoutes() map[Event][]Notifier {
+	routesOnce.Do(func() {
+		routes = buildRoutes()
+		for _, event := range allEvents {
+			if len(routes[event]) > 0 {
+				names := make([]string, 0, len(routes[event]))
+				for _, n := range routes[event] {
+					names = append(names, n.Name())
+				}
+				log.Printf("[NOTIFY] %s -> %s", event, strings.Join(names, ", "))
+			}
+		}
+	})
+	return routes
+}
+
+// Send delivers a message to every notifier routed for its event in the background
+func Send(msg Message) {
+	for _, n := range getRoutes()[msg.Event] {
+		go func(n Notifier) {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			if err := n.Notify(ctx, msg); err != nil {
+				log.Printf("[NOTIFY] %s - ERROR: Failed to send %s: %v", n.Name(), msg.Event, err)
+			}
+		}(n)
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"arbitrage.trade/clients"
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/config"
+	"arbitrage.trade/notify"
+)
+
+// Protective options (advanced mode)
+//
+// With OPTIONS_HEDGE_ENABLED=true, positions of at least OPTIONS_HEDGE_MIN_AMOUNT USDT buy a
+// short-dated option on OPTIONS_HEDGE_EXCHANGE (default okx) while the spot/perp hedge is open,
+// capping the loss if a sharp move liquidates the perp leg before the spot leg can follow.
+// Forward positions (short perp) buy a call, reverse positions (long perp) buy a put, struck at
+// least OPTIONS_HEDGE_OTM_PCT out of the money and expiring within OPTIONS_HEDGE_MAX_EXPIRY.
+// The option is skipped when its premium exceeds OPTIONS_HEDGE_MAX_PREMIUM_PCT of the position,
+// and sold back when the position closes.
+
+// optionsHedgeEnabled reports whether protective options are bought for large positions
+func optionsHedgeEnabled() bool {
+	return config.GetBool("OPTIONS_HEDGE_ENABLED", false)
+}
+
+// hedgeWithOptions buys a protective option for an opened position when it is large enough
+func hedgeWithOptions(position *ArbitragePosition) {
+	if !optionsHedgeEnabled() {
+		return
+	}
+	if position.AmountUSDT < config.GetFloat("OPTIONS_HEDGE_MIN_AMOUNT", 5000) {
+		return
+	}
+
+	ctx := position.tradeContext()
+	exchange := common.ExchangeType(config.GetString("OPTIONS_HEDGE_EXCHANGE", string(common.Okx)))
+
+	optionType := common.Call
+	if position.Reverse {
+		optionType = common.Put
+	}
+
+	quote, err := clients.QuoteProtectiveOption(ctx, exchange, position.PairName, optionType, position.AmountUSDT,
+		config.GetFloat("OPTIONS_HEDGE_OTM_PCT", 5), config.GetDuration("OPTIONS_HEDGE_MAX_EXPIRY", 48*time.Hour))
+	if err != nil {
+		log.Printf("[OPTIONS %s] No protective %s on %s: %v", position.PairName, optionType, exchange, err)
+		return
+	}
+
+	maxPremium := position.AmountUSDT * config.GetFloat("OPTIONS_HEDGE_MAX_PREMIUM_PCT", 0.3) / 100
+	if quote.Premium > maxPremium {
+		log.Printf("[OPTIONS %s] Skipping %s: premium %.2f USDT above limit %.2f USDT",
+			position.PairName, quote.Instrument, quote.Premium, maxPremium)
+		return
+	}
+
+	result, err := clients.TradeOption(ctx, exchange, position.PairName, quote, "open", quote.Contracts)
+	if err != nil {
+		log.Printf("[OPTIONS %s] ERROR: Failed to buy %s: %v", position.PairName, quote.Instrument, err)
+		return
+	}
+
+	position.mu.Lock()
+	if !position.IsOpen {
+		// The position closed while the order was in flight, nothing left to protect
+		position.mu.Unlock()
+		log.Printf("[OPTIONS %s] Position closed before %s filled, selling it back", position.PairName, quote.Instrument)
+		sellOption(ctx, position.PairName, exchange, quote, result)
+		return
+	}
+	position.Option = quote
+	position.OptionExchange = exchange
+	position.OptionFill = result
+	position.mu.Unlock()
+	persistPositions()
+
+	log.Printf("[OPTIONS %s] Bought %.4g %s (strike %.2f, expires %s) for %.2f USDT, covering %.2f USDT",
+		position.PairName, result.ExecutedQty, quote.Instrument, quote.Strike, quote.Expiry.Format(time.RFC3339),
+		result.ExecutedQty*result.ExecutedPrice+result.Fee, quote.Covered)
+}
+
+// closeOptionHedge sells a position's protective option and returns its profit (proceeds minus cost)
+func closeOptionHedge(ctx context.Context, position *ArbitragePosition) float64 {
+	position.mu.Lock()
+	quote, exchange, bought := position.Option, position.OptionExchange, position.OptionFill
+	position.Option, position.OptionFill = nil, nil
+	position.mu.Unlock()
+	persistPositions()
+
+	if quote == nil || bought == nil {
+		return 0
+	}
+	return sellOption(ctx, position.PairName, exchange, quote, bought)
+}
+
+// sellOption sells back a bought option and returns proceeds minus the premium and fees paid
+func sellOption(ctx context.Context, pairName string, exchange common.ExchangeType, quote *common.OptionQuote, bought *common.TradeResult) float64 {
+	cost := bought.ExecutedQty*bought.ExecutedPrice + bought.Fee
+
+	sold, err := clients.TradeOption(ctx, exchange, pairName, quote, "close", bought.ExecutedQty)
+	if err != nil {
+		log.Printf("[OPTIONS %s] ERROR: Failed to sell %s: %v", pairName, quote.Instrument, err)
+		notify.Send(notify.Message{
+			Event: notify.EventError,
+			Title: fmt.Sprintf("%s protective option not sold", pairName),
+			Body: fmt.Sprintf("%.4g %s on %s is still held: %v. It expires %s.",
+				bought.ExecutedQty, quote.Instrument, exchange, err, quote.Expiry.Format(time.RFC3339)),
+		})
+		return -cost
+	}
+
+	profit := sold.ExecutedQty*sold.ExecutedPrice - sold.Fee - cost
+	log.Printf("[OPTIONS %s] Sold %.4g %s, hedge P&L %+.4f USDT", pairName, sold.ExecutedQty, quote.Instrument, profit)
+	return profit
+}
@@ -0,0 +1,150 @@
+package orderbook
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"arbitrage.trade/clients/common"
+)
+
+// AggregatedBook is one pair's depth consolidated across every venue's signal-feed book.
+// Each level keeps the volume every venue quotes there, so monitoring can see where
+// liquidity sits and an order router can split a size across venues.
+type AggregatedBook struct {
+	Pair      string                `json:"pair"`
+	Market    string                `json:"market"` // spot or perp
+	Bids      []AggregatedLevel     `json:"bids"`   // Highest first
+	Asks      []AggregatedLevel     `json:"asks"`   // Lowest first
+	Venues    map[string]VenueState `json:"venues"` // Books merged into the view
+	Timestamp int64                 `json:"timestamp"`
+}
+
+// AggregatedLevel is one consolidated price level
+type AggregatedLevel struct {
+	Price  float64            `json:"price"`
+	Volume float64            `json:"volume"` // USDT across venues
+	Venues map[string]float64 `json:"venues"` // exchange -> USDT at this level
+}
+
+// VenueState describes one venue book merged into an aggregated view
+type VenueState struct {
+	BestBid      float64 `json:"best_bid"`
+	BestAsk      float64 `json:"best_ask"`
+	Latency      float64 `json:"latency"`
+	LastUpdateTs int64   `json:"last_update_ts"`
+}
+
+// AggregateOptions controls how venue books are merged
+type AggregateOptions struct {
+	Depth  int           // Levels kept per side, 0 for all
+	Tick   float64       // Bucket size prices are grouped into (bids floored, asks ceiled), 0 groups exact prices
+	MaxAge time.Duration // Books not updated within MaxAge are left out, 0 keeps every book
+}
+
+// Aggregate merges every venue's book into one consolidated view
+func (eob *ExchangeOrderBooks) Aggregate(pairName, market string, opts AggregateOptions) *AggregatedBook {
+	eob.mu.RLock()
+	books := make(map[string]*OrderBook, len(eob.OrderBooks))
+	for exchange, ob := range eob.OrderBooks {
+		books[exchange] = ob
+	}
+	eob.mu.RUnlock()
+
+	now := time.Now()
+	bids := make(map[float64]*AggregatedLevel)
+	asks := make(map[float64]*AggregatedLevel)
+	venues := make(map[string]VenueState)
+
+	for exchange, ob := range books {
+		bidLevels, askLevels, updated := ob.GetSnapshot()
+		if opts.MaxAge > 0 && ob.Age() > opts.MaxAge {
+			continue
+		}
+		if len(bidLevels) == 0 && len(askLevels) == 0 {
+			continue
+		}
+
+		state := VenueState{LastUpdateTs: updated.UnixMilli()}
+		ob.mu.RLock()
+		state.Latency = ob.Latency
+		ob.mu.RUnlock()
+		if len(bidLevels) > 0 {
+			state.BestBid = bidLevels[0].Price
+		}
+		if len(askLevels) > 0 {
+			state.BestAsk = askLevels[0].Price
+		}
+		venues[exchange] = state
+
+		for _, level := range bidLevels {
+			addLevel(bids, exchange, bucketPrice(level.Price, opts.Tick, math.Floor), level.Quantity)
+		}
+		for _, level := range askLevels {
+			addLevel(asks, exchange, bucketPrice(level.Price, opts.Tick, math.Ceil), level.Quantity)
+		}
+	}
+
+	return &AggregatedBook{
+		Pair:      pairName,
+		Market:    market,
+		Bids:      sortedLevels(bids, true, opts.Depth),
+		Asks:      sortedLevels(asks, false, opts.Depth),
+		Venues:    venues,
+		Timestamp: now.UnixMilli(),
+	}
+}
+
+// bucketPrice rounds a price onto the tick grid, leaving it unchanged without a tick
+func bucketPrice(price, tick float64, round func(float64) float64) float64 {
+	if !common.IsPositive(tick) {
+		return price
+	}
+	return round(price/tick) * tick
+}
+
+func addLevel(levels map[float64]*AggregatedLevel, exchange string, price, volume float64) {
+	level, ok := levels[price]
+	if !ok {
+		level = &AggregatedLevel{Price: price, Venues: make(map[string]float64)}
+		levels[price] = level
+	}
+	level.Volume += volume
+	level.Venues[exchange] += volume
+}
+
+// sortedLevels orders levels best first and keeps the top depth
+func sortedLevels(levels map[float64]*AggregatedLevel, isBid bool, depth int) []AggregatedLevel {
+	sorted := make([]AggregatedLevel, 0, len(levels))
+	for _, level := range levels {
+		sorted = append(sorted, *level)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if isBid {
+			return sorted[i].Price > sorted[j].Price
+		}
+		return sorted[i].Price < sorted[j].Price
+	})
+
+	if depth > 0 && len(sorted) > depth {
+		sorted = sorted[:depth]
+	}
+	return sorted
+}
+
+// AggregatedBook consolidates the pair's spot or perp books across venues
+func (pm *PairManager) AggregatedBook(isSpot bool, opts AggregateOptions) *AggregatedBook {
+	if isSpot {
+		return pm.spotBooks.Aggregate(pm.pairName, "spot", opts)
+	}
+	return pm.perpBooks.Aggregate(pm.pairName, "perp", opts)
+}
+
+// AggregatedBook consolidates a pair's spot or perp books across venues
+func (gm *GlobalManager) AggregatedBook(pairName string, isSpot bool, opts AggregateOptions) (*AggregatedBook, bool) {
+	pm, exists := gm.GetPairManager(pairName)
+	if !exists {
+		return nil, false
+	}
+	return pm.AggregatedBook(isSpot, opts), true
+}
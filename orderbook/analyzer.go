@@ -3,19 +3,51 @@ package orderbook
 import (
 	"context"
 	"fmt"
+	"math"
 	"os"
+	"sort"
 	"sync"
 	"time"
 
+	"arbitrage.trade/capability"
 	"arbitrage.trade/clients/common"
+	"arbitrage.trade/config"
+	"arbitrage.trade/logging"
 )
 
 // OpportunityCallback is called when a valid arbitrage opportunity is found
 type OpportunityCallback func(ctx context.Context, opp *Opportunity) bool
 
 // PriceUpdateCallback is called on each price update for position tracking
+// shortPrice and longPrice are what closing each leg would execute at (see feedExitPrices)
 type PriceUpdateCallback func(pairName string, shortExchange string, shortPrice float64, longExchange string, longPrice float64)
 
+// InventorySource reports the standing spot inventory venues hold for the reverse direction
+type InventorySource interface {
+	// Holds reports whether a venue keeps inventory of the pair's base asset
+	Holds(exchange, pairName string) bool
+	// Sellable returns the base quantity that may be sold from the venue's inventory
+	Sellable(exchange, pairName string) float64
+}
+
+// FundingSource reports the funding perp venues have historically paid shorts
+type FundingSource interface {
+	// ShortBias returns the average funding in % per settlement a short received on the venue (negative = paid)
+	ShortBias(exchange, pairName string) float64
+}
+
+// FeeSource reports venues' current taker fees
+type FeeSource interface {
+	// TakerPct returns the venue market's taker fee in % ("spot" or "futures"), 0 when unknown
+	TakerPct(exchange, market string) float64
+}
+
+// BudgetSource caps the size of new positions per pair
+type BudgetSource interface {
+	// LegHeadroom returns how much USDT a new position in the pair may put on each leg, +Inf when uncapped
+	LegHeadroom(pairName string) float64
+}
+
 // Analyzer performs arbitrage analysis on orderbook updates
 type Analyzer struct {
 	globalManager       *GlobalManager
@@ -26,10 +58,18 @@ type Analyzer struct {
 	executionMu         sync.Mutex
 	isExecuting         bool
 	supportedExchanges  map[string]bool
+	inventory           InventorySource
+	funding             FundingSource
+	fees                FeeSource
+	budget              BudgetSource
+	warmMu              sync.Mutex
+	warmed              map[string]bool // pair:market:exchange books that completed warmup
+	heatmap             *Heatmap
 }
 
 // Opportunity represents a detected arbitrage opportunity
 type Opportunity struct {
+	ID              string // Clock-stamped trade ID, carried by the position, its orders, events and records
 	Pair            string
 	SpotExchange    string
 	PerpExchange    string
@@ -40,6 +80,24 @@ type Opportunity struct {
 	SpreadPct       float64
 	UsableVolumeUSD float64 // Minimum volume that can be used on both sides
 	Timestamp       time.Time
+
+	// Reverse opportunities sell spot inventory at the bid and long the perp at the ask
+	Reverse       bool
+	SpotBidPrice  float64
+	SpotBidVolume float64
+	PerpAskPrice  float64
+	PerpAskVolume float64
+
+	// FundingBias is the average funding in % per settlement the perp leg has received (negative = paid)
+	// FeePct is the taker fees in % both legs pay to open and close
+	// Score is the spread plus that bias less the fees, the opportunity's expected edge
+	FundingBias float64
+	FeePct      float64
+	Score       float64
+
+	// SpotRoutes splits the spot buy across venues, the first being SpotExchange (see routing.go)
+	// nil when the spot leg goes to SpotExchange alone
+	SpotRoutes []SpotRoute
 }
 
 // NewAnalyzer creates a new orderbook analyzer
@@ -63,6 +121,8 @@ func NewAnalyzer(gm *GlobalManager, supportedExchanges map[string]bool) *Analyze
 		globalManager:      gm,
 		logFile:            logFile,
 		supportedExchanges: supportedExchanges,
+		warmed:             make(map[string]bool),
+		heatmap:            newHeatmap(),
 	}
 }
 
@@ -71,6 +131,69 @@ func (a *Analyzer) SetExecutionCallback(callback OpportunityCallback) {
 	a.executionCallback = callback
 }
 
+// SetInventorySource enables reverse opportunities against venues' standing spot inventory
+func (a *Analyzer) SetInventorySource(source InventorySource) {
+	a.inventory = source
+}
+
+// SetFundingSource makes the analyzer try perp venues in order of their funding bias
+func (a *Analyzer) SetFundingSource(source FundingSource) {
+	a.funding = source
+}
+
+// SetFeeSource makes the analyzer score opportunities net of the venues' current fees
+func (a *Analyzer) SetFeeSource(source FeeSource) {
+	a.fees = source
+}
+
+// roundTripFees returns the taker fees in % of opening and closing both legs, 0 without a fee source
+func (a *Analyzer) roundTripFees(spotExchange, perpExchange string) float64 {
+	if a.fees == nil {
+		return 0
+	}
+	return 2 * (a.fees.TakerPct(spotExchange, "spot") + a.fees.TakerPct(perpExchange, "futures"))
+}
+
+// SetBudgetSource caps opportunity sizes at the pairs' remaining capital budgets
+func (a *Analyzer) SetBudgetSource(source BudgetSource) {
+	a.budget = source
+}
+
+// targetNotional returns the USDT per leg an opportunity on the pair aims to trade: TARGET_NOTIONAL_USDT
+// (default 20), capped by what is left of the pair's capital budget
+func (a *Analyzer) targetNotional(pairName string) float64 {
+	target := config.GetFloat("TARGET_NOTIONAL_USDT", 20)
+	if a.budget != nil {
+		target = math.Min(target, a.budget.LegHeadroom(pairName))
+	}
+	return target
+}
+
+// perpFundingBias returns the funding the perp leg is expected to receive, 0 without a funding source
+// A reverse trade is long the perp, so it receives what shorts pay
+func (a *Analyzer) perpFundingBias(perpExchange, pairName string, reverse bool) float64 {
+	if a.funding == nil {
+		return 0
+	}
+	bias := a.funding.ShortBias(perpExchange, pairName)
+	if reverse {
+		return -bias
+	}
+	return bias
+}
+
+// sortByFunding orders perp venues by the funding their leg is expected to receive, best first
+func (a *Analyzer) sortByFunding(perpExchanges []string, pairName string, reverse bool) []string {
+	if a.funding == nil {
+		return perpExchanges
+	}
+	sorted := append([]string(nil), perpExchanges...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return a.perpFundingBias(sorted[i], pairName, reverse) > a.perpFundingBias(sorted[j], pairName, reverse)
+	})
+	return sorted
+}
+
 // SetPriceUpdateCallback sets the callback function for position tracking price updates
 func (a *Analyzer) SetPriceUpdateCallback(callback PriceUpdateCallback) {
 	a.priceUpdateCallback = callback
@@ -82,7 +205,7 @@ func (a *Analyzer) ResetExecutionFlag() {
 	a.executionMu.Lock()
 	a.isExecuting = false
 	a.executionMu.Unlock()
-	fmt.Println("🔓 Execution flag reset - ready for next trade")
+	logging.Infof("analyzer", "🔓 Execution flag reset - ready for next trade")
 }
 
 // Close closes the log file
@@ -102,6 +225,9 @@ func (a *Analyzer) AnalyzePair(pairName string) {
 
 	opportunity := a.analyzeSignal(pm)
 	if opportunity != nil {
+		opportunity.ID = common.NewTradeID(opportunity.Timestamp)
+		a.heatmap.record(opportunity, time.Now())
+
 		// Check if both exchanges are supported
 		spotSupported := a.supportedExchanges[opportunity.SpotExchange]
 		perpSupported := a.supportedExchanges[opportunity.PerpExchange]
@@ -110,13 +236,62 @@ func (a *Analyzer) AnalyzePair(pairName string) {
 		differentExchanges := opportunity.SpotExchange != opportunity.PerpExchange
 
 		// Call price update callback for position tracking (if set)
-		if a.priceUpdateCallback != nil && spotSupported && perpSupported && differentExchanges {
-			a.priceUpdateCallback(pairName, opportunity.PerpExchange, opportunity.PerpBidPrice, opportunity.SpotExchange, opportunity.SpotAskPrice)
+		// Skipped while direct venue feeds are tracking the pair's open position
+		_, _, _, _, watching := pm.GetHoldOrderBooks()
+		if a.priceUpdateCallback != nil && !watching && spotSupported && perpSupported && differentExchanges {
+			spotOB, spotOk := pm.GetSpotOrderBook(opportunity.SpotExchange)
+			perpOB, perpOk := pm.GetPerpOrderBook(opportunity.PerpExchange)
+			if spotOk && perpOk {
+				a.feedExitPrices(pairName, opportunity.SpotExchange, spotOB, opportunity.PerpExchange, perpOB)
+			}
 		}
 
-		// Execute trade if both exchanges are supported, different, and spread >= 1%
-		if spotSupported && perpSupported && differentExchanges && common.GreaterThanOrEqual(opportunity.SpreadPct, 1.5) {
+		// Execute trade if both exchanges are supported, different, warmed up, and the spread reaches the combination's entry threshold
+		if spotSupported && perpSupported && differentExchanges && common.GreaterThanOrEqual(opportunity.SpreadPct, EntrySpreadPct(pairName, opportunity.SpotExchange, opportunity.PerpExchange)) && netSpreadOk(opportunity) &&
+			a.isWarm(pm, opportunity.SpotExchange, true) && a.isWarm(pm, opportunity.PerpExchange, false) {
 			a.executeOpportunity(opportunity)
+		} else if logging.Enabled("analyzer", logging.LevelDebug) {
+			logging.Debugf("analyzer", "[ANALYZER %s] Not executing %s %s/%s spread %.4f%% - supported: %v/%v, different: %v",
+				pairName, opportunity.ID, opportunity.SpotExchange, opportunity.PerpExchange, opportunity.SpreadPct, spotSupported, perpSupported, differentExchanges)
+		}
+	}
+}
+
+// AnalyzeHold feeds position tracking from the direct venue books of an open position
+// This is called whenever one of the pair's venue feeds receives an update
+func (a *Analyzer) AnalyzeHold(pairName string) {
+	if a.priceUpdateCallback == nil {
+		return
+	}
+
+	pm, exists := a.globalManager.GetPairManager(pairName)
+	if !exists {
+		return
+	}
+
+	spotExchange, spotOB, perpExchange, perpOB, ok := pm.GetHoldOrderBooks()
+	if !ok {
+		return
+	}
+	a.feedExitPrices(pairName, spotExchange, spotOB, perpExchange, perpOB)
+}
+
+// feedExitPrices passes position tracking the prices a position on the two venues would close at,
+// read from their books rather than the quotes the entry was taken on
+// Forward (short perp, long spot) buys the perp back at its best ask and sells the spot at its best bid;
+// reverse (short spot, long perp) buys the spot back at its best ask and sells the perp at its best bid
+func (a *Analyzer) feedExitPrices(pairName, spotExchange string, spotOB *OrderBook, perpExchange string, perpOB *OrderBook) {
+	spotBid, _, spotBidOk := spotOB.GetBestBid()
+	perpAsk, _, perpAskOk := perpOB.GetBestAsk()
+	if spotBidOk && perpAskOk {
+		a.priceUpdateCallback(pairName, perpExchange, perpAsk, spotExchange, spotBid)
+	}
+
+	if a.inventory != nil {
+		spotAsk, _, spotAskOk := spotOB.GetBestAsk()
+		perpBid, _, perpBidOk := perpOB.GetBestBid()
+		if spotAskOk && perpBidOk {
+			a.priceUpdateCallback(pairName, spotExchange, spotAsk, perpExchange, perpBid)
 		}
 	}
 }
@@ -134,12 +309,12 @@ func (a *Analyzer) executeOpportunity(opp *Opportunity) {
 
 	// Call the execution callback if set
 	if a.executionCallback != nil {
-		ctx := context.Background()
+		ctx := common.WithTradeID(context.Background(), opp.ID)
 		success := a.executionCallback(ctx, opp)
 
 		if success {
 			// Position opened successfully, DO NOT EXIT - let position tracking close it
-			fmt.Println("✅ Trade opened successfully. Monitoring position for exit...")
+			logging.Infof("analyzer", "✅ Trade opened successfully. Monitoring position for exit...")
 			// Keep running to allow position tracking to work
 			return
 		}
@@ -154,14 +329,14 @@ func (a *Analyzer) executeOpportunity(opp *Opportunity) {
 // logOpportunity logs an opportunity to console and file with detailed information
 func (a *Analyzer) logOpportunity(opp *Opportunity) {
 	// Calculate potential profit on the usable volume
-	profitPerUnit := opp.PerpBidPrice - opp.SpotAskPrice
-	profitPct := (profitPerUnit / opp.SpotAskPrice) * 100.0
-	estimatedProfit := opp.UsableVolumeUSD * (profitPct / 100.0)
+	profitPct := common.SpreadPct(opp.PerpBidPrice, opp.SpotAskPrice)
+	estimatedProfit := common.NewDecimal(opp.UsableVolumeUSD).Mul(common.NewDecimal(profitPct)).Div(common.NewDecimal(100)).Float64()
 
 	// Format log message with comprehensive info
 	timestamp := time.Now().Format("2006-01-02 15:04:05.000")
-	logMsg := fmt.Sprintf("[%s] %s | Spot: %s @ $%.8f (vol: %.4f) | Perp: %s @ $%.8f (vol: %.4f) | Spread: %.5f%% | Usable: $%.2f | Profit: $%.6f\n",
+	logMsg := fmt.Sprintf("[%s] %s %s | Spot: %s @ $%.8f (vol: %.4f) | Perp: %s @ $%.8f (vol: %.4f) | Spread: %.5f%% | Funding: %+.4f%% | Usable: $%.2f | Profit: $%.6f\n",
 		timestamp,
+		opp.ID,
 		opp.Pair,
 		opp.SpotExchange,
 		opp.SpotAskPrice,
@@ -170,6 +345,7 @@ func (a *Analyzer) logOpportunity(opp *Opportunity) {
 		opp.PerpBidPrice,
 		opp.PerpBidVolume,
 		opp.SpreadPct,
+		opp.FundingBias,
 		opp.UsableVolumeUSD,
 		estimatedProfit,
 	)
@@ -185,14 +361,51 @@ func (a *Analyzer) logOpportunity(opp *Opportunity) {
 	}
 }
 
+// netSpreadOk reports whether the spread net of round-trip fees reaches MIN_NET_SPREAD_PCT (default 0, disabled)
+func netSpreadOk(opp *Opportunity) bool {
+	minNet := config.GetFloat("MIN_NET_SPREAD_PCT", 0)
+	return minNet <= 0 || common.GreaterThanOrEqual(opp.SpreadPct-opp.FeePct, minNet)
+}
+
+// isWarm reports whether a venue's book for the pair has completed its startup warmup: at least
+// WARMUP_MIN_UPDATES messages (default 10) and WARMUP_MIN_DEPTH_USDT (default 20) on its thinner side
+// Cold books are still analyzed for position tracking but never executed against
+func (a *Analyzer) isWarm(pm *PairManager, exchangeName string, isSpot bool) bool {
+	ob, ok := pm.GetSpotOrderBook(exchangeName)
+	market := "spot"
+	if !isSpot {
+		ob, ok = pm.GetPerpOrderBook(exchangeName)
+		market = "perp"
+	}
+	if !ok {
+		return false
+	}
+
+	ob.mu.RLock()
+	updates := ob.Updates
+	ob.mu.RUnlock()
+
+	if updates < int64(config.GetInt("WARMUP_MIN_UPDATES", 10)) || ob.Depth() < config.GetFloat("WARMUP_MIN_DEPTH_USDT", 20) {
+		return false
+	}
+
+	key := pm.pairName + ":" + market + ":" + exchangeName
+	a.warmMu.Lock()
+	if !a.warmed[key] {
+		a.warmed[key] = true
+		logging.Infof("analyzer", "[WARMUP %s] %s %s book ready after %d updates", pm.pairName, exchangeName, market, updates)
+	}
+	a.warmMu.Unlock()
+	return true
+}
+
 // isReliable checks if an orderbook is reliable based on latency and freshness
 func isReliable(ob *OrderBook) bool {
 	ob.mu.RLock()
 	defer ob.mu.RUnlock()
 
 	latencyOk := common.LessThan(ob.Latency, 200.0)
-	ageMs := float64(time.Now().UnixMilli() - ob.LastUpdateTs)
-	freshnessOk := common.LessThan(ageMs, 5000.0)
+	freshnessOk := ob.age() < 5*time.Second
 
 	return latencyOk && freshnessOk
 }
@@ -217,8 +430,26 @@ func (a *Analyzer) analyzeSignal(pm *PairManager) *Opportunity {
 	}
 	pm.perpBooks.mu.RUnlock()
 
+	// Leave out books whose prices are off the other venues by an order of magnitude
+	outliers := pm.priceOutliers()
+	spotExchanges = withoutOutliers(spotExchanges, "spot", outliers)
+	perpExchanges = withoutOutliers(perpExchanges, "perp", outliers)
+
+	// Prefer perp venues where shorts have been receiving funding
+	forwardPerps := a.sortByFunding(perpExchanges, pm.pairName, false)
+
 	// Iterate through all spot exchanges
 	for _, spotExchange := range spotExchanges {
+		// Never pair a venue that doesn't list the pair on the market we need
+		if !capability.CanTrade(spotExchange, pm.pairName, true) {
+			continue
+		}
+
+		// Closing a forward spot long sells the whole holding, which would dump standing inventory
+		if a.inventory != nil && a.inventory.Holds(spotExchange, pm.pairName) {
+			continue
+		}
+
 		spotOB, spotExists := pm.GetSpotOrderBook(spotExchange)
 		if !spotExists || !isReliable(spotOB) {
 			continue
@@ -232,12 +463,16 @@ func (a *Analyzer) analyzeSignal(pm *PairManager) *Opportunity {
 		// spotAskVol is already in USDT (quantity × price)
 
 		// Compare against all perp exchanges
-		for _, perpExchange := range perpExchanges {
+		for _, perpExchange := range forwardPerps {
 			// Skip if same exchange (avoid self-comparison)
 			if perpExchange == spotExchange {
 				continue
 			}
 
+			if !capability.CanTrade(perpExchange, pm.pairName, false) {
+				continue
+			}
+
 			perpOB, perpExists := pm.GetPerpOrderBook(perpExchange)
 			if !perpExists || !isReliable(perpOB) {
 				continue
@@ -251,11 +486,11 @@ func (a *Analyzer) analyzeSignal(pm *PairManager) *Opportunity {
 			// perpBidVol is already in USDT (quantity × price)
 
 			// Target notional USD (what we want to trade)
-			targetNotionalUSD := 20.0
+			targetNotionalUSD := a.targetNotional(pm.pairName)
 
-			// Check minimum achievable volume on each side based on quantity precision
-			spotMinAchievable := common.CalculateMinAchievableVolume(spotBestAsk, pm.pairName)
-			perpMinAchievable := common.CalculateMinAchievableVolume(perpBestBid, pm.pairName)
+			// Smallest order each venue accepts: quantity precision, step size, minimum quantity and notional
+			spotMinAchievable := capability.MinOrderVolume(spotExchange, pm.pairName, true, spotBestAsk)
+			perpMinAchievable := capability.MinOrderVolume(perpExchange, pm.pairName, false, perpBestBid)
 
 			// Take the minimum volume between:
 			// 1. What orderbook offers on spot side (already in USDT)
@@ -269,29 +504,25 @@ func (a *Analyzer) analyzeSignal(pm *PairManager) *Opportunity {
 				minVolume = targetNotionalUSD
 			}
 
-			// Check if minimum volume is achievable with quantity precision on BOTH sides
-			// If volume < target AND either side can't achieve it, skip this opportunity
-			if common.LessThan(minVolume, targetNotionalUSD) {
-				// Volume is less than target - check if it's at least achievable
-				spotCanAchieve := common.CanAchieveVolume(minVolume, spotBestAsk, pm.pairName)
-				perpCanAchieve := common.CanAchieveVolume(minVolume, perpBestBid, pm.pairName)
-
-				if !spotCanAchieve || !perpCanAchieve {
-					// Can't achieve even the available volume with precision - skip
-					continue
-				}
+			// The usable volume must be a legal order on BOTH sides, otherwise skip this opportunity
+			// before it reaches the venues (checked at the target size too, e.g. a 40 USDT minimum)
+			if common.LessThan(minVolume, spotMinAchievable) || common.LessThan(minVolume, perpMinAchievable) {
+				continue
 			}
 
-			// Also ensure both sides can at least achieve their minimum
-			if common.LessThan(spotAskVol, spotMinAchievable) || common.LessThan(perpBidVol, perpMinAchievable) {
+			// Both legs must meet the venues' minimum order sizes
+			if !capability.CanFill(spotExchange, pm.pairName, true, spotBestAsk, minVolume) ||
+				!capability.CanFill(perpExchange, pm.pairName, false, perpBestBid, minVolume) {
 				continue
 			}
 
 			// Check if arbitrage exists: perp bid > spot ask
 			if common.GreaterThan(perpBestBid, spotBestAsk) {
-				spreadPct := ((perpBestBid - spotBestAsk) / spotBestAsk) * 100.0
+				spreadPct := common.SpreadPct(perpBestBid, spotBestAsk)
+				fundingBias := a.perpFundingBias(perpExchange, pm.pairName, false)
+				feePct := a.roundTripFees(spotExchange, perpExchange)
 
-				return &Opportunity{
+				opp := &Opportunity{
 					Pair:            pm.pairName,
 					SpotExchange:    spotExchange,
 					PerpExchange:    perpExchange,
@@ -302,7 +533,104 @@ func (a *Analyzer) analyzeSignal(pm *PairManager) *Opportunity {
 					SpreadPct:       spreadPct,
 					UsableVolumeUSD: minVolume, // This is the synchronized volume to use
 					Timestamp:       time.Now(),
+					FundingBias:     fundingBias,
+					FeePct:          feePct,
+					Score:           spreadPct + fundingBias - feePct,
 				}
+
+				// The perp bid can take more than this venue's top of book, buy the rest elsewhere
+				if common.LessThan(spotAskVol, targetNotionalUSD) && common.GreaterThan(perpBidVol, spotAskVol) {
+					a.routeSpot(pm, opp, math.Min(perpBidVol, targetNotionalUSD))
+				}
+				return opp
+			}
+		}
+	}
+
+	if a.inventory != nil {
+		return a.analyzeReverse(pm, spotExchanges, perpExchanges)
+	}
+	return nil
+}
+
+// analyzeReverse looks for spot bid > perp ask on venues holding inventory to sell
+func (a *Analyzer) analyzeReverse(pm *PairManager, spotExchanges, perpExchanges []string) *Opportunity {
+	// The perp leg is long, so prefer venues where shorts have been paying funding
+	perpExchanges = a.sortByFunding(perpExchanges, pm.pairName, true)
+
+	for _, spotExchange := range spotExchanges {
+		sellable := a.inventory.Sellable(spotExchange, pm.pairName)
+		if common.IsNegativeOrZero(sellable) || !capability.CanTrade(spotExchange, pm.pairName, true) {
+			continue
+		}
+
+		spotOB, spotExists := pm.GetSpotOrderBook(spotExchange)
+		if !spotExists || !isReliable(spotOB) {
+			continue
+		}
+
+		spotBestBid, spotBidVol, spotBidOk := spotOB.GetBestBid()
+		if !spotBidOk {
+			continue
+		}
+
+		for _, perpExchange := range perpExchanges {
+			if perpExchange == spotExchange || !capability.CanTrade(perpExchange, pm.pairName, false) {
+				continue
+			}
+
+			perpOB, perpExists := pm.GetPerpOrderBook(perpExchange)
+			if !perpExists || !isReliable(perpOB) {
+				continue
+			}
+
+			perpBestAsk, perpAskVol, perpAskOk := perpOB.GetBestAsk()
+			if !perpAskOk || !common.GreaterThan(spotBestBid, perpBestAsk) {
+				continue
+			}
+
+			// Same sizing as the forward direction, additionally capped by sellable inventory
+			targetNotionalUSD := a.targetNotional(pm.pairName)
+			minVolume := spotBidVol
+			if common.LessThan(perpAskVol, minVolume) {
+				minVolume = perpAskVol
+			}
+			if common.LessThan(targetNotionalUSD, minVolume) {
+				minVolume = targetNotionalUSD
+			}
+			if common.LessThan(sellable*spotBestBid, minVolume) {
+				minVolume = sellable * spotBestBid
+			}
+
+			if common.LessThan(minVolume, capability.MinOrderVolume(spotExchange, pm.pairName, true, spotBestBid)) ||
+				common.LessThan(minVolume, capability.MinOrderVolume(perpExchange, pm.pairName, false, perpBestAsk)) {
+				continue
+			}
+
+			if !capability.CanFill(spotExchange, pm.pairName, true, spotBestBid, minVolume) ||
+				!capability.CanFill(perpExchange, pm.pairName, false, perpBestAsk, minVolume) {
+				continue
+			}
+
+			spreadPct := common.SpreadPct(spotBestBid, perpBestAsk)
+			fundingBias := a.perpFundingBias(perpExchange, pm.pairName, true)
+			feePct := a.roundTripFees(spotExchange, perpExchange)
+
+			return &Opportunity{
+				Pair:            pm.pairName,
+				SpotExchange:    spotExchange,
+				PerpExchange:    perpExchange,
+				SpreadPct:       spreadPct,
+				UsableVolumeUSD: minVolume,
+				Timestamp:       time.Now(),
+				Reverse:         true,
+				SpotBidPrice:    spotBestBid,
+				SpotBidVolume:   spotBidVol,
+				PerpAskPrice:    perpBestAsk,
+				PerpAskVolume:   perpAskVol,
+				FundingBias:     fundingBias,
+				FeePct:          feePct,
+				Score:           spreadPct + fundingBias - feePct,
 			}
 		}
 	}
@@ -0,0 +1,140 @@
+package orderbook
+
+import (
+	"bytes"
+	"strconv"
+	"sync"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"github.com/vmihailenco/msgpack/v5/msgpcode"
+)
+
+// Decode pooling
+//
+// Bursts of market activity bring hundreds of book messages a second per pair. Decoding each into
+// generic maps boxed every price and quantity in an interface and allocated maps that were dropped
+// right after the merge, so GC pauses landed exactly when the books moved most. The signal decode
+// path now walks the message token by token with a pooled decoder and reader, into level maps and
+// update slices taken from pools; the manager hands them back once a batch is merged into the books.
+
+var (
+	readerPool = sync.Pool{New: func() interface{} { return new(bytes.Reader) }}
+	levelsPool = sync.Pool{New: func() interface{} { return make(map[float64]float64) }}
+
+	// Update slices are pooled through pointers, a slice header put as an interface would be copied to the heap
+	updatesPool = sync.Pool{New: func() interface{} {
+		updates := make([]BookUpdate, 0, 8)
+		return &updates
+	}}
+)
+
+// acquireDecoder returns a pooled decoder reading message
+func acquireDecoder(message []byte) (*msgpack.Decoder, *bytes.Reader) {
+	reader := readerPool.Get().(*bytes.Reader)
+	reader.Reset(message)
+	dec := msgpack.GetDecoder()
+	dec.Reset(reader)
+	return dec, reader
+}
+
+// releaseDecoder returns a decoder and its reader to their pools
+func releaseDecoder(dec *msgpack.Decoder, reader *bytes.Reader) {
+	msgpack.PutDecoder(dec)
+	reader.Reset(nil)
+	readerPool.Put(reader)
+}
+
+// acquireLevels returns an empty level map, reusing a released one's buckets
+func acquireLevels() map[float64]float64 {
+	return levelsPool.Get().(map[float64]float64)
+}
+
+// releaseLevels empties a level map and returns it to the pool
+func releaseLevels(levels map[float64]float64) {
+	if levels == nil {
+		return
+	}
+	clear(levels)
+	levelsPool.Put(levels)
+}
+
+// acquireUpdates returns an empty update slice
+func acquireUpdates() []BookUpdate {
+	return (*updatesPool.Get().(*[]BookUpdate))[:0]
+}
+
+// release returns the batch's level maps and update slice to their pools
+// The batch must not be used afterwards
+func (b *BookBatch) release() {
+	for i := range b.Updates {
+		releaseLevels(b.Updates[i].Bids)
+		releaseLevels(b.Updates[i].Asks)
+		b.Updates[i] = BookUpdate{}
+	}
+	if cap(b.Updates) > 0 {
+		updates := b.Updates[:0]
+		updatesPool.Put(&updates)
+	}
+	b.Updates = nil
+}
+
+// decodeArrayLen reads an array header; ok is false (and the value skipped) when the next value is not an array
+func decodeArrayLen(dec *msgpack.Decoder) (int, bool, error) {
+	code, err := dec.PeekCode()
+	if err != nil {
+		return 0, false, err
+	}
+	if !msgpcode.IsFixedArray(code) && code != msgpcode.Array16 && code != msgpcode.Array32 {
+		return 0, false, dec.Skip()
+	}
+	n, err := dec.DecodeArrayLen()
+	return n, err == nil, err
+}
+
+// decodeMapLen reads a map header; ok is false (and the value skipped) when the next value is not a map
+func decodeMapLen(dec *msgpack.Decoder) (int, bool, error) {
+	code, err := dec.PeekCode()
+	if err != nil {
+		return 0, false, err
+	}
+	if !msgpcode.IsFixedMap(code) && code != msgpcode.Map16 && code != msgpcode.Map32 {
+		return 0, false, dec.Skip()
+	}
+	n, err := dec.DecodeMapLen()
+	return n, err == nil, err
+}
+
+// decodeNumber reads an integer, float or numeric string as float64
+// ok is false (and the value skipped) for anything else, which reads as 0
+func decodeNumber(dec *msgpack.Decoder) (float64, bool, error) {
+	code, err := dec.PeekCode()
+	if err != nil {
+		return 0, false, err
+	}
+
+	switch {
+	case msgpcode.IsString(code):
+		s, err := dec.DecodeString()
+		if err != nil {
+			return 0, false, err
+		}
+		v, parseErr := strconv.ParseFloat(s, 64)
+		return v, parseErr == nil, nil
+	case msgpcode.IsFixedNum(code), code == msgpcode.Float, code == msgpcode.Double,
+		code >= msgpcode.Uint8 && code <= msgpcode.Int64:
+		v, err := dec.DecodeFloat64()
+		return v, err == nil, err
+	default:
+		return 0, false, dec.Skip()
+	}
+}
+
+// skipN skips the next n values
+func skipN(dec *msgpack.Decoder, n int) error {
+	for i := 0; i < n; i++ {
+		if err := dec.Skip(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
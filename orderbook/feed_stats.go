@@ -0,0 +1,206 @@
+package orderbook
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"arbitrage.trade/config"
+	"arbitrage.trade/logging"
+)
+
+// Signal feed backpressure
+//
+// Each pair's signal updates are decoded and merged into its books as soon as they are read, and
+// the pair is analyzed on its own worker. When updates arrive faster than the pair can be
+// analyzed, every update landing while an analysis runs is folded into one follow-up run on the
+// latest books: intermediate book states are skipped instead of queued, so detection lag stays
+// bounded by a single analysis rather than growing with the backlog. Update rate, decode and
+// analysis times, pending depth and skipped states are kept per pair (GET /feed/stats), and lag
+// from an update to the analysis covering it above FEED_LAG_WARN (default 500ms) is logged.
+
+// FeedStats is a pair's signal feed throughput and backpressure
+type FeedStats struct {
+	Pair              string  `json:"pair"`
+	Messages          int64   `json:"messages"`
+	MessagesPerSec    float64 `json:"messages_per_sec"`
+	PeakPerSec        float64 `json:"peak_per_sec"`
+	AvgDecodeMicros   float64 `json:"avg_decode_us"`
+	MaxDecodeMicros   float64 `json:"max_decode_us"`
+	Analyses          int64   `json:"analyses"`
+	Skipped           int64   `json:"skipped"` // Updates folded into a later analysis
+	Pending           int64   `json:"pending"` // Updates waiting for the next analysis
+	AvgAnalysisMicros float64 `json:"avg_analysis_us"`
+	MaxAnalysisMicros float64 `json:"max_analysis_us"`
+	LastLagMs         float64 `json:"last_lag_ms"`
+	MaxLagMs          float64 `json:"max_lag_ms"`
+	OutliersRejected  int64   `json:"outliers_rejected"` // Venue books left out of an analysis by the price sanity check
+}
+
+// feedMonitor accumulates a pair's feed stats
+type feedMonitor struct {
+	mu sync.Mutex
+
+	messages    int64
+	secondStart time.Time
+	secondCount int64
+	rate        float64
+	peakRate    float64
+	decodeTotal time.Duration
+	decodeMax   time.Duration
+
+	pending      int64
+	pendingSince time.Time // Arrival of the oldest update not yet analyzed
+
+	analyses      int64
+	skipped       int64
+	analysisTotal time.Duration
+	analysisMax   time.Duration
+	lastLag       time.Duration
+	maxLag        time.Duration
+	outliers      int64
+}
+
+// received records an update merged into the books and waiting for analysis
+func (m *feedMonitor) received(decode time.Duration) {
+	now := time.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.messages++
+	if elapsed := now.Sub(m.secondStart); elapsed >= time.Second {
+		if !m.secondStart.IsZero() {
+			m.rate = float64(m.secondCount) / elapsed.Seconds()
+			if m.rate > m.peakRate {
+				m.peakRate = m.rate
+			}
+		}
+		m.secondStart, m.secondCount = now, 0
+	}
+	m.secondCount++
+
+	m.decodeTotal += decode
+	if decode > m.decodeMax {
+		m.decodeMax = decode
+	}
+
+	if m.pending == 0 {
+		m.pendingSince = now
+	}
+	m.pending++
+}
+
+// startAnalysis takes every pending update for one analysis and returns how many it covers and their lag
+func (m *feedMonitor) startAnalysis() (int64, time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	covered := m.pending
+	if covered == 0 {
+		return 0, 0
+	}
+	lag := time.Since(m.pendingSince)
+
+	m.pending = 0
+	m.skipped += covered - 1
+	m.lastLag = lag
+	if lag > m.maxLag {
+		m.maxLag = lag
+	}
+	return covered, lag
+}
+
+// rejected counts venue books the price sanity check left out of an analysis
+func (m *feedMonitor) rejected(n int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.outliers += n
+}
+
+// finishAnalysis records how long an analysis took
+func (m *feedMonitor) finishAnalysis(took time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.analyses++
+	m.analysisTotal += took
+	if took > m.analysisMax {
+		m.analysisMax = took
+	}
+}
+
+// snapshot returns the pair's current stats
+func (m *feedMonitor) snapshot(pairName string) FeedStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := FeedStats{
+		Pair:              pairName,
+		Messages:          m.messages,
+		MessagesPerSec:    m.rate,
+		PeakPerSec:        m.peakRate,
+		MaxDecodeMicros:   float64(m.decodeMax.Microseconds()),
+		Analyses:          m.analyses,
+		Skipped:           m.skipped,
+		Pending:           m.pending,
+		MaxAnalysisMicros: float64(m.analysisMax.Microseconds()),
+		LastLagMs:         float64(m.lastLag.Microseconds()) / 1000.0,
+		MaxLagMs:          float64(m.maxLag.Microseconds()) / 1000.0,
+		OutliersRejected:  m.outliers,
+	}
+	if m.messages > 0 {
+		stats.AvgDecodeMicros = float64(m.decodeTotal.Microseconds()) / float64(m.messages)
+	}
+	if m.analyses > 0 {
+		stats.AvgAnalysisMicros = float64(m.analysisTotal.Microseconds()) / float64(m.analyses)
+	}
+	return stats
+}
+
+// requestAnalysis wakes the pair's analysis worker; a wake-up already pending covers this one too
+func (pm *PairManager) requestAnalysis() {
+	select {
+	case pm.analyzeCh <- struct{}{}:
+	default:
+	}
+}
+
+// runAnalysis analyzes the pair on the latest books whenever updates are pending, until the manager stops
+func (pm *PairManager) runAnalysis() {
+	for {
+		select {
+		case <-pm.ctx.Done():
+			return
+		case <-pm.analyzeCh:
+		}
+
+		covered, lag := pm.feed.startAnalysis()
+		if covered == 0 || pm.analyzer == nil {
+			continue
+		}
+		if warn := config.GetDuration("FEED_LAG_WARN", 500*time.Millisecond); warn > 0 && lag > warn {
+			logging.Warnf("orderbook", "[ORDERBOOK] %s - Analysis %dms behind, %d updates folded into one",
+				pm.pairName, lag.Milliseconds(), covered)
+		}
+
+		started := time.Now()
+		pm.analyzer.AnalyzePair(pm.pairName)
+		pm.feed.finishAnalysis(time.Since(started))
+	}
+}
+
+// FeedStats returns every pair's signal feed stats, sorted by pair
+func (gm *GlobalManager) FeedStats() []FeedStats {
+	gm.mu.RLock()
+	stats := make([]FeedStats, 0, len(gm.pairManagers))
+	for pairName, pm := range gm.pairManagers {
+		stats = append(stats, pm.feed.snapshot(pairName))
+	}
+	gm.mu.RUnlock()
+
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Pair < stats[j].Pair
+	})
+	return stats
+}
@@ -1,22 +1,24 @@
 package orderbook
 
 import (
+	"sort"
 	"sync"
+	"time"
 )
 
 // GlobalManager manages all pair managers
 type GlobalManager struct {
 	mu           sync.RWMutex
 	pairManagers map[string]*PairManager
-	signalURL    string
+	source       MarketDataSource
 	analyzer     *Analyzer
 }
 
-// NewGlobalManager creates a new global orderbook manager
-func NewGlobalManager(signalURL string) *GlobalManager {
+// NewGlobalManager creates a new global orderbook manager whose pairs are fed by source
+func NewGlobalManager(source MarketDataSource) *GlobalManager {
 	return &GlobalManager{
 		pairManagers: make(map[string]*PairManager),
-		signalURL:    signalURL,
+		source:       source,
 	}
 }
 
@@ -42,7 +44,7 @@ func (gm *GlobalManager) AddPair(pairName string) error {
 		return nil // Already monitoring
 	}
 
-	pm := NewPairManager(pairName, gm.signalURL)
+	pm := NewPairManager(pairName, gm.source)
 
 	// Set analyzer if one exists
 	if gm.analyzer != nil {
@@ -77,6 +79,32 @@ func (gm *GlobalManager) GetPairManager(pairName string) (*PairManager, bool) {
 	return pm, exists
 }
 
+// WatchVenues starts direct venue feeds for a pair's open position
+func (gm *GlobalManager) WatchVenues(pairName, spotExchange, perpExchange string) {
+	if pm, exists := gm.GetPairManager(pairName); exists {
+		pm.WatchVenues(spotExchange, perpExchange)
+	}
+}
+
+// UnwatchVenues stops the direct venue feeds for a pair
+func (gm *GlobalManager) UnwatchVenues(pairName string) {
+	if pm, exists := gm.GetPairManager(pairName); exists {
+		pm.UnwatchVenues()
+	}
+}
+
+// GetBook returns the signal-feed orderbook for a pair, exchange and market
+func (gm *GlobalManager) GetBook(pairName, exchangeName string, isSpot bool) (*OrderBook, bool) {
+	pm, exists := gm.GetPairManager(pairName)
+	if !exists {
+		return nil, false
+	}
+	if isSpot {
+		return pm.GetSpotOrderBook(exchangeName)
+	}
+	return pm.GetPerpOrderBook(exchangeName)
+}
+
 // GetAllPairs returns all monitored pair names
 func (gm *GlobalManager) GetAllPairs() []string {
 	gm.mu.RLock()
@@ -99,3 +127,120 @@ func (gm *GlobalManager) StopAll() {
 	}
 	gm.pairManagers = make(map[string]*PairManager)
 }
+
+// BookSummary is one venue book's top of book and freshness
+type BookSummary struct {
+	Pair     string  `json:"pair"`
+	Exchange string  `json:"exchange"`
+	Market   string  `json:"market"` // "spot" or "perp"
+	BestBid  float64 `json:"best_bid"`
+	BestAsk  float64 `json:"best_ask"`
+	Levels   int     `json:"levels"`
+	Updates  int64   `json:"updates"`
+	Latency  float64 `json:"latency_ms"`
+	AgeMs    float64 `json:"age_ms"`
+}
+
+// BookSummaries returns the top of every signal-feed book, sorted by pair, market and exchange
+func (gm *GlobalManager) BookSummaries() []BookSummary {
+	gm.mu.RLock()
+	managers := make([]*PairManager, 0, len(gm.pairManagers))
+	for _, pm := range gm.pairManagers {
+		managers = append(managers, pm)
+	}
+	gm.mu.RUnlock()
+
+	summaries := make([]BookSummary, 0)
+	for _, pm := range managers {
+		for market, books := range map[string]*ExchangeOrderBooks{"spot": pm.spotBooks, "perp": pm.perpBooks} {
+			books.mu.RLock()
+			for exchange, ob := range books.OrderBooks {
+				bid, _, _ := ob.GetBestBid()
+				ask, _, _ := ob.GetBestAsk()
+
+				ob.mu.RLock()
+				summary := BookSummary{
+					Pair:     pm.pairName,
+					Exchange: exchange,
+					Market:   market,
+					BestBid:  bid,
+					BestAsk:  ask,
+					Levels:   len(ob.Bids) + len(ob.Asks),
+					Updates:  ob.Updates,
+					Latency:  ob.Latency,
+					AgeMs:    durationMs(ob.age()),
+				}
+				ob.mu.RUnlock()
+				summaries = append(summaries, summary)
+			}
+			books.mu.RUnlock()
+		}
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		a, b := summaries[i], summaries[j]
+		if a.Pair != b.Pair {
+			return a.Pair < b.Pair
+		}
+		if a.Market != b.Market {
+			return a.Market < b.Market
+		}
+		return a.Exchange < b.Exchange
+	})
+	return summaries
+}
+
+// VenueBook is one venue's top price levels of a pair's market as the bot sees them
+type VenueBook struct {
+	Exchange   string       `json:"exchange"`
+	Market     string       `json:"market"` // "spot" or "perp"
+	Bids       []PriceLevel `json:"bids"`   // Highest first
+	Asks       []PriceLevel `json:"asks"`   // Lowest first
+	LastUpdate time.Time    `json:"last_update"`
+	AgeMs      float64      `json:"age_ms"`
+	Latency    float64      `json:"latency_ms"`
+}
+
+// TopOfBooks returns the top depth levels (0 for all) of every venue book of a pair, sorted by market and exchange
+// ok is false when the pair is not monitored
+func (gm *GlobalManager) TopOfBooks(pairName string, depth int) ([]VenueBook, bool) {
+	pm, exists := gm.GetPairManager(pairName)
+	if !exists {
+		return nil, false
+	}
+
+	venues := make([]VenueBook, 0)
+	for market, books := range map[string]*ExchangeOrderBooks{"spot": pm.spotBooks, "perp": pm.perpBooks} {
+		books.mu.RLock()
+		for exchange, ob := range books.OrderBooks {
+			bids, asks, updated := ob.GetSnapshot()
+			if depth > 0 {
+				bids = bids[:min(depth, len(bids))]
+				asks = asks[:min(depth, len(asks))]
+			}
+
+			ob.mu.RLock()
+			latency, age := ob.Latency, ob.age()
+			ob.mu.RUnlock()
+
+			venues = append(venues, VenueBook{
+				Exchange:   exchange,
+				Market:     market,
+				Bids:       bids,
+				Asks:       asks,
+				LastUpdate: updated,
+				AgeMs:      durationMs(age),
+				Latency:    latency,
+			})
+		}
+		books.mu.RUnlock()
+	}
+
+	sort.Slice(venues, func(i, j int) bool {
+		if venues[i].Market != venues[j].Market {
+			return venues[i].Market < venues[j].Market
+		}
+		return venues[i].Exchange < venues[j].Exchange
+	})
+	return venues, true
+}
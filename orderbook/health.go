@@ -0,0 +1,192 @@
+package orderbook
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"arbitrage.trade/config"
+	"arbitrage.trade/logging"
+)
+
+// Degraded pairs
+//
+// The analyzer only trades books that are reliable (fresh and low latency), so when every venue on
+// one side of a pair goes stale it finds nothing and stays silent, which looks the same as a quiet
+// market. Each pair's sides are checked every FEED_HEALTH_INTERVAL (default 1s): a side with no
+// reliable venue book is blind, and the pair is degraded while either side is. The state, since when,
+// which venues are stale and how often and how long the pair was degraded are served on GET
+// /feed/health and in /debug/state; transitions are logged once the pair is past its startup warmup.
+
+// Pair health states
+const (
+	HealthOK        = "ok"
+	HealthSpotBlind = "spot_blind"
+	HealthPerpBlind = "perp_blind"
+	HealthBlind     = "blind" // Both sides
+)
+
+// healthWarmup is how long after start a pair's books may still be filling without being logged as degraded
+const healthWarmup = 30 * time.Second
+
+// PairHealth is whether a pair's analysis can see both sides of the market
+type PairHealth struct {
+	Pair            string    `json:"pair"`
+	State           string    `json:"state"`
+	Since           time.Time `json:"since"`
+	FreshSpot       []string  `json:"fresh_spot"`
+	StaleSpot       []string  `json:"stale_spot"`
+	FreshPerp       []string  `json:"fresh_perp"`
+	StalePerp       []string  `json:"stale_perp"`
+	Degradations    int64     `json:"degradations"`     // Degraded stretches reported past the startup warmup
+	DegradedSeconds float64   `json:"degraded_seconds"` // Total time degraded, the current stretch included
+	LastDegradedAt  time.Time `json:"last_degraded_at,omitempty"`
+	LastRecoveredAt time.Time `json:"last_recovered_at,omitempty"`
+}
+
+// healthMonitor tracks a pair's health across checks
+type healthMonitor struct {
+	mu       sync.Mutex
+	started  time.Time
+	current  PairHealth
+	degraded time.Duration // Completed degraded stretches
+	reported bool          // The current degraded stretch was logged and counted
+}
+
+// splitFresh returns the venues whose books are reliable and those that are not, each sorted
+func (eob *ExchangeOrderBooks) splitFresh() ([]string, []string) {
+	eob.mu.RLock()
+	fresh, stale := make([]string, 0), make([]string, 0)
+	for exchange, ob := range eob.OrderBooks {
+		if isReliable(ob) {
+			fresh = append(fresh, exchange)
+		} else {
+			stale = append(stale, exchange)
+		}
+	}
+	eob.mu.RUnlock()
+
+	sort.Strings(fresh)
+	sort.Strings(stale)
+	return fresh, stale
+}
+
+// checkHealth re-evaluates the pair's sides and logs state changes
+func (pm *PairManager) checkHealth(now time.Time) {
+	freshSpot, staleSpot := pm.spotBooks.splitFresh()
+	freshPerp, stalePerp := pm.perpBooks.splitFresh()
+
+	state := HealthOK
+	switch {
+	case len(freshSpot) == 0 && len(freshPerp) == 0:
+		state = HealthBlind
+	case len(freshSpot) == 0:
+		state = HealthSpotBlind
+	case len(freshPerp) == 0:
+		state = HealthPerpBlind
+	}
+
+	m := &pm.health
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	prev := m.current
+	m.current.Pair = pm.pairName
+	m.current.FreshSpot, m.current.StaleSpot = freshSpot, staleSpot
+	m.current.FreshPerp, m.current.StalePerp = freshPerp, stalePerp
+	warm := now.Sub(m.started) >= healthWarmup
+
+	if prev.State == state {
+		// A side that never came up during the warmup is reported once it is over
+		if state != HealthOK && warm && !m.reported {
+			m.reported = true
+			m.current.Degradations++
+			logging.Warnf("orderbook", "[HEALTH] %s - Degraded: %s since %s (stale spot %v, stale perp %v)",
+				pm.pairName, state, m.current.Since.Format(time.RFC3339), staleSpot, stalePerp)
+		}
+		return
+	}
+
+	m.current.State = state
+	m.current.Since = now
+
+	switch {
+	case state == HealthOK:
+		if prev.State != "" {
+			m.degraded += now.Sub(prev.Since)
+			m.current.LastRecoveredAt = now
+			if m.reported {
+				logging.Infof("orderbook", "[HEALTH] %s - Recovered after %s %s", pm.pairName, now.Sub(prev.Since).Round(time.Second), prev.State)
+			}
+		}
+		m.reported = false
+	case prev.State == HealthOK || prev.State == "":
+		m.current.LastDegradedAt = now
+		if warm {
+			m.reported = true
+			m.current.Degradations++
+			logging.Warnf("orderbook", "[HEALTH] %s - Degraded: %s (stale spot %v, stale perp %v)", pm.pairName, state, staleSpot, stalePerp)
+		}
+	default:
+		// One degraded state into another, the stretch continues
+		m.current.Since = prev.Since
+		m.current.LastDegradedAt = prev.LastDegradedAt
+		if m.reported {
+			logging.Warnf("orderbook", "[HEALTH] %s - Now %s (stale spot %v, stale perp %v)", pm.pairName, state, staleSpot, stalePerp)
+		}
+	}
+}
+
+// watchHealth checks the pair's health until the manager stops
+func (pm *PairManager) watchHealth() {
+	pm.health.mu.Lock()
+	pm.health.started = time.Now()
+	pm.health.mu.Unlock()
+
+	interval := config.GetDuration("FEED_HEALTH_INTERVAL", time.Second)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-pm.ctx.Done():
+			return
+		case now := <-ticker.C:
+			pm.checkHealth(now)
+		}
+	}
+}
+
+// healthSnapshot returns the pair's current health
+func (pm *PairManager) healthSnapshot() PairHealth {
+	m := &pm.health
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	health := m.current
+	health.Pair = pm.pairName
+	degraded := m.degraded
+	if health.State != HealthOK && !health.Since.IsZero() {
+		degraded += time.Since(health.Since)
+	}
+	health.DegradedSeconds = degraded.Seconds()
+	return health
+}
+
+// PairHealth returns every pair's health, degraded pairs first, then by pair
+func (gm *GlobalManager) PairHealth() []PairHealth {
+	gm.mu.RLock()
+	health := make([]PairHealth, 0, len(gm.pairManagers))
+	for _, pm := range gm.pairManagers {
+		health = append(health, pm.healthSnapshot())
+	}
+	gm.mu.RUnlock()
+
+	sort.Slice(health, func(i, j int) bool {
+		if (health[i].State == HealthOK) != (health[j].State == HealthOK) {
+			return health[j].State == HealthOK
+		}
+		return health[i].Pair < health[j].Pair
+	})
+	return health
+}
@@ -0,0 +1,150 @@
+package orderbook
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"arbitrage.trade/config"
+	"arbitrage.trade/redis"
+	"arbitrage.trade/scheduler"
+)
+
+// Opportunity heatmap
+//
+// Every opportunity the analyzer sees is counted per pair, spot venue, perp venue and direction in
+// one-minute buckets kept for a day. The matrix of observation counts and average spread over the
+// last hour and day is served at GET /opportunities/heatmap and published on the
+// arbitrage-opportunity-heatmap Redis channel every HEATMAP_EXPORT_INTERVAL (default 5m, 0 disables).
+
+// heatmapRetention is the longest window the heatmap reports
+const heatmapRetention = 24 * time.Hour
+
+type heatmapKey struct {
+	pair, spot, perp string
+	reverse          bool
+}
+
+// heatmapBucket is one minute of a cell's observations
+type heatmapBucket struct {
+	count    int
+	sum, max float64
+	last     time.Time
+}
+
+// Heatmap counts observed opportunities per pair and venue combination
+type Heatmap struct {
+	mu    sync.Mutex
+	cells map[heatmapKey]map[int64]*heatmapBucket // minute unix -> bucket
+}
+
+func newHeatmap() *Heatmap {
+	return &Heatmap{cells: make(map[heatmapKey]map[int64]*heatmapBucket)}
+}
+
+// record adds an observed opportunity to its cell's current minute
+func (h *Heatmap) record(opp *Opportunity, now time.Time) {
+	k := heatmapKey{pair: opp.Pair, spot: opp.SpotExchange, perp: opp.PerpExchange, reverse: opp.Reverse}
+	minute := now.Truncate(time.Minute).Unix()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	buckets, ok := h.cells[k]
+	if !ok {
+		buckets = make(map[int64]*heatmapBucket)
+		h.cells[k] = buckets
+	}
+	bucket, ok := buckets[minute]
+	if !ok {
+		bucket = &heatmapBucket{}
+		buckets[minute] = bucket
+
+		// A new minute started for this cell, drop the ones past retention
+		cutoff := now.Add(-heatmapRetention).Unix()
+		for m := range buckets {
+			if m < cutoff {
+				delete(buckets, m)
+			}
+		}
+	}
+
+	bucket.count++
+	bucket.sum += opp.SpreadPct
+	bucket.max = math.Max(bucket.max, opp.SpreadPct)
+	bucket.last = now
+}
+
+// Cells returns every pair and venue combination observed within the window, most observed first
+func (h *Heatmap) Cells(window time.Duration, now time.Time) []redis.HeatmapCell {
+	if window > heatmapRetention {
+		window = heatmapRetention
+	}
+	cutoff := now.Add(-window).Truncate(time.Minute).Unix()
+
+	h.mu.Lock()
+	cells := make([]redis.HeatmapCell, 0, len(h.cells))
+	for k, buckets := range h.cells {
+		cell := redis.HeatmapCell{Pair: k.pair, SpotExchange: k.spot, PerpExchange: k.perp, Direction: "forward"}
+		if k.reverse {
+			cell.Direction = "reverse"
+		}
+
+		sum := 0.0
+		for minute, bucket := range buckets {
+			if minute < cutoff {
+				continue
+			}
+			cell.Observations += bucket.count
+			sum += bucket.sum
+			cell.MaxSpreadPct = math.Max(cell.MaxSpreadPct, bucket.max)
+			if bucket.last.After(cell.LastSeen) {
+				cell.LastSeen = bucket.last
+			}
+		}
+		if cell.Observations == 0 {
+			continue
+		}
+		cell.AvgSpreadPct = sum / float64(cell.Observations)
+		cell.PerHour = float64(cell.Observations) / window.Hours()
+		cells = append(cells, cell)
+	}
+	h.mu.Unlock()
+
+	sort.Slice(cells, func(i, j int) bool {
+		if cells[i].Observations != cells[j].Observations {
+			return cells[i].Observations > cells[j].Observations
+		}
+		return cells[i].AvgSpreadPct > cells[j].AvgSpreadPct
+	})
+	return cells
+}
+
+// Heatmap returns the analyzer's observed opportunities within the window, most observed first
+func (a *Analyzer) Heatmap(window time.Duration) []redis.HeatmapCell {
+	return a.heatmap.Cells(window, time.Now())
+}
+
+// StartHeatmapExport publishes the last hour's and day's heatmap to Redis every HEATMAP_EXPORT_INTERVAL
+func (a *Analyzer) StartHeatmapExport() {
+	interval := config.GetDuration("HEATMAP_EXPORT_INTERVAL", 5*time.Minute)
+	if interval <= 0 {
+		return
+	}
+
+	scheduler.Register(scheduler.Job{
+		Name:     "opportunity_heatmap",
+		Schedule: "@every " + interval.String(),
+		Run: func(ctx context.Context, at time.Time) error {
+			now := time.Now()
+			redis.PublishHeatmap(redis.OpportunityHeatmap{
+				Hour:      a.heatmap.Cells(time.Hour, now),
+				Day:       a.heatmap.Cells(24*time.Hour, now),
+				Timestamp: now,
+			})
+			return nil
+		},
+	})
+}
@@ -0,0 +1,193 @@
+package orderbook
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"arbitrage.trade/config"
+)
+
+// Book history
+//
+// Post-trade analysis needs the books as they were when a trade was decided and when its fills
+// landed, not as they are now. Every BOOK_HISTORY_INTERVAL (default 100ms) each of a pair's venue
+// books that changed is snapshotted to its top BOOK_HISTORY_DEPTH (default 20) levels into a ring
+// buffer spanning BOOK_HISTORY_WINDOW (default 30s, 0 disables). BookHistory returns the snapshots
+// of a time range, each venue's last one before the range included as its state at the start.
+
+// BookSnapshot is a venue book's top levels at one moment
+type BookSnapshot struct {
+	At time.Time `json:"at"`
+	VenueBook
+}
+
+// bookRing is one venue book's recent snapshots, oldest overwritten first
+type bookRing struct {
+	snapshots []BookSnapshot
+	next      int
+	full      bool
+	updates   int64 // Book's update count at the last snapshot
+}
+
+func (r *bookRing) push(snapshot BookSnapshot) {
+	r.snapshots[r.next] = snapshot
+	r.next = (r.next + 1) % len(r.snapshots)
+	r.full = r.full || r.next == 0
+}
+
+// ordered returns the snapshots oldest first
+func (r *bookRing) ordered() []BookSnapshot {
+	if !r.full {
+		return r.snapshots[:r.next]
+	}
+	return append(append([]BookSnapshot(nil), r.snapshots[r.next:]...), r.snapshots[:r.next]...)
+}
+
+// bookHistory holds a pair's rings keyed by "<market>:<exchange>"
+type bookHistory struct {
+	mu    sync.Mutex
+	rings map[string]*bookRing
+}
+
+// topLevels returns the book's best depth levels per side and the venue's last update time
+func (ob *OrderBook) topLevels(depth int) ([]PriceLevel, []PriceLevel, time.Time) {
+	ob.mu.RLock()
+	bids := make([]PriceLevel, 0, len(ob.Bids))
+	for price, qty := range ob.Bids {
+		bids = append(bids, PriceLevel{Price: price, Quantity: qty})
+	}
+	asks := make([]PriceLevel, 0, len(ob.Asks))
+	for price, qty := range ob.Asks {
+		asks = append(asks, PriceLevel{Price: price, Quantity: qty})
+	}
+	updated := time.UnixMilli(ob.LastUpdateTs)
+	ob.mu.RUnlock()
+
+	sort.Slice(bids, func(i, j int) bool { return bids[i].Price > bids[j].Price })
+	sort.Slice(asks, func(i, j int) bool { return asks[i].Price < asks[j].Price })
+	return bids[:min(depth, len(bids))], asks[:min(depth, len(asks))], updated
+}
+
+// sampleHistory snapshots the pair's changed books into their rings
+func (pm *PairManager) sampleHistory(now time.Time, capacity, depth int) {
+	h := &pm.history
+	for market, books := range map[string]*ExchangeOrderBooks{"spot": pm.spotBooks, "perp": pm.perpBooks} {
+		books.mu.RLock()
+		for exchange, ob := range books.OrderBooks {
+			ob.mu.RLock()
+			updates, latency, age := ob.Updates, ob.Latency, ob.age()
+			ob.mu.RUnlock()
+
+			key := market + ":" + exchange
+			h.mu.Lock()
+			ring, ok := h.rings[key]
+			if !ok {
+				ring = &bookRing{snapshots: make([]BookSnapshot, capacity), updates: -1}
+				h.rings[key] = ring
+			}
+			changed := ring.updates != updates
+			h.mu.Unlock()
+			if !changed {
+				continue
+			}
+
+			bids, asks, updated := ob.topLevels(depth)
+			snapshot := BookSnapshot{At: now, VenueBook: VenueBook{
+				Exchange:   exchange,
+				Market:     market,
+				Bids:       bids,
+				Asks:       asks,
+				LastUpdate: updated,
+				AgeMs:      durationMs(age),
+				Latency:    latency,
+			}}
+
+			h.mu.Lock()
+			ring.push(snapshot)
+			ring.updates = updates
+			h.mu.Unlock()
+		}
+		books.mu.RUnlock()
+	}
+}
+
+// recordHistory samples the pair's books until the manager stops
+func (pm *PairManager) recordHistory() {
+	window := config.GetDuration("BOOK_HISTORY_WINDOW", 30*time.Second)
+	interval := config.GetDuration("BOOK_HISTORY_INTERVAL", 100*time.Millisecond)
+	if window <= 0 || interval <= 0 {
+		return
+	}
+	capacity := int(window/interval) + 1
+	depth := max(config.GetInt("BOOK_HISTORY_DEPTH", 20), 1)
+
+	pm.history.mu.Lock()
+	pm.history.rings = make(map[string]*bookRing)
+	pm.history.mu.Unlock()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-pm.ctx.Done():
+			return
+		case now := <-ticker.C:
+			pm.sampleHistory(now, capacity, depth)
+		}
+	}
+}
+
+// historyBetween returns the snapshots taken in [from, to] of the given venue books ("spot:binance",
+// all when none), each book's last snapshot before from first
+func (pm *PairManager) historyBetween(from, to time.Time, keys []string) []BookSnapshot {
+	h := &pm.history
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	wanted := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		wanted[key] = true
+	}
+
+	snapshots := make([]BookSnapshot, 0)
+	for key, ring := range h.rings {
+		if len(wanted) > 0 && !wanted[key] {
+			continue
+		}
+		var before *BookSnapshot
+		for _, s := range ring.ordered() {
+			switch {
+			case s.At.Before(from):
+				before = &s
+			case !s.At.After(to):
+				snapshots = append(snapshots, s)
+			}
+		}
+		if before != nil {
+			snapshots = append(snapshots, *before)
+		}
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		if !snapshots[i].At.Equal(snapshots[j].At) {
+			return snapshots[i].At.Before(snapshots[j].At)
+		}
+		if snapshots[i].Market != snapshots[j].Market {
+			return snapshots[i].Market < snapshots[j].Market
+		}
+		return snapshots[i].Exchange < snapshots[j].Exchange
+	})
+	return snapshots
+}
+
+// BookHistory returns a pair's book snapshots in [from, to], limited to the given "<market>:<exchange>"
+// books when any are given; ok is false when the pair is not monitored
+func (gm *GlobalManager) BookHistory(pairName string, from, to time.Time, books ...string) ([]BookSnapshot, bool) {
+	pm, exists := gm.GetPairManager(pairName)
+	if !exists {
+		return nil, false
+	}
+	return pm.historyBetween(from, to, books), true
+}
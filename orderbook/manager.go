@@ -1,57 +1,57 @@
 package orderbook
 
 import (
-	"bytes"
 	"context"
 	"fmt"
-	"log"
-	"strconv"
 	"sync"
 	"time"
 
-	"arbitrage.trade/clients/common"
-	"github.com/gorilla/websocket"
-	"github.com/vmihailenco/msgpack/v5"
+	"arbitrage.trade/chaos"
+	"arbitrage.trade/logging"
+	"arbitrage.trade/outage"
 )
 
-// SignalUpdate represents the raw update from the signal sender
-type SignalUpdate struct {
-	ExchangeName string
-	Bids         map[float64]float64
-	Asks         map[float64]float64
-	Latency      float64
-	LastUpdateTs int64
-}
-
-// PairManager manages orderbooks and WebSocket connections for a trading pair
+// PairManager manages the orderbooks of a trading pair fed by a market data source
 type PairManager struct {
-	pairName    string
-	perpName    string
-	signalURL   string
-	spotBooks   *ExchangeOrderBooks
-	perpBooks   *ExchangeOrderBooks
-	spotConn    *websocket.Conn
-	perpConn    *websocket.Conn
-	mu          sync.Mutex
-	ctx         context.Context
-	cancel      context.CancelFunc
-	reconnectMu sync.Mutex
-	analyzer    *Analyzer // Analyzer to trigger on updates
+	pairName  string
+	source    MarketDataSource
+	spotBooks *ExchangeOrderBooks
+	perpBooks *ExchangeOrderBooks
+	ctx       context.Context
+	cancel    context.CancelFunc
+	analyzer  *Analyzer // Analyzer to trigger on updates
+
+	// Direct exchange feeds for the venues of an open position
+	holdMu       sync.RWMutex
+	holdSpotFeed *VenueFeed
+	holdPerpFeed *VenueFeed
+
+	// Signal feed backpressure: updates wake one analysis worker through a single-slot channel
+	analyzeCh chan struct{}
+	feed      feedMonitor
+
+	// Which sides of the pair have a reliable book, see health.go
+	health healthMonitor
+
+	// Books the price sanity check rejected at the last analysis, see sanity.go
+	outliers outlierTracker
+
+	// Recent book snapshots for post-trade analysis, see history.go
+	history bookHistory
 }
 
 // NewPairManager creates a new manager for a trading pair
-func NewPairManager(pairName, signalURL string) *PairManager {
+func NewPairManager(pairName string, source MarketDataSource) *PairManager {
 	ctx, cancel := context.WithCancel(context.Background())
-	perpName := pairName + "-perp"
 
 	return &PairManager{
 		pairName:  pairName,
-		perpName:  perpName,
-		signalURL: signalURL,
+		source:    source,
 		spotBooks: NewExchangeOrderBooks(),
 		perpBooks: NewExchangeOrderBooks(),
 		ctx:       ctx,
 		cancel:    cancel,
+		analyzeCh: make(chan struct{}, 1),
 	}
 }
 
@@ -60,15 +60,26 @@ func (pm *PairManager) SetAnalyzer(analyzer *Analyzer) {
 	pm.analyzer = analyzer
 }
 
-// Start begins listening to orderbook updates for both spot and perpetual
+// Start subscribes to the pair's spot and perpetual book updates
 func (pm *PairManager) Start() error {
-	log.Printf("[ORDERBOOK] Starting pair manager for %s", pm.pairName)
+	logging.Infof("orderbook", "[ORDERBOOK] Starting pair manager for %s", pm.pairName)
+
+	// Stream spot and perpetual book updates
+	updates, err := pm.source.Subscribe(pm.ctx, pm.pairName)
+	if err != nil {
+		pm.cancel()
+		return fmt.Errorf("failed to subscribe %s: %w", pm.pairName, err)
+	}
+	go pm.consume(updates)
 
-	// Start spot connection
-	go pm.maintainConnection(pm.pairName, true)
+	// Analyze on the latest books, skipping states the analysis can't keep up with
+	go pm.runAnalysis()
 
-	// Start perpetual connection
-	go pm.maintainConnection(pm.perpName, false)
+	// Make a blind side visible instead of looking like no opportunities
+	go pm.watchHealth()
+
+	// Keep the last seconds of books to look back at around executions
+	go pm.recordHistory()
 
 	// Start periodic orderbook printer (every 10 seconds)
 	go pm.printOrderbookPeriodically(10 * time.Second)
@@ -76,236 +87,111 @@ func (pm *PairManager) Start() error {
 	return nil
 }
 
-// Stop closes all connections and stops the manager
+// Stop ends the pair's subscription and stops the manager
 func (pm *PairManager) Stop() {
-	log.Printf("[ORDERBOOK] Stopping pair manager for %s", pm.pairName)
+	logging.Infof("orderbook", "[ORDERBOOK] Stopping pair manager for %s", pm.pairName)
 	pm.cancel()
-
-	pm.mu.Lock()
-	defer pm.mu.Unlock()
-
-	if pm.spotConn != nil {
-		pm.spotConn.Close()
-	}
-	if pm.perpConn != nil {
-		pm.perpConn.Close()
-	}
+	pm.UnwatchVenues()
 }
 
-// maintainConnection maintains a WebSocket connection with auto-reconnect
-func (pm *PairManager) maintainConnection(topic string, isSpot bool) {
-	for {
-		select {
-		case <-pm.ctx.Done():
-			return
-		default:
-			err := pm.connectAndListen(topic, isSpot)
-			if err != nil {
-				log.Printf("[ORDERBOOK] Connection error for %s: %v. Reconnecting in 5s...", topic, err)
-				time.Sleep(5 * time.Second)
-			}
-		}
+// consume merges the source's updates into the books until the subscription ends
+func (pm *PairManager) consume(updates <-chan BookBatch) {
+	for batch := range updates {
+		pm.applyBatch(batch)
 	}
 }
 
-// connectAndListen establishes connection and listens for updates
-func (pm *PairManager) connectAndListen(topic string, isSpot bool) error {
-	conn, _, err := websocket.DefaultDialer.Dial(pm.signalURL, nil)
-	if err != nil {
-		return fmt.Errorf("failed to connect: %w", err)
-	}
-	defer conn.Close()
-
-	// Store connection reference
-	pm.mu.Lock()
-	if isSpot {
-		pm.spotConn = conn
-	} else {
-		pm.perpConn = conn
-	}
-	pm.mu.Unlock()
+// applyBatch merges a batch of venue updates into the books and requests an analysis
+func (pm *PairManager) applyBatch(batch BookBatch) {
+	started := time.Now()
 
-	// Subscribe to topic
-	subscribeMsg := map[string]string{"topic": topic}
-	if err := conn.WriteJSON(subscribeMsg); err != nil {
-		return fmt.Errorf("failed to subscribe: %w", err)
-	}
-
-	log.Printf("[ORDERBOOK] Subscribed to %s", topic)
-
-	// Wait 1 minute for orderbook to fully reconstruct
-	// This prevents false opportunities from incomplete orderbooks
-	log.Printf("[ORDERBOOK] %s - Warming up orderbook for 10 seconds...", topic)
-	time.Sleep(10 * time.Second)
-	log.Printf("[ORDERBOOK] %s - Orderbook ready, starting analysis", topic)
-
-	// Listen for updates
-	for {
-		select {
-		case <-pm.ctx.Done():
-			return nil
-		default:
-			_, message, err := conn.ReadMessage()
-			if err != nil {
-				return fmt.Errorf("read error: %w", err)
-			}
+	for _, update := range batch.Updates {
+		if outage.Active(update.Exchange, outage.WSSilence) || chaos.FeedGap(update.Exchange) {
+			continue
+		}
 
-			if err := pm.processMessage(message, isSpot); err != nil {
-				log.Printf("[ORDERBOOK] Error processing message for %s: %v", topic, err)
-			}
+		// Update the appropriate orderbook (spot or perp)
+		books := pm.spotBooks
+		if !update.Spot {
+			books = pm.perpBooks
 		}
-	}
-}
 
-// processMessage decodes and processes a MessagePack update
-func (pm *PairManager) processMessage(message []byte, isSpot bool) error {
-	// Decode MessagePack - always comes in unified state format:
-	// {
-	//   "pair-name": {
-	//     "exchange1": [[bids, asks], latency, timestamp],
-	//     "exchange2": [[bids, asks], latency, timestamp]
-	//   }
-	// }
-	// This structure is used for scalability - signal can send 1 pair or 100 pairs
-	// using the same format, and we just deep merge into our state
-
-	var rawData map[string]interface{}
-	dec := msgpack.NewDecoder(bytes.NewReader(message))
-	if err := dec.Decode(&rawData); err != nil {
-		return fmt.Errorf("failed to decode msgpack: %w", err)
+		ob := books.GetOrCreate(update.Exchange)
+		ob.Update(update.Bids, update.Asks, update.Latency, update.LastUpdateTs)
 	}
 
-	// Iterate through pairs in the update (usually just one for single subscription)
-	for _, pairValue := range rawData {
-		exchangesData, ok := pairValue.(map[string]interface{})
-		if !ok {
-			continue
-		}
-
-		// Process each exchange in this pair's data
-		for exchangeName, exchangeData := range exchangesData {
-			update, err := pm.parseExchangeData(exchangeName, exchangeData)
-			if err != nil {
-				continue
-			}
+	// The levels are merged into the books, their maps go back to the decode pools
+	batch.release()
 
-			// Update the appropriate orderbook (spot or perp)
-			books := pm.spotBooks
-			if !isSpot {
-				books = pm.perpBooks
-			}
+	// Trigger analysis after processing updates
+	pm.feed.received(batch.DecodeTime + time.Since(started))
+	pm.requestAnalysis()
+}
 
-			ob := books.GetOrCreate(exchangeName)
-			ob.Update(update.Bids, update.Asks, update.Latency, update.LastUpdateTs)
-		}
-	}
+// GetSpotOrderBook returns the spot orderbook for an exchange
+func (pm *PairManager) GetSpotOrderBook(exchangeName string) (*OrderBook, bool) {
+	return pm.spotBooks.GetOrderBook(exchangeName)
+}
 
-	// Trigger analysis after processing updates
-	if pm.analyzer != nil {
-		pm.analyzer.AnalyzePair(pm.pairName)
-	}
+// GetPerpOrderBook returns the perpetual orderbook for an exchange
+func (pm *PairManager) GetPerpOrderBook(exchangeName string) (*OrderBook, bool) {
+	return pm.perpBooks.GetOrderBook(exchangeName)
+}
 
-	return nil
-} // parseExchangeData converts the array format to SignalUpdate
-func (pm *PairManager) parseExchangeData(exchangeName string, data interface{}) (*SignalUpdate, error) {
-	// Data format: [[bids_map, asks_map], latency, lastUpdateTs]
-	dataArray, ok := data.([]interface{})
-	if !ok || len(dataArray) < 3 {
-		return nil, fmt.Errorf("invalid data format")
-	}
+// WatchVenues starts direct exchange feeds for the spot and perp venues of an open position.
+// Each update re-runs exit tracking on the fresh venue quotes.
+func (pm *PairManager) WatchVenues(spotExchange, perpExchange string) {
+	pm.UnwatchVenues()
 
-	// Parse orderbook data [bids, asks]
-	obData, ok := dataArray[0].([]interface{})
-	if !ok || len(obData) < 2 {
-		return nil, fmt.Errorf("invalid orderbook format")
+	onUpdate := func() {
+		if pm.analyzer != nil {
+			pm.analyzer.AnalyzeHold(pm.pairName)
+		}
 	}
 
-	// Parse bids
-	bids, err := pm.parseOrderBookSide(obData[0])
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse bids: %w", err)
+	spotFeed := NewVenueFeed(spotExchange, pm.pairName, true, onUpdate)
+	if err := spotFeed.Start(); err != nil {
+		logging.Infof("orderbook", "[ORDERBOOK] %s - Failed to start spot venue feed for %s: %v", pm.pairName, spotExchange, err)
+		return
 	}
 
-	// Parse asks
-	asks, err := pm.parseOrderBookSide(obData[1])
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse asks: %w", err)
+	perpFeed := NewVenueFeed(perpExchange, pm.pairName, false, onUpdate)
+	if err := perpFeed.Start(); err != nil {
+		logging.Infof("orderbook", "[ORDERBOOK] %s - Failed to start perp venue feed for %s: %v", pm.pairName, perpExchange, err)
+		spotFeed.Stop()
+		return
 	}
 
-	// Parse latency
-	latency := common.ToFloat64(dataArray[1])
-
-	// Parse lastUpdateTs
-	lastUpdateTs := common.ToInt64(dataArray[2])
-
-	return &SignalUpdate{
-		ExchangeName: exchangeName,
-		Bids:         bids,
-		Asks:         asks,
-		Latency:      latency,
-		LastUpdateTs: lastUpdateTs,
-	}, nil
+	pm.holdMu.Lock()
+	pm.holdSpotFeed = spotFeed
+	pm.holdPerpFeed = perpFeed
+	pm.holdMu.Unlock()
 }
 
-// parseOrderBookSide converts map[string]interface{} to map[float64]float64
-func (pm *PairManager) parseOrderBookSide(data interface{}) (map[float64]float64, error) {
-	result := make(map[float64]float64)
-
-	// Try map[interface{}]interface{} first (MessagePack format)
-	if dataMap, ok := data.(map[interface{}]interface{}); ok {
-		for k, v := range dataMap {
-			// Parse price key
-			var price float64
-			switch p := k.(type) {
-			case string:
-				price, _ = strconv.ParseFloat(p, 64)
-			case float64:
-				price = p
-			case float32:
-				price = float64(p)
-			case int:
-				price = float64(p)
-			case int64:
-				price = float64(p)
-			default:
-				// Try to convert to string and parse
-				priceStr := fmt.Sprintf("%v", p)
-				price, _ = strconv.ParseFloat(priceStr, 64)
-			}
+// UnwatchVenues stops the direct exchange feeds started by WatchVenues
+func (pm *PairManager) UnwatchVenues() {
+	pm.holdMu.Lock()
+	defer pm.holdMu.Unlock()
 
-			// Parse quantity value
-			qty := common.ToFloat64(v)
-			if price > 0 { // Only add valid prices
-				result[price] = qty
-			}
-		}
-		return result, nil
+	if pm.holdSpotFeed != nil {
+		pm.holdSpotFeed.Stop()
+		pm.holdSpotFeed = nil
 	}
-
-	// Try map[string]interface{} (alternative format)
-	if dataMap, ok := data.(map[string]interface{}); ok {
-		for k, v := range dataMap {
-			price, _ := strconv.ParseFloat(k, 64)
-			qty := common.ToFloat64(v)
-			if price > 0 {
-				result[price] = qty
-			}
-		}
-		return result, nil
+	if pm.holdPerpFeed != nil {
+		pm.holdPerpFeed.Stop()
+		pm.holdPerpFeed = nil
 	}
-
-	// Empty map is ok
-	return result, nil
 }
 
-// GetSpotOrderBook returns the spot orderbook for an exchange
-func (pm *PairManager) GetSpotOrderBook(exchangeName string) (*OrderBook, bool) {
-	return pm.spotBooks.GetOrderBook(exchangeName)
-}
+// GetHoldOrderBooks returns the direct venue books for an open position, if being watched
+func (pm *PairManager) GetHoldOrderBooks() (spotExchange string, spotOB *OrderBook, perpExchange string, perpOB *OrderBook, ok bool) {
+	pm.holdMu.RLock()
+	defer pm.holdMu.RUnlock()
 
-// GetPerpOrderBook returns the perpetual orderbook for an exchange
-func (pm *PairManager) GetPerpOrderBook(exchangeName string) (*OrderBook, bool) {
-	return pm.perpBooks.GetOrderBook(exchangeName)
+	if pm.holdSpotFeed == nil || pm.holdPerpFeed == nil {
+		return "", nil, "", nil, false
+	}
+	return pm.holdSpotFeed.exchange, pm.holdSpotFeed.Book(), pm.holdPerpFeed.exchange, pm.holdPerpFeed.Book(), true
 }
 
 // printOrderbookPeriodically prints the orderbook state as JSON every interval
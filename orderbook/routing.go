@@ -0,0 +1,107 @@
+package orderbook
+
+import (
+	"math"
+	"sort"
+
+	"arbitrage.trade/capability"
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/config"
+)
+
+// Spot order routing
+//
+// With SPOT_ROUTING_ENABLED (default false), a forward opportunity whose spot venue can't fill the
+// target notional at its best ask buys the rest on other supported spot venues, cheapest ask first,
+// each taking what its top of book offers. A venue only joins when its ask alone keeps the entry
+// spread and its share meets the venue's minimum order size and quantity precision. At most
+// SPOT_ROUTING_MAX_VENUES venues (default 3) share the leg. The perp short stays on one venue.
+
+// SpotRoute is one venue's share of a routed spot leg
+type SpotRoute struct {
+	Exchange  string
+	Price     float64 // Best ask when the leg was routed
+	VolumeUSD float64 // USDT to buy on the venue
+}
+
+// routeSpot splits the opportunity's spot leg across further venues until want USDT is covered
+// Leaves the opportunity on its single spot venue when routing is off or no other venue qualifies
+func (a *Analyzer) routeSpot(pm *PairManager, opp *Opportunity, want float64) {
+	if !config.GetBool("SPOT_ROUTING_ENABLED", false) {
+		return
+	}
+	maxVenues := config.GetInt("SPOT_ROUTING_MAX_VENUES", 3)
+	if maxVenues < 2 {
+		return
+	}
+
+	pm.spotBooks.mu.RLock()
+	exchanges := make([]string, 0, len(pm.spotBooks.OrderBooks))
+	for exName := range pm.spotBooks.OrderBooks {
+		exchanges = append(exchanges, exName)
+	}
+	pm.spotBooks.mu.RUnlock()
+	exchanges = withoutOutliers(exchanges, "spot", pm.outlierSnapshot())
+
+	candidates := make([]SpotRoute, 0, len(exchanges))
+	for _, exchange := range exchanges {
+		if exchange == opp.SpotExchange || exchange == opp.PerpExchange || !a.supportedExchanges[exchange] {
+			continue
+		}
+		if !capability.CanTrade(exchange, pm.pairName, true) {
+			continue
+		}
+		if a.inventory != nil && a.inventory.Holds(exchange, pm.pairName) {
+			continue
+		}
+
+		ob, ok := pm.GetSpotOrderBook(exchange)
+		if !ok || !isReliable(ob) || !a.isWarm(pm, exchange, true) {
+			continue
+		}
+		ask, volume, ok := ob.GetBestAsk()
+		if !ok || common.LessThan(common.SpreadPct(opp.PerpBidPrice, ask), EntrySpreadPct(pm.pairName, exchange, opp.PerpExchange)) {
+			continue
+		}
+		candidates = append(candidates, SpotRoute{Exchange: exchange, Price: ask, VolumeUSD: volume})
+	}
+	if len(candidates) == 0 {
+		return
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].Price < candidates[j].Price
+	})
+
+	routes := []SpotRoute{{Exchange: opp.SpotExchange, Price: opp.SpotAskPrice, VolumeUSD: opp.UsableVolumeUSD}}
+	filled := opp.UsableVolumeUSD
+	for _, candidate := range candidates {
+		if len(routes) >= maxVenues || !common.LessThan(filled, want) {
+			break
+		}
+
+		amount := math.Min(candidate.VolumeUSD, want-filled)
+		if common.LessThan(amount, capability.MinOrderVolume(candidate.Exchange, pm.pairName, true, candidate.Price)) ||
+			!capability.CanFill(candidate.Exchange, pm.pairName, true, candidate.Price, amount) {
+			continue
+		}
+
+		candidate.VolumeUSD = amount
+		routes = append(routes, candidate)
+		filled += amount
+	}
+	if len(routes) < 2 {
+		return
+	}
+
+	// The spread is taken against the quantity-weighted ask of all routes
+	quantity := 0.0
+	for _, route := range routes {
+		quantity += route.VolumeUSD / route.Price
+	}
+
+	opp.SpotRoutes = routes
+	opp.UsableVolumeUSD = filled
+	opp.SpreadPct = common.SpreadPct(opp.PerpBidPrice, filled/quantity)
+	opp.Score = opp.SpreadPct + opp.FundingBias - opp.FeePct
+}
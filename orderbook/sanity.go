@@ -0,0 +1,138 @@
+package orderbook
+
+import (
+	"math"
+	"sort"
+	"sync"
+
+	"arbitrage.trade/config"
+	"arbitrage.trade/logging"
+)
+
+// Price sanity
+//
+// A venue occasionally sends a corrupt price (off by 10x or 100x) that shows up as a huge phantom
+// spread. Before each analysis every reliable venue book of the pair, spot and perp alike, has its mid
+// compared to the median mid across them; a book more than PRICE_SANITY_BAND_PCT (default 20, 0
+// disables) away from it, or whose own ask is that far above its bid, is left out of the analysis.
+// With only two books there is no majority, so both are left out when they disagree by the band.
+// Venues are logged when they start and stop being rejected; rejections are counted in /feed/stats.
+
+// outlierTracker remembers which of a pair's books were rejected, to log only changes
+type outlierTracker struct {
+	mu       sync.Mutex
+	rejected map[string]bool // "spot:<exchange>" or "perp:<exchange>"
+}
+
+// bookMid returns the mid of a reliable two-sided book (ok false otherwise) and whether its own
+// ask is beyond the band above its bid
+func bookMid(ob *OrderBook, band float64) (mid float64, ok bool, wide bool) {
+	if !isReliable(ob) {
+		return 0, false, false
+	}
+	bid, _, bidOk := ob.GetBestBid()
+	ask, _, askOk := ob.GetBestAsk()
+	if !bidOk || !askOk || bid <= 0 || ask <= 0 {
+		return 0, false, false
+	}
+	return (bid + ask) / 2, true, ask > bid*(1+band/100)
+}
+
+// priceOutliers returns the pair's books whose prices are off the cross-venue median beyond the band
+func (pm *PairManager) priceOutliers() map[string]bool {
+	band := config.GetFloat("PRICE_SANITY_BAND_PCT", 20)
+	if band <= 0 {
+		return nil
+	}
+
+	mids := make(map[string]float64)
+	outliers := make(map[string]bool)
+	for market, books := range map[string]*ExchangeOrderBooks{"spot": pm.spotBooks, "perp": pm.perpBooks} {
+		books.mu.RLock()
+		for exchange, ob := range books.OrderBooks {
+			if mid, ok, wide := bookMid(ob, band); ok {
+				mids[market+":"+exchange] = mid
+				if wide {
+					outliers[market+":"+exchange] = true
+				}
+			}
+		}
+		books.mu.RUnlock()
+	}
+
+	values := make([]float64, 0, len(mids))
+	for key, mid := range mids {
+		if !outliers[key] {
+			values = append(values, mid)
+		}
+	}
+	sort.Float64s(values)
+
+	median := 0.0
+	if n := len(values); n > 0 {
+		median = values[n/2]
+		if n%2 == 0 {
+			median = (values[n/2-1] + values[n/2]) / 2
+		}
+	}
+
+	for key, mid := range mids {
+		if outliers[key] || median <= 0 {
+			continue
+		}
+		off := false
+		if len(values) == 2 {
+			off = values[1] > values[0]*(1+band/100)
+		} else {
+			off = math.Abs(mid-median)/median*100 > band
+		}
+		if off {
+			outliers[key] = true
+		}
+	}
+
+	pm.reportOutliers(outliers, mids, median)
+	return outliers
+}
+
+// reportOutliers logs books that started or stopped being rejected and counts the rejections
+func (pm *PairManager) reportOutliers(outliers map[string]bool, mids map[string]float64, median float64) {
+	t := &pm.outliers
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for key := range outliers {
+		if !t.rejected[key] {
+			logging.Warnf("analyzer", "[SANITY] %s - Ignoring %s: mid %.8g vs cross-venue median %.8g",
+				pm.pairName, key, mids[key], median)
+		}
+	}
+	for key := range t.rejected {
+		if !outliers[key] {
+			logging.Infof("analyzer", "[SANITY] %s - %s back within the band", pm.pairName, key)
+		}
+	}
+	t.rejected = outliers
+	pm.feed.rejected(int64(len(outliers)))
+}
+
+// outlierSnapshot returns the books rejected at the pair's last analysis
+func (pm *PairManager) outlierSnapshot() map[string]bool {
+	pm.outliers.mu.Lock()
+	defer pm.outliers.mu.Unlock()
+	return pm.outliers.rejected
+}
+
+// withoutOutliers returns the exchanges of the market that are not outliers
+func withoutOutliers(exchanges []string, market string, outliers map[string]bool) []string {
+	if len(outliers) == 0 {
+		return exchanges
+	}
+	kept := make([]string, 0, len(exchanges))
+	for _, exchange := range exchanges {
+		if !outliers[market+":"+exchange] {
+			kept = append(kept, exchange)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,314 @@
+package orderbook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/logging"
+	"github.com/gorilla/websocket"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// SignalSource streams books from the signal server, one WebSocket per pair and market
+type SignalSource struct {
+	url      string
+	activity topicActivity // Last message per topic, see stall.go
+}
+
+// NewSignalSource creates a source reading the signal server at url
+func NewSignalSource(url string) *SignalSource {
+	return &SignalSource{url: url, activity: topicActivity{last: make(map[string]time.Time)}}
+}
+
+// Subscribe opens the pair's spot ("btc-usdt") and perp ("btc-usdt-perp") topics
+func (s *SignalSource) Subscribe(ctx context.Context, pairName string) (<-chan BookBatch, error) {
+	out := make(chan BookBatch, 1)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		s.maintainConnection(ctx, pairName, true, out)
+	}()
+	go func() {
+		defer wg.Done()
+		s.maintainConnection(ctx, pairName+"-perp", false, out)
+	}()
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	return out, nil
+}
+
+// maintainConnection maintains a WebSocket connection with auto-reconnect
+func (s *SignalSource) maintainConnection(ctx context.Context, topic string, isSpot bool, out chan<- BookBatch) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+			err := s.connectAndListen(ctx, topic, isSpot, out)
+			if err != nil && ctx.Err() == nil {
+				delay := common.WSLimitsFor("signal").ReconnectDelay(err, 5*time.Second)
+				logging.Infof("orderbook", "[ORDERBOOK] Connection error for %s: %v. Reconnecting in %s...", topic, err, delay)
+				time.Sleep(delay)
+			}
+		}
+	}
+}
+
+// connectAndListen establishes connection and listens for updates
+func (s *SignalSource) connectAndListen(ctx context.Context, topic string, isSpot bool, out chan<- BookBatch) error {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, s.url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer conn.Close()
+
+	limits := common.WSLimitsFor("signal")
+	conn.SetReadLimit(limits.ReadLimit)
+	guard := limits.NewRateGuard()
+
+	// Unblock the read below when the subscription ends
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	// Subscribe to topic
+	subscribeMsg := map[string]string{"topic": topic}
+	if err := conn.WriteJSON(subscribeMsg); err != nil {
+		return fmt.Errorf("failed to subscribe: %w", err)
+	}
+
+	logging.Infof("orderbook", "[ORDERBOOK] Subscribed to %s", topic)
+
+	// Wait for orderbook to fully reconstruct
+	// This prevents false opportunities from incomplete orderbooks
+	logging.Infof("orderbook", "[ORDERBOOK] %s - Warming up orderbook for 10 seconds...", topic)
+	select {
+	case <-ctx.Done():
+		return nil
+	case <-time.After(10 * time.Second):
+	}
+	logging.Infof("orderbook", "[ORDERBOOK] %s - Orderbook ready, starting analysis", topic)
+
+	// Resubscribe when the topic goes silent while the others flow
+	watch := &topicWatch{topic: topic}
+	s.activity.seen(topic, time.Now())
+	defer s.activity.forget(topic)
+	go s.watchStall(conn, watch, done)
+
+	// Listen for updates
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if watch.stalled.Load() {
+				return errStalled
+			}
+			return readError(err)
+		}
+		now := time.Now()
+		s.activity.seen(topic, now)
+		if err := guard.Received(now); err != nil {
+			return err
+		}
+
+		batch, err := decodeSignalMessage(message, isSpot)
+		if err != nil {
+			logging.Infof("orderbook", "[ORDERBOOK] Error processing message for %s: %v", topic, err)
+			continue
+		}
+
+		watch.sending.Store(true)
+		select {
+		case out <- batch:
+		case <-ctx.Done():
+			return nil
+		}
+		watch.sending.Store(false)
+		s.activity.seen(topic, time.Now())
+	}
+}
+
+// readError wraps a failed read, marking messages over the read limit as abuse
+func readError(err error) error {
+	if errors.Is(err, websocket.ErrReadLimit) {
+		return fmt.Errorf("%w: %v", common.ErrWSAbuse, err)
+	}
+	return fmt.Errorf("read error: %w", err)
+}
+
+// decodeSignalMessage decodes a MessagePack update
+func decodeSignalMessage(message []byte, isSpot bool) (BookBatch, error) {
+	// Decode MessagePack - always comes in unified state format:
+	// {
+	//   "pair-name": {
+	//     "exchange1": [[bids, asks], latency, timestamp],
+	//     "exchange2": [[bids, asks], latency, timestamp]
+	//   }
+	// }
+	// This structure is used for scalability - signal can send 1 pair or 100 pairs
+	// using the same format, and we just deep merge into our state.
+	// The message is walked token by token into pooled level maps rather than decoded into generic
+	// maps, see decode_pool.go
+
+	started := time.Now()
+
+	dec, reader := acquireDecoder(message)
+	defer releaseDecoder(dec, reader)
+
+	batch := BookBatch{Updates: acquireUpdates()}
+	pairs, ok, err := decodeMapLen(dec)
+	if err == nil && !ok {
+		err = fmt.Errorf("top level is not a map")
+	}
+	if err != nil {
+		batch.release()
+		return BookBatch{}, fmt.Errorf("failed to decode msgpack: %w", err)
+	}
+
+	// Iterate through pairs in the update (usually just one for single subscription)
+	for i := 0; i < pairs; i++ {
+		if err := dec.Skip(); err != nil { // Pair name, the subscription already identifies it
+			batch.release()
+			return BookBatch{}, fmt.Errorf("failed to decode msgpack: %w", err)
+		}
+		exchanges, ok, err := decodeMapLen(dec)
+		if err != nil {
+			batch.release()
+			return BookBatch{}, fmt.Errorf("failed to decode msgpack: %w", err)
+		}
+		if !ok {
+			continue
+		}
+
+		// Process each exchange in this pair's data, skipping malformed entries
+		for j := 0; j < exchanges; j++ {
+			exchangeName, err := dec.DecodeString()
+			if err != nil {
+				batch.release()
+				return BookBatch{}, fmt.Errorf("failed to decode msgpack: %w", err)
+			}
+			update, ok, err := decodeExchangeData(dec, exchangeName)
+			if err != nil {
+				batch.release()
+				return BookBatch{}, fmt.Errorf("failed to decode msgpack: %w", err)
+			}
+			if !ok {
+				continue
+			}
+			update.Spot = isSpot
+			batch.Updates = append(batch.Updates, update)
+		}
+	}
+
+	batch.DecodeTime = time.Since(started)
+	return batch, nil
+}
+
+// decodeExchangeData reads one venue's [[bids, asks], latency, lastUpdateTs] entry
+// ok is false for a malformed entry, which is consumed whole; err means the message itself is broken
+func decodeExchangeData(dec *msgpack.Decoder, exchangeName string) (BookUpdate, bool, error) {
+	n, ok, err := decodeArrayLen(dec)
+	if err != nil || !ok {
+		return BookUpdate{}, false, err
+	}
+	if n < 3 {
+		return BookUpdate{}, false, skipN(dec, n)
+	}
+
+	// Parse orderbook data [bids, asks]
+	sides, ok, err := decodeArrayLen(dec)
+	if err != nil {
+		return BookUpdate{}, false, err
+	}
+	if !ok || sides < 2 {
+		if err := skipN(dec, sides); err != nil {
+			return BookUpdate{}, false, err
+		}
+		return BookUpdate{}, false, skipN(dec, n-1)
+	}
+	bids, err := decodeOrderBookSide(dec)
+	if err != nil {
+		return BookUpdate{}, false, err
+	}
+	asks, err := decodeOrderBookSide(dec)
+	if err != nil {
+		releaseLevels(bids)
+		return BookUpdate{}, false, err
+	}
+	if err := skipN(dec, sides-2); err != nil {
+		releaseLevels(bids)
+		releaseLevels(asks)
+		return BookUpdate{}, false, err
+	}
+
+	// Parse latency and lastUpdateTs
+	latency, _, err := decodeNumber(dec)
+	if err == nil {
+		var lastUpdateTs float64
+		lastUpdateTs, _, err = decodeNumber(dec)
+		if err == nil {
+			err = skipN(dec, n-3)
+		}
+		if err == nil {
+			return BookUpdate{
+				Exchange:     exchangeName,
+				Bids:         bids,
+				Asks:         asks,
+				Latency:      latency,
+				LastUpdateTs: int64(lastUpdateTs),
+			}, true, nil
+		}
+	}
+	releaseLevels(bids)
+	releaseLevels(asks)
+	return BookUpdate{}, false, err
+}
+
+// decodeOrderBookSide reads a price -> quantity map into pooled levels; prices may be strings or numbers
+// Anything but a map reads as an empty side
+func decodeOrderBookSide(dec *msgpack.Decoder) (map[float64]float64, error) {
+	levels := acquireLevels()
+
+	n, ok, err := decodeMapLen(dec)
+	if err != nil {
+		releaseLevels(levels)
+		return nil, err
+	}
+	if !ok {
+		return levels, nil
+	}
+
+	for i := 0; i < n; i++ {
+		price, _, err := decodeNumber(dec)
+		if err != nil {
+			releaseLevels(levels)
+			return nil, err
+		}
+		qty, _, err := decodeNumber(dec)
+		if err != nil {
+			releaseLevels(levels)
+			return nil, err
+		}
+		if price > 0 { // Only add valid prices
+			levels[price] = qty
+		}
+	}
+	return levels, nil
+}
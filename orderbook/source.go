@@ -0,0 +1,37 @@
+package orderbook
+
+import (
+	"context"
+	"time"
+)
+
+// Market data sources
+//
+// PairManager keeps books and analyzes them without knowing where the updates come from: a
+// MarketDataSource streams a pair's venue book updates, spot and perp, and the manager merges them.
+// The signal server (SignalSource) is the live one; exchange WebSockets, recorded files or generated
+// books plug in by implementing Subscribe and passing the source to NewGlobalManager.
+
+// BookUpdate is one venue's book change: levels to merge, a zero quantity removes the level
+type BookUpdate struct {
+	Exchange     string
+	Spot         bool // Spot book, perp otherwise
+	Bids         map[float64]float64
+	Asks         map[float64]float64
+	Latency      float64 // Venue to source latency in ms, as reported by the source
+	LastUpdateTs int64   // Venue timestamp in ms
+}
+
+// BookBatch is the updates a source delivers together, merged before one analysis
+// The manager recycles the updates' level maps once merged, so sources must not keep them
+type BookBatch struct {
+	Updates    []BookUpdate
+	DecodeTime time.Duration // Time the source spent decoding the batch, shown in /feed/stats
+}
+
+// MarketDataSource streams book updates for trading pairs
+type MarketDataSource interface {
+	// Subscribe streams the pair's spot and perp book updates until ctx is done, then closes the
+	// channel; the source reconnects on its own and returns an error only if it can't serve the pair
+	Subscribe(ctx context.Context, pairName string) (<-chan BookBatch, error)
+}
@@ -0,0 +1,99 @@
+package orderbook
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"arbitrage.trade/config"
+	"arbitrage.trade/logging"
+	"github.com/gorilla/websocket"
+)
+
+// Stall detection
+//
+// A signal topic can go silent while its TCP connection stays up: the upstream subscription died
+// but nothing closes the socket. When a topic has delivered nothing for SIGNAL_STALL_TIMEOUT (default
+// 30s, 0 disables) while other topics of the same source kept flowing within that time, its
+// connection is closed and resubscribed. A source where every topic is silent is left to reconnect
+// on its own, since that is the signal server or the network, not one subscription.
+
+var errStalled = errors.New("topic stalled while other topics flowed")
+
+// topicActivity is when each of a source's topics last delivered a message
+type topicActivity struct {
+	mu   sync.Mutex
+	last map[string]time.Time
+}
+
+func (a *topicActivity) seen(topic string, at time.Time) {
+	a.mu.Lock()
+	a.last[topic] = at
+	a.mu.Unlock()
+}
+
+func (a *topicActivity) forget(topic string) {
+	a.mu.Lock()
+	delete(a.last, topic)
+	a.mu.Unlock()
+}
+
+// lastSeen returns when the topic last delivered a message
+func (a *topicActivity) lastSeen(topic string) time.Time {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.last[topic]
+}
+
+// others returns the latest message time of any topic but the given one, zero when none
+func (a *topicActivity) others(topic string) time.Time {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var latest time.Time
+	for t, at := range a.last {
+		if t != topic && at.After(latest) {
+			latest = at
+		}
+	}
+	return latest
+}
+
+// topicWatch tracks one connection's topic for stall detection
+type topicWatch struct {
+	topic   string
+	sending atomic.Bool // Blocked handing a batch to a slow consumer, not a stall
+	stalled atomic.Bool
+}
+
+// watchStall closes conn once its topic stalled while the source's other topics flowed, until done closes
+func (s *SignalSource) watchStall(conn *websocket.Conn, watch *topicWatch, done <-chan struct{}) {
+	timeout := config.GetDuration("SIGNAL_STALL_TIMEOUT", 30*time.Second)
+	if timeout <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(min(timeout/4, time.Second))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case now := <-ticker.C:
+			if watch.sending.Load() {
+				continue
+			}
+			last, others := s.activity.lastSeen(watch.topic), s.activity.others(watch.topic)
+
+			if now.Sub(last) < timeout || others.IsZero() || now.Sub(others) >= timeout {
+				continue
+			}
+			logging.Warnf("orderbook", "[ORDERBOOK] %s - No message for %s while other topics flow, resubscribing", watch.topic, now.Sub(last).Round(time.Second))
+			watch.stalled.Store(true)
+			conn.Close()
+			return
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package orderbook
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"arbitrage.trade/config"
+	"arbitrage.trade/logging"
+)
+
+// Entry spread thresholds
+//
+// An entry needs the spread to reach ENTRY_SPREAD_PCT (default 1.5). Fees and slippage differ by venue
+// combination, so ENTRY_SPREAD_OVERRIDES sets it per pair, spot venue and perp venue as comma separated
+// "<pair>:<spot venue>:<perp venue>=<pct>" entries, any field "*" (trailing ones may be left out):
+//
+//	ENTRY_SPREAD_OVERRIDES=*:*:whitebit=2.0,btc-usdt=1.2,btc-usdt:binance:okx=1.0
+//
+// The most specific matching entry wins (perp venue over spot venue over pair, then the later entry),
+// so above btc-usdt shorts on whitebit need 2.0% and btc-usdt between binance and okx 1.0%. The
+// combination is the same in both directions: a reverse trade sells on its spot venue.
+
+// ThresholdOverride is one ENTRY_SPREAD_OVERRIDES entry, "" fields match any value
+type ThresholdOverride struct {
+	Pair         string  `json:"pair,omitempty"`
+	SpotExchange string  `json:"spot_exchange,omitempty"`
+	PerpExchange string  `json:"perp_exchange,omitempty"`
+	SpreadPct    float64 `json:"spread_pct"`
+}
+
+// specificity ranks how narrowly the override matches, the perp venue weighing most
+func (o ThresholdOverride) specificity() int {
+	rank := 0
+	if o.Pair != "" {
+		rank++
+	}
+	if o.SpotExchange != "" {
+		rank += 2
+	}
+	if o.PerpExchange != "" {
+		rank += 4
+	}
+	return rank
+}
+
+func (o ThresholdOverride) matches(pairName, spotExchange, perpExchange string) bool {
+	return (o.Pair == "" || o.Pair == pairName) &&
+		(o.SpotExchange == "" || o.SpotExchange == spotExchange) &&
+		(o.PerpExchange == "" || o.PerpExchange == perpExchange)
+}
+
+// parseThresholdOverride parses "<pair>[:<spot venue>[:<perp venue>]]=<pct>"
+func parseThresholdOverride(entry string) (ThresholdOverride, error) {
+	key, value, ok := strings.Cut(entry, "=")
+	if !ok {
+		return ThresholdOverride{}, fmt.Errorf("missing =<pct> in %q", entry)
+	}
+	pct, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || pct < 0 {
+		return ThresholdOverride{}, fmt.Errorf("invalid spread %q in %q", value, entry)
+	}
+
+	fields := strings.Split(strings.TrimSpace(key), ":")
+	if len(fields) > 3 {
+		return ThresholdOverride{}, fmt.Errorf("too many fields in %q", entry)
+	}
+	for len(fields) < 3 {
+		fields = append(fields, "*")
+	}
+	for i, field := range fields {
+		if field = strings.ToLower(strings.TrimSpace(field)); field == "*" {
+			field = ""
+		}
+		fields[i] = field
+	}
+	return ThresholdOverride{Pair: fields[0], SpotExchange: fields[1], PerpExchange: fields[2], SpreadPct: pct}, nil
+}
+
+var (
+	overridesMu  sync.Mutex
+	overridesRaw string
+	overrides    []ThresholdOverride
+)
+
+// ThresholdOverrides returns the parsed ENTRY_SPREAD_OVERRIDES, logging malformed entries once
+func ThresholdOverrides() []ThresholdOverride {
+	raw := config.GetString("ENTRY_SPREAD_OVERRIDES", "")
+
+	overridesMu.Lock()
+	defer overridesMu.Unlock()
+
+	if raw == overridesRaw {
+		return overrides
+	}
+	overridesRaw, overrides = raw, nil
+	for _, entry := range strings.Split(raw, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		override, err := parseThresholdOverride(entry)
+		if err != nil {
+			logging.Warnf("analyzer", "[ANALYZER] Ignoring ENTRY_SPREAD_OVERRIDES entry: %v", err)
+			continue
+		}
+		overrides = append(overrides, override)
+	}
+	return overrides
+}
+
+// EntrySpreadPct returns the spread in % an entry on the pair between the spot and perp venues needs
+func EntrySpreadPct(pairName, spotExchange, perpExchange string) float64 {
+	threshold := config.GetFloat("ENTRY_SPREAD_PCT", 1.5)
+	best := -1
+	for _, o := range ThresholdOverrides() {
+		if o.matches(pairName, spotExchange, perpExchange) && o.specificity() >= best {
+			best = o.specificity()
+			threshold = o.SpreadPct
+		}
+	}
+	return threshold
+}
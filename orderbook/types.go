@@ -3,12 +3,14 @@ package orderbook
 import (
 	"sync"
 	"time"
+
+	"arbitrage.trade/clients/common"
 )
 
 // PriceLevel represents a single price level in the orderbook
 type PriceLevel struct {
-	Price    float64
-	Quantity float64
+	Price    float64 `json:"price"`
+	Quantity float64 `json:"quantity"`
 }
 
 // OrderBook represents the current state of bids and asks for an exchange
@@ -17,7 +19,10 @@ type OrderBook struct {
 	Bids         map[float64]float64 // price -> quantity
 	Asks         map[float64]float64 // price -> quantity
 	Latency      float64
-	LastUpdateTs int64
+	LastUpdateTs int64     // Source wall-clock time of the data, Unix ms
+	ReceivedAt   time.Time // Local receipt, its monotonic reading keeps age math immune to clock steps
+	Updates      int64     // Messages applied since the book was created
+	transit      time.Duration
 }
 
 // NewOrderBook creates a new empty orderbook
@@ -52,7 +57,72 @@ func (ob *OrderBook) Update(bids, asks map[float64]float64, latency float64, las
 	}
 
 	ob.Latency = latency
+	ob.stamp(lastUpdateTs)
+	ob.Updates++
+}
+
+// Replace discards the current levels and installs the given ones
+// Used by top-of-book feeds where every message is a full snapshot
+func (ob *OrderBook) Replace(bids, asks map[float64]float64, latency float64, lastUpdateTs int64) {
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+
+	ob.Bids = bids
+	ob.Asks = asks
+	ob.Latency = latency
+	ob.stamp(lastUpdateTs)
+	ob.Updates++
+}
+
+// stamp records when the data was produced and received. The source's delay is measured once here,
+// the only wall-clock comparison; from then on age counts on the monotonic clock
+// Callers must hold ob.mu.
+func (ob *OrderBook) stamp(lastUpdateTs int64) {
 	ob.LastUpdateTs = lastUpdateTs
+	ob.ReceivedAt = time.Now()
+	ob.transit = 0
+	if lastUpdateTs > 0 {
+		ob.transit = max(0, ob.ReceivedAt.Sub(time.UnixMilli(lastUpdateTs)))
+	}
+}
+
+// age returns how old the book's data is: its source delay at receipt plus the time since
+// Callers must hold ob.mu.
+func (ob *OrderBook) age() time.Duration {
+	if ob.ReceivedAt.IsZero() {
+		return time.Duration(1<<63 - 1)
+	}
+	return ob.transit + time.Since(ob.ReceivedAt)
+}
+
+// Age returns how old the book's data is, measured on the monotonic clock since it was received
+func (ob *OrderBook) Age() time.Duration {
+	ob.mu.RLock()
+	defer ob.mu.RUnlock()
+	return ob.age()
+}
+
+// durationMs returns d in milliseconds, keeping sub-millisecond precision
+func durationMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// Depth returns the USDT volume resting on the thinner side of the book
+func (ob *OrderBook) Depth() float64 {
+	ob.mu.RLock()
+	defer ob.mu.RUnlock()
+
+	bids, asks := 0.0, 0.0
+	for _, qty := range ob.Bids {
+		bids += qty
+	}
+	for _, qty := range ob.Asks {
+		asks += qty
+	}
+	if bids < asks {
+		return bids
+	}
+	return asks
 }
 
 // GetBestBid returns the highest bid price
@@ -95,6 +165,27 @@ func (ob *OrderBook) GetBestAsk() (float64, float64, bool) {
 	return bestPrice, bestQty, true
 }
 
+// LevelVolume returns the volume resting at an exact price level (0 if absent)
+func (ob *OrderBook) LevelVolume(isBid bool, price float64) float64 {
+	ob.mu.RLock()
+	defer ob.mu.RUnlock()
+
+	levels := ob.Asks
+	if isBid {
+		levels = ob.Bids
+	}
+
+	if qty, ok := levels[price]; ok {
+		return qty
+	}
+	for p, qty := range levels {
+		if common.Equal(p, price) {
+			return qty
+		}
+	}
+	return 0
+}
+
 // GetSnapshot returns sorted bids and asks
 func (ob *OrderBook) GetSnapshot() ([]PriceLevel, []PriceLevel, time.Time) {
 	ob.mu.RLock()
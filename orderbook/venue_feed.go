@@ -0,0 +1,562 @@
+package orderbook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"arbitrage.trade/chaos"
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/clients/cryptocom"
+	"arbitrage.trade/clients/hyperliquid"
+	"arbitrage.trade/clients/kraken"
+	"arbitrage.trade/logging"
+	"arbitrage.trade/outage"
+)
+
+// VenueFeed streams top-of-book directly from one exchange's public WebSocket.
+// It is only used while a position is open, so exit logic sees the venue's own
+// quotes instead of waiting for the aggregated signal feed.
+type VenueFeed struct {
+	exchange string
+	pairName string
+	isSpot   bool
+	book     *OrderBook
+	onUpdate func()
+	ctx      context.Context
+	cancel   context.CancelFunc
+}
+
+// venueStream describes how to connect to and decode an exchange book-ticker stream
+type venueStream struct {
+	url          string
+	subscribe    interface{}   // nil when the subscription is part of the URL
+	pingMessage  []byte        // nil when the exchange uses WebSocket-level pings
+	pingInterval time.Duration // how often to send pingMessage
+	parse        func(message []byte) (bid, bidQty, ask, askQty float64, eventTs int64, ok bool)
+	multiplier   float64                     // Base units per quoted unit for perps listed per 1000 units, 0 when 1:1
+	respond      func(message []byte) []byte // Reply to a server-initiated heartbeat, nil when none is needed
+}
+
+// NewVenueFeed creates a feed for one exchange and market of a pair
+func NewVenueFeed(exchange, pairName string, isSpot bool, onUpdate func()) *VenueFeed {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &VenueFeed{
+		exchange: exchange,
+		pairName: pairName,
+		isSpot:   isSpot,
+		book:     NewOrderBook(),
+		onUpdate: onUpdate,
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// Book returns the top-of-book maintained by this feed
+func (vf *VenueFeed) Book() *OrderBook {
+	return vf.book
+}
+
+// Start connects in the background and reconnects until Stop is called
+func (vf *VenueFeed) Start() error {
+	stream, err := venueStreamFor(vf.exchange, vf.pairName, vf.isSpot)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		for {
+			select {
+			case <-vf.ctx.Done():
+				return
+			default:
+				if err := vf.connectAndListen(stream); err != nil {
+					delay := common.WSLimitsFor("venue").ReconnectDelay(err, time.Second)
+					logging.Infof("orderbook", "[VENUE FEED] %s %s %s error: %v. Reconnecting in %s...", vf.exchange, vf.pairName, vf.market(), err, delay)
+					time.Sleep(delay)
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Stop closes the feed
+func (vf *VenueFeed) Stop() {
+	vf.cancel()
+}
+
+func (vf *VenueFeed) market() string {
+	if vf.isSpot {
+		return "spot"
+	}
+	return "perp"
+}
+
+func (vf *VenueFeed) connectAndListen(stream *venueStream) error {
+	conn, _, err := websocket.DefaultDialer.DialContext(vf.ctx, stream.url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer conn.Close()
+
+	limits := common.WSLimitsFor("venue")
+	conn.SetReadLimit(limits.ReadLimit)
+	guard := limits.NewRateGuard()
+
+	// Close the connection when the feed is stopped so ReadMessage unblocks
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-vf.ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	var writeMu sync.Mutex
+	if stream.subscribe != nil {
+		if err := conn.WriteJSON(stream.subscribe); err != nil {
+			return fmt.Errorf("failed to subscribe: %w", err)
+		}
+	}
+
+	if stream.pingMessage != nil {
+		go func() {
+			ticker := time.NewTicker(stream.pingInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-done:
+					return
+				case <-ticker.C:
+					writeMu.Lock()
+					conn.WriteMessage(websocket.TextMessage, stream.pingMessage)
+					writeMu.Unlock()
+				}
+			}
+		}()
+	}
+
+	logging.Infof("orderbook", "[VENUE FEED] Subscribed to %s %s %s", vf.exchange, vf.pairName, vf.market())
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			if vf.ctx.Err() != nil {
+				return nil
+			}
+			return readError(err)
+		}
+		if err := guard.Received(time.Now()); err != nil {
+			return err
+		}
+
+		if stream.respond != nil {
+			if reply := stream.respond(message); reply != nil {
+				writeMu.Lock()
+				err := conn.WriteMessage(websocket.TextMessage, reply)
+				writeMu.Unlock()
+				if err != nil {
+					return fmt.Errorf("failed to answer heartbeat: %w", err)
+				}
+				continue
+			}
+		}
+
+		bid, bidQty, ask, askQty, eventTs, ok := stream.parse(message)
+		if !ok || outage.Active(vf.exchange, outage.WSSilence) || chaos.FeedGap(vf.exchange) {
+			continue
+		}
+		if stream.multiplier > 1 {
+			bid, ask = bid/stream.multiplier, ask/stream.multiplier
+			bidQty, askQty = bidQty*stream.multiplier, askQty*stream.multiplier
+		}
+
+		now := time.Now().UnixMilli()
+		latency := 0.0
+		if eventTs > 0 && eventTs <= now {
+			latency = float64(now - eventTs)
+		}
+
+		// Volumes are kept in USDT to match the signal feed books
+		vf.book.Replace(
+			map[float64]float64{bid: bidQty * bid},
+			map[float64]float64{ask: askQty * ask},
+			latency,
+			now,
+		)
+
+		if vf.onUpdate != nil {
+			vf.onUpdate()
+		}
+	}
+}
+
+// venueStreamFor returns the book-ticker stream definition for an exchange market
+func venueStreamFor(exchange, pairName string, isSpot bool) (*venueStream, error) {
+	parts := strings.Split(strings.ToUpper(pairName), "-")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid pair name: %s", pairName)
+	}
+	base, quote := parts[0], parts[1]
+
+	switch exchange {
+	case "binance":
+		symbol := strings.ToLower(base + quote)
+		url := "wss://stream.binance.com:9443/ws/" + symbol + "@bookTicker"
+		multiplier := 0.0
+		if !isSpot {
+			if override, ok := common.FuturesSymbolOverride(exchange, pairName); ok {
+				symbol, multiplier = strings.ToLower(override.Symbol), override.Multiplier
+			}
+			url = "wss://fstream.binance.com/ws/" + symbol + "@bookTicker"
+		}
+		return &venueStream{url: url, parse: parseBinanceBookTicker, multiplier: multiplier}, nil
+
+	case "okx":
+		instId := base + "-" + quote
+		if !isSpot {
+			instId += "-SWAP"
+		}
+		return &venueStream{
+			url: "wss://ws.okx.com:8443/ws/v5/public",
+			subscribe: map[string]interface{}{
+				"op":   "subscribe",
+				"args": []map[string]string{{"channel": "bbo-tbt", "instId": instId}},
+			},
+			pingMessage:  []byte("ping"),
+			pingInterval: 20 * time.Second,
+			parse:        parseLevelsBookTicker,
+		}, nil
+
+	case "bitget":
+		instType := "SPOT"
+		if !isSpot {
+			instType = "USDT-FUTURES"
+		}
+		return &venueStream{
+			url: "wss://ws.bitget.com/v2/ws/public",
+			subscribe: map[string]interface{}{
+				"op":   "subscribe",
+				"args": []map[string]string{{"instType": instType, "channel": "books1", "instId": base + quote}},
+			},
+			pingMessage:  []byte("ping"),
+			pingInterval: 25 * time.Second,
+			parse:        parseLevelsBookTicker,
+		}, nil
+
+	case "whitebit":
+		market := base + "_" + quote
+		if !isSpot {
+			market = base + "_PERP"
+		}
+		return &venueStream{
+			url: "wss://api.whitebit.com/ws",
+			subscribe: map[string]interface{}{
+				"id":     1,
+				"method": "bookTicker_subscribe",
+				"params": []string{market},
+			},
+			pingMessage:  []byte(`{"id":0,"method":"ping","params":[]}`),
+			pingInterval: 30 * time.Second,
+			parse:        parseWhitebitBookTicker,
+		}, nil
+
+	case "gate":
+		url := "wss://api.gateio.ws/ws/v4/"
+		channel := "spot.book_ticker"
+		if !isSpot {
+			url = "wss://fx-ws.gateio.ws/v4/ws/usdt"
+			channel = "futures.book_ticker"
+		}
+		return &venueStream{
+			url: url,
+			subscribe: map[string]interface{}{
+				"time":    time.Now().Unix(),
+				"channel": channel,
+				"event":   "subscribe",
+				"payload": []string{base + "_" + quote},
+			},
+			parse: parseGateBookTicker,
+		}, nil
+
+	case "kraken":
+		// Spot WebSocket v2 uses ISO codes (BTC/USDT); Kraken Futures keeps XBT in PF_XBTUSD
+		if !isSpot {
+			return &venueStream{
+				url: "wss://futures.kraken.com/ws/v1",
+				subscribe: map[string]interface{}{
+					"event":       "subscribe",
+					"feed":        "ticker",
+					"product_ids": []string{kraken.FuturesSymbol(pairName)},
+				},
+				parse: parseKrakenFuturesTicker,
+			}, nil
+		}
+		return &venueStream{
+			url: "wss://ws.kraken.com/v2",
+			subscribe: map[string]interface{}{
+				"method": "subscribe",
+				"params": map[string]interface{}{
+					"channel": "ticker",
+					"symbol":  []string{base + "/" + quote},
+				},
+			},
+			parse: parseKrakenTicker,
+		}, nil
+
+	case "cryptocom":
+		instrument := cryptocom.SpotSymbol(pairName)
+		if !isSpot {
+			instrument = cryptocom.PerpSymbol(pairName)
+		}
+		return &venueStream{
+			url: "wss://stream.crypto.com/exchange/v1/market",
+			subscribe: map[string]interface{}{
+				"id":     1,
+				"method": "subscribe",
+				"params": map[string]interface{}{"channels": []string{"ticker." + instrument}},
+				"nonce":  time.Now().UnixMilli(),
+			},
+			respond: respondCryptocomHeartbeat,
+			parse:   parseCryptocomTicker,
+		}, nil
+
+	case "hyperliquid":
+		if isSpot {
+			return nil, fmt.Errorf("no spot venue feed for exchange: %s", exchange)
+		}
+		coin, multiplier := hyperliquid.Coin(pairName)
+		return &venueStream{
+			url: "wss://api.hyperliquid.xyz/ws",
+			subscribe: map[string]interface{}{
+				"method":       "subscribe",
+				"subscription": map[string]string{"type": "bbo", "coin": coin},
+			},
+			pingMessage:  []byte(`{"method":"ping"}`),
+			pingInterval: 50 * time.Second,
+			parse:        parseHyperliquidBbo,
+			multiplier:   multiplier,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("no venue feed for exchange: %s", exchange)
+}
+
+// parseBinanceBookTicker decodes {"b":"..","B":"..","a":"..","A":"..","E":ms}
+func parseBinanceBookTicker(message []byte) (float64, float64, float64, float64, int64, bool) {
+	var msg struct {
+		Bid       string `json:"b"`
+		BidQty    string `json:"B"`
+		Ask       string `json:"a"`
+		AskQty    string `json:"A"`
+		EventTime int64  `json:"E"`
+	}
+	if err := json.Unmarshal(message, &msg); err != nil || msg.Bid == "" || msg.Ask == "" {
+		return 0, 0, 0, 0, 0, false
+	}
+
+	bid, _ := strconv.ParseFloat(msg.Bid, 64)
+	bidQty, _ := strconv.ParseFloat(msg.BidQty, 64)
+	ask, _ := strconv.ParseFloat(msg.Ask, 64)
+	askQty, _ := strconv.ParseFloat(msg.AskQty, 64)
+	return bid, bidQty, ask, askQty, msg.EventTime, bid > 0 && ask > 0
+}
+
+// parseLevelsBookTicker decodes the OKX bbo-tbt / Bitget books1 shape:
+// {"data":[{"bids":[["px","sz",...]],"asks":[["px","sz",...]],"ts":"ms"}]}
+func parseLevelsBookTicker(message []byte) (float64, float64, float64, float64, int64, bool) {
+	var msg struct {
+		Data []struct {
+			Bids [][]string `json:"bids"`
+			Asks [][]string `json:"asks"`
+			Ts   string     `json:"ts"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(message, &msg); err != nil || len(msg.Data) == 0 {
+		return 0, 0, 0, 0, 0, false
+	}
+
+	d := msg.Data[0]
+	if len(d.Bids) == 0 || len(d.Asks) == 0 || len(d.Bids[0]) < 2 || len(d.Asks[0]) < 2 {
+		return 0, 0, 0, 0, 0, false
+	}
+
+	bid, _ := strconv.ParseFloat(d.Bids[0][0], 64)
+	bidQty, _ := strconv.ParseFloat(d.Bids[0][1], 64)
+	ask, _ := strconv.ParseFloat(d.Asks[0][0], 64)
+	askQty, _ := strconv.ParseFloat(d.Asks[0][1], 64)
+	ts, _ := strconv.ParseInt(d.Ts, 10, 64)
+	return bid, bidQty, ask, askQty, ts, bid > 0 && ask > 0
+}
+
+// parseWhitebitBookTicker decodes
+// {"method":"bookTicker_update","params":[[ts, txTs, market, updateId, bid, bidQty, ask, askQty]]}
+func parseWhitebitBookTicker(message []byte) (float64, float64, float64, float64, int64, bool) {
+	var msg struct {
+		Method string          `json:"method"`
+		Params [][]interface{} `json:"params"`
+	}
+	if err := json.Unmarshal(message, &msg); err != nil || msg.Method != "bookTicker_update" || len(msg.Params) == 0 {
+		return 0, 0, 0, 0, 0, false
+	}
+
+	p := msg.Params[0]
+	if len(p) < 8 {
+		return 0, 0, 0, 0, 0, false
+	}
+
+	bid := jsonNumber(p[4])
+	ask := jsonNumber(p[6])
+	eventTs := int64(jsonNumber(p[0]) * 1000)
+	return bid, jsonNumber(p[5]), ask, jsonNumber(p[7]), eventTs, bid > 0 && ask > 0
+}
+
+// parseGateBookTicker decodes {"event":"update","result":{"t":ms,"b":"..","B":..,"a":"..","A":..}}
+func parseGateBookTicker(message []byte) (float64, float64, float64, float64, int64, bool) {
+	var msg struct {
+		Event  string `json:"event"`
+		Result struct {
+			T      int64       `json:"t"`
+			Bid    interface{} `json:"b"`
+			BidQty interface{} `json:"B"`
+			Ask    interface{} `json:"a"`
+			AskQty interface{} `json:"A"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(message, &msg); err != nil || msg.Event != "update" {
+		return 0, 0, 0, 0, 0, false
+	}
+
+	bid := jsonNumber(msg.Result.Bid)
+	ask := jsonNumber(msg.Result.Ask)
+	return bid, jsonNumber(msg.Result.BidQty), ask, jsonNumber(msg.Result.AskQty), msg.Result.T, bid > 0 && ask > 0
+}
+
+// parseKrakenTicker decodes the spot v2 shape:
+// {"channel":"ticker","type":"update","data":[{"bid":..,"bid_qty":..,"ask":..,"ask_qty":..}]}
+// Tickers carry no event time, so latency is not measured
+func parseKrakenTicker(message []byte) (float64, float64, float64, float64, int64, bool) {
+	var msg struct {
+		Channel string `json:"channel"`
+		Data    []struct {
+			Bid    float64 `json:"bid"`
+			BidQty float64 `json:"bid_qty"`
+			Ask    float64 `json:"ask"`
+			AskQty float64 `json:"ask_qty"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(message, &msg); err != nil || msg.Channel != "ticker" || len(msg.Data) == 0 {
+		return 0, 0, 0, 0, 0, false
+	}
+
+	d := msg.Data[0]
+	return d.Bid, d.BidQty, d.Ask, d.AskQty, 0, d.Bid > 0 && d.Ask > 0
+}
+
+// parseKrakenFuturesTicker decodes {"feed":"ticker","bid":..,"bid_size":..,"ask":..,"ask_size":..,"time":ms}
+func parseKrakenFuturesTicker(message []byte) (float64, float64, float64, float64, int64, bool) {
+	var msg struct {
+		Feed    string  `json:"feed"`
+		Bid     float64 `json:"bid"`
+		BidSize float64 `json:"bid_size"`
+		Ask     float64 `json:"ask"`
+		AskSize float64 `json:"ask_size"`
+		Time    int64   `json:"time"`
+	}
+	if err := json.Unmarshal(message, &msg); err != nil || msg.Feed != "ticker" {
+		return 0, 0, 0, 0, 0, false
+	}
+
+	return msg.Bid, msg.BidSize, msg.Ask, msg.AskSize, msg.Time, msg.Bid > 0 && msg.Ask > 0
+}
+
+// parseCryptocomTicker decodes
+// {"method":"subscribe","result":{"channel":"ticker","data":[{"b":"..","bs":"..","k":"..","ks":"..","t":ms}]}}
+func parseCryptocomTicker(message []byte) (float64, float64, float64, float64, int64, bool) {
+	var msg struct {
+		Result struct {
+			Channel string `json:"channel"`
+			Data    []struct {
+				Bid    string `json:"b"`
+				BidQty string `json:"bs"`
+				Ask    string `json:"k"`
+				AskQty string `json:"ks"`
+				T      int64  `json:"t"`
+			} `json:"data"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(message, &msg); err != nil || msg.Result.Channel != "ticker" || len(msg.Result.Data) == 0 {
+		return 0, 0, 0, 0, 0, false
+	}
+
+	d := msg.Result.Data[0]
+	bid, _ := strconv.ParseFloat(d.Bid, 64)
+	bidQty, _ := strconv.ParseFloat(d.BidQty, 64)
+	ask, _ := strconv.ParseFloat(d.Ask, 64)
+	askQty, _ := strconv.ParseFloat(d.AskQty, 64)
+	return bid, bidQty, ask, askQty, d.T, bid > 0 && ask > 0
+}
+
+// respondCryptocomHeartbeat answers {"id":n,"method":"public/heartbeat"}; unanswered heartbeats close the connection
+func respondCryptocomHeartbeat(message []byte) []byte {
+	var msg struct {
+		ID     int64  `json:"id"`
+		Method string `json:"method"`
+	}
+	if err := json.Unmarshal(message, &msg); err != nil || msg.Method != "public/heartbeat" {
+		return nil
+	}
+	return []byte(fmt.Sprintf(`{"id":%d,"method":"public/respond-heartbeat"}`, msg.ID))
+}
+
+// parseHyperliquidBbo decodes {"channel":"bbo","data":{"time":ms,"bbo":[{"px":"..","sz":".."},{"px":"..","sz":".."}]}}
+func parseHyperliquidBbo(message []byte) (float64, float64, float64, float64, int64, bool) {
+	var msg struct {
+		Channel string `json:"channel"`
+		Data    struct {
+			Time int64 `json:"time"`
+			Bbo  []*struct {
+				Px string `json:"px"`
+				Sz string `json:"sz"`
+			} `json:"bbo"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(message, &msg); err != nil || msg.Channel != "bbo" || len(msg.Data.Bbo) < 2 {
+		return 0, 0, 0, 0, 0, false
+	}
+
+	// A side is null when that side of the book is empty
+	bidLevel, askLevel := msg.Data.Bbo[0], msg.Data.Bbo[1]
+	if bidLevel == nil || askLevel == nil {
+		return 0, 0, 0, 0, 0, false
+	}
+
+	bid, _ := strconv.ParseFloat(bidLevel.Px, 64)
+	bidQty, _ := strconv.ParseFloat(bidLevel.Sz, 64)
+	ask, _ := strconv.ParseFloat(askLevel.Px, 64)
+	askQty, _ := strconv.ParseFloat(askLevel.Sz, 64)
+	return bid, bidQty, ask, askQty, msg.Data.Time, bid > 0 && ask > 0
+}
+
+// jsonNumber converts a decoded JSON value that may be a number or numeric string
+func jsonNumber(v interface{}) float64 {
+	switch val := v.(type) {
+	case float64:
+		return val
+	case string:
+		f, _ := strconv.ParseFloat(val, 64)
+		return f
+	default:
+		return 0
+	}
+}
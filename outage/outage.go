@@ -0,0 +1,137 @@
+package outage
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"arbitrage.trade/config"
+)
+
+// Venue outage simulation
+//
+// In paper mode (PAPER_TRADING=true) PAPER_OUTAGES injects venue outages on a fixed schedule
+// counted from startup, so leg rollback, book staleness handling and the reject back-off can be
+// exercised deterministically, e.g. in CI. Scenarios are comma-separated
+// "<exchange>:<kind>@<start>+<duration>", for example
+// PAPER_OUTAGES=binance:rest_5xx@30s+20s,okx:ws_silence@1m+45s,gate:partial@0s+10m
+//
+//	rest_5xx    every REST call to the venue fails as a 503 would
+//	ws_silence  the venue's books receive no updates and go stale
+//	partial     orders fill only PAPER_OUTAGE_FILL_RATIO (default 0.5) of their size
+//
+// Live trading ignores the setting.
+
+// Kind is the failure a scenario injects
+type Kind string
+
+const (
+	REST5xx      Kind = "rest_5xx"
+	WSSilence    Kind = "ws_silence"
+	PartialFills Kind = "partial"
+)
+
+// Scenario is one scheduled venue outage
+type Scenario struct {
+	Exchange string
+	Kind     Kind
+	Start    time.Duration // Offset from startup
+	Duration time.Duration
+}
+
+var (
+	scenarios []Scenario
+	startedAt time.Time
+	loadOnce  sync.Once
+)
+
+// Start loads the schedule and starts its clock; later calls are no-ops
+func Start() {
+	loadOnce.Do(load)
+}
+
+func load() {
+	startedAt = time.Now()
+	if !config.GetBool("PAPER_TRADING", false) {
+		return
+	}
+
+	for _, entry := range strings.Split(config.GetString("PAPER_OUTAGES", ""), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		scenario, err := parseScenario(entry)
+		if err != nil {
+			log.Printf("[OUTAGE] Skipping %q: %v", entry, err)
+			continue
+		}
+		scenarios = append(scenarios, scenario)
+		log.Printf("[OUTAGE] %s %s scheduled from +%s for %s", scenario.Exchange, scenario.Kind, scenario.Start, scenario.Duration)
+	}
+}
+
+// parseScenario parses "<exchange>:<kind>@<start>+<duration>"
+func parseScenario(entry string) (Scenario, error) {
+	venue, window, ok := strings.Cut(entry, "@")
+	if !ok {
+		return Scenario{}, fmt.Errorf("missing @<start>+<duration>")
+	}
+	exchange, kind, ok := strings.Cut(venue, ":")
+	if !ok {
+		return Scenario{}, fmt.Errorf("missing <exchange>:<kind>")
+	}
+	switch Kind(kind) {
+	case REST5xx, WSSilence, PartialFills:
+	default:
+		return Scenario{}, fmt.Errorf("unknown kind %q", kind)
+	}
+
+	start, length, ok := strings.Cut(window, "+")
+	if !ok {
+		return Scenario{}, fmt.Errorf("missing +<duration>")
+	}
+	startOffset, err := time.ParseDuration(start)
+	if err != nil {
+		return Scenario{}, fmt.Errorf("invalid start: %w", err)
+	}
+	duration, err := time.ParseDuration(length)
+	if err != nil || duration <= 0 {
+		return Scenario{}, fmt.Errorf("invalid duration %q", length)
+	}
+
+	return Scenario{
+		Exchange: strings.ToLower(exchange),
+		Kind:     Kind(kind),
+		Start:    startOffset,
+		Duration: duration,
+	}, nil
+}
+
+// Active reports whether an outage of the kind is in progress on the exchange
+func Active(exchange string, kind Kind) bool {
+	Start()
+	if len(scenarios) == 0 {
+		return false
+	}
+
+	elapsed := time.Since(startedAt)
+	for _, scenario := range scenarios {
+		if scenario.Kind == kind && scenario.Exchange == exchange &&
+			elapsed >= scenario.Start && elapsed < scenario.Start+scenario.Duration {
+			return true
+		}
+	}
+	return false
+}
+
+// FillRatio returns the share of an order's size filled during a partial-fill outage
+func FillRatio() float64 {
+	ratio := config.GetFloat("PAPER_OUTAGE_FILL_RATIO", 0.5)
+	if ratio <= 0 || ratio >= 1 {
+		return 0.5
+	}
+	return ratio
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"log"
+	"math"
+	"sync"
+
+	"arbitrage.trade/clients"
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/config"
+	"arbitrage.trade/storage"
+)
+
+// Partial close
+//
+// With PARTIAL_CLOSE_AT set (convergence in %, default 0 disables), a forward position whose
+// convergence reaches it closes PARTIAL_CLOSE_FRACTION of both legs (default 0.5) and keeps the
+// rest open for the final target, a reversal or max hold. Spreads that stall halfway then still
+// bank part of their edge. It fires once per position, only below the final target, and only
+// when every venue of the position supports partial closes; otherwise the position is closed
+// as a whole as before. Reduce orders leave the venues' USDT balance snapshots alone, so the final
+// close's balance diff already holds what the partial close realized; PartialSpotProfit and
+// PartialFuturesProfit only feed live P&L and the stop loss until then.
+
+// partialCloseAt returns the convergence in % that triggers the partial close, 0 when disabled
+func partialCloseAt() float64 {
+	return config.GetFloat("PARTIAL_CLOSE_AT", 0)
+}
+
+// partialCloseFraction returns the share of the position closed at the intermediate target
+func partialCloseFraction() float64 {
+	fraction := config.GetFloat("PARTIAL_CLOSE_FRACTION", 0.5)
+	if fraction <= 0 || fraction >= 1 {
+		return 0.5
+	}
+	return fraction
+}
+
+// partialCloseDue reports whether the position should close part of its legs at this convergence
+// Callers must hold p.mu.
+func (p *ArbitragePosition) partialCloseDue(convergence float64) bool {
+	at := partialCloseAt()
+	if at <= 0 || p.PartialClosed || p.Reverse || p.Inverse {
+		return false
+	}
+	return convergence >= at
+}
+
+// waitPartialClose blocks until an in-flight partial close finished, so the final close sees what is left
+func (p *ArbitragePosition) waitPartialClose() {
+	p.mu.RLock()
+	done := p.partialDone
+	p.mu.RUnlock()
+	if done != nil {
+		<-done
+	}
+}
+
+// partialClose closes the configured share of a forward position's perp short and spot routes
+// Callers must have set PartialClosed and partialDone under p.mu.
+func partialClose(position *ArbitragePosition) {
+	position.mu.RLock()
+	done := position.partialDone
+	closeSpread := position.CurrentSpread
+	closeShort, closeLong := position.CurrentShort, position.CurrentLong
+	spotFill, perpFill := position.SpotFill, position.PerpFill
+	position.mu.RUnlock()
+	defer close(done)
+
+	routes := position.spotRoutes()
+	for _, exchange := range append([]common.ExchangeType{position.ShortExchange}, routeExchanges(routes)...) {
+		if !clients.SupportsPartialClose(exchange) {
+			log.Printf("[PARTIAL %s] %s does not support partial closes - holding the full position", position.PairName, exchange)
+			return
+		}
+	}
+
+	fraction := partialCloseFraction()
+	routed := 0.0
+	for _, route := range routes {
+		routed += route.AmountUSDT
+	}
+	if common.IsNegativeOrZero(perpFill.Quantity) || common.IsNegativeOrZero(spotFill.Quantity) || common.IsNegativeOrZero(routed) {
+		log.Printf("[PARTIAL %s] Open fills unknown - holding the full position", position.PairName)
+		return
+	}
+
+	log.Printf("[PARTIAL %s] Closing %.0f%% | Entry: %.2f%% | Current: %.2f%%",
+		position.PairName, fraction*100.0, position.EntrySpread, closeSpread)
+
+	ctx := position.tradeContext()
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	spotProfit, futuresProfit := 0.00, 0.00
+	spotClosed, perpClosed := 0.0, 0.0
+	wg.Add(1 + len(routes))
+
+	go func() {
+		defer wg.Done()
+		result, err := clients.Reduce(ctx, position.ShortExchange, common.CloseFuturesShort, position.PairName, perpFill.Quantity*fraction, closeSpread)
+		if err != nil {
+			log.Printf("[ERROR] Failed to partially close futures short: %v", err)
+			return
+		}
+		position.addFill("close_perp", result, closeShort, true)
+		if result != nil {
+			mu.Lock()
+			perpClosed += result.ExecutedQty
+			futuresProfit += result.ExecutedQty*(perpFill.AvgPrice()-result.ExecutedPrice) - result.Fee
+			mu.Unlock()
+		}
+	}()
+
+	for _, route := range routes {
+		go func(route storage.SpotRoute) {
+			defer wg.Done()
+			exchange := common.ExchangeType(route.Exchange)
+			quantity := spotFill.Quantity * fraction * route.AmountUSDT / routed
+			result, err := clients.Reduce(ctx, exchange, common.CloseSpotLong, position.PairName, quantity, closeSpread)
+			if err != nil {
+				log.Printf("[ERROR] Failed to partially close spot long on %s: %v", exchange, err)
+				return
+			}
+			position.addVenueFill("close_spot", exchange, result, closeLong, false)
+			if result != nil {
+				mu.Lock()
+				spotClosed += result.ExecutedQty
+				spotProfit += result.ExecutedQty*(result.ExecutedPrice-spotFill.AvgPrice()) - result.Fee
+				mu.Unlock()
+			}
+		}(route)
+	}
+
+	wg.Wait()
+
+	// What is left open is tracked against the same entry prices, so convergence keeps its meaning
+	position.mu.Lock()
+	position.PartialSpotProfit += spotProfit
+	position.PartialFuturesProfit += futuresProfit
+	position.SpotFill.Notional -= math.Min(spotClosed, position.SpotFill.Quantity) * spotFill.AvgPrice()
+	position.SpotFill.Quantity = math.Max(position.SpotFill.Quantity-spotClosed, 0)
+	position.PerpFill.Notional -= math.Min(perpClosed, position.PerpFill.Quantity) * perpFill.AvgPrice()
+	position.PerpFill.Quantity = math.Max(position.PerpFill.Quantity-perpClosed, 0)
+	position.mu.Unlock()
+	persistPositions()
+
+	log.Printf("[PARTIAL %s] Realized: %.4f USDT | Spot: %.4f | Futures: %.4f",
+		position.PairName, spotProfit+futuresProfit, spotProfit, futuresProfit)
+}
+
+// routeExchanges returns the venues of a spot leg's routes
+func routeExchanges(routes []storage.SpotRoute) []common.ExchangeType {
+	exchanges := make([]common.ExchangeType, 0, len(routes))
+	for _, route := range routes {
+		exchanges = append(exchanges, common.ExchangeType(route.Exchange))
+	}
+	return exchanges
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+
+	"arbitrage.trade/clients"
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/orderbook"
+	"arbitrage.trade/storage"
+)
+
+// Forward entries follow the execution playbook of their spot and perp venues (EXEC_PLAYBOOKS, see
+// clients/playbook.go): the order the legs go out in, an extra preflight right before submission and
+// how strictly the opened legs are verified. Leg execution modes travel with the trade's context.
+
+// openPerpLeg sends the perp short for amountUSDT and returns the base quantity it filled
+// A failed leg marks the position as not open
+func (p *ArbitragePosition) openPerpLeg(ctx context.Context, amountUSDT, spreadPct float64) float64 {
+	command := common.PutFuturesShort
+	if p.Inverse {
+		command = common.PutInverseShort
+	}
+	result, _, err := clients.Execute(legContext(ctx, p.EntryShortPrice, false), p.ShortExchange, command, p.PairName, amountUSDT, spreadPct)
+	p.addFill("open_perp", result, p.EntryShortPrice, false)
+	if err != nil {
+		log.Printf("[ERROR] Failed to open futures short: %v", err)
+		p.mu.Lock()
+		p.IsOpen = false
+		p.mu.Unlock()
+	}
+	if result == nil {
+		return 0
+	}
+	return result.ExecutedQty
+}
+
+// openSpotLegs buys the spot routes in parallel and returns the base quantity they filled
+// A failed leg marks the position as not open
+func (p *ArbitragePosition) openSpotLegs(ctx context.Context, routes []storage.SpotRoute, spreadPct float64) float64 {
+	var wg sync.WaitGroup
+	var filledMu sync.Mutex
+	filled := 0.0
+
+	for _, route := range routes {
+		wg.Add(1)
+		go func(route storage.SpotRoute) {
+			defer wg.Done()
+			exchange := common.ExchangeType(route.Exchange)
+			result, _, err := clients.Execute(legContext(ctx, route.Price, true), exchange, common.PutSpotLong, p.PairName, route.AmountUSDT, spreadPct)
+			p.addVenueFill("open_spot", exchange, result, route.Price, true)
+			if err != nil {
+				log.Printf("[ERROR] Failed to open spot long on %s: %v", exchange, err)
+				p.mu.Lock()
+				p.IsOpen = false
+				p.mu.Unlock()
+			}
+			if result != nil {
+				filledMu.Lock()
+				filled += result.ExecutedQty
+				filledMu.Unlock()
+			}
+		}(route)
+	}
+
+	wg.Wait()
+	return filled
+}
+
+// openForwardLegs sends a forward entry's legs in the playbook's order
+// Sequential legs only send the second side once the first filled, sized to what it filled
+func (p *ArbitragePosition) openForwardLegs(ctx context.Context, playbook clients.Playbook, spreadPct float64) {
+	routes := p.spotRoutes()
+
+	switch playbook.Order {
+	case clients.OrderPerpFirst:
+		filled := p.openPerpLeg(ctx, p.AmountUSDT, spreadPct)
+		ratio, ok := p.firstLegFilled(filled, p.EntryShortPrice, p.AmountUSDT)
+		if !ok {
+			return
+		}
+		if ratio < 1 {
+			scaled := make([]storage.SpotRoute, len(routes))
+			for i, route := range routes {
+				route.AmountUSDT *= ratio
+				scaled[i] = route
+			}
+			routes = scaled
+			p.mu.Lock()
+			p.SpotRoutes = scaled
+			p.mu.Unlock()
+			log.Printf("[PLAYBOOK %s] %s - Perp filled %.1f%%, spot sized to match", p.PairName, playbook.Name, ratio*100)
+		}
+		p.openSpotLegs(ctx, routes, spreadPct)
+
+	case clients.OrderSpotFirst:
+		filled := p.openSpotLegs(ctx, routes, spreadPct)
+		ratio, ok := p.firstLegFilled(filled, p.EntryLongPrice, p.AmountUSDT)
+		if !ok {
+			return
+		}
+		if ratio < 1 {
+			log.Printf("[PLAYBOOK %s] %s - Spot filled %.1f%%, perp sized to match", p.PairName, playbook.Name, ratio*100)
+		}
+		p.openPerpLeg(ctx, p.AmountUSDT*ratio, spreadPct)
+
+	default:
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			p.openPerpLeg(ctx, p.AmountUSDT, spreadPct)
+		}()
+		go func() {
+			defer wg.Done()
+			p.openSpotLegs(ctx, routes, spreadPct)
+		}()
+		wg.Wait()
+	}
+}
+
+// firstLegFilled returns the share of its notional a sequential entry's first side filled, and whether
+// the second side should be sent at all
+func (p *ArbitragePosition) firstLegFilled(filled, price, amountUSDT float64) (float64, bool) {
+	p.mu.RLock()
+	isOpen := p.IsOpen
+	p.mu.RUnlock()
+
+	if !isOpen || common.IsNegativeOrZero(filled) || common.IsNegativeOrZero(amountUSDT) {
+		if isOpen {
+			p.mu.Lock()
+			p.IsOpen = false
+			p.mu.Unlock()
+		}
+		log.Printf("[PLAYBOOK %s] First leg did not fill, second leg not sent", p.PairName)
+		return 0, false
+	}
+	return min(filled*price/amountUSDT, 1), true
+}
+
+// preflightEntry re-checks an entry right before submission: no venue of its legs holds the pair,
+// even with the presence guard off, and the venues' books still show the entry spread
+func preflightEntry(ctx context.Context, position *ArbitragePosition) error {
+	if err := checkExistingExposure(ctx, position); err != nil {
+		return fmt.Errorf("preflight: %w", err)
+	}
+	if globalBooks == nil {
+		return nil
+	}
+
+	pm, ok := globalBooks.GetPairManager(position.PairName)
+	if !ok {
+		return fmt.Errorf("preflight: no books for %s", position.PairName)
+	}
+	spotOB, spotOk := pm.GetSpotOrderBook(string(position.LongExchange))
+	perpOB, perpOk := pm.GetPerpOrderBook(string(position.ShortExchange))
+	if !spotOk || !perpOk {
+		return fmt.Errorf("preflight: missing %s spot or %s perp book", position.LongExchange, position.ShortExchange)
+	}
+	ask, _, askOk := spotOB.GetBestAsk()
+	bid, _, bidOk := perpOB.GetBestBid()
+	if !askOk || !bidOk || common.IsNegativeOrZero(ask) {
+		return fmt.Errorf("preflight: empty %s spot or %s perp book", position.LongExchange, position.ShortExchange)
+	}
+
+	spread := (bid - ask) / ask * 100.0
+	threshold := orderbook.EntrySpreadPct(position.PairName, string(position.LongExchange), string(position.ShortExchange))
+	if common.LessThan(spread, threshold) {
+		return fmt.Errorf("preflight: spread fell to %.3f%% (entry %.3f%%, needs %.2f%%)", spread, position.EntrySpread, threshold)
+	}
+	return nil
+}
+
+// verifyOpenedLegs confirms on the venues that every spot venue holds what it reported filling and
+// the perp venue holds a short
+func verifyOpenedLegs(ctx context.Context, position *ArbitragePosition) error {
+	position.mu.RLock()
+	filled := make(map[common.ExchangeType]float64)
+	for _, leg := range position.Legs {
+		if leg.Leg == "open_spot" {
+			filled[common.ExchangeType(leg.Exchange)] += leg.Quantity
+		}
+	}
+	position.mu.RUnlock()
+
+	for exchange, quantity := range filled {
+		holding, err := clients.SpotHolding(ctx, exchange, position.PairName)
+		if err != nil {
+			return fmt.Errorf("verify: %s spot holding: %w", exchange, err)
+		}
+		// Fees charged in the base asset leave slightly less than the reported fill
+		if common.LessThan(holding, quantity*0.98) {
+			return fmt.Errorf("verify: %s holds %.8g %s, reported fill %.8g", exchange, holding, position.PairName, quantity)
+		}
+	}
+
+	flat, err := clients.IsFlat(ctx, position.ShortExchange, position.perpMarket(), position.PairName)
+	if err != nil {
+		return fmt.Errorf("verify: %s %s position: %w", position.ShortExchange, position.perpMarket(), err)
+	}
+	if flat {
+		return fmt.Errorf("verify: %s reported a %s fill but holds no position", position.ShortExchange, position.perpMarket())
+	}
+	return nil
+}
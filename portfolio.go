@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/config"
+	"arbitrage.trade/inventory"
+	"arbitrage.trade/redis"
+	"arbitrage.trade/scheduler"
+	"arbitrage.trade/storage"
+)
+
+// Portfolio exposure
+//
+// Each position is hedged on its own, but a leg filled short of its counterpart, a partial close or
+// the inventory strategy's standing holdings leave directional exposure that only shows summed up.
+// The portfolio view nets every open or closing position's filled legs (spot bought on each venue,
+// perp shorts and reverse perp longs, less what was closed) with the tracked inventory per base asset,
+// and sums gross notional per venue, marked at the positions' live marks or fill prices and the
+// inventory's spot mids. It is served on GET /portfolio and published every PORTFOLIO_INTERVAL
+// (default 30s, 0 disables) on arbitrage-portfolio-exposure.
+
+// startPortfolio registers the periodic portfolio exposure publish
+func startPortfolio() {
+	interval := config.GetDuration("PORTFOLIO_INTERVAL", 30*time.Second)
+	if interval <= 0 {
+		return
+	}
+
+	scheduler.Register(scheduler.Job{
+		Name:     "portfolio",
+		Schedule: "@every " + interval.String(),
+		Run: func(ctx context.Context, at time.Time) error {
+			redis.PublishPortfolio(currentPortfolio())
+			return nil
+		},
+	})
+}
+
+// portfolioSource serves the portfolio exposure to the operator API
+type portfolioSource struct{}
+
+// Portfolio returns the current portfolio exposure
+func (portfolioSource) Portfolio() redis.Portfolio {
+	return currentPortfolio()
+}
+
+// currentPortfolio nets the tracked positions' legs and the inventory per asset and venue
+func currentPortfolio() redis.Portfolio {
+	assets := make(map[string]*redis.AssetExposure)
+	venues := make(map[string]*redis.VenueExposure)
+	asset := func(pairName string) *redis.AssetExposure {
+		name := strings.ToUpper(strings.Split(pairName, "-")[0])
+		exposure, ok := assets[name]
+		if !ok {
+			exposure = &redis.AssetExposure{Asset: name}
+			assets[name] = exposure
+		}
+		return exposure
+	}
+	venue := func(exchange string) *redis.VenueExposure {
+		exposure, ok := venues[exchange]
+		if !ok {
+			exposure = &redis.VenueExposure{Exchange: exchange}
+			venues[exchange] = exposure
+		}
+		return exposure
+	}
+
+	for _, s := range (positionSource{}).Positions() {
+		if !s.IsOpen && !s.IsClosing {
+			continue
+		}
+		exposure := asset(s.Pair)
+		exposure.Positions++
+
+		spotPrice, perpPrice := s.SpotFill.AvgPrice(), s.PerpFill.AvgPrice()
+		if s.LivePnL != nil {
+			spotPrice, perpPrice = s.LivePnL.SpotMark, s.LivePnL.PerpMark
+		}
+		if common.IsZero(exposure.Price) {
+			exposure.Price = spotPrice
+		}
+		if common.IsZero(exposure.Price) {
+			exposure.Price = perpPrice
+		}
+
+		// A reverse spot leg is sold from, and bought back into, the inventory counted below
+		for exchange, quantity := range openLegQuantities(s.Legs, "spot") {
+			if s.Reverse {
+				continue
+			}
+			exposure.SpotQuantity += quantity
+			venue(exchange).SpotUSDT += math.Abs(quantity) * spotPrice
+		}
+		for exchange, quantity := range openLegQuantities(s.Legs, "perp") {
+			if !s.Reverse {
+				quantity = -quantity
+			}
+			exposure.PerpQuantity += quantity
+			venue(exchange).PerpUSDT += math.Abs(quantity) * perpPrice
+		}
+	}
+
+	for _, holding := range inventory.Snapshot() {
+		if common.IsZero(holding.Quantity) {
+			continue
+		}
+		exposure := asset(holding.Pair)
+		price := holding.Price
+		if common.IsZero(price) {
+			price = holding.AvgCost
+		}
+		if common.IsZero(exposure.Price) {
+			exposure.Price = price
+		}
+		exposure.SpotQuantity += holding.Quantity
+		venue(holding.Exchange).SpotUSDT += holding.Quantity * price
+	}
+
+	portfolio := redis.Portfolio{Assets: []redis.AssetExposure{}, Venues: []redis.VenueExposure{}, Timestamp: time.Now()}
+	for _, exposure := range assets {
+		exposure.NetDelta = exposure.SpotQuantity + exposure.PerpQuantity
+		exposure.NetDeltaUSDT = exposure.NetDelta * exposure.Price
+		exposure.GrossUSDT = (math.Abs(exposure.SpotQuantity) + math.Abs(exposure.PerpQuantity)) * exposure.Price
+		portfolio.NetDeltaUSDT += exposure.NetDeltaUSDT
+		portfolio.Assets = append(portfolio.Assets, *exposure)
+	}
+	for _, exposure := range venues {
+		exposure.GrossUSDT = exposure.SpotUSDT + exposure.PerpUSDT
+		portfolio.GrossUSDT += exposure.GrossUSDT
+		portfolio.Venues = append(portfolio.Venues, *exposure)
+	}
+
+	sort.Slice(portfolio.Assets, func(i, j int) bool {
+		return math.Abs(portfolio.Assets[i].NetDeltaUSDT) > math.Abs(portfolio.Assets[j].NetDeltaUSDT)
+	})
+	sort.Slice(portfolio.Venues, func(i, j int) bool {
+		return portfolio.Venues[i].GrossUSDT > portfolio.Venues[j].GrossUSDT
+	})
+	return portfolio
+}
+
+// openLegQuantities returns the base quantity a position still holds on each venue in a market
+// ("spot" or "perp"): what its open legs filled less what its close legs filled
+func openLegQuantities(legs []storage.LegExecution, market string) map[string]float64 {
+	quantities := make(map[string]float64)
+	for _, leg := range legs {
+		switch leg.Leg {
+		case "open_" + market:
+			quantities[leg.Exchange] += leg.Quantity
+		case "close_" + market:
+			quantities[leg.Exchange] -= leg.Quantity
+		}
+	}
+	for exchange, quantity := range quantities {
+		if common.IsZero(quantity) {
+			delete(quantities, exchange)
+		}
+	}
+	return quantities
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/storage"
+)
+
+// Position persistence
+//
+// Every change to activePositions (entry, fills, option hedge, close, release) rewrites POSITIONS_FILE
+// (default positions.json). On start, stored positions are resumed with their original entry context:
+// open ones go back to exit monitoring on their venue books with the safety timer counting from the
+// original entry, closing ones (and failed entries) resume the close retry until both venues are flat.
+
+// safetyHold is how long a position may stay open before it is force closed
+const safetyHold = 65 * time.Second
+
+var persistMu sync.Mutex
+
+// snapshot returns the position's persisted form
+func (p *ArbitragePosition) snapshot() storage.PositionSnapshot {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return storage.PositionSnapshot{
+		TradeID:              p.TradeID,
+		Strategy:             p.Strategy,
+		ConfigVersion:        p.ConfigVersion,
+		Experiment:           p.Experiment,
+		Variant:              p.Variant,
+		Pair:                 p.PairName,
+		ShortExchange:        string(p.ShortExchange),
+		LongExchange:         string(p.LongExchange),
+		EntryShortPrice:      p.EntryShortPrice,
+		EntryLongPrice:       p.EntryLongPrice,
+		EntrySpread:          p.EntrySpread,
+		AmountUSDT:           p.AmountUSDT,
+		EntryTime:            p.EntryTime,
+		DetectedAt:           p.DetectedAt,
+		FirstFillAt:          p.FirstFillAt,
+		Reverse:              p.Reverse,
+		Inverse:              p.Inverse,
+		SpotQuantity:         p.SpotQuantity,
+		SpotProceeds:         p.SpotProceeds,
+		Fees:                 p.Fees,
+		Rebates:              p.Rebates,
+		Slippage:             p.Slippage,
+		IsOpen:               p.IsOpen,
+		IsClosing:            p.IsClosing,
+		ExitReason:           p.ExitReason,
+		Legs:                 append([]storage.LegExecution(nil), p.Legs...),
+		SpotFill:             p.SpotFill,
+		PerpFill:             p.PerpFill,
+		SpotRoutes:           p.SpotRoutes,
+		PartialClosed:        p.PartialClosed,
+		PartialSpotProfit:    p.PartialSpotProfit,
+		PartialFuturesProfit: p.PartialFuturesProfit,
+		Option:               p.Option,
+		OptionExchange:       string(p.OptionExchange),
+		OptionFill:           p.OptionFill,
+		LivePnL:              p.LivePnL,
+	}
+}
+
+// persistPositions writes every tracked position to the snapshot file
+// Callers must not hold positionsMutex or a position's mu.
+func persistPositions() {
+	persistMu.Lock()
+	defer persistMu.Unlock()
+
+	if err := storage.SavePositions(positionSource{}.Positions()); err != nil {
+		log.Printf("[ERROR] Failed to persist positions: %v", err)
+	}
+}
+
+// positionSource serves the tracked positions to the operator API
+type positionSource struct{}
+
+// Positions returns every tracked position's snapshot
+func (positionSource) Positions() []storage.PositionSnapshot {
+	positionsMutex.RLock()
+	positions := make([]*ArbitragePosition, 0, len(activePositions))
+	for _, position := range activePositions {
+		positions = append(positions, position)
+	}
+	positionsMutex.RUnlock()
+
+	snapshots := make([]storage.PositionSnapshot, 0, len(positions))
+	for _, position := range positions {
+		snapshots = append(snapshots, position.snapshot())
+	}
+	return snapshots
+}
+
+// restorePosition rebuilds a position from its snapshot
+func restorePosition(s storage.PositionSnapshot) *ArbitragePosition {
+	return &ArbitragePosition{
+		TradeID:              s.TradeID,
+		Strategy:             s.Strategy,
+		ConfigVersion:        s.ConfigVersion,
+		Experiment:           s.Experiment,
+		Variant:              s.Variant,
+		PairName:             s.Pair,
+		ShortExchange:        common.ExchangeType(s.ShortExchange),
+		LongExchange:         common.ExchangeType(s.LongExchange),
+		EntryShortPrice:      s.EntryShortPrice,
+		EntryLongPrice:       s.EntryLongPrice,
+		EntrySpread:          s.EntrySpread,
+		CurrentSpread:        s.EntrySpread,
+		CurrentShort:         s.EntryShortPrice,
+		CurrentLong:          s.EntryLongPrice,
+		Fees:                 s.Fees,
+		Rebates:              s.Rebates,
+		Slippage:             s.Slippage,
+		AmountUSDT:           s.AmountUSDT,
+		EntryTime:            s.EntryTime,
+		LastLogTime:          time.Now(),
+		IsOpen:               s.IsOpen,
+		IsClosing:            s.IsClosing,
+		Reverse:              s.Reverse,
+		Inverse:              s.Inverse,
+		SpotQuantity:         s.SpotQuantity,
+		SpotProceeds:         s.SpotProceeds,
+		Option:               s.Option,
+		OptionExchange:       common.ExchangeType(s.OptionExchange),
+		OptionFill:           s.OptionFill,
+		DetectedAt:           s.DetectedAt,
+		FirstFillAt:          s.FirstFillAt,
+		Legs:                 s.Legs,
+		SpotFill:             s.SpotFill,
+		PerpFill:             s.PerpFill,
+		SpotRoutes:           s.SpotRoutes,
+		PartialClosed:        s.PartialClosed,
+		PartialSpotProfit:    s.PartialSpotProfit,
+		PartialFuturesProfit: s.PartialFuturesProfit,
+		ExitReason:           s.ExitReason,
+	}
+}
+
+// resumePositions restores the positions open or closing when the process last stopped
+func resumePositions() {
+	snapshots, err := storage.LoadPositions()
+	if err != nil {
+		log.Printf("[RESUME] ERROR: %v", err)
+		return
+	}
+
+	for _, s := range snapshots {
+		position := restorePosition(s)
+
+		positionsMutex.Lock()
+		activePositions[position.PairName] = position
+		positionsMutex.Unlock()
+
+		if position.IsOpen && !position.IsClosing {
+			log.Printf("[RESUME %s] Open since %s | Spot: %s | Perp: %s | Entry spread: %.2f%% - monitoring for exit",
+				position.PairName, position.EntryTime.Format(time.RFC3339), position.spotExchange(), position.perpExchange(), position.EntrySpread)
+			if globalBooks != nil {
+				globalBooks.WatchVenues(position.PairName, string(position.spotExchange()), string(position.perpExchange()))
+			}
+			startSafetyTimer(position)
+			continue
+		}
+
+		log.Printf("[RESUME %s] Was closing (%s) - retrying close until flat", position.PairName, position.ExitReason)
+		position.mu.Lock()
+		position.IsOpen = false
+		position.IsClosing = true
+		position.mu.Unlock()
+		go func() {
+			retryCloseLegs(position.tradeContext(), position)
+			closeUntilFlat(position.tradeContext(), position)
+		}()
+	}
+
+	if len(snapshots) > 0 {
+		log.Printf("[RESUME] Restored %d positions", len(snapshots))
+	}
+}
+
+// startSafetyTimer force closes the position once it has been open for safetyHold, counted from entry
+func startSafetyTimer(position *ArbitragePosition) {
+	go func() {
+		time.Sleep(time.Until(position.EntryTime.Add(safetyHold)))
+		position.mu.RLock()
+		stillOpen := position.IsOpen
+		position.mu.RUnlock()
+
+		if stillOpen {
+			log.Printf("[FORCE CLOSE %s] Safety timer triggered - position held too long", position.PairName)
+			closePosition(position, "Safety timer (65s)")
+		}
+	}()
+}
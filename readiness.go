@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"arbitrage.trade/capability"
+	"arbitrage.trade/clients"
+	"arbitrage.trade/clients/common"
+)
+
+// Startup readiness report
+//
+// Once exchange capabilities are loaded, every traded pair is checked for an arbitrage threshold,
+// quantity/price precision data and at least two venues listing both its spot and perp market, and
+// every supported exchange for its API credentials (skipped in paper mode). The result is logged as
+// one table before trading begins. Problems are only reported unless READINESS_STRICT=true, which
+// aborts startup instead.
+
+// readinessRow is one checked pair or exchange and what it is missing
+type readinessRow struct {
+	name     string
+	columns  []string
+	problems []string
+}
+
+// checkReadiness logs the readiness report and returns the number of problems found
+func checkReadiness(pairs []string, venues []string) int {
+	sort.Strings(venues)
+
+	var pairRows []readinessRow
+	for _, pair := range pairs {
+		row := readinessRow{name: pair}
+
+		threshold, ok := arbitrageThresholds[pair]
+		if ok {
+			row.columns = append(row.columns, fmt.Sprintf("%.2f%%", threshold))
+		} else {
+			row.columns = append(row.columns, "-")
+			row.problems = append(row.problems, "no threshold")
+		}
+
+		if prec, ok := common.PairPrecisions[pair]; ok {
+			row.columns = append(row.columns, fmt.Sprintf("qty %d / price %d", prec.QuantityPrecision, prec.PricePrecision))
+		} else {
+			row.columns = append(row.columns, "-")
+			row.problems = append(row.problems, "no precision data")
+		}
+
+		var both []string
+		for _, venue := range venues {
+			if capability.CanTrade(venue, pair, true) && capability.CanTrade(venue, pair, false) {
+				both = append(both, venue)
+			}
+		}
+		if len(both) == 0 {
+			row.columns = append(row.columns, "-")
+		} else {
+			row.columns = append(row.columns, strings.Join(both, ","))
+		}
+		if len(both) < 2 {
+			row.problems = append(row.problems, fmt.Sprintf("%d venue(s) with spot and perp, need 2", len(both)))
+		}
+		pairRows = append(pairRows, row)
+	}
+
+	var exchangeRows []readinessRow
+	for _, venue := range venues {
+		row := readinessRow{name: venue}
+		switch missing := clients.MissingCredentials(common.ExchangeType(venue)); {
+		case clients.IsPaperTrading():
+			row.columns = append(row.columns, "not needed (paper)")
+		case len(missing) > 0:
+			row.columns = append(row.columns, "missing")
+			row.problems = append(row.problems, "unset "+strings.Join(missing, ", "))
+		default:
+			row.columns = append(row.columns, "set")
+		}
+		exchangeRows = append(exchangeRows, row)
+	}
+
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PAIR\tTHRESHOLD\tPRECISION\tSPOT+PERP VENUES\tSTATUS")
+	problems := writeReadinessRows(w, pairRows)
+	fmt.Fprintln(w, "\t\t\t\t")
+	fmt.Fprintln(w, "EXCHANGE\tCREDENTIALS\t\t\tSTATUS")
+	problems += writeReadinessRows(w, exchangeRows)
+	w.Flush()
+
+	status := "READY"
+	if problems > 0 {
+		status = fmt.Sprintf("NOT READY - %d problem(s)", problems)
+	}
+	log.Printf("[READINESS] Startup self-check: %s\n%s", status, b.String())
+	return problems
+}
+
+// writeReadinessRows writes one tab-separated line per row and returns how many problems the rows have
+func writeReadinessRows(w *tabwriter.Writer, rows []readinessRow) int {
+	problems := 0
+	for _, row := range rows {
+		columns := row.columns
+		// Exchange rows have one column, padded to line up with the pair table
+		for len(columns) < 3 {
+			columns = append(columns, "")
+		}
+		status := "OK"
+		if len(row.problems) > 0 {
+			status = "FAIL: " + strings.Join(row.problems, "; ")
+			problems += len(row.problems)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", row.name, strings.Join(columns, "\t"), status)
+	}
+	return problems
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"time"
 
+	"arbitrage.trade/webhook"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -23,12 +24,19 @@ func InitRedis() error {
 	defer cancel()
 
 	if err := client.Ping(ctx).Err(); err != nil {
-		fmt.Printf("⚠️  Failed to connect to Redis: %v (trade notifications disabled)\n", err)
-		client = nil
+		if spoolLimit() <= 0 {
+			fmt.Printf("⚠️  Failed to connect to Redis: %v (trade notifications disabled)\n", err)
+			client = nil
+			return err
+		}
+		// Keep the client so events spooled meanwhile are replayed once Redis comes up
+		fmt.Printf("⚠️  Failed to connect to Redis: %v (trade events spooled until it is reachable)\n", err)
+		startSpoolReplay()
 		return err
 	}
 
 	fmt.Println("✅ Connected to Redis - trade executions will be published")
+	startSpoolReplay()
 	return nil
 }
 
@@ -42,20 +50,62 @@ func CloseRedis() {
 	}
 }
 
+// Payload schema versions, bumped only on incompatible changes (see schema/README.md)
+const (
+	TradeExecutionSchemaVersion = 1
+	TradeSummarySchemaVersion   = 1
+	DailyReportSchemaVersion    = 1
+	HeatmapSchemaVersion        = 1
+	PositionPnLSchemaVersion    = 1
+	PortfolioSchemaVersion      = 1
+)
+
+// Event types carried in every payload's "type" field
+const (
+	EventTradeExecution = "trade_execution"
+	EventTradeSummary   = "trade_summary"
+	EventDailyReport    = "daily_report"
+	EventHeatmap        = "opportunity_heatmap"
+	EventPositionPnL    = "position_pnl"
+	EventPortfolio      = "portfolio_exposure"
+)
+
 // TradeExecution represents a single trade action
 type TradeExecution struct {
-	Exchange  string    `json:"exchange"`
-	Pair      string    `json:"pair"`
-	Side      string    `json:"side"`       // "spot_long", "futures_short", "close_spot_long", "close_futures_short"
-	Action    string    `json:"action"`     // "open" or "close"
-	Amount    float64   `json:"amount"`     // USDT amount
-	Price     float64   `json:"price"`      // Entry/Exit price
-	SpreadPct float64   `json:"spread_pct"` // Spread at execution
-	Timestamp time.Time `json:"timestamp"`
+	SchemaVersion int       `json:"schema_version"`     // Set by PublishTradeExecution
+	Type          string    `json:"type"`               // Set by PublishTradeExecution
+	TradeID       string    `json:"trade_id,omitempty"` // Trade the order belongs to, "" for rebalances
+	Strategy      string    `json:"strategy,omitempty"` // Strategy the trade runs under
+	ConfigVersion string    `json:"config_version,omitempty"`
+	Experiment    string    `json:"experiment,omitempty"`
+	Variant       string    `json:"variant,omitempty"`
+	Exchange      string    `json:"exchange"`
+	Pair          string    `json:"pair"`
+	Side          string    `json:"side"`         // "spot_long", "futures_short", "margin_short", "futures_long", "inverse_short", "spot_inventory", "option_call", "option_put"
+	Action        string    `json:"action"`       // "open", "close" or "rebalance"
+	Amount        float64   `json:"amount"`       // USDT amount requested
+	Price         float64   `json:"price"`        // Average fill price
+	Quantity      float64   `json:"quantity"`     // Filled base quantity
+	Fee           float64   `json:"fee"`          // Fee in USDT equivalent
+	OrderID       string    `json:"order_id"`     // Exchange order ID(s), comma separated when maker + taker
+	Filled        bool      `json:"filled"`       // Exchange reported the order fully filled
+	Profit        float64   `json:"profit"`       // Realized USDT profit of the leg (close only)
+	SpreadPct     float64   `json:"spread_pct"`   // Spread at execution
+	SubmittedAt   time.Time `json:"submitted_at"` // Client time the order was sent
+	CompletedAt   time.Time `json:"completed_at"` // Client time the fill result was received
+	LatencyMs     float64   `json:"latency_ms"`   // Client-measured submit to fill round trip
+	Timestamp     time.Time `json:"timestamp"`
 }
 
 // TradeSummary represents the final P&L after all 4 trades complete
 type TradeSummary struct {
+	SchemaVersion   int       `json:"schema_version"` // Set by PublishTradeSummary
+	Type            string    `json:"type"`           // Set by PublishTradeSummary
+	TradeID         string    `json:"trade_id,omitempty"`
+	Strategy        string    `json:"strategy,omitempty"`
+	ConfigVersion   string    `json:"config_version,omitempty"`
+	Experiment      string    `json:"experiment,omitempty"`
+	Variant         string    `json:"variant,omitempty"`
 	Pair            string    `json:"pair"`
 	SpotExchange    string    `json:"spot_exchange"`
 	FuturesExchange string    `json:"futures_exchange"`
@@ -68,17 +118,23 @@ type TradeSummary struct {
 	Duration        float64   `json:"duration_seconds"`
 	OpenTime        time.Time `json:"open_time"`
 	CloseTime       time.Time `json:"close_time"`
+
+	// Where the edge went: the theoretical entry spread is what the analyzer saw, the realized one what
+	// the open fills achieved; slippage, fees and funding are USDT (slippage and fees as costs, funding
+	// positive when received)
+	TheoreticalEntrySpread float64 `json:"theoretical_entry_spread_pct"`
+	RealizedEntrySpread    float64 `json:"realized_entry_spread_pct"`
+	EntrySlippage          float64 `json:"entry_slippage"`
+	ExitSlippage           float64 `json:"exit_slippage"`
+	TotalFees              float64 `json:"total_fees"`
+	TotalRebates           float64 `json:"total_rebates,omitempty"`
+	Funding                float64 `json:"funding"`
 }
 
 // PublishTradeExecution publishes a single trade execution to Redis
 func PublishTradeExecution(trade TradeExecution) {
-	if client == nil {
-		fmt.Println("⚠️  Redis client not initialized - skipping trade execution publish")
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-	defer cancel()
+	trade.SchemaVersion = TradeExecutionSchemaVersion
+	trade.Type = EventTradeExecution
 
 	jsonData, err := json.Marshal(trade)
 	if err != nil {
@@ -86,9 +142,16 @@ func PublishTradeExecution(trade TradeExecution) {
 		return
 	}
 
+	// Webhooks are delivered independently of Redis availability
+	webhook.Send(EventTradeExecution, jsonData)
+
+	if client == nil {
+		fmt.Println("⚠️  Redis client not initialized - skipping trade execution publish")
+		return
+	}
+
 	// Publish to trade-execution topic
-	if err := client.Publish(ctx, "arbitrage-trade-execution", jsonData).Err(); err != nil {
-		fmt.Printf("❌ Failed to publish trade execution to Redis: %v\n", err)
+	if !deliver("arbitrage-trade-execution", "trade execution", jsonData) {
 		return
 	}
 
@@ -98,26 +161,255 @@ func PublishTradeExecution(trade TradeExecution) {
 
 // PublishTradeSummary publishes the final P&L summary to Redis
 func PublishTradeSummary(summary TradeSummary) {
+	summary.SchemaVersion = TradeSummarySchemaVersion
+	summary.Type = EventTradeSummary
+
+	jsonData, err := json.Marshal(summary)
+	if err != nil {
+		fmt.Printf("❌ Failed to marshal trade summary: %v\n", err)
+		return
+	}
+
+	// Webhooks are delivered independently of Redis availability
+	webhook.Send(EventTradeSummary, jsonData)
+
 	if client == nil {
 		fmt.Println("⚠️  Redis client not initialized - skipping trade summary publish")
 		return
 	}
 
+	// Publish to trade-summary topic
+	if !deliver("arbitrage-trade-summary", "trade summary", jsonData) {
+		return
+	}
+
+	fmt.Printf("📤 Published trade summary to Redis: %s - %.4f USDT profit\n",
+		summary.Pair, summary.TotalProfit)
+}
+
+// PairReport is one pair's line in the daily report
+type PairReport struct {
+	Pair    string  `json:"pair"`
+	Trades  int     `json:"trades"`
+	Wins    int     `json:"wins"`
+	Profit  float64 `json:"profit"`
+	Fees    float64 `json:"fees"`
+	Rebates float64 `json:"rebates,omitempty"`
+	Funding float64 `json:"funding"`
+	Volume  float64 `json:"volume"`
+}
+
+// StrategyReport is one strategy tag's line in the daily report
+type StrategyReport struct {
+	Strategy      string  `json:"strategy"` // "" for trades recorded before tagging
+	ConfigVersion string  `json:"config_version,omitempty"`
+	Trades        int     `json:"trades"`
+	Wins          int     `json:"wins"`
+	Profit        float64 `json:"profit"`
+	Fees          float64 `json:"fees"`
+	Funding       float64 `json:"funding"`
+	Volume        float64 `json:"volume"`
+}
+
+// ConvertedAmounts are a report's USDT totals in the operator's reporting currency (REPORT_CURRENCY)
+type ConvertedAmounts struct {
+	Currency string  `json:"currency"`
+	Rate     float64 `json:"rate"` // Units of the currency per USDT
+	Profit   float64 `json:"profit"`
+	Fees     float64 `json:"fees"`
+	Funding  float64 `json:"funding"`
+	Volume   float64 `json:"volume"`
+}
+
+// DailyReport aggregates the trades closed in one reporting day
+type DailyReport struct {
+	SchemaVersion int               `json:"schema_version"` // Set by PublishDailyReport
+	Type          string            `json:"type"`           // Set by PublishDailyReport
+	From          time.Time         `json:"from"`
+	To            time.Time         `json:"to"`
+	Trades        int               `json:"trades"`
+	Wins          int               `json:"wins"`
+	WinRate       float64           `json:"win_rate_pct"`
+	Profit        float64           `json:"profit"` // Net of fees and funding
+	Fees          float64           `json:"fees"`
+	Rebates       float64           `json:"rebates,omitempty"` // Maker rebates, not netted from Fees
+	Funding       float64           `json:"funding"`
+	Volume        float64           `json:"volume"`
+	Pairs         []PairReport      `json:"pairs"`               // Sorted by profit, best first
+	Strategies    []StrategyReport  `json:"strategies"`          // Sorted by profit, best first
+	Converted     *ConvertedAmounts `json:"converted,omitempty"` // Set when REPORT_CURRENCY is not USDT
+}
+
+// PublishDailyReport publishes the daily P&L report to Redis
+func PublishDailyReport(report DailyReport) {
+	report.SchemaVersion = DailyReportSchemaVersion
+	report.Type = EventDailyReport
+
+	jsonData, err := json.Marshal(report)
+	if err != nil {
+		fmt.Printf("❌ Failed to marshal daily report: %v\n", err)
+		return
+	}
+
+	// Webhooks are delivered independently of Redis availability
+	webhook.Send(EventDailyReport, jsonData)
+
+	if client == nil {
+		fmt.Println("⚠️  Redis client not initialized - skipping daily report publish")
+		return
+	}
+
+	if !deliver("arbitrage-daily-report", "daily report", jsonData) {
+		return
+	}
+
+	fmt.Printf("📤 Published daily report to Redis: %d trades, %.4f USDT profit\n", report.Trades, report.Profit)
+}
+
+// HeatmapCell is one pair and venue combination's observed opportunities within a window
+type HeatmapCell struct {
+	Pair         string    `json:"pair"`
+	SpotExchange string    `json:"spot_exchange"`
+	PerpExchange string    `json:"perp_exchange"`
+	Direction    string    `json:"direction"`    // "forward" (long spot, short perp) or "reverse"
+	Observations int       `json:"observations"` // Analyzer updates that showed the opportunity
+	PerHour      float64   `json:"per_hour"`
+	AvgSpreadPct float64   `json:"avg_spread_pct"`
+	MaxSpreadPct float64   `json:"max_spread_pct"`
+	LastSeen     time.Time `json:"last_seen"`
+}
+
+// OpportunityHeatmap is where spreads were observed over the last hour and day
+type OpportunityHeatmap struct {
+	SchemaVersion int           `json:"schema_version"` // Set by PublishHeatmap
+	Type          string        `json:"type"`           // Set by PublishHeatmap
+	Hour          []HeatmapCell `json:"hour"`           // Sorted by observations, most first
+	Day           []HeatmapCell `json:"day"`
+	Timestamp     time.Time     `json:"timestamp"`
+}
+
+// PublishHeatmap publishes the opportunity heatmap to Redis
+func PublishHeatmap(heatmap OpportunityHeatmap) {
+	heatmap.SchemaVersion = HeatmapSchemaVersion
+	heatmap.Type = EventHeatmap
+
+	jsonData, err := json.Marshal(heatmap)
+	if err != nil {
+		fmt.Printf("❌ Failed to marshal opportunity heatmap: %v\n", err)
+		return
+	}
+
+	if client == nil {
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
-	jsonData, err := json.Marshal(summary)
+	if err := client.Publish(ctx, "arbitrage-opportunity-heatmap", jsonData).Err(); err != nil {
+		fmt.Printf("❌ Failed to publish opportunity heatmap to Redis: %v\n", err)
+	}
+}
+
+// PositionPnL is an open position's live unrealized PnL in USDT
+type PositionPnL struct {
+	SchemaVersion int       `json:"schema_version"` // Set by PublishPositionPnL
+	Type          string    `json:"type"`           // Set by PublishPositionPnL
+	TradeID       string    `json:"trade_id,omitempty"`
+	Strategy      string    `json:"strategy,omitempty"`
+	ConfigVersion string    `json:"config_version,omitempty"`
+	Experiment    string    `json:"experiment,omitempty"`
+	Variant       string    `json:"variant,omitempty"`
+	Pair          string    `json:"pair"`
+	SpotExchange  string    `json:"spot_exchange"`
+	PerpExchange  string    `json:"perp_exchange"`
+	Reverse       bool      `json:"reverse"`
+	SpotPnL       float64   `json:"spot_pnl"` // Spot leg marked to its book
+	PerpPnL       float64   `json:"perp_pnl"`
+	PerpSource    string    `json:"perp_source"` // "venue" or "book"
+	Realized      float64   `json:"realized"`    // Partial closes, net of their fees
+	Fees          float64   `json:"fees"`        // Paid opening the legs, net of maker rebates
+	Total         float64   `json:"total"`
+	EntrySpread   float64   `json:"entry_spread_pct"`
+	CurrentSpread float64   `json:"current_spread_pct"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+// PublishPositionPnL publishes an open position's live PnL to Redis
+// Not spooled: a missed refresh is superseded by the next
+func PublishPositionPnL(pnl PositionPnL) {
+	pnl.SchemaVersion = PositionPnLSchemaVersion
+	pnl.Type = EventPositionPnL
+
+	jsonData, err := json.Marshal(pnl)
 	if err != nil {
-		fmt.Printf("❌ Failed to marshal trade summary: %v\n", err)
+		fmt.Printf("❌ Failed to marshal position PnL: %v\n", err)
 		return
 	}
 
-	// Publish to trade-summary topic
-	if err := client.Publish(ctx, "arbitrage-trade-summary", jsonData).Err(); err != nil {
-		fmt.Printf("❌ Failed to publish trade summary to Redis: %v\n", err)
+	if client == nil {
 		return
 	}
 
-	fmt.Printf("📤 Published trade summary to Redis: %s - %.4f USDT profit\n",
-		summary.Pair, summary.TotalProfit)
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	if err := client.Publish(ctx, "arbitrage-position-pnl", jsonData).Err(); err != nil {
+		fmt.Printf("❌ Failed to publish position PnL to Redis: %v\n", err)
+	}
+}
+
+// AssetExposure is one base asset's net delta across every venue
+type AssetExposure struct {
+	Asset        string  `json:"asset"`
+	SpotQuantity float64 `json:"spot_quantity"` // Held on spot venues: forward spot legs plus inventory
+	PerpQuantity float64 `json:"perp_quantity"` // Signed, shorts negative
+	NetDelta     float64 `json:"net_delta"`     // Spot plus perp, base units
+	Price        float64 `json:"price"`
+	NetDeltaUSDT float64 `json:"net_delta_usdt"`
+	GrossUSDT    float64 `json:"gross_usdt"` // Spot plus absolute perp notional
+	Positions    int     `json:"positions"`  // Open or closing positions in the asset
+}
+
+// VenueExposure is one venue's gross notional across its markets
+type VenueExposure struct {
+	Exchange  string  `json:"exchange"`
+	SpotUSDT  float64 `json:"spot_usdt"`
+	PerpUSDT  float64 `json:"perp_usdt"` // Absolute, longs and shorts alike
+	GrossUSDT float64 `json:"gross_usdt"`
+}
+
+// Portfolio is the aggregate exposure of every open position and inventory holding
+type Portfolio struct {
+	SchemaVersion int             `json:"schema_version"` // Set by PublishPortfolio
+	Type          string          `json:"type"`           // Set by PublishPortfolio
+	Assets        []AssetExposure `json:"assets"`         // Largest absolute net delta first
+	Venues        []VenueExposure `json:"venues"`         // Largest gross notional first
+	NetDeltaUSDT  float64         `json:"net_delta_usdt"` // Summed over assets, signed
+	GrossUSDT     float64         `json:"gross_usdt"`
+	Timestamp     time.Time       `json:"timestamp"`
+}
+
+// PublishPortfolio publishes the portfolio exposure to Redis
+// Not spooled: a missed refresh is superseded by the next
+func PublishPortfolio(portfolio Portfolio) {
+	portfolio.SchemaVersion = PortfolioSchemaVersion
+	portfolio.Type = EventPortfolio
+
+	jsonData, err := json.Marshal(portfolio)
+	if err != nil {
+		fmt.Printf("❌ Failed to marshal portfolio exposure: %v\n", err)
+		return
+	}
+
+	if client == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	if err := client.Publish(ctx, "arbitrage-portfolio-exposure", jsonData).Err(); err != nil {
+		fmt.Printf("❌ Failed to publish portfolio exposure to Redis: %v\n", err)
+	}
 }
@@ -0,0 +1,16 @@
+package redis
+
+import (
+	"embed"
+	"fmt"
+)
+
+// Schemas holds the published JSON schemas for event payloads (schema/<type>.v<version>.json)
+//
+//go:embed schema/*.json
+var Schemas embed.FS
+
+// Schema returns the JSON schema for an event type and version
+func Schema(eventType string, version int) ([]byte, error) {
+	return Schemas.ReadFile(fmt.Sprintf("schema/%s.v%d.json", eventType, version))
+}
@@ -0,0 +1,259 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"arbitrage.trade/config"
+)
+
+// Event spool
+//
+// Trade executions, trade summaries and daily reports that can't be published while Redis is down
+// are appended to REDIS_SPOOL_PATH (default redis_spool.jsonl) instead of being dropped, keeping at
+// most REDIS_SPOOL_MAX_EVENTS (default 10000, 0 disables spooling) with the oldest dropped beyond.
+// Every REDIS_SPOOL_REPLAY_INTERVAL (default 10s) the spool is replayed in order once Redis answers a
+// ping. Payloads are republished unchanged, so they keep their original timestamps, and events
+// published while older ones are spooled queue behind them.
+
+type spooledEvent struct {
+	Channel   string          `json:"channel"`
+	Payload   json.RawMessage `json:"payload"`
+	SpooledAt time.Time       `json:"spooled_at"`
+}
+
+// SpoolStats counts publish failures and what became of the events spooled because of them
+type SpoolStats struct {
+	Pending         int       `json:"pending"`
+	OldestSpooledAt time.Time `json:"oldest_spooled_at,omitempty"`
+	PublishFailures int64     `json:"publish_failures"`
+	Spooled         int64     `json:"spooled"`
+	Replayed        int64     `json:"replayed"`
+	Dropped         int64     `json:"dropped"` // Spool full, oldest discarded
+	LastError       string    `json:"last_error,omitempty"`
+	LastErrorAt     time.Time `json:"last_error_at,omitempty"`
+}
+
+var (
+	spoolMu     sync.Mutex
+	spoolLoaded bool
+	spoolEvents []spooledEvent
+	spoolStats  SpoolStats
+	replayOnce  sync.Once
+)
+
+func spoolPath() string {
+	return config.GetString("REDIS_SPOOL_PATH", "redis_spool.jsonl")
+}
+
+func spoolLimit() int {
+	return config.GetInt("REDIS_SPOOL_MAX_EVENTS", 10000)
+}
+
+// loadSpoolLocked reads events left spooled by a previous run, once
+func loadSpoolLocked() {
+	if spoolLoaded {
+		return
+	}
+	spoolLoaded = true
+
+	f, err := os.Open(spoolPath())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			fmt.Printf("❌ Failed to open Redis spool: %v\n", err)
+		}
+		return
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var event spooledEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			fmt.Printf("❌ Skipping unreadable Redis spool entry: %v\n", err)
+			continue
+		}
+		spoolEvents = append(spoolEvents, event)
+	}
+	if len(spoolEvents) > 0 {
+		fmt.Printf("📥 %d trade events spooled by a previous run, replaying once Redis is reachable\n", len(spoolEvents))
+	}
+}
+
+// rewriteSpoolLocked replaces the spool file with the pending events
+func rewriteSpoolLocked() error {
+	path := spoolPath()
+	if len(spoolEvents) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	for _, event := range spoolEvents {
+		line, err := json.Marshal(event)
+		if err != nil {
+			f.Close()
+			return err
+		}
+		w.Write(line)
+		w.WriteByte('\n')
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// spoolLocked queues an event behind those already spooled, dropping the oldest beyond the limit
+func spoolLocked(channel string, payload []byte) {
+	event := spooledEvent{Channel: channel, Payload: payload, SpooledAt: time.Now()}
+	spoolEvents = append(spoolEvents, event)
+	spoolStats.Spooled++
+
+	if over := len(spoolEvents) - spoolLimit(); over > 0 {
+		spoolEvents = spoolEvents[over:]
+		spoolStats.Dropped += int64(over)
+		fmt.Printf("⚠️  Redis spool full - dropped %d oldest events\n", over)
+		if err := rewriteSpoolLocked(); err != nil {
+			fmt.Printf("❌ Failed to rewrite Redis spool: %v\n", err)
+		}
+		return
+	}
+
+	line, err := json.Marshal(event)
+	if err == nil {
+		var f *os.File
+		f, err = os.OpenFile(spoolPath(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err == nil {
+			_, err = f.Write(append(line, '\n'))
+			f.Close()
+		}
+	}
+	if err != nil {
+		fmt.Printf("❌ Failed to write Redis spool, event kept in memory only: %v\n", err)
+	}
+}
+
+func recordFailureLocked(err error) {
+	spoolStats.PublishFailures++
+	spoolStats.LastError = err.Error()
+	spoolStats.LastErrorAt = time.Now()
+}
+
+// deliver publishes a payload, spooling it when Redis is unreachable or older events are still spooled
+// Returns whether it was published now
+func deliver(channel, label string, payload []byte) bool {
+	spoolMu.Lock()
+	defer spoolMu.Unlock()
+
+	enabled := spoolLimit() > 0
+	if enabled {
+		loadSpoolLocked()
+	}
+
+	if len(spoolEvents) == 0 || !enabled {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		err := client.Publish(ctx, channel, payload).Err()
+		cancel()
+		if err == nil {
+			return true
+		}
+		recordFailureLocked(err)
+		if !enabled {
+			fmt.Printf("❌ Failed to publish %s to Redis: %v\n", label, err)
+			return false
+		}
+		fmt.Printf("❌ Failed to publish %s to Redis, spooling: %v\n", label, err)
+	}
+
+	spoolLocked(channel, payload)
+	fmt.Printf("📥 Spooled %s (%d pending)\n", label, len(spoolEvents))
+	return false
+}
+
+// replaySpool republishes spooled events in order, stopping at the first failure
+func replaySpool() {
+	spoolMu.Lock()
+	defer spoolMu.Unlock()
+
+	loadSpoolLocked()
+	if len(spoolEvents) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	err := client.Ping(ctx).Err()
+	cancel()
+	if err != nil {
+		return
+	}
+
+	sent := 0
+	for _, event := range spoolEvents {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		err := client.Publish(ctx, event.Channel, []byte(event.Payload)).Err()
+		cancel()
+		if err != nil {
+			recordFailureLocked(err)
+			break
+		}
+		sent++
+	}
+	if sent == 0 {
+		return
+	}
+
+	spoolEvents = spoolEvents[sent:]
+	spoolStats.Replayed += int64(sent)
+	if err := rewriteSpoolLocked(); err != nil {
+		fmt.Printf("❌ Failed to rewrite Redis spool: %v\n", err)
+	}
+	fmt.Printf("📤 Replayed %d spooled trade events to Redis (%d pending)\n", sent, len(spoolEvents))
+}
+
+// startSpoolReplay replays the spool in the background for the life of the process
+func startSpoolReplay() {
+	if spoolLimit() <= 0 {
+		return
+	}
+	replayOnce.Do(func() {
+		go func() {
+			replaySpool()
+			ticker := time.NewTicker(config.GetDuration("REDIS_SPOOL_REPLAY_INTERVAL", 10*time.Second))
+			defer ticker.Stop()
+			for range ticker.C {
+				replaySpool()
+			}
+		}()
+	})
+}
+
+// CurrentSpoolStats returns the publish failure and spool counters
+func CurrentSpoolStats() SpoolStats {
+	spoolMu.Lock()
+	defer spoolMu.Unlock()
+
+	stats := spoolStats
+	stats.Pending = len(spoolEvents)
+	if len(spoolEvents) > 0 {
+		stats.OldestSpooledAt = spoolEvents[0].SpooledAt
+	}
+	return stats
+}
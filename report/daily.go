@@ -0,0 +1,222 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/config"
+	"arbitrage.trade/fx"
+	"arbitrage.trade/notify"
+	"arbitrage.trade/redis"
+	"arbitrage.trade/scheduler"
+	"arbitrage.trade/storage"
+)
+
+// Daily P&L report
+//
+// Runs once a day at DAILY_REPORT_TIME (HH:MM, default 00:00) in DAILY_REPORT_TZ
+// (IANA name, default UTC) over the trades closed in the preceding 24 hours, broken
+// down per pair and per strategy tag (see strategy.go), and posts it to the summary
+// notifiers and the arbitrage-daily-report Redis channel, with its totals also in
+// REPORT_CURRENCY when that is not USDT (see fx/fx.go).
+// Set DAILY_REPORT_ENABLED=false to turn it off (or override the schedule with SCHEDULE_DAILY_REPORT).
+
+// BuildDailyReport aggregates the trades closed in [from, to)
+func BuildDailyReport(trades []storage.TradeRecord, from, to time.Time) redis.DailyReport {
+	report := redis.DailyReport{From: from, To: to, Pairs: []redis.PairReport{}, Strategies: []redis.StrategyReport{}}
+	pairs := make(map[string]*redis.PairReport)
+	strategies := make(map[[2]string]*redis.StrategyReport)
+
+	for _, t := range trades {
+		pair, ok := pairs[t.Pair]
+		if !ok {
+			pair = &redis.PairReport{Pair: t.Pair}
+			pairs[t.Pair] = pair
+		}
+
+		key := [2]string{t.Strategy, t.ConfigVersion}
+		strategy, ok := strategies[key]
+		if !ok {
+			strategy = &redis.StrategyReport{Strategy: t.Strategy, ConfigVersion: t.ConfigVersion}
+			strategies[key] = strategy
+		}
+
+		pair.Trades++
+		pair.Profit += t.TotalProfit
+		pair.Fees += t.Fees
+		pair.Rebates += t.Rebates
+		pair.Funding += t.Funding
+		pair.Volume += t.Amount
+
+		strategy.Trades++
+		strategy.Profit += t.TotalProfit
+		strategy.Fees += t.Fees
+		strategy.Funding += t.Funding
+		strategy.Volume += t.Amount
+
+		report.Trades++
+		report.Profit += t.TotalProfit
+		report.Fees += t.Fees
+		report.Rebates += t.Rebates
+		report.Funding += t.Funding
+		report.Volume += t.Amount
+
+		if t.TotalProfit > 0 {
+			pair.Wins++
+			strategy.Wins++
+			report.Wins++
+		}
+	}
+
+	if report.Trades > 0 {
+		report.WinRate = float64(report.Wins) / float64(report.Trades) * 100
+	}
+
+	for _, pair := range pairs {
+		report.Pairs = append(report.Pairs, *pair)
+	}
+	sort.Slice(report.Pairs, func(i, j int) bool {
+		return report.Pairs[i].Profit > report.Pairs[j].Profit
+	})
+
+	for _, strategy := range strategies {
+		report.Strategies = append(report.Strategies, *strategy)
+	}
+	sort.Slice(report.Strategies, func(i, j int) bool {
+		return report.Strategies[i].Profit > report.Strategies[j].Profit
+	})
+
+	return report
+}
+
+// FormatDailyReport renders a report as plain text for notifiers
+func FormatDailyReport(report redis.DailyReport) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Period: %s -> %s\n", report.From.Format("2006-01-02 15:04"), report.To.Format("2006-01-02 15:04 MST"))
+	fmt.Fprintf(&b, "Trades: %d | Wins: %d | Win rate: %.1f%%\n", report.Trades, report.Wins, report.WinRate)
+	fmt.Fprintf(&b, "Net P&L: %+.4f USDT | Volume: %.2f USDT\n", report.Profit, report.Volume)
+	fmt.Fprintf(&b, "Fees: %.4f USDT | Funding: %+.4f USDT\n", report.Fees, report.Funding)
+	if report.Rebates > 0 {
+		fmt.Fprintf(&b, "Maker rebates: %.4f USDT\n", report.Rebates)
+	}
+	if c := report.Converted; c != nil {
+		fmt.Fprintf(&b, "In %s at %.4f/USDT: Net P&L %+.2f | Fees %.2f | Funding %+.2f | Volume %.2f\n",
+			c.Currency, c.Rate, c.Profit, c.Fees, c.Funding, c.Volume)
+	}
+
+	if len(report.Pairs) > 0 {
+		b.WriteString("\nPair        Trades  Wins       P&L      Fees\n")
+		for _, p := range report.Pairs {
+			fmt.Fprintf(&b, "%-11s %6d %5d %+9.4f %9.4f\n", p.Pair, p.Trades, p.Wins, p.Profit, p.Fees)
+		}
+	}
+
+	// A single strategy is the totals again
+	if len(report.Strategies) > 1 {
+		b.WriteString("\nStrategy              Trades  Wins       P&L      Fees\n")
+		for _, s := range report.Strategies {
+			name := common.StrategyTag{Strategy: s.Strategy, ConfigVersion: s.ConfigVersion}.String()
+			if name == "" {
+				name = "untagged"
+			}
+			fmt.Fprintf(&b, "%-21s %6d %5d %+9.4f %9.4f\n", name, s.Trades, s.Wins, s.Profit, s.Fees)
+		}
+	}
+
+	return b.String()
+}
+
+// RunDailyReport builds, posts and publishes the report for the 24 hours before `to`
+func RunDailyReport(to time.Time) error {
+	from := to.Add(-24 * time.Hour)
+
+	trades, err := storage.LoadTrades(from, to)
+	if err != nil {
+		return fmt.Errorf("failed to load trades: %w", err)
+	}
+
+	report := BuildDailyReport(trades, from, to)
+	title := fmt.Sprintf("Daily report %s: %+.4f USDT", from.Format("2006-01-02"), report.Profit)
+	if fx.Enabled() {
+		if rate, err := fx.Current(context.Background()); err != nil {
+			log.Printf("[REPORT] RunDailyReport - ERROR: Reporting in USDT only: %v", err)
+		} else {
+			report.Converted = convertReport(report, rate)
+			title += fmt.Sprintf(" (%+.2f %s)", report.Converted.Profit, rate.Currency)
+		}
+	}
+
+	notify.Send(notify.Message{
+		Event: notify.EventSummary,
+		Title: title,
+		Body:  FormatDailyReport(report),
+	})
+	redis.PublishDailyReport(report)
+
+	return nil
+}
+
+// convertReport returns the report's totals in the reporting currency
+func convertReport(report redis.DailyReport, rate fx.Rate) *redis.ConvertedAmounts {
+	return &redis.ConvertedAmounts{
+		Currency: rate.Currency,
+		Rate:     rate.Rate,
+		Profit:   rate.Convert(report.Profit),
+		Fees:     rate.Convert(report.Fees),
+		Funding:  rate.Convert(report.Funding),
+		Volume:   rate.Convert(report.Volume),
+	}
+}
+
+// StartDailyReport schedules the daily report in the background
+func StartDailyReport() {
+	if !config.GetBool("DAILY_REPORT_ENABLED", true) {
+		return
+	}
+
+	tz := config.GetString("DAILY_REPORT_TZ", "UTC")
+	if _, err := time.LoadLocation(tz); err != nil {
+		log.Printf("[REPORT] StartDailyReport - ERROR: Invalid DAILY_REPORT_TZ, using UTC: %v", err)
+		tz = "UTC"
+	}
+
+	hour, minute, err := parseClock(config.GetString("DAILY_REPORT_TIME", "00:00"))
+	if err != nil {
+		log.Printf("[REPORT] StartDailyReport - ERROR: Invalid DAILY_REPORT_TIME, using 00:00: %v", err)
+		hour, minute = 0, 0
+	}
+
+	scheduler.Register(scheduler.Job{
+		Name:     "daily_report",
+		Schedule: fmt.Sprintf("CRON_TZ=%s %d %d * * *", tz, minute, hour),
+		Run: func(ctx context.Context, at time.Time) error {
+			return RunDailyReport(at)
+		},
+	})
+}
+
+// parseClock parses an HH:MM time of day
+func parseClock(value string) (int, int, error) {
+	parts := strings.Split(strings.TrimSpace(value), ":")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("expected HH:MM, got %q", value)
+	}
+
+	hour, err := strconv.Atoi(parts[0])
+	if err != nil || hour < 0 || hour > 23 {
+		return 0, 0, fmt.Errorf("invalid hour in %q", value)
+	}
+	minute, err := strconv.Atoi(parts[1])
+	if err != nil || minute < 0 || minute > 59 {
+		return 0, 0, fmt.Errorf("invalid minute in %q", value)
+	}
+
+	return hour, minute, nil
+}
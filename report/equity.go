@@ -0,0 +1,97 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"arbitrage.trade/config"
+	"arbitrage.trade/fx"
+	"arbitrage.trade/storage"
+)
+
+// EquityPoint is one reporting day of the equity curve
+type EquityPoint struct {
+	Date                time.Time `json:"date"` // Start of the day in DAILY_REPORT_TZ
+	Trades              int       `json:"trades"`
+	Profit              float64   `json:"profit"`     // USDT, net of fees and funding
+	Cumulative          float64   `json:"cumulative"` // USDT since the start of the curve
+	ProfitConverted     float64   `json:"profit_converted,omitempty"`
+	CumulativeConverted float64   `json:"cumulative_converted,omitempty"`
+}
+
+// EquityCurve is the cumulative realized P&L per day, in USDT and the reporting currency
+type EquityCurve struct {
+	From     time.Time     `json:"from,omitempty"`
+	To       time.Time     `json:"to"`
+	Currency string        `json:"currency"`       // REPORT_CURRENCY the converted amounts are in, USDT when unconverted
+	Rate     float64       `json:"rate,omitempty"` // Units of the currency per USDT
+	Total    float64       `json:"total"`
+	Points   []EquityPoint `json:"points"` // Oldest first, days without trades omitted
+}
+
+// BuildEquityCurve sums the trades per day of their close in loc and converts at rate (1 leaves USDT)
+func BuildEquityCurve(trades []storage.TradeRecord, loc *time.Location, rate fx.Rate) EquityCurve {
+	curve := EquityCurve{Currency: rate.Currency, Rate: rate.Rate, Points: []EquityPoint{}}
+	byDay := make(map[time.Time]*EquityPoint)
+
+	for _, t := range trades {
+		closed := t.CloseTime.In(loc)
+		day := time.Date(closed.Year(), closed.Month(), closed.Day(), 0, 0, 0, 0, loc)
+		point, ok := byDay[day]
+		if !ok {
+			point = &EquityPoint{Date: day}
+			byDay[day] = point
+		}
+		point.Trades++
+		point.Profit += t.TotalProfit
+	}
+
+	for _, point := range byDay {
+		curve.Points = append(curve.Points, *point)
+	}
+	sort.Slice(curve.Points, func(i, j int) bool {
+		return curve.Points[i].Date.Before(curve.Points[j].Date)
+	})
+
+	for i := range curve.Points {
+		curve.Total += curve.Points[i].Profit
+		curve.Points[i].Cumulative = curve.Total
+		if rate.Currency != "USDT" {
+			curve.Points[i].ProfitConverted = rate.Convert(curve.Points[i].Profit)
+			curve.Points[i].CumulativeConverted = rate.Convert(curve.Total)
+		}
+	}
+	return curve
+}
+
+// LoadEquityCurve builds the equity curve over trades closed in the last `days` days, or all time when days <= 0
+// Without a usable reporting currency rate the curve is given in USDT only
+func LoadEquityCurve(ctx context.Context, days int) (EquityCurve, error) {
+	to := time.Now()
+	from := time.Time{}
+	if days > 0 {
+		from = to.AddDate(0, 0, -days)
+	}
+
+	trades, err := storage.LoadTrades(from, to)
+	if err != nil {
+		return EquityCurve{}, fmt.Errorf("failed to load trades: %w", err)
+	}
+
+	loc, err := time.LoadLocation(config.GetString("DAILY_REPORT_TZ", "UTC"))
+	if err != nil {
+		loc = time.UTC
+	}
+	rate, err := fx.Current(ctx)
+	if err != nil {
+		log.Printf("[REPORT] LoadEquityCurve - ERROR: Reporting in USDT only: %v", err)
+		rate = fx.Rate{Currency: "USDT", Rate: 1}
+	}
+
+	curve := BuildEquityCurve(trades, loc, rate)
+	curve.From, curve.To = from, to
+	return curve, nil
+}
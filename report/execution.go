@@ -0,0 +1,80 @@
+package report
+
+import (
+	"math"
+	"sort"
+
+	"arbitrage.trade/storage"
+)
+
+// ExecutionStats is the execution quality of one spot:perp venue combination's trades
+type ExecutionStats struct {
+	SpotExchange     string  `json:"spot_exchange"`
+	PerpExchange     string  `json:"perp_exchange"`
+	Trades           int     `json:"trades"`
+	DivergedTrades   int     `json:"diverged_trades"` // Trades with a leg beyond EXEC_DIVERGENCE_LIMIT_BPS
+	Legs             int     `json:"legs"`
+	DivergedLegs     int     `json:"diverged_legs"`
+	AvgSlippage      float64 `json:"avg_slippage_pct"`   // Per leg, positive when worse than decided
+	AvgDivergence    float64 `json:"avg_divergence_bps"` // Per leg, either way
+	MaxDivergence    float64 `json:"max_divergence_bps"`
+	AvgDetectToFill  float64 `json:"avg_detect_to_fill_ms"`
+	AvgSpreadCapture float64 `json:"avg_spread_capture_pct"`
+	DivergedRate     float64 `json:"diverged_rate_pct"` // Share of trades flagged
+	sumSlippage      float64
+	sumDivergence    float64
+	sumDetectToFill  float64
+	sumCapture       float64
+}
+
+// ExecutionQuality aggregates the execution log per venue combination, most flagged trades first
+func ExecutionQuality(records []storage.ExecutionRecord) []ExecutionStats {
+	byCombination := make(map[string]*ExecutionStats)
+
+	for _, r := range records {
+		key := r.SpotExchange + ":" + r.FuturesExchange
+		stats, ok := byCombination[key]
+		if !ok {
+			stats = &ExecutionStats{SpotExchange: r.SpotExchange, PerpExchange: r.FuturesExchange}
+			byCombination[key] = stats
+		}
+
+		stats.Trades++
+		stats.sumDetectToFill += r.DetectToFillMs
+		stats.sumCapture += r.SpreadCapture
+		if r.Diverged {
+			stats.DivergedTrades++
+		}
+		for _, leg := range r.Legs {
+			divergence := math.Abs(leg.SlippagePct) * 100
+			stats.Legs++
+			stats.sumSlippage += leg.SlippagePct
+			stats.sumDivergence += divergence
+			stats.MaxDivergence = max(stats.MaxDivergence, divergence)
+			if leg.Diverged {
+				stats.DivergedLegs++
+			}
+		}
+	}
+
+	quality := make([]ExecutionStats, 0, len(byCombination))
+	for _, stats := range byCombination {
+		n := float64(stats.Trades)
+		stats.AvgDetectToFill = stats.sumDetectToFill / n
+		stats.AvgSpreadCapture = stats.sumCapture / n
+		stats.DivergedRate = float64(stats.DivergedTrades) / n * 100
+		if stats.Legs > 0 {
+			stats.AvgSlippage = stats.sumSlippage / float64(stats.Legs)
+			stats.AvgDivergence = stats.sumDivergence / float64(stats.Legs)
+		}
+		quality = append(quality, *stats)
+	}
+
+	sort.Slice(quality, func(i, j int) bool {
+		if quality[i].DivergedTrades != quality[j].DivergedTrades {
+			return quality[i].DivergedTrades > quality[j].DivergedTrades
+		}
+		return quality[i].SpotExchange+":"+quality[i].PerpExchange < quality[j].SpotExchange+":"+quality[j].PerpExchange
+	})
+	return quality
+}
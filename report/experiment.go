@@ -0,0 +1,168 @@
+package report
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"arbitrage.trade/storage"
+)
+
+// VariantStats is one experiment variant's closed trades
+type VariantStats struct {
+	Variant          string  `json:"variant"`
+	Trades           int     `json:"trades"`
+	Wins             int     `json:"wins"`
+	WinRate          float64 `json:"win_rate_pct"`
+	Profit           float64 `json:"profit"`
+	AvgProfit        float64 `json:"avg_profit"`
+	StdDevProfit     float64 `json:"stddev_profit"`
+	Fees             float64 `json:"fees"`
+	Volume           float64 `json:"volume"`
+	ReturnOnVolume   float64 `json:"return_on_volume_pct"`
+	AvgSlippage      float64 `json:"avg_slippage_pct"` // Summed over a trade's legs
+	AvgSpreadCapture float64 `json:"avg_spread_capture_pct"`
+	AvgDetectToFill  float64 `json:"avg_detect_to_fill_ms"`
+	DivergedRate     float64 `json:"diverged_rate_pct"`
+	sumSquares       float64
+	sumSlippage      float64
+	executions       int
+	sumCapture       float64
+	sumDetectToFill  float64
+	diverged         int
+}
+
+// ExperimentReport compares an A/B experiment's variants
+type ExperimentReport struct {
+	Experiment    string         `json:"experiment"`
+	From          time.Time      `json:"from,omitempty"`
+	To            time.Time      `json:"to"`
+	Variants      []VariantStats `json:"variants"`        // Sorted by name
+	AvgProfitDiff float64        `json:"avg_profit_diff"` // Second variant minus first, per trade
+	TStat         float64        `json:"t_stat"`          // Welch t-statistic of the difference, 0 with fewer than 2 trades a side
+}
+
+// CompareExperiment aggregates the experiment's closed trades and their execution records per variant
+func CompareExperiment(experiment string, trades []storage.TradeRecord, executions []storage.ExecutionRecord) ExperimentReport {
+	report := ExperimentReport{Experiment: experiment, Variants: []VariantStats{}}
+	byVariant := make(map[string]*VariantStats)
+	variantStats := func(variant string) *VariantStats {
+		stats, ok := byVariant[variant]
+		if !ok {
+			stats = &VariantStats{Variant: variant}
+			byVariant[variant] = stats
+		}
+		return stats
+	}
+
+	for _, t := range trades {
+		if t.Experiment != experiment || t.Variant == "" {
+			continue
+		}
+		stats := variantStats(t.Variant)
+		stats.Trades++
+		stats.Profit += t.TotalProfit
+		stats.sumSquares += t.TotalProfit * t.TotalProfit
+		stats.Fees += t.Fees
+		stats.Volume += t.Amount
+		stats.sumSlippage += t.Slippage
+		if t.TotalProfit > 0 {
+			stats.Wins++
+		}
+	}
+
+	for _, r := range executions {
+		if r.Experiment != experiment || r.Variant == "" {
+			continue
+		}
+		stats := variantStats(r.Variant)
+		stats.executions++
+		stats.sumCapture += r.SpreadCapture
+		stats.sumDetectToFill += r.DetectToFillMs
+		if r.Diverged {
+			stats.diverged++
+		}
+	}
+
+	for _, stats := range byVariant {
+		if stats.Trades > 0 {
+			n := float64(stats.Trades)
+			stats.AvgProfit = stats.Profit / n
+			stats.WinRate = float64(stats.Wins) / n * 100
+			stats.AvgSlippage = stats.sumSlippage / n
+			if stats.Trades > 1 {
+				stats.StdDevProfit = math.Sqrt(max(stats.sumSquares-n*stats.AvgProfit*stats.AvgProfit, 0) / (n - 1))
+			}
+		}
+		if stats.Volume > 0 {
+			stats.ReturnOnVolume = stats.Profit / stats.Volume * 100
+		}
+		if stats.executions > 0 {
+			n := float64(stats.executions)
+			stats.AvgSpreadCapture = stats.sumCapture / n
+			stats.AvgDetectToFill = stats.sumDetectToFill / n
+			stats.DivergedRate = float64(stats.diverged) / n * 100
+		}
+		report.Variants = append(report.Variants, *stats)
+	}
+	sort.Slice(report.Variants, func(i, j int) bool {
+		return report.Variants[i].Variant < report.Variants[j].Variant
+	})
+
+	if len(report.Variants) == 2 {
+		a, b := report.Variants[0], report.Variants[1]
+		report.AvgProfitDiff = b.AvgProfit - a.AvgProfit
+		if a.Trades > 1 && b.Trades > 1 {
+			se := math.Sqrt(a.StdDevProfit*a.StdDevProfit/float64(a.Trades) + b.StdDevProfit*b.StdDevProfit/float64(b.Trades))
+			if se > 0 {
+				report.TStat = report.AvgProfitDiff / se
+			}
+		}
+	}
+	return report
+}
+
+// FormatExperiment renders the comparison as a text table
+func FormatExperiment(report ExperimentReport) string {
+	if len(report.Variants) == 0 {
+		return fmt.Sprintf("No closed trades in experiment %s\n", report.Experiment)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Experiment: %s\n", report.Experiment)
+	fmt.Fprintf(&b, "%-16s %6s %6s %11s %9s %9s %9s %9s %9s\n",
+		"Variant", "Trades", "Win%", "P&L", "Avg P&L", "Slippage", "Capture", "Fill ms", "RoV%")
+	for _, s := range report.Variants {
+		fmt.Fprintf(&b, "%-16s %6d %6.1f %+11.4f %+9.4f %8.3f%% %+8.3f%% %9.0f %+9.3f\n",
+			s.Variant, s.Trades, s.WinRate, s.Profit, s.AvgProfit, s.AvgSlippage, s.AvgSpreadCapture, s.AvgDetectToFill, s.ReturnOnVolume)
+	}
+	if len(report.Variants) == 2 {
+		fmt.Fprintf(&b, "\n%s - %s: %+.4f USDT per trade, t = %.2f (|t| > 2 is unlikely to be noise)\n",
+			report.Variants[1].Variant, report.Variants[0].Variant, report.AvgProfitDiff, report.TStat)
+	}
+	return b.String()
+}
+
+// LoadExperiment compares the experiment's trades closed in the last `days` days, or all time when days <= 0
+func LoadExperiment(experiment string, days int) (ExperimentReport, error) {
+	to := time.Now()
+	from := time.Time{}
+	if days > 0 {
+		from = to.AddDate(0, 0, -days)
+	}
+
+	trades, err := storage.LoadTrades(from, to)
+	if err != nil {
+		return ExperimentReport{}, fmt.Errorf("failed to load trades: %w", err)
+	}
+	executions, err := storage.LoadExecutions(from, to)
+	if err != nil {
+		return ExperimentReport{}, fmt.Errorf("failed to load executions: %w", err)
+	}
+
+	report := CompareExperiment(experiment, trades, executions)
+	report.From, report.To = from, to
+	return report, nil
+}
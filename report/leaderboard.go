@@ -0,0 +1,100 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"arbitrage.trade/storage"
+)
+
+// PairStats is one pair's cumulative performance
+type PairStats struct {
+	Pair           string  `json:"pair"`
+	Trades         int     `json:"trades"`
+	Wins           int     `json:"wins"`
+	Profit         float64 `json:"profit"`
+	Fees           float64 `json:"fees"`
+	Volume         float64 `json:"volume"`
+	AvgProfit      float64 `json:"avg_profit"`
+	AvgCaptured    float64 `json:"avg_captured_spread_pct"` // Entry spread minus exit spread
+	AvgSlippage    float64 `json:"avg_slippage_pct"`
+	ReturnOnVolume float64 `json:"return_on_volume_pct"`
+	WinRate        float64 `json:"win_rate_pct"`
+	sumCaptured    float64
+	sumSlippage    float64
+}
+
+// Leaderboard ranks pairs by cumulative profit, best first
+func Leaderboard(trades []storage.TradeRecord) []PairStats {
+	byPair := make(map[string]*PairStats)
+
+	for _, t := range trades {
+		stats, ok := byPair[t.Pair]
+		if !ok {
+			stats = &PairStats{Pair: t.Pair}
+			byPair[t.Pair] = stats
+		}
+
+		stats.Trades++
+		stats.Profit += t.TotalProfit
+		stats.Fees += t.Fees
+		stats.Volume += t.Amount
+		stats.sumCaptured += t.EntrySpread - t.ExitSpread
+		stats.sumSlippage += t.Slippage
+		if t.TotalProfit > 0 {
+			stats.Wins++
+		}
+	}
+
+	board := make([]PairStats, 0, len(byPair))
+	for _, stats := range byPair {
+		n := float64(stats.Trades)
+		stats.AvgProfit = stats.Profit / n
+		stats.AvgCaptured = stats.sumCaptured / n
+		stats.AvgSlippage = stats.sumSlippage / n
+		stats.WinRate = float64(stats.Wins) / n * 100
+		if stats.Volume > 0 {
+			stats.ReturnOnVolume = stats.Profit / stats.Volume * 100
+		}
+		board = append(board, *stats)
+	}
+
+	sort.Slice(board, func(i, j int) bool {
+		return board[i].Profit > board[j].Profit
+	})
+
+	return board
+}
+
+// FormatLeaderboard renders the leaderboard as a text table
+func FormatLeaderboard(board []PairStats) string {
+	if len(board) == 0 {
+		return "No closed trades\n"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-4s %-11s %6s %6s %11s %9s %9s %9s %8s\n",
+		"#", "Pair", "Trades", "Win%", "P&L", "Avg P&L", "Captured", "Slippage", "RoV%")
+	for i, s := range board {
+		fmt.Fprintf(&b, "%-4d %-11s %6d %6.1f %+11.4f %+9.4f %8.3f%% %8.3f%% %+8.3f\n",
+			i+1, s.Pair, s.Trades, s.WinRate, s.Profit, s.AvgProfit, s.AvgCaptured, s.AvgSlippage, s.ReturnOnVolume)
+	}
+	return b.String()
+}
+
+// LoadLeaderboard ranks pairs over trades closed in the last `days` days, or all time when days <= 0
+func LoadLeaderboard(days int) ([]PairStats, error) {
+	to := time.Now()
+	from := time.Time{}
+	if days > 0 {
+		from = to.AddDate(0, 0, -days)
+	}
+
+	trades, err := storage.LoadTrades(from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load trades: %w", err)
+	}
+	return Leaderboard(trades), nil
+}
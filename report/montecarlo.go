@@ -0,0 +1,216 @@
+package report
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"arbitrage.trade/storage"
+)
+
+// Monte Carlo risk report
+//
+// Simulates weeks of trading from the closed-trade history: each simulated week draws its trade count
+// from a Poisson distribution at the historical weekly rate, and each trade's return draws the captured
+// spread (entry minus exit spread) and the slippage independently from their historical distributions,
+// with the fees and funding of the trade the spread came from. Returns are scaled to the configured
+// notional, so the report shows what today's sizing would have risked rather than past sizing.
+
+// MonteCarloConfig is the simulated horizon and sizing
+type MonteCarloConfig struct {
+	Weeks    int
+	Runs     int
+	Notional float64 // USDT per trade
+	Seed     int64
+}
+
+// Distribution summarizes simulated outcomes in USDT
+type Distribution struct {
+	Mean float64 `json:"mean"`
+	P1   float64 `json:"p1"`
+	P5   float64 `json:"p5"`
+	P25  float64 `json:"p25"`
+	P50  float64 `json:"p50"`
+	P75  float64 `json:"p75"`
+	P95  float64 `json:"p95"`
+	P99  float64 `json:"p99"`
+	Min  float64 `json:"min"`
+	Max  float64 `json:"max"`
+}
+
+// MonteCarloReport is the outcome of a Monte Carlo run
+type MonteCarloReport struct {
+	Config        MonteCarloConfig `json:"config"`
+	SampleTrades  int              `json:"sample_trades"`
+	TradesPerWeek float64          `json:"trades_per_week"`
+	WeeklyPnL     Distribution     `json:"weekly_pnl"`
+	LosingWeekPct float64          `json:"losing_week_pct"`
+	HorizonPnL    Distribution     `json:"horizon_pnl"`
+	MaxDrawdown   Distribution     `json:"max_drawdown"` // Peak-to-trough of cumulative P&L trade by trade, positive
+	LosingRunPct  float64          `json:"losing_run_pct"`
+	HistoryFrom   time.Time        `json:"history_from"`
+	HistoryTo     time.Time        `json:"history_to"`
+}
+
+// tradeSample is one historical trade's return components in % of notional
+type tradeSample struct {
+	spreadPct   float64
+	slippagePct float64
+	costPct     float64 // Fees net of funding received
+}
+
+// distribution returns the percentiles of the values, sorting them in place
+func distribution(values []float64) Distribution {
+	if len(values) == 0 {
+		return Distribution{}
+	}
+	sort.Float64s(values)
+	pct := func(p float64) float64 {
+		return values[int(math.Round(p/100*float64(len(values)-1)))]
+	}
+
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return Distribution{
+		Mean: sum / float64(len(values)),
+		P1:   pct(1), P5: pct(5), P25: pct(25), P50: pct(50), P75: pct(75), P95: pct(95), P99: pct(99),
+		Min: values[0], Max: values[len(values)-1],
+	}
+}
+
+// poisson draws a Poisson distributed count with mean lambda
+func poisson(rng *rand.Rand, lambda float64) int {
+	if lambda <= 0 {
+		return 0
+	}
+	if lambda > 30 {
+		// Normal approximation, Knuth's method underflows for large means
+		return max(0, int(math.Round(lambda+math.Sqrt(lambda)*rng.NormFloat64())))
+	}
+	limit, k, p := math.Exp(-lambda), 0, 1.0
+	for {
+		p *= rng.Float64()
+		if p <= limit {
+			return k
+		}
+		k++
+	}
+}
+
+// MonteCarlo simulates cfg.Runs paths of cfg.Weeks weeks from the historical trades
+func MonteCarlo(trades []storage.TradeRecord, cfg MonteCarloConfig) (*MonteCarloReport, error) {
+	if cfg.Runs < 1 || cfg.Weeks < 1 {
+		return nil, fmt.Errorf("runs and weeks must be positive")
+	}
+
+	var samples []tradeSample
+	var from, to time.Time
+	for _, t := range trades {
+		if t.Amount <= 0 {
+			continue
+		}
+		samples = append(samples, tradeSample{
+			spreadPct:   t.EntrySpread - t.ExitSpread,
+			slippagePct: t.Slippage,
+			costPct:     (t.Fees - t.Funding) / t.Amount * 100,
+		})
+		if from.IsZero() || t.CloseTime.Before(from) {
+			from = t.CloseTime
+		}
+		if t.CloseTime.After(to) {
+			to = t.CloseTime
+		}
+	}
+	if len(samples) < 2 {
+		return nil, fmt.Errorf("need at least 2 closed trades, have %d", len(samples))
+	}
+
+	// At least a week of history, a burst of trades in one afternoon is not a weekly rate
+	weeks := math.Max(to.Sub(from).Hours()/(24*7), 1)
+	rate := float64(len(samples)) / weeks
+
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	weekly := make([]float64, 0, cfg.Runs*cfg.Weeks)
+	horizon := make([]float64, 0, cfg.Runs)
+	drawdowns := make([]float64, 0, cfg.Runs)
+	losingWeeks, losingRuns := 0, 0
+
+	for run := 0; run < cfg.Runs; run++ {
+		cumulative, peak, drawdown := 0.0, 0.0, 0.0
+		for week := 0; week < cfg.Weeks; week++ {
+			pnl := 0.0
+			for n := poisson(rng, rate); n > 0; n-- {
+				s := samples[rng.Intn(len(samples))]
+				slippage := samples[rng.Intn(len(samples))].slippagePct
+				trade := cfg.Notional * (s.spreadPct - slippage - s.costPct) / 100
+				pnl += trade
+
+				cumulative += trade
+				peak = math.Max(peak, cumulative)
+				drawdown = math.Max(drawdown, peak-cumulative)
+			}
+			weekly = append(weekly, pnl)
+			if pnl < 0 {
+				losingWeeks++
+			}
+		}
+		horizon = append(horizon, cumulative)
+		drawdowns = append(drawdowns, drawdown)
+		if cumulative < 0 {
+			losingRuns++
+		}
+	}
+
+	return &MonteCarloReport{
+		Config:        cfg,
+		SampleTrades:  len(samples),
+		TradesPerWeek: rate,
+		WeeklyPnL:     distribution(weekly),
+		LosingWeekPct: float64(losingWeeks) / float64(len(weekly)) * 100,
+		HorizonPnL:    distribution(horizon),
+		MaxDrawdown:   distribution(drawdowns),
+		LosingRunPct:  float64(losingRuns) / float64(cfg.Runs) * 100,
+		HistoryFrom:   from,
+		HistoryTo:     to,
+	}, nil
+}
+
+// FormatMonteCarlo renders the report as text
+func FormatMonteCarlo(r *MonteCarloReport) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Monte Carlo: %d runs × %d weeks at %.2f USDT per trade\n", r.Config.Runs, r.Config.Weeks, r.Config.Notional)
+	fmt.Fprintf(&b, "History: %d trades %s → %s, %.1f trades/week\n\n",
+		r.SampleTrades, r.HistoryFrom.Format("2006-01-02"), r.HistoryTo.Format("2006-01-02"), r.TradesPerWeek)
+
+	fmt.Fprintf(&b, "%-16s %10s %10s %10s %10s %10s %10s %10s\n", "", "Mean", "P1", "P5", "P50", "P95", "P99", "Worst")
+	row := func(name string, d Distribution, worst float64) {
+		fmt.Fprintf(&b, "%-16s %+10.2f %+10.2f %+10.2f %+10.2f %+10.2f %+10.2f %+10.2f\n",
+			name, d.Mean, d.P1, d.P5, d.P50, d.P95, d.P99, worst)
+	}
+	row("Weekly P&L", r.WeeklyPnL, r.WeeklyPnL.Min)
+	row(fmt.Sprintf("%d-week P&L", r.Config.Weeks), r.HorizonPnL, r.HorizonPnL.Min)
+	row("Max drawdown", r.MaxDrawdown, r.MaxDrawdown.Max)
+
+	fmt.Fprintf(&b, "\nLosing weeks: %.1f%%, losing %d-week runs: %.1f%%\n", r.LosingWeekPct, r.Config.Weeks, r.LosingRunPct)
+	return b.String()
+}
+
+// LoadMonteCarlo simulates from the trades closed in the last `days` days, or all time when days <= 0
+func LoadMonteCarlo(days int, cfg MonteCarloConfig) (*MonteCarloReport, error) {
+	to := time.Now()
+	from := time.Time{}
+	if days > 0 {
+		from = to.AddDate(0, 0, -days)
+	}
+
+	trades, err := storage.LoadTrades(from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load trades: %w", err)
+	}
+	return MonteCarlo(trades, cfg)
+}
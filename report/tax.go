@@ -0,0 +1,245 @@
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"arbitrage.trade/storage"
+)
+
+// Per-lot realized gains
+//
+// Spot buys open lots of the base asset and spot sells close them first-in first-out, pooled across
+// venues. Buys and sells come from the spot legs of EXECUTIONS_FILE, plus the imported fills of
+// FILLS_FILE that aren't the bot's own (or predate the execution log, whose legs they'd otherwise
+// repeat). Buy fees add to a lot's cost basis and sell fees reduce proceeds; USDT is taken at par
+// with USD. A sell with no open lot left (e.g. reverse mode selling inventory bought off-bot) is
+// reported with a zero cost basis and no acquisition date. Perp legs aren't lots and are excluded.
+
+// SpotTrade is one spot buy or sell of the base asset
+type SpotTrade struct {
+	Time     time.Time
+	Exchange string
+	Asset    string
+	Side     string // "buy" or "sell"
+	Quantity float64
+	Price    float64
+	Fee      float64 // USDT
+}
+
+// Disposal is the part of one sell closed against one lot
+type Disposal struct {
+	Asset      string
+	Quantity   float64
+	Acquired   time.Time // Zero when no open lot was left
+	Sold       time.Time
+	Proceeds   float64
+	CostBasis  float64
+	Gain       float64
+	AcquiredOn string // Exchange the lot was bought on
+	SoldOn     string
+}
+
+// LongTerm reports whether the lot was held for more than a year
+func (d Disposal) LongTerm() bool {
+	return !d.Acquired.IsZero() && d.Sold.After(d.Acquired.AddDate(1, 0, 0))
+}
+
+// baseAsset returns the upper-cased base asset of a pair such as btc-usdt
+func baseAsset(pair string) string {
+	return strings.ToUpper(strings.SplitN(pair, "-", 2)[0])
+}
+
+// SpotTrades collects the spot buys and sells of executions and imported fills, oldest first
+func SpotTrades(executions []storage.ExecutionRecord, fills []storage.FillRecord) []SpotTrade {
+	var trades []SpotTrade
+	var firstLeg time.Time
+	for _, record := range executions {
+		for _, leg := range record.Legs {
+			if !strings.HasSuffix(leg.Leg, "_spot") || leg.Quantity <= 0 {
+				continue
+			}
+			// Forward positions buy spot on open and sell on close, reverse ones the opposite
+			side := "sell"
+			if strings.HasPrefix(leg.Leg, "open_") != record.Reverse {
+				side = "buy"
+			}
+			trades = append(trades, SpotTrade{
+				Time:     leg.FilledAt,
+				Exchange: leg.Exchange,
+				Asset:    baseAsset(record.Pair),
+				Side:     side,
+				Quantity: leg.Quantity,
+				Price:    leg.Price,
+				Fee:      leg.Fee,
+			})
+			if firstLeg.IsZero() || leg.FilledAt.Before(firstLeg) {
+				firstLeg = leg.FilledAt
+			}
+		}
+	}
+
+	for _, fill := range fills {
+		if fill.Market != "spot" || fill.Quantity <= 0 {
+			continue
+		}
+		if fill.Bot && !firstLeg.IsZero() && !fill.Time.Before(firstLeg) {
+			continue
+		}
+
+		asset := baseAsset(fill.Pair)
+		quantity, fee := fill.Quantity, 0.0
+		switch strings.ToUpper(fill.FeeAsset) {
+		case "USDT", "USDC", "USD":
+			fee = fill.Fee
+		case asset:
+			// Charged in the base asset: a buy receives less, a sell's fee is worth its price
+			if fill.Side == "buy" {
+				quantity -= fill.Fee
+			} else {
+				fee = fill.Fee * fill.Price
+			}
+		}
+		trades = append(trades, SpotTrade{
+			Time:     fill.Time,
+			Exchange: fill.Exchange,
+			Asset:    asset,
+			Side:     fill.Side,
+			Quantity: quantity,
+			Price:    fill.Price,
+			Fee:      fee,
+		})
+	}
+
+	sort.SliceStable(trades, func(i, j int) bool {
+		return trades[i].Time.Before(trades[j].Time)
+	})
+	return trades
+}
+
+// FIFODisposals matches each sell against the oldest open lots of its asset
+func FIFODisposals(trades []SpotTrade) []Disposal {
+	type lot struct {
+		quantity float64
+		costPer  float64 // USDT per unit, buy fee included
+		acquired time.Time
+		exchange string
+	}
+	open := make(map[string][]*lot)
+
+	var disposals []Disposal
+	for _, t := range trades {
+		if t.Quantity <= 0 {
+			continue
+		}
+		if t.Side == "buy" {
+			open[t.Asset] = append(open[t.Asset], &lot{
+				quantity: t.Quantity,
+				costPer:  (t.Quantity*t.Price + t.Fee) / t.Quantity,
+				acquired: t.Time,
+				exchange: t.Exchange,
+			})
+			continue
+		}
+
+		proceedsPer := (t.Quantity*t.Price - t.Fee) / t.Quantity
+		remaining := t.Quantity
+		lots := open[t.Asset]
+		for remaining > 1e-12 && len(lots) > 0 {
+			l := lots[0]
+			quantity := remaining
+			if l.quantity < quantity {
+				quantity = l.quantity
+			}
+			d := Disposal{
+				Asset:      t.Asset,
+				Quantity:   quantity,
+				Acquired:   l.acquired,
+				Sold:       t.Time,
+				Proceeds:   quantity * proceedsPer,
+				CostBasis:  quantity * l.costPer,
+				AcquiredOn: l.exchange,
+				SoldOn:     t.Exchange,
+			}
+			d.Gain = d.Proceeds - d.CostBasis
+			disposals = append(disposals, d)
+
+			remaining -= quantity
+			l.quantity -= quantity
+			if l.quantity <= 1e-12 {
+				lots = lots[1:]
+			}
+		}
+		open[t.Asset] = lots
+
+		if remaining > 1e-12 {
+			disposals = append(disposals, Disposal{
+				Asset:    t.Asset,
+				Quantity: remaining,
+				Sold:     t.Time,
+				Proceeds: remaining * proceedsPer,
+				Gain:     remaining * proceedsPer,
+				SoldOn:   t.Exchange,
+			})
+		}
+	}
+	return disposals
+}
+
+// WriteTaxCSV writes the disposals as a per-lot realized gains CSV (Form 8949 style columns)
+func WriteTaxCSV(w io.Writer, disposals []Disposal) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"Asset", "Amount", "Date Acquired", "Date Sold", "Proceeds (USD)", "Cost Basis (USD)",
+		"Gain (USD)", "Holding Period", "Acquired On", "Sold On"})
+
+	usd := func(v float64) string { return strconv.FormatFloat(v, 'f', 2, 64) }
+	for _, d := range disposals {
+		acquired := ""
+		if !d.Acquired.IsZero() {
+			acquired = d.Acquired.UTC().Format(time.RFC3339)
+		}
+		holding := "short"
+		if d.LongTerm() {
+			holding = "long"
+		}
+		cw.Write([]string{
+			d.Asset,
+			strconv.FormatFloat(d.Quantity, 'f', -1, 64),
+			acquired,
+			d.Sold.UTC().Format(time.RFC3339),
+			usd(d.Proceeds),
+			usd(d.CostBasis),
+			usd(d.Gain),
+			holding,
+			d.AcquiredOn,
+			d.SoldOn,
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// LoadTaxReport returns the disposals sold in [from, to), matched against lots from the full history
+func LoadTaxReport(from, to time.Time) ([]Disposal, error) {
+	executions, err := storage.LoadExecutions(time.Time{}, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load executions: %w", err)
+	}
+	fills, err := storage.LoadFills(time.Time{}, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load fills: %w", err)
+	}
+
+	var disposals []Disposal
+	for _, d := range FIFODisposals(SpotTrades(executions, fills)) {
+		if !d.Sold.Before(from) && d.Sold.Before(to) {
+			disposals = append(disposals, d)
+		}
+	}
+	return disposals, nil
+}
@@ -0,0 +1,84 @@
+package risk
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+)
+
+var ErrInsufficientCapital = errors.New("insufficient unreserved capital")
+
+// CapitalLedger locks free USDT per exchange and market while an opportunity's legs are in flight,
+// so two opportunities firing together cannot both size against the same balance
+type CapitalLedger struct {
+	mu sync.Mutex
+	// exchange:market -> pair -> locked USDT
+	locked map[string]map[string]float64
+}
+
+var capital = &CapitalLedger{
+	locked: make(map[string]map[string]float64),
+}
+
+// LockCapital locks amountUSDT of a venue's freeUSDT for a pair
+// Returns an error without locking anything if the balance is already spoken for
+func LockCapital(exchange, market, pairName string, amountUSDT, freeUSDT float64) error {
+	capital.mu.Lock()
+	defer capital.mu.Unlock()
+
+	key := venueKey(exchange, market)
+	locked := capital.totalLocked(key)
+
+	if locked+amountUSDT > freeUSDT {
+		return fmt.Errorf("%w: %s %s locked %.2f + %.2f exceeds free %.2f USDT",
+			ErrInsufficientCapital, exchange, market, locked, amountUSDT, freeUSDT)
+	}
+
+	if _, ok := capital.locked[key]; !ok {
+		capital.locked[key] = make(map[string]float64)
+	}
+	capital.locked[key][pairName] += amountUSDT
+
+	log.Printf("[RISK] Locked %.2f USDT capital on %s %s for %s (locked: %.2f / free: %.2f)",
+		amountUSDT, exchange, market, pairName, locked+amountUSDT, freeUSDT)
+	return nil
+}
+
+// UnlockCapital releases the capital locked for a pair once its legs completed or failed
+func UnlockCapital(exchange, market, pairName string) {
+	capital.mu.Lock()
+	defer capital.mu.Unlock()
+
+	delete(capital.locked[venueKey(exchange, market)], pairName)
+}
+
+// LockedCapital returns the USDT currently locked on an exchange market
+func LockedCapital(exchange, market string) float64 {
+	capital.mu.Lock()
+	defer capital.mu.Unlock()
+
+	return capital.totalLocked(venueKey(exchange, market))
+}
+
+func (l *CapitalLedger) totalLocked(key string) float64 {
+	total := 0.0
+	for _, amount := range l.locked[key] {
+		total += amount
+	}
+	return total
+}
+
+// CapitalSnapshot returns a copy of locked capital keyed by exchange:market
+func CapitalSnapshot() map[string]float64 {
+	capital.mu.Lock()
+	defer capital.mu.Unlock()
+
+	result := make(map[string]float64, len(capital.locked))
+	for key := range capital.locked {
+		if locked := capital.totalLocked(key); locked > 0 {
+			result[key] = locked
+		}
+	}
+	return result
+}
@@ -0,0 +1,157 @@
+package risk
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sync"
+
+	"arbitrage.trade/config"
+)
+
+// DefaultMaxOpenNotional is the per exchange+market cap in USDT when no override is configured
+// Override with MAX_NOTIONAL_PER_VENUE, or per venue with MAX_NOTIONAL_<EXCHANGE>_<MARKET> (e.g. MAX_NOTIONAL_BINANCE_SPOT)
+const DefaultMaxOpenNotional = 100.0
+
+var ErrNotionalLimit = errors.New("open notional limit reached")
+
+var ErrPairBudget = errors.New("pair capital budget reached")
+
+// NotionalLedger tracks open notional per exchange and market (spot / futures)
+// Every order that adds exposure must reserve against it first
+type NotionalLedger struct {
+	mu sync.Mutex
+	// exchange:market -> pair -> reserved USDT
+	reserved map[string]map[string]float64
+}
+
+var ledger = &NotionalLedger{
+	reserved: make(map[string]map[string]float64),
+}
+
+func venueKey(exchange, market string) string {
+	return exchange + ":" + market
+}
+
+// MaxOpenNotional returns the configured cap for an exchange market
+func MaxOpenNotional(exchange, market string) float64 {
+	def := config.GetFloat("MAX_NOTIONAL_PER_VENUE", DefaultMaxOpenNotional)
+	return config.GetFloat(config.Key("MAX_NOTIONAL", exchange, market), def)
+}
+
+// MaxPairNotional returns a pair's capital budget: the notional all its legs together may hold open across venues
+// MAX_NOTIONAL_PER_PAIR (default 0, unlimited), or per pair MAX_NOTIONAL_PAIR_<PAIR> (e.g. MAX_NOTIONAL_PAIR_BTC_USDT)
+func MaxPairNotional(pairName string) float64 {
+	def := config.GetFloat("MAX_NOTIONAL_PER_PAIR", 0)
+	return config.GetFloat(config.Key("MAX_NOTIONAL_PAIR", pairName), def)
+}
+
+// PairOpenNotional returns the notional reserved for a pair across every exchange market
+func PairOpenNotional(pairName string) float64 {
+	ledger.mu.Lock()
+	defer ledger.mu.Unlock()
+
+	return ledger.pairLocked(pairName)
+}
+
+// LegHeadroom returns how much USDT a new two-legged position in the pair may put on each leg
+// within its budget, +Inf when the pair has none
+func LegHeadroom(pairName string) float64 {
+	budget := MaxPairNotional(pairName)
+	if budget <= 0 {
+		return math.Inf(1)
+	}
+	return math.Max(0, (budget-PairOpenNotional(pairName))/2)
+}
+
+// Reserve books amountUSDT of open notional for a pair on an exchange market
+// Returns an error without reserving anything if the venue cap would be exceeded
+func Reserve(exchange, market, pairName string, amountUSDT float64) error {
+	ledger.mu.Lock()
+	defer ledger.mu.Unlock()
+
+	key := venueKey(exchange, market)
+	open := ledger.openLocked(key)
+	limit := MaxOpenNotional(exchange, market)
+
+	if open+amountUSDT > limit {
+		return fmt.Errorf("%w: %s %s open %.2f + %.2f exceeds %.2f USDT",
+			ErrNotionalLimit, exchange, market, open, amountUSDT, limit)
+	}
+
+	if budget := MaxPairNotional(pairName); budget > 0 {
+		if pairOpen := ledger.pairLocked(pairName); pairOpen+amountUSDT > budget {
+			return fmt.Errorf("%w: %s open %.2f + %.2f exceeds %.2f USDT",
+				ErrPairBudget, pairName, pairOpen, amountUSDT, budget)
+		}
+	}
+
+	if _, ok := ledger.reserved[key]; !ok {
+		ledger.reserved[key] = make(map[string]float64)
+	}
+	ledger.reserved[key][pairName] += amountUSDT
+
+	log.Printf("[RISK] Reserved %.2f USDT on %s %s for %s (open: %.2f / %.2f)",
+		amountUSDT, exchange, market, pairName, open+amountUSDT, limit)
+	return nil
+}
+
+// Release frees the notional reserved for a pair on an exchange market
+func Release(exchange, market, pairName string) {
+	ledger.mu.Lock()
+	defer ledger.mu.Unlock()
+
+	key := venueKey(exchange, market)
+	pairs, ok := ledger.reserved[key]
+	if !ok {
+		return
+	}
+
+	amount := pairs[pairName]
+	delete(pairs, pairName)
+
+	log.Printf("[RISK] Released %.2f USDT on %s %s for %s (open: %.2f)",
+		amount, exchange, market, pairName, ledger.openLocked(key))
+}
+
+// OpenNotional returns the currently reserved notional for an exchange market
+func OpenNotional(exchange, market string) float64 {
+	ledger.mu.Lock()
+	defer ledger.mu.Unlock()
+
+	return ledger.openLocked(venueKey(exchange, market))
+}
+
+// Snapshot returns a copy of reserved notional keyed by exchange:market
+func Snapshot() map[string]float64 {
+	ledger.mu.Lock()
+	defer ledger.mu.Unlock()
+
+	result := make(map[string]float64, len(ledger.reserved))
+	for key := range ledger.reserved {
+		result[key] = ledger.openLocked(key)
+	}
+	return result
+}
+
+func (l *NotionalLedger) openLocked(key string) float64 {
+	total := 0.0
+	for _, amount := range l.reserved[key] {
+		total += amount
+	}
+	return total
+}
+
+func (l *NotionalLedger) pairLocked(pairName string) float64 {
+	total := 0.0
+	for _, pairs := range l.reserved {
+		total += pairs[pairName]
+	}
+	return total
+}
+
+// BudgetSource exposes the pairs' capital budgets to the analyzer's sizing
+type BudgetSource struct{}
+
+func (BudgetSource) LegHeadroom(pairName string) float64 { return LegHeadroom(pairName) }
@@ -0,0 +1,215 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"arbitrage.trade/config"
+	"arbitrage.trade/notify"
+	"arbitrage.trade/scheduler"
+)
+
+// Trading state
+//
+// New entries are paused while any pause reason is active; each source pauses and resumes only its
+// own reason, so e.g. the end of a maintenance window does not undo an operator pause. Sources:
+//   operator        - control API (POST /control/pause, /control/resume)
+//   circuit_breaker - CIRCUIT_BREAKER_LOSSES consecutive losing trades (0 disables), cleared after
+//                     CIRCUIT_BREAKER_COOLDOWN (default 1h, 0 = only by operator resume)
+//   maintenance     - the TRADING_DAYS / TRADING_HOURS / BLACKOUT_WINDOWS calendar
+//   kill_switch     - KILL_SWITCH_FILE exists (checked every STATE_CHECK_INTERVAL, default 5s)
+// Open positions keep being managed and closed while paused.
+
+var ErrPaused = errors.New("trading paused")
+
+// PauseReason identifies what paused trading
+type PauseReason string
+
+const (
+	ReasonOperator       PauseReason = "operator"
+	ReasonCircuitBreaker PauseReason = "circuit_breaker"
+	ReasonMaintenance    PauseReason = "maintenance"
+	ReasonKillSwitch     PauseReason = "kill_switch"
+)
+
+// ActivePause is one active pause reason
+type ActivePause struct {
+	Reason PauseReason `json:"reason"`
+	Detail string      `json:"detail,omitempty"`
+	Since  time.Time   `json:"since"`
+}
+
+// TradingState is whether new entries are allowed and every reason they aren't
+type TradingState struct {
+	Paused bool          `json:"paused"`
+	Pauses []ActivePause `json:"pauses"`
+}
+
+var (
+	pauses   = make(map[PauseReason]ActivePause)
+	pausesMu sync.Mutex
+
+	losingStreak int
+	streakMu     sync.Mutex
+)
+
+// Pause disables new entries for the reason, updating the detail if it is already active
+func Pause(reason PauseReason, detail string) {
+	pausesMu.Lock()
+	p, active := pauses[reason]
+	if active && p.Detail == detail {
+		pausesMu.Unlock()
+		return
+	}
+	if !active {
+		p = ActivePause{Reason: reason, Since: time.Now()}
+	}
+	p.Detail = detail
+	pauses[reason] = p
+	pausesMu.Unlock()
+
+	if active {
+		return
+	}
+	log.Printf("[STATE] Trading paused (%s): %s", reason, detail)
+	notify.Send(notify.Message{
+		Event: notify.EventAlert,
+		Title: fmt.Sprintf("Trading paused: %s", reason),
+		Body:  detail,
+	})
+}
+
+// Resume clears the reason; trading resumes once no reason is left
+func Resume(reason PauseReason) {
+	pausesMu.Lock()
+	_, active := pauses[reason]
+	delete(pauses, reason)
+	remaining := len(pauses)
+	pausesMu.Unlock()
+
+	if !active {
+		return
+	}
+	if reason == ReasonCircuitBreaker {
+		resetLosingStreak()
+	}
+
+	log.Printf("[STATE] Pause cleared (%s), %d reasons remaining", reason, remaining)
+	if remaining == 0 {
+		notify.Send(notify.Message{
+			Event: notify.EventAlert,
+			Title: "Trading resumed",
+			Body:  fmt.Sprintf("Last pause reason %s cleared.", reason),
+		})
+	}
+}
+
+// CurrentState returns the active pause reasons, oldest first
+func CurrentState() TradingState {
+	pausesMu.Lock()
+	defer pausesMu.Unlock()
+
+	state := TradingState{Paused: len(pauses) > 0, Pauses: make([]ActivePause, 0, len(pauses))}
+	for _, p := range pauses {
+		state.Pauses = append(state.Pauses, p)
+	}
+	sort.Slice(state.Pauses, func(i, j int) bool { return state.Pauses[i].Since.Before(state.Pauses[j].Since) })
+	return state
+}
+
+// pausedBlock returns why the trading state blocks entries, or "" when it doesn't
+func pausedBlock() string {
+	state := CurrentState()
+	if !state.Paused {
+		return ""
+	}
+	p := state.Pauses[0]
+	if p.Detail == "" {
+		return string(p.Reason)
+	}
+	return fmt.Sprintf("%s - %s", p.Reason, p.Detail)
+}
+
+// RecordTradeResult feeds a closed trade's profit to the circuit breaker
+func RecordTradeResult(profit float64) {
+	limit := config.GetInt("CIRCUIT_BREAKER_LOSSES", 0)
+	if limit <= 0 {
+		return
+	}
+
+	streakMu.Lock()
+	if profit < 0 {
+		losingStreak++
+	} else {
+		losingStreak = 0
+	}
+	streak := losingStreak
+	streakMu.Unlock()
+
+	if streak < limit {
+		return
+	}
+	Pause(ReasonCircuitBreaker, fmt.Sprintf("%d consecutive losing trades", streak))
+
+	if cooldown := config.GetDuration("CIRCUIT_BREAKER_COOLDOWN", time.Hour); cooldown > 0 {
+		pausesMu.Lock()
+		since := pauses[ReasonCircuitBreaker].Since
+		pausesMu.Unlock()
+
+		time.AfterFunc(cooldown, func() {
+			// Skip if the operator already resumed and the breaker tripped again since
+			pausesMu.Lock()
+			current, active := pauses[ReasonCircuitBreaker]
+			pausesMu.Unlock()
+			if active && current.Since.Equal(since) {
+				Resume(ReasonCircuitBreaker)
+			}
+		})
+	}
+}
+
+func resetLosingStreak() {
+	streakMu.Lock()
+	losingStreak = 0
+	streakMu.Unlock()
+}
+
+// StartStateWatch applies the maintenance calendar and kill switch in the background
+func StartStateWatch() {
+	interval := config.GetDuration("STATE_CHECK_INTERVAL", 5*time.Second)
+	scheduler.Register(scheduler.Job{
+		Name:       "trading_state",
+		Schedule:   "@every " + interval.String(),
+		RunAtStart: true,
+		Run: func(context.Context, time.Time) error {
+			checkStateSources()
+			return nil
+		},
+	})
+}
+
+// checkStateSources pauses or resumes the calendar and kill-switch reasons from their current state
+func checkStateSources() {
+	if reason := windowBlock(time.Now()); reason != "" {
+		Pause(ReasonMaintenance, reason)
+	} else {
+		Resume(ReasonMaintenance)
+	}
+
+	path := config.GetString("KILL_SWITCH_FILE", "")
+	if path == "" {
+		Resume(ReasonKillSwitch)
+		return
+	}
+	if _, err := os.Stat(path); err == nil {
+		Pause(ReasonKillSwitch, fmt.Sprintf("%s present", path))
+	} else {
+		Resume(ReasonKillSwitch)
+	}
+}
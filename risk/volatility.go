@@ -0,0 +1,159 @@
+package risk
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"arbitrage.trade/config"
+	"arbitrage.trade/orderbook"
+)
+
+// Volatility regime filter
+//
+// Every VOLATILITY_SAMPLE_INTERVAL (default 10s) the spot mid of each pair, averaged over the venues
+// with a book, is sampled. Realized volatility is the standard deviation of log returns over the last
+// VOLATILITY_WINDOW (default 30m), scaled to one hour, in %. New entries on a pair are disabled while
+// it exceeds VOLATILITY_MAX_PCT (0 disables the filter).
+
+// minVolatilitySamples is the number of returns needed before a pair's volatility is judged
+const minVolatilitySamples = 10
+
+// BookSource provides the spot books volatility is sampled from
+type BookSource interface {
+	GetBook(pairName, exchangeName string, isSpot bool) (*orderbook.OrderBook, bool)
+}
+
+type priceSample struct {
+	at    time.Time
+	price float64
+}
+
+var (
+	samples   = make(map[string][]priceSample) // pair -> mids, oldest first
+	samplesMu sync.Mutex
+)
+
+// StartVolatilitySampling samples pair mids in the background when the volatility filter is enabled
+func StartVolatilitySampling(books BookSource, exchanges []string, pairs []string) {
+	if config.GetFloat("VOLATILITY_MAX_PCT", 0) <= 0 {
+		return
+	}
+
+	interval := config.GetDuration("VOLATILITY_SAMPLE_INTERVAL", 10*time.Second)
+	go func() {
+		for {
+			for _, pairName := range pairs {
+				if mid := averageMid(books, exchanges, pairName); mid > 0 {
+					recordSample(pairName, mid, time.Now())
+				}
+			}
+			time.Sleep(interval)
+		}
+	}()
+}
+
+// averageMid returns the mean spot mid across venues with a two-sided book, or 0 when none has one
+func averageMid(books BookSource, exchanges []string, pairName string) float64 {
+	sum, n := 0.0, 0
+	for _, exchange := range exchanges {
+		book, ok := books.GetBook(pairName, exchange, true)
+		if !ok {
+			continue
+		}
+		bid, _, bidOk := book.GetBestBid()
+		ask, _, askOk := book.GetBestAsk()
+		if !bidOk || !askOk {
+			continue
+		}
+		sum += (bid + ask) / 2
+		n++
+	}
+	if n == 0 {
+		return 0
+	}
+	return sum / float64(n)
+}
+
+// recordSample appends a mid and drops samples older than the volatility window
+func recordSample(pairName string, price float64, at time.Time) {
+	window := config.GetDuration("VOLATILITY_WINDOW", 30*time.Minute)
+
+	samplesMu.Lock()
+	defer samplesMu.Unlock()
+
+	s := append(samples[pairName], priceSample{at: at, price: price})
+	cutoff := at.Add(-window)
+	for len(s) > 0 && s[0].at.Before(cutoff) {
+		s = s[1:]
+	}
+	samples[pairName] = s
+}
+
+// RealizedVolatility returns the pair's hourly realized volatility in %, false until enough samples exist
+func RealizedVolatility(pairName string) (float64, bool) {
+	samplesMu.Lock()
+	s := append([]priceSample(nil), samples[pairName]...)
+	samplesMu.Unlock()
+
+	if len(s) <= minVolatilitySamples {
+		return 0, false
+	}
+
+	returns := make([]float64, 0, len(s)-1)
+	for i := 1; i < len(s); i++ {
+		returns = append(returns, math.Log(s[i].price/s[i-1].price))
+	}
+
+	mean := 0.0
+	for _, r := range returns {
+		mean += r
+	}
+	mean /= float64(len(returns))
+
+	variance := 0.0
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+	}
+	variance /= float64(len(returns) - 1)
+
+	// Scale the per-sample deviation to one hour
+	step := s[len(s)-1].at.Sub(s[0].at) / time.Duration(len(returns))
+	if step <= 0 {
+		return 0, false
+	}
+	perHour := float64(time.Hour) / float64(step)
+	return math.Sqrt(variance*perHour) * 100.0, true
+}
+
+// volatilityBlock returns why the volatility regime blocks entries on the pair, or "" when it doesn't
+func volatilityBlock(pairName string) string {
+	limit := config.GetFloat("VOLATILITY_MAX_PCT", 0)
+	if limit <= 0 {
+		return ""
+	}
+	vol, ok := RealizedVolatility(pairName)
+	if !ok || vol <= limit {
+		return ""
+	}
+	return fmt.Sprintf("realized volatility %.2f%%/h above %.2f%%/h", vol, limit)
+}
+
+// VolatilitySnapshot returns the realized volatility of every pair with enough samples
+func VolatilitySnapshot() map[string]float64 {
+	samplesMu.Lock()
+	pairs := make([]string, 0, len(samples))
+	for pairName := range samples {
+		pairs = append(pairs, pairName)
+	}
+	samplesMu.Unlock()
+
+	snapshot := make(map[string]float64, len(pairs))
+	for _, pairName := range pairs {
+		if vol, ok := RealizedVolatility(pairName); ok {
+			snapshot[pairName] = vol
+		}
+	}
+	return snapshot
+}
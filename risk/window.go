@@ -0,0 +1,317 @@
+package risk
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"arbitrage.trade/config"
+)
+
+// Trading windows
+//
+// New entries are only allowed on TRADING_DAYS (e.g. "mon,tue,wed,thu,fri", empty = every day)
+// within TRADING_HOURS (UTC "HH:MM-HH:MM" ranges, comma separated, may wrap midnight, empty = all day),
+// and never inside a BLACKOUT_WINDOWS entry ("2026-11-12T13:15:00Z/45m", comma separated) such as
+// CPI or FOMC releases. The calendar is applied as the maintenance pause reason (see state.go).
+// PAIR_BLACKOUTS disables entries on one pair around its token events (unlocks, migrations such as
+// MATIC→POL, redenominations), whose spreads are mostly untradeable artifacts:
+// "<pair>@<RFC3339 start>/<duration>[#note]", comma separated, e.g.
+// "matic-usdt@2024-09-04T00:00:00Z/72h#POL migration". Open positions keep being managed and closed
+// outside the windows.
+
+var ErrEntryWindow = errors.New("outside trading window")
+
+type hourRange struct {
+	from, to int // Minutes since midnight UTC
+}
+
+type blackout struct {
+	start time.Time
+	end   time.Time
+}
+
+// PairBlackout is a window during which entries on one pair are disabled
+type PairBlackout struct {
+	Pair  string    `json:"pair"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Note  string    `json:"note,omitempty"`
+}
+
+type windowConfig struct {
+	days          map[time.Weekday]bool // nil = every day
+	hours         []hourRange           // empty = all day
+	blackouts     []blackout
+	pairBlackouts []PairBlackout
+}
+
+var (
+	windowMu      sync.Mutex
+	windowRaw     string
+	windowParsed  *windowConfig
+	entryBlocked  string // Last logged reason entries were blocked for, "" when allowed
+	entryBlockMu  sync.Mutex
+	weekdayByName = map[string]time.Weekday{
+		"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+		"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+	}
+)
+
+// loadWindows returns the parsed window configuration, re-parsing only when the env changed
+func loadWindows() *windowConfig {
+	days := config.GetString("TRADING_DAYS", "")
+	hours := config.GetString("TRADING_HOURS", "")
+	blackouts := config.GetString("BLACKOUT_WINDOWS", "")
+	pairBlackouts := config.GetString("PAIR_BLACKOUTS", "")
+	raw := days + "|" + hours + "|" + blackouts + "|" + pairBlackouts
+
+	windowMu.Lock()
+	defer windowMu.Unlock()
+
+	if windowParsed != nil && raw == windowRaw {
+		return windowParsed
+	}
+
+	cfg := &windowConfig{}
+	for _, d := range splitList(days) {
+		day, ok := weekdayByName[strings.ToLower(d)[:min(3, len(d))]]
+		if !ok {
+			log.Printf("[SCHEDULE] Ignoring invalid TRADING_DAYS entry %q", d)
+			continue
+		}
+		if cfg.days == nil {
+			cfg.days = make(map[time.Weekday]bool)
+		}
+		cfg.days[day] = true
+	}
+
+	for _, h := range splitList(hours) {
+		r, err := parseHourRange(h)
+		if err != nil {
+			log.Printf("[SCHEDULE] Ignoring invalid TRADING_HOURS entry %q: %v", h, err)
+			continue
+		}
+		cfg.hours = append(cfg.hours, r)
+	}
+
+	for _, b := range splitList(blackouts) {
+		w, err := parseBlackout(b)
+		if err != nil {
+			log.Printf("[SCHEDULE] Ignoring invalid BLACKOUT_WINDOWS entry %q: %v", b, err)
+			continue
+		}
+		cfg.blackouts = append(cfg.blackouts, w)
+	}
+
+	for _, b := range splitList(pairBlackouts) {
+		w, err := parsePairBlackout(b)
+		if err != nil {
+			log.Printf("[SCHEDULE] Ignoring invalid PAIR_BLACKOUTS entry %q: %v", b, err)
+			continue
+		}
+		cfg.pairBlackouts = append(cfg.pairBlackouts, w)
+	}
+
+	windowRaw, windowParsed = raw, cfg
+	return cfg
+}
+
+func splitList(v string) []string {
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseHourRange parses "HH:MM-HH:MM"
+func parseHourRange(v string) (hourRange, error) {
+	from, to, ok := strings.Cut(v, "-")
+	if !ok {
+		return hourRange{}, fmt.Errorf("expected HH:MM-HH:MM")
+	}
+	f, err := time.Parse("15:04", strings.TrimSpace(from))
+	if err != nil {
+		return hourRange{}, err
+	}
+	t, err := time.Parse("15:04", strings.TrimSpace(to))
+	if err != nil {
+		return hourRange{}, err
+	}
+	return hourRange{from: f.Hour()*60 + f.Minute(), to: t.Hour()*60 + t.Minute()}, nil
+}
+
+// parseBlackout parses "<RFC3339 start>/<duration>"
+func parseBlackout(v string) (blackout, error) {
+	start, length, ok := strings.Cut(v, "/")
+	if !ok {
+		return blackout{}, fmt.Errorf("expected <RFC3339 start>/<duration>")
+	}
+	s, err := time.Parse(time.RFC3339, strings.TrimSpace(start))
+	if err != nil {
+		return blackout{}, err
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(length))
+	if err != nil {
+		return blackout{}, err
+	}
+	return blackout{start: s, end: s.Add(d)}, nil
+}
+
+// parsePairBlackout parses "<pair>@<RFC3339 start>/<duration>[#note]"
+func parsePairBlackout(v string) (PairBlackout, error) {
+	pair, window, ok := strings.Cut(v, "@")
+	if !ok || strings.TrimSpace(pair) == "" {
+		return PairBlackout{}, fmt.Errorf("expected <pair>@<RFC3339 start>/<duration>[#note]")
+	}
+	window, note, _ := strings.Cut(window, "#")
+	b, err := parseBlackout(window)
+	if err != nil {
+		return PairBlackout{}, err
+	}
+	return PairBlackout{
+		Pair:  strings.ToLower(strings.TrimSpace(pair)),
+		Start: b.start,
+		End:   b.end,
+		Note:  strings.TrimSpace(note),
+	}, nil
+}
+
+func (r hourRange) contains(minute int) bool {
+	if r.from <= r.to {
+		return minute >= r.from && minute < r.to
+	}
+	// Wraps midnight, e.g. 22:00-02:00
+	return minute >= r.from || minute < r.to
+}
+
+// windowBlock returns why entries are blocked at t by the schedule, or "" when they are allowed
+func windowBlock(t time.Time) string {
+	cfg := loadWindows()
+	t = t.UTC()
+
+	for _, b := range cfg.blackouts {
+		if !t.Before(b.start) && t.Before(b.end) {
+			return fmt.Sprintf("blackout %s - %s", b.start.Format(time.RFC3339), b.end.Format(time.RFC3339))
+		}
+	}
+
+	if cfg.days != nil && !cfg.days[t.Weekday()] {
+		return fmt.Sprintf("%s not a trading day", t.Weekday())
+	}
+
+	if len(cfg.hours) > 0 {
+		minute := t.Hour()*60 + t.Minute()
+		inside := false
+		for _, r := range cfg.hours {
+			if r.contains(minute) {
+				inside = true
+				break
+			}
+		}
+		if !inside {
+			return fmt.Sprintf("%s UTC outside TRADING_HOURS", t.Format("15:04"))
+		}
+	}
+
+	return ""
+}
+
+// pairBlackoutBlock returns why entries on the pair are blocked at t by its token-event calendar, or "" when they are allowed
+func pairBlackoutBlock(pairName string, t time.Time) string {
+	pairName = strings.ToLower(pairName)
+	for _, b := range loadWindows().pairBlackouts {
+		if b.Pair != pairName || t.Before(b.Start) || !t.Before(b.End) {
+			continue
+		}
+		reason := fmt.Sprintf("%s blackout until %s", b.Pair, b.End.UTC().Format(time.RFC3339))
+		if b.Note != "" {
+			reason += " (" + b.Note + ")"
+		}
+		return reason
+	}
+	return ""
+}
+
+// PairBlackouts returns the pair blackouts that are active or still ahead at t, soonest first
+func PairBlackouts(t time.Time) []PairBlackout {
+	var result []PairBlackout
+	for _, b := range loadWindows().pairBlackouts {
+		if t.Before(b.End) {
+			result = append(result, b)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Start.Before(result[j].Start)
+	})
+	return result
+}
+
+// EntryAllowed reports whether a new position may be opened on the pair now
+// Returns ErrPaused naming the active pause reason, or ErrEntryWindow when a pair blackout or the
+// volatility regime blocks it
+func EntryAllowed(pairName string) error {
+	if reason := pausedBlock(); reason != "" {
+		logEntryState(pairName, reason)
+		return fmt.Errorf("%w: %s", ErrPaused, reason)
+	}
+
+	reason := pairBlackoutBlock(pairName, time.Now())
+	if reason == "" {
+		reason = volatilityBlock(pairName)
+	}
+	logEntryState(pairName, reason)
+	if reason != "" {
+		return fmt.Errorf("%w: %s", ErrEntryWindow, reason)
+	}
+	return nil
+}
+
+// logEntryState logs when entries become blocked or allowed again rather than on every signal
+func logEntryState(pairName, reason string) {
+	entryBlockMu.Lock()
+	defer entryBlockMu.Unlock()
+
+	key := reason
+	if reason != "" {
+		key = pairName + ": " + reason
+	}
+	if key == entryBlocked {
+		return
+	}
+	if reason != "" {
+		log.Printf("[SCHEDULE %s] New entries disabled - %s", pairName, reason)
+	} else if entryBlocked != "" {
+		log.Printf("[SCHEDULE] New entries enabled")
+	}
+	entryBlocked = key
+}
+
+// WindowState describes whether entries are currently allowed and why not
+type WindowState struct {
+	EntriesAllowed bool               `json:"entries_allowed"`
+	Reason         string             `json:"reason,omitempty"`
+	Volatility     map[string]float64 `json:"volatility_pct"` // Realized hourly volatility per pair
+	MaxVolatility  float64            `json:"max_volatility_pct"`
+	PairBlackouts  []PairBlackout     `json:"pair_blackouts"` // Active and upcoming
+}
+
+// CurrentWindow returns the schedule state, the realized volatility of every sampled pair and the pair blackouts ahead
+func CurrentWindow() WindowState {
+	now := time.Now()
+	reason := windowBlock(now)
+	return WindowState{
+		EntriesAllowed: reason == "",
+		Reason:         reason,
+		Volatility:     VolatilitySnapshot(),
+		MaxVolatility:  config.GetFloat("VOLATILITY_MAX_PCT", 0),
+		PairBlackouts:  PairBlackouts(now),
+	}
+}
@@ -0,0 +1,139 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"arbitrage.trade/config"
+)
+
+// Periodic jobs
+//
+// Background work (funding collection, capability refresh, wallet and balance checks, trading-state
+// checks, the daily report) registers here instead of running its own sleep loop. Each job runs on
+// its default schedule unless SCHEDULE_<JOB> overrides it (see Parse for the syntax, "off" disables),
+// never overlaps itself, and has panics recovered and counted so one failing job can't take the
+// process down. Per-job metrics are served on GET /jobs.
+
+// Job is a periodic task
+type Job struct {
+	Name       string
+	Schedule   string                                        // Default schedule, overridden by SCHEDULE_<NAME>
+	RunAtStart bool                                          // Also run once right away
+	Run        func(ctx context.Context, at time.Time) error // at is the scheduled time
+}
+
+// JobStats are a job's run metrics
+type JobStats struct {
+	Name         string    `json:"name"`
+	Schedule     string    `json:"schedule"`
+	Runs         int       `json:"runs"`
+	Failures     int       `json:"failures"`
+	Panics       int       `json:"panics"`
+	Running      bool      `json:"running"`
+	LastRun      time.Time `json:"last_run,omitempty"`
+	LastDuration float64   `json:"last_duration_ms"`
+	LastError    string    `json:"last_error,omitempty"`
+	NextRun      time.Time `json:"next_run,omitempty"`
+}
+
+var (
+	jobs   = make(map[string]*JobStats)
+	jobsMu sync.RWMutex
+)
+
+// Register starts a job on its schedule in the background
+// An invalid schedule is logged and the job not started.
+func Register(job Job) {
+	spec := config.GetString(config.Key("SCHEDULE", job.Name), job.Schedule)
+	if strings.EqualFold(strings.TrimSpace(spec), "off") {
+		log.Printf("[SCHEDULER] %s disabled", job.Name)
+		return
+	}
+
+	schedule, err := Parse(spec)
+	if err != nil {
+		log.Printf("[SCHEDULER] %s - ERROR: %v, job not started", job.Name, err)
+		return
+	}
+
+	stats := &JobStats{Name: job.Name, Schedule: spec}
+	jobsMu.Lock()
+	jobs[job.Name] = stats
+	jobsMu.Unlock()
+
+	go func() {
+		if job.RunAtStart {
+			run(job, stats, time.Now())
+		}
+		for {
+			next := schedule.Next(time.Now())
+			if next.IsZero() {
+				log.Printf("[SCHEDULER] %s - schedule %q never fires again", job.Name, spec)
+				return
+			}
+			jobsMu.Lock()
+			stats.NextRun = next
+			jobsMu.Unlock()
+
+			time.Sleep(time.Until(next))
+			run(job, stats, next)
+		}
+	}()
+}
+
+// run executes one run of a job, recovering panics and recording its metrics
+func run(job Job, stats *JobStats, at time.Time) {
+	jobsMu.Lock()
+	stats.Running = true
+	jobsMu.Unlock()
+
+	started := time.Now()
+	err := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic: %v", r)
+				log.Printf("[SCHEDULER] %s - PANIC: %v\n%s", job.Name, r, debug.Stack())
+
+				jobsMu.Lock()
+				stats.Panics++
+				jobsMu.Unlock()
+			}
+		}()
+		return job.Run(context.Background(), at)
+	}()
+	duration := time.Since(started)
+
+	jobsMu.Lock()
+	defer jobsMu.Unlock()
+
+	stats.Running = false
+	stats.Runs++
+	stats.LastRun = started
+	stats.LastDuration = float64(duration.Microseconds()) / 1000.0
+	stats.LastError = ""
+	if err != nil {
+		stats.Failures++
+		stats.LastError = err.Error()
+		log.Printf("[SCHEDULER] %s - ERROR: %v", job.Name, err)
+	}
+}
+
+// Stats returns every registered job's metrics sorted by name
+func Stats() []JobStats {
+	jobsMu.RLock()
+	defer jobsMu.RUnlock()
+
+	stats := make([]JobStats, 0, len(jobs))
+	for _, s := range jobs {
+		stats = append(stats, *s)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Name < stats[j].Name })
+	return stats
+}
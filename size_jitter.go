@@ -0,0 +1,35 @@
+package main
+
+import (
+	"arbitrage.trade/capability"
+	"arbitrage.trade/clients"
+	"arbitrage.trade/clients/common"
+)
+
+// entryLeg is one order an entry places, as sized before jitter
+type entryLeg struct {
+	exchange   common.ExchangeType
+	isSpot     bool
+	price      float64
+	amountUSDT float64
+}
+
+// sizeJitter returns the factor to scale an entry's legs by (ORDER_SIZE_JITTER_PCT), 1 when jitter
+// is off or the jittered size would take a leg below its venue's minimum order
+func sizeJitter(pairName string, legs ...entryLeg) float64 {
+	exchanges := make([]common.ExchangeType, 0, len(legs))
+	for _, leg := range legs {
+		exchanges = append(exchanges, leg.exchange)
+	}
+
+	factor := clients.SizeJitter(exchanges...)
+	if factor == 1 {
+		return 1
+	}
+	for _, leg := range legs {
+		if !capability.CanFill(string(leg.exchange), pairName, leg.isSpot, leg.price, leg.amountUSDT*factor) {
+			return 1
+		}
+	}
+	return factor
+}
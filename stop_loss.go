@@ -0,0 +1,36 @@
+package main
+
+import (
+	"math"
+
+	"arbitrage.trade/config"
+)
+
+// USDT stop-loss
+//
+// A hard stop on what a position may lose: with STOP_LOSS_USDT set (default 0, disabled; given as a
+// loss, the sign is ignored) a position is closed at once when the combined unrealized PnL of its two
+// legs, less the fees paid so far and plus what a partial close banked, drops to minus that amount -
+// whatever its convergence and however long it has been held. It is checked on every price update
+// against the legs marked at the tracked close prices, and on every live PnL refresh (see live_pnl.go)
+// against its total, the perp leg as its venue reports it.
+
+// stopLossLimit returns the combined PnL at or below which a position is closed, 0 when disabled
+func stopLossLimit() float64 {
+	return -math.Abs(config.GetFloat("STOP_LOSS_USDT", 0))
+}
+
+// stopLossHit reports whether pnl breaches the stop-loss
+func stopLossHit(pnl float64) bool {
+	limit := stopLossLimit()
+	return limit < 0 && pnl <= limit
+}
+
+// combinedPnL returns the legs' unrealized PnL at the short and long prices, less the fees paid so far
+// net of rebates, plus what partial closes banked
+// Callers must hold p.mu.
+func (p *ArbitragePosition) combinedPnL(shortPrice, longPrice float64) float64 {
+	short, long := p.shortFill(), p.longFill()
+	unrealized := (short.Notional - short.Quantity*shortPrice) + (long.Quantity*longPrice - long.Notional)
+	return unrealized - p.Fees + p.Rebates + p.PartialSpotProfit + p.PartialFuturesProfit
+}
@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"arbitrage.trade/config"
+)
+
+// BookLevel is one price level of a captured book
+type BookLevel struct {
+	Price    float64 `json:"price"`
+	Quantity float64 `json:"quantity"`
+}
+
+// BookFrame is a venue book's top levels at one moment of a capture
+type BookFrame struct {
+	At         time.Time   `json:"at"`
+	Exchange   string      `json:"exchange"`
+	Market     string      `json:"market"` // "spot" or "perp"
+	Bids       []BookLevel `json:"bids"`   // Highest first
+	Asks       []BookLevel `json:"asks"`   // Lowest first
+	LastUpdate time.Time   `json:"last_update"`
+	Latency    float64     `json:"latency_ms"`
+}
+
+// BookCapture is how a trade's venue books moved from its decision to its last fill
+type BookCapture struct {
+	TradeID    string      `json:"trade_id,omitempty"`
+	Pair       string      `json:"pair"`
+	Phase      string      `json:"phase"` // "open" or "close"
+	DecidedAt  time.Time   `json:"decided_at"`
+	LastFillAt time.Time   `json:"last_fill_at,omitempty"`
+	From       time.Time   `json:"from"`
+	To         time.Time   `json:"to"`
+	Frames     []BookFrame `json:"frames"` // Oldest first
+}
+
+var bookCapturesMu sync.Mutex
+
+// bookCapturesPath returns the book capture file (BOOK_CAPTURES_FILE, default book_captures.jsonl)
+func bookCapturesPath() string {
+	return config.GetString("BOOK_CAPTURES_FILE", "book_captures.jsonl")
+}
+
+// AppendBookCapture appends a trade's captured books
+func AppendBookCapture(capture BookCapture) error {
+	bookCapturesMu.Lock()
+	defer bookCapturesMu.Unlock()
+
+	f, err := os.OpenFile(bookCapturesPath(), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open book captures: %w", err)
+	}
+	defer f.Close()
+
+	line, err := json.Marshal(capture)
+	if err != nil {
+		return fmt.Errorf("failed to marshal book capture: %w", err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write book capture: %w", err)
+	}
+	return nil
+}
+
+// LoadBookCaptures returns the captures of trades decided in [from, to)
+func LoadBookCaptures(from, to time.Time) ([]BookCapture, error) {
+	bookCapturesMu.Lock()
+	defer bookCapturesMu.Unlock()
+
+	f, err := os.Open(bookCapturesPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open book captures: %w", err)
+	}
+	defer f.Close()
+
+	var captures []BookCapture
+	scanner := bufio.NewScanner(f)
+	// A capture holds a few seconds of depth, far beyond the default 64KB line limit
+	scanner.Buffer(make([]byte, 0, 1024*1024), 64*1024*1024)
+	for scanner.Scan() {
+		var capture BookCapture
+		if err := json.Unmarshal(scanner.Bytes(), &capture); err != nil {
+			continue
+		}
+		if !capture.DecidedAt.Before(from) && capture.DecidedAt.Before(to) {
+			captures = append(captures, capture)
+		}
+	}
+
+	return captures, scanner.Err()
+}
@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"arbitrage.trade/config"
+)
+
+// LegExecution is one filled order of a trade measured against the price it was decided at
+type LegExecution struct {
+	Leg         string    `json:"leg"` // "open_spot", "open_perp", "close_spot", "close_perp"
+	Exchange    string    `json:"exchange"`
+	Expected    float64   `json:"expected_price"`
+	Price       float64   `json:"price"`
+	Quantity    float64   `json:"quantity"`
+	Fee         float64   `json:"fee"`                 // Paid, never negative
+	MakerQty    float64   `json:"maker_qty,omitempty"` // Filled by a resting maker order
+	Rebate      float64   `json:"rebate,omitempty"`    // USDT earned on the maker fill
+	SlippagePct float64   `json:"slippage_pct"`        // Positive when the fill was worse than expected
+	Diverged    bool      `json:"diverged,omitempty"`  // Beyond EXEC_DIVERGENCE_LIMIT_BPS from the decision price either way
+	FilledAt    time.Time `json:"filled_at"`
+}
+
+// ExecutionRecord is the execution quality of one closed trade
+type ExecutionRecord struct {
+	TradeID         string         `json:"trade_id,omitempty"`
+	Strategy        string         `json:"strategy,omitempty"`
+	ConfigVersion   string         `json:"config_version,omitempty"`
+	Experiment      string         `json:"experiment,omitempty"`
+	Variant         string         `json:"variant,omitempty"`
+	Pair            string         `json:"pair"`
+	SpotExchange    string         `json:"spot_exchange"`
+	FuturesExchange string         `json:"futures_exchange"`
+	Reverse         bool           `json:"reverse"`
+	DetectedAt      time.Time      `json:"detected_at"`
+	FirstFillAt     time.Time      `json:"first_fill_at"`
+	DetectToFillMs  float64        `json:"detect_to_fill_ms"`
+	DecisionSpread  float64        `json:"decision_spread_pct"`
+	RealizedSpread  float64        `json:"realized_entry_spread_pct"` // From the open fills
+	SpreadCapture   float64        `json:"spread_capture_pct"`        // Realized minus decision spread
+	ExitReason      string         `json:"exit_reason"`
+	ExitSpread      float64        `json:"exit_spread_pct"`
+	Diverged        bool           `json:"diverged,omitempty"` // A leg filled beyond the divergence limit
+	MaxDivergence   float64        `json:"max_divergence_bps"` // Largest distance of a fill from its decision price
+	Legs            []LegExecution `json:"legs"`
+	TotalProfit     float64        `json:"total_profit"`
+	OpenTime        time.Time      `json:"open_time"`
+	CloseTime       time.Time      `json:"close_time"`
+}
+
+var executionsMu sync.Mutex
+
+// executionsPath returns the execution-quality log file (EXECUTIONS_FILE, default executions.jsonl)
+func executionsPath() string {
+	return config.GetString("EXECUTIONS_FILE", "executions.jsonl")
+}
+
+// AppendExecution appends a closed trade's execution quality to the execution log
+func AppendExecution(record ExecutionRecord) error {
+	executionsMu.Lock()
+	defer executionsMu.Unlock()
+
+	f, err := os.OpenFile(executionsPath(), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open execution log: %w", err)
+	}
+	defer f.Close()
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal execution: %w", err)
+	}
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write execution: %w", err)
+	}
+	return nil
+}
+
+// LoadExecutions returns the execution records of trades closed in [from, to)
+func LoadExecutions(from, to time.Time) ([]ExecutionRecord, error) {
+	executionsMu.Lock()
+	defer executionsMu.Unlock()
+
+	f, err := os.Open(executionsPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open execution log: %w", err)
+	}
+	defer f.Close()
+
+	var records []ExecutionRecord
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var record ExecutionRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			continue
+		}
+		if !record.CloseTime.Before(from) && record.CloseTime.Before(to) {
+			records = append(records, record)
+		}
+	}
+
+	return records, scanner.Err()
+}
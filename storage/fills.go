@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"arbitrage.trade/config"
+)
+
+// FillRecord is one execution imported from a venue account's trade history
+type FillRecord struct {
+	Exchange      string    `json:"exchange"`
+	Market        string    `json:"market"` // "spot" or "futures"
+	Pair          string    `json:"pair"`
+	FillID        string    `json:"fill_id"`
+	OrderID       string    `json:"order_id"`
+	ClientOrderID string    `json:"client_order_id,omitempty"`
+	Bot           bool      `json:"bot"` // Client order ID carries the bot's prefix
+	Side          string    `json:"side"`
+	Price         float64   `json:"price"`
+	Quantity      float64   `json:"quantity"` // Base units
+	Fee           float64   `json:"fee"`      // In FeeAsset, negative for rebates
+	FeeAsset      string    `json:"fee_asset"`
+	RealizedPnL   float64   `json:"realized_pnl,omitempty"`
+	Time          time.Time `json:"time"`
+	ImportedAt    time.Time `json:"imported_at"`
+}
+
+// Key identifies the fill across imports
+func (r FillRecord) Key() string {
+	return r.Exchange + ":" + r.Market + ":" + r.FillID
+}
+
+var fillsMu sync.Mutex
+
+// fillsPath returns the imported fill history file (FILLS_FILE, default fills.jsonl)
+func fillsPath() string {
+	return config.GetString("FILLS_FILE", "fills.jsonl")
+}
+
+// AppendFills appends imported fills to the fill history
+func AppendFills(records []FillRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	fillsMu.Lock()
+	defer fillsMu.Unlock()
+
+	f, err := os.OpenFile(fillsPath(), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open fill history: %w", err)
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	for _, record := range records {
+		line, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("failed to marshal fill: %w", err)
+		}
+		w.Write(append(line, '\n'))
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to write fills: %w", err)
+	}
+	return nil
+}
+
+// LoadFills returns the imported fills executed in [from, to)
+func LoadFills(from, to time.Time) ([]FillRecord, error) {
+	fillsMu.Lock()
+	defer fillsMu.Unlock()
+
+	f, err := os.Open(fillsPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open fill history: %w", err)
+	}
+	defer f.Close()
+
+	var records []FillRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var record FillRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			continue
+		}
+		if !record.Time.Before(from) && record.Time.Before(to) {
+			records = append(records, record)
+		}
+	}
+
+	return records, scanner.Err()
+}
@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"arbitrage.trade/config"
+)
+
+// FundingRecord is one observed perp funding rate
+type FundingRecord struct {
+	Exchange    string    `json:"exchange"`
+	Pair        string    `json:"pair"`
+	Rate        float64   `json:"rate"` // Fraction per interval, positive when longs pay shorts
+	FundingTime time.Time `json:"funding_time"`
+	CollectedAt time.Time `json:"collected_at"`
+}
+
+var fundingMu sync.Mutex
+
+// fundingPath returns the funding-rate history file (FUNDING_FILE, default funding.jsonl)
+func fundingPath() string {
+	return config.GetString("FUNDING_FILE", "funding.jsonl")
+}
+
+// AppendFunding appends collected funding rates to the funding history
+func AppendFunding(records []FundingRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	fundingMu.Lock()
+	defer fundingMu.Unlock()
+
+	f, err := os.OpenFile(fundingPath(), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open funding history: %w", err)
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	for _, record := range records {
+		line, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("failed to marshal funding: %w", err)
+		}
+		w.Write(append(line, '\n'))
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to write funding: %w", err)
+	}
+	return nil
+}
+
+// LoadFunding returns the funding rates collected since from
+func LoadFunding(from time.Time) ([]FundingRecord, error) {
+	fundingMu.Lock()
+	defer fundingMu.Unlock()
+
+	f, err := os.Open(fundingPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open funding history: %w", err)
+	}
+	defer f.Close()
+
+	var records []FundingRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var record FundingRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			continue
+		}
+		if !record.CollectedAt.Before(from) {
+			records = append(records, record)
+		}
+	}
+
+	return records, scanner.Err()
+}
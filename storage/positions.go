@@ -0,0 +1,136 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/config"
+)
+
+// LegFill is the size one side of a position was actually opened at, summed over its open fills
+type LegFill struct {
+	Quantity float64  `json:"quantity"`
+	Notional float64  `json:"notional"` // USDT, fill price times quantity
+	OrderIDs []string `json:"order_ids,omitempty"`
+}
+
+// AvgPrice returns the leg's volume-weighted fill price, 0 when nothing filled
+func (l LegFill) AvgPrice() float64 {
+	if common.IsNegativeOrZero(l.Quantity) {
+		return 0
+	}
+	return l.Notional / l.Quantity
+}
+
+// SpotRoute is one venue's share of a spot leg routed across venues
+type SpotRoute struct {
+	Exchange   string  `json:"exchange"`
+	Price      float64 `json:"price"` // Best ask the share was routed at
+	AmountUSDT float64 `json:"amount_usdt"`
+}
+
+// LivePnL is an open position's unrealized PnL while it is held, in USDT
+type LivePnL struct {
+	SpotPnL    float64   `json:"spot_pnl"` // Spot leg marked to its book
+	PerpPnL    float64   `json:"perp_pnl"`
+	PerpSource string    `json:"perp_source"` // "venue" when the perp venue reported it, "book" when marked to its book
+	SpotMark   float64   `json:"spot_mark"`
+	PerpMark   float64   `json:"perp_mark"`
+	Realized   float64   `json:"realized"` // Partial closes, net of their fees
+	Fees       float64   `json:"fees"`     // Paid opening the legs, net of maker rebates
+	Total      float64   `json:"total"`    // Spot + perp + realized - fees
+	At         time.Time `json:"at"`
+}
+
+// PositionSnapshot is the entry context of an open or closing position, kept so it survives a restart
+type PositionSnapshot struct {
+	TradeID              string              `json:"trade_id,omitempty"`
+	Strategy             string              `json:"strategy,omitempty"`
+	ConfigVersion        string              `json:"config_version,omitempty"`
+	Experiment           string              `json:"experiment,omitempty"`
+	Variant              string              `json:"variant,omitempty"`
+	Pair                 string              `json:"pair"`
+	ShortExchange        string              `json:"short_exchange"`
+	LongExchange         string              `json:"long_exchange"`
+	EntryShortPrice      float64             `json:"entry_short_price"`
+	EntryLongPrice       float64             `json:"entry_long_price"`
+	EntrySpread          float64             `json:"entry_spread_pct"`
+	AmountUSDT           float64             `json:"amount_usdt"`
+	EntryTime            time.Time           `json:"entry_time"`
+	DetectedAt           time.Time           `json:"detected_at"`
+	FirstFillAt          time.Time           `json:"first_fill_at"`
+	Reverse              bool                `json:"reverse"`
+	Inverse              bool                `json:"inverse,omitempty"`
+	SpotQuantity         float64             `json:"spot_quantity,omitempty"`
+	SpotProceeds         float64             `json:"spot_proceeds,omitempty"`
+	Fees                 float64             `json:"fees"`
+	Rebates              float64             `json:"rebates,omitempty"`
+	Slippage             float64             `json:"slippage_pct"`
+	IsOpen               bool                `json:"is_open"`
+	IsClosing            bool                `json:"is_closing"`
+	ExitReason           string              `json:"exit_reason,omitempty"`
+	Legs                 []LegExecution      `json:"legs"`
+	SpotFill             LegFill             `json:"spot_fill"`
+	PerpFill             LegFill             `json:"perp_fill"`
+	SpotRoutes           []SpotRoute         `json:"spot_routes,omitempty"`
+	PartialClosed        bool                `json:"partial_closed,omitempty"`
+	PartialSpotProfit    float64             `json:"partial_spot_profit,omitempty"`
+	PartialFuturesProfit float64             `json:"partial_futures_profit,omitempty"`
+	Option               *common.OptionQuote `json:"option,omitempty"`
+	OptionExchange       string              `json:"option_exchange,omitempty"`
+	OptionFill           *common.TradeResult `json:"option_fill,omitempty"`
+	LivePnL              *LivePnL            `json:"live_pnl,omitempty"` // Not restored, refreshed while held
+}
+
+var positionsMu sync.Mutex
+
+// positionsPath returns the open-position snapshot file (POSITIONS_FILE, default positions.json)
+func positionsPath() string {
+	return config.GetString("POSITIONS_FILE", "positions.json")
+}
+
+// SavePositions replaces the stored snapshot with the given positions
+// The file is written next to the target and renamed so a crash never leaves it half written
+func SavePositions(positions []PositionSnapshot) error {
+	positionsMu.Lock()
+	defer positionsMu.Unlock()
+
+	data, err := json.MarshalIndent(positions, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal positions: %w", err)
+	}
+
+	path := positionsPath()
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write positions: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to replace positions: %w", err)
+	}
+	return nil
+}
+
+// LoadPositions returns the stored positions, none when no snapshot exists
+func LoadPositions() ([]PositionSnapshot, error) {
+	positionsMu.Lock()
+	defer positionsMu.Unlock()
+
+	data, err := os.ReadFile(positionsPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read positions: %w", err)
+	}
+
+	var positions []PositionSnapshot
+	if err := json.Unmarshal(data, &positions); err != nil {
+		return nil, fmt.Errorf("failed to parse positions: %w", err)
+	}
+	return positions, nil
+}
@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"arbitrage.trade/config"
+)
+
+// TradeRecord is one closed arbitrage position
+type TradeRecord struct {
+	TradeID         string    `json:"trade_id,omitempty"`
+	Strategy        string    `json:"strategy,omitempty"`       // Strategy the trade ran under
+	ConfigVersion   string    `json:"config_version,omitempty"` // Parameter set of the strategy
+	Experiment      string    `json:"experiment,omitempty"`     // A/B experiment the trade was assigned in
+	Variant         string    `json:"variant,omitempty"`        // Execution configuration it assigned
+	Pair            string    `json:"pair"`
+	SpotExchange    string    `json:"spot_exchange"`
+	FuturesExchange string    `json:"futures_exchange"`
+	EntrySpread     float64   `json:"entry_spread_pct"`
+	ExitSpread      float64   `json:"exit_spread_pct"`
+	SpotProfit      float64   `json:"spot_profit"`
+	FuturesProfit   float64   `json:"futures_profit"`
+	HedgeProfit     float64   `json:"hedge_profit,omitempty"` // Protective option proceeds minus premium
+	TotalProfit     float64   `json:"total_profit"`           // Net of fees and funding
+	Fees            float64   `json:"fees"`
+	Rebates         float64   `json:"rebates,omitempty"` // Maker rebates earned, not included in Fees
+	Funding         float64   `json:"funding"`           // Funding received (+) or paid (-) while open
+	Slippage        float64   `json:"slippage_pct"`      // Adverse slippage summed over the four legs
+	Amount          float64   `json:"amount"`
+	OpenTime        time.Time `json:"open_time"`
+	CloseTime       time.Time `json:"close_time"`
+}
+
+var fileMu sync.Mutex
+
+// tradesPath returns the closed-trade log file (TRADES_FILE, default trades.jsonl)
+func tradesPath() string {
+	return config.GetString("TRADES_FILE", "trades.jsonl")
+}
+
+// AppendTrade appends a closed trade to the trade log
+func AppendTrade(record TradeRecord) error {
+	fileMu.Lock()
+	defer fileMu.Unlock()
+
+	f, err := os.OpenFile(tradesPath(), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open trade log: %w", err)
+	}
+	defer f.Close()
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal trade: %w", err)
+	}
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write trade: %w", err)
+	}
+	return nil
+}
+
+// LoadTrades returns the trades closed in [from, to)
+func LoadTrades(from, to time.Time) ([]TradeRecord, error) {
+	fileMu.Lock()
+	defer fileMu.Unlock()
+
+	f, err := os.Open(tradesPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open trade log: %w", err)
+	}
+	defer f.Close()
+
+	var trades []TradeRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var record TradeRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			continue
+		}
+		if !record.CloseTime.Before(from) && record.CloseTime.Before(to) {
+			trades = append(trades, record)
+		}
+	}
+
+	return trades, scanner.Err()
+}
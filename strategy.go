@@ -0,0 +1,29 @@
+package main
+
+import (
+	"arbitrage.trade/clients/common"
+	"arbitrage.trade/config"
+)
+
+// Strategy tags
+//
+// Every position is tagged at entry with the strategy it runs under, STRATEGY_NAME (default "default"),
+// and STRATEGY_CONFIG_VERSION (unset by default) naming the parameter set, to be bumped whenever
+// thresholds or execution settings change. The tag stays with the position across restarts and is
+// carried on its orders' trade_execution events, its trade summary and live PnL, the trade and execution
+// logs and /debug/state, and the daily report breaks P&L down per tag, so when several strategies or
+// parameter sets run side by side each variant's P&L is attributed on its own.
+
+// currentStrategy returns the tag new positions are opened under
+func currentStrategy() common.StrategyTag {
+	return common.StrategyTag{
+		Strategy:      config.GetString("STRATEGY_NAME", "default"),
+		ConfigVersion: config.GetString("STRATEGY_CONFIG_VERSION", ""),
+	}
+}
+
+// strategyTag returns the tag the position was opened under
+// Callers must hold p.mu or own the position.
+func (p *ArbitragePosition) strategyTag() common.StrategyTag {
+	return common.StrategyTag{Strategy: p.Strategy, ConfigVersion: p.ConfigVersion, Experiment: p.Experiment, Variant: p.Variant}
+}
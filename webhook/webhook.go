@@ -0,0 +1,116 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"arbitrage.trade/config"
+)
+
+// Webhook sink for trade events
+//
+// Every payload is POSTed to each URL in WEBHOOK_URLS (comma separated) with headers:
+//   X-Event-Type:  payload type (trade_execution, trade_summary)
+//   X-Timestamp:   unix seconds the request was signed at
+//   X-Signature:   sha256=<hex HMAC-SHA256 of "<timestamp>.<body>" keyed with WEBHOOK_SECRET>
+// Failed deliveries (network errors, 429, 5xx) are retried WEBHOOK_MAX_RETRIES times with
+// exponential backoff starting at WEBHOOK_RETRY_DELAY
+
+var httpClient = &http.Client{}
+
+// Enabled reports whether any webhook URL is configured
+func Enabled() bool {
+	return len(urls()) > 0
+}
+
+func urls() []string {
+	var out []string
+	for _, u := range strings.Split(config.GetString("WEBHOOK_URLS", ""), ",") {
+		if u = strings.TrimSpace(u); u != "" {
+			out = append(out, u)
+		}
+	}
+	return out
+}
+
+// Sign returns the X-Signature value for a body signed at timestamp
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Send delivers a JSON payload to every configured webhook in the background
+func Send(eventType string, body []byte) {
+	for _, url := range urls() {
+		go deliver(url, eventType, body)
+	}
+}
+
+func deliver(url, eventType string, body []byte) {
+	maxRetries := config.GetInt("WEBHOOK_MAX_RETRIES", 3)
+	delay := config.GetDuration("WEBHOOK_RETRY_DELAY", time.Second)
+
+	var lastErr error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(delay)
+			delay *= 2
+		}
+
+		retry, err := post(url, eventType, body)
+		if err == nil {
+			return
+		}
+		lastErr = err
+		if !retry {
+			break
+		}
+	}
+
+	fmt.Printf("❌ Failed to deliver %s webhook to %s: %v\n", eventType, url, lastErr)
+}
+
+// post sends one signed request and reports whether a failure is worth retrying
+func post(url, eventType string, body []byte) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), config.GetDuration("WEBHOOK_TIMEOUT", 5*time.Second))
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-Type", eventType)
+	req.Header.Set("X-Timestamp", strconv.FormatInt(timestamp, 10))
+	if secret := config.GetString("WEBHOOK_SECRET", ""); secret != "" {
+		req.Header.Set("X-Signature", Sign(secret, timestamp, body))
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("status %d", resp.StatusCode)
+}